@@ -16,7 +16,8 @@ type AuthFlags struct {
 	AuthKeysFile string
 }
 
-func defaultAuthKeysFile() string {
+// defaultConfigDir returns the default gnokms config directory.
+func defaultConfigDir() string {
 	dir, err := os.UserConfigDir()
 	if err != nil {
 		var derr error
@@ -27,7 +28,11 @@ func defaultAuthKeysFile() string {
 			).Error())
 		}
 	}
-	return filepath.Join(dir, "gnokms/auth_keys.json")
+	return filepath.Join(dir, "gnokms")
+}
+
+func defaultAuthKeysFile() string {
+	return filepath.Join(defaultConfigDir(), "auth_keys.json")
 }
 
 func (f *AuthFlags) RegisterFlags(fs *flag.FlagSet) {
@@ -39,8 +44,26 @@ func (f *AuthFlags) RegisterFlags(fs *flag.FlagSet) {
 	)
 }
 
+type StateFlags struct {
+	StateFile string
+}
+
+func defaultStateFile() string {
+	return filepath.Join(defaultConfigDir(), "sign_state.json")
+}
+
+func (f *StateFlags) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&f.StateFile,
+		"state-file",
+		defaultStateFile(),
+		"path to the file persisting the last sign state, used to prevent double signing",
+	)
+}
+
 type ServerFlags struct {
 	AuthFlags
+	StateFlags
 
 	Listener        string
 	KeepAlivePeriod time.Duration
@@ -59,6 +82,7 @@ var defaultServerFlags = ServerFlags{
 
 func (f *ServerFlags) RegisterFlags(fs *flag.FlagSet) {
 	f.AuthFlags.RegisterFlags(fs)
+	f.StateFlags.RegisterFlags(fs)
 
 	fs.StringVar(
 		&f.Listener,
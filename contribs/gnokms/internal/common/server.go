@@ -13,6 +13,7 @@ import (
 	rss "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/server"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	osm "github.com/gnolang/gno/tm2/pkg/os"
 	"go.uber.org/multierr"
 )
@@ -62,39 +63,48 @@ func NewSignerServer(
 	return server, err
 }
 
-// printValidatorInfo prints the validator info in genesis and bech32 formats.
-func printValidatorInfo(signer types.Signer, logger *slog.Logger) error {
-	// Check if the signer is nil.
-	if signer == nil {
-		return errors.New("signer is nil")
-	}
-
-	// Get the public key of the signer.
-	pubKey := signer.PubKey()
-
-	// Create a genesis validator with the signer's public key.
+// ValidatorInfo returns the validator info of the given public key in genesis and
+// bech32 formats.
+func ValidatorInfo(pubKey crypto.PubKey, name string) (string, error) {
+	// Create a genesis validator with the public key.
 	genesisValidator := types.GenesisValidator{
 		PubKey:  pubKey,
 		Address: pubKey.Address(),
 		Power:   10,
-		Name:    "gnokms_remote_signer",
+		Name:    name,
 	}
 
 	// Marshal the genesis validator info to JSON using amino.
 	const indent = "  "
 	genesisValidatorInfo, err := amino.MarshalJSONIndent(genesisValidator, "", indent)
 	if err != nil {
-		return fmt.Errorf("unable to marshal genesis validator info to JSON: %w", err)
+		return "", fmt.Errorf("unable to marshal genesis validator info to JSON: %w", err)
 	}
 
-	// Print the validator info in genesis and bech32 formats.
-	logger.Info(fmt.Sprintf("Validator info:\n%s\n%s\n%s\n%s%s%s\n%s%s%s",
+	// Format the validator info in genesis and bech32 formats.
+	return fmt.Sprintf("Validator info:\n%s\n%s\n%s\n%s%s%s\n%s%s%s",
 		"Genesis format:",
 		genesisValidatorInfo,
 		"Bech32 format:",
-		indent, "pub_key: ", pubKey.String(),
+		indent, "pub_key: ", crypto.PubKeyToBech32(pubKey),
 		indent, "address: ", pubKey.Address().String(),
-	))
+	), nil
+}
+
+// printValidatorInfo prints the validator info in genesis and bech32 formats.
+func printValidatorInfo(signer types.Signer, logger *slog.Logger) error {
+	// Check if the signer is nil.
+	if signer == nil {
+		return errors.New("signer is nil")
+	}
+
+	// Get the validator info of the signer's public key.
+	validatorInfo, err := ValidatorInfo(signer.PubKey(), "gnokms_remote_signer")
+	if err != nil {
+		return err
+	}
+
+	logger.Info(validatorInfo)
 
 	return nil
 }
@@ -116,8 +126,14 @@ func RunSignerServer(ctx context.Context, commonFlags *ServerFlags, signer types
 		return fmt.Errorf("unable to print genesis validator info: %w", err)
 	}
 
+	// Guard the gnokms signer with the persistent sign state to prevent double signing.
+	stateSigner, err := NewStateSigner(signer, commonFlags.StateFile, logger.With("module", "state_signer"))
+	if err != nil {
+		return fmt.Errorf("sign state initialization failed: %w", err)
+	}
+
 	// Initialize the remote signer server with the gnokms signer.
-	server, err := NewSignerServer(commonFlags, signer, logger)
+	server, err := NewSignerServer(commonFlags, stateSigner, logger)
 	if err != nil {
 		return fmt.Errorf("signer server initialization failed: %w", err)
 	}
@@ -142,6 +158,6 @@ func RunSignerServer(ctx context.Context, commonFlags *ServerFlags, signer types
 	// Close the server and the signer gracefully.
 	return multierr.Combine(
 		server.Stop(),
-		signer.Close(),
+		stateSigner.Close(),
 	)
 }
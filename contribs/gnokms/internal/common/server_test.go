@@ -223,6 +223,31 @@ func TestRunSignerServer(t *testing.T) {
 		))
 	})
 
+	t.Run("invalid state file", func(t *testing.T) {
+		t.Parallel()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+
+		filePath := filepath.Join(t.TempDir(), "invalid")
+		os.WriteFile(filePath, []byte("invalid"), 0o600)
+
+		serverFlags := &ServerFlags{
+			Listener: "tcp://127.0.0.1:0",
+			LogLevel: zapcore.ErrorLevel.String(),
+			StateFlags: StateFlags{
+				StateFile: filePath,
+			},
+		}
+
+		assert.Error(t, RunSignerServer(
+			ctx,
+			serverFlags,
+			types.NewMockSigner(),
+			commands.NewDefaultIO(),
+		))
+	})
+
 	t.Run("signer fail on close", func(t *testing.T) {
 		t.Parallel()
 
@@ -232,6 +257,9 @@ func TestRunSignerServer(t *testing.T) {
 		serverFlags := &ServerFlags{
 			Listener: "tcp://127.0.0.1:0",
 			LogLevel: zapcore.ErrorLevel.String(),
+			StateFlags: StateFlags{
+				StateFile: filepath.Join(t.TempDir(), "sign_state.json"),
+			},
 		}
 
 		assert.ErrorIs(t, RunSignerServer(
@@ -253,6 +281,9 @@ func TestRunSignerServer(t *testing.T) {
 		serverFlags := &ServerFlags{
 			Listener: "tcp://127.0.0.1:0",
 			LogLevel: zapcore.ErrorLevel.String(),
+			StateFlags: StateFlags{
+				StateFile: filepath.Join(t.TempDir(), "sign_state.json"),
+			},
 		}
 
 		assert.NoError(t, RunSignerServer(
@@ -0,0 +1,169 @@
+package common
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	fstate "github.com/gnolang/gno/tm2/pkg/bft/privval/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// StateSigner errors.
+var (
+	errUnknownSignBytes    = errors.New("sign bytes are neither a vote nor a proposal")
+	errDoubleSign          = errors.New("refusing to double sign: same HRS with conflicting data")
+	errTimestampChanged    = errors.New("refusing to sign: same HRS with a different timestamp")
+	errStateSignerMismatch = errors.New("state signature verification failed using signer public key")
+)
+
+// StateSigner wraps a gnokms signer with a persistent sign state, refusing to sign
+// votes and proposals that would conflict with the last signed ones, the same way the
+// validator privval does. It is safe for concurrent use by multiple clients.
+type StateSigner struct {
+	signer types.Signer
+	state  *fstate.FileState
+	logger *slog.Logger
+	lock   sync.Mutex
+}
+
+// StateSigner type implements types.Signer.
+var _ types.Signer = (*StateSigner)(nil)
+
+// PubKey implements types.Signer.
+func (ss *StateSigner) PubKey() crypto.PubKey {
+	return ss.signer.PubKey()
+}
+
+// Sign implements types.Signer.
+func (ss *StateSigner) Sign(signBytes []byte) ([]byte, error) {
+	// Get the height, round, step (HRS) from the sign bytes.
+	height, round, step, err := signBytesHRS(signBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Checking, signing and persisting the state must be atomic across clients.
+	ss.lock.Lock()
+	defer ss.lock.Unlock()
+
+	// Check for identical HRS against the last state.
+	sameHRS, err := ss.state.CheckHRS(height, round, step)
+	if err != nil {
+		ss.logger.Warn("Refused to sign", "height", height, "round", round, "step", step, "error", err)
+		return nil, err
+	}
+
+	if sameHRS {
+		// If signBytes are the same, use the last signature.
+		if bytes.Equal(signBytes, ss.state.SignBytes) {
+			return ss.state.Signature, nil
+		}
+
+		// If they only differ by timestamp, privval reuses the last signature along
+		// with the last timestamp. The remote signer protocol only returns the
+		// signature, which would not be valid for the new timestamp, so refuse to
+		// sign: a single signature is ever produced for a given HRS.
+		// Otherwise, something is wrong.
+		var onlyTimestamp bool
+		if step == fstate.StepPropose {
+			_, onlyTimestamp = ss.state.CheckProposalsOnlyDifferByTimestamp(signBytes)
+		} else {
+			_, onlyTimestamp = ss.state.CheckVotesOnlyDifferByTimestamp(signBytes)
+		}
+
+		if onlyTimestamp {
+			ss.logger.Warn("Refused to sign, sign bytes only differ by timestamp",
+				"height", height, "round", round, "step", step)
+			return nil, errTimestampChanged
+		}
+
+		ss.logger.Error("Refused to double sign", "height", height, "round", round, "step", step)
+		return nil, errDoubleSign
+	}
+
+	signature, err := ss.signer.Sign(signBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// Never return a signature that was not persisted in the state.
+	if err := ss.state.Update(height, round, step, signBytes, signature); err != nil {
+		return nil, fmt.Errorf("unable to persist sign state: %w", err)
+	}
+
+	return signature, nil
+}
+
+// Close implements types.Signer.
+func (ss *StateSigner) Close() error {
+	return ss.signer.Close()
+}
+
+// signBytesHRS returns the height, round, step (HRS) of the vote or proposal
+// sign bytes.
+func signBytesHRS(signBytes []byte) (int64, int, fstate.Step, error) {
+	// Votes and proposals can't be told apart by decoding, so check their type first.
+	var msgType struct {
+		Type types.SignedMsgType
+	}
+	if err := amino.UnmarshalSized(signBytes, &msgType); err != nil {
+		return 0, 0, 0, fmt.Errorf("%w: %w", errUnknownSignBytes, err)
+	}
+
+	switch msgType.Type {
+	case types.PrevoteType, types.PrecommitType:
+		var vote types.CanonicalVote
+		if err := amino.UnmarshalSized(signBytes, &vote); err != nil {
+			return 0, 0, 0, fmt.Errorf("%w: %w", errUnknownSignBytes, err)
+		}
+
+		return vote.Height, int(vote.Round), fstate.VoteTypeToStep(vote.Type), nil
+
+	case types.ProposalType:
+		var proposal types.CanonicalProposal
+		if err := amino.UnmarshalSized(signBytes, &proposal); err != nil {
+			return 0, 0, 0, fmt.Errorf("%w: %w", errUnknownSignBytes, err)
+		}
+
+		return proposal.Height, int(proposal.Round), fstate.StepPropose, nil
+
+	default:
+		return 0, 0, 0, fmt.Errorf("%w: invalid type %#x", errUnknownSignBytes, msgType.Type)
+	}
+}
+
+// NewStateSigner wraps the given signer with the sign state persisted at the given
+// file path. If the state file does not exist, it will be created.
+func NewStateSigner(signer types.Signer, stateFilePath string, logger *slog.Logger) (*StateSigner, error) {
+	// Ensure the parent directory exists.
+	if err := osm.EnsureDir(filepath.Dir(stateFilePath), 0o700); err != nil {
+		return nil, err
+	}
+
+	// Load existing file state or create a new one.
+	state, err := fstate.LoadOrMakeFileState(stateFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("unable to load sign state: %w", err)
+	}
+
+	// Check if the state was signed by this signer.
+	if state.SignBytes != nil {
+		// Verify state signature using the signer public key.
+		if !signer.PubKey().VerifyBytes(state.SignBytes, state.Signature) {
+			return nil, errStateSignerMismatch
+		}
+	}
+
+	return &StateSigner{
+		signer: signer,
+		state:  state,
+		logger: logger,
+	}, nil
+}
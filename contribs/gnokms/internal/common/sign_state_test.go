@@ -0,0 +1,280 @@
+package common
+
+import (
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	fstate "github.com/gnolang/gno/tm2/pkg/bft/privval/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testChainID = "test-chain"
+
+func voteSignBytes(height int64, round int, voteType types.SignedMsgType, blockHash []byte, timestamp time.Time) []byte {
+	vote := &types.Vote{
+		Type:      voteType,
+		Height:    height,
+		Round:     round,
+		BlockID:   types.BlockID{Hash: blockHash},
+		Timestamp: timestamp,
+	}
+
+	return vote.SignBytes(testChainID)
+}
+
+func proposalSignBytes(height int64, round, polRound int, blockHash []byte, timestamp time.Time) []byte {
+	proposal := &types.Proposal{
+		Type:      types.ProposalType,
+		Height:    height,
+		Round:     round,
+		POLRound:  polRound,
+		BlockID:   types.BlockID{Hash: blockHash},
+		Timestamp: timestamp,
+	}
+
+	return proposal.SignBytes(testChainID)
+}
+
+func newTestStateSigner(t *testing.T, signer types.Signer) (*StateSigner, string) {
+	t.Helper()
+
+	filePath := filepath.Join(t.TempDir(), "gnokms", "sign_state.json")
+
+	stateSigner, err := NewStateSigner(signer, filePath, log.NewNoopLogger())
+	require.NoError(t, err)
+	require.NotNil(t, stateSigner)
+
+	return stateSigner, filePath
+}
+
+func TestSignBytesHRS(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+
+	testTable := []struct {
+		name      string
+		signBytes []byte
+		height    int64
+		round     int
+		step      fstate.Step
+	}{
+		{"prevote", voteSignBytes(3, 1, types.PrevoteType, []byte("hash"), now), 3, 1, fstate.StepPrevote},
+		{"nil precommit", voteSignBytes(3, 2, types.PrecommitType, nil, now), 3, 2, fstate.StepPrecommit},
+		{"proposal", proposalSignBytes(4, 1, -1, []byte("hash"), now), 4, 1, fstate.StepPropose},
+		// Proposal decodes as a vote without error when POLRound is 0.
+		{"proposal with zero POL round", proposalSignBytes(4, 2, 0, []byte("hash"), now), 4, 2, fstate.StepPropose},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			height, round, step, err := signBytesHRS(testCase.signBytes)
+			require.NoError(t, err)
+			assert.Equal(t, testCase.height, height)
+			assert.Equal(t, testCase.round, round)
+			assert.Equal(t, testCase.step, step)
+		})
+	}
+
+	t.Run("invalid sign bytes", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, _, err := signBytesHRS([]byte("invalid"))
+		assert.ErrorIs(t, err, errUnknownSignBytes)
+	})
+
+	t.Run("unknown type", func(t *testing.T) {
+		t.Parallel()
+
+		_, _, _, err := signBytesHRS(voteSignBytes(3, 1, types.SignedMsgType(0x42), nil, now))
+		assert.ErrorIs(t, err, errUnknownSignBytes)
+	})
+}
+
+func TestStateSigner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("same HRS", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, _ := newTestStateSigner(t, types.NewMockSigner())
+		now := time.Now()
+
+		// Sign a first vote.
+		signBytes := voteSignBytes(1, 0, types.PrevoteType, []byte("hash"), now)
+		signature, err := stateSigner.Sign(signBytes)
+		require.NoError(t, err)
+		require.True(t, stateSigner.PubKey().VerifyBytes(signBytes, signature))
+
+		// The same vote returns the same signature.
+		resigned, err := stateSigner.Sign(signBytes)
+		require.NoError(t, err)
+		assert.Equal(t, signature, resigned)
+
+		// A vote only differing by its timestamp is refused, as the last signature
+		// is not valid for it.
+		resigned, err = stateSigner.Sign(voteSignBytes(1, 0, types.PrevoteType, []byte("hash"), now.Add(time.Second)))
+		require.Nil(t, resigned)
+		assert.ErrorIs(t, err, errTimestampChanged)
+
+		// A conflicting vote is refused.
+		signature, err = stateSigner.Sign(voteSignBytes(1, 0, types.PrevoteType, []byte("other"), now))
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, errDoubleSign)
+	})
+
+	t.Run("conflicting proposal", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, _ := newTestStateSigner(t, types.NewMockSigner())
+		now := time.Now()
+
+		_, err := stateSigner.Sign(proposalSignBytes(1, 0, -1, []byte("hash"), now))
+		require.NoError(t, err)
+
+		// A proposal only differing by its timestamp is refused.
+		resigned, err := stateSigner.Sign(proposalSignBytes(1, 0, -1, []byte("hash"), now.Add(time.Second)))
+		require.Nil(t, resigned)
+		assert.ErrorIs(t, err, errTimestampChanged)
+
+		signature, err := stateSigner.Sign(proposalSignBytes(1, 0, -1, []byte("other"), now))
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, errDoubleSign)
+	})
+
+	t.Run("regression", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, _ := newTestStateSigner(t, types.NewMockSigner())
+		now := time.Now()
+
+		_, err := stateSigner.Sign(voteSignBytes(2, 1, types.PrecommitType, []byte("hash"), now))
+		require.NoError(t, err)
+
+		// Height, round and step regressions are refused.
+		for _, signBytes := range [][]byte{
+			voteSignBytes(1, 1, types.PrecommitType, []byte("hash"), now),
+			voteSignBytes(2, 0, types.PrecommitType, []byte("hash"), now),
+			voteSignBytes(2, 1, types.PrevoteType, []byte("hash"), now),
+			proposalSignBytes(2, 1, -1, []byte("hash"), now),
+		} {
+			signature, err := stateSigner.Sign(signBytes)
+			assert.Nil(t, signature)
+			assert.Error(t, err)
+		}
+
+		// A new height is signed.
+		_, err = stateSigner.Sign(proposalSignBytes(3, 0, -1, []byte("hash"), now))
+		assert.NoError(t, err)
+	})
+
+	t.Run("unknown sign bytes", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, _ := newTestStateSigner(t, types.NewMockSigner())
+
+		signature, err := stateSigner.Sign([]byte("arbitrary bytes"))
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, errUnknownSignBytes)
+	})
+
+	t.Run("signer error", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, filePath := newTestStateSigner(t, types.NewErroringMockSigner())
+
+		signature, err := stateSigner.Sign(voteSignBytes(1, 0, types.PrevoteType, nil, time.Now()))
+		require.Nil(t, signature)
+		require.ErrorIs(t, err, types.ErrErroringMockSigner)
+
+		// The state is not updated.
+		state, err := fstate.LoadFileState(filePath)
+		require.NoError(t, err)
+		assert.Zero(t, state.Height)
+	})
+
+	t.Run("persisted state", func(t *testing.T) {
+		t.Parallel()
+
+		signer := types.NewMockSigner()
+		stateSigner, filePath := newTestStateSigner(t, signer)
+		now := time.Now()
+
+		_, err := stateSigner.Sign(voteSignBytes(5, 2, types.PrecommitType, []byte("hash"), now))
+		require.NoError(t, err)
+
+		// Reload the state with the same signer, the conflicting vote is still refused.
+		stateSigner, err = NewStateSigner(signer, filePath, log.NewNoopLogger())
+		require.NoError(t, err)
+		signature, err := stateSigner.Sign(voteSignBytes(5, 2, types.PrecommitType, []byte("other"), now))
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, errDoubleSign)
+
+		// Reload the state with another signer.
+		stateSigner, err = NewStateSigner(types.NewMockSigner(), filePath, log.NewNoopLogger())
+		require.Nil(t, stateSigner)
+		assert.ErrorIs(t, err, errStateSignerMismatch)
+	})
+
+	t.Run("concurrent clients", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, _ := newTestStateSigner(t, types.NewMockSigner())
+		now := time.Now()
+
+		const clients = 10
+
+		var (
+			wg        sync.WaitGroup
+			successes = make(chan struct{}, clients)
+		)
+
+		// Each client tries to sign a conflicting vote for the same HRS.
+		for i := range clients {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+
+				signBytes := voteSignBytes(1, 0, types.PrevoteType, []byte{byte(i)}, now)
+				if _, err := stateSigner.Sign(signBytes); err == nil {
+					successes <- struct{}{}
+				} else {
+					assert.ErrorIs(t, err, errDoubleSign)
+				}
+			}()
+		}
+		wg.Wait()
+
+		// Only one of them got a signature.
+		assert.Len(t, successes, 1)
+	})
+}
+
+func TestNewStateSigner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("empty file path", func(t *testing.T) {
+		t.Parallel()
+
+		stateSigner, err := NewStateSigner(types.NewMockSigner(), "", log.NewNoopLogger())
+		require.Nil(t, stateSigner)
+		assert.Error(t, err)
+	})
+
+	t.Run("creates parent directory", func(t *testing.T) {
+		t.Parallel()
+
+		_, filePath := newTestStateSigner(t, types.NewMockSigner())
+
+		state, err := fstate.LoadFileState(filePath)
+		require.NoError(t, err)
+		assert.Zero(t, state.Height)
+	})
+}
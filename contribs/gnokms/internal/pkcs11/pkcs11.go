@@ -0,0 +1,113 @@
+package pkcs11
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/gnolang/gno/contribs/gnokms/internal/common"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type pkcs11Flags struct {
+	common.ServerFlags
+
+	module           string
+	tokenLabel       string
+	keyLabel         string
+	insecurePinStdin bool
+}
+
+var defaultPKCS11Flags = &pkcs11Flags{
+	module:           "",
+	tokenLabel:       "",
+	keyLabel:         "",
+	insecurePinStdin: false,
+}
+
+var (
+	errModuleNotSet     = errors.New("the PKCS#11 module path must be provided")
+	errTokenLabelNotSet = errors.New("the token label must be provided")
+	errKeyLabelNotSet   = errors.New("the key label must be provided")
+)
+
+// NewPKCS11Cmd creates the gnokms pkcs11 subcommand.
+func NewPKCS11Cmd(io commands.IO) *commands.Command {
+	pkFlags := &pkcs11Flags{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "pkcs11",
+			ShortUsage: "pkcs11 [flags]",
+			ShortHelp:  "uses a PKCS#11 token (HSM) as a remote signer",
+			LongHelp: "Runs a gnokms remote signer server using a key held in a PKCS#11 token as backend. " +
+				"Both ed25519 and secp256k1 keys are supported.",
+		},
+		pkFlags,
+		func(ctx context.Context, _ []string) error {
+			return execPKCS11(ctx, pkFlags, io)
+		},
+	)
+}
+
+func (f *pkcs11Flags) RegisterFlags(fs *flag.FlagSet) {
+	f.ServerFlags.RegisterFlags(fs)
+
+	fs.StringVar(
+		&f.module,
+		"module",
+		defaultPKCS11Flags.module,
+		"path to the PKCS#11 module library (e.g. /usr/lib/softhsm/libsofthsm2.so)",
+	)
+
+	fs.StringVar(
+		&f.tokenLabel,
+		"token-label",
+		defaultPKCS11Flags.tokenLabel,
+		"label of the token holding the signing key",
+	)
+
+	fs.StringVar(
+		&f.keyLabel,
+		"key-label",
+		defaultPKCS11Flags.keyLabel,
+		"label of the signing key pair in the token",
+	)
+
+	fs.BoolVar(
+		&f.insecurePinStdin,
+		"insecure-pin-stdin",
+		defaultPKCS11Flags.insecurePinStdin,
+		"WARNING! take the token user PIN from stdin",
+	)
+}
+
+func (f *pkcs11Flags) validate() error {
+	switch {
+	case f.module == "":
+		return errModuleNotSet
+	case f.tokenLabel == "":
+		return errTokenLabelNotSet
+	case f.keyLabel == "":
+		return errKeyLabelNotSet
+	default:
+		return nil
+	}
+}
+
+func execPKCS11(ctx context.Context, pkFlags *pkcs11Flags, io commands.IO) error {
+	// Module, token and key must be provided.
+	if err := pkFlags.validate(); err != nil {
+		io.ErrPrintfln("error: %v\n", err)
+		return flag.ErrHelp
+	}
+
+	// Initialize the PKCS#11 signer with the provided token and key.
+	pkcs11Signer, err := newPKCS11Signer(pkFlags, io)
+	if err != nil {
+		return err
+	}
+
+	// Run the remote signer server with the PKCS#11 signer.
+	return common.RunSignerServer(ctx, &pkFlags.ServerFlags, pkcs11Signer, io)
+}
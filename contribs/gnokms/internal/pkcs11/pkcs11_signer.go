@@ -0,0 +1,373 @@
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	tmsecp256k1 "github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	p11 "github.com/miekg/pkcs11"
+	"go.uber.org/multierr"
+)
+
+// PKCS#11 v3.0 EdDSA mechanism, not defined by the miekg/pkcs11 package.
+const ckmEdDSA = 0x00001057
+
+// Curve identifiers found in the CKA_EC_PARAMS attribute of the keys.
+var (
+	oidEd25519   = asn1.ObjectIdentifier{1, 3, 101, 112}
+	oidSecp256k1 = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+
+	// PKCS#11 v3.0 also allows to identify Edwards curves by name.
+	nameEd25519 = "edwards25519"
+)
+
+var (
+	errModuleLoad         = errors.New("unable to load PKCS#11 module")
+	errTokenNotFound      = errors.New("token not found")
+	errKeyNotFound        = errors.New("key not found")
+	errMultipleKeysFound  = errors.New("multiple keys found with the same label")
+	errUnsupportedCurve   = errors.New("unsupported key curve: only ed25519 and secp256k1 are supported")
+	errInvalidECPoint     = errors.New("invalid EC point")
+	errInvalidSignature   = errors.New("invalid signature returned by the token")
+	errKeyPairMismatch    = errors.New("private and public keys do not match")
+	errSignerSelfTestFail = errors.New("signer self-test failed")
+)
+
+// pkcs11Signer is a gnokms signer based on a key held in a PKCS#11 token.
+type pkcs11Signer struct {
+	mux sync.Mutex // a PKCS#11 session must not be used concurrently
+
+	ctx        *p11.Ctx
+	session    p11.SessionHandle
+	hasSession bool
+	loggedIn   bool
+
+	privKey p11.ObjectHandle
+	pubKey  crypto.PubKey
+}
+
+// pkcs11Signer type implements types.Signer.
+var _ types.Signer = (*pkcs11Signer)(nil)
+
+// PubKey implements types.Signer.
+func (ps *pkcs11Signer) PubKey() crypto.PubKey {
+	return ps.pubKey
+}
+
+// Sign implements types.Signer.
+func (ps *pkcs11Signer) Sign(signBytes []byte) ([]byte, error) {
+	ps.mux.Lock()
+	defer ps.mux.Unlock()
+
+	switch ps.pubKey.(type) {
+	case ed25519.PubKeyEd25519:
+		return ps.sign(ckmEdDSA, signBytes)
+
+	case tmsecp256k1.PubKeySecp256k1:
+		// CKM_ECDSA signs a digest, hashed with SHA256 like tm2 secp256k1 keys.
+		signature, err := ps.sign(p11.CKM_ECDSA, crypto.Sha256(signBytes))
+		if err != nil {
+			return nil, err
+		}
+
+		return normalizeECDSASignature(signature)
+
+	default:
+		return nil, errUnsupportedCurve
+	}
+}
+
+// sign signs the message with the private key using the given mechanism.
+func (ps *pkcs11Signer) sign(mechanism uint, message []byte) ([]byte, error) {
+	if err := ps.ctx.SignInit(
+		ps.session,
+		[]*p11.Mechanism{p11.NewMechanism(mechanism, nil)},
+		ps.privKey,
+	); err != nil {
+		return nil, fmt.Errorf("unable to initialize signing: %w", err)
+	}
+
+	signature, err := ps.ctx.Sign(ps.session, message)
+	if err != nil {
+		return nil, fmt.Errorf("unable to sign: %w", err)
+	}
+
+	return signature, nil
+}
+
+// Close implements types.Signer.
+func (ps *pkcs11Signer) Close() error {
+	ps.mux.Lock()
+	defer ps.mux.Unlock()
+
+	var err error
+
+	if ps.loggedIn {
+		err = multierr.Append(err, ps.ctx.Logout(ps.session))
+		ps.loggedIn = false
+	}
+
+	if ps.hasSession {
+		err = multierr.Append(err, ps.ctx.CloseSession(ps.session))
+		ps.hasSession = false
+	}
+
+	if ps.ctx != nil {
+		err = multierr.Append(err, ps.ctx.Finalize())
+		ps.ctx.Destroy()
+		ps.ctx = nil
+	}
+
+	return err
+}
+
+// newPKCS11Signer initializes a new PKCS#11 signer using the key pair labeled keyLabel
+// in the token labeled tokenLabel, and asks the user for the token PIN.
+func newPKCS11Signer(pkFlags *pkcs11Flags, io commands.IO) (_ *pkcs11Signer, err error) {
+	// Load the PKCS#11 module.
+	ctx := p11.New(pkFlags.module)
+	if ctx == nil {
+		return nil, fmt.Errorf("%w: %s", errModuleLoad, pkFlags.module)
+	}
+
+	if err := ctx.Initialize(); err != nil {
+		ctx.Destroy()
+		return nil, fmt.Errorf("unable to initialize PKCS#11 module: %w", err)
+	}
+
+	signer := &pkcs11Signer{ctx: ctx}
+
+	// Release the token and the module on failure.
+	defer func() {
+		if err != nil {
+			signer.Close()
+		}
+	}()
+
+	// Open a session with the token.
+	slot, err := findSlot(ctx, pkFlags.tokenLabel)
+	if err != nil {
+		return nil, err
+	}
+
+	if signer.session, err = ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION); err != nil {
+		return nil, fmt.Errorf("unable to open token session: %w", err)
+	}
+	signer.hasSession = true
+
+	// Get the PIN from the user. The login is not retried since tokens
+	// usually lock themselves after a few failed attempts.
+	pin, err := io.GetPassword("Enter the token user PIN", pkFlags.insecurePinStdin)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get token PIN: %w", err)
+	}
+
+	if err := ctx.Login(signer.session, p11.CKU_USER, pin); err != nil {
+		return nil, fmt.Errorf("unable to login to token: %w", err)
+	}
+	signer.loggedIn = true
+
+	// Find the private key, then read the curve and point of its public key.
+	if signer.privKey, err = findKey(ctx, signer.session, p11.CKO_PRIVATE_KEY, pkFlags.keyLabel); err != nil {
+		return nil, fmt.Errorf("unable to find private key: %w", err)
+	}
+
+	pubKeyObj, err := findKey(ctx, signer.session, p11.CKO_PUBLIC_KEY, pkFlags.keyLabel)
+	if err != nil {
+		return nil, fmt.Errorf("unable to find public key: %w", err)
+	}
+
+	attrs, err := ctx.GetAttributeValue(signer.session, pubKeyObj, []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_EC_PARAMS, nil),
+		p11.NewAttribute(p11.CKA_EC_POINT, nil),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to read public key: %w", err)
+	}
+
+	if signer.pubKey, err = pubKeyFromECAttributes(attrs[0].Value, attrs[1].Value); err != nil {
+		return nil, err
+	}
+
+	// Make sure the private key matches the public key before serving.
+	if err := selfTest(signer); err != nil {
+		return nil, err
+	}
+
+	return signer, nil
+}
+
+// findSlot returns the slot of the token with the given label.
+func findSlot(ctx *p11.Ctx, tokenLabel string) (uint, error) {
+	slots, err := ctx.GetSlotList(true)
+	if err != nil {
+		return 0, fmt.Errorf("unable to list token slots: %w", err)
+	}
+
+	for _, slot := range slots {
+		info, err := ctx.GetTokenInfo(slot)
+		if err != nil {
+			continue
+		}
+
+		// Token labels are padded to 32 bytes.
+		if strings.TrimRight(info.Label, " \x00") == tokenLabel {
+			return slot, nil
+		}
+	}
+
+	return 0, fmt.Errorf("%w: %q", errTokenNotFound, tokenLabel)
+}
+
+// findKey returns the single key object of the given class with the given label.
+func findKey(ctx *p11.Ctx, session p11.SessionHandle, class uint, label string) (p11.ObjectHandle, error) {
+	template := []*p11.Attribute{
+		p11.NewAttribute(p11.CKA_CLASS, class),
+		p11.NewAttribute(p11.CKA_LABEL, label),
+	}
+
+	if err := ctx.FindObjectsInit(session, template); err != nil {
+		return 0, err
+	}
+
+	objects, _, err := ctx.FindObjects(session, 2)
+	if ferr := ctx.FindObjectsFinal(session); err == nil {
+		err = ferr
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	switch len(objects) {
+	case 0:
+		return 0, fmt.Errorf("%w: %q", errKeyNotFound, label)
+	case 1:
+		return objects[0], nil
+	default:
+		return 0, fmt.Errorf("%w: %q", errMultipleKeysFound, label)
+	}
+}
+
+// selfTest signs a test message and verifies it with the signer public key.
+func selfTest(signer *pkcs11Signer) error {
+	message := []byte("gnokms pkcs11 self-test")
+
+	signature, err := signer.Sign(message)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errSignerSelfTestFail, err)
+	}
+
+	if !signer.pubKey.VerifyBytes(message, signature) {
+		return fmt.Errorf("%w: %w", errSignerSelfTestFail, errKeyPairMismatch)
+	}
+
+	return nil
+}
+
+// pubKeyFromECAttributes returns the tm2 public key matching the CKA_EC_PARAMS
+// and CKA_EC_POINT attributes of a PKCS#11 public key.
+func pubKeyFromECAttributes(params, point []byte) (crypto.PubKey, error) {
+	var curve asn1.RawValue
+	if rest, err := asn1.Unmarshal(params, &curve); err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("invalid EC params: %x", params)
+	}
+
+	switch {
+	case isCurve(curve, oidEd25519, nameEd25519):
+		raw, err := unwrapECPoint(point, ed25519.PubKeyEd25519Size)
+		if err != nil {
+			return nil, err
+		}
+
+		var pubKey ed25519.PubKeyEd25519
+		copy(pubKey[:], raw)
+
+		return pubKey, nil
+
+	case isCurve(curve, oidSecp256k1, ""):
+		raw, err := unwrapECPoint(point, secp256k1.PubKeyBytesLenUncompressed)
+		if err != nil {
+			return nil, err
+		}
+
+		pub, err := secp256k1.ParsePubKey(raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errInvalidECPoint, err)
+		}
+
+		// tm2 secp256k1 keys are compressed.
+		var pubKey tmsecp256k1.PubKeySecp256k1
+		copy(pubKey[:], pub.SerializeCompressed())
+
+		return pubKey, nil
+
+	default:
+		return nil, errUnsupportedCurve
+	}
+}
+
+// isCurve returns true if the EC params identify the curve with the given
+// object identifier or, if not empty, the given printable name.
+func isCurve(curve asn1.RawValue, oid asn1.ObjectIdentifier, name string) bool {
+	switch {
+	case curve.Class != asn1.ClassUniversal:
+		return false
+
+	case curve.Tag == asn1.TagOID:
+		var id asn1.ObjectIdentifier
+		if _, err := asn1.Unmarshal(curve.FullBytes, &id); err != nil {
+			return false
+		}
+		return id.Equal(oid)
+
+	case curve.Tag == asn1.TagPrintableString:
+		return name != "" && string(curve.Bytes) == name
+
+	default:
+		return false
+	}
+}
+
+// unwrapECPoint returns the raw EC point of the given size. The CKA_EC_POINT
+// attribute is a DER encoded octet string, but some tokens return it raw.
+func unwrapECPoint(point []byte, size int) ([]byte, error) {
+	if len(point) == size {
+		return point, nil
+	}
+
+	var raw []byte
+	if rest, err := asn1.Unmarshal(point, &raw); err != nil || len(rest) != 0 || len(raw) != size {
+		return nil, fmt.Errorf("%w: %x", errInvalidECPoint, point)
+	}
+
+	return raw, nil
+}
+
+// normalizeECDSASignature returns the R || S ECDSA signature in lower-S form,
+// the only form accepted by tm2 secp256k1 keys.
+func normalizeECDSASignature(signature []byte) ([]byte, error) {
+	if len(signature) != 64 {
+		return nil, fmt.Errorf("%w: expected 64 bytes, got %d", errInvalidSignature, len(signature))
+	}
+
+	var s secp256k1.ModNScalar
+	if s.SetByteSlice(signature[32:]) {
+		return nil, fmt.Errorf("%w: S overflows the curve order", errInvalidSignature)
+	}
+
+	if !s.IsOverHalfOrder() {
+		return signature, nil
+	}
+
+	sBytes := s.Negate().Bytes()
+
+	return append(signature[:32:32], sBytes[:]...), nil
+}
@@ -0,0 +1,300 @@
+package pkcs11
+
+import (
+	"encoding/asn1"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	tmsecp256k1 "github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	p11 "github.com/miekg/pkcs11"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func marshalASN1(t *testing.T, value any, params string) []byte {
+	t.Helper()
+
+	bz, err := asn1.MarshalWithParams(value, params)
+	require.NoError(t, err)
+
+	return bz
+}
+
+func TestPubKeyFromECAttributes(t *testing.T) {
+	t.Parallel()
+
+	edPubKey := ed25519.GenPrivKey().PubKey().(ed25519.PubKeyEd25519)
+
+	secpPrivKey := tmsecp256k1.GenPrivKey()
+	secpPubKey := secpPrivKey.PubKey().(tmsecp256k1.PubKeySecp256k1)
+	secpUncompressed := secp256k1.PrivKeyFromBytes(secpPrivKey[:]).PubKey().SerializeUncompressed()
+
+	t.Run("ed25519 with oid", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, oidEd25519, ""),
+			marshalASN1(t, edPubKey[:], ""),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, edPubKey, pubKey)
+	})
+
+	t.Run("ed25519 with name and raw point", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, nameEd25519, "printable"),
+			edPubKey[:],
+		)
+		require.NoError(t, err)
+		assert.Equal(t, edPubKey, pubKey)
+	})
+
+	t.Run("secp256k1 with oid", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, oidSecp256k1, ""),
+			marshalASN1(t, secpUncompressed, ""),
+		)
+		require.NoError(t, err)
+		assert.Equal(t, secpPubKey, pubKey)
+	})
+
+	t.Run("secp256k1 with raw point", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, oidSecp256k1, ""),
+			secpUncompressed,
+		)
+		require.NoError(t, err)
+		assert.Equal(t, secpPubKey, pubKey)
+	})
+
+	t.Run("unsupported curve", func(t *testing.T) {
+		t.Parallel()
+
+		// prime256v1
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, asn1.ObjectIdentifier{1, 2, 840, 10045, 3, 1, 7}, ""),
+			secpUncompressed,
+		)
+		require.Nil(t, pubKey)
+		assert.ErrorIs(t, err, errUnsupportedCurve)
+	})
+
+	t.Run("invalid params", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes([]byte{0x06, 0xff}, edPubKey[:])
+		require.Nil(t, pubKey)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid point size", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, oidEd25519, ""),
+			marshalASN1(t, edPubKey[:31], ""),
+		)
+		require.Nil(t, pubKey)
+		assert.ErrorIs(t, err, errInvalidECPoint)
+	})
+
+	t.Run("invalid secp256k1 point", func(t *testing.T) {
+		t.Parallel()
+
+		pubKey, err := pubKeyFromECAttributes(
+			marshalASN1(t, oidSecp256k1, ""),
+			make([]byte, secp256k1.PubKeyBytesLenUncompressed),
+		)
+		require.Nil(t, pubKey)
+		assert.ErrorIs(t, err, errInvalidECPoint)
+	})
+}
+
+func TestNormalizeECDSASignature(t *testing.T) {
+	t.Parallel()
+
+	privKey := tmsecp256k1.GenPrivKey()
+	message := []byte("message")
+
+	// tm2 secp256k1 signatures are already in lower-S form.
+	lowS, err := privKey.Sign(message)
+	require.NoError(t, err)
+
+	// Build the equivalent higher-S signature, as a token may return it.
+	var s secp256k1.ModNScalar
+	s.SetByteSlice(lowS[32:])
+	highSBytes := s.Negate().Bytes()
+	highS := append(append([]byte{}, lowS[:32]...), highSBytes[:]...)
+	require.False(t, privKey.PubKey().VerifyBytes(message, highS))
+
+	t.Run("lower-S", func(t *testing.T) {
+		t.Parallel()
+
+		signature, err := normalizeECDSASignature(lowS)
+		require.NoError(t, err)
+		assert.Equal(t, lowS, signature)
+	})
+
+	t.Run("higher-S", func(t *testing.T) {
+		t.Parallel()
+
+		signature, err := normalizeECDSASignature(highS)
+		require.NoError(t, err)
+		assert.Equal(t, lowS, signature)
+		assert.True(t, privKey.PubKey().VerifyBytes(message, signature))
+	})
+
+	t.Run("invalid length", func(t *testing.T) {
+		t.Parallel()
+
+		signature, err := normalizeECDSASignature(lowS[:63])
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, errInvalidSignature)
+	})
+}
+
+// The following test requires an initialized PKCS#11 token, e.g. using SoftHSM:
+//
+//	softhsm2-util --init-token --free --label gnokms --pin 1234 --so-pin 1234
+//	export GNOKMS_TEST_PKCS11_MODULE=/usr/lib/softhsm/libsofthsm2.so
+//	export GNOKMS_TEST_PKCS11_TOKEN_LABEL=gnokms
+//	export GNOKMS_TEST_PKCS11_PIN=1234
+func TestPKCS11SignerToken(t *testing.T) {
+	module := os.Getenv("GNOKMS_TEST_PKCS11_MODULE")
+	tokenLabel := os.Getenv("GNOKMS_TEST_PKCS11_TOKEN_LABEL")
+	pin := os.Getenv("GNOKMS_TEST_PKCS11_PIN")
+	if module == "" || tokenLabel == "" || pin == "" {
+		t.Skip("PKCS#11 token not configured")
+	}
+
+	// PKCS#11 v3.0 EdDSA key generation mechanism.
+	const ckmECEdwardsKeyPairGen = 0x00001055
+
+	testTable := []struct {
+		name      string
+		mechanism uint
+		params    []byte
+	}{
+		{"ed25519", ckmECEdwardsKeyPairGen, marshalASN1(t, nameEd25519, "printable")},
+		{"secp256k1", p11.CKM_EC_KEY_PAIR_GEN, marshalASN1(t, oidSecp256k1, "")},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			keyLabel := fmt.Sprintf("gnokms-test-%s", xid.New())
+			generateTokenKey(t, module, tokenLabel, pin, keyLabel, testCase.mechanism, testCase.params)
+
+			// Create a stdin with the PIN.
+			io := commands.NewTestIO()
+			io.SetIn(strings.NewReader(pin + "\n"))
+
+			signer, err := newPKCS11Signer(
+				&pkcs11Flags{
+					module:           module,
+					tokenLabel:       tokenLabel,
+					keyLabel:         keyLabel,
+					insecurePinStdin: true,
+				},
+				io,
+			)
+			require.NoError(t, err)
+			defer signer.Close()
+
+			message := []byte("message")
+			signature, err := signer.Sign(message)
+			require.NoError(t, err)
+			assert.True(t, signer.PubKey().VerifyBytes(message, signature))
+		})
+	}
+
+	t.Run("unknown key", func(t *testing.T) {
+		io := commands.NewTestIO()
+		io.SetIn(strings.NewReader(pin + "\n"))
+
+		signer, err := newPKCS11Signer(
+			&pkcs11Flags{
+				module:           module,
+				tokenLabel:       tokenLabel,
+				keyLabel:         "unknown",
+				insecurePinStdin: true,
+			},
+			io,
+		)
+		require.Nil(t, signer)
+		assert.ErrorIs(t, err, errKeyNotFound)
+	})
+
+	t.Run("unknown token", func(t *testing.T) {
+		signer, err := newPKCS11Signer(
+			&pkcs11Flags{
+				module:     module,
+				tokenLabel: "unknown",
+				keyLabel:   "unknown",
+			},
+			commands.NewTestIO(),
+		)
+		require.Nil(t, signer)
+		assert.ErrorIs(t, err, errTokenNotFound)
+	})
+}
+
+// generateTokenKey generates a key pair in the token, destroyed at the end of the test.
+func generateTokenKey(
+	t *testing.T,
+	module, tokenLabel, pin, keyLabel string,
+	mechanism uint,
+	params []byte,
+) {
+	t.Helper()
+
+	ctx := p11.New(module)
+	require.NotNil(t, ctx)
+	require.NoError(t, ctx.Initialize())
+
+	slot, err := findSlot(ctx, tokenLabel)
+	require.NoError(t, err)
+
+	session, err := ctx.OpenSession(slot, p11.CKF_SERIAL_SESSION|p11.CKF_RW_SESSION)
+	require.NoError(t, err)
+	require.NoError(t, ctx.Login(session, p11.CKU_USER, pin))
+
+	pub, priv, err := ctx.GenerateKeyPair(
+		session,
+		[]*p11.Mechanism{p11.NewMechanism(mechanism, nil)},
+		[]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_TOKEN, true),
+			p11.NewAttribute(p11.CKA_VERIFY, true),
+			p11.NewAttribute(p11.CKA_EC_PARAMS, params),
+			p11.NewAttribute(p11.CKA_LABEL, keyLabel),
+		},
+		[]*p11.Attribute{
+			p11.NewAttribute(p11.CKA_TOKEN, true),
+			p11.NewAttribute(p11.CKA_PRIVATE, true),
+			p11.NewAttribute(p11.CKA_SENSITIVE, true),
+			p11.NewAttribute(p11.CKA_SIGN, true),
+			p11.NewAttribute(p11.CKA_LABEL, keyLabel),
+		},
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		ctx.DestroyObject(session, pub)
+		ctx.DestroyObject(session, priv)
+		ctx.Logout(session)
+		ctx.CloseSession(session)
+		ctx.Finalize()
+		ctx.Destroy()
+	})
+}
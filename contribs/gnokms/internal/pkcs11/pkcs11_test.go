@@ -0,0 +1,65 @@
+package pkcs11
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewPKCS11Cmd(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name string
+		args []string
+	}{
+		{"without flags", []string{}},
+		{"without token label", []string{"--module", "/invalid/module.so", "--key-label", "key"}},
+		{"without key label", []string{"--module", "/invalid/module.so", "--token-label", "token"}},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			// Create the command.
+			cmd := NewPKCS11Cmd(commands.NewTestIO())
+			require.NotNil(t, cmd)
+			cmd.SetOutput(commands.WriteNopCloser(new(bytes.Buffer)))
+
+			// Create a context with a 5s timeout.
+			ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancelFn()
+
+			// Run the command.
+			cmdErr := cmd.ParseAndRun(ctx, testCase.args)
+			assert.ErrorIs(t, cmdErr, flag.ErrHelp)
+		})
+	}
+
+	t.Run("invalid module", func(t *testing.T) {
+		t.Parallel()
+
+		// Create the command.
+		cmd := NewPKCS11Cmd(commands.NewTestIO())
+		require.NotNil(t, cmd)
+
+		// Create a context with a 5s timeout.
+		ctx, cancelFn := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelFn()
+
+		// Run the command.
+		cmdErr := cmd.ParseAndRun(ctx, []string{
+			"--module", "/invalid/module.so",
+			"--token-label", "token",
+			"--key-label", "key",
+		})
+		assert.ErrorIs(t, cmdErr, errModuleLoad)
+	})
+}
@@ -0,0 +1,70 @@
+package state
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gnolang/gno/contribs/gnokms/internal/common"
+	fstate "github.com/gnolang/gno/tm2/pkg/bft/privval/state"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	osm "github.com/gnolang/gno/tm2/pkg/os"
+)
+
+// NewStateCmd creates the gnokms state subcommand.
+func NewStateCmd(io commands.IO) *commands.Command {
+	stateFlags := &common.StateFlags{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "state",
+			ShortUsage: "state [flags]",
+			ShortHelp:  "prints the last sign state of gnokms",
+			LongHelp:   "Prints the height, round and step of the last vote or proposal signed by gnokms. gnokms refuses to sign anything conflicting with this state to prevent double signing.",
+		},
+		stateFlags,
+		func(_ context.Context, _ []string) error {
+			return execState(stateFlags, io)
+		},
+	)
+}
+
+// stepName returns the name of the given consensus step.
+func stepName(step fstate.Step) string {
+	switch step {
+	case fstate.StepPropose:
+		return "propose"
+	case fstate.StepPrevote:
+		return "prevote"
+	case fstate.StepPrecommit:
+		return "precommit"
+	default:
+		return "none"
+	}
+}
+
+func execState(stateFlags *common.StateFlags, io commands.IO) error {
+	// Check if the file exists.
+	if !osm.FileExists(stateFlags.StateFile) {
+		return fmt.Errorf("%s: %s\n%s",
+			"error: sign state file does not exist at path", stateFlags.StateFile,
+			"it is created when starting a gnokms server",
+		)
+	}
+
+	// Load the sign state file.
+	state, err := fstate.LoadFileState(stateFlags.StateFile)
+	if err != nil {
+		return fmt.Errorf("%s: %s\n%s: %w",
+			"error: sign state file is invalid at path", stateFlags.StateFile,
+			"unable to load", err,
+		)
+	}
+
+	// Print the last sign state.
+	io.Printfln("Sign state at path: %q", stateFlags.StateFile)
+	io.Printfln("  height: %d", state.Height)
+	io.Printfln("  round: %d", state.Round)
+	io.Printfln("  step: %s", stepName(state.Step))
+
+	return nil
+}
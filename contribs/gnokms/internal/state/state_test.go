@@ -0,0 +1,72 @@
+package state
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/contribs/gnokms/internal/common"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStateCmd(t *testing.T) {
+	t.Parallel()
+
+	t.Run("non-existent state file", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := NewStateCmd(commands.NewTestIO())
+		assert.Error(t, cmd.ParseAndRun(
+			context.Background(),
+			[]string{"--state-file", filepath.Join(t.TempDir(), "non-existent")},
+		))
+	})
+
+	t.Run("invalid state file", func(t *testing.T) {
+		t.Parallel()
+
+		filePath := filepath.Join(t.TempDir(), "invalid")
+		require.NoError(t, os.WriteFile(filePath, []byte("invalid"), 0o600))
+
+		cmd := NewStateCmd(commands.NewTestIO())
+		assert.Error(t, cmd.ParseAndRun(context.Background(), []string{"--state-file", filePath}))
+	})
+
+	t.Run("valid state file", func(t *testing.T) {
+		t.Parallel()
+
+		// Sign a precommit to update the state.
+		filePath := filepath.Join(t.TempDir(), "sign_state.json")
+		stateSigner, err := common.NewStateSigner(types.NewMockSigner(), filePath, log.NewNoopLogger())
+		require.NoError(t, err)
+
+		vote := &types.Vote{
+			Type:      types.PrecommitType,
+			Height:    42,
+			Round:     3,
+			Timestamp: time.Now(),
+		}
+		_, err = stateSigner.Sign(vote.SignBytes("test-chain"))
+		require.NoError(t, err)
+
+		// Print the state.
+		buffer := new(bytes.Buffer)
+		io := commands.NewTestIO()
+		io.SetOut(commands.WriteNopCloser(buffer))
+
+		cmd := NewStateCmd(io)
+		require.NoError(t, cmd.ParseAndRun(context.Background(), []string{"--state-file", filePath}))
+
+		output := buffer.String()
+		assert.Contains(t, output, "height: 42")
+		assert.Contains(t, output, "round: 3")
+		assert.Contains(t, output, "step: precommit")
+	})
+}
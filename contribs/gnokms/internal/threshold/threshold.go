@@ -0,0 +1,91 @@
+package threshold
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/gnolang/gno/contribs/gnokms/internal/common"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+)
+
+var errDuplicatePubKey = errors.New("duplicate public key")
+
+type thresholdFlags struct {
+	threshold int
+}
+
+func (f *thresholdFlags) RegisterFlags(fs *flag.FlagSet) {
+	fs.IntVar(
+		&f.threshold,
+		"threshold",
+		0,
+		"minimum number of gnokms signatures required to sign on behalf of the validator",
+	)
+}
+
+// NewThresholdCmd creates the gnokms threshold subcommand.
+func NewThresholdCmd(io commands.IO) *commands.Command {
+	thresholdFlags := &thresholdFlags{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "threshold",
+			ShortUsage: "threshold -threshold <t> <pub_key> [<pub_key>...]",
+			ShortHelp:  "prints the validator info of a t-of-n threshold signer",
+			LongHelp: "Prints the validator info of a threshold signer requiring t signatures out of the n gnokms instances whose bech32 public keys are given. " +
+				"The public keys must be given in the same order as the server addresses of the validator threshold signer config.",
+		},
+		thresholdFlags,
+		func(_ context.Context, args []string) error {
+			return execThreshold(thresholdFlags, args, io)
+		},
+	)
+}
+
+func execThreshold(thresholdFlags *thresholdFlags, args []string, io commands.IO) error {
+	// Check the threshold against the number of public keys.
+	if len(args) == 0 || thresholdFlags.threshold < 1 || thresholdFlags.threshold > len(args) {
+		return flag.ErrHelp
+	}
+
+	// Decode the public keys of the gnokms instances.
+	pubKeys := make([]crypto.PubKey, len(args))
+	for i, arg := range args {
+		pubKey, err := crypto.PubKeyFromBech32(arg)
+		if err != nil {
+			return fmt.Errorf("invalid public key %q: %w", arg, err)
+		}
+
+		// A gnokms holding a key twice could reach the threshold by itself.
+		for j := range i {
+			if pubKeys[j].Equals(pubKey) {
+				return fmt.Errorf("%w: %q", errDuplicatePubKey, arg)
+			}
+		}
+
+		pubKeys[i] = pubKey
+	}
+
+	// Build the validator multisig public key.
+	pubKey := multisig.PubKeyMultisigThreshold{
+		K:       uint(thresholdFlags.threshold),
+		PubKeys: pubKeys,
+	}
+	if err := types.ValidateValidatorPubKey(pubKey); err != nil {
+		return err
+	}
+
+	validatorInfo, err := common.ValidatorInfo(pubKey, "gnokms_threshold_signer")
+	if err != nil {
+		return err
+	}
+
+	io.Println(validatorInfo)
+
+	return nil
+}
@@ -0,0 +1,93 @@
+package threshold
+
+import (
+	"bytes"
+	"context"
+	"flag"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewThresholdCmd(t *testing.T) {
+	t.Parallel()
+
+	pubKeys := []crypto.PubKey{
+		ed25519.GenPrivKey().PubKey(),
+		secp256k1.GenPrivKey().PubKey(),
+		ed25519.GenPrivKey().PubKey(),
+	}
+	bech32PubKeys := make([]string, len(pubKeys))
+	for i, pubKey := range pubKeys {
+		bech32PubKeys[i] = pubKey.String()
+	}
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		t.Parallel()
+
+		testTable := []struct {
+			name string
+			args []string
+		}{
+			{"without public keys", []string{"--threshold", "1"}},
+			{"without threshold", bech32PubKeys},
+			{"threshold above public keys", append([]string{"--threshold", "4"}, bech32PubKeys...)},
+		}
+
+		for _, testCase := range testTable {
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				cmd := NewThresholdCmd(commands.NewTestIO())
+				cmd.SetOutput(commands.WriteNopCloser(new(bytes.Buffer)))
+				assert.ErrorIs(t, cmd.ParseAndRun(context.Background(), testCase.args), flag.ErrHelp)
+			})
+		}
+	})
+
+	t.Run("invalid public key", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := NewThresholdCmd(commands.NewTestIO())
+		assert.Error(t, cmd.ParseAndRun(context.Background(), []string{"--threshold", "1", "invalid"}))
+	})
+
+	t.Run("duplicate public key", func(t *testing.T) {
+		t.Parallel()
+
+		cmd := NewThresholdCmd(commands.NewTestIO())
+		assert.ErrorIs(t, cmd.ParseAndRun(
+			context.Background(),
+			[]string{"--threshold", "1", bech32PubKeys[0], bech32PubKeys[1], bech32PubKeys[0]},
+		), errDuplicatePubKey)
+	})
+
+	t.Run("valid public keys", func(t *testing.T) {
+		t.Parallel()
+
+		io := commands.NewTestIO()
+		output := new(bytes.Buffer)
+		io.SetOut(commands.WriteNopCloser(output))
+
+		cmd := NewThresholdCmd(io)
+		require.NoError(t, cmd.ParseAndRun(context.Background(), append([]string{"--threshold", "2"}, bech32PubKeys...)))
+
+		// The printed bech32 public key is the 2-of-3 multisig of the public keys, in order.
+		expected := multisig.NewPubKeyMultisigThreshold(2, pubKeys)
+		assert.Contains(t, output.String(), crypto.PubKeyToBech32(expected))
+		assert.Contains(t, output.String(), expected.Address().String())
+		assert.Contains(t, output.String(), amino.GetTypeURL(expected))
+
+		// The bech32 public key can be decoded, e.g. by gnogenesis.
+		decoded, err := crypto.PubKeyFromBech32(crypto.PubKeyToBech32(expected))
+		require.NoError(t, err)
+		assert.True(t, expected.Equals(decoded))
+	})
+}
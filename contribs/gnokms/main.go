@@ -6,6 +6,9 @@ import (
 
 	"github.com/gnolang/gno/contribs/gnokms/internal/auth"
 	"github.com/gnolang/gno/contribs/gnokms/internal/gnokey"
+	"github.com/gnolang/gno/contribs/gnokms/internal/pkcs11"
+	"github.com/gnolang/gno/contribs/gnokms/internal/state"
+	"github.com/gnolang/gno/contribs/gnokms/internal/threshold"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 )
 
@@ -24,6 +27,9 @@ func main() {
 	cmd.AddSubCommands(
 		auth.NewAuthCmd(io),
 		gnokey.NewGnokeyCmd(io),
+		pkcs11.NewPKCS11Cmd(io),
+		state.NewStateCmd(io),
+		threshold.NewThresholdCmd(io),
 	)
 
 	cmd.Execute(context.Background(), os.Args[1:])
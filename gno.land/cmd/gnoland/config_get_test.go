@@ -508,6 +508,96 @@ func TestConfig_Get_Events(t *testing.T) {
 	verifyGetTestTableCommon(t, testTable)
 }
 
+func TestConfig_Get_Pruning(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testGetCase{
+		{
+			"keep recent",
+			"pruning.keep_recent",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Pruning.KeepRecent, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
+		{
+			"keep every",
+			"pruning.keep_every",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Pruning.KeepEvery, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
+	}
+
+	verifyGetTestTableCommon(t, testTable)
+}
+
+func TestConfig_Get_StateSync(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testGetCase{
+		{
+			"enable toggle",
+			"state_sync.enable",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.Enable, unmarshalJSONCommon[bool](t, value))
+			},
+			false,
+		},
+		{
+			"rpc servers",
+			"state_sync.rpc_servers",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.RPCServers, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"trust height",
+			"state_sync.trust_height",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.TrustHeight, unmarshalJSONCommon[int64](t, value))
+			},
+			false,
+		},
+		{
+			"trust hash",
+			"state_sync.trust_hash",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.TrustHash, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"trust period",
+			"state_sync.trust_period",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.TrustPeriod, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
+		{
+			"discovery time",
+			"state_sync.discovery_time",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.DiscoveryTime, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
+		{
+			"chunk timeout",
+			"state_sync.chunk_timeout",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.StateSync.ChunkTimeout, unmarshalJSONCommon[time.Duration](t, value))
+			},
+			false,
+		},
+	}
+
+	verifyGetTestTableCommon(t, testTable)
+}
+
 func TestConfig_Get_P2P(t *testing.T) {
 	t.Parallel()
 
@@ -769,6 +859,30 @@ func TestConfig_Get_RPC(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"max subscription clients",
+			"rpc.max_subscription_clients",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RPC.MaxSubscriptionClients, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
+		{
+			"max subscriptions per client",
+			"rpc.max_subscriptions_per_client",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RPC.MaxSubscriptionsPerClient, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
+		{
+			"subscription buffer size",
+			"rpc.subscription_buffer_size",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.RPC.SubscriptionBufferSize, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
 		{
 			"tx commit broadcast timeout",
 			"rpc.timeout_broadcast_tx_commit",
@@ -906,6 +1020,22 @@ func TestConfig_Get_Mempool(t *testing.T) {
 			},
 			false,
 		},
+		{
+			"type",
+			"mempool.type",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.Type, unmarshalJSONCommon[string](t, value))
+			},
+			false,
+		},
+		{
+			"max txs per sender",
+			"mempool.max_txs_per_sender",
+			func(loadedCfg *config.Config, value []byte) {
+				assert.Equal(t, loadedCfg.Mempool.MaxTxsPerSender, unmarshalJSONCommon[int](t, value))
+			},
+			false,
+		},
 	}
 
 	verifyGetTestTableCommon(t, testTable)
@@ -430,6 +430,104 @@ func TestConfig_Set_Events(t *testing.T) {
 	verifySetTestTableCommon(t, testTable)
 }
 
+func TestConfig_Set_Pruning(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testSetCase{
+		{
+			"keep recent updated",
+			[]string{
+				"pruning.keep_recent",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Pruning.KeepRecent))
+			},
+		},
+		{
+			"keep every updated",
+			[]string{
+				"pruning.keep_every",
+				"100",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Pruning.KeepEvery))
+			},
+		},
+	}
+
+	verifySetTestTableCommon(t, testTable)
+}
+
+func TestConfig_Set_StateSync(t *testing.T) {
+	t.Parallel()
+
+	testTable := []testSetCase{
+		{
+			"rpc servers updated",
+			[]string{
+				"state_sync.rpc_servers",
+				"tcp://127.0.0.1:26657,tcp://127.0.0.2:26657",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.RPCServers)
+			},
+		},
+		{
+			"trust height updated",
+			[]string{
+				"state_sync.trust_height",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.StateSync.TrustHeight))
+			},
+		},
+		{
+			"trust hash updated",
+			[]string{
+				"state_sync.trust_hash",
+				"0A0B0C",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.TrustHash)
+			},
+		},
+		{
+			"trust period updated",
+			[]string{
+				"state_sync.trust_period",
+				"24h0m0s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.TrustPeriod.String())
+			},
+		},
+		{
+			"discovery time updated",
+			[]string{
+				"state_sync.discovery_time",
+				"1m0s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.DiscoveryTime.String())
+			},
+		},
+		{
+			"chunk timeout updated",
+			[]string{
+				"state_sync.chunk_timeout",
+				"10s",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.StateSync.ChunkTimeout.String())
+			},
+		},
+	}
+
+	verifySetTestTableCommon(t, testTable)
+}
+
 func TestConfig_Set_P2P(t *testing.T) {
 	t.Parallel()
 
@@ -669,6 +767,36 @@ func TestConfig_Set_RPC(t *testing.T) {
 				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.MaxOpenConnections))
 			},
 		},
+		{
+			"max subscription clients updated",
+			[]string{
+				"rpc.max_subscription_clients",
+				"10",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.MaxSubscriptionClients))
+			},
+		},
+		{
+			"max subscriptions per client updated",
+			[]string{
+				"rpc.max_subscriptions_per_client",
+				"10",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.MaxSubscriptionsPerClient))
+			},
+		},
+		{
+			"subscription buffer size updated",
+			[]string{
+				"rpc.subscription_buffer_size",
+				"10",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.RPC.SubscriptionBufferSize))
+			},
+		},
 		{
 			"tx commit broadcast timeout updated",
 			[]string{
@@ -804,6 +932,26 @@ func TestConfig_Set_Mempool(t *testing.T) {
 				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Mempool.CacheSize))
 			},
 		},
+		{
+			"type updated",
+			[]string{
+				"mempool.type",
+				"priority",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, loadedCfg.Mempool.Type)
+			},
+		},
+		{
+			"max txs per sender updated",
+			[]string{
+				"mempool.max_txs_per_sender",
+				"100",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Mempool.MaxTxsPerSender))
+			},
+		},
 	}
 
 	verifySetTestTableCommon(t, testTable)
@@ -833,6 +981,26 @@ func TestConfig_Set_Application(t *testing.T) {
 				assert.Equal(t, types.PruneStrategy(value), loadedCfg.Application.PruneStrategy)
 			},
 		},
+		{
+			"snapshot interval updated",
+			[]string{
+				"application.snapshot_interval",
+				"1000",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.SnapshotInterval))
+			},
+		},
+		{
+			"snapshot keep recent updated",
+			[]string{
+				"application.snapshot_keep_recent",
+				"5",
+			},
+			func(loadedCfg *config.Config, value string) {
+				assert.Equal(t, value, fmt.Sprintf("%d", loadedCfg.Application.SnapshotKeepRecent))
+			},
+		},
 	}
 
 	verifySetTestTableCommon(t, testTable)
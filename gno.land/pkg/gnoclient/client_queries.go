@@ -127,6 +127,29 @@ func (c *Client) QEval(pkgPath string, expression string) (string, *ctypes.Resul
 	return string(qres.Response.Data), qres, nil
 }
 
+// QueryStoreKey retrieves the value of a key of the given store, along with
+// its Merkle proof, at the given height, or the latest one if 0. The proof is
+// verified when the RPC client is a light.VerifyingClient, otherwise the
+// value is trusted from the RPC node. The value is nil if the key is not set.
+func (c *Client) QueryStoreKey(storeName string, key []byte, height int64) ([]byte, *ctypes.ResultABCIQuery, error) {
+	if err := c.validateRPCClient(); err != nil {
+		return nil, nil, err
+	}
+
+	path := fmt.Sprintf(".store/%s/key", storeName)
+	opts := rpcclient.ABCIQueryOptions{Height: height, Prove: true}
+
+	qres, err := c.RPCClient.ABCIQueryWithOptions(context.Background(), path, key, opts)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "query store key")
+	}
+	if qres.Response.Error != nil {
+		return nil, nil, errors.Wrapf(qres.Response.Error, "QueryStoreKey failed: log:%s", qres.Response.Log)
+	}
+
+	return qres.Response.Value, qres, nil
+}
+
 // Block gets the latest block at height, if any
 // Height must be larger than 0
 func (c *Client) Block(height int64) (*ctypes.ResultBlock, error) {
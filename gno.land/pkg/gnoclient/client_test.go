@@ -13,6 +13,7 @@ import (
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gno.land/pkg/sdk/vm"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	rpcclient "github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -1241,6 +1242,34 @@ func TestLatestBlockHeight(t *testing.T) {
 	assert.Equal(t, latestHeight, head)
 }
 
+func TestQueryStoreKey(t *testing.T) {
+	t.Parallel()
+
+	c := &Client{
+		Signer: &mockSigner{},
+		RPCClient: &mockRPCClient{
+			abciQueryWithOptions: func(ctx context.Context, path string, data []byte, opts rpcclient.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+				assert.Equal(t, ".store/main/key", path)
+				assert.Equal(t, []byte("key"), data)
+				assert.Equal(t, rpcclient.ABCIQueryOptions{Height: 5, Prove: true}, opts)
+
+				return &ctypes.ResultABCIQuery{
+					Response: abci.ResponseQuery{
+						Key:    data,
+						Value:  []byte("value"),
+						Height: opts.Height,
+					},
+				}, nil
+			},
+		},
+	}
+
+	value, qres, err := c.QueryStoreKey("main", []byte("key"), 5)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value"), value)
+	assert.Equal(t, int64(5), qres.Response.Height)
+}
+
 func TestBlockErrors(t *testing.T) {
 	t.Parallel()
 
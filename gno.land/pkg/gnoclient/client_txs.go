@@ -8,6 +8,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -24,11 +25,13 @@ const simulatePath = ".app/simulate"
 
 // BaseTxCfg defines the base transaction configuration, shared by all message types
 type BaseTxCfg struct {
-	GasFee         string // Gas fee
-	GasWanted      int64  // Gas wanted
-	AccountNumber  uint64 // Account number
-	SequenceNumber uint64 // Sequence number
-	Memo           string // Memo
+	GasFee         string         // Gas fee
+	GasWanted      int64          // Gas wanted
+	AccountNumber  uint64         // Account number
+	SequenceNumber uint64         // Sequence number
+	Memo           string         // Memo
+	TimeoutHeight  uint64         // Last block height the tx can be included in, if set
+	FeeGranter     crypto.Address // Account paying the gas fee from its fee allowance, if set
 }
 
 // Call executes one or more MsgCall calls on the blockchain
@@ -74,10 +77,11 @@ func NewCallTx(cfg BaseTxCfg, msgs ...vm.MsgCall) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           cfg.fee(gasFeeCoins),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -124,10 +128,11 @@ func NewRunTx(cfg BaseTxCfg, msgs ...vm.MsgRun) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           cfg.fee(gasFeeCoins),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -174,10 +179,11 @@ func NewSendTx(cfg BaseTxCfg, msgs ...bank.MsgSend) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           cfg.fee(gasFeeCoins),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -224,10 +230,11 @@ func NewAddPackageTx(cfg BaseTxCfg, msgs ...vm.MsgAddPackage) (*std.Tx, error) {
 
 	// Pack transaction
 	return &std.Tx{
-		Msgs:       vmMsgs,
-		Fee:        std.NewFee(cfg.GasWanted, gasFeeCoins),
-		Signatures: nil,
-		Memo:       cfg.Memo,
+		Msgs:          vmMsgs,
+		Fee:           cfg.fee(gasFeeCoins),
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
 	}, nil
 }
 
@@ -1,5 +1,7 @@
 package gnoclient
 
+import "github.com/gnolang/gno/tm2/pkg/std"
+
 func (cfg BaseTxCfg) validateBaseTxConfig() error {
 	if cfg.GasWanted <= 0 {
 		return ErrInvalidGasWanted
@@ -10,3 +12,12 @@ func (cfg BaseTxCfg) validateBaseTxConfig() error {
 
 	return nil
 }
+
+// fee returns the fee of the tx, paid by the fee granter if set.
+func (cfg BaseTxCfg) fee(gasFee std.Coin) std.Fee {
+	fee := std.NewFee(cfg.GasWanted, gasFee)
+	if !cfg.FeeGranter.IsZero() {
+		fee.Granter = cfg.FeeGranter.Bech32()
+	}
+	return fee
+}
@@ -31,6 +31,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
@@ -44,6 +45,8 @@ type AppOptions struct {
 	InitChainerConfig                             // options related to InitChainer
 	MinGasPrices               string             // optional
 	PruneStrategy              types.PruneStrategy
+	SnapshotDir                string            // optional, snapshots are disabled if empty
+	SnapshotOptions            snapshots.Options // options related to the state snapshots
 }
 
 // TestAppOptions provides a "ready" default [AppOptions] for use with
@@ -94,6 +97,14 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 
 	appOpts = append(appOpts, sdk.SetPruningOptions(cfg.PruneStrategy.Options()))
 
+	if cfg.SnapshotDir != "" {
+		snapshotStore, err := snapshots.NewStore(cfg.SnapshotDir)
+		if err != nil {
+			return nil, err
+		}
+		appOpts = append(appOpts, sdk.SetSnapshots(snapshotStore, cfg.SnapshotOptions))
+	}
+
 	// Create BaseApp.
 	baseApp := sdk.NewBaseApp("gnoland", cfg.Logger, cfg.DB, baseKey, mainKey, appOpts...)
 	baseApp.SetAppVersion("dev")
@@ -198,6 +209,11 @@ func NewAppWithOptions(cfg *AppOptions) (abci.Application, error) {
 	baseApp.Router().AddRoute("params", params.NewHandler(prmk))
 	baseApp.Router().AddRoute("vm", vm.NewHandler(vmk))
 
+	// Initialize the VMKeeper again once the state is restored from a snapshot.
+	baseApp.SetRestoreHook(func(ms store.MultiStore) {
+		vmk.Reinitialize(cfg.Logger, ms)
+	})
+
 	// Load latest version.
 	if err := baseApp.LoadLatestVersion(); err != nil {
 		return nil, err
@@ -247,6 +263,13 @@ func NewApp(
 		SkipGenesisSigVerification: genesisCfg.SkipSigVerification,
 		PruneStrategy:              appCfg.PruneStrategy,
 	}
+	if appCfg.SnapshotInterval > 0 {
+		cfg.SnapshotDir = filepath.Join(dataRootDir, "snapshots")
+		cfg.SnapshotOptions = snapshots.Options{
+			Interval:   appCfg.SnapshotInterval,
+			KeepRecent: appCfg.SnapshotKeepRecent,
+		}
+	}
 	if genesisCfg.SkipFailingTxs {
 		cfg.GenesisTxResultHandler = NoopGenesisTxResultHandler
 	}
@@ -360,6 +383,18 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 	// Apply genesis balances.
 	for _, bal := range state.Balances {
 		acc := cfg.acck.NewAccountWithAddress(ctx, bal.Address)
+		if bal.Vesting != nil {
+			// Lock the balance in a vesting account instead.
+			gacc, ok := acc.(*GnoAccount)
+			if !ok {
+				return nil, fmt.Errorf("vesting genesis balance %s must be a gno account, not %T", bal.Address, acc)
+			}
+			vacc, err := bal.Vesting.newAccount(*gacc, bal.Amount)
+			if err != nil {
+				return nil, fmt.Errorf("invalid vesting of genesis balance %s: %w", bal.Address, err)
+			}
+			acc = vacc
+		}
 		cfg.acck.SetAccount(ctx, acc)
 		err := cfg.bankk.SetCoins(ctx, bal.Address, bal.Amount)
 		if err != nil {
@@ -376,10 +411,13 @@ func (cfg InitChainerConfig) loadAppState(ctx sdk.Context, appState any) ([]abci
 	for _, addr := range state.Auth.Params.UnrestrictedAddrs {
 		acc := cfg.acck.GetAccount(ctx, addr)
 		if acc == nil {
-			panic(fmt.Errorf("unrestricted address must be one of the genesis accounts: invalid account %q", addr))
+			return nil, fmt.Errorf("unrestricted address must be one of the genesis accounts: invalid account %q", addr)
 		}
 
-		accr := acc.(*GnoAccount)
+		accr, ok := acc.(gnoAccountUnrestricter)
+		if !ok {
+			return nil, fmt.Errorf("unrestricted address must be a gno account: invalid account %q", addr)
+		}
 		accr.SetUnrestricted()
 		cfg.acck.SetAccount(ctx, acc)
 	}
@@ -526,6 +564,17 @@ func EndBlocker(
 				return true // delete it
 			}
 
+			// Make sure the public key is a valid consensus key
+			if err := bft.ValidateValidatorPubKey(u.PubKey); err != nil {
+				app.Logger().Error(
+					"valset update invalid; invalid pubkey",
+					"address", u.Address.String(),
+					"err", err,
+				)
+
+				return true // delete it
+			}
+
 			return false // keep it, update is valid
 		})
 
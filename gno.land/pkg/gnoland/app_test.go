@@ -34,6 +34,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
@@ -481,6 +482,130 @@ func TestInitChainer_MetadataTxs(t *testing.T) {
 	}
 }
 
+func TestInitChainer_VestingBalances(t *testing.T) {
+	t.Parallel()
+
+	app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+	require.NoError(t, err)
+	bapp := app.(*sdk.BaseApp)
+
+	addr := crypto.AddressFromPreimage([]byte("vesting"))
+	addr2 := crypto.AddressFromPreimage([]byte("periodic"))
+
+	appState := DefaultGenState()
+	appState.Balances = []Balance{
+		{
+			Address: addr,
+			Amount:  std.NewCoins(std.NewCoin("ugnot", 1000)),
+			Vesting: &Vesting{StartTime: 1000, EndTime: 2000},
+		},
+		{
+			Address: addr2,
+			Amount:  std.NewCoins(std.NewCoin("ugnot", 1000)),
+			Vesting: &Vesting{StartTime: 1000, Periods: []std.VestingPeriod{
+				{Length: 100, Amount: std.NewCoins(std.NewCoin("ugnot", 100))},
+				{Length: 1000, Amount: std.NewCoins(std.NewCoin("ugnot", 900))},
+			}},
+		},
+	}
+	appState.Auth.Params.UnrestrictedAddrs = []crypto.Address{addr}
+
+	resp := bapp.InitChain(abci.RequestInitChain{
+		Time:    time.Unix(1250, 0),
+		ChainID: "dev",
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		AppState: appState,
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+	bapp.Commit()
+
+	for addr, expected := range map[crypto.Address]bank.VestingBalance{
+		addr: {
+			Coins:           std.NewCoins(std.NewCoin("ugnot", 1000)),
+			Spendable:       std.NewCoins(std.NewCoin("ugnot", 250)),
+			OriginalVesting: std.NewCoins(std.NewCoin("ugnot", 1000)),
+			Vested:          std.NewCoins(std.NewCoin("ugnot", 250)),
+			Locked:          std.NewCoins(std.NewCoin("ugnot", 750)),
+		},
+		addr2: {
+			Coins:           std.NewCoins(std.NewCoin("ugnot", 1000)),
+			Spendable:       std.NewCoins(std.NewCoin("ugnot", 100)),
+			OriginalVesting: std.NewCoins(std.NewCoin("ugnot", 1000)),
+			Vested:          std.NewCoins(std.NewCoin("ugnot", 100)),
+			Locked:          std.NewCoins(std.NewCoin("ugnot", 900)),
+		},
+	} {
+		qres := bapp.Query(abci.RequestQuery{Path: "bank/vesting/" + addr.String()})
+		require.True(t, qres.IsOK(), "query response: %v", qres)
+
+		var bal bank.VestingBalance
+		require.NoError(t, amino.UnmarshalJSON(qres.Data, &bal))
+		assert.Equal(t, expected, bal)
+	}
+
+	// The vesting accounts are still gno accounts, which can be unrestricted.
+	for addr, expected := range map[crypto.Address]gnoAccountUnrestricter{
+		addr:  &GnoContinuousVestingAccount{},
+		addr2: &GnoPeriodicVestingAccount{},
+	} {
+		qres := bapp.Query(abci.RequestQuery{Path: "auth/accounts/" + addr.String()})
+		require.True(t, qres.IsOK(), "query response: %v", qres)
+
+		acc := expected
+		require.NoError(t, amino.UnmarshalJSON(qres.Data, acc))
+		assert.Equal(t, std.NewCoins(std.NewCoin("ugnot", 1000)), acc.(std.VestingAccount).GetOriginalVesting())
+		assert.Equal(t, addr == appState.Auth.Params.UnrestrictedAddrs[0], acc.IsUnrestricted())
+	}
+
+	t.Run("unknown unrestricted address", func(t *testing.T) {
+		t.Parallel()
+
+		app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+		require.NoError(t, err)
+
+		appState := DefaultGenState()
+		appState.Auth.Params.UnrestrictedAddrs = []crypto.Address{addr}
+
+		resp := app.InitChain(abci.RequestInitChain{
+			ChainID: "dev",
+			ConsensusParams: &abci.ConsensusParams{
+				Block: defaultBlockParams(),
+			},
+			AppState: appState,
+		})
+		assert.False(t, resp.IsOK())
+		assert.ErrorContains(t, resp.Error, "unrestricted address must be one of the genesis accounts")
+	})
+
+	t.Run("invalid vesting", func(t *testing.T) {
+		t.Parallel()
+
+		app, err := NewAppWithOptions(TestAppOptions(memdb.NewMemDB()))
+		require.NoError(t, err)
+
+		appState := DefaultGenState()
+		appState.Balances = []Balance{
+			{
+				Address: addr,
+				Amount:  std.NewCoins(std.NewCoin("ugnot", 1000)),
+				Vesting: &Vesting{StartTime: 2000, EndTime: 1000},
+			},
+		}
+
+		resp := app.InitChain(abci.RequestInitChain{
+			ChainID: "dev",
+			ConsensusParams: &abci.ConsensusParams{
+				Block: defaultBlockParams(),
+			},
+			AppState: appState,
+		})
+		assert.False(t, resp.IsOK())
+		assert.ErrorContains(t, resp.Error, "invalid vesting of genesis balance")
+	})
+}
+
 func TestEndBlocker(t *testing.T) {
 	t.Parallel()
 
@@ -1314,3 +1439,92 @@ func TestPruneStrategyNothing(t *testing.T) {
 	err = db.Close()
 	require.NoError(t, err)
 }
+
+func TestSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	newSnapshotApp := func(t *testing.T) *sdk.BaseApp {
+		t.Helper()
+
+		opts := TestAppOptions(memdb.NewMemDB())
+		opts.SnapshotDir = t.TempDir()
+		opts.SnapshotOptions = snapshots.Options{Interval: 1}
+
+		app, err := NewAppWithOptions(opts)
+		require.NoError(t, err)
+
+		return app.(*sdk.BaseApp)
+	}
+
+	// Commit the genesis, deploying a realm, which is then snapshotted
+	app := newSnapshotApp(t)
+	addr := crypto.AddressFromPreimage([]byte("test1"))
+
+	appState := DefaultGenState()
+	appState.Balances = []Balance{
+		{
+			Address: addr,
+			Amount:  []std.Coin{{Amount: 1e15, Denom: "ugnot"}},
+		},
+	}
+	appState.Txs = []TxWithMetadata{
+		{
+			Tx: std.Tx{
+				Msgs: []std.Msg{vm.NewMsgAddPackage(addr, "gno.land/r/demo", []*std.MemFile{
+					{
+						Name: "demo.gno",
+						Body: "package demo; func Hello() string { return `hello` }",
+					},
+					{
+						Name: "gnomod.toml",
+						Body: gnolang.GenGnoModLatest("gno.land/r/demo"),
+					},
+				})},
+				Fee:        std.Fee{GasWanted: 1e6, GasFee: std.Coin{Amount: 1e6, Denom: "ugnot"}},
+				Signatures: []std.Signature{{}}, // one empty signature
+			},
+		},
+	}
+
+	resp := app.InitChain(abci.RequestInitChain{
+		Time:    time.Now(),
+		ChainID: "dev",
+		ConsensusParams: &abci.ConsensusParams{
+			Block: defaultBlockParams(),
+		},
+		AppState: appState,
+	})
+	require.True(t, resp.IsOK(), "InitChain response: %v", resp)
+	cres := app.Commit()
+	require.NoError(t, app.Close()) // waits for the snapshot
+
+	list := app.ListSnapshots(abci.RequestListSnapshots{})
+	require.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+
+	// Restore the snapshot in a new app
+	restored := newSnapshotApp(t)
+	offer := restored.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: &snapshot, AppHash: cres.Data})
+	require.True(t, offer.IsOK(), "OfferSnapshot response: %v", offer)
+
+	for i := range snapshot.Chunks {
+		chunk := app.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  i,
+		})
+		require.True(t, chunk.IsOK(), "LoadSnapshotChunk response: %v", chunk)
+
+		res := restored.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: i, Chunk: chunk.Chunk})
+		require.True(t, res.IsOK(), "ApplySnapshotChunk response: %v", res)
+	}
+	assert.Equal(t, app.LastCommitID(), restored.LastCommitID())
+
+	// The deployed realm is available to the VM of the restored app
+	qres := restored.Query(abci.RequestQuery{
+		Path: "vm/qeval",
+		Data: []byte("gno.land/r/demo.Hello()"),
+	})
+	require.True(t, qres.IsOK(), "Query response: %v", qres)
+	assert.Equal(t, `("hello" string)`, string(qres.Data))
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"slices"
+	"strconv"
 	"strings"
 
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
@@ -15,6 +16,7 @@ import (
 type Balance struct {
 	Address bft.Address
 	Amount  std.Coins
+	Vesting *Vesting // if set, the amount is locked and vests over time.
 }
 
 func (b *Balance) Verify() error {
@@ -26,11 +28,20 @@ func (b *Balance) Verify() error {
 		return ErrBalanceEmptyAmount
 	}
 
+	if b.Vesting != nil {
+		if _, err := b.Vesting.newAccount(GnoAccount{BaseAccount: std.BaseAccount{Address: b.Address}}, b.Amount); err != nil {
+			return fmt.Errorf("%w: %w", ErrBalanceInvalidVesting, err)
+		}
+	}
+
 	return nil
 }
 
+// Parse parses a balance entry, in the format <address>=<coins>, optionally
+// followed by the vesting schedule of the coins: @<vesting>.
+// See Vesting.Parse for the format of the vesting schedules.
 func (b *Balance) Parse(entry string) error {
-	parts := strings.Split(strings.TrimSpace(entry), "=") // <address>=<coins>
+	parts := strings.Split(strings.TrimSpace(entry), "=") // <address>=<coins>[@<vesting>]
 	if len(parts) != 2 {
 		return fmt.Errorf("malformed entry: %q", entry)
 	}
@@ -42,9 +53,18 @@ func (b *Balance) Parse(entry string) error {
 		return fmt.Errorf("invalid address %q: %w", parts[0], err)
 	}
 
-	b.Amount, err = std.ParseCoins(parts[1])
+	amount, vesting, vests := strings.Cut(parts[1], "@")
+	b.Amount, err = std.ParseCoins(amount)
 	if err != nil {
-		return fmt.Errorf("invalid amount %q: %w", parts[1], err)
+		return fmt.Errorf("invalid amount %q: %w", amount, err)
+	}
+
+	b.Vesting = nil
+	if vests {
+		b.Vesting = new(Vesting)
+		if err := b.Vesting.Parse(vesting); err != nil {
+			return fmt.Errorf("invalid vesting %q: %w", vesting, err)
+		}
 	}
 
 	return nil
@@ -59,9 +79,119 @@ func (b Balance) MarshalAmino() (string, error) {
 }
 
 func (b Balance) String() string {
+	if b.Vesting != nil {
+		return fmt.Sprintf("%s=%s@%s", b.Address.String(), b.Amount.String(), b.Vesting.String())
+	}
 	return fmt.Sprintf("%s=%s", b.Address.String(), b.Amount.String())
 }
 
+const (
+	vestingContinuous = "continuous"
+	vestingPeriodic   = "periodic"
+)
+
+// Vesting is the vesting schedule of the amount of a genesis balance, which is
+// locked in a vesting account at genesis. The amount vests linearly from the
+// start time to the end time, or, if there are periods, in steps at the end of
+// each of them.
+type Vesting struct {
+	StartTime int64               // UNIX time, in seconds.
+	EndTime   int64               // UNIX time, in seconds; unset if there are periods.
+	Periods   []std.VestingPeriod // their amounts add up to the balance amount.
+}
+
+// Parse parses a vesting schedule, in one of the formats:
+//
+//	continuous:<start>:<end>
+//	periodic:<start>:<length>/<coins>[;<length>/<coins>...]
+//
+// The start and end times are UNIX times and the period lengths durations,
+// all in seconds.
+func (v *Vesting) Parse(schedule string) error {
+	parts := strings.SplitN(schedule, ":", 3)
+	if len(parts) != 3 {
+		return fmt.Errorf("malformed vesting schedule: %q", schedule)
+	}
+
+	var err error
+
+	v.StartTime, err = strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid start time %q: %w", parts[1], err)
+	}
+
+	v.EndTime, v.Periods = 0, nil
+	switch parts[0] {
+	case vestingContinuous:
+		v.EndTime, err = strconv.ParseInt(parts[2], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid end time %q: %w", parts[2], err)
+		}
+
+	case vestingPeriodic:
+		for _, entry := range strings.Split(parts[2], ";") {
+			length, amount, ok := strings.Cut(entry, "/")
+			if !ok {
+				return fmt.Errorf("malformed vesting period: %q", entry)
+			}
+
+			var period std.VestingPeriod
+			period.Length, err = strconv.ParseInt(length, 10, 64)
+			if err != nil {
+				return fmt.Errorf("invalid period length %q: %w", length, err)
+			}
+			period.Amount, err = std.ParseCoins(amount)
+			if err != nil {
+				return fmt.Errorf("invalid period amount %q: %w", amount, err)
+			}
+
+			v.Periods = append(v.Periods, period)
+		}
+
+	default:
+		return fmt.Errorf("unknown vesting type %q", parts[0])
+	}
+
+	return nil
+}
+
+func (v Vesting) String() string {
+	if len(v.Periods) == 0 {
+		return fmt.Sprintf("%s:%d:%d", vestingContinuous, v.StartTime, v.EndTime)
+	}
+
+	periods := make([]string, 0, len(v.Periods))
+	for _, period := range v.Periods {
+		periods = append(periods, fmt.Sprintf("%d/%s", period.Length, period.Amount))
+	}
+	return fmt.Sprintf("%s:%d:%s", vestingPeriodic, v.StartTime, strings.Join(periods, ";"))
+}
+
+// newAccount returns the vesting account locking the amount of the balance,
+// from its account. The attributes of the account are kept.
+func (v Vesting) newAccount(acc GnoAccount, amount std.Coins) (gnoAccountUnrestricter, error) {
+	if len(v.Periods) == 0 {
+		cva := std.NewContinuousVestingAccount(acc.BaseAccount, amount, v.StartTime, v.EndTime)
+		return &GnoContinuousVestingAccount{
+			ContinuousVestingAccount: *cva,
+			Attributes:               acc.Attributes,
+		}, cva.Validate()
+	}
+
+	pva := std.NewPeriodicVestingAccount(acc.BaseAccount, v.StartTime, v.Periods)
+	if err := pva.Validate(); err != nil {
+		return nil, err
+	}
+	if !pva.OriginalVesting.IsAllGTE(amount) || !amount.IsAllGTE(pva.OriginalVesting) {
+		return nil, fmt.Errorf("vesting periods total %q, not the balance amount %q", pva.OriginalVesting, amount)
+	}
+
+	return &GnoPeriodicVestingAccount{
+		PeriodicVestingAccount: *pva,
+		Attributes:             acc.Attributes,
+	}, nil
+}
+
 type Balances map[crypto.Address]Balance
 
 func NewBalances() Balances {
@@ -36,6 +36,12 @@ func TestBalance_Verify(t *testing.T) {
 		{"empty amount", Balance{Address: validAddress, Amount: emptyAmount}, true},
 		{"empty address", Balance{Address: bft.Address{}, Amount: nonEmptyAmount}, true},
 		{"valid balance", Balance{Address: validAddress, Amount: nonEmptyAmount}, false},
+		{"valid vesting", Balance{Address: validAddress, Amount: nonEmptyAmount, Vesting: &Vesting{StartTime: 10, EndTime: 20}}, false},
+		{"invalid vesting times", Balance{Address: validAddress, Amount: nonEmptyAmount, Vesting: &Vesting{StartTime: 20, EndTime: 10}}, true},
+		{"vesting periods mismatch", Balance{Address: validAddress, Amount: nonEmptyAmount, Vesting: &Vesting{
+			StartTime: 10,
+			Periods:   []std.VestingPeriod{{Length: 10, Amount: std.NewCoins(std.NewCoin("test", 50))}},
+		}}, true},
 	}
 
 	for _, tc := range tests {
@@ -63,6 +69,24 @@ func TestBalance_Parse(t *testing.T) {
 		{"valid entry", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5=100test", validBalance, false},
 		{"invalid address", "invalid=100test", Balance{}, true},
 		{"incomplete entry", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5", Balance{}, true},
+		{
+			"continuous vesting",
+			"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5=100test@continuous:1000:2000",
+			Balance{Address: validAddress, Amount: validBalance.Amount, Vesting: &Vesting{StartTime: 1000, EndTime: 2000}},
+			false,
+		},
+		{
+			"periodic vesting",
+			"g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5=100test@periodic:1000:60/40test;3600/60test",
+			Balance{Address: validAddress, Amount: validBalance.Amount, Vesting: &Vesting{StartTime: 1000, Periods: []std.VestingPeriod{
+				{Length: 60, Amount: std.NewCoins(std.NewCoin("test", 40))},
+				{Length: 3600, Amount: std.NewCoins(std.NewCoin("test", 60))},
+			}}},
+			false,
+		},
+		{"unknown vesting", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5=100test@linear:1000:2000", Balance{}, true},
+		{"invalid vesting time", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5=100test@continuous:start:2000", Balance{}, true},
+		{"malformed vesting period", "g1jg8mtutu9khhfwc4nxmuhcpftf0pajdhfvsqf5=100test@periodic:1000:100test", Balance{}, true},
 	}
 
 	for _, tc := range tests {
@@ -74,6 +98,7 @@ func TestBalance_Parse(t *testing.T) {
 			} else {
 				assert.NoError(t, err)
 				assert.Equal(t, tc.expected, balance)
+				assert.Equal(t, tc.entry, balance.String())
 			}
 		})
 	}
@@ -43,22 +43,13 @@ func LoadGenesisBalancesFile(path string) (Balances, error) {
 			continue
 		}
 
-		parts := strings.Split(line, "=") // <address>=<coin>
-		if len(parts) != 2 {
-			return nil, errors.New("invalid genesis_balance line: " + line)
+		// <address>=<coin>[@<vesting>]
+		var balance Balance
+		if err := balance.Parse(line); err != nil {
+			return nil, fmt.Errorf("invalid genesis_balance line %q: %w", line, err)
 		}
 
-		addr, err := crypto.AddressFromBech32(parts[0])
-		if err != nil {
-			return nil, fmt.Errorf("invalid balance addr %s: %w", parts[0], err)
-		}
-
-		coins, err := std.ParseCoins(parts[1])
-		if err != nil {
-			return nil, fmt.Errorf("invalid balance coins %s: %w", parts[1], err)
-		}
-
-		balances.Set(addr, coins)
+		balances[balance.Address] = balance
 	}
 
 	return balances, nil
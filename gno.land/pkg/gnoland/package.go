@@ -10,6 +10,8 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	amino.GetCallersDirname(),
 ).WithDependencies().WithTypes(
 	&GnoAccount{}, "Account",
+	&GnoContinuousVestingAccount{}, "ContinuousVestingAccount",
+	&GnoPeriodicVestingAccount{}, "PeriodicVestingAccount",
 	GnoGenesisState{}, "GenesisState",
 	TxWithMetadata{}, "TxWithMetadata",
 	GnoTxMetadata{}, "GnoTxMetadata",
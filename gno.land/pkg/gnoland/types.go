@@ -16,8 +16,9 @@ import (
 )
 
 var (
-	ErrBalanceEmptyAddress = errors.New("balance address is empty")
-	ErrBalanceEmptyAmount  = errors.New("balance amount is empty")
+	ErrBalanceEmptyAddress   = errors.New("balance address is empty")
+	ErrBalanceEmptyAmount    = errors.New("balance amount is empty")
+	ErrBalanceInvalidVesting = errors.New("balance vesting is invalid")
 )
 
 const (
@@ -52,24 +53,36 @@ type GnoAccount struct {
 var validFlags = flagUnrestricted | flagValidatorAccount | flagRealmAccount
 
 func (ga *GnoAccount) setFlag(flag BitSet) {
+	ga.Attributes.set(flag)
+}
+
+func (ga *GnoAccount) clearFlag(flag BitSet) {
+	ga.Attributes.clear(flag)
+}
+
+func (ga *GnoAccount) hasFlag(flag BitSet) bool {
+	return ga.Attributes.has(flag)
+}
+
+func (bs *BitSet) set(flag BitSet) {
 	if !isValidFlag(flag) {
 		panic(fmt.Sprintf("setFlag: invalid flag %d (binary: %b). Valid flags: %b", flag, flag, validFlags))
 	}
-	ga.Attributes |= flag
+	*bs |= flag
 }
 
-func (ga *GnoAccount) clearFlag(flag BitSet) {
+func (bs *BitSet) clear(flag BitSet) {
 	if !isValidFlag(flag) {
 		panic(fmt.Sprintf("clearFlag: invalid flag %d (binary: %b). Valid flags: %b", flag, flag, validFlags))
 	}
-	ga.Attributes &= ^flag
+	*bs &= ^flag
 }
 
-func (ga *GnoAccount) hasFlag(flag BitSet) bool {
+func (bs BitSet) has(flag BitSet) bool {
 	if !isValidFlag(flag) {
 		panic(fmt.Sprintf("hasFlag: invalid flag %d (binary: %b). Valid flags: %b", flag, flag, validFlags))
 	}
-	return ga.Attributes&flag != 0
+	return bs&flag != 0
 }
 
 // isValidFlag ensures that a given BitSet uses only the allowed subset of bits
@@ -102,6 +115,72 @@ func ProtoGnoAccount() std.Account {
 	return &GnoAccount{}
 }
 
+// gnoAccountUnrestricter is implemented by all the accounts of gno.land,
+// which can be unrestricted in genesis.
+type gnoAccountUnrestricter interface {
+	std.Account
+	std.AccountUnrestricter
+	SetUnrestricted()
+}
+
+var (
+	_ gnoAccountUnrestricter = &GnoAccount{}
+	_ gnoAccountUnrestricter = &GnoContinuousVestingAccount{}
+	_ gnoAccountUnrestricter = &GnoPeriodicVestingAccount{}
+	_ std.VestingAccount     = &GnoContinuousVestingAccount{}
+	_ std.VestingAccount     = &GnoPeriodicVestingAccount{}
+)
+
+// GnoContinuousVestingAccount is a GnoAccount whose genesis balance
+// vests linearly, see std.ContinuousVestingAccount.
+type GnoContinuousVestingAccount struct {
+	std.ContinuousVestingAccount
+	Attributes BitSet `json:"attributes" yaml:"attributes"`
+}
+
+// SetUnrestricted allows the account to bypass global transfer locking restrictions.
+func (ga *GnoContinuousVestingAccount) SetUnrestricted() {
+	ga.Attributes.set(flagUnrestricted)
+}
+
+// IsUnrestricted checks whether the account is flagUnrestricted.
+func (ga *GnoContinuousVestingAccount) IsUnrestricted() bool {
+	return ga.Attributes.has(flagUnrestricted)
+}
+
+// String implements fmt.Stringer
+func (ga *GnoContinuousVestingAccount) String() string {
+	return fmt.Sprintf("%s\n  Attributes:	 %s",
+		ga.BaseVestingAccount.String(),
+		ga.Attributes.String(),
+	)
+}
+
+// GnoPeriodicVestingAccount is a GnoAccount whose genesis balance
+// vests by periods, see std.PeriodicVestingAccount.
+type GnoPeriodicVestingAccount struct {
+	std.PeriodicVestingAccount
+	Attributes BitSet `json:"attributes" yaml:"attributes"`
+}
+
+// SetUnrestricted allows the account to bypass global transfer locking restrictions.
+func (ga *GnoPeriodicVestingAccount) SetUnrestricted() {
+	ga.Attributes.set(flagUnrestricted)
+}
+
+// IsUnrestricted checks whether the account is flagUnrestricted.
+func (ga *GnoPeriodicVestingAccount) IsUnrestricted() bool {
+	return ga.Attributes.has(flagUnrestricted)
+}
+
+// String implements fmt.Stringer
+func (ga *GnoPeriodicVestingAccount) String() string {
+	return fmt.Sprintf("%s\n  Attributes:	 %s",
+		ga.PeriodicVestingAccount.String(),
+		ga.Attributes.String(),
+	)
+}
+
 type GnoGenesisState struct {
 	Balances []Balance         `json:"balances"`
 	Txs      []TxWithMetadata  `json:"txs"`
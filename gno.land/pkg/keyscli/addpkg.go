@@ -125,6 +125,11 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 	if err != nil {
 		panic(err)
 	}
+	fee := std.NewFee(gaswanted, gasfee)
+	fee.Granter, err = cfg.RootCfg.ParseFeeGranter()
+	if err != nil {
+		return errors.Wrap(err, "parsing fee granter address")
+	}
 	// construct msg & tx and marshal.
 	msg := vm.MsgAddPackage{
 		Creator:    creator,
@@ -133,10 +138,11 @@ func execMakeAddPkg(cfg *MakeAddPkgCfg, args []string, io commands.IO) error {
 		MaxDeposit: deposit,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
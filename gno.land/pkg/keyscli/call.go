@@ -129,6 +129,11 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return errors.Wrap(err, "parsing gas fee coin")
 	}
+	fee := std.NewFee(gaswanted, gasfee)
+	fee.Granter, err = cfg.RootCfg.ParseFeeGranter()
+	if err != nil {
+		return errors.Wrap(err, "parsing fee granter address")
+	}
 
 	// construct msg & tx and marshal.
 	msg := vm.MsgCall{
@@ -140,10 +145,11 @@ func execMakeCall(cfg *MakeCallCfg, args []string, io commands.IO) error {
 		Args:       cfg.Args,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
@@ -10,9 +10,11 @@ import (
 type MakeTxCfg struct {
 	RootCfg *client.BaseCfg
 
-	GasWanted int64
-	GasFee    string
-	Memo      string
+	GasWanted     int64
+	GasFee        string
+	Memo          string
+	TimeoutHeight uint64
+	FeeGranter    string
 
 	Broadcast bool
 	ChainID   string
@@ -35,6 +37,10 @@ func NewMakeTxCmd(rootCfg *client.BaseCfg, io commands.IO) *commands.Command {
 
 	cmd.AddSubCommands(
 		client.NewMakeSendCmd(cfg, io),
+		client.NewMakeGrantCmd(cfg, io),
+		client.NewMakeRevokeCmd(cfg, io),
+		client.NewMakeCreateSessionCmd(cfg, io),
+		client.NewMakeRevokeSessionCmd(cfg, io),
 
 		// custom commands
 		NewMakeAddPkgCmd(cfg, io),
@@ -67,6 +73,20 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"any descriptive text",
 	)
 
+	fs.Uint64Var(
+		&c.TimeoutHeight,
+		"timeout-height",
+		0,
+		"last block height the tx can be included in (0 means no timeout)",
+	)
+
+	fs.StringVar(
+		&c.FeeGranter,
+		"fee-granter",
+		"",
+		"address of the account paying the gas fee, from the fee allowance it granted",
+	)
+
 	fs.BoolVar(
 		&c.Broadcast,
 		"broadcast",
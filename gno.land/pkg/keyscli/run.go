@@ -87,6 +87,11 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 	if err != nil {
 		return errors.Wrap(err, "parsing gas fee coin")
 	}
+	fee := std.NewFee(gaswanted, gasfee)
+	fee.Granter, err = cfg.RootCfg.ParseFeeGranter()
+	if err != nil {
+		return errors.Wrap(err, "parsing fee granter address")
+	}
 
 	memPkg := &std.MemPackage{}
 	if sourcePath == "-" { // stdin
@@ -136,10 +141,11 @@ func execMakeRun(cfg *MakeRunCfg, args []string, cmdio commands.IO) error {
 		MaxDeposit: deposit,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
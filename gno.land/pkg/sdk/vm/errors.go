@@ -23,6 +23,7 @@ type (
 	UnauthorizedUserError struct{ abciError }
 	InvalidPackageError   struct{ abciError }
 	InvalidFileError      struct{ abciError }
+	ChanPersistError      struct{ abciError }
 	TypeCheckError        struct {
 		abciError
 		Errors []string `json:"errors"`
@@ -37,6 +38,7 @@ func (e InvalidFileError) Error() string      { return "file is not available" }
 func (e InvalidExprError) Error() string      { return "invalid expression" }
 func (e UnauthorizedUserError) Error() string { return "unauthorized user" }
 func (e InvalidPackageError) Error() string   { return "invalid package" }
+func (e ChanPersistError) Error() string      { return "cannot persist channel values" }
 func (e TypeCheckError) Error() string {
 	var bld strings.Builder
 	bld.WriteString("invalid gno package; type check errors:\n")
@@ -72,6 +74,10 @@ func ErrInvalidPackage(msg string) error {
 	return errors.Wrap(InvalidPackageError{}, msg)
 }
 
+func ErrChanPersist(msg string) error {
+	return errors.Wrap(ChanPersistError{}, msg)
+}
+
 func ErrTypeCheck(err error) error {
 	var tce TypeCheckError
 	errs := multierr.Errors(err)
@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	"github.com/gnolang/gno/gnovm/pkg/version"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -78,6 +79,7 @@ const (
 	QueryDoc     = "qdoc"
 	QueryPaths   = "qpaths"
 	QueryStorage = "qstorage"
+	QueryProfile = "qprofile"
 )
 
 func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -101,6 +103,8 @@ func (vh vmHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Resp
 		res = vh.queryPaths(ctx, req)
 	case QueryStorage:
 		res = vh.queryStorage(ctx, req)
+	case QueryProfile:
+		res = vh.queryProfile(ctx, req)
 	default:
 		return sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest(fmt.Sprintf(
@@ -255,6 +259,21 @@ func (vh vmHandler) queryStorage(ctx sdk.Context, req abci.RequestQuery) (res ab
 	return
 }
 
+// queryProfile simulates the MsgCall or MsgRun given as amino JSON, and
+// returns the JSON of its gas usage.
+func (vh vmHandler) queryProfile(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	var msg std.Msg
+	if err := amino.UnmarshalJSON(req.Data, &msg); err != nil {
+		return sdk.ABCIResponseQueryFromError(std.ErrTxDecode(err.Error()))
+	}
+	prof, err := vh.vm.QueryProfile(ctx, msg)
+	if err != nil {
+		return sdk.ABCIResponseQueryFromError(err)
+	}
+	res.Data = []byte(prof.JSON())
+	return
+}
+
 // ----------------------------------------
 // misc
 
@@ -6,10 +6,12 @@ import (
 
 	"github.com/gnolang/gno/gnovm/pkg/doc"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func Test_parseQueryEvalData(t *testing.T) {
@@ -466,3 +468,104 @@ func Hello(msg string) (res string) { res = prefix+" "+msg; return }
 		})
 	}
 }
+
+func TestVmHandlerQuery_Profile(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, std.MustParseCoins("10000000ugnot"))
+
+	// Create test package.
+	const pkgPath = "gno.land/r/counter"
+	files := []*std.MemFile{
+		{Name: "counter.gno", Body: `package counter
+
+var values []int
+
+func Push(cur realm, n int) int {
+	for i := 0; i < n; i++ {
+		values = append(values, square(i))
+	}
+	return len(values)
+}
+
+func square(x int) int {
+	return x * x
+}
+`},
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+	}
+	err := env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files))
+	require.NoError(t, err)
+	env.vmk.CommitGnoTransactionStore(ctx)
+
+	query := func(msg std.Msg) abci.ResponseQuery {
+		return env.vmh.Query(env.ctx, abci.RequestQuery{
+			Path: "vm/qprofile",
+			Data: amino.MustMarshalJSONAny(msg),
+		})
+	}
+
+	res := query(NewMsgCall(addr, nil, pkgPath, "Push", []string{"10"}))
+	require.True(t, res.IsOK(), "should not have error: %v", res.Error)
+	var prof TxProfile
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &prof))
+	assert.Empty(t, prof.Error)
+	assert.Greater(t, prof.GasUsed, int64(0))
+
+	funcs := map[string]FuncProfile{}
+	for _, f := range prof.Funcs {
+		funcs[f.Func] = f
+	}
+	push, square := funcs[pkgPath+".Push"], funcs[pkgPath+".square"]
+	assert.Equal(t, "counter.gno", push.File)
+	assert.Equal(t, 5, push.Line)
+	assert.Greater(t, square.FlatGas, int64(0))
+	assert.Equal(t, square.FlatGas, square.CumGas)
+	assert.GreaterOrEqual(t, push.CumGas, push.FlatGas+square.CumGas)
+
+	var kinds []string
+	var total int64
+	for _, k := range prof.GasKinds {
+		kinds = append(kinds, k.Kind)
+		total += k.Gas
+	}
+	assert.Contains(t, kinds, "CPUCycles")
+	assert.Contains(t, kinds, gnolang.GasSetObjectDesc)
+	assert.Equal(t, prof.GasUsed, total)
+	require.NotEmpty(t, prof.Ops)
+	assert.Greater(t, prof.Ops[0].Count, int64(0))
+	require.Len(t, prof.StorageDiffs, 1)
+	assert.Equal(t, pkgPath, prof.StorageDiffs[0].Realm)
+	assert.Greater(t, prof.StorageDiffs[0].Diff, int64(0))
+
+	// The simulation is not committed.
+	res = query(NewMsgCall(addr, nil, pkgPath, "Push", []string{"1"}))
+	require.True(t, res.IsOK())
+	var prof2 TxProfile
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &prof2))
+	assert.Less(t, prof2.GasUsed, prof.GasUsed)
+	res = env.vmh.Query(env.ctx, abci.RequestQuery{
+		Path: "vm/qeval",
+		Data: []byte(pkgPath + ".len(values)"),
+	})
+	require.True(t, res.IsOK())
+	assert.Equal(t, "(0 int)", string(res.Data))
+
+	// Errors of the simulated message are part of the profile.
+	res = query(NewMsgCall(addr, nil, pkgPath, "Nope", nil))
+	require.True(t, res.IsOK())
+	var prof3 TxProfile
+	require.NoError(t, amino.UnmarshalJSON(res.Data, &prof3))
+	assert.NotEmpty(t, prof3.Error)
+
+	// Only MsgCall and MsgRun can be profiled.
+	res = query(NewMsgAddPackage(addr, "gno.land/r/other", files))
+	assert.False(t, res.IsOK())
+	res = env.vmh.Query(env.ctx, abci.RequestQuery{Path: "vm/qprofile", Data: []byte("{")})
+	assert.False(t, res.IsOK())
+}
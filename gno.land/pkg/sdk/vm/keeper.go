@@ -4,6 +4,7 @@ package vm
 
 import (
 	"bytes"
+	"cmp"
 	"context"
 	goerrors "errors"
 	"fmt"
@@ -38,8 +39,11 @@ import (
 	stypes "github.com/gnolang/gno/tm2/pkg/store/types"
 	"github.com/gnolang/gno/tm2/pkg/telemetry"
 	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
 	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -144,6 +148,18 @@ func (vm *VMKeeper) Initialize(
 	}
 }
 
+// Reinitialize initializes the VMKeeper again from the given multistore,
+// whose content replaced the one the VMKeeper was initialized with, as when
+// the state is restored from a snapshot.
+func (vm *VMKeeper) Reinitialize(
+	logger *slog.Logger,
+	ms store.MultiStore,
+) {
+	vm.gnoStore = nil
+	vm.typeCheckCache = gno.TypeCheckCache{}
+	vm.Initialize(logger, ms)
+}
+
 type stdlibCache struct {
 	dir  string
 	base store.Store
@@ -282,6 +298,7 @@ type vmkContextKey int
 const (
 	vmkContextKeyStore vmkContextKey = iota
 	vmkContextKeyTypeCheckCache
+	vmkContextKeyProfiler
 )
 
 func (vm *VMKeeper) newGnoTransactionStore(ctx sdk.Context) gno.TransactionStore {
@@ -317,9 +334,16 @@ func (vm *VMKeeper) getTypeCheckCache(ctx sdk.Context) gno.TypeCheckCache {
 	return ctx.Value(vmkContextKeyTypeCheckCache).(gno.TypeCheckCache)
 }
 
+// getProfiler returns the profiler of a vm/qprofile query, or nil.
+func (vm *VMKeeper) getProfiler(ctx sdk.Context) *gno.Profiler {
+	p, _ := ctx.Value(vmkContextKeyProfiler).(*gno.Profiler)
+	return p
+}
+
 func (vm *VMKeeper) getGnoTransactionStore(ctx sdk.Context) gno.TransactionStore {
 	txStore := ctx.Value(vmkContextKeyStore).(gno.TransactionStore)
 	txStore.ClearObjectCache()
+	txStore.SetTraceContext(ctx.Context())
 	return txStore
 }
 
@@ -415,6 +439,9 @@ func (vm *VMKeeper) checkNamespacePermission(ctx sdk.Context, creator crypto.Add
 
 // AddPackage adds a package with given fileset.
 func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
+	ctx, span := startSpan(ctx, "vm/AddPackage", traces.PkgPathKey.String(msg.Package.Path))
+	defer func() { endSpan(span, err) }()
+
 	creator := msg.Creator
 	pkgPath := msg.Package.Path
 	memPkg := msg.Package
@@ -555,6 +582,12 @@ func (vm *VMKeeper) AddPackage(ctx sdk.Context, msg MsgAddPackage) (err error) {
 
 // Call calls a public Gno function (for delivertx).
 func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
+	ctx, span := startSpan(ctx, "vm/Call",
+		traces.PkgPathKey.String(msg.PkgPath),
+		attribute.String("vm.func", msg.Func),
+	)
+	defer func() { endSpan(span, err) }()
+
 	params := vm.GetParams(ctx)
 	pkgPath := msg.PkgPath // to import
 	fnc := msg.Func
@@ -631,6 +664,7 @@ func (vm *VMKeeper) Call(ctx sdk.Context, msg MsgCall) (res string, err error) {
 			Context:  msgCtx,
 			Alloc:    gnostore.GetAllocator(),
 			GasMeter: ctx.GasMeter(),
+			Profiler: vm.getProfiler(ctx),
 		})
 	defer m.Release()
 	m.SetActivePackage(mpv)
@@ -693,6 +727,13 @@ func doRecoverInternal(m *gno.Machine, e *error, r any, repanicOutOfGas bool) {
 			*e = oog
 			return
 		}
+		var cpe gno.ChanPersistError
+		if goerrors.As(err, &cpe) {
+			*e = ErrChanPersist(fmt.Sprintf(
+				"VM panic: %s\nStacktrace:\n%s\n",
+				cpe.Error(), m.Stacktrace().String()))
+			return
+		}
 		var up gno.UnhandledPanicError
 		if goerrors.As(err, &up) {
 			// Common unhandled panic error, skip machine state.
@@ -713,6 +754,9 @@ func doRecoverInternal(m *gno.Machine, e *error, r any, repanicOutOfGas bool) {
 
 // Run executes arbitrary Gno code in the context of the caller's realm.
 func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
+	ctx, span := startSpan(ctx, "vm/Run")
+	defer func() { endSpan(span, err) }()
+
 	caller := msg.Caller
 	pkgAddr := caller
 	gnostore := vm.getGnoTransactionStore(ctx)
@@ -727,6 +771,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 	// the path in the message must be "" or the following path.
 	// this is already checked in MsgRun.ValidateBasic
 	memPkg.Path = chainDomain + "/e/" + msg.Caller.String() + "/run"
+	span.SetAttributes(traces.PkgPathKey.String(memPkg.Path))
 
 	// Validate arguments.
 	callerAcc := vm.acck.GetAccount(ctx, caller)
@@ -786,6 +831,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 				Alloc:    alloc,
 				Context:  msgCtx,
 				GasMeter: ctx.GasMeter(),
+				Profiler: vm.getProfiler(ctx),
 			})
 		defer m.Release()
 		defer doRecover(m, &err)
@@ -806,6 +852,7 @@ func (vm *VMKeeper) Run(ctx sdk.Context, msg MsgRun) (res string, err error) {
 			Alloc:    alloc,
 			Context:  msgCtx,
 			GasMeter: ctx.GasMeter(),
+			Profiler: vm.getProfiler(ctx),
 		})
 	defer m2.Release()
 	m2.SetActivePackage(pv)
@@ -1096,6 +1143,120 @@ func (vm *VMKeeper) QueryStorage(ctx sdk.Context, pkgPath string) (string, error
 	return res, nil
 }
 
+// QueryProfile simulates msg, a MsgCall or a MsgRun, on top of the state of
+// ctx without committing it, and returns how its gas is spent. Only the gas
+// consumed by the VM is measured; the gas of the ante handler, such as for
+// signature verification, is not included.
+func (vm *VMKeeper) QueryProfile(ctx sdk.Context, msg std.Msg) (*TxProfile, error) {
+	if err := msg.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	switch msg.(type) {
+	case MsgCall, MsgRun:
+	default:
+		return nil, std.ErrUnknownRequest(fmt.Sprintf("cannot profile message type: %T", msg))
+	}
+
+	// The simulation runs on a cache of the stores, which is never written.
+	profiler := gno.NewProfiler()
+	ctx = ctx.WithMultiStore(ctx.MultiStore().MultiCacheWrap())
+	ctx = ctx.WithGasMeter(profiler.GasMeter(store.NewGasMeter(maxGasQuery)))
+	ctx = vm.MakeGnoTransactionStore(ctx).WithValue(vmkContextKeyProfiler, profiler)
+
+	prof := &TxProfile{}
+	func() {
+		defer func() {
+			// Out of gas, and other panics recovered by the BaseApp
+			// when running transactions.
+			if r := recover(); r != nil {
+				prof.Error = fmt.Sprint(r)
+			}
+		}()
+		var err error
+		switch msg := msg.(type) {
+		case MsgCall:
+			_, err = vm.Call(ctx, msg)
+		case MsgRun:
+			_, err = vm.Run(ctx, msg)
+		}
+		if err != nil {
+			prof.Error = err.Error()
+		}
+	}()
+
+	samples := profiler.GasSamples()
+	prof.GasUsed = ctx.GasMeter().GasConsumed()
+	prof.Funcs = profileFuncs(samples)
+	for _, op := range profiler.Ops() {
+		prof.Ops = append(prof.Ops, OpProfile{
+			Op:    op.Op.String(),
+			Count: op.Count,
+			Gas:   overflow.Mulp(op.Cycles, gno.GasFactorCPU),
+		})
+	}
+	kinds := map[string]int64{}
+	for _, s := range samples {
+		kinds[s.Descriptor] += s.Value
+	}
+	for kind, gas := range kinds {
+		prof.GasKinds = append(prof.GasKinds, GasKindProfile{Kind: kind, Gas: gas})
+	}
+	slices.SortFunc(prof.GasKinds, func(a, b GasKindProfile) int {
+		if a.Gas != b.Gas {
+			return cmp.Compare(b.Gas, a.Gas)
+		}
+		return strings.Compare(a.Kind, b.Kind)
+	})
+	// Not using getGnoTransactionStore, which clears the storage diffs.
+	gnostore := ctx.Value(vmkContextKeyStore).(gno.TransactionStore)
+	for realm, diff := range gnostore.RealmStorageDiffs() {
+		prof.StorageDiffs = append(prof.StorageDiffs, RealmStorageDiff{Realm: realm, Diff: diff})
+	}
+	slices.SortFunc(prof.StorageDiffs, func(a, b RealmStorageDiff) int {
+		return strings.Compare(a.Realm, b.Realm)
+	})
+	return prof, nil
+}
+
+// profileFuncs returns the gas consumed by each function of the samples.
+func profileFuncs(samples []gno.ProfileSample) []FuncProfile {
+	type funcKey struct{ pkgPath, file, name string }
+	funcs := map[funcKey]*FuncProfile{}
+	for _, s := range samples {
+		seen := map[funcKey]bool{} // count recursive calls once
+		for i, f := range s.Stack {
+			k := funcKey{f.PkgPath, f.File, f.Func}
+			fp := funcs[k]
+			if fp == nil {
+				name := f.Func
+				if f.PkgPath != "" {
+					name = f.PkgPath + "." + name
+				}
+				fp = &FuncProfile{Func: name, File: f.File, Line: f.StartLine}
+				funcs[k] = fp
+			}
+			if i == 0 {
+				fp.FlatGas += s.Value
+			}
+			if !seen[k] {
+				seen[k] = true
+				fp.CumGas += s.Value
+			}
+		}
+	}
+	res := make([]FuncProfile, 0, len(funcs))
+	for _, fp := range funcs {
+		res = append(res, *fp)
+	}
+	slices.SortFunc(res, func(a, b FuncProfile) int {
+		if a.CumGas != b.CumGas {
+			return cmp.Compare(b.CumGas, a.CumGas)
+		}
+		return strings.Compare(a.Func, b.Func)
+	})
+	return res
+}
+
 // processStorageDeposit processes storage deposit adjustments for package realms based on
 // storage size changes tracked within the gnoStore.
 //
@@ -1227,6 +1388,23 @@ func (vm *VMKeeper) refundStorageDeposit(ctx sdk.Context, refundReceiver crypto.
 	return nil
 }
 
+// startSpan starts the trace span of a VM message, returning the context
+// with the span for the GnoVM store to trace its object loads
+func startSpan(ctx sdk.Context, name string, attrs ...attribute.KeyValue) (sdk.Context, trace.Span) {
+	spanCtx, span := traces.Tracer().Start(ctx.Context(), name, trace.WithAttributes(attrs...))
+	return ctx.WithContext(spanCtx), span
+}
+
+// endSpan ends the trace span of a VM message with its error
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
+
 // logTelemetry logs the VM processing telemetry
 func logTelemetry(
 	gasUsed int64,
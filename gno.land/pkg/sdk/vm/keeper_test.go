@@ -9,9 +9,14 @@ import (
 	"runtime"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/gnolang/gno/gno.land/pkg/gnoland/ugnot"
 	"github.com/gnolang/gno/gnovm/pkg/gnolang"
@@ -23,6 +28,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
 )
 
 var (
@@ -795,9 +801,8 @@ func Echo(cur realm, msg string) string {
 	assert.Equal(t, `("echo:hello world" string)`+"\n\n", res)
 
 	// Clear out gnovm and reinitialize.
-	env.vmk.gnoStore = nil
 	mcw := env.ctx.MultiStore().MultiCacheWrap()
-	env.vmk.Initialize(log.NewNoopLogger(), mcw)
+	env.vmk.Reinitialize(log.NewNoopLogger(), mcw)
 	mcw.MultiWrite()
 
 	// Run echo again, and it should still work.
@@ -958,6 +963,69 @@ func Echo(cur realm, msg string){
 	assert.True(t, depDeltaTest.Add(depDeltaFoo).IsEqual(msg2.MaxDeposit))
 }
 
+// The storage deposits cannot be paid with the locked coins of a vesting
+// account, which would then be refunded to the caller releasing the storage.
+func TestProcessStorageDeposit_Vesting(t *testing.T) {
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+	header := ctx.BlockHeader().(*bft.Header).Copy()
+	header.Time = time.Unix(1000, 0)
+	ctx = ctx.WithBlockHeader(header)
+
+	// Give "addr1" some gnots, and lock the ones of "addr2" until 2000.
+	addr1 := crypto.AddressFromPreimage([]byte("addr1"))
+	env.acck.SetAccount(ctx, env.acck.NewAccountWithAddress(ctx, addr1))
+	env.bankk.SetCoins(ctx, addr1, initialBalance)
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	base := std.NewBaseAccountWithAddress(addr2)
+	base.Coins = initialBalance
+	env.acck.SetAccount(ctx, std.NewContinuousVestingAccount(base, initialBalance, 1000, 2000))
+
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "test.gno", Body: `
+package test
+
+var data string
+
+func Store(cur realm, s string) {
+	data = s
+}
+
+func Clear(cur realm) {
+	data = ""
+}`},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr1, pkgPath, files)))
+	depAddr := gnolang.DeriveStorageDepositCryptoAddr(pkgPath)
+	deposit := env.bankk.GetCoins(ctx, depAddr)
+
+	// The deposit of "addr2" is refused while all its coins are locked.
+	msg := NewMsgCall(addr2, nil, pkgPath, "Store", []string{strings.Repeat("a", 1000)})
+	_, err := env.vmk.Call(ctx, msg)
+	require.ErrorIs(t, err, std.InsufficientCoinsError{})
+	assert.True(t, env.bankk.GetCoins(ctx, addr2).IsEqual(initialBalance))
+	assert.True(t, env.bankk.GetCoins(ctx, depAddr).IsEqual(deposit))
+
+	// Once half of its coins vested, the deposit is paid with them.
+	header.Time = time.Unix(1500, 0)
+	ctx = ctx.WithBlockHeader(header)
+	msg = NewMsgCall(addr2, nil, pkgPath, "Store", []string{strings.Repeat("b", 2000)})
+	_, err = env.vmk.Call(ctx, msg)
+	require.NoError(t, err)
+	paid := env.bankk.GetCoins(ctx, depAddr).Sub(deposit)
+	assert.True(t, paid.IsAllPositive())
+	assert.True(t, env.bankk.GetCoins(ctx, addr2).IsEqual(initialBalance.Sub(paid)))
+
+	// The deposit is refunded to the caller releasing the storage.
+	_, err = env.vmk.Call(ctx, NewMsgCall(addr1, nil, pkgPath, "Clear", nil))
+	require.NoError(t, err)
+	refund := env.bankk.GetCoins(ctx, addr1).Sub(initialBalance.Sub(deposit))
+	assert.True(t, refund.IsAllPositive())
+	assert.True(t, env.bankk.GetCoins(ctx, depAddr).IsEqual(deposit.Add(paid).Sub(refund)))
+}
+
 // TestVMKeeper_RealmDiffIterationDeterminism is a regression test for issue #4580.
 // It verifies that the processStorageDeposit function iterates over realms
 // in a deterministic order by sorting the realm paths before iteration.
@@ -1092,3 +1160,58 @@ func UpdateAll(cur realm) {
 	// All runs produced identical results - this is expected with the fix applied
 	t.Logf("SUCCESS: All %d runs produced identical results, confirming deterministic behavior", numRuns)
 }
+
+func TestVMKeeperTraces(t *testing.T) {
+	// Record the spans in memory
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdkTrace.NewTracerProvider(sdkTrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+
+	env := setupTestEnv()
+	ctx := env.vmk.MakeGnoTransactionStore(env.ctx)
+
+	// Give "addr1" some gnots.
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	acc := env.acck.NewAccountWithAddress(ctx, addr)
+	env.acck.SetAccount(ctx, acc)
+	env.bankk.SetCoins(ctx, addr, initialBalance)
+
+	// Create test package.
+	const pkgPath = "gno.land/r/test"
+	files := []*std.MemFile{
+		{Name: "gnomod.toml", Body: gnolang.GenGnoModLatest(pkgPath)},
+		{Name: "init.gno", Body: `
+package test
+
+var counter int
+
+func Inc(cur realm) int {
+	counter++
+	return counter
+}`},
+	}
+	require.NoError(t, env.vmk.AddPackage(ctx, NewMsgAddPackage(addr, pkgPath, files)))
+
+	_, err := env.vmk.Call(ctx, NewMsgCall(addr, nil, pkgPath, "Inc", nil))
+	require.NoError(t, err)
+
+	spans := make(map[string][]sdkTrace.ReadOnlySpan)
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = append(spans[span.Name()], span)
+	}
+
+	// The VM spans carry the realm path
+	require.Len(t, spans["vm/AddPackage"], 1)
+	require.Len(t, spans["vm/Call"], 1)
+	call := spans["vm/Call"][0]
+	assert.Contains(t, call.Attributes(), traces.PkgPathKey.String(pkgPath))
+
+	// The realm objects loaded by the call are traced within its span
+	var loads int
+	for _, span := range spans["gno/LoadObject"] {
+		if span.Parent().SpanID() == call.SpanContext().SpanID() {
+			loads++
+		}
+	}
+	assert.Positive(t, loads)
+}
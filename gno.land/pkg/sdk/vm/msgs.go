@@ -85,6 +85,16 @@ func (msg MsgAddPackage) GetReceived() std.Coins {
 	return msg.Send
 }
 
+// Implements auth.SendMsg.
+func (msg MsgAddPackage) GetSend() std.Coins {
+	return msg.Send
+}
+
+// Implements auth.DepositMsg.
+func (msg MsgAddPackage) GetMaxDeposit() std.Coins {
+	return msg.MaxDeposit
+}
+
 //----------------------------------------
 // MsgCall
 
@@ -157,6 +167,26 @@ func (msg MsgCall) GetReceived() std.Coins {
 	return msg.Send
 }
 
+// Implements auth.PkgPathMsg.
+func (msg MsgCall) GetPkgPath() string {
+	return msg.PkgPath
+}
+
+// Implements auth.FuncMsg.
+func (msg MsgCall) GetFunc() string {
+	return msg.Func
+}
+
+// Implements auth.SendMsg.
+func (msg MsgCall) GetSend() std.Coins {
+	return msg.Send
+}
+
+// Implements auth.DepositMsg.
+func (msg MsgCall) GetMaxDeposit() std.Coins {
+	return msg.MaxDeposit
+}
+
 //----------------------------------------
 // MsgRun
 
@@ -237,3 +267,13 @@ func (msg MsgRun) GetSigners() []crypto.Address {
 func (msg MsgRun) GetReceived() std.Coins {
 	return msg.Send
 }
+
+// Implements auth.SendMsg.
+func (msg MsgRun) GetSend() std.Coins {
+	return msg.Send
+}
+
+// Implements auth.DepositMsg.
+func (msg MsgRun) GetMaxDeposit() std.Coins {
+	return msg.MaxDeposit
+}
@@ -25,4 +25,5 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	TypeCheckError{}, "TypeCheckError",
 	UnauthorizedUserError{}, "UnauthorizedUserError",
 	InvalidPackageError{}, "InvalidPackageError",
+	ChanPersistError{}, "ChanPersistError",
 ))
@@ -51,3 +51,49 @@ func (fsigs FunctionSignatures) JSON() string {
 	bz := amino.MustMarshalJSON(fsigs)
 	return string(bz)
 }
+
+// TxProfile is the result of the vm/qprofile query: how the gas of a
+// simulated MsgCall or MsgRun is spent.
+type TxProfile struct {
+	GasUsed      int64
+	Error        string             // error of the simulated message, if any
+	Funcs        []FuncProfile      // by decreasing cumulative gas
+	Ops          []OpProfile        // by decreasing gas
+	GasKinds     []GasKindProfile   // by decreasing gas
+	StorageDiffs []RealmStorageDiff // by realm path
+}
+
+// FuncProfile is the gas consumed by a function, including the gas of the
+// storage accesses and of the natives it made.
+type FuncProfile struct {
+	Func    string // package path and name of the function
+	File    string // file of the function, in its package
+	Line    int    // line of the function declaration
+	FlatGas int64  // gas consumed by the function itself
+	CumGas  int64  // gas consumed by the function and its callees
+}
+
+// OpProfile is the gas consumed by the CPU cycles of an op.
+type OpProfile struct {
+	Op    string
+	Count int64
+	Gas   int64
+}
+
+// GasKindProfile is the gas consumed for a kind of operation, as named by
+// the gas meter descriptors (e.g. "CPUCycles", "GetObjectPerByte").
+type GasKindProfile struct {
+	Kind string
+	Gas  int64
+}
+
+// RealmStorageDiff is the change of the storage used by a realm, in bytes.
+type RealmStorageDiff struct {
+	Realm string
+	Diff  int64
+}
+
+func (prof *TxProfile) JSON() string {
+	bz := amino.MustMarshalJSON(prof)
+	return string(bz)
+}
@@ -56,6 +56,7 @@ func TestLoadOpcodesPackage(t *testing.T) {
 		"OpForLoop",
 		"OpTypes",
 		"OpOpValues",
+		"OpChan",
 	}
 
 	for i := 3; i < 3+len(funcValues); i++ {
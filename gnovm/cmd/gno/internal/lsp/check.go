@@ -0,0 +1,197 @@
+package lsp
+
+import (
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/types"
+	"io"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/packages"
+	"github.com/gnolang/gno/gnovm/pkg/test"
+	"go.uber.org/multierr"
+)
+
+// pkgCheck is the result of checking a package.
+type pkgCheck struct {
+	dir     string
+	pkgPath string
+	info    *gno.TypeCheckInfo      // nil if the package could not be parsed
+	dirs    map[string]string       // directories of loaded packages, by import path
+	diags   map[string][]Diagnostic // by file path
+}
+
+// check loads and type checks the package in dir, then preprocesses it if
+// type checking succeeds, in the same way as `gno lint`.
+func (s *Server) check(dir string) (c *pkgCheck) {
+	c = &pkgCheck{dir: dir, dirs: map[string]string{}, diags: map[string][]Diagnostic{}}
+	defer func() {
+		if r := recover(); r != nil {
+			err, ok := r.(error)
+			if !ok {
+				err = fmt.Errorf("%v", r)
+			}
+			c.addError(err)
+		}
+	}()
+
+	conf := packages.LoadConfig{
+		Fetcher:    s.cfg.Fetcher,
+		Deps:       true,
+		Test:       true,
+		AllowEmpty: true,
+		GnoRoot:    s.cfg.RootDir,
+		Out:        io.Discard,
+		Dir:        dir,
+	}
+	pkgs, err := packages.Load(conf, dir)
+	if err != nil {
+		c.addError(err)
+		return c
+	}
+	var pkg *packages.Package
+	for _, p := range pkgs {
+		if p.ImportPath != "" {
+			c.dirs[p.ImportPath] = p.Dir
+		}
+		if p.Dir == dir {
+			pkg = p
+		}
+	}
+	if pkg == nil {
+		return c
+	}
+	for _, err := range pkg.Errors {
+		c.addError(err)
+	}
+	c.pkgPath = pkg.ImportPath
+	if c.pkgPath == "" {
+		c.pkgPath = "gno.land/r/test" // same default as gno test
+	}
+
+	mpkg, err := gno.ReadMemPackage(dir, c.pkgPath, gno.MPAnyAll)
+	if err != nil {
+		c.addError(err)
+		return c
+	}
+	for p, text := range s.docs {
+		if filepath.Dir(p) == dir && strings.HasSuffix(p, ".gno") {
+			mpkg.SetFile(filepath.Base(p), text)
+		}
+	}
+
+	_, prodgs := test.StoreWithOptions(
+		s.cfg.RootDir, io.Discard,
+		test.StoreOptions{PreprocessOnly: true, WithExamples: true, Packages: pkgs},
+	)
+	_, testgs := test.StoreWithOptions(
+		s.cfg.RootDir, io.Discard,
+		test.StoreOptions{PreprocessOnly: true, WithExamples: true, Testing: true, SourceStore: prodgs, Packages: pkgs},
+	)
+	info := gno.NewTypeCheckInfo()
+	_, errs := gno.TypeCheckMemPackage(mpkg, gno.TypeCheckOptions{
+		Getter:     prodgs,
+		TestGetter: testgs,
+		Mode:       gno.TCLatestRelaxed,
+		Info:       info,
+	})
+	if info.Files != nil {
+		c.info = info
+	}
+	if errs != nil {
+		c.addError(errs)
+		return c
+	}
+
+	// Preprocess production files, for errors not detected by Go type checking.
+	pmpkg := gno.MPFProd.FilterMemPackage(mpkg)
+	m := test.Machine(prodgs, io.Discard, c.pkgPath, false)
+	defer m.Release()
+	fset := gno.ParseMemPackageAsType(pmpkg, pmpkg.Type.(gno.MemPackageType))
+	m.PreprocessFiles(pmpkg.Name, pmpkg.Path, fset, false, false, "")
+	return c
+}
+
+var reLocation = regexp.MustCompile(`^(.*?\.gno):(\d+)(?::(\d+))?(?:-(\d+)(?::(\d+))?)?:\s*(.*)$`)
+
+// addError adds the diagnostics for err.
+func (c *pkgCheck) addError(err error) {
+	var (
+		perr *gno.PreprocessError
+		ierr gno.ImportError
+		terr types.Error
+		serr scanner.ErrorList
+	)
+	switch {
+	case errors.As(err, &perr):
+		c.addMessage(perr.Unwrap().Error(), "gnoPreprocessError")
+	case errors.As(err, &terr):
+		pos := terr.Fset.Position(terr.Pos)
+		code := "gnoTypeCheckError"
+		if strings.Contains(terr.Msg, "(unknown import path \"") {
+			code = "gnoImportError"
+		}
+		c.add(pos.Filename, pos.Line, pos.Column, terr.Msg, code)
+	case errors.As(err, &serr):
+		for _, e := range serr {
+			c.add(e.Pos.Filename, e.Pos.Line, e.Pos.Column, e.Msg, "gnoParserError")
+		}
+	case errors.As(err, &ierr):
+		c.addMessage(ierr.GetLocation()+": "+ierr.GetMsg(), "gnoImportError")
+	default:
+		if errs := multierr.Errors(err); len(errs) > 1 {
+			for _, err := range errs {
+				c.addError(err)
+			}
+			return
+		}
+		var lerr *packages.Error
+		if errors.As(err, &lerr) && lerr.Pos != "" {
+			c.addMessage(lerr.Pos+": "+lerr.Msg, "gnoLoadError")
+			return
+		}
+		c.addMessage(err.Error(), "gnoUnknownError")
+	}
+}
+
+// addMessage adds a diagnostic from an error message, which may start with
+// a "file:line:column" location.
+func (c *pkgCheck) addMessage(msg, code string) {
+	match := reLocation.FindStringSubmatch(msg)
+	if match == nil {
+		// Unknown location, report at the top of gnomod.toml.
+		c.add(filepath.Join(c.dir, "gnomod.toml"), 1, 1, msg, code)
+		return
+	}
+	line, _ := strconv.Atoi(match[2])
+	col, _ := strconv.Atoi(match[3])
+	c.add(match[1], line, col, match[6], code)
+}
+
+// add adds a diagnostic at the given 1-based line and column of file, which
+// is either a file path, or a file name prefixed by its package path.
+func (c *pkgCheck) add(file string, line, col int, msg, code string) {
+	if !filepath.IsAbs(file) {
+		file = filepath.Join(c.dir, path.Base(file))
+	}
+	pos := Position{Line: max(line-1, 0), Character: max(col-1, 0)}
+	c.diags[file] = append(c.diags[file], Diagnostic{
+		Range:    Range{Start: pos, End: pos},
+		Severity: severityError,
+		Code:     code,
+		Source:   "gno",
+		Message:  msg,
+	})
+}
+
+// filePath returns the local path of a file name of the type checked package
+// file set, which is prefixed by the package path.
+func (c *pkgCheck) filePath(name string) string {
+	return filepath.Join(c.dir, path.Base(name))
+}
@@ -0,0 +1,472 @@
+package lsp
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"golang.org/x/tools/go/ast/astutil"
+)
+
+// keywords are the completion candidates besides declared identifiers.
+var keywords = []string{
+	"break", "case", "chan", "const", "continue", "default", "defer", "else",
+	"fallthrough", "for", "func", "go", "goto", "if", "import", "interface",
+	"map", "package", "range", "return", "select", "struct", "switch", "type", "var",
+}
+
+// target is the identifier at a document position, with its type information.
+type target struct {
+	c     *pkgCheck
+	file  *ast.File
+	tfile *token.File
+	pos   token.Pos
+	ident *ast.Ident
+	obj   types.Object
+}
+
+// lookup returns the identifier at pos in the document at path, using
+// the last type information of its package. The identifier may be nil.
+func (s *Server) lookup(path string, pos Position) *target {
+	c := s.checks[filepath.Dir(path)]
+	if c == nil || c.info == nil {
+		return nil
+	}
+	text, ok := s.text(path)
+	if !ok {
+		return nil
+	}
+	t := &target{c: c}
+	for _, f := range c.info.Files {
+		tf := c.info.Fset.File(f.Pos())
+		if tf != nil && c.filePath(tf.Name()) == path {
+			t.file, t.tfile = f, tf
+			break
+		}
+	}
+	if t.file == nil {
+		return nil
+	}
+	t.pos = t.tfile.Pos(min(offsetOf(text, pos), t.tfile.Size()))
+	nodes, _ := astutil.PathEnclosingInterval(t.file, t.pos, t.pos)
+	if len(nodes) > 0 {
+		if id, ok := nodes[0].(*ast.Ident); ok {
+			t.ident = id
+			t.obj = c.info.Uses[id]
+			if t.obj == nil {
+				t.obj = c.info.Defs[id]
+			}
+		}
+	}
+	return t
+}
+
+func (s *Server) hover(path string, pos Position) *hover {
+	t := s.lookup(path, pos)
+	if t == nil || t.obj == nil {
+		return nil
+	}
+	qualifier := func(p *types.Package) string {
+		if p.Path() == t.c.pkgPath {
+			return ""
+		}
+		return p.Name()
+	}
+	var sb strings.Builder
+	sb.WriteString("```gno\n")
+	sb.WriteString(types.ObjectString(t.obj, qualifier))
+	sb.WriteString("\n```")
+	if _, _, doc := s.declaration(t.c, t.obj); doc != "" {
+		sb.WriteString("\n\n")
+		sb.WriteString(doc)
+	}
+	return &hover{Contents: markupContent{Kind: "markdown", Value: sb.String()}}
+}
+
+func (s *Server) definition(path string, pos Position) *Location {
+	t := s.lookup(path, pos)
+	if t == nil || t.obj == nil {
+		return nil
+	}
+	file, offset, _ := s.declaration(t.c, t.obj)
+	if file == "" {
+		return nil
+	}
+	text, ok := s.text(file)
+	if !ok {
+		return nil
+	}
+	start := positionOf(text, offset)
+	end := positionOf(text, offset+len(t.obj.Name()))
+	return &Location{URI: pathToURI(file), Range: Range{Start: start, End: end}}
+}
+
+// declaration returns the file path and byte offset of the declaration of
+// obj, and its documentation, or an empty path if not found.
+func (s *Server) declaration(c *pkgCheck, obj types.Object) (file string, offset int, doc string) {
+	if obj.Pkg() == nil {
+		return "", 0, "" // universe scope
+	}
+	// Object of the checked package.
+	if pkgPath := strings.TrimSuffix(obj.Pkg().Path(), "_test"); pkgPath == c.pkgPath {
+		tf := c.info.Fset.File(obj.Pos())
+		if tf == nil {
+			return "", 0, ""
+		}
+		for _, f := range c.info.Files {
+			if c.info.Fset.File(f.Pos()) == tf {
+				return c.filePath(tf.Name()), tf.Offset(obj.Pos()), declDoc(f, obj.Pos())
+			}
+		}
+		return "", 0, ""
+	}
+	// Object of an imported package: positions of imported packages are not
+	// recorded, so the declaration is found by parsing the package sources.
+	dir := c.dirs[obj.Pkg().Path()]
+	if dir == "" {
+		for _, d := range []string{
+			filepath.Join(s.cfg.RootDir, "gnovm", "stdlibs", filepath.FromSlash(obj.Pkg().Path())),
+			filepath.Join(s.cfg.RootDir, "examples", filepath.FromSlash(obj.Pkg().Path())),
+		} {
+			if fi, err := os.Stat(d); err == nil && fi.IsDir() {
+				dir = d
+				break
+			}
+		}
+	}
+	if dir == "" {
+		return "", 0, ""
+	}
+	decls := s.pkgDecls(dir)
+	id := decls.find(obj)
+	if id == nil {
+		return "", 0, ""
+	}
+	tf := decls.fset.File(id.Pos())
+	for _, f := range decls.files {
+		if decls.fset.File(f.Pos()) == tf {
+			return tf.Name(), tf.Offset(id.Pos()), declDoc(f, id.Pos())
+		}
+	}
+	return "", 0, ""
+}
+
+// declDoc returns the documentation of the declaration at pos in f.
+func declDoc(f *ast.File, pos token.Pos) string {
+	nodes, _ := astutil.PathEnclosingInterval(f, pos, pos)
+	for _, n := range nodes {
+		var doc *ast.CommentGroup
+		switch n := n.(type) {
+		case *ast.FuncDecl:
+			doc = n.Doc
+		case *ast.Field:
+			doc = n.Doc
+			if doc == nil {
+				doc = n.Comment
+			}
+		case *ast.ValueSpec:
+			doc = n.Doc
+			if doc == nil {
+				doc = n.Comment
+			}
+		case *ast.TypeSpec:
+			doc = n.Doc
+			if doc == nil {
+				doc = n.Comment
+			}
+		case *ast.GenDecl:
+			doc = n.Doc
+		case *ast.BlockStmt, *ast.File:
+			return ""
+		default:
+			continue
+		}
+		if doc != nil {
+			return strings.TrimSpace(doc.Text())
+		}
+	}
+	return ""
+}
+
+// pkgDecls holds the parsed production files of an imported package.
+type pkgDecls struct {
+	fset  *token.FileSet
+	files []*ast.File
+}
+
+func (s *Server) pkgDecls(dir string) *pkgDecls {
+	if d, ok := s.decls[dir]; ok {
+		return d
+	}
+	d := &pkgDecls{fset: token.NewFileSet()}
+	entries, _ := os.ReadDir(dir)
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() || !strings.HasSuffix(name, ".gno") ||
+			strings.HasSuffix(name, "_test.gno") || strings.HasSuffix(name, "_filetest.gno") {
+			continue
+		}
+		f, err := parser.ParseFile(d.fset, filepath.Join(dir, name), nil, parser.ParseComments|parser.SkipObjectResolution)
+		if err != nil {
+			continue
+		}
+		d.files = append(d.files, f)
+	}
+	s.decls[dir] = d
+	return d
+}
+
+// find returns the identifier declaring obj, or nil if not found.
+func (d *pkgDecls) find(obj types.Object) *ast.Ident {
+	recv := ""
+	if fn, ok := obj.(*types.Func); ok {
+		if sig, ok := fn.Type().(*types.Signature); ok && sig.Recv() != nil {
+			rt := sig.Recv().Type()
+			if p, ok := rt.(*types.Pointer); ok {
+				rt = p.Elem()
+			}
+			if n, ok := rt.(*types.Named); ok {
+				recv = n.Obj().Name()
+			}
+		}
+	}
+	field := false
+	if v, ok := obj.(*types.Var); ok && v.IsField() {
+		field = true
+	}
+	var found *ast.Ident
+	for _, f := range d.files {
+		ast.Inspect(f, func(n ast.Node) bool {
+			if found != nil {
+				return false
+			}
+			switch n := n.(type) {
+			case *ast.FuncDecl:
+				if n.Name.Name != obj.Name() {
+					return false
+				}
+				if recv == "" && n.Recv == nil || recv != "" && n.Recv != nil && recvName(n.Recv) == recv {
+					found = n.Name
+				}
+				return false
+			case *ast.TypeSpec:
+				if !field && n.Name.Name == obj.Name() {
+					found = n.Name
+					return false
+				}
+				return field
+			case *ast.ValueSpec:
+				for _, id := range n.Names {
+					if !field && id.Name == obj.Name() {
+						found = id
+					}
+				}
+				return false
+			case *ast.Field:
+				for _, id := range n.Names {
+					if field && id.Name == obj.Name() {
+						found = id
+					}
+				}
+			}
+			return true
+		})
+		if found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// recvName returns the base type name of a method receiver.
+func recvName(recv *ast.FieldList) string {
+	if len(recv.List) == 0 {
+		return ""
+	}
+	x := recv.List[0].Type
+	for {
+		switch t := x.(type) {
+		case *ast.StarExpr:
+			x = t.X
+		case *ast.IndexExpr:
+			x = t.X
+		case *ast.IndexListExpr:
+			x = t.X
+		case *ast.Ident:
+			return t.Name
+		default:
+			return ""
+		}
+	}
+}
+
+func (s *Server) completion(path string, pos Position) []CompletionItem {
+	items := []CompletionItem{}
+	text, ok := s.text(path)
+	if !ok {
+		return items
+	}
+	offset := offsetOf(text, pos)
+	start := offset
+	for start > 0 && isIdentChar(rune(text[start-1])) {
+		start--
+	}
+	prefix := text[start:offset]
+
+	t := s.lookup(path, pos)
+	if t == nil {
+		return items
+	}
+	scope := t.c.info.Scopes[t.file]
+	if scope == nil {
+		return items
+	}
+	// Adjust the position to the start of the prefix, which may not be
+	// in the last checked version of the document.
+	if n := offset - start; int(t.pos)-n >= t.tfile.Base() {
+		t.pos -= token.Pos(n)
+	}
+	scope = scope.Innermost(t.pos)
+	if scope == nil {
+		scope = t.c.info.Scopes[t.file]
+	}
+
+	add := func(obj types.Object) {
+		if !strings.HasPrefix(obj.Name(), prefix) || obj.Name() == "_" {
+			return
+		}
+		items = append(items, completionItem(obj, t.c.pkgPath))
+	}
+
+	// Selector expression: complete members of the selected package or value.
+	if start > 0 && text[start-1] == '.' {
+		end := start - 1
+		xstart := end
+		for xstart > 0 && isIdentChar(rune(text[xstart-1])) {
+			xstart--
+		}
+		name := text[xstart:end]
+		if name == "" {
+			return items
+		}
+		_, obj := scope.LookupParent(name, token.NoPos)
+		switch obj := obj.(type) {
+		case nil:
+		case *types.PkgName:
+			ps := obj.Imported().Scope()
+			for _, n := range ps.Names() {
+				if o := ps.Lookup(n); o.Exported() {
+					add(o)
+				}
+			}
+		default:
+			for _, o := range members(obj.Type()) {
+				if o.Exported() || o.Pkg() != nil && o.Pkg().Path() == t.c.pkgPath {
+					add(o)
+				}
+			}
+		}
+		sortItems(items)
+		return items
+	}
+
+	// Identifier: complete names in scope, and keywords.
+	seen := map[string]bool{}
+	for sc := scope; sc != nil; sc = sc.Parent() {
+		// Local declarations must precede the position, unlike package
+		// and file level ones.
+		local := sc.Parent() != nil && sc.Parent() != types.Universe && sc.Parent().Parent() != types.Universe
+		for _, n := range sc.Names() {
+			obj := sc.Lookup(n)
+			if seen[n] || local && obj.Pos() > t.pos {
+				continue
+			}
+			seen[n] = true
+			add(obj)
+		}
+	}
+	for _, kw := range keywords {
+		if prefix != "" && strings.HasPrefix(kw, prefix) {
+			items = append(items, CompletionItem{Label: kw, Kind: kindKeyword})
+		}
+	}
+	sortItems(items)
+	return items
+}
+
+// members returns the fields and methods of values of type typ.
+func members(typ types.Type) []types.Object {
+	var objs []types.Object
+	if p, ok := typ.Underlying().(*types.Pointer); ok {
+		typ = p.Elem()
+	}
+	if st, ok := typ.Underlying().(*types.Struct); ok {
+		for i := 0; i < st.NumFields(); i++ {
+			objs = append(objs, st.Field(i))
+		}
+	}
+	mtyp := typ
+	if _, ok := typ.Underlying().(*types.Interface); !ok {
+		mtyp = types.NewPointer(typ)
+	}
+	ms := types.NewMethodSet(mtyp)
+	for i := 0; i < ms.Len(); i++ {
+		objs = append(objs, ms.At(i).Obj())
+	}
+	return objs
+}
+
+func completionItem(obj types.Object, pkgPath string) CompletionItem {
+	item := CompletionItem{Label: obj.Name()}
+	qualifier := func(p *types.Package) string {
+		if p.Path() == pkgPath {
+			return ""
+		}
+		return p.Name()
+	}
+	switch obj := obj.(type) {
+	case *types.Func:
+		item.Kind = kindFunction
+		if sig, ok := obj.Type().(*types.Signature); ok && sig.Recv() != nil {
+			item.Kind = kindMethod
+		}
+		item.Detail = types.TypeString(obj.Type(), qualifier)
+	case *types.Var:
+		item.Kind = kindVariable
+		if obj.IsField() {
+			item.Kind = kindField
+		}
+		item.Detail = types.TypeString(obj.Type(), qualifier)
+	case *types.Const:
+		item.Kind = kindConstant
+		item.Detail = types.TypeString(obj.Type(), qualifier)
+	case *types.TypeName:
+		item.Kind = kindClass
+		switch obj.Type().Underlying().(type) {
+		case *types.Struct:
+			item.Kind = kindStruct
+		case *types.Interface:
+			item.Kind = kindInterface
+		}
+	case *types.PkgName:
+		item.Kind = kindModule
+		item.Detail = obj.Imported().Path()
+	case *types.Builtin:
+		item.Kind = kindFunction
+	}
+	return item
+}
+
+func sortItems(items []CompletionItem) {
+	sort.SliceStable(items, func(i, j int) bool { return items[i].Label < items[j].Label })
+}
+
+func isIdentChar(r rune) bool {
+	return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+}
@@ -0,0 +1,291 @@
+// Package lsp implements a Language Server Protocol server for Gno, as
+// served by `gno lsp`.
+//
+// Packages are loaded with the gnovm packages loader and type checked with
+// [gno.TypeCheckMemPackage], using the content of the documents opened in
+// the editor in place of the files on disk. Type information of the last
+// successful check of a package is used for hover, go-to-definition and
+// completion requests.
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/gnovm/pkg/packages/pkgdownload"
+)
+
+// Config is the configuration of a Server.
+type Config struct {
+	RootDir string                     // clone location of github.com/gnolang/gno
+	Fetcher pkgdownload.PackageFetcher // used to load remote dependencies
+	Log     io.Writer                  // if set, used to log errors
+}
+
+// Server is a Language Server Protocol server.
+type Server struct {
+	cfg      Config
+	out      io.Writer
+	docs     map[string]string    // open documents text, by path
+	checks   map[string]*pkgCheck // last check results, by package dir
+	decls    map[string]*pkgDecls // parsed declarations of imported packages, by dir
+	diags    map[string]bool      // paths with published diagnostics
+	shutdown bool
+}
+
+// NewServer returns a new Server.
+func NewServer(cfg Config) *Server {
+	return &Server{
+		cfg:    cfg,
+		docs:   map[string]string{},
+		checks: map[string]*pkgCheck{},
+		decls:  map[string]*pkgDecls{},
+		diags:  map[string]bool{},
+	}
+}
+
+// Serve reads requests from in and writes responses and notifications
+// to out, until the client sends the exit notification or closes in.
+func (s *Server) Serve(in io.Reader, out io.Writer) error {
+	s.out = out
+	r := bufio.NewReader(in)
+	for {
+		msg, err := readMessage(r)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if msg.Method == "exit" {
+			return nil
+		}
+		s.handle(msg)
+	}
+}
+
+// readMessage reads a JSON-RPC message: a set of headers, including
+// Content-Length, followed by an empty line and a JSON content.
+func readMessage(r *bufio.Reader) (*message, error) {
+	n := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if n >= 0 {
+				break
+			}
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "Content-Length:"); ok {
+			if n, err = strconv.Atoi(strings.TrimSpace(v)); err != nil {
+				return nil, fmt.Errorf("invalid header: %q", line)
+			}
+		}
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	msg := &message{}
+	if err := json.Unmarshal(buf, msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+func (s *Server) send(msg *message) {
+	msg.JSONRPC = "2.0"
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Fprintf(s.out, "Content-Length: %d\r\n\r\n%s", len(b), b)
+}
+
+func (s *Server) notify(method string, params any) {
+	b, err := json.Marshal(params)
+	if err != nil {
+		panic(err)
+	}
+	s.send(&message{Method: method, Params: b})
+}
+
+func (s *Server) logf(format string, args ...any) {
+	if s.cfg.Log != nil {
+		fmt.Fprintf(s.cfg.Log, format+"\n", args...)
+	}
+}
+
+// handle processes a request or a notification. Requests always get
+// a response, with a null result if none.
+func (s *Server) handle(msg *message) {
+	result, err := s.dispatch(msg)
+	if msg.ID == nil {
+		if err != nil {
+			s.logf("%s: %v", msg.Method, err)
+		}
+		return
+	}
+	resp := &message{ID: msg.ID}
+	var rerr *responseError
+	switch {
+	case errors.As(err, &rerr):
+		resp.Error = rerr
+	case err != nil:
+		resp.Error = &responseError{Code: codeInternalError, Message: err.Error()}
+	case result == nil:
+		resp.Result = json.RawMessage("null")
+	default:
+		resp.Result = result
+	}
+	s.send(resp)
+}
+
+func (s *Server) dispatch(msg *message) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v", r)
+		}
+	}()
+	if s.shutdown && msg.Method != "exit" {
+		return nil, &responseError{Code: codeInvalidParams, Message: "server is shut down"}
+	}
+	switch msg.Method {
+	case "initialize":
+		return map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync": map[string]any{
+					"openClose": true,
+					"change":    1, // full document
+					"save":      true,
+				},
+				"hoverProvider":      true,
+				"definitionProvider": true,
+				"completionProvider": map[string]any{"triggerCharacters": []string{"."}},
+			},
+			"serverInfo": map[string]any{"name": "gno lsp"},
+		}, nil
+	case "initialized":
+		return nil, nil
+	case "shutdown":
+		s.shutdown = true
+		return nil, nil
+	case "textDocument/didOpen":
+		var p didOpenParams
+		if err := unmarshal(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		path := uriToPath(p.TextDocument.URI)
+		s.docs[path] = p.TextDocument.Text
+		s.update(filepath.Dir(path))
+		return nil, nil
+	case "textDocument/didChange":
+		var p didChangeParams
+		if err := unmarshal(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		if len(p.ContentChanges) == 0 {
+			return nil, nil
+		}
+		path := uriToPath(p.TextDocument.URI)
+		s.docs[path] = p.ContentChanges[len(p.ContentChanges)-1].Text
+		s.update(filepath.Dir(path))
+		return nil, nil
+	case "textDocument/didSave":
+		return nil, nil
+	case "textDocument/didClose":
+		var p didCloseParams
+		if err := unmarshal(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		path := uriToPath(p.TextDocument.URI)
+		delete(s.docs, path)
+		s.update(filepath.Dir(path))
+		return nil, nil
+	case "textDocument/hover":
+		var p textDocumentPositionParams
+		if err := unmarshal(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		h := s.hover(uriToPath(p.TextDocument.URI), p.Position)
+		if h == nil {
+			return nil, nil
+		}
+		return h, nil
+	case "textDocument/definition":
+		var p textDocumentPositionParams
+		if err := unmarshal(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		loc := s.definition(uriToPath(p.TextDocument.URI), p.Position)
+		if loc == nil {
+			return nil, nil
+		}
+		return loc, nil
+	case "textDocument/completion":
+		var p textDocumentPositionParams
+		if err := unmarshal(msg.Params, &p); err != nil {
+			return nil, err
+		}
+		return &completionList{Items: s.completion(uriToPath(p.TextDocument.URI), p.Position)}, nil
+	}
+	if msg.ID == nil || strings.HasPrefix(msg.Method, "$/") {
+		return nil, nil // ignore unknown notifications
+	}
+	return nil, &responseError{Code: codeMethodNotFound, Message: "method not supported: " + msg.Method}
+}
+
+func unmarshal(params json.RawMessage, v any) error {
+	if err := json.Unmarshal(params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// update checks the package in dir and publishes its diagnostics.
+func (s *Server) update(dir string) {
+	c := s.check(dir)
+	if prev := s.checks[dir]; prev != nil && c.info == nil {
+		// Keep type information of the last successful check,
+		// e.g. while the user is typing a selector expression.
+		c.info, c.pkgPath = prev.info, prev.pkgPath
+	}
+	s.checks[dir] = c
+
+	for path := range s.diags {
+		if filepath.Dir(path) == dir {
+			if _, ok := c.diags[path]; !ok {
+				delete(s.diags, path)
+				s.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: pathToURI(path), Diagnostics: []Diagnostic{}})
+			}
+		}
+	}
+	for path, diags := range c.diags {
+		s.diags[path] = true
+		s.notify("textDocument/publishDiagnostics", &publishDiagnosticsParams{URI: pathToURI(path), Diagnostics: diags})
+	}
+}
+
+// text returns the content of the file at path, from the open documents
+// or from disk.
+func (s *Server) text(path string) (string, bool) {
+	if text, ok := s.docs[path]; ok {
+		return text, true
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", false
+	}
+	return string(b), true
+}
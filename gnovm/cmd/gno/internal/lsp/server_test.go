@@ -0,0 +1,212 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/packages/pkgdownload/examplespkgfetcher"
+)
+
+const testSource = `package hello
+
+import "strings"
+
+// Greeting is the greeting prefix.
+const Greeting = "Hello"
+
+// Hello returns a greeting for name.
+func Hello(name string) string {
+	return Greeting + " " + strings.ToUpper(name)
+}
+
+func Render(path string) string {
+	return Hello(path)
+}
+`
+
+// testClient is a minimal Language Server Protocol client.
+type testClient struct {
+	t    *testing.T
+	w    io.Writer
+	id   int
+	msgs chan *message
+}
+
+func newTestClient(t *testing.T) *testClient {
+	t.Helper()
+	cr, sw := io.Pipe()
+	sr, cw := io.Pipe()
+	c := &testClient{t: t, w: cw, msgs: make(chan *message, 16)}
+	s := NewServer(Config{
+		RootDir: gnoenv.RootDir(),
+		Fetcher: examplespkgfetcher.New(""),
+	})
+	go func() {
+		s.Serve(sr, sw)
+		sw.Close()
+	}()
+	go func() {
+		defer close(c.msgs)
+		r := bufio.NewReader(cr)
+		for {
+			msg, err := readMessage(r)
+			if err != nil {
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	t.Cleanup(func() { cw.Close() })
+	return c
+}
+
+func (c *testClient) write(msg map[string]any) {
+	c.t.Helper()
+	msg["jsonrpc"] = "2.0"
+	b, err := json.Marshal(msg)
+	require.NoError(c.t, err)
+	_, err = fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	require.NoError(c.t, err)
+}
+
+// request sends a request and decodes its result into result.
+func (c *testClient) request(method string, params any, result any) {
+	c.t.Helper()
+	c.id++
+	c.write(map[string]any{"id": c.id, "method": method, "params": params})
+	msg := c.wait(func(msg *message) bool { return string(msg.ID) == fmt.Sprint(c.id) })
+	require.Nil(c.t, msg.Error, "%s failed", method)
+	if result != nil {
+		b, err := json.Marshal(msg.Result)
+		require.NoError(c.t, err)
+		require.NoError(c.t, json.Unmarshal(b, result))
+	}
+}
+
+func (c *testClient) notify(method string, params any) {
+	c.t.Helper()
+	c.write(map[string]any{"method": method, "params": params})
+}
+
+// diagnostics waits for the next diagnostics published for uri.
+func (c *testClient) diagnostics(uri string) []Diagnostic {
+	c.t.Helper()
+	msg := c.wait(func(msg *message) bool {
+		return msg.Method == "textDocument/publishDiagnostics" && strings.Contains(string(msg.Params), uri)
+	})
+	var p publishDiagnosticsParams
+	require.NoError(c.t, json.Unmarshal(msg.Params, &p))
+	return p.Diagnostics
+}
+
+func (c *testClient) wait(match func(*message) bool) *message {
+	c.t.Helper()
+	timeout := time.After(time.Minute)
+	for {
+		select {
+		case msg, ok := <-c.msgs:
+			require.True(c.t, ok, "connection closed")
+			if match(msg) {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatal("timeout")
+		}
+	}
+}
+
+func TestServer(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "gnomod.toml"),
+		[]byte("module = \"gno.land/r/test/hello\"\ngno = \"0.9\"\n"), 0o644))
+	path := filepath.Join(dir, "hello.gno")
+	require.NoError(t, os.WriteFile(path, []byte(testSource), 0o644))
+	uri := pathToURI(path)
+
+	c := newTestClient(t)
+
+	var init struct {
+		Capabilities map[string]any `json:"capabilities"`
+	}
+	c.request("initialize", map[string]any{"rootUri": pathToURI(dir)}, &init)
+	assert.Equal(t, true, init.Capabilities["hoverProvider"])
+	c.notify("initialized", map[string]any{})
+
+	// Diagnostics for a type error in the open document.
+	bad := strings.Replace(testSource, `return Hello(path)`, `return Hello(1)`, 1)
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{"uri": uri, "languageId": "gno", "version": 1, "text": bad},
+	})
+	diags := c.diagnostics(uri)
+	require.Len(t, diags, 1)
+	assert.Equal(t, 13, diags[0].Range.Start.Line)
+	assert.Equal(t, "gnoTypeCheckError", diags[0].Code)
+	assert.Contains(t, diags[0].Message, "cannot use 1")
+
+	// Diagnostics are cleared once fixed.
+	c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri, "version": 2},
+		"contentChanges": []map[string]any{{"text": testSource}},
+	})
+	assert.Empty(t, c.diagnostics(uri))
+
+	at := func(line, char int) map[string]any {
+		return map[string]any{"textDocument": map[string]any{"uri": uri}, "position": map[string]any{"line": line, "character": char}}
+	}
+
+	// Hover on a local function call and an imported function.
+	var h hover
+	c.request("textDocument/hover", at(13, 9), &h)
+	assert.Contains(t, h.Contents.Value, "func Hello(name string) string")
+	assert.Contains(t, h.Contents.Value, "Hello returns a greeting for name.")
+	c.request("textDocument/hover", at(9, 34), &h)
+	assert.Contains(t, h.Contents.Value, "func strings.ToUpper(s string) string")
+
+	// Go to definition, in the package and in the standard library.
+	var loc Location
+	c.request("textDocument/definition", at(13, 9), &loc)
+	assert.Equal(t, uri, loc.URI)
+	assert.Equal(t, Range{Start: Position{8, 5}, End: Position{8, 10}}, loc.Range)
+	c.request("textDocument/definition", at(9, 34), &loc)
+	assert.True(t, strings.HasPrefix(uriToPath(loc.URI), filepath.Join(gnoenv.RootDir(), "gnovm", "stdlibs", "strings")), loc.URI)
+
+	// Completion of package members, while the document does not parse.
+	incomplete := strings.Replace(testSource, `return Hello(path)`, `return strings.Has`, 1)
+	c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri, "version": 3},
+		"contentChanges": []map[string]any{{"text": incomplete}},
+	})
+	c.diagnostics(uri)
+	var list completionList
+	c.request("textDocument/completion", at(13, 19), &list)
+	var labels []string
+	for _, item := range list.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.Equal(t, []string{"HasPrefix", "HasSuffix"}, labels)
+
+	// Completion of identifiers in scope.
+	c.request("textDocument/completion", at(9, 8), &list)
+	labels = labels[:0]
+	for _, item := range list.Items {
+		labels = append(labels, item.Label)
+	}
+	assert.Contains(t, labels, "Greeting")
+	assert.Contains(t, labels, "name")
+	assert.Contains(t, labels, "strings")
+	assert.NotContains(t, labels, "path")
+
+	c.request("shutdown", nil, nil)
+	c.notify("exit", nil)
+}
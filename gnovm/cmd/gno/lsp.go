@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+
+	"github.com/gnolang/gno/gnovm/cmd/gno/internal/lsp"
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+)
+
+type lspCmd struct {
+	rootDir string
+}
+
+func newLspCmd(io commands.IO) *commands.Command {
+	cmd := &lspCmd{}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "lsp",
+			ShortUsage: "lsp [flags]",
+			ShortHelp:  "runs the language server over stdio",
+			LongHelp: `Runs a Language Server Protocol server, reading requests from stdin and
+writing responses to stdout, for use by editors.
+
+It provides lint diagnostics, completion, hover documentation and
+go-to-definition for .gno files. Imports are resolved using the same package
+loader as the other gno commands, including the standard libraries and the
+examples/ directory of the gno root.`,
+		},
+		cmd,
+		func(_ context.Context, args []string) error {
+			return execLsp(cmd, args, io)
+		},
+	)
+}
+
+func (c *lspCmd) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(&c.rootDir, "root-dir", "", "clone location of github.com/gnolang/gno (gno tries to guess it)")
+}
+
+func execLsp(cmd *lspCmd, args []string, io commands.IO) error {
+	if len(args) > 0 {
+		return flag.ErrHelp
+	}
+	if cmd.rootDir == "" {
+		cmd.rootDir = gnoenv.RootDir()
+	}
+	server := lsp.NewServer(lsp.Config{
+		RootDir: cmd.rootDir,
+		Fetcher: testPackageFetcher,
+		Log:     io.Err(),
+	})
+	return server.Serve(io.In(), io.Out())
+}
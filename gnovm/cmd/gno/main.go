@@ -104,6 +104,7 @@ func newGnocliCmd(io commands.IO) (*commands.Command, *rootConfig) {
 		// install
 		newListCmd(io),
 		newLintCmd(io),
+		newLspCmd(io),
 		newModCmd(io),
 		// work
 		newReplCmd(),
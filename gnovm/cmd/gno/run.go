@@ -15,14 +15,18 @@ import (
 	"github.com/gnolang/gno/gnovm/pkg/test"
 	"github.com/gnolang/gno/tm2/pkg/commands"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
 )
 
 type runCmd struct {
-	verbose   bool
-	rootDir   string
-	expr      string
-	debug     bool
-	debugAddr string
+	verbose    bool
+	rootDir    string
+	expr       string
+	debug      bool
+	debugAddr  string
+	debugDAP   bool
+	cpuProfile string
+	gasProfile string
 }
 
 func newRunCmd(cio commands.IO) *commands.Command {
@@ -76,12 +80,36 @@ func (c *runCmd) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.BoolVar(
+		&c.debugDAP,
+		"debug-dap",
+		false,
+		"serve the debugger at -debug-addr using the Debug Adapter Protocol, for editors",
+	)
+
+	fs.StringVar(
+		&c.cpuProfile,
+		"cpuprofile",
+		"",
+		"write a pprof profile of the GnoVM CPU cycles to the given file",
+	)
+
+	fs.StringVar(
+		&c.gasProfile,
+		"gasprofile",
+		"",
+		"write a pprof profile of the gas consumed, including by the store, to the given file",
+	)
 }
 
-func execRun(cfg *runCmd, args []string, cio commands.IO) error {
+func execRun(cfg *runCmd, args []string, cio commands.IO) (err error) {
 	if len(args) == 0 {
 		return flag.ErrHelp
 	}
+	if cfg.debugDAP && cfg.debugAddr == "" {
+		return errors.New("-debug-dap requires -debug-addr")
+	}
 
 	if cfg.rootDir == "" {
 		cfg.rootDir = gnoenv.RootDir()
@@ -93,7 +121,7 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 
 	// init store and machine
 	output := test.OutputWithError(stdout, stderr)
-	_, testStore := test.ProdStore(
+	baseStore, testStore := test.ProdStore(
 		cfg.rootDir, output, nil)
 
 	if len(args) == 0 {
@@ -110,6 +138,19 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 		return errors.New("no files to run")
 	}
 
+	// With profiling, the machine and the store share a gas meter recording
+	// the gas consumed by each function.
+	var (
+		profiler *gno.Profiler
+		gasMeter storetypes.GasMeter
+	)
+	if cfg.cpuProfile != "" || cfg.gasProfile != "" {
+		profiler = gno.NewProfiler()
+		gasMeter = profiler.GasMeter(storetypes.NewInfiniteGasMeter())
+		cw := baseStore.CacheWrap()
+		testStore = testStore.BeginTransaction(cw, cw, gasMeter)
+	}
+
 	var send std.Coins
 	pkgPath := string(files[0].PkgName)
 	ctx := test.Context("", pkgPath, send)
@@ -121,12 +162,27 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 		MaxAllocBytes: maxAllocRun,
 		Context:       ctx,
 		Debug:         cfg.debug || cfg.debugAddr != "",
+		GasMeter:      gasMeter,
+		Profiler:      profiler,
 	})
 
 	defer m.Release()
 
 	// If the debug address is set, the debugger waits for a remote client to connect to it.
-	if cfg.debugAddr != "" {
+	if cfg.debugDAP {
+		dap, serr := gno.ServeDAP(cfg.debugAddr)
+		if serr != nil {
+			return serr
+		}
+		m.Debugger.EnableDAP(dap, nil)
+		defer func() {
+			exitCode := 0
+			if err != nil {
+				exitCode = 1
+			}
+			dap.Close(exitCode)
+		}()
+	} else if cfg.debugAddr != "" {
 		if err := m.Debugger.Serve(cfg.debugAddr); err != nil {
 			return err
 		}
@@ -134,7 +190,28 @@ func execRun(cfg *runCmd, args []string, cio commands.IO) error {
 
 	// run files
 	m.RunFiles(files...)
-	return runExpr(m, cfg.expr)
+	err = runExpr(m, cfg.expr)
+
+	if profiler != nil {
+		// Profiles are also written when the expression panics.
+		dir := args[0]
+		if s, serr := os.Stat(dir); serr == nil && !s.IsDir() {
+			dir = filepath.Dir(dir)
+		}
+		dirs := map[string]string{pkgPath: dir}
+		for _, p := range []struct{ fpath, typ string }{
+			{cfg.cpuProfile, test.ProfileCPU},
+			{cfg.gasProfile, test.ProfileGas},
+		} {
+			if p.fpath == "" {
+				continue
+			}
+			if perr := writeProfile(p.fpath, p.typ, profiler, dirs); perr != nil {
+				return perr
+			}
+		}
+	}
+	return err
 }
 
 func parseFiles(fpaths []string, stderr io.WriteCloser) ([]*gno.FileNode, error) {
@@ -78,6 +78,14 @@ func TestRunApp(t *testing.T) {
 			args:             []string{"run", "-debug-addr", "invalidhost:17538", "../../tests/integ/debugger/sample.gno"},
 			errShouldContain: "listen tcp",
 		},
+		{
+			args:             []string{"run", "-debug-dap", "../../tests/integ/debugger/sample.gno"},
+			errShouldContain: "-debug-dap requires -debug-addr",
+		},
+		{
+			args:             []string{"run", "-debug-dap", "-debug-addr", "invalidhost:17538", "../../tests/integ/debugger/sample.gno"},
+			errShouldContain: "listen tcp",
+		},
 		{
 			args:                 []string{"run", "../../tests/integ/invalid_assign/main.gno"},
 			recoverShouldContain: "cannot use bool as main.C without explicit conversion",
@@ -10,6 +10,7 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -33,6 +34,17 @@ type testCmd struct {
 	printEvents         bool
 	debug               bool
 	debugAddr           string
+	debugDAP            bool
+	cover               bool
+	coverMode           coverModeFlag
+	coverProfile        string
+	cpuProfile          string
+	gasProfile          string
+	bench               string
+	benchTime           string
+	fuzz                string
+	fuzzTime            string
+	fuzzMinimizeTime    string
 }
 
 func newTestCmd(io commands.IO) *commands.Command {
@@ -50,9 +62,25 @@ file pattern "*_test.gno" or "*_filetest.gno".
 
 The <package> can be directory or file path (relative or absolute).
 
-- "*_test.gno" files work like "*_test.go" files, but they contain only test
-functions. Benchmark and fuzz functions aren't supported yet. Similarly, only
-tests that belong to the same package are supported for now (no "xxx_test").
+- "*_test.gno" files work like "*_test.go" files, but they contain only test,
+benchmark and fuzz functions. As in Go, they may either belong to the package
+under test, or to a separate "xxx_test" package; the latter import the package
+under test by its path, and only have access to its exported identifiers. They
+may also import packages which themselves import the package under test.
+
+Benchmark functions are only run if their name matches the -bench flag; use
+-bench=. to run all of them. Alongside the time per iteration, benchmarks
+report the resources used by the GnoVM for each iteration: CPU cycles, gas
+(including the gas used by the store) and bytes allocated.
+
+Fuzz functions are run as tests, calling the fuzz target with each input of
+the seed corpus and of the "testdata/fuzz/FuzzXxx" directory. With -fuzz, the
+matching fuzz function (there must be only one, in a single package) is then
+fuzzed: new inputs are generated by mutating the corpus, until -fuzztime
+elapses or an input makes the fuzz target fail. The failing input is
+minimized, then written to "testdata/fuzz/FuzzXxx", so that it is run by
+subsequent invocations of 'gno test'. The fuzzed parameters may be strings,
+[]byte, bools, integers and floats.
 
 The package path used to execute the "*_test.gno" file is fetched from the
 module name found in 'gno.mod', or else it is set to
@@ -92,6 +120,30 @@ of the GnoVM, acting as a "golden test":
 To speed up execution, imports of pure packages are processed separately from
 the execution of the tests. This makes testing faster, but means that the
 initialization of imported pure packages cannot be checked in filetests.
+
+The -cover flag enables statement coverage of the tested packages (excluding
+test files), counting the statements executed both by "*_test.gno" and
+"*_filetest.gno" files. With -coverprofile, the results are additionally
+written to a file in the same format used by 'go test', so that they can be
+inspected with 'go tool cover', for instance:
+
+	gno test -coverprofile=cover.out ./r/demo/foo
+	go tool cover -html=cover.out
+
+Like in 'go test', setting -covermode or -coverprofile enables the coverage,
+even if -covermode is set to its default value. Only statement coverage is
+supported: the branches of the conditions are not measured.
+
+The -cpuprofile and -gasprofile flags write profiles of the CPU cycles and
+of the gas consumed by the GnoVM while running the tests, attributed to the
+Gno functions consuming them. The gas profile includes the gas used by the
+store to read and write objects, attributed to the function accessing them;
+samples are labeled with the kind of gas ("descriptor"). Both profiles use
+the pprof format, so they can be inspected with 'go tool pprof':
+
+	gno test -gasprofile=gas.out ./r/demo/foo
+	go tool pprof -top gas.out
+	go tool pprof -tagfocus=descriptor=GetObjectPerByte -top gas.out
 `,
 		},
 		cmd,
@@ -178,9 +230,86 @@ func (c *testCmd) RegisterFlags(fs *flag.FlagSet) {
 		"",
 		"enable interactive debugger using tcp address in the form [host]:port",
 	)
+
+	fs.BoolVar(
+		&c.debugDAP,
+		"debug-dap",
+		false,
+		"serve the debugger at -debug-addr using the Debug Adapter Protocol, for editors",
+	)
+
+	fs.StringVar(
+		&c.bench,
+		"bench",
+		"",
+		"run only those benchmarks matching a regular expression",
+	)
+
+	fs.StringVar(
+		&c.benchTime,
+		"benchtime",
+		"1s",
+		"run each benchmark for duration d or N times if `d` is of the form Nx",
+	)
+
+	fs.StringVar(
+		&c.fuzz,
+		"fuzz",
+		"",
+		"run the fuzz test matching the regular expression",
+	)
+
+	fs.StringVar(
+		&c.fuzzTime,
+		"fuzztime",
+		"",
+		"time to spend fuzzing, or `d` inputs if of the form Nx; default is to run until failure",
+	)
+
+	fs.StringVar(
+		&c.fuzzMinimizeTime,
+		"fuzzminimizetime",
+		"60s",
+		"time to spend minimizing a failing input, or `d` inputs if of the form Nx",
+	)
+
+	fs.BoolVar(
+		&c.cover,
+		"cover",
+		false,
+		"enable statement coverage analysis",
+	)
+
+	c.coverMode = coverModeFlag{mode: test.CoverModeSet}
+	fs.Var(
+		&c.coverMode,
+		"covermode",
+		"coverage analysis mode: set or count; implies -cover",
+	)
+
+	fs.StringVar(
+		&c.coverProfile,
+		"coverprofile",
+		"",
+		"write a Go-compatible coverage profile to the given file; implies -cover",
+	)
+
+	fs.StringVar(
+		&c.cpuProfile,
+		"cpuprofile",
+		"",
+		"write a pprof profile of the GnoVM CPU cycles to the given file",
+	)
+
+	fs.StringVar(
+		&c.gasProfile,
+		"gasprofile",
+		"",
+		"write a pprof profile of the gas consumed to the given file",
+	)
 }
 
-func execTest(cmd *testCmd, args []string, io commands.IO) error {
+func execTest(cmd *testCmd, args []string, io commands.IO) (err error) {
 	// Default to current directory if no args provided
 	if len(args) == 0 {
 		args = []string{"."}
@@ -191,6 +320,36 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 		cmd.rootDir = gnoenv.RootDir()
 	}
 
+	switch cmd.coverMode.mode {
+	case test.CoverModeSet, test.CoverModeCount:
+	default:
+		return fmt.Errorf("invalid -covermode %q: must be %q or %q",
+			cmd.coverMode.mode, test.CoverModeSet, test.CoverModeCount)
+	}
+	if cmd.coverProfile != "" || cmd.coverMode.set {
+		cmd.cover = true
+	}
+	if cmd.debugDAP && cmd.debugAddr == "" {
+		return errors.New("-debug-dap requires -debug-addr")
+	}
+
+	benchTime, benchTimeN, err := parseDurationOrCount("benchtime", cmd.benchTime)
+	if err != nil {
+		return err
+	}
+	var fuzzTime, fuzzMinimizeTime time.Duration
+	var fuzzTimeN, fuzzMinimizeTimeN int
+	if cmd.fuzzTime != "" {
+		fuzzTime, fuzzTimeN, err = parseDurationOrCount("fuzztime", cmd.fuzzTime)
+		if err != nil {
+			return err
+		}
+	}
+	fuzzMinimizeTime, fuzzMinimizeTimeN, err = parseDurationOrCount("fuzzminimizetime", cmd.fuzzMinimizeTime)
+	if err != nil {
+		return err
+	}
+
 	loadConf := packages.LoadConfig{
 		Fetcher:    testPackageFetcher,
 		Out:        io.Err(),
@@ -208,6 +367,18 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 		return nil
 	}
 
+	if cmd.fuzz != "" {
+		matched := 0
+		for _, pkg := range pkgs {
+			if len(pkg.Match) != 0 {
+				matched++
+			}
+		}
+		if matched > 1 {
+			return errors.New("cannot use -fuzz flag with multiple packages")
+		}
+	}
+
 	if cmd.timeout > 0 {
 		go func() {
 			time.Sleep(cmd.timeout)
@@ -226,8 +397,37 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 	opts.Verbose = cmd.verbose
 	opts.Metrics = cmd.printRuntimeMetrics
 	opts.Events = cmd.printEvents
-	opts.Debug = cmd.debug
+	opts.Debug = cmd.debug || cmd.debugDAP
 	opts.FailfastFlag = cmd.failfast
+	opts.BenchFlag = cmd.bench
+	opts.BenchTime = benchTime
+	opts.BenchTimeN = benchTimeN
+	opts.FuzzFlag = cmd.fuzz
+	opts.FuzzTime = fuzzTime
+	opts.FuzzTimeN = fuzzTimeN
+	opts.FuzzMinimizeTime = fuzzMinimizeTime
+	opts.FuzzMinimizeTimeN = fuzzMinimizeTimeN
+	if cmd.cover {
+		opts.Coverage = gno.NewCoverage()
+	}
+	if cmd.cpuProfile != "" || cmd.gasProfile != "" {
+		opts.Profiler = gno.NewProfiler()
+	}
+	pkgDirs := make(map[string]string)
+	if cmd.debugDAP {
+		dap, serr := gno.ServeDAP(cmd.debugAddr)
+		if serr != nil {
+			return serr
+		}
+		opts.DebugDAP = dap
+		defer func() {
+			exitCode := 0
+			if err != nil {
+				exitCode = 1
+			}
+			dap.Close(exitCode)
+		}()
+	}
 	cache := make(gno.TypeCheckCache, 64)
 
 	// test.ProdStore() is suitable for type-checking prod (non-test) files.
@@ -304,6 +504,10 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 
 		// Read MemPackage with all files.
 		mpkg := gno.MustReadMemPackage(pkg.Dir, pkgPath, gno.MPAnyAll)
+		pkgDirs[pkgPath] = pkg.Dir
+		if opts.DebugDAP != nil {
+			opts.DebugDAP.Dirs[pkgPath] = pkg.Dir
+		}
 		var didPanic, didError bool
 		startedAt := time.Now()
 		didPanic = catchPanic(pkg.Dir, pkgPath, io.Err(), func() {
@@ -343,10 +547,30 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 			if cmd.failfast {
 				return fail()
 			}
+		} else if cmd.cover {
+			io.ErrPrintfln("ok      %s \t%s\tcoverage: %.1f%% of statements",
+				prettyDir, dstr, opts.Coverage.Percent(pkgPath))
 		} else {
 			io.ErrPrintfln("ok      %s \t%s", prettyDir, dstr)
 		}
 	}
+
+	if cmd.coverProfile != "" {
+		if err := writeCoverProfile(cmd.coverProfile, cmd.coverMode.mode, opts.Coverage, pkgDirs); err != nil {
+			return err
+		}
+	}
+	if cmd.cpuProfile != "" {
+		if err := writeProfile(cmd.cpuProfile, test.ProfileCPU, opts.Profiler, pkgDirs); err != nil {
+			return err
+		}
+	}
+	if cmd.gasProfile != "" {
+		if err := writeProfile(cmd.gasProfile, test.ProfileGas, opts.Profiler, pkgDirs); err != nil {
+			return err
+		}
+	}
+
 	if testErrCount > 0 || buildErrCount > 0 {
 		return fail()
 	}
@@ -354,6 +578,65 @@ func execTest(cmd *testCmd, args []string, io commands.IO) error {
 	return nil
 }
 
+// parseDurationOrCount parses flags like -benchtime, which are either a
+// duration or a number of iterations like "100x".
+func parseDurationOrCount(flagName, s string) (time.Duration, int, error) {
+	if strings.HasSuffix(s, "x") {
+		n, err := strconv.Atoi(s[:len(s)-1])
+		if err != nil || n <= 0 {
+			return 0, 0, fmt.Errorf("invalid count %q for -%s", s, flagName)
+		}
+		return 0, n, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, 0, fmt.Errorf("invalid duration %q for -%s", s, flagName)
+	}
+	return d, 0, nil
+}
+
+// coverModeFlag is the value of the -covermode flag, which records whether it
+// was set explicitly, as it then implies -cover.
+type coverModeFlag struct {
+	mode string
+	set  bool
+}
+
+func (f coverModeFlag) String() string {
+	return f.mode
+}
+
+func (f *coverModeFlag) Set(mode string) error {
+	f.mode, f.set = mode, true
+	return nil
+}
+
+func writeCoverProfile(fpath, mode string, cov *gno.Coverage, dirs map[string]string) error {
+	f, err := os.Create(fpath)
+	if err != nil {
+		return fmt.Errorf("unable to create coverage profile: %w", err)
+	}
+	defer f.Close()
+
+	if err := test.WriteCoverProfile(f, cov, mode, dirs); err != nil {
+		return fmt.Errorf("unable to write coverage profile: %w", err)
+	}
+	return f.Close()
+}
+
+func writeProfile(fpath, typ string, p *gno.Profiler, dirs map[string]string) error {
+	f, err := os.Create(fpath)
+	if err != nil {
+		return fmt.Errorf("unable to create %s profile: %w", typ, err)
+	}
+	defer f.Close()
+
+	if err := test.WriteProfile(f, p, typ, dirs); err != nil {
+		return fmt.Errorf("unable to write %s profile: %w", typ, err)
+	}
+	return f.Close()
+}
+
 func determinePkgPath(mod *gnomod.File, dir, rootDir string) (string, bool) {
 	if mod != nil {
 		return mod.Module, true
@@ -29,6 +29,7 @@ const (
 	_allocSliceValue       = 40
 	_allocFuncValue        = 312
 	_allocMapValue         = 144
+	_allocChanValue        = 112
 	_allocBoundMethodValue = 176
 	_allocBlock            = 472
 	_allocPackageValue     = 240
@@ -60,6 +61,8 @@ const (
 	allocFunc        = _allocBase + _allocPointer + _allocFuncValue
 	allocMap         = _allocBase + _allocPointer + _allocMapValue
 	allocMapItem     = _allocTypedValue * 3 // XXX
+	allocChan        = _allocBase + _allocPointer + _allocChanValue
+	allocChanItem    = _allocTypedValue
 	allocBoundMethod = _allocBase + _allocPointer + _allocBoundMethodValue
 	allocBlock       = _allocBase + _allocPointer + _allocBlock
 	allocBlockItem   = _allocTypedValue
@@ -179,6 +182,10 @@ func (alloc *Allocator) AllocateMapItem() {
 	alloc.Allocate(allocMapItem)
 }
 
+func (alloc *Allocator) AllocateChan(items int64) {
+	alloc.Allocate(allocChan + allocChanItem*items)
+}
+
 func (alloc *Allocator) AllocateBoundMethod() {
 	alloc.Allocate(allocBoundMethod)
 }
@@ -333,6 +340,11 @@ func (alloc *Allocator) NewMap(size int) *MapValue {
 	return mv
 }
 
+func (alloc *Allocator) NewChan(size int) *ChanValue {
+	alloc.AllocateChan(int64(size))
+	return &ChanValue{Cap: size}
+}
+
 // Only used for constructing the main package
 func (alloc *Allocator) NewPackageValue(pn *PackageNode) *PackageValue {
 	alloc.AllocatePackageValue()
@@ -415,6 +427,10 @@ func (mv *MapValue) GetShallowSize() int64 {
 	return allocMap + allocMapItem*int64(mv.GetLength())
 }
 
+func (cv *ChanValue) GetShallowSize() int64 {
+	return allocChan + allocChanItem*int64(cv.Cap)
+}
+
 func (bmv *BoundMethodValue) GetShallowSize() int64 {
 	// skip .uverse
 	if bmv.Func.PkgPath == ".uverse" {
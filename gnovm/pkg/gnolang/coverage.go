@@ -0,0 +1,199 @@
+package gnolang
+
+import (
+	"sort"
+)
+
+// Coverage records how many times the statements of a set of files have
+// been executed. Files to be measured are registered with [Coverage.AddFile];
+// the statements executed by any [Machine] whose Coverage field points to
+// this value are then counted, by location.
+//
+// Coverage is not safe for concurrent use; it is meant to be shared by the
+// Machines of a single (sequential) test run.
+type Coverage struct {
+	blocks map[Location]*CoverBlock   // keyed by statement location
+	pkgs   map[string][]*CoverBlock   // by pkgpath, sorted by file/pos
+	files  map[string]map[string]bool // pkgpath -> file -> registered
+	hits   map[Location]int           // executed, but not (yet) registered
+	seen   map[Stmt]Location          // cache of computed stmt locations
+
+	covered int // number of distinct statements executed
+}
+
+// CoverBlock is a single statement in the coverage report.
+//
+// For statements containing other statements (like if, for and switch
+// statements), the span is truncated so that it ends where the first nested
+// statement begins, so that the blocks in a file never overlap.
+type CoverBlock struct {
+	File    string
+	Span    Span
+	NumStmt int
+	Count   int
+}
+
+// NewCoverage returns a new, empty Coverage.
+func NewCoverage() *Coverage {
+	return &Coverage{
+		blocks: make(map[Location]*CoverBlock),
+		pkgs:   make(map[string][]*CoverBlock),
+		files:  make(map[string]map[string]bool),
+		hits:   make(map[Location]int),
+		seen:   make(map[Stmt]Location),
+	}
+}
+
+// AddFile registers the statements of fn as belonging to the package
+// pkgPath. fn should be freshly parsed (see [ParseFile]); the statements are
+// matched against the executed ones using their location.
+// Files which have already been registered are ignored.
+func (c *Coverage) AddFile(pkgPath string, fn *FileNode) {
+	pfiles := c.files[pkgPath]
+	if pfiles == nil {
+		pfiles = make(map[string]bool)
+		c.files[pkgPath] = pfiles
+	}
+	if pfiles[fn.FileName] {
+		return
+	}
+	pfiles[fn.FileName] = true
+
+	// Collect all coverable statements in the file.
+	var spans []Span
+	collect := func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if s, ok := n.(Stmt); ok && isCoverableStmt(s) && !s.GetSpan().IsZero() {
+			spans = append(spans, s.GetSpan())
+		}
+		return n, TRANS_CONTINUE
+	}
+	Transcribe(fn, collect)
+	// Generic declarations are skipped when transcribing a file; their
+	// instances are executed with the spans of the template.
+	for _, d := range fn.Decls {
+		if isGenericDecl(d) {
+			Transcribe(d, collect)
+		}
+	}
+	sort.Slice(spans, func(i, j int) bool {
+		return spans[i].Compare(spans[j]) < 0
+	})
+
+	blocks := make([]*CoverBlock, 0, len(spans))
+	for i, span := range spans {
+		loc := Location{PkgPath: pkgPath, File: fn.FileName, Span: span}
+		if _, exists := c.blocks[loc]; exists {
+			continue
+		}
+		// Truncate the span of a statement containing other statements
+		// at the beginning of the first one.
+		bspan := span
+		if i+1 < len(spans) && spans[i+1].Pos.Compare(span.End) < 0 {
+			bspan.End = spans[i+1].Pos
+		}
+		bspan.Num = 0
+		cb := &CoverBlock{
+			File:    fn.FileName,
+			Span:    bspan,
+			NumStmt: 1,
+			Count:   c.hits[loc],
+		}
+		c.blocks[loc] = cb
+		blocks = append(blocks, cb)
+	}
+
+	pblocks := append(c.pkgs[pkgPath], blocks...)
+	sort.SliceStable(pblocks, func(i, j int) bool {
+		if pblocks[i].File != pblocks[j].File {
+			return pblocks[i].File < pblocks[j].File
+		}
+		return pblocks[i].Span.Compare(pblocks[j].Span) < 0
+	})
+	c.pkgs[pkgPath] = pblocks
+}
+
+// Packages returns the sorted package paths which have registered files.
+func (c *Coverage) Packages() []string {
+	res := make([]string, 0, len(c.pkgs))
+	for pkgPath := range c.pkgs {
+		res = append(res, pkgPath)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// Blocks returns the registered statements of pkgPath, sorted by file
+// and position.
+func (c *Coverage) Blocks(pkgPath string) []*CoverBlock {
+	return c.pkgs[pkgPath]
+}
+
+// Percent returns the percentage of registered statements of pkgPath which
+// have been executed at least once. If there are no statements, it returns 0.
+func (c *Coverage) Percent(pkgPath string) float64 {
+	var total, covered int
+	for _, cb := range c.pkgs[pkgPath] {
+		total += cb.NumStmt
+		if cb.Count > 0 {
+			covered += cb.NumStmt
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return 100 * float64(covered) / float64(total)
+}
+
+// NumCovered returns the number of distinct statements which have been
+// executed at least once, including those in files which are not registered.
+// It is used to guide fuzzing, which looks for inputs reaching new statements.
+func (c *Coverage) NumCovered() int {
+	return c.covered
+}
+
+// recordStmt is called by the Machine for each executed statement.
+func (c *Coverage) recordStmt(m *Machine, s Stmt) {
+	loc, ok := c.seen[s]
+	if !ok {
+		span := s.GetSpan()
+		if span.IsZero() || len(m.Blocks) == 0 {
+			return
+		}
+		bloc := m.LastBlock().GetSource(m.Store).GetLocation()
+		if bloc.File == "" {
+			// package level statement; e.g. the REPL.
+			return
+		}
+		loc = Location{PkgPath: bloc.PkgPath, File: bloc.File, Span: span}
+		c.seen[s] = loc
+	}
+	if cb := c.blocks[loc]; cb != nil {
+		if cb.Count == 0 {
+			c.covered++
+		}
+		cb.Count++
+	} else {
+		// Keep the count, in case the file is registered later.
+		if c.hits[loc] == 0 {
+			c.covered++
+		}
+		c.hits[loc]++
+	}
+}
+
+// isCoverableStmt returns whether s is a statement which is executed
+// (through OpExec) by the Machine, and as such counted by Coverage.
+func isCoverableStmt(s Stmt) bool {
+	switch s.(type) {
+	case *AssignStmt, *ExprStmt, *ForStmt, *IfStmt,
+		*IncDecStmt, *ReturnStmt, *RangeStmt, *BranchStmt,
+		*DeclStmt, *DeferStmt, *SwitchStmt,
+		*GoStmt, *SendStmt, *SelectStmt:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,61 @@
+package gnolang
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCoverage(t *testing.T) {
+	const src = `package cov
+
+func Abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
+
+func Unused() int {
+	return 1
+}
+`
+	cov := NewCoverage()
+	fn, err := ParseFile("cov.gno", src)
+	require.NoError(t, err)
+	cov.AddFile("gno.land/p/cov", fn)
+
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:  "gno.land/p/cov",
+		Coverage: cov,
+	})
+	defer m.Release()
+	m.RunMemPackage(&std.MemPackage{
+		Type:  MPUserProd,
+		Name:  "cov",
+		Path:  "gno.land/p/cov",
+		Files: []*std.MemFile{{Name: "cov.gno", Body: src}},
+	}, false)
+	m.Eval(Call(X("Abs"), 3))
+	m.Eval(Call(X("Abs"), 4))
+
+	type block struct {
+		span  string
+		count int
+	}
+	var got []block
+	for _, cb := range cov.Blocks("gno.land/p/cov") {
+		got = append(got, block{cb.Span.String(), cb.Count})
+	}
+	assert.Equal(t, []block{
+		{"4:2-5:3", 2}, // if x < 0 {
+		{"5:3-12", 0},  // return -x
+		{"7:2-10", 2},  // return x
+		{"11:2-10", 0}, // return 1
+	}, got)
+	assert.Equal(t, 50.0, cov.Percent("gno.land/p/cov"))
+	assert.Equal(t, 2, cov.NumCovered())
+	assert.Equal(t, []string{"gno.land/p/cov"}, cov.Packages())
+}
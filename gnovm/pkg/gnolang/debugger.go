@@ -59,6 +59,7 @@ type Debugger struct {
 	nextDepth   int                         // function call depth at the 'next' command
 	getSrc      func(string, string) string // helper to access source from repl or others
 	rootDir     string
+	dap         *DAPServer // if not nil, debugger IO uses the Debug Adapter Protocol
 }
 
 // Enable makes the debugger d active, using in as input reader, out as output writer and f as a source helper.
@@ -140,26 +141,39 @@ loop:
 		switch m.Debugger.state {
 		case DebugAtInit:
 			debugUpdateLocation(m)
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.init(m)
+				continue loop
+			}
 			fmt.Fprintln(m.Debugger.out, "Welcome to the Gnovm debugger. Type 'help' for list of commands.")
 			m.Debugger.scanner = bufio.NewScanner(m.Debugger.in)
 			m.Debugger.state = DebugAtCmd
 		case DebugAtCmd:
-			if err := debugCmd(m); err != nil {
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.serve(m)
+			} else if err := debugCmd(m); err != nil {
 				fmt.Fprintln(m.Debugger.out, "Command failed:", err)
 			}
 		case DebugAtRun:
 			if !m.Debugger.enabled {
 				break loop
 			}
+			if m.Debugger.dap != nil && m.Debugger.dap.poll(m) {
+				continue loop
+			}
 			switch m.Debugger.lastCmd {
 			case "si", "stepi":
 				m.Debugger.state = DebugAtCmd
-				debugLineInfo(m)
+				if m.Debugger.dap != nil {
+					m.Debugger.dap.stopped("step")
+				} else {
+					debugLineInfo(m)
+				}
 			case "s", "step":
 				if m.Debugger.loc != m.Debugger.prevLoc && m.Debugger.loc.File != "" {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "step")
 					continue loop
 				}
 			case "n", "next":
@@ -167,26 +181,29 @@ loop:
 					(m.Debugger.nextDepth == 0 || !sameLine(m.Debugger.loc, m.Debugger.nextLoc) && callDepth(m) <= m.Debugger.nextDepth) {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "step")
 					continue loop
 				}
 			case "stepout", "so":
 				if callDepth(m) < m.Debugger.nextDepth {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "step")
 					continue loop
 				}
 			default:
 				if atBreak(m) {
 					m.Debugger.state = DebugAtCmd
 					m.Debugger.prevLoc = m.Debugger.loc
-					debugList(m, "")
+					debugStopped(m, "breakpoint")
 					continue loop
 				}
 			}
 			break loop
 		case DebugAtExit:
+			if m.Debugger.dap != nil {
+				m.Debugger.dap.Close(0)
+			}
 			os.Exit(0)
 		}
 	}
@@ -203,6 +220,16 @@ loop:
 	}
 }
 
+// debugStopped reports to the user that the program has stopped for the
+// given reason ("step" or "breakpoint").
+func debugStopped(m *Machine, reason string) {
+	if m.Debugger.dap != nil {
+		m.Debugger.dap.stopped(reason)
+		return
+	}
+	debugList(m, "")
+}
+
 // callDepth returns the function call depth.
 func callDepth(m *Machine) int {
 	n := 0
@@ -227,13 +254,53 @@ func atBreak(m *Machine) bool {
 		return false
 	}
 	for _, b := range m.Debugger.breakpoints {
-		if loc.File == b.File && loc.Line == b.Line {
+		if loc.Line != b.Line {
+			continue
+		}
+		if loc.File == b.File {
+			return true
+		}
+		// Same file given by different paths, i.e. relative versus absolute.
+		if p := m.Debugger.sourcePath(loc); p != "" && p == m.Debugger.sourcePath(b) {
 			return true
 		}
 	}
 	return false
 }
 
+// sourcePath returns the absolute path of the source file at loc, or
+// an empty string if it can not be found on the local filesystem.
+func (d *Debugger) sourcePath(loc Location) string {
+	if loc.File == "" {
+		return ""
+	}
+	var paths []string
+	if filepath.IsAbs(loc.File) {
+		paths = append(paths, loc.File)
+	} else {
+		if d.dap != nil {
+			if dir, ok := d.dap.Dirs[loc.PkgPath]; ok {
+				paths = append(paths, filepath.Join(dir, loc.File))
+			}
+		}
+		paths = append(paths, loc.File)
+		if d.rootDir != "" && loc.PkgPath != "" {
+			paths = append(paths,
+				filepath.Join(d.rootDir, "gnovm", "stdlibs", loc.PkgPath, loc.File),
+				filepath.Join(d.rootDir, "examples", loc.PkgPath, loc.File),
+			)
+		}
+	}
+	for _, p := range paths {
+		if fi, err := os.Stat(p); err == nil && !fi.IsDir() {
+			if p, err = filepath.Abs(p); err == nil {
+				return p
+			}
+		}
+	}
+	return ""
+}
+
 // debugCmd processes a debugger REPL command. It displays a prompt, then
 // reads and parses a command from the debugger input stream, then executes
 // the corresponding function or returns an error.
@@ -739,6 +806,37 @@ func debugEvalExpr(m *Machine, node ast.Node) (tv TypedValue, err error) {
 // the current function call frame, or the global frame if not found.
 // Note: the commands 'up' and 'down' change the frame level to start from.
 func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
+	sblocks := debugFrameBlocks(m)
+	if len(sblocks) == 0 {
+		return tv, false
+	}
+
+	// Search value in current frame level blocks, or main scope.
+	for _, b := range sblocks {
+		switch t := b.Source.(type) {
+		case *IfStmt:
+			for i, s := range ifBody(m, t).Source.GetBlockNames() {
+				if string(s) == name {
+					return b.Values[i], true
+				}
+			}
+		}
+		for i, s := range b.Source.GetBlockNames() {
+			if string(s) == name {
+				return b.Values[i], true
+			}
+		}
+	}
+	// Fallback: search a global value.
+	if v := sblocks[0].Source.GetSlot(m.Store, Name(name), true); v != nil {
+		return *v, true
+	}
+	return tv, false
+}
+
+// debugFrameBlocks returns the blocks of the current frame level, innermost
+// first, followed by the global block if any.
+func debugFrameBlocks(m *Machine) []*Block {
 	// Position to the right frame.
 	ncall := 0
 	var i int
@@ -757,7 +855,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// XXX The following logic isn't necessary and it isn't correct either.
@@ -791,7 +889,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 		}
 	}
 	if i < 0 {
-		return tv, false
+		return nil
 	}
 
 	// get SourceBlocks in the same frame level.
@@ -805,28 +903,7 @@ func debugLookup(m *Machine, name string) (tv TypedValue, ok bool) {
 	if i > 0 {
 		sblocks = append(sblocks, m.Blocks[0]) // Add global block
 	}
-
-	// Search value in current frame level blocks, or main scope.
-	for _, b := range sblocks {
-		switch t := b.Source.(type) {
-		case *IfStmt:
-			for i, s := range ifBody(m, t).Source.GetBlockNames() {
-				if string(s) == name {
-					return b.Values[i], true
-				}
-			}
-		}
-		for i, s := range b.Source.GetBlockNames() {
-			if string(s) == name {
-				return b.Values[i], true
-			}
-		}
-	}
-	// Fallback: search a global value.
-	if v := sblocks[0].Source.GetSlot(m.Store, Name(name), true); v != nil {
-		return *v, true
-	}
-	return tv, false
+	return sblocks
 }
 
 // ifBody returns the Then or Else body corresponding to the current location.
@@ -864,13 +941,21 @@ func debugStack(m *Machine, arg string) error {
 }
 
 func debugFrameFunc(m *Machine, n int) *FuncValue {
+	if f := debugCallFrame(m, n); f != nil {
+		return f.Func
+	}
+	return nil
+}
+
+// debugCallFrame returns the n-th call frame, starting from the innermost one.
+func debugCallFrame(m *Machine, n int) *Frame {
 	for ncall, i := 0, len(m.Frames)-1; i >= 0; i-- {
-		f := m.Frames[i]
+		f := &m.Frames[i]
 		if f.Func == nil {
 			continue
 		}
 		if ncall == n {
-			return f.Func
+			return f
 		}
 		ncall++
 	}
@@ -0,0 +1,706 @@
+package gnolang
+
+// This file implements a Debug Adapter Protocol (DAP) front-end to the
+// machine debugger, so that editors such as VS Code or nvim-dap can drive it.
+// See https://microsoft.github.io/debug-adapter-protocol/specification.
+//
+// The machine is single threaded from the debugger point of view, and is
+// exposed as a single DAP thread. Frame ids are debugger frame levels, as
+// used by the 'up' and 'down' commands.
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go/parser"
+	"io"
+	"net"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	dapThreadID     = 1    // the only thread reported to the client
+	dapMaxChildren  = 1000 // maximum number of children reported for a variable
+	dapMaxValueSize = 256  // maximum size of a variable value string
+)
+
+// DAPServer is a Debug Adapter Protocol server attached to a Debugger
+// with EnableDAP.
+type DAPServer struct {
+	// Dirs maps package paths to their directory on the local filesystem,
+	// in order to resolve source file paths between client and machine.
+	Dirs map[string]string
+
+	conn        io.ReadWriteCloser
+	reqs        chan *dapRequest
+	seq         int
+	configured  bool             // configurationDone request received
+	stopOnEntry bool             // as requested in launch or attach arguments
+	breakpoints map[string][]int // breakpoint lines per client source path
+	vars        []dapVarRef      // variable references, valid while stopped
+	sources     []Location       // source references, for sources without a local path
+	closed      bool
+}
+
+// dapVarRef is the target of a DAP variables reference.
+type dapVarRef struct {
+	level   int        // frame level, for scopes
+	globals bool       // if true, the package scope
+	locals  bool       // if true, the frame local scope
+	tv      TypedValue // else the value to expand
+}
+
+type dapRequest struct {
+	Seq       int             `json:"seq"`
+	Type      string          `json:"type"`
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+type dapResponse struct {
+	Seq        int    `json:"seq"`
+	Type       string `json:"type"`
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type dapEvent struct {
+	Seq   int    `json:"seq"`
+	Type  string `json:"type"`
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+type dapSource struct {
+	Name            string `json:"name,omitempty"`
+	Path            string `json:"path,omitempty"`
+	SourceReference int    `json:"sourceReference,omitempty"`
+	Origin          string `json:"origin,omitempty"`
+}
+
+type dapStackFrame struct {
+	ID     int        `json:"id"`
+	Name   string     `json:"name"`
+	Source *dapSource `json:"source,omitempty"`
+	Line   int        `json:"line"`
+	Column int        `json:"column"`
+}
+
+type dapScope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type dapVariable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+type dapBreakpoint struct {
+	Verified bool `json:"verified"`
+	Line     int  `json:"line"`
+}
+
+// ServeDAP waits for a DAP client to connect at addr, and returns
+// a server using this connection.
+func ServeDAP(addr string) (*DAPServer, error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	defer l.Close()
+	print("Waiting for DAP client to connect at ", addr)
+	conn, err := l.Accept()
+	if err != nil {
+		return nil, err
+	}
+	println(" connected!")
+	return NewDAPServer(conn), nil
+}
+
+// NewDAPServer returns a DAP server using conn for client IO.
+func NewDAPServer(conn io.ReadWriteCloser) *DAPServer {
+	s := &DAPServer{
+		Dirs:        map[string]string{},
+		conn:        conn,
+		reqs:        make(chan *dapRequest, 16),
+		breakpoints: map[string][]int{},
+	}
+	go s.read()
+	return s
+}
+
+// Close notifies the client that the program has exited with exitCode,
+// and closes the connection.
+func (s *DAPServer) Close(exitCode int) error {
+	if s.closed {
+		return nil
+	}
+	s.event("exited", map[string]any{"exitCode": exitCode})
+	s.event("terminated", nil)
+	s.closed = true
+	return s.conn.Close()
+}
+
+// EnableDAP makes the debugger d active, using the DAP server s for IO
+// and f as a source helper.
+func (d *Debugger) EnableDAP(s *DAPServer, f func(string, string) string) {
+	d.Enable(nil, io.Discard, f)
+	d.dap = s
+	// Breakpoints may already be known from a previous machine.
+	d.breakpoints = s.locations()
+}
+
+// read decodes the client requests and queues them, until the connection
+// is closed.
+func (s *DAPServer) read() {
+	defer close(s.reqs)
+	r := bufio.NewReader(s.conn)
+	for {
+		req, err := readDAPMessage(r)
+		if err != nil {
+			return
+		}
+		s.reqs <- req
+	}
+}
+
+// readDAPMessage reads a DAP message: a set of headers, including
+// Content-Length, followed by an empty line and a JSON content.
+func readDAPMessage(r *bufio.Reader) (*dapRequest, error) {
+	n := -1
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+		if line == "" {
+			if n >= 0 {
+				break
+			}
+			continue
+		}
+		if v, ok := strings.CutPrefix(line, "Content-Length:"); ok {
+			if n, err = strconv.Atoi(strings.TrimSpace(v)); err != nil {
+				return nil, fmt.Errorf("invalid DAP header: %q", line)
+			}
+		}
+	}
+	buf := make([]byte, n)
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	req := &dapRequest{}
+	if err := json.Unmarshal(buf, req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+func (s *DAPServer) send(msg any) {
+	if s.closed {
+		return
+	}
+	b, err := json.Marshal(msg)
+	if err != nil {
+		panic(err)
+	}
+	// Write errors are detected by the reader, which then detaches the debugger.
+	fmt.Fprintf(s.conn, "Content-Length: %d\r\n\r\n%s", len(b), b)
+}
+
+func (s *DAPServer) respond(req *dapRequest, body any) {
+	s.seq++
+	s.send(&dapResponse{Seq: s.seq, Type: "response", RequestSeq: req.Seq, Success: true, Command: req.Command, Body: body})
+}
+
+func (s *DAPServer) fail(req *dapRequest, err error) {
+	s.seq++
+	s.send(&dapResponse{Seq: s.seq, Type: "response", RequestSeq: req.Seq, Command: req.Command, Message: err.Error()})
+}
+
+func (s *DAPServer) event(name string, body any) {
+	s.seq++
+	s.send(&dapEvent{Seq: s.seq, Type: "event", Event: name, Body: body})
+}
+
+// stopped notifies the client that the program has stopped, and invalidates
+// previous variable references.
+func (s *DAPServer) stopped(reason string) {
+	s.vars = s.vars[:0]
+	s.event("stopped", map[string]any{"reason": reason, "threadId": dapThreadID, "allThreadsStopped": true})
+}
+
+// init processes the client configuration requests, until the
+// configurationDone request, then starts or stops the program.
+func (s *DAPServer) init(m *Machine) {
+	for !s.configured {
+		req, ok := <-s.reqs
+		if !ok {
+			debugDetach(m, "")
+			return
+		}
+		s.handle(m, req)
+	}
+	if s.stopOnEntry {
+		m.Debugger.state = DebugAtCmd
+		s.stopped("entry")
+		return
+	}
+	m.Debugger.lastCmd = "continue"
+	debugContinue(m, "")
+}
+
+// serve processes client requests while the program is stopped, until
+// one of them resumes it.
+func (s *DAPServer) serve(m *Machine) {
+	req, ok := <-s.reqs
+	if !ok {
+		debugDetach(m, "") // Clean close of debugger, the target program resumes.
+		return
+	}
+	s.handle(m, req)
+}
+
+// poll processes the pending client requests while the program is running,
+// without blocking. It returns true if the program has been stopped.
+func (s *DAPServer) poll(m *Machine) bool {
+	for {
+		select {
+		case req, ok := <-s.reqs:
+			if !ok {
+				debugDetach(m, "")
+				return true
+			}
+			s.handle(m, req)
+			if m.Debugger.state != DebugAtRun {
+				return true
+			}
+		default:
+			return false
+		}
+	}
+}
+
+func (s *DAPServer) handle(m *Machine, req *dapRequest) {
+	if err := s.dispatch(m, req); err != nil {
+		s.fail(req, err)
+	}
+}
+
+func (s *DAPServer) dispatch(m *Machine, req *dapRequest) error {
+	running := m.Debugger.state == DebugAtRun
+	switch req.Command {
+	case "initialize":
+		s.respond(req, map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsTerminateRequest":         true,
+			"supportsEvaluateForHovers":        true,
+		})
+		s.event("initialized", nil)
+	case "launch", "attach":
+		// The program is already started: launch is the same as attach.
+		var args struct {
+			StopOnEntry bool `json:"stopOnEntry"`
+		}
+		if len(req.Arguments) > 0 {
+			if err := json.Unmarshal(req.Arguments, &args); err != nil {
+				return err
+			}
+		}
+		s.stopOnEntry = args.StopOnEntry
+		s.respond(req, nil)
+	case "setBreakpoints":
+		var args struct {
+			Source      dapSource `json:"source"`
+			Breakpoints []struct {
+				Line int `json:"line"`
+			} `json:"breakpoints"`
+			Lines []int `json:"lines"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return err
+		}
+		lines := args.Lines
+		if args.Breakpoints != nil {
+			lines = lines[:0]
+			for _, b := range args.Breakpoints {
+				lines = append(lines, b.Line)
+			}
+		}
+		if args.Source.Path == "" {
+			return errors.New("breakpoints require a source path")
+		}
+		s.breakpoints[args.Source.Path] = lines
+		m.Debugger.breakpoints = s.locations()
+		bps := make([]dapBreakpoint, len(lines))
+		for i, line := range lines {
+			bps[i] = dapBreakpoint{Verified: true, Line: line}
+		}
+		s.respond(req, map[string]any{"breakpoints": bps})
+	case "setExceptionBreakpoints", "setFunctionBreakpoints":
+		s.respond(req, map[string]any{"breakpoints": []dapBreakpoint{}})
+	case "configurationDone":
+		s.configured = true
+		s.respond(req, nil)
+	case "threads":
+		s.respond(req, map[string]any{"threads": []map[string]any{{"id": dapThreadID, "name": "main"}}})
+	case "stackTrace":
+		s.respond(req, map[string]any{"stackFrames": s.stackFrames(m)})
+	case "scopes":
+		var args struct {
+			FrameID int `json:"frameId"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return err
+		}
+		s.respond(req, map[string]any{"scopes": []dapScope{
+			{Name: "Locals", VariablesReference: s.ref(dapVarRef{level: args.FrameID, locals: true})},
+			{Name: "Globals", VariablesReference: s.ref(dapVarRef{level: args.FrameID, globals: true}), Expensive: true},
+		}})
+	case "variables":
+		var args struct {
+			VariablesReference int `json:"variablesReference"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return err
+		}
+		if args.VariablesReference < 1 || args.VariablesReference > len(s.vars) {
+			return errors.New("invalid variables reference")
+		}
+		s.respond(req, map[string]any{"variables": s.variables(m, s.vars[args.VariablesReference-1])})
+	case "evaluate":
+		var args struct {
+			Expression string `json:"expression"`
+			FrameID    *int   `json:"frameId"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return err
+		}
+		if running {
+			return errors.New("program is running")
+		}
+		if args.FrameID != nil {
+			m.Debugger.frameLevel = *args.FrameID
+		}
+		expr, err := parser.ParseExpr(args.Expression)
+		if err != nil {
+			return err
+		}
+		tv, err := debugEvalExpr(m, expr)
+		if err != nil {
+			return err
+		}
+		v := s.variable(m, "", tv)
+		s.respond(req, map[string]any{"result": v.Value, "type": v.Type, "variablesReference": v.VariablesReference})
+	case "source":
+		var args struct {
+			SourceReference int `json:"sourceReference"`
+		}
+		if err := json.Unmarshal(req.Arguments, &args); err != nil {
+			return err
+		}
+		if args.SourceReference < 1 || args.SourceReference > len(s.sources) {
+			return errors.New("invalid source reference")
+		}
+		content, err := debugSource(m, s.sources[args.SourceReference-1])
+		if err != nil {
+			return err
+		}
+		s.respond(req, map[string]any{"content": content})
+	case "continue", "next", "stepIn", "stepOut":
+		if !running {
+			m.Debugger.lastCmd = map[string]string{"continue": "continue", "next": "next", "stepIn": "step", "stepOut": "stepout"}[req.Command]
+			debugContinue(m, "")
+		}
+		if req.Command == "continue" {
+			s.respond(req, map[string]any{"allThreadsContinued": true})
+		} else {
+			s.respond(req, nil)
+		}
+	case "pause":
+		s.respond(req, nil)
+		if running {
+			m.Debugger.state = DebugAtCmd
+			m.Debugger.prevLoc = m.Debugger.loc
+			s.stopped("pause")
+		}
+	case "disconnect":
+		var args struct {
+			TerminateDebuggee bool `json:"terminateDebuggee"`
+		}
+		if len(req.Arguments) > 0 {
+			if err := json.Unmarshal(req.Arguments, &args); err != nil {
+				return err
+			}
+		}
+		s.respond(req, nil)
+		if args.TerminateDebuggee {
+			m.Debugger.state = DebugAtExit
+		} else {
+			debugDetach(m, "")
+			s.closed = true
+			s.conn.Close()
+		}
+	case "terminate":
+		s.respond(req, nil)
+		m.Debugger.state = DebugAtExit
+	default:
+		return fmt.Errorf("unsupported request: %s", req.Command)
+	}
+	return nil
+}
+
+// locations returns the debugger breakpoint locations from client source paths.
+func (s *DAPServer) locations() []Location {
+	paths := make([]string, 0, len(s.breakpoints))
+	for p := range s.breakpoints {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	var locs []Location
+	for _, p := range paths {
+		loc := Location{File: p}
+		dir := filepath.Dir(p)
+		for pkgPath, d := range s.Dirs {
+			if d, err := filepath.Abs(d); err == nil && d == dir {
+				loc = Location{PkgPath: pkgPath, File: filepath.Base(p)}
+				break
+			}
+		}
+		for _, line := range s.breakpoints[p] {
+			loc.Line = line
+			locs = append(locs, loc)
+		}
+	}
+	return locs
+}
+
+// ref returns a new variables reference to r.
+func (s *DAPServer) ref(r dapVarRef) int {
+	s.vars = append(s.vars, r)
+	return len(s.vars)
+}
+
+// source returns the DAP source of loc, either as a local path, or as a
+// source reference if the file is not on the local filesystem.
+func (s *DAPServer) source(m *Machine, loc Location) *dapSource {
+	if loc.File == "" {
+		return nil
+	}
+	if p := m.Debugger.sourcePath(loc); p != "" {
+		return &dapSource{Name: filepath.Base(p), Path: p}
+	}
+	loc = Location{PkgPath: loc.PkgPath, File: loc.File}
+	for i, l := range s.sources {
+		if l == loc {
+			return &dapSource{Name: loc.File, SourceReference: i + 1, Origin: loc.PkgPath}
+		}
+	}
+	s.sources = append(s.sources, loc)
+	return &dapSource{Name: loc.File, SourceReference: len(s.sources), Origin: loc.PkgPath}
+}
+
+// debugSource returns the source content of the file at loc.
+func debugSource(m *Machine, loc Location) (string, error) {
+	if m.Debugger.getSrc != nil {
+		if src := m.Debugger.getSrc(loc.PkgPath, loc.File); src != "" {
+			return src, nil
+		}
+	}
+	return fileContent(m.Store, loc.PkgPath, loc.File)
+}
+
+func (s *DAPServer) stackFrames(m *Machine) []dapStackFrame {
+	var frames []dapStackFrame
+	for i := 0; ; i++ {
+		f := debugCallFrame(m, i)
+		if f == nil {
+			break
+		}
+		ff := f.Func
+		loc := debugFrameLoc(m, i)
+		var name string
+		if ff.IsMethod {
+			name = fmt.Sprintf("%v.(%v).%v", ff.PkgPath, ff.Type.(*FuncType).Params[0].Type, ff.Name)
+		} else {
+			name = fmt.Sprintf("%v.%v", ff.PkgPath, ff.Name)
+		}
+		if f.WithCross {
+			name += " (crossing)"
+		}
+		frames = append(frames, dapStackFrame{ID: i, Name: name, Source: s.source(m, loc), Line: loc.Line, Column: max(loc.Column, 1)})
+	}
+	if len(frames) == 0 {
+		// Not in a function, i.e. package initialization.
+		loc := m.Debugger.loc
+		frames = append(frames, dapStackFrame{Name: loc.PkgPath, Source: s.source(m, loc), Line: loc.Line, Column: max(loc.Column, 1)})
+	}
+	return frames
+}
+
+// variables returns the children of variables reference r.
+func (s *DAPServer) variables(m *Machine, r dapVarRef) []dapVariable {
+	vars := []dapVariable{}
+	switch {
+	case r.locals:
+		saved := m.Debugger.frameLevel
+		m.Debugger.frameLevel = r.level
+		sblocks := debugFrameBlocks(m)
+		m.Debugger.frameLevel = saved
+		seen := map[string]bool{}
+		for i, b := range sblocks {
+			if i > 0 && i == len(sblocks)-1 && b == m.Blocks[0] {
+				break // global block
+			}
+			if _, ok := b.Source.(*FileNode); ok {
+				break
+			}
+			names := b.Source.GetBlockNames()
+			if t, ok := b.Source.(*IfStmt); ok {
+				names = ifBody(m, t).Source.GetBlockNames()
+			}
+			for j, n := range names {
+				if j >= len(b.Values) || !dapVisibleName(n) || seen[string(n)] {
+					continue
+				}
+				seen[string(n)] = true
+				vars = append(vars, s.variable(m, string(n), b.Values[j]))
+			}
+		}
+	case r.globals:
+		if m.Package == nil {
+			break
+		}
+		b := m.Package.GetBlock(m.Store)
+		for j, n := range b.Source.GetBlockNames() {
+			if j >= len(b.Values) || !dapVisibleName(n) {
+				continue
+			}
+			tv := fillValueTV(m.Store, &b.Values[j])
+			if tv.T == nil {
+				continue
+			}
+			switch tv.T.Kind() {
+			case TypeKind, PackageKind:
+				continue
+			case FuncKind:
+				if _, ok := tv.V.(*FuncValue); ok {
+					continue // function declaration
+				}
+			}
+			vars = append(vars, s.variable(m, string(n), *tv))
+		}
+	default:
+		s.children(m, r.tv, func(name string, tv TypedValue) {
+			vars = append(vars, s.variable(m, name, tv))
+		})
+	}
+	return vars
+}
+
+// dapVisibleName returns true if a block name should be reported as a variable.
+func dapVisibleName(n Name) bool {
+	return n != "" && n != blankIdentifier && !strings.HasPrefix(string(n), ".")
+}
+
+// variable returns the DAP variable for value tv, with a reference
+// to its children if any.
+func (s *DAPServer) variable(m *Machine, name string, tv TypedValue) (v dapVariable) {
+	if hiv, ok := tv.V.(*HeapItemValue); ok {
+		tv = hiv.Value
+	}
+	v.Name = name
+	if tv.T == nil {
+		v.Value = "nil"
+		return v
+	}
+	v.Type = tv.T.String()
+	v.Value = dapValueString(tv)
+	if dapHasChildren(tv) {
+		v.VariablesReference = s.ref(dapVarRef{tv: tv})
+	}
+	return v
+}
+
+// dapHasChildren returns true if value tv can be expanded by the client.
+func dapHasChildren(tv TypedValue) bool {
+	if tv.V == nil {
+		return false
+	}
+	switch baseOf(tv.T).(type) {
+	case *StructType, *PointerType:
+		return true
+	case *ArrayType, *SliceType, *MapType:
+		return tv.GetLength() > 0
+	}
+	return false
+}
+
+// dapValueString returns the printed value of tv, truncated if too long.
+func dapValueString(tv TypedValue) (str string) {
+	defer func() {
+		if r := recover(); r != nil {
+			str = fmt.Sprintf("<error: %v>", r)
+		}
+	}()
+	if bt, ok := baseOf(tv.T).(PrimitiveType); ok && (bt == StringType || bt == UntypedStringType) {
+		str = strconv.Quote(tv.GetString())
+	} else {
+		str = tv.ProtectedSprint(newSeenValues(), false)
+	}
+	if len(str) > dapMaxValueSize {
+		str = str[:dapMaxValueSize] + "..."
+	}
+	return str
+}
+
+// children calls f for each child of value tv: struct fields, array, slice
+// and map elements, or pointed value.
+func (s *DAPServer) children(m *Machine, tv TypedValue, f func(string, TypedValue)) {
+	defer func() { recover() }() // values which can't be inspected have no children
+	if tv.V == nil {
+		return
+	}
+	switch bt := baseOf(tv.T).(type) {
+	case *StructType:
+		sv := fillValueTV(m.Store, &tv).V.(*StructValue)
+		for i, fd := range bt.Fields {
+			f(string(fd.Name), *fillValueTV(m.Store, &sv.Fields[i]))
+		}
+	case *ArrayType, *SliceType:
+		n := min(tv.GetLength(), dapMaxChildren)
+		for i := range n {
+			f("["+strconv.Itoa(i)+"]", tv.GetPointerAtIndexInt(m.Store, i).Deref())
+		}
+	case *MapType:
+		mv := fillValueTV(m.Store, &tv).V.(*MapValue)
+		n := 0
+		for item := mv.List.Head; item != nil && n < dapMaxChildren; item = item.Next {
+			f("["+dapValueString(item.Key)+"]", *fillValueTV(m.Store, &item.Value))
+			n++
+		}
+	case *PointerType:
+		pv, ok := tv.V.(PointerValue)
+		if !ok {
+			return
+		}
+		dv := pv.Deref()
+		if _, ok := baseOf(dv.T).(*StructType); ok {
+			s.children(m, dv, f)
+			return
+		}
+		f("*", dv)
+	}
+}
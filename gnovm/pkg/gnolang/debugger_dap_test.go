@@ -0,0 +1,222 @@
+package gnolang_test
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/gnovm/pkg/gnoenv"
+	"github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/gnovm/pkg/test"
+)
+
+// dapClient is a minimal Debug Adapter Protocol client.
+type dapClient struct {
+	t    *testing.T
+	conn net.Conn
+	seq  int
+	msgs chan map[string]any
+}
+
+func newDAPClient(t *testing.T, conn net.Conn) *dapClient {
+	t.Helper()
+	c := &dapClient{t: t, conn: conn, msgs: make(chan map[string]any, 16)}
+	go func() {
+		defer close(c.msgs)
+		r := bufio.NewReader(conn)
+		for {
+			var n int
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				line = strings.TrimSpace(line)
+				if line == "" {
+					break
+				}
+				if v, ok := strings.CutPrefix(line, "Content-Length:"); ok {
+					n, _ = strconv.Atoi(strings.TrimSpace(v))
+				}
+			}
+			buf := make([]byte, n)
+			if _, err := io.ReadFull(r, buf); err != nil {
+				return
+			}
+			msg := map[string]any{}
+			if err := json.Unmarshal(buf, &msg); err != nil {
+				return
+			}
+			c.msgs <- msg
+		}
+	}()
+	return c
+}
+
+// request sends a request and returns the body of its response.
+func (c *dapClient) request(command string, args any) map[string]any {
+	c.t.Helper()
+	c.seq++
+	b, err := json.Marshal(map[string]any{"seq": c.seq, "type": "request", "command": command, "arguments": args})
+	require.NoError(c.t, err)
+	_, err = fmt.Fprintf(c.conn, "Content-Length: %d\r\n\r\n%s", len(b), b)
+	require.NoError(c.t, err)
+	resp := c.wait("response", command)
+	require.Equal(c.t, true, resp["success"], "%s failed: %v", command, resp["message"])
+	body, _ := resp["body"].(map[string]any)
+	return body
+}
+
+// wait returns the next response or event with the given name, skipping
+// other messages.
+func (c *dapClient) wait(typ, name string) map[string]any {
+	c.t.Helper()
+	key := map[string]string{"response": "command", "event": "event"}[typ]
+	timeout := time.After(10 * time.Second)
+	for {
+		select {
+		case msg, ok := <-c.msgs:
+			require.True(c.t, ok, "connection closed while waiting for %s %s", typ, name)
+			if msg["type"] == typ && msg[key] == name {
+				return msg
+			}
+		case <-timeout:
+			c.t.Fatalf("timeout waiting for %s %s", typ, name)
+		}
+	}
+}
+
+// variables returns the values of variables at ref, by name.
+func (c *dapClient) variables(ref any) map[string]map[string]any {
+	c.t.Helper()
+	body := c.request("variables", map[string]any{"variablesReference": ref})
+	vars := map[string]map[string]any{}
+	for _, v := range body["variables"].([]any) {
+		v := v.(map[string]any)
+		vars[v["name"].(string)] = v
+	}
+	return vars
+}
+
+func TestDAP(t *testing.T) {
+	serverConn, clientConn := net.Pipe()
+	defer clientConn.Close()
+
+	target, err := filepath.Abs(debugTarget)
+	require.NoError(t, err)
+
+	bout := bytes.NewBufferString("")
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		output := test.OutputWithError(writeNopCloser{bout}, writeNopCloser{bout})
+		_, testStore := test.TestStore(gnoenv.RootDir(), output, nil)
+		f := gnolang.MustReadFile(debugTarget)
+		m := gnolang.NewMachineWithOptions(gnolang.MachineOptions{
+			PkgPath: string(f.PkgName),
+			Output:  output,
+			Store:   testStore,
+			Context: test.Context(test.DefaultCaller, string(f.PkgName), nil),
+			Debug:   true,
+		})
+		defer m.Release()
+		dap := gnolang.NewDAPServer(serverConn)
+		m.Debugger.EnableDAP(dap, nil)
+		m.RunFiles(f)
+		ex, _ := gnolang.ParseExpr("main()")
+		m.Eval(ex)
+		dap.Close(0)
+	}()
+
+	c := newDAPClient(t, clientConn)
+
+	// Configuration sequence.
+	body := c.request("initialize", map[string]any{"adapterID": "gno"})
+	assert.Equal(t, true, body["supportsConfigurationDoneRequest"])
+	c.wait("event", "initialized")
+	c.request("launch", map[string]any{})
+	body = c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []map[string]any{{"line": 7}},
+	})
+	assert.Len(t, body["breakpoints"], 1)
+	c.request("configurationDone", nil)
+
+	// Stop at breakpoint in f, called by g.
+	ev := c.wait("event", "stopped")
+	assert.Equal(t, "breakpoint", ev["body"].(map[string]any)["reason"])
+
+	body = c.request("threads", nil)
+	assert.Len(t, body["threads"], 1)
+
+	body = c.request("stackTrace", map[string]any{"threadId": 1})
+	frames := body["stackFrames"].([]any)
+	require.Len(t, frames, 3)
+	var names []string
+	var lines []float64
+	for _, f := range frames {
+		f := f.(map[string]any)
+		names = append(names, f["name"].(string))
+		lines = append(lines, f["line"].(float64))
+		assert.Equal(t, target, f["source"].(map[string]any)["path"])
+	}
+	assert.Equal(t, []string{"main.f", "main.g", "main.main"}, names)
+	assert.Equal(t, []float64{7, 11, 37}, lines)
+
+	body = c.request("scopes", map[string]any{"frameId": 0})
+	scopes := body["scopes"].([]any)
+	require.Len(t, scopes, 2)
+	locals := c.variables(scopes[0].(map[string]any)["variablesReference"])
+	assert.Equal(t, `"hello"`, locals["name"]["value"])
+	assert.Equal(t, "3", locals["i"]["value"])
+	assert.Equal(t, "int", locals["i"]["type"])
+	globals := c.variables(scopes[1].(map[string]any)["variablesReference"])
+	assert.Equal(t, `"test"`, globals["global"]["value"])
+	assert.NotContains(t, globals, "main")
+
+	body = c.request("evaluate", map[string]any{"expression": "s", "frameId": 1})
+	assert.Equal(t, `"hello"`, body["result"])
+
+	// Next breakpoint, in a method with a pointer receiver.
+	c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []map[string]any{{"line": 21}},
+	})
+	c.request("continue", map[string]any{"threadId": 1})
+	c.wait("event", "stopped")
+	body = c.request("scopes", map[string]any{"frameId": 0})
+	locals = c.variables(body["scopes"].([]any)[0].(map[string]any)["variablesReference"])
+	require.Contains(t, locals, "t")
+	fields := c.variables(locals["t"]["variablesReference"])
+	require.Contains(t, fields, "A")
+	elems := c.variables(fields["A"]["variablesReference"])
+	assert.Equal(t, "2", elems["[1]"]["value"])
+
+	// Step to the next line.
+	c.request("next", map[string]any{"threadId": 1})
+	ev = c.wait("event", "stopped")
+	assert.Equal(t, "step", ev["body"].(map[string]any)["reason"])
+
+	// Run to completion.
+	c.request("setBreakpoints", map[string]any{
+		"source":      map[string]any{"path": target},
+		"breakpoints": []map[string]any{},
+	})
+	c.request("continue", map[string]any{"threadId": 1})
+	ev = c.wait("event", "exited")
+	assert.Equal(t, float64(0), ev["body"].(map[string]any)["exitCode"])
+	c.wait("event", "terminated")
+	<-done
+	assert.Contains(t, bout.String(), "hello 3")
+}
@@ -80,6 +80,14 @@ func (m *Machine) GarbageCollect() (left int64, ok bool) {
 		}
 	}
 
+	// Visit parked goroutines
+	if m.sched != nil {
+		stop := m.sched.visitParked(m.Alloc, vis)
+		if stop {
+			return -1, false
+		}
+	}
+
 	// Visit package
 	stop := vis(m.Package)
 	if stop {
@@ -290,6 +298,35 @@ func (mv *MapValue) VisitAssociated(vis Visitor) (stop bool) {
 	return
 }
 
+func (cv *ChanValue) VisitAssociated(vis Visitor) (stop bool) {
+	// channels are not objects; break cycles here.
+	if cv.visiting {
+		return
+	}
+	cv.visiting = true
+	defer func() { cv.visiting = false }()
+
+	// visit buffered values.
+	for _, tv := range cv.Buffer {
+		if tv.V != nil {
+			stop = vis(tv.V)
+		}
+		if stop {
+			return
+		}
+	}
+	// visit values of parked senders.
+	for _, w := range cv.sendq {
+		if w.isValid() && w.value.V != nil {
+			stop = vis(w.value.V)
+		}
+		if stop {
+			return
+		}
+	}
+	return
+}
+
 func (pv *PackageValue) VisitAssociated(vis Visitor) (stop bool) {
 	if pv.PkgPath == ".uverse" {
 		return false
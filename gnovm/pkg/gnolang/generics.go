@@ -0,0 +1,958 @@
+package gnolang
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Generic functions and types are implemented by monomorphization, during
+// preprocessing.
+//
+// Generic declarations are templates: they are neither predefined nor
+// preprocessed, and [Transcribe] skips them when walking a *FileNode.
+// Each instantiation (e.g. Map[int, string], or a call to a generic function
+// with inferred type arguments) creates a copy of the template, where the
+// type parameters are replaced by the type arguments. The copy is then
+// preprocessed like any other declaration, in the file of the template.
+//
+// Instances are identified by the TypeIDs of their type arguments, which are
+// stable across restarts:
+//   - function instances are *FuncDecl block nodes, located at the span of
+//     the template with Location.Inst set (e.g. "[int,string]");
+//   - type instances are *DeclaredTypes named after the template and the type
+//     arguments (e.g. "List[int]"), which are saved to the store like other
+//     types. Their methods are instances of the methods of the template.
+//
+// Instances belong to the package of their template, whichever package
+// instantiates them, so that the instances of all the packages are
+// identical; e.g. p.List[r.Foo] is saved under the path of p, by the
+// transaction which first creates it, and paid by its caller. Their type
+// arguments are saved to the store as a tuple type, whose TypeID is derived
+// from Location.Inst (e.g. "(int,string)"): the block nodes of an instance,
+// which are not saved, can then be recreated from its location alone, even
+// if the package which instantiated it (e.g. a MsgRun) is not preprocessed
+// again after a restart; see restoreInstance.
+//
+// Type checking of generic code is left to go/types; the preprocessor only
+// checks that type arguments satisfy their constraints.
+
+// genericRef refers to a generic declaration, which has yet to be
+// instantiated. It is set as ATTR_GENERIC on the *ConstExpr which replaces
+// the name of a generic declaration; see genericMarker.
+type genericRef struct {
+	decl  Decl         // *FuncDecl or *TypeDecl
+	pn    *PackageNode // package of decl
+	fn    *FileNode    // file of decl
+	targs []Type       // explicit type arguments, if partially instantiated
+}
+
+func (ref *genericRef) name() Name {
+	switch d := ref.decl.(type) {
+	case *FuncDecl:
+		return d.Name
+	case *TypeDecl:
+		return d.Name
+	default:
+		panic("should not happen")
+	}
+}
+
+func (ref *genericRef) typeParams() FieldTypeExprs {
+	switch d := ref.decl.(type) {
+	case *FuncDecl:
+		return d.TypeParams
+	case *TypeDecl:
+		return d.TypeParams
+	default:
+		panic("should not happen")
+	}
+}
+
+// isGenericDecl returns whether d is a generic declaration: a function or
+// type with type parameters, a method of a generic type, or an interface
+// which can only be used as a type constraint.
+func isGenericDecl(d Decl) bool {
+	switch d := d.(type) {
+	case *FuncDecl:
+		if len(d.TypeParams) > 0 {
+			return true
+		}
+		if d.IsMethod {
+			_, tparams := recvTypeParams(&d.Recv)
+			return tparams != nil
+		}
+	case *TypeDecl:
+		return len(d.TypeParams) > 0 || isConstraintInterface(d.Type)
+	}
+	return false
+}
+
+// recvTypeParams returns the base type name and the type parameters of a
+// method receiver like `l *List[T]`. tparams is nil if the receiver is not a
+// generic type.
+func recvTypeParams(recv *FieldTypeExpr) (base Name, tparams []*NameExpr) {
+	x := recv.Type
+	if sx, ok := x.(*StarExpr); ok {
+		x = sx.X
+	}
+	var bx Expr
+	var idxs Exprs
+	switch cx := x.(type) {
+	case *NameExpr:
+		return cx.Name, nil
+	case *IndexExpr:
+		bx, idxs = cx.X, Exprs{cx.Index}
+	case *IndexListExpr:
+		bx, idxs = cx.X, cx.Indices
+	default:
+		return "", nil
+	}
+	nx, ok := bx.(*NameExpr)
+	if !ok {
+		return "", nil
+	}
+	for _, idx := range idxs {
+		tp, ok := idx.(*NameExpr)
+		if !ok {
+			// e.g. an instance of a generic method.
+			return nx.Name, nil
+		}
+		tparams = append(tparams, tp)
+	}
+	return nx.Name, tparams
+}
+
+// isConstraintInterface returns whether x is an interface with a type set,
+// which can only be used as a type constraint; e.g. `interface{ ~int | ~uint }`.
+func isConstraintInterface(x Expr) bool {
+	it, ok := x.(*InterfaceTypeExpr)
+	if !ok {
+		return false
+	}
+	for _, m := range it.Methods {
+		if m.Name != "" {
+			continue
+		}
+		switch mx := m.Type.(type) {
+		case *BinaryExpr:
+			if mx.Op == BOR {
+				return true
+			}
+		case *UnaryExpr:
+			if mx.Op == TILDE {
+				return true
+			}
+		case *NameExpr:
+			if mx.Name == "comparable" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// lookupGeneric returns the generic declaration which name refers to from
+// last, or nil if name does not refer to a generic declaration.
+func lookupGeneric(last BlockNode, name Name) *genericRef {
+	for bn := last; bn != nil; bn = bn.GetParentNode(nil) {
+		if _, ok := bn.GetLocalIndex(name); ok {
+			if pn, ok := bn.(*PackageNode); ok {
+				return packageGenericRef(pn, name)
+			}
+			return nil // shadowed.
+		}
+	}
+	return nil
+}
+
+// packageGenericRef returns the generic declaration of pn named name, or nil
+// if there is none.
+func packageGenericRef(pn *PackageNode, name Name) *genericRef {
+	idx, ok := pn.GetLocalIndex(name)
+	if !ok || pn.Types[idx] != nil || pn.FileSet == nil {
+		// generic declarations are reserved, but never defined.
+		return nil
+	}
+	fn, d, ok := pn.FileSet.GetDeclForSafe(name)
+	if !ok || !isGenericDecl(*d) {
+		return nil
+	}
+	return &genericRef{decl: *d, pn: pn, fn: fn}
+}
+
+// genericRefOf returns the generic declaration x refers to, if x is a
+// marker returned by genericMarker.
+func genericRefOf(x Expr) *genericRef {
+	if cx, ok := x.(*ConstExpr); ok {
+		ref, _ := cx.GetAttribute(ATTR_GENERIC).(*genericRef)
+		return ref
+	}
+	return nil
+}
+
+// genericMarker returns the *ConstExpr which replaces x, a reference to a
+// generic declaration, until its parent instantiates it: it must be either
+// indexed with type arguments, or called with inferred type arguments.
+func genericMarker(x Expr, ftype TransField, ref *genericRef) *ConstExpr {
+	if td, ok := ref.decl.(*TypeDecl); ok && len(td.TypeParams) == 0 {
+		panic(fmt.Sprintf(
+			"cannot use type %s outside a type constraint: interface contains type constraints",
+			genericName(x)))
+	}
+	switch ftype {
+	case TRANS_CALL_FUNC, TRANS_INDEX_X, TRANS_INDEXLIST_X:
+	default:
+		panic(fmt.Sprintf(
+			"cannot use generic %s %s without instantiation",
+			genericKind(ref.decl), genericName(x)))
+	}
+	cx := &ConstExpr{Source: x}
+	cx.SetSpan(x.GetSpan())
+	cx.SetAttribute(ATTR_GENERIC, ref)
+	setPreprocessed(cx)
+	return cx
+}
+
+// genericName returns x as written in the source, for error messages.
+func genericName(x Expr) string {
+	switch cx := x.(type) {
+	case *NameExpr:
+		return string(cx.Name)
+	case *SelectorExpr:
+		return genericName(cx.X) + "." + string(cx.Sel)
+	case *ConstExpr:
+		if cx.Source != nil {
+			return genericName(cx.Source)
+		}
+	case *IndexExpr:
+		return genericName(cx.X) + "[...]"
+	case *IndexListExpr:
+		return genericName(cx.X) + "[...]"
+	}
+	return x.String()
+}
+
+func genericKind(d Decl) string {
+	if _, ok := d.(*TypeDecl); ok {
+		return "type"
+	}
+	return "function"
+}
+
+// instantiateExpr instantiates the generic declaration ref with the type
+// arguments idxs, as in x: `ref[idxs...]`. It returns the expression which
+// replaces x.
+func instantiateExpr(store Store, last BlockNode, x Expr, ftype TransField, ref *genericRef, idxs Exprs) Expr {
+	tparams := ref.typeParams()
+	if len(ref.targs) > 0 {
+		panic(fmt.Sprintf("invalid operation: cannot index %s", genericName(x)))
+	}
+	if len(idxs) > len(tparams) {
+		panic(fmt.Sprintf(
+			"got %d type arguments but %s has %d type parameters",
+			len(idxs), ref.name(), len(tparams)))
+	}
+	targs := make([]Type, len(idxs))
+	for i, idx := range idxs {
+		targs[i] = evalStaticType(store, last, idx)
+	}
+	if len(targs) < len(tparams) {
+		if _, ok := ref.decl.(*FuncDecl); ok && ftype == TRANS_CALL_FUNC {
+			// the remaining type arguments are inferred by the call.
+			ref2 := *ref
+			ref2.targs = targs
+			return genericMarker(x, ftype, &ref2)
+		}
+		panic(fmt.Sprintf(
+			"not enough type arguments for %s %s: have %d, want %d",
+			genericKind(ref.decl), ref.name(), len(targs), len(tparams)))
+	}
+	switch ref.decl.(type) {
+	case *FuncDecl:
+		fv := instantiateFunc(store, ref, targs)
+		return toConstExpr(x, TypedValue{T: fv.Type, V: fv})
+	case *TypeDecl:
+		dt := instantiateType(store, ref, targs)
+		// see the deferred TRANS_COMPOSITE_TYPE handler.
+		x.SetAttribute(ATTR_TYPE_VALUE, dt)
+		return toConstTypeExpr(last, x, dt)
+	default:
+		panic("should not happen")
+	}
+}
+
+// instanceSuffix returns the Location.Inst of an instance; e.g. "[int,string]".
+func instanceSuffix(targs []Type) string {
+	ids := make([]string, len(targs))
+	for i, targ := range targs {
+		ids[i] = string(targ.TypeID())
+	}
+	return "[" + strings.Join(ids, ",") + "]"
+}
+
+// instantiateFunc returns the instance of the generic function ref with
+// targs, creating it if it doesn't exist yet.
+func instantiateFunc(store Store, ref *genericRef, targs []Type) *FuncValue {
+	tpl := ref.decl.(*FuncDecl)
+	checkTypeArgs(store, ref.fn, tpl.TypeParams, targs)
+	inst := instanceSuffix(targs)
+	loc := Location{
+		PkgPath: ref.pn.PkgPath,
+		File:    ref.fn.FileName,
+		Span:    tpl.GetSpan(),
+		Inst:    inst,
+	}
+	d, ok := store.GetBlockNodeSafe(loc).(*FuncDecl)
+	if !ok {
+		d = newFuncInstance(store, ref, targs, inst)
+	}
+	ft := getType(&d.Type).(*FuncType)
+	return &FuncValue{
+		Type:     ft,
+		Source:   d,
+		Name:     tpl.Name + Name(inst),
+		Parent:   nil, // set lazily.
+		FileName: ref.fn.FileName,
+		PkgPath:  ref.pn.PkgPath,
+		Crossing: ft.IsCrossing(),
+		// body is set lazily, as it may not be preprocessed yet.
+	}
+}
+
+// newFuncInstance creates the instance of the generic function ref with
+// targs, and saves its block nodes.
+func newFuncInstance(store Store, ref *genericRef, targs []Type, inst string) *FuncDecl {
+	tpl := ref.decl.(*FuncDecl)
+	if tpl.Body == nil {
+		panic(fmt.Sprintf("generic function %s must have a body", tpl.Name))
+	}
+	saveInstanceArgs(store, targs)
+	d := copyTemplate(tpl).(*FuncDecl)
+	d.TypeParams = nil
+	d.SetAttribute(ATTR_GENERIC_INSTANCE, targs)
+	substTypeParams(ref.fn, d, typeParamsMap(tpl.TypeParams, targs))
+	setNodeLines(d)
+	setInstanceLocations(ref.pn.PkgPath, ref.fn.FileName, inst, d)
+	initStaticBlocks(store, ref.fn, d)
+	predefineDeps(store, ref.fn, &d.Type)
+	d.Type = *Preprocess(store, ref.fn, &d.Type).(*FuncTypeExpr)
+	evalStaticType(store, ref.fn, &d.Type)
+	d.SetAttribute(ATTR_PREDEFINED, true)
+	// save before the body, for recursive functions.
+	store.SetBlockNode(d)
+	preprocessInstanceBody(store, ref.fn, d)
+	return d
+}
+
+// saveInstanceArgs saves the type arguments of an instance to the store, as
+// a tuple type, unless they already are.
+func saveInstanceArgs(store Store, targs []Type) {
+	tt := &tupleType{Elts: targs}
+	if store.GetTypeSafe(tt.TypeID()) == nil {
+		store.SetType(tt)
+	}
+}
+
+// restoreInstance recreates the instance of the generic declaration whose
+// block node is at loc, if its type arguments were saved to the store. This
+// saves all the block nodes of the instance; e.g. the ones of all the
+// methods of a type instance.
+func restoreInstance(store Store, loc Location) {
+	tid := TypeID("(" + strings.TrimSuffix(strings.TrimPrefix(loc.Inst, "["), "]") + ")")
+	tt, ok := store.GetTypeSafe(tid).(*tupleType)
+	if !ok {
+		return
+	}
+	pn, ok := store.GetBlockNodeSafe(PackageNodeLocation(loc.PkgPath)).(*PackageNode)
+	if !ok || pn.FileSet == nil {
+		return
+	}
+	fn := pn.FileSet.GetFileByName(loc.File)
+	if fn == nil {
+		return
+	}
+	for _, d := range fn.Decls {
+		span := d.GetSpan()
+		if span.Pos.Compare(loc.Span.Pos) > 0 || loc.Span.End.Compare(span.End) > 0 {
+			continue
+		}
+		fd, ok := d.(*FuncDecl)
+		if !ok || !isGenericDecl(fd) {
+			return
+		}
+		if !fd.IsMethod {
+			newFuncInstance(store, &genericRef{decl: fd, pn: pn, fn: fn}, tt.Elts, loc.Inst)
+			return
+		}
+		base, _ := recvTypeParams(&fd.Recv)
+		ref := packageGenericRef(pn, base)
+		if ref == nil {
+			return
+		}
+		dt := instantiateType(store, ref, tt.Elts)
+		if store.GetBlockNodeSafe(loc) == nil {
+			// dt was loaded by another transaction, whose method
+			// nodes were discarded.
+			defineInstanceMethods(store, ref, dt, loc.Inst, false)
+		}
+		return
+	}
+}
+
+// instantiateType returns the instance of the generic type ref with targs,
+// creating it and its methods if it doesn't exist yet.
+func instantiateType(store Store, ref *genericRef, targs []Type) *DeclaredType {
+	tpl := ref.decl.(*TypeDecl)
+	if tpl.IsAlias {
+		panic(fmt.Sprintf("generic type alias %s is not supported", tpl.Name))
+	}
+	checkTypeArgs(store, ref.fn, tpl.TypeParams, targs)
+	inst := instanceSuffix(targs)
+	name := tpl.Name + Name(inst)
+	tid := DeclaredTypeID(ref.pn.PkgPath, Location{}, name)
+	if dt, ok := store.GetTypeSafe(tid).(*DeclaredType); ok {
+		if dt.targs == nil {
+			// loaded from the store; the method nodes are missing.
+			dt.targs = targs
+			defineInstanceMethods(store, ref, dt, inst, false)
+		}
+		return dt
+	}
+	saveInstanceArgs(store, targs)
+	dt := &DeclaredType{
+		PkgPath: ref.pn.PkgPath,
+		Name:    name,
+		targs:   targs,
+	}
+	// register before evaluating the base type, for recursive types.
+	store.SetCacheType(dt)
+	tx := copyTemplate(tpl.Type).(Expr)
+	tx = substTypeParams(ref.fn, tx, typeParamsMap(tpl.TypeParams, targs)).(Expr)
+	setNodeLines(tx)
+	dt.Base = baseOf(evalTypeIn(store, ref.fn, tx))
+	dt.Seal()
+	defineInstanceMethods(store, ref, dt, inst, true)
+	store.SetType(dt)
+	return dt
+}
+
+// defineInstanceMethods instantiates the methods of the generic type ref for
+// its instance dt. If define is false, dt already has the methods (as it was
+// loaded from the store), and only their nodes are created.
+func defineInstanceMethods(store Store, ref *genericRef, dt *DeclaredType, inst string, define bool) {
+	type method struct {
+		fn *FileNode
+		d  *FuncDecl
+	}
+	var methods []method
+	for _, fn := range ref.pn.FileSet.Files {
+		for _, d := range fn.Decls {
+			md, ok := d.(*FuncDecl)
+			if !ok || !md.IsMethod {
+				continue
+			}
+			base, tparams := recvTypeParams(&md.Recv)
+			if base != ref.name() || tparams == nil {
+				continue
+			}
+			if len(tparams) != len(dt.targs) {
+				panic(fmt.Sprintf(
+					"receiver of %s.%s has %d type parameters, but %s has %d",
+					base, md.Name, len(tparams), base, len(dt.targs)))
+			}
+			names := make(FieldTypeExprs, len(tparams))
+			for i, tp := range tparams {
+				names[i].Name = tp.Name
+			}
+			d2 := copyTemplate(md).(*FuncDecl)
+			d2.SetAttribute(ATTR_GENERIC_INSTANCE, dt.targs)
+			substTypeParams(fn, d2, typeParamsMap(names, dt.targs))
+			setNodeLines(d2)
+			setInstanceLocations(ref.pn.PkgPath, fn.FileName, inst, d2)
+			initStaticBlocks(store, fn, d2)
+			store.SetBlockNode(d2)
+			methods = append(methods, method{fn, d2})
+		}
+	}
+	// Define all methods before preprocessing any body, as bodies may
+	// depend on the method set of dt.
+	for _, m := range methods {
+		d := m.d
+		predefineDeps(store, m.fn, &d.Type)
+		d.Recv = *Preprocess(store, m.fn, &d.Recv).(*FieldTypeExpr)
+		d.Type = *Preprocess(store, m.fn, &d.Type).(*FuncTypeExpr)
+		rft := evalStaticType(store, m.fn, &d.Recv).(FieldType)
+		ft := evalStaticType(store, m.fn, &d.Type).(*FuncType)
+		d.SetAttribute(ATTR_PREDEFINED, true)
+		if !define {
+			continue
+		}
+		if !dt.TryDefineMethod(&FuncValue{
+			Type:     ft.UnboundType(rft),
+			IsMethod: true,
+			Source:   d,
+			Name:     d.Name,
+			Parent:   nil, // set lazily.
+			FileName: m.fn.FileName,
+			PkgPath:  ref.pn.PkgPath,
+			Crossing: ft.IsCrossing(),
+			// body is set lazily, as it may not be preprocessed yet.
+		}) {
+			panic(fmt.Sprintf("redeclaration of method %s.%s",
+				dt.Name, d.Name))
+		}
+	}
+	for _, m := range methods {
+		preprocessInstanceBody(store, m.fn, m.d)
+	}
+}
+
+// preprocessInstanceBody preprocesses the body of d, an instance of a generic
+// function or method declared in fn. If fn is not preprocessed yet, this is
+// deferred until it is (see preprocessPendingInstances), as the body may
+// depend on names which are not yet predefined.
+func preprocessInstanceBody(store Store, fn *FileNode, d *FuncDecl) {
+	if fn.GetAttribute(ATTR_PREPROCESSED) != true {
+		pending, _ := fn.GetAttribute(ATTR_GENERIC_PENDING).([]*FuncDecl)
+		fn.SetAttribute(ATTR_GENERIC_PENDING, append(pending, d))
+		return
+	}
+	Preprocess(store, fn, d)
+	// save the block nodes of the body.
+	Transcribe(d, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok {
+			store.SetBlockNode(bn)
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// preprocessPendingInstances preprocesses the bodies of the instances of the
+// generic declarations of fn, which were instantiated before fn was
+// preprocessed.
+func preprocessPendingInstances(store Store, fn *FileNode) {
+	for {
+		pending, _ := fn.GetAttribute(ATTR_GENERIC_PENDING).([]*FuncDecl)
+		if len(pending) == 0 {
+			return
+		}
+		fn.DelAttribute(ATTR_GENERIC_PENDING)
+		for _, d := range pending {
+			preprocessInstanceBody(store, fn, d)
+		}
+	}
+}
+
+// evalTypeIn preprocesses and evaluates the type expression x, in the
+// context of the file fn.
+func evalTypeIn(store Store, fn *FileNode, x Expr) Type {
+	predefineDeps(store, fn, x)
+	x = Preprocess(store, fn, x).(Expr)
+	return evalStaticType(store, fn, x)
+}
+
+// predefineDeps predefines the package declarations x depends on. This is
+// needed when instantiating a template whose package is still being
+// predefined.
+func predefineDeps(store Store, fn *FileNode, x Expr) {
+	pn := packageOf(fn)
+	for {
+		un, _ := findUndefinedT(store, fn, x, nil, map[Name]struct{}{}, false, false)
+		if un == "" {
+			return
+		}
+		dfn, d := pn.FileSet.GetDeclFor(un)
+		if (*d).GetAttribute(ATTR_PREDEFINED) == true {
+			panic(fmt.Sprintf("invalid recursive declaration: %s", un))
+		}
+		predefineRecursively(store, dfn, *d)
+	}
+}
+
+// copyTemplate returns a copy of n, a node of a generic declaration,
+// including the attributes which are set by go2gno.
+func copyTemplate(n Node) Node {
+	var src []Node
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			src = append(src, n)
+		}
+		return n, TRANS_CONTINUE
+	})
+	cp := n.Copy()
+	i := 0
+	Transcribe(cp, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage == TRANS_ENTER {
+			orig := src[i]
+			n.SetSpan(orig.GetSpan())
+			n.SetLabel(orig.GetLabel())
+			if iota := orig.GetAttribute(ATTR_IOTA); iota != nil {
+				n.SetAttribute(ATTR_IOTA, iota)
+			}
+			i++
+		}
+		return n, TRANS_CONTINUE
+	})
+	return cp
+}
+
+func typeParamsMap(tparams FieldTypeExprs, targs []Type) map[Name]Type {
+	res := make(map[Name]Type, len(tparams))
+	for i, tp := range tparams {
+		if tp.Name != blankIdentifier {
+			res[tp.Name] = targs[i]
+		}
+	}
+	return res
+}
+
+// substTypeParams replaces the type parameters in n by the corresponding
+// type arguments of targs, to be resolved in fn.
+func substTypeParams(fn *FileNode, n Node, targs map[Name]Type) Node {
+	return Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		nx, ok := n.(*NameExpr)
+		if !ok {
+			return n, TRANS_CONTINUE
+		}
+		t, ok := targs[nx.Name]
+		if !ok {
+			return n, TRANS_CONTINUE
+		}
+		switch ftype {
+		case TRANS_COMPOSITE_KEY:
+			// may be a struct field name.
+			return n, TRANS_CONTINUE
+		case TRANS_ASSIGN_LHS:
+			if ns[len(ns)-1].(*AssignStmt).Op == DEFINE {
+				return n, TRANS_CONTINUE
+			}
+		}
+		return toConstTypeExpr(fn, nx, t), TRANS_CONTINUE
+	})
+}
+
+// setInstanceLocations is like setNodeLocations, for a node of an instance.
+func setInstanceLocations(pkgPath string, fileName string, inst string, n Node) {
+	Transcribe(n, func(ns []Node, ftype TransField, index int, n Node, stage TransStage) (Node, TransCtrl) {
+		if stage != TRANS_ENTER {
+			return n, TRANS_CONTINUE
+		}
+		if bn, ok := n.(BlockNode); ok {
+			bn.SetLocation(Location{
+				PkgPath: pkgPath,
+				File:    fileName,
+				Span:    bn.GetSpan(),
+				Inst:    inst,
+			})
+		}
+		return n, TRANS_CONTINUE
+	})
+}
+
+// ----------------------------------------
+// Type inference
+
+// inferFunc instantiates the generic function ref, called by n, inferring
+// its type arguments from the types of the arguments of n.
+func inferFunc(store Store, last BlockNode, n *CallExpr, ref *genericRef) Expr {
+	fd, ok := ref.decl.(*FuncDecl)
+	if !ok {
+		panic(fmt.Sprintf("cannot use generic type %s without instantiation",
+			ref.name()))
+	}
+	inf := &typeInferrer{
+		store: store,
+		fn:    ref.fn,
+		index: make(map[Name]int, len(fd.TypeParams)),
+		targs: make([]Type, len(fd.TypeParams)),
+	}
+	for i, tp := range fd.TypeParams {
+		inf.index[tp.Name] = i
+	}
+	copy(inf.targs, ref.targs)
+
+	// argument types.
+	var ats []Type
+	if len(n.Args) == 1 {
+		if tt, ok := evalStaticTypeOfRaw(store, last, n.Args[0]).(*tupleType); ok {
+			ats = tt.Elts
+		}
+	}
+	if ats == nil {
+		ats = make([]Type, len(n.Args))
+		for i, arg := range n.Args {
+			ats[i] = evalStaticTypeOf(store, last, arg)
+		}
+	}
+	// parameter type expressions.
+	params := fd.Type.Params
+	paramOf := func(i int) Expr {
+		if len(params) == 0 {
+			return nil
+		}
+		lp := params[len(params)-1].Type
+		if vx, ok := lp.(*SliceTypeExpr); ok && vx.Vrd && i >= len(params)-1 {
+			if n.Varg {
+				return vx
+			}
+			return vx.Elt
+		}
+		if i < len(params) {
+			return params[i].Type
+		}
+		return nil
+	}
+
+	// Typed arguments first, then untyped constants for type parameters
+	// which are still unknown, with their default type.
+	for i, at := range ats {
+		if px := paramOf(i); px != nil && at != nil && !isUntyped(at) {
+			inf.unify(px, at)
+		}
+	}
+	defaults := make([]Type, len(inf.targs))
+	for i, at := range ats {
+		if at == nil || !isUntyped(at) {
+			continue
+		}
+		px, ok := paramOf(i).(*NameExpr)
+		if !ok {
+			continue
+		}
+		if j, ok := inf.index[px.Name]; ok && inf.targs[j] == nil {
+			// e.g. Max(1, 2.5) infers float64.
+			if defaults[j] == nil || untypedRank(at) > untypedRank(defaults[j]) {
+				defaults[j] = at
+			}
+		}
+	}
+	for i, at := range defaults {
+		if at != nil {
+			inf.targs[i] = defaultTypeOf(at)
+		}
+	}
+	for i, targ := range inf.targs {
+		if targ == nil {
+			panic(fmt.Sprintf("in call to %s, cannot infer %s",
+				fd.Name, fd.TypeParams[i].Name))
+		}
+	}
+	fv := instantiateFunc(store, ref, inf.targs)
+	return toConstExpr(n.Func, TypedValue{T: fv.Type, V: fv})
+}
+
+// untypedRank orders the kinds of untyped numeric constants, for the
+// inference of the default type.
+func untypedRank(t Type) int {
+	switch t {
+	case UntypedBigintType:
+		return 1
+	case UntypedRuneType:
+		return 2
+	case UntypedBigdecType:
+		return 3
+	default:
+		return 0
+	}
+}
+
+type typeInferrer struct {
+	store Store
+	fn    *FileNode // file of the generic function
+	index map[Name]int
+	targs []Type
+}
+
+// unify infers type arguments by matching the type expression x, of a
+// parameter of the generic function, with the type t of the argument.
+func (inf *typeInferrer) unify(x Expr, t Type) {
+	switch cx := x.(type) {
+	case *NameExpr:
+		if i, ok := inf.index[cx.Name]; ok && inf.targs[i] == nil {
+			inf.targs[i] = t
+		}
+	case *StarExpr:
+		if pt, ok := baseOf(t).(*PointerType); ok {
+			inf.unify(cx.X, pt.Elt)
+		}
+	case *SliceTypeExpr:
+		if st, ok := baseOf(t).(*SliceType); ok {
+			inf.unify(cx.Elt, st.Elt)
+		}
+	case *ArrayTypeExpr:
+		if at, ok := baseOf(t).(*ArrayType); ok {
+			inf.unify(cx.Elt, at.Elt)
+		}
+	case *MapTypeExpr:
+		if mt, ok := baseOf(t).(*MapType); ok {
+			inf.unify(cx.Key, mt.Key)
+			inf.unify(cx.Value, mt.Value)
+		}
+	case *ChanTypeExpr:
+		if ct, ok := baseOf(t).(*ChanType); ok {
+			inf.unify(cx.Value, ct.Elt)
+		}
+	case *FuncTypeExpr:
+		ft, ok := baseOf(t).(*FuncType)
+		if !ok || len(ft.Params) != len(cx.Params) || len(ft.Results) != len(cx.Results) {
+			return
+		}
+		for i := range cx.Params {
+			inf.unify(cx.Params[i].Type, ft.Params[i].Type)
+		}
+		for i := range cx.Results {
+			inf.unify(cx.Results[i].Type, ft.Results[i].Type)
+		}
+	case *IndexExpr:
+		inf.unifyInstance(cx.X, Exprs{cx.Index}, t)
+	case *IndexListExpr:
+		inf.unifyInstance(cx.X, cx.Indices, t)
+	}
+}
+
+// unifyInstance unifies `gx[idxs...]` with t, if t is an instance of the
+// generic type gx.
+func (inf *typeInferrer) unifyInstance(gx Expr, idxs Exprs, t Type) {
+	dt, ok := t.(*DeclaredType)
+	if !ok || len(dt.targs) != len(idxs) {
+		return
+	}
+	var ref *genericRef
+	switch cx := gx.(type) {
+	case *NameExpr:
+		ref = lookupGeneric(inf.fn, cx.Name)
+	case *SelectorExpr:
+		ref = selectorGenericRef(inf.store, inf.fn, cx)
+	}
+	if ref == nil ||
+		ref.pn.PkgPath != dt.PkgPath ||
+		!strings.HasPrefix(string(dt.Name), string(ref.name())+"[") {
+		return
+	}
+	for i, idx := range idxs {
+		inf.unify(idx, dt.targs[i])
+	}
+}
+
+// selectorGenericRef returns the generic declaration referred to by sx, if
+// it is like pkg.Name.
+func selectorGenericRef(store Store, last BlockNode, sx *SelectorExpr) *genericRef {
+	nx, ok := sx.X.(*NameExpr)
+	if !ok {
+		return nil
+	}
+	tv := last.GetSlot(store, nx.Name, true)
+	if tv == nil {
+		return nil
+	}
+	pv, ok := tv.V.(*PackageValue)
+	if !ok {
+		return nil
+	}
+	return packageGenericRef(pv.GetPackageNode(store), sx.Sel)
+}
+
+// ----------------------------------------
+// Constraints
+
+// checkTypeArgs panics if targs don't satisfy the constraints of tparams,
+// declared in fn.
+func checkTypeArgs(store Store, fn *FileNode, tparams FieldTypeExprs, targs []Type) {
+	tpm := typeParamsMap(tparams, targs)
+	for i, tp := range tparams {
+		cx := copyTemplate(tp.Type).(Expr)
+		cx = substTypeParams(fn, cx, tpm).(Expr)
+		if !satisfies(store, fn, cx, targs[i]) {
+			panic(fmt.Sprintf("%s does not satisfy %s",
+				targs[i].String(), genericName(tp.Type)))
+		}
+	}
+}
+
+// satisfies returns whether t satisfies the constraint cx, declared in fn.
+func satisfies(store Store, fn *FileNode, cx Expr, t Type) bool {
+	switch cx := cx.(type) {
+	case *NameExpr:
+		switch cx.Name {
+		case "any":
+			return true
+		case "comparable":
+			return isComparableType(t)
+		}
+		if ref := lookupGeneric(fn, cx.Name); ref != nil {
+			return satisfiesDecl(store, ref, t)
+		}
+	case *SelectorExpr:
+		if ref := selectorGenericRef(store, fn, cx); ref != nil {
+			return satisfiesDecl(store, ref, t)
+		}
+	case *BinaryExpr:
+		if cx.Op == BOR {
+			return satisfies(store, fn, cx.Left, t) ||
+				satisfies(store, fn, cx.Right, t)
+		}
+	case *UnaryExpr:
+		if cx.Op == TILDE {
+			ut := evalTypeIn(store, fn, cx.X)
+			return baseOf(t).TypeID() == baseOf(ut).TypeID()
+		}
+	case *InterfaceTypeExpr:
+		// embedded elements (including type sets) must all be
+		// satisfied, then the methods are checked below.
+		var methods FieldTypeExprs
+		for _, m := range cx.Methods {
+			if m.Name != "" {
+				methods = append(methods, m)
+			} else if !satisfies(store, fn, m.Type, t) {
+				return false
+			}
+		}
+		if len(methods) == 0 {
+			return true
+		}
+		return implementsMethods(store, fn, &InterfaceTypeExpr{Methods: methods}, t)
+	}
+	// a type, or a basic interface.
+	ct := evalTypeIn(store, fn, cx)
+	if it, ok := baseOf(ct).(*InterfaceType); ok {
+		return it.IsImplementedBy(t)
+	}
+	return ct.TypeID() == t.TypeID()
+}
+
+func satisfiesDecl(store Store, ref *genericRef, t Type) bool {
+	td, ok := ref.decl.(*TypeDecl)
+	if !ok || len(td.TypeParams) > 0 {
+		panic(fmt.Sprintf("cannot use %s as a type constraint", ref.name()))
+	}
+	return satisfies(store, ref.fn, copyTemplate(td.Type).(Expr), t)
+}
+
+func implementsMethods(store Store, fn *FileNode, itx *InterfaceTypeExpr, t Type) bool {
+	it := evalTypeIn(store, fn, itx).(*InterfaceType)
+	return it.IsImplementedBy(t)
+}
+
+// isComparableType returns whether t satisfies comparable: that is, whether
+// its values can be compared with ==.
+func isComparableType(t Type) bool {
+	switch bt := baseOf(t).(type) {
+	case *SliceType, *MapType, *FuncType:
+		return false
+	case *ArrayType:
+		return isComparableType(bt.Elt)
+	case *StructType:
+		for _, f := range bt.Fields {
+			if !isComparableType(f.Type) {
+				return false
+			}
+		}
+	}
+	return true
+}
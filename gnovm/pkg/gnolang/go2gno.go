@@ -263,6 +263,11 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			X:     toExpr(fs, gon.X),
 			Index: toExpr(fs, gon.Index),
 		}
+	case *ast.IndexListExpr:
+		return &IndexListExpr{
+			X:       toExpr(fs, gon.X),
+			Indices: toExprs(fs, gon.Indices),
+		}
 	case *ast.SelectorExpr:
 		return &SelectorExpr{
 			X:   toExpr(fs, gon.X),
@@ -521,11 +526,12 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 			body = Go2Gno(fs, gon.Body).(*BlockStmt).Body
 		}
 		return &FuncDecl{
-			IsMethod: isMethod,
-			Recv:     recv,
-			NameExpr: NameExpr{Name: name},
-			Type:     *type_,
-			Body:     body,
+			IsMethod:   isMethod,
+			Recv:       recv,
+			NameExpr:   NameExpr{Name: name},
+			TypeParams: toFieldsFromList(fs, gon.Type.TypeParams),
+			Type:       *type_,
+			Body:       body,
 		}
 	case *ast.GenDecl:
 		panicWithPos("unexpected *ast.GenDecl; use toDecls(fs,) instead")
@@ -546,13 +552,20 @@ func Go2Gno(fs *token.FileSet, gon ast.Node) (n Node) {
 		}
 	case *ast.EmptyStmt:
 		return &EmptyStmt{}
-	case *ast.IndexListExpr:
-		if len(gon.Indices) > 1 {
-			panicWithPos("invalid operation: more than one index")
-		}
-		panicWithPos("invalid operation: indexList is not permitted in Gno")
 	case *ast.GoStmt:
-		panicWithPos("goroutines are not permitted")
+		cx := toExpr(fs, gon.Call).(*CallExpr)
+		return &GoStmt{
+			Call: *cx,
+		}
+	case *ast.SendStmt:
+		return &SendStmt{
+			Chan:  toExpr(fs, gon.Chan),
+			Value: toExpr(fs, gon.Value),
+		}
+	case *ast.SelectStmt:
+		return &SelectStmt{
+			Cases: toSelectCases(fs, gon.Body.List),
+		}
 	default:
 		panicWithPos("unknown Go type %v: %s\n",
 			reflect.TypeOf(gon),
@@ -593,6 +606,7 @@ var token2word = map[token.Token]Word{
 	token.SHL:            SHL,
 	token.SHR:            SHR,
 	token.AND_NOT:        BAND_NOT,
+	token.TILDE:          TILDE,
 	token.ADD_ASSIGN:     ADD_ASSIGN,
 	token.SUB_ASSIGN:     SUB_ASSIGN,
 	token.MUL_ASSIGN:     MUL_ASSIGN,
@@ -725,9 +739,10 @@ func toDecls(fs *token.FileSet, gd *ast.GenDecl) (ds Decls) {
 			tipe := toExpr(fs, s.Type)
 			alias := s.Assign != 0
 			td := &TypeDecl{
-				NameExpr: NameExpr{Name: name},
-				Type:     tipe,
-				IsAlias:  alias,
+				NameExpr:   NameExpr{Name: name},
+				TypeParams: toFieldsFromList(fs, s.TypeParams),
+				Type:       tipe,
+				IsAlias:    alias,
 			}
 			setSpan(fs, s, td)
 			ds = append(ds, td)
@@ -895,6 +910,27 @@ func toClauses(fs *token.FileSet, csz []ast.Stmt) []SwitchClauseStmt {
 	return res
 }
 
+func toSelectCases(fs *token.FileSet, csz []ast.Stmt) []SelectCaseStmt {
+	res := make([]SelectCaseStmt, 0, len(csz))
+	hasDefault := false
+	for _, cs := range csz {
+		cc := cs.(*ast.CommClause)
+		if cc.Comm == nil {
+			if hasDefault {
+				panic("multiple defaults in select")
+			}
+			hasDefault = true
+		}
+		scs := SelectCaseStmt{
+			Comm: toSimp(fs, cc.Comm),
+			Body: toStmts(fs, cc.Body),
+		}
+		setSpan(fs, cc, &scs)
+		res = append(res, scs)
+	}
+	return res
+}
+
 func toSwitchClauseStmt(fs *token.FileSet, cc *ast.CaseClause) SwitchClauseStmt {
 	scs := SwitchClauseStmt{
 		Cases: toExprs(fs, cc.List),
@@ -0,0 +1,510 @@
+package gnolang
+
+// Goroutines and channels.
+//
+// Goroutines are run by a deterministic, cooperative scheduler within a
+// single Machine. A goroutine runs until it blocks on a channel operation,
+// waits for other goroutines, or terminates; only then is the next runnable
+// goroutine (in FIFO order) resumed. There is no preemption, so the
+// interleaving only depends on the program itself.
+//
+// All goroutines must have terminated before the realm changes they made
+// are finalized: a goroutine returning across a realm boundary first waits
+// for the goroutines it started within that call, and the root goroutine
+// waits for all goroutines before halting. If every goroutine is blocked,
+// the Machine panics with a deadlock error, aborting the transaction.
+
+const deadlockError = "all goroutines are asleep - deadlock!"
+
+// goroutine holds the state of a goroutine. The state of the running
+// goroutine lives in the Machine; it is saved here when the goroutine
+// is parked, and restored when it resumes.
+type goroutine struct {
+	id     int
+	parent *goroutine // nil for the root goroutine
+	depth  int        // number of parent frames when started
+
+	ops        []Op
+	values     []TypedValue
+	exprs      []Expr
+	stmts      []Stmt
+	blocks     []*Block
+	frames     []Frame
+	pkg        *PackageValue
+	realm      *Realm
+	exception  *Exception
+	numResults int
+
+	joining   bool        // parked until its children terminate
+	joinDepth int         // frame index of the returning call
+	seq       int         // invalidates stale waiters
+	woken     *chanWaiter // completed channel operation, if any
+}
+
+// chanWaiter is a goroutine parked on a channel operation.
+type chanWaiter struct {
+	g     *goroutine
+	seq   int        // g.seq when parked
+	index int        // select case index, or -1
+	value TypedValue // value sent, or received
+	ok    bool       // false if woken by close
+}
+
+func (w *chanWaiter) isValid() bool {
+	return w.g.woken == nil && w.g.seq == w.seq
+}
+
+type scheduler struct {
+	root   *goroutine
+	cur    *goroutine
+	runq   []*goroutine // runnable goroutines, FIFO
+	all    []*goroutine // live goroutines except root, in order of creation
+	nextID int
+}
+
+// getScheduler returns the scheduler, initializing it with the current
+// state as the root goroutine if needed.
+func (m *Machine) getScheduler() *scheduler {
+	if m.sched == nil {
+		root := &goroutine{}
+		m.sched = &scheduler{
+			root:   root,
+			cur:    root,
+			nextID: 1,
+		}
+	}
+	return m.sched
+}
+
+// inGoroutine returns true if the running goroutine is not the root one.
+func (m *Machine) inGoroutine() bool {
+	return m.sched != nil && m.sched.cur != m.sched.root
+}
+
+// startGoroutine creates a goroutine calling the function on top of the
+// values stack with numArgs arguments, and makes it runnable.
+func (m *Machine) startGoroutine(cx *CallExpr, numArgs int) {
+	s := m.getScheduler()
+	vals := m.PopValues(numArgs + 1)
+	g := &goroutine{
+		id:     s.nextID,
+		parent: s.cur,
+		depth:  len(m.Frames),
+		ops:    make([]Op, 0, startingOpsCap),
+		values: make([]TypedValue, 0, startingValuesCap),
+		pkg:    m.Package,
+		realm:  m.Realm,
+	}
+	// Arguments are evaluated and copied in the calling goroutine.
+	for _, v := range vals {
+		g.values = append(g.values, v.Copy(m.Alloc))
+	}
+	g.ops = append(g.ops, OpHalt, OpPrecall)
+	g.exprs = []Expr{cx}
+	s.nextID++
+	s.all = append(s.all, g)
+	s.runq = append(s.runq, g)
+}
+
+func (m *Machine) saveGoroutine(g *goroutine) {
+	g.ops = m.Ops
+	g.values = m.Values
+	g.exprs = m.Exprs
+	g.stmts = m.Stmts
+	g.blocks = m.Blocks
+	g.frames = m.Frames
+	g.pkg = m.Package
+	g.realm = m.Realm
+	g.exception = m.Exception
+	g.numResults = m.NumResults
+}
+
+func (m *Machine) restoreGoroutine(g *goroutine) {
+	m.Ops = g.ops
+	m.Values = g.values
+	m.Exprs = g.exprs
+	m.Stmts = g.stmts
+	m.Blocks = g.blocks
+	m.Frames = g.frames
+	m.Package = g.pkg
+	m.Realm = g.realm
+	m.Exception = g.exception
+	m.NumResults = g.numResults
+	// release references held while parked.
+	*g = goroutine{
+		id:        g.id,
+		parent:    g.parent,
+		depth:     g.depth,
+		joining:   g.joining,
+		joinDepth: g.joinDepth,
+		seq:       g.seq,
+		woken:     g.woken,
+	}
+}
+
+// parkGoroutine saves the running goroutine, which must have arranged to be
+// woken up, and resumes the next runnable one.
+func (m *Machine) parkGoroutine() {
+	s := m.getScheduler()
+	m.saveGoroutine(s.cur)
+	m.resumeNextGoroutine()
+}
+
+func (m *Machine) resumeNextGoroutine() {
+	s := m.sched
+	if len(s.runq) == 0 {
+		panic(deadlockError)
+	}
+	g := s.runq[0]
+	s.runq[0] = nil
+	s.runq = s.runq[1:]
+	s.cur = g
+	m.restoreGoroutine(g)
+}
+
+// readyGoroutine makes a parked goroutine runnable.
+func (m *Machine) readyGoroutine(g *goroutine) {
+	s := m.sched
+	s.runq = append(s.runq, g)
+}
+
+// exitGoroutine is called when a goroutine other than the root one halts.
+func (m *Machine) exitGoroutine() {
+	s := m.sched
+	g := s.cur
+	for i, x := range s.all {
+		if x == g {
+			copy(s.all[i:], s.all[i+1:])
+			s.all[len(s.all)-1] = nil
+			s.all = s.all[:len(s.all)-1]
+			break
+		}
+	}
+	// Wake up goroutines waiting for their children to terminate.
+	m.wakeJoiningGoroutine(s.root)
+	for _, x := range s.all {
+		m.wakeJoiningGoroutine(x)
+	}
+	m.resumeNextGoroutine()
+}
+
+func (m *Machine) wakeJoiningGoroutine(g *goroutine) {
+	if g.joining && !m.sched.hasChildren(g, g.joinDepth) {
+		g.joining = false
+		m.readyGoroutine(g)
+	}
+}
+
+// hasChildren returns true if a live goroutine was started by g (directly,
+// or by one of its descendants) while g had more than depth frames.
+func (s *scheduler) hasChildren(g *goroutine, depth int) bool {
+	for _, x := range s.all {
+		for c := x; c.parent != nil; c = c.parent {
+			if c.parent == g && c.depth > depth {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// haltGoroutine is called upon OpHalt. It returns true if the Machine
+// should keep running, either because a goroutine terminated and another
+// one was resumed, or because the root goroutine must wait for the others.
+func (m *Machine) haltGoroutine() bool {
+	s := m.sched
+	if s == nil || len(m.Ops) != 0 {
+		// no goroutines, or the end of a nested Run().
+		return false
+	}
+	if s.cur != s.root {
+		m.exitGoroutine()
+		return true
+	}
+	if len(s.all) == 0 {
+		m.sched = nil
+		return false
+	}
+	// Wait for all goroutines to terminate, then halt again.
+	m.PushOp(OpHalt)
+	s.root.joining = true
+	s.root.joinDepth = -1
+	m.parkGoroutine()
+	return true
+}
+
+// joinGoroutines is called before returning from a call. If the call
+// returns across a realm boundary while goroutines started within it are
+// still running, the running goroutine is parked until they terminate,
+// after which op is executed again. It returns true if parked.
+func (m *Machine) joinGoroutines(op Op) bool {
+	s := m.sched
+	if s == nil {
+		return false
+	}
+	depth := -1
+	for i := len(m.Frames) - 1; i >= 0; i-- {
+		if m.Frames[i].IsCall() {
+			depth = i
+			break
+		}
+	}
+	if depth < 0 || !m.isRealmBoundary(&m.Frames[depth]) {
+		return false
+	}
+	if !s.hasChildren(s.cur, depth) {
+		return false
+	}
+	m.PushOp(op)
+	s.cur.joining = true
+	s.cur.joinDepth = depth
+	m.parkGoroutine()
+	return true
+}
+
+// takeWoken returns the channel operation completed on behalf of the
+// running goroutine while it was parked, if any.
+func (m *Machine) takeWoken() *chanWaiter {
+	if m.sched == nil {
+		return nil
+	}
+	g := m.sched.cur
+	w := g.woken
+	if w != nil {
+		g.woken = nil
+		g.seq++ // invalidate the other waiters of a select.
+	}
+	return w
+}
+
+// parkOnChans parks the running goroutine on the given channels, for
+// either sending (with the values given) or receiving.
+func (m *Machine) parkOnChans(chs []*ChanValue, sends []bool, vals []TypedValue, indices []int) {
+	g := m.getScheduler().cur
+	for i, ch := range chs {
+		w := &chanWaiter{
+			g:     g,
+			seq:   g.seq,
+			index: indices[i],
+		}
+		if sends[i] {
+			w.value = vals[i]
+			ch.sendq = append(pruneWaiters(ch.sendq), w)
+		} else {
+			ch.recvq = append(pruneWaiters(ch.recvq), w)
+		}
+	}
+	m.parkGoroutine()
+}
+
+// pruneWaiters removes the waiters which are no longer valid,
+// e.g. those of a select which already proceeded.
+func pruneWaiters(q []*chanWaiter) []*chanWaiter {
+	res := q[:0]
+	for _, w := range q {
+		if w.isValid() {
+			res = append(res, w)
+		}
+	}
+	clear(q[len(res):])
+	return res
+}
+
+// popWaiter pops the first valid waiter of q.
+func popWaiter(q *[]*chanWaiter) *chanWaiter {
+	for len(*q) > 0 {
+		w := (*q)[0]
+		(*q)[0] = nil
+		*q = (*q)[1:]
+		if w.isValid() {
+			return w
+		}
+	}
+	return nil
+}
+
+func hasWaiter(q []*chanWaiter) bool {
+	for _, w := range q {
+		if w.isValid() {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *Machine) wakeWaiter(w *chanWaiter, tv TypedValue, ok bool) {
+	w.value = tv
+	w.ok = ok
+	w.g.woken = w
+	m.readyGoroutine(w.g)
+}
+
+// ----------------------------------------
+// Channel operations
+
+func panicClosedChan(op string) {
+	panic(&Exception{Value: typedString(op + " of closed channel")})
+}
+
+// canSend returns true if sending on ch would not block.
+func (ch *ChanValue) canSend() bool {
+	return ch.Closed || len(ch.Buffer) < ch.Cap || hasWaiter(ch.recvq)
+}
+
+// canRecv returns true if receiving from ch would not block.
+func (ch *ChanValue) canRecv() bool {
+	return ch.Closed || len(ch.Buffer) > 0 || hasWaiter(ch.sendq)
+}
+
+// chanSend sends tv on ch, which must not block.
+func (m *Machine) chanSend(ch *ChanValue, tv TypedValue) {
+	if ch.Closed {
+		panic(&Exception{Value: typedString("send on closed channel")})
+	}
+	if w := popWaiter(&ch.recvq); w != nil {
+		m.wakeWaiter(w, tv, true)
+		return
+	}
+	if len(ch.Buffer) < ch.Cap {
+		ch.Buffer = append(ch.Buffer, tv)
+		return
+	}
+	panic("should not happen")
+}
+
+// chanRecv receives from ch, which must not block. It returns the zero
+// value of et and false if the channel is closed and empty.
+func (m *Machine) chanRecv(ch *ChanValue, et Type) (TypedValue, bool) {
+	if len(ch.Buffer) > 0 {
+		tv := ch.Buffer[0]
+		ch.Buffer[0] = TypedValue{}
+		ch.Buffer = ch.Buffer[1:]
+		// Move a parked sender's value into the buffer.
+		if w := popWaiter(&ch.sendq); w != nil {
+			ch.Buffer = append(ch.Buffer, w.value)
+			m.wakeWaiter(w, TypedValue{}, true)
+		}
+		return tv, true
+	}
+	if w := popWaiter(&ch.sendq); w != nil {
+		tv := w.value
+		m.wakeWaiter(w, TypedValue{}, true)
+		return tv, true
+	}
+	if ch.Closed {
+		return defaultTypedValue(m.Alloc, et), false
+	}
+	panic("should not happen")
+}
+
+// chanClose closes ch, waking up all parked goroutines.
+func (m *Machine) chanClose(ch *ChanValue, et Type) {
+	if ch.Closed {
+		panicClosedChan("close")
+	}
+	ch.Closed = true
+	for {
+		w := popWaiter(&ch.recvq)
+		if w == nil {
+			break
+		}
+		m.wakeWaiter(w, defaultTypedValue(m.Alloc, et), false)
+	}
+	for {
+		w := popWaiter(&ch.sendq)
+		if w == nil {
+			break
+		}
+		m.wakeWaiter(w, TypedValue{}, false)
+	}
+}
+
+// sendOrPark sends tv on the channel chv, or parks the running goroutine
+// until it can. It returns false if parked, in which case retry is pushed
+// to be executed again when the goroutine resumes.
+func (m *Machine) sendOrPark(chv *TypedValue, tv TypedValue, retry Op) bool {
+	if w := m.takeWoken(); w != nil {
+		if !w.ok {
+			panic(&Exception{Value: typedString("send on closed channel")})
+		}
+		return true
+	}
+	ch, _ := chv.V.(*ChanValue)
+	if ch != nil && ch.canSend() {
+		m.chanSend(ch, tv)
+		return true
+	}
+	m.PushOp(retry)
+	if ch == nil {
+		// Sending on a nil channel blocks forever.
+		m.parkGoroutine()
+		return false
+	}
+	m.parkOnChans([]*ChanValue{ch}, []bool{true}, []TypedValue{tv}, []int{-1})
+	return false
+}
+
+// recvOrPark receives from the channel chv, or parks the running goroutine
+// until it can. It returns done=false if parked, in which case retry is
+// pushed to be executed again when the goroutine resumes.
+func (m *Machine) recvOrPark(chv *TypedValue, retry Op) (tv TypedValue, ok bool, done bool) {
+	if w := m.takeWoken(); w != nil {
+		return w.value, w.ok, true
+	}
+	ch, _ := chv.V.(*ChanValue)
+	if ch != nil && ch.canRecv() {
+		et := baseOf(chv.T).(*ChanType).Elt
+		tv, ok = m.chanRecv(ch, et)
+		return tv, ok, true
+	}
+	if retry < OpSticky {
+		m.PushOp(retry) // sticky ops are not popped.
+	}
+	if ch == nil {
+		// Receiving from a nil channel blocks forever.
+		m.parkGoroutine()
+		return
+	}
+	m.parkOnChans([]*ChanValue{ch}, []bool{false}, nil, []int{-1})
+	return
+}
+
+// visitParked visits the state of parked goroutines, for GC.
+func (s *scheduler) visitParked(alloc *Allocator, vis Visitor) (stop bool) {
+	gs := append([]*goroutine{s.root}, s.all...)
+	for _, g := range gs {
+		if g == s.cur {
+			continue // visited with the Machine.
+		}
+		for _, tv := range g.values {
+			if tv.V != nil {
+				if stop = vis(tv.V); stop {
+					return
+				}
+			}
+		}
+		for _, b := range g.blocks {
+			if b != nil {
+				if stop = vis(b); stop {
+					return
+				}
+			}
+		}
+		for _, fr := range g.frames {
+			if stop = fr.Visit(alloc, vis); stop {
+				return
+			}
+		}
+		for e := g.exception; e != nil; e = e.Previous {
+			if stop = e.Visit(alloc, vis); stop {
+				return
+			}
+		}
+		if g.woken != nil && g.woken.value.V != nil {
+			if stop = vis(g.woken.value.V); stop {
+				return
+			}
+		}
+	}
+	return
+}
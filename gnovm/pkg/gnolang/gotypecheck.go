@@ -160,6 +160,31 @@ type TypeCheckOptions struct {
 	// libraries. Packages found in the Cache won't need to be type checked
 	// again.
 	Cache TypeCheckCache
+
+	// Info is optionally filled with the Go type information of the
+	// checked package and its tests, but not of its imports.
+	Info *TypeCheckInfo
+}
+
+// TypeCheckInfo holds the Go type information collected by
+// [TypeCheckMemPackage], for tools such as `gno lsp`.
+type TypeCheckInfo struct {
+	types.Info
+	Fset  *token.FileSet // file set of Files
+	Files []*ast.File    // parsed .gno files of the package
+}
+
+// NewTypeCheckInfo returns a TypeCheckInfo recording types, definitions,
+// uses, selections and scopes.
+func NewTypeCheckInfo() *TypeCheckInfo {
+	return &TypeCheckInfo{Info: types.Info{
+		Types:      map[ast.Expr]types.TypeAndValue{},
+		Defs:       map[*ast.Ident]types.Object{},
+		Uses:       map[*ast.Ident]types.Object{},
+		Implicits:  map[ast.Node]types.Object{},
+		Selections: map[*ast.SelectorExpr]*types.Selection{},
+		Scopes:     map[ast.Node]*types.Scope{},
+	}}
 }
 
 // TypeCheckMemPackage performs type validation and checking on the given
@@ -183,6 +208,7 @@ func TypeCheckMemPackage(mpkg *std.MemPackage, opts TypeCheckOptions) (
 		tgetter:   gimpGetterWrapper{mpkg, opts.TestGetter},
 		cache:     map[string]*gnoImporterResult{},
 		permCache: opts.Cache,
+		info:      opts.Info,
 		cfg: &types.Config{
 			Error: func(err error) {
 				gimp.Error(err)
@@ -215,6 +241,7 @@ type gnoImporter struct {
 	tgetter   MemPackageGetter // used for stdlibs if .testing
 	cache     map[string]*gnoImporterResult
 	permCache TypeCheckCache
+	info      *TypeCheckInfo // for the top level package only
 	cfg       *types.Config
 	errors    []error  // there may be many for a single import
 	stack     []string // stack of pkgpaths for cyclic import detection
@@ -441,6 +468,14 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, wtests *bool)
 		return nil, errs
 	}
 
+	// Only record type information of the top level package.
+	var info *types.Info
+	if wtests == nil && gimp.info != nil {
+		gimp.info.Fset = gofset
+		gimp.info.Files = allgofs
+		info = &gimp.info.Info
+	}
+
 	// STEP 3: Prepare for Go type-checking.
 	for _, gof := range allgofs {
 		err := prepareGoGno0p9(gof)
@@ -477,7 +512,7 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, wtests *bool)
 	// Preserve gimp.testing, sub-imports are under the same context.
 	// gimp.testing = false <-- incorrect!
 	pgofs := filterTests(gofset, gofs) // prod gofs.
-	pkg, _ = gimp.cfg.Check(mpkg.Path, gofset, pgofs, nil)
+	pkg, _ = gimp.cfg.Check(mpkg.Path, gofset, pgofs, info)
 	// Fail early: there's no point checking the others.
 	if len(gimp.errors) != numErrs {
 		errs = multierr.Combine(gimp.errors[numErrs:]...)
@@ -494,7 +529,7 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, wtests *bool)
 	// STEP 4: Type-check Gno0.9 AST in Go (w/ tests, but not xxx_tests).
 	if len(pgofs) < len(gofs) {
 		gimp.testing = true // use tgetter for stdlibs, default to getter.
-		pkg, _ = gimp.cfg.Check(mpkg.Path, gofset, gofs, nil)
+		pkg, _ = gimp.cfg.Check(mpkg.Path, gofset, gofs, info)
 		// Fail early: there's no point checking the others.
 		if len(gimp.errors) != numErrs {
 			errs = multierr.Combine(gimp.errors[numErrs:]...)
@@ -521,7 +556,7 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, wtests *bool)
 		_gofs2 = append(_gofs, gmgof)
 	}
 	gimp.testing = true // use tgetter for stdlibs, default to getter.
-	_, _ = gimp.cfg.Check(mpkg.Path+"_test", gofset, _gofs2, nil)
+	_, _ = gimp.cfg.Check(mpkg.Path+"_test", gofset, _gofs2, info)
 	/* NOTE: Uncomment to fail earlier.
 	if len(gimp.errors) != numErrs {
 		errs = multierr.Combine(gimp.errors[numErrs:]...)
@@ -536,7 +571,7 @@ func (gimp *gnoImporter) typeCheckMemPackage(mpkg *std.MemPackage, wtests *bool)
 		gmgof.Name = ast.NewIdent(tpname)
 		tgofs2 := []*ast.File{gmgof, tgof}
 		gimp.testing = true // use tgetter for stdlibs, default to tgetter.
-		_, _ = gimp.cfg.Check(mpkg.Path, gofset, tgofs2, nil)
+		_, _ = gimp.cfg.Check(mpkg.Path, gofset, tgofs2, info)
 		/* NOTE: Uncomment to fail earlier.
 		if len(gimp.errors) != numErrs {
 			errs = multierr.Combine(gimp.errors[numErrs:]...)
@@ -39,6 +39,8 @@ type Machine struct {
 	Stage         Stage         // pre for static eval, add for package init, run otherwise
 	ReviveEnabled bool          // true if revive() enabled (only in testing mode for now)
 
+	sched *scheduler // goroutines; nil until the first go statement
+
 	Debugger Debugger
 
 	// Configuration
@@ -46,6 +48,8 @@ type Machine struct {
 	Store    Store
 	Context  any
 	GasMeter store.GasMeter
+	Coverage *Coverage // if set, records executed statements
+	Profiler *Profiler // if set, records CPU cycles by call stack
 }
 
 // NewMachine initializes a new gno virtual machine, acting as a shorthand
@@ -78,7 +82,9 @@ type MachineOptions struct {
 	MaxAllocBytes int64      // or 0 for no limit.
 	GasMeter      store.GasMeter
 	ReviveEnabled bool
-	SkipPackage   bool // don't get/set package or realm.
+	SkipPackage   bool      // don't get/set package or realm.
+	Coverage      *Coverage // or nil to disable statement coverage.
+	Profiler      *Profiler // or nil to disable profiling.
 }
 
 const (
@@ -138,6 +144,8 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 	mm.Debugger.in = opts.Input
 	mm.Debugger.out = output
 	mm.ReviveEnabled = opts.ReviveEnabled
+	mm.Coverage = opts.Coverage
+	mm.Profiler = opts.Profiler
 	// Maybe get/set package and realm.
 	if !opts.SkipPackage && opts.PkgPath != "" {
 		pv := (*PackageValue)(nil)
@@ -162,6 +170,9 @@ func NewMachineWithOptions(opts MachineOptions) *Machine {
 // and m should not be used after this call. Only Machines initialized with this
 // package's constructors should be released.
 func (m *Machine) Release() {
+	if m.Profiler != nil {
+		m.Profiler.release(m)
+	}
 	// here we zero in the values for the next user
 	ops, values := m.Ops[:0:startingOpsCap], m.Values[:0:startingValuesCap]
 	clear(ops[:startingOpsCap])
@@ -604,6 +615,10 @@ func (m *Machine) runFileDecls(withOverrides bool, fns ...*FileNode) []TypedValu
 	// recursive function for var declarations.
 	var runDeclarationFor func(fn *FileNode, decl Decl)
 	runDeclarationFor = func(fn *FileNode, decl Decl) {
+		// generic declarations are only declared through their instances.
+		if isGenericDecl(decl) {
+			return
+		}
 		// get fileblock of fn.
 		// fb := pv.GetFileBlock(nil, fn.FileName)
 		// get dependencies of decl.
@@ -1013,7 +1028,7 @@ const (
 	OpUneg  Op = 0x21 // - (unary)
 	OpUnot  Op = 0x22 // ! (unary)
 	OpUxor  Op = 0x23 // ^ (unary)
-	OpUrecv Op = 0x25 // <- (unary) // TODO make expr
+	OpUrecv Op = 0x25 // <- (unary)
 	OpLor   Op = 0x26 // ||
 	OpLand  Op = 0x27 // &&
 	OpEql   Op = 0x28 // ==
@@ -1082,6 +1097,7 @@ const (
 	OpDefine      Op = 0x8C // X... := Y...
 	OpInc         Op = 0x8D // X++
 	OpDec         Op = 0x8E // X--
+	OpSend        Op = 0x8F // X <- Y
 
 	/* Decl operators */
 	OpValueDecl Op = 0x90 // var/const ...
@@ -1096,6 +1112,7 @@ const (
 	OpRangeIterMap      Op = 0xD5
 	OpRangeIterArrayPtr Op = 0xD6
 	OpReturnCallDefers  Op = 0xD7 // XXX rename to OpCallDefers
+	OpRangeIterChan     Op = 0xD8
 	OpVoid              Op = 0xFF // For profiling simple operation
 )
 
@@ -1110,6 +1127,9 @@ func (m *Machine) incrCPU(cycles int64) {
 		m.GasMeter.ConsumeGas(gasCPU, "CPUCycles") // May panic if out of gas.
 	}
 	m.Cycles += cycles
+	if m.Profiler != nil {
+		m.Profiler.recordCycles(m, cycles)
+	}
 }
 
 const (
@@ -1125,8 +1145,8 @@ const (
 	OpCPUCallNativeBody      = 424
 	OpCPUDefer               = 64
 	OpCPUCallDeferNativeBody = 33
-	OpCPUGo                  = 1 // Not yet implemented
-	OpCPUSelect              = 1 // Not yet implemented
+	OpCPUGo                  = 489
+	OpCPUSelect              = 106
 	OpCPUSwitchClause        = 38
 	OpCPUSwitchClauseCase    = 143
 	OpCPUTypeSwitch          = 171
@@ -1147,7 +1167,7 @@ const (
 	OpCPUUneg  = 25
 	OpCPUUnot  = 6
 	OpCPUUxor  = 14
-	OpCPUUrecv = 1 // Not yet implemented
+	OpCPUUrecv = 43
 	OpCPULor   = 26
 	OpCPULand  = 24
 	OpCPUEql   = 160
@@ -1218,6 +1238,7 @@ const (
 	OpCPUDefine      = 111
 	OpCPUInc         = 76
 	OpCPUDec         = 46
+	OpCPUSend        = 51
 
 	/* Decl operators */
 	OpCPUValueDecl = 113
@@ -1232,6 +1253,7 @@ const (
 	OpCPURangeIterMap      = 48
 	OpCPURangeIterArrayPtr = 46
 	OpCPUReturnCallDefers  = 78
+	OpCPURangeIterChan     = 65
 )
 
 //----------------------------------------
@@ -1267,6 +1289,9 @@ func (m *Machine) Run(st Stage) {
 			m.Debug()
 		}
 		op := m.PopOp()
+		if m.Profiler != nil {
+			m.Profiler.recordOp(op)
+		}
 		if bm.OpsEnabled {
 			// benchmark the operation.
 			bm.StartOpCode(byte(OpVoid))
@@ -1284,6 +1309,9 @@ func (m *Machine) Run(st Stage) {
 			if bm.OpsEnabled {
 				bm.StopOpCode()
 			}
+			if m.haltGoroutine() {
+				continue
+			}
 			return
 		case OpNoop:
 			m.incrCPU(OpCPUNoop)
@@ -1328,10 +1356,10 @@ func (m *Machine) Run(st Stage) {
 			m.doOpCallDeferNativeBody()
 		case OpGo:
 			m.incrCPU(OpCPUGo)
-			panic("not yet implemented")
+			m.doOpGo()
 		case OpSelect:
 			m.incrCPU(OpCPUSelect)
-			panic("not yet implemented")
+			m.doOpSelect()
 		case OpSwitchClause:
 			m.incrCPU(OpCPUSwitchClause)
 			m.doOpSwitchClause()
@@ -1559,6 +1587,9 @@ func (m *Machine) Run(st Stage) {
 		case OpDec:
 			m.incrCPU(OpCPUDec)
 			m.doOpDec()
+		case OpSend:
+			m.incrCPU(OpCPUSend)
+			m.doOpSend()
 		/* Decl operators */
 		case OpValueDecl:
 			m.incrCPU(OpCPUValueDecl)
@@ -1585,6 +1616,9 @@ func (m *Machine) Run(st Stage) {
 		case OpRangeIterMap:
 			m.incrCPU(OpCPURangeIterMap)
 			m.doOpExec(op)
+		case OpRangeIterChan:
+			m.incrCPU(OpCPURangeIterChan)
+			m.doOpExec(op)
 		case OpReturnCallDefers:
 			m.incrCPU(OpCPUReturnCallDefers)
 			m.doOpReturnCallDefers()
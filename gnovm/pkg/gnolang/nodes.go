@@ -101,6 +101,9 @@ const (
 	SWITCH
 	TYPE
 	VAR
+
+	// Appended, to keep the values of the words above stable.
+	TILDE // ~
 )
 
 type Name string
@@ -139,8 +142,11 @@ const (
 	ATTR_LAST_BLOCK_STMT       GnoAttribute = "ATTR_LAST_BLOCK_STMT"
 	ATTR_PACKAGE_REF           GnoAttribute = "ATTR_PACKAGE_REF"
 	ATTR_PACKAGE_DECL          GnoAttribute = "ATTR_PACKAGE_DECL"
-	ATTR_PACKAGE_PATH          GnoAttribute = "ATTR_PACKAGE_PATH" // if name expr refers to package.
-	ATTR_FIX_FROM              GnoAttribute = "ATTR_FIX_FROM"     // gno fix this version.
+	ATTR_PACKAGE_PATH          GnoAttribute = "ATTR_PACKAGE_PATH"     // if name expr refers to package.
+	ATTR_FIX_FROM              GnoAttribute = "ATTR_FIX_FROM"         // gno fix this version.
+	ATTR_GENERIC               GnoAttribute = "ATTR_GENERIC"          // *genericRef, if expr refers to a generic decl.
+	ATTR_GENERIC_INSTANCE      GnoAttribute = "ATTR_GENERIC_INSTANCE" // []Type type args of an instance decl.
+	ATTR_GENERIC_PENDING       GnoAttribute = "ATTR_GENERIC_PENDING"  // []*FuncDecl instances to preprocess with file.
 )
 
 // Embedded in each Node.
@@ -225,6 +231,7 @@ func (*BasicLitExpr) assertNode()      {}
 func (*BinaryExpr) assertNode()        {}
 func (*CallExpr) assertNode()          {}
 func (*IndexExpr) assertNode()         {}
+func (*IndexListExpr) assertNode()     {}
 func (*SelectorExpr) assertNode()      {}
 func (*SliceExpr) assertNode()         {}
 func (*StarExpr) assertNode()          {}
@@ -277,6 +284,7 @@ var (
 	_ Node = &BinaryExpr{}
 	_ Node = &CallExpr{}
 	_ Node = &IndexExpr{}
+	_ Node = &IndexListExpr{}
 	_ Node = &SelectorExpr{}
 	_ Node = &SliceExpr{}
 	_ Node = &StarExpr{}
@@ -341,6 +349,7 @@ func (*BasicLitExpr) assertExpr()     {}
 func (*BinaryExpr) assertExpr()       {}
 func (*CallExpr) assertExpr()         {}
 func (*IndexExpr) assertExpr()        {}
+func (*IndexListExpr) assertExpr()    {}
 func (*SelectorExpr) assertExpr()     {}
 func (*SliceExpr) assertExpr()        {}
 func (*StarExpr) assertExpr()         {}
@@ -358,6 +367,7 @@ var (
 	_ Expr = &BinaryExpr{}
 	_ Expr = &CallExpr{}
 	_ Expr = &IndexExpr{}
+	_ Expr = &IndexListExpr{}
 	_ Expr = &SelectorExpr{}
 	_ Expr = &SliceExpr{}
 	_ Expr = &StarExpr{}
@@ -466,6 +476,14 @@ type IndexExpr struct { // X[Index]
 	HasOK bool // if true, is form: `value, ok := <X>[<Key>]
 }
 
+// IndexListExpr is the instantiation of a generic function or type with
+// more than one type argument.
+type IndexListExpr struct { // X[Indices...]
+	Attributes
+	X       Expr  // expression
+	Indices Exprs // type arguments
+}
+
 type SelectorExpr struct { // X.Sel
 	Attributes
 	X    Expr      // expression
@@ -507,8 +525,9 @@ type TypeAssertExpr struct { // X.(Type)
 // (referencing) are represented with RefExpr nodes.
 type UnaryExpr struct { // (Op X)
 	Attributes
-	X  Expr // operand
-	Op Word // operator
+	X     Expr // operand
+	Op    Word // operator
+	HasOK bool // if true, is form: `value, ok := <-<X>`
 }
 
 // MyType{<key>:<value>} struct, array, slice, and map
@@ -930,6 +949,7 @@ type RangeStmt struct {
 	IsMap      bool // if X is map type
 	IsString   bool // if X is string type
 	IsArrayPtr bool // if X is array-pointer type
+	IsChan     bool // if X is chan type
 }
 
 type ReturnStmt struct {
@@ -1090,10 +1110,11 @@ type FuncDecl struct {
 	Attributes
 	StaticBlock
 	NameExpr
-	IsMethod bool
-	Recv     FieldTypeExpr // receiver (if method); or empty (if function)
-	Type     FuncTypeExpr  // function signature: parameters and results
-	Body                   // function body; or empty for external (non-Go) function
+	IsMethod   bool
+	Recv       FieldTypeExpr  // receiver (if method); or empty (if function)
+	TypeParams FieldTypeExprs // type parameters (if generic function)
+	Type       FuncTypeExpr   // function signature: parameters and results
+	Body                      // function body; or empty for external (non-Go) function
 
 	unboundType *FuncTypeExpr // memoized
 }
@@ -1179,8 +1200,9 @@ func (x *ValueDecl) GetDeclNames() []Name {
 type TypeDecl struct {
 	Attributes
 	NameExpr
-	Type    Expr // Name, SelectorExpr, StarExpr, or XxxTypes
-	IsAlias bool // type alias since Go 1.9
+	TypeParams FieldTypeExprs // type parameters (if generic type)
+	Type       Expr           // Name, SelectorExpr, StarExpr, or XxxTypes
+	IsAlias    bool           // type alias since Go 1.9
 }
 
 func (x *TypeDecl) GetDeclNames() []Name {
@@ -274,6 +274,7 @@ type Location struct {
 	PkgPath string
 	File    string
 	Span
+	Inst string `json:",omitempty"` // type arguments, if within a generic instance; e.g. "[int,string]".
 }
 
 // Convenience with no modifications.
@@ -303,15 +304,17 @@ func ParseLocation(locstr string) (loc Location, err error) {
 // Overridden by Attributes.String().
 func (loc Location) String() string {
 	if loc.File == "" {
-		return fmt.Sprintf("%s:%s",
+		return fmt.Sprintf("%s:%s%s",
 			loc.PkgPath,
 			loc.Span.String(),
+			loc.Inst,
 		)
 	} else {
-		return fmt.Sprintf("%s/%s:%s",
+		return fmt.Sprintf("%s/%s:%s%s",
 			loc.PkgPath,
 			loc.File,
 			loc.Span.String(),
+			loc.Inst,
 		)
 	}
 }
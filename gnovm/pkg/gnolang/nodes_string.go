@@ -45,6 +45,7 @@ var wordTokenStrings = map[Word]string{
 	LEQ:             "<=",
 	GEQ:             ">=",
 	DEFINE:          ":=",
+	TILDE:           "~",
 
 	// Branch operations
 	BREAK:       "break",
@@ -136,6 +137,10 @@ func (x IndexExpr) String() string {
 	return fmt.Sprintf("%s[%s]", x.X, x.Index)
 }
 
+func (x IndexListExpr) String() string {
+	return fmt.Sprintf("%s[%s]", x.X, x.Indices.String())
+}
+
 func (x SelectorExpr) String() string {
 	return fmt.Sprintf("%s.%s", x.X, x.Sel)
 }
@@ -230,9 +235,9 @@ func (x InterfaceTypeExpr) String() string {
 func (x ChanTypeExpr) String() string {
 	switch x.Dir {
 	case SEND:
-		return fmt.Sprintf("<-chan %s", x.Value)
-	case RECV:
 		return fmt.Sprintf("chan<- %s", x.Value)
+	case RECV:
+		return fmt.Sprintf("<-chan %s", x.Value)
 	case SEND | RECV:
 		return fmt.Sprintf("chan %s", x.Value)
 	default:
@@ -380,6 +385,9 @@ func (x SelectStmt) String() string {
 }
 
 func (x SelectCaseStmt) String() string {
+	if x.Comm == nil {
+		return fmt.Sprintf("default: %s", x.Body.String())
+	}
 	return fmt.Sprintf("case %v: %s", x.Comm.String(), x.Body.String())
 }
 
@@ -420,8 +428,12 @@ func (x FuncDecl) String() string {
 	if x.IsMethod {
 		recv = "(" + x.Recv.String() + ") "
 	}
-	return fmt.Sprintf("func %s%s%s { %s }",
-		recv, x.Name, x.Type.String()[4:], x.Body.String())
+	tparams := ""
+	if len(x.TypeParams) > 0 {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
+	return fmt.Sprintf("func %s%s%s%s { %s }",
+		recv, x.Name, tparams, x.Type.String()[4:], x.Body.String())
 }
 
 func (x ImportDecl) String() string {
@@ -449,10 +461,14 @@ func (x ValueDecl) String() string {
 }
 
 func (x TypeDecl) String() string {
+	tparams := ""
+	if len(x.TypeParams) > 0 {
+		tparams = "[" + x.TypeParams.String() + "]"
+	}
 	if x.IsAlias {
-		return fmt.Sprintf("type %s = %s", x.Name, x.Type.String())
+		return fmt.Sprintf("type %s%s = %s", x.Name, tparams, x.Type.String())
 	}
-	return fmt.Sprintf("type %s %s", x.Name, x.Type.String())
+	return fmt.Sprintf("type %s%s %s", x.Name, tparams, x.Type.String())
 }
 
 func (x FileNode) String() string {
@@ -237,7 +237,7 @@ func (m *Machine) isRealmBoundary(cfr *Frame) bool {
 			// borrow-realms, the storage realm
 			// of a method's receiver.
 			return true
-		} else if m.NumFrames() == 1 {
+		} else if m.NumFrames() == 1 && !m.inGoroutine() {
 			// We are exiting the machine's realm.
 			if m.Stage == StageAdd {
 				// Unless StageAdd, where functions are called
@@ -272,6 +272,10 @@ func (m *Machine) maybeFinalize(cfr *Frame) {
 
 // Assumes that result values are pushed onto the Values stack.
 func (m *Machine) doOpReturn() {
+	// Wait for goroutines if exiting realm boundary.
+	if m.joinGoroutines(OpReturn) {
+		return
+	}
 	// Unwind stack.
 	cfr := m.PopUntilLastCallFrame()
 	// Finalize if exiting realm boundary.
@@ -284,6 +288,9 @@ func (m *Machine) doOpReturn() {
 func (m *Machine) doOpReturnAfterCopy() {
 	// If there are named results that are heap defined,
 	// need to write to those from stack before returning.
+	if m.joinGoroutines(OpReturnAfterCopy) {
+		return
+	}
 	cfr := m.MustPeekCallFrame(1)
 	fv := cfr.Func
 	ft := fv.GetType(m.Store)
@@ -308,6 +315,9 @@ func (m *Machine) doOpReturnAfterCopy() {
 // i.e. named result vars declared in func signatures,
 // because return was called with no return arguments.
 func (m *Machine) doOpReturnFromBlock() {
+	if m.joinGoroutines(OpReturnFromBlock) {
+		return
+	}
 	// Copy results from block.
 	cfr := m.PopUntilLastCallFrame()
 	fv := cfr.Func
@@ -533,6 +543,21 @@ func (m *Machine) doOpDefer() {
 	m.PopValue() // pop func
 }
 
+func (m *Machine) doOpGo() {
+	gs := m.PopStmt().(*GoStmt)
+	numArgs := gs.Call.NumArgs
+	// Peek func to check it.
+	ftv := m.PeekValue(numArgs + 1)
+	switch ftv.V.(type) {
+	case *FuncValue, *BoundMethodValue:
+		m.startGoroutine(&gs.Call, numArgs)
+	case nil:
+		m.pushPanic(typedString("go of nil func value"))
+	default:
+		m.pushPanic(typedString(fmt.Sprintf("invalid go function call: %v", ftv.V)))
+	}
+}
+
 // Build exception string just as go, separated by \n\t.
 // TODO: deprecate UnhandledPanicError and just use the Exception.
 // (use a field to mark transaction abort)
@@ -320,6 +320,14 @@ func (m *Machine) doOpEval() {
 		// nodes may be more persistent than values in a tx.
 		// (currently all nodes are cached, but we don't want to cache
 		// all packages too).
+		if fv, ok := tv.V.(*FuncValue); ok {
+			if fd, ok := fv.Source.(*FuncDecl); ok && fd.HasAttribute(ATTR_GENERIC_INSTANCE) {
+				// instances of generic functions are not declared
+				// in a block; copy them, so that the value held by
+				// the node is never shared (e.g. across realms).
+				tv.V = fv.Copy(m.Alloc)
+			}
+		}
 		m.PushValue(tv)
 	case *constTypeExpr:
 		m.PopExpr()
@@ -28,6 +28,7 @@ ForStmt ->
 RangeStmt ->
   OpRangeIterList +block
   OpRangeIterMap +block
+  OpRangeIterChan +block
   OpRangeIterString +block
 
 IfStmt ->
@@ -39,7 +40,7 @@ SwitchStmt -> +block
   OpTypeSwitch
 
 SelectStmt ->
-  OpSelectCase +block
+  OpSelect -> +block
 
 */
 
@@ -428,12 +429,73 @@ func (m *Machine) doOpExec(op Op) {
 				panic("should not happen")
 			}
 		}
+	case OpRangeIterChan:
+		bs := s.(*bodyStmt)
+		xv := m.PeekValue(1)
+		switch bs.NextBodyIndex {
+		case -2: // init.
+			bs.NumOps = len(m.Ops)
+			bs.NumValues = len(m.Values)
+			bs.NumExprs = len(m.Exprs)
+			bs.NumStmts = len(m.Stmts)
+			bs.NextBodyIndex++
+			fallthrough
+		case -1: // receive and assign element.
+			ev, ok, done := m.recvOrPark(xv, op)
+			if !done {
+				return // parked; retried upon resume.
+			}
+			if !ok {
+				// channel closed: done with range.
+				m.PopFrameAndReset()
+				return
+			}
+			if bs.Key != nil {
+				switch bs.Op {
+				case ASSIGN:
+					m.PopAsPointer(bs.Key).Assign2(m.Alloc, m.Store, m.Realm, ev, false)
+				case DEFINE:
+					knx := bs.Key.(*NameExpr)
+					ptr := m.LastBlock().GetPointerToMaybeHeapDefine(m.Store, knx)
+					ptr.TV.Assign(m.Alloc, ev, false)
+				default:
+					panic("should not happen")
+				}
+			}
+			bs.NextBodyIndex++
+			fallthrough
+		default:
+			// NOTE: duplicated for OpRangeIter,
+			// but receives until the channel is closed.
+			if bs.NextBodyIndex < bs.BodyLen {
+				next := bs.Body[bs.NextBodyIndex]
+				bs.NextBodyIndex++
+				// continue onto exec stmt.
+				bs.Active = next
+				s = next // switch on bs.Active
+				goto EXEC_SWITCH
+			} else if bs.NextBodyIndex == bs.BodyLen {
+				// set up next assign if needed.
+				if bs.Op == ASSIGN && bs.Key != nil {
+					m.PushForPointer(bs.Key)
+				}
+				bs.ListIndex++
+				bs.NextBodyIndex = -1
+				bs.Active = nil
+				return // redo doOpExec:*bodyStmt
+			} else {
+				panic("should not happen")
+			}
+		}
 	}
 
 EXEC_SWITCH:
 	if debug {
 		debug.Printf("EXEC: %v\n", s)
 	}
+	if m.Coverage != nil {
+		m.Coverage.recordStmt(m, s)
+	}
 	switch cs := s.(type) {
 	case *AssignStmt:
 		switch cs.Op {
@@ -594,6 +656,8 @@ EXEC_SWITCH:
 			m.PushOp(OpRangeIterString)
 		} else if cs.IsArrayPtr {
 			m.PushOp(OpRangeIterArrayPtr)
+		} else if cs.IsChan {
+			m.PushOp(OpRangeIterChan)
 		} else {
 			m.PushOp(OpRangeIter)
 		}
@@ -626,7 +690,7 @@ EXEC_SWITCH:
 			for {
 				fr := m.LastFrame()
 				switch fr.Source.(type) {
-				case *ForStmt, *RangeStmt, *SwitchStmt:
+				case *ForStmt, *RangeStmt, *SelectStmt, *SwitchStmt:
 					if cs.Label != "" && cs.Label != fr.Label {
 						m.PopFrame()
 					} else {
@@ -726,6 +790,29 @@ EXEC_SWITCH:
 		// evaluate func
 		m.PushExpr(cs.Call.Func)
 		m.PushOp(OpEval)
+	case *GoStmt:
+		m.PushOp(OpGo)
+		// evaluate args
+		args := cs.Call.Args
+		for i := len(args) - 1; 0 <= i; i-- {
+			m.PushExpr(args[i])
+			m.PushOp(OpEval)
+		}
+		// evaluate func
+		m.PushExpr(cs.Call.Func)
+		m.PushOp(OpEval)
+	case *SendStmt:
+		m.PushOp(OpSend)
+		// evaluate value
+		m.PushExpr(cs.Value)
+		m.PushOp(OpEval)
+		// evaluate chan
+		m.PushExpr(cs.Chan)
+		m.PushOp(OpEval)
+	case *SelectStmt:
+		m.PushFrameBasic(cs)
+		m.PushOp(OpPopFrameAndReset)
+		m.PushOp(OpSelect)
 	case *SwitchStmt:
 		m.PushFrameBasic(cs)
 		m.PushOp(OpPopFrameAndReset)
@@ -975,3 +1062,175 @@ func (m *Machine) doOpSwitchClauseCase() {
 		}
 	}
 }
+
+func (m *Machine) doOpSend() {
+	tv := m.PeekValue(1)  // value to send
+	chv := m.PeekValue(2) // channel
+	if !m.sendOrPark(chv, *tv, OpSend) {
+		return // parked; retried upon resume.
+	}
+	m.PopStmt()
+	m.PopValue()
+	m.PopValue()
+}
+
+// selectCaseComm returns the channel expression of a select case, the value
+// expression if it sends, and the assignment if it receives into variables.
+// All are nil for the default case.
+func selectCaseComm(sc *SelectCaseStmt) (chx, valx Expr, as *AssignStmt) {
+	switch cs := sc.Comm.(type) {
+	case nil:
+		return nil, nil, nil
+	case *SendStmt:
+		return cs.Chan, cs.Value, nil
+	case *ExprStmt:
+		return cs.X.(*UnaryExpr).X, nil, nil
+	case *AssignStmt:
+		return cs.Rhs[0].(*UnaryExpr).X, nil, cs
+	default:
+		panic(fmt.Sprintf("unexpected select case %v", sc.Comm))
+	}
+}
+
+// doOpSelect first evaluates the channel (and value) expressions of each
+// case in source order, one case per run, each within its case block.
+// Then it proceeds with the first case ready to communicate, the default
+// case, or parks the goroutine until one of the channels is ready.
+func (m *Machine) doOpSelect() {
+	ss := m.PeekStmt1().(*SelectStmt)
+	numValues := len(m.Values) - m.LastFrame().NumValues
+	// Find the next case to evaluate, if any.
+	n := 0
+	for i := range ss.Cases {
+		sc := &ss.Cases[i]
+		if n == numValues {
+			chx, valx, _ := selectCaseComm(sc)
+			if chx == nil {
+				continue // default case
+			}
+			m.PushOp(OpSelect)
+			b := m.Alloc.NewBlock(sc, m.LastBlock())
+			m.PushBlock(b)
+			m.PushOp(OpPopBlock)
+			if valx != nil {
+				m.PushExpr(valx)
+				m.PushOp(OpEval)
+			}
+			m.PushExpr(chx)
+			m.PushOp(OpEval)
+			return
+		}
+		if chx, valx, _ := selectCaseComm(sc); chx != nil {
+			n++
+			if valx != nil {
+				n++
+			}
+		}
+	}
+	// All cases were evaluated.
+	vals := m.Values[len(m.Values)-numValues:]
+	chosen := -1
+	var rv TypedValue
+	var ok bool
+	if w := m.takeWoken(); w != nil {
+		// Woken by another goroutine.
+		chosen, rv, ok = w.index, w.value, w.ok
+		if _, valx, _ := selectCaseComm(&ss.Cases[chosen]); valx != nil && !ok {
+			panic(&Exception{Value: typedString("send on closed channel")})
+		}
+	} else {
+		var chs []*ChanValue
+		var sends []bool
+		var svs []TypedValue
+		var indices []int
+		dflt := -1
+		j := 0
+		for i := range ss.Cases {
+			sc := &ss.Cases[i]
+			chx, valx, _ := selectCaseComm(sc)
+			if chx == nil {
+				dflt = i
+				continue
+			}
+			chv := vals[j]
+			ch, _ := chv.V.(*ChanValue)
+			j++
+			if valx != nil {
+				sv := vals[j]
+				j++
+				if ch == nil {
+					continue // never ready
+				}
+				if ch.canSend() {
+					m.chanSend(ch, sv)
+					chosen = i
+					break
+				}
+				chs = append(chs, ch)
+				sends = append(sends, true)
+				svs = append(svs, sv)
+				indices = append(indices, i)
+			} else {
+				if ch == nil {
+					continue // never ready
+				}
+				if ch.canRecv() {
+					rv, ok = m.chanRecv(ch, baseOf(chv.T).(*ChanType).Elt)
+					chosen = i
+					break
+				}
+				chs = append(chs, ch)
+				sends = append(sends, false)
+				svs = append(svs, TypedValue{})
+				indices = append(indices, i)
+			}
+		}
+		if chosen < 0 {
+			if dflt < 0 {
+				// Block until a case can proceed.
+				m.PushOp(OpSelect)
+				if len(chs) == 0 {
+					m.parkGoroutine() // forever
+				} else {
+					m.parkOnChans(chs, sends, svs, indices)
+				}
+				return
+			}
+			chosen = dflt
+		}
+	}
+	// Proceed with the chosen case.
+	m.PopStmt()
+	m.PopValues(numValues)
+	sc := &ss.Cases[chosen]
+	b := m.Alloc.NewBlock(sc, m.LastBlock())
+	m.PushBlock(b)
+	m.PushOp(OpPopBlock)
+	// exec case body
+	b.bodyStmt = bodyStmt{
+		Body:          sc.Body,
+		BodyLen:       len(sc.Body),
+		NextBodyIndex: -2,
+	}
+	m.PushOp(OpBody)
+	m.PushStmt(b.GetBodyStmt())
+	// assign received values
+	if _, _, as := selectCaseComm(sc); as != nil {
+		m.PushStmt(as)
+		if as.Op == DEFINE {
+			m.PushOp(OpDefine)
+		} else {
+			m.PushOp(OpAssign)
+		}
+		rvs := []TypedValue{rv, untypedBool(ok)}[:len(as.Lhs)]
+		for i := len(rvs) - 1; 0 <= i; i-- {
+			m.PushExpr(&ConstExpr{TypedValue: rvs[i]})
+			m.PushOp(OpEval)
+		}
+		if as.Op != DEFINE {
+			for i := len(as.Lhs) - 1; 0 <= i; i-- {
+				m.PushForPointer(as.Lhs[i])
+			}
+		}
+	}
+}
@@ -427,8 +427,25 @@ func (m *Machine) doOpStaticTypeOf() {
 			m.PushOp(OpEval)
 		}
 	case *UnaryExpr:
-		m.PushExpr(x.X)
-		m.PushOp(OpStaticTypeOf)
+		if x.Op == ARROW {
+			if x.HasOK {
+				panic("channel receive used with return 2 values; has no type")
+			}
+			start := len(m.Values)
+			m.PushOp(OpHalt)
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+			m.Run(StageRun)
+			xt := m.ReapValues(start)[0].GetType()
+			if ct, ok := baseOf(xt).(*ChanType); ok {
+				m.PushValue(asValue(ct.Elt))
+			} else {
+				panic("unexpected receive expression")
+			}
+		} else {
+			m.PushExpr(x.X)
+			m.PushOp(OpStaticTypeOf)
+		}
 	case *CompositeLitExpr:
 		m.PushExpr(x.Type)
 		m.PushOp(OpEval)
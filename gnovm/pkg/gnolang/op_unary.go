@@ -122,5 +122,18 @@ func (m *Machine) doOpUxor() {
 }
 
 func (m *Machine) doOpUrecv() {
-	panic("not yet implemented")
+	ux := m.PeekExpr(1).(*UnaryExpr)
+	if debug {
+		debug.Printf("doOpUrecv(%v)\n", ux)
+	}
+	xv := m.PeekValue(1)
+	rv, ok, done := m.recvOrPark(xv, OpUrecv)
+	if !done {
+		return // parked; retried upon resume.
+	}
+	m.PopExpr()
+	*xv = rv // reuse as result
+	if ux.HasOK {
+		m.PushValue(untypedBool(ok))
+	}
 }
@@ -252,7 +252,9 @@ func initStaticBlocks(store Store, ctx BlockNode, nn Node) {
 						// NOTE: document somewhere.
 						n.Recv.Name = ".recv"
 					}
-				} else {
+				} else if !n.HasAttribute(ATTR_GENERIC_INSTANCE) {
+					// NOTE: instances of generic functions are not
+					// declared in the package block.
 					pkg := skipFile(last).(*PackageNode)
 					// special case: if n.Name == "init", assign unique suffix.
 					switch n.Name {
@@ -300,6 +302,29 @@ func initStaticBlocks(store Store, ctx BlockNode, nn Node) {
 		case TRANS_BLOCK:
 			pushInitBlock(n.(BlockNode), &last, &stack)
 			switch n := n.(type) {
+			case *FileNode:
+				// Generic declarations are not transcribed, but
+				// their names are reserved so that they can be
+				// resolved (and shadowed); see lookupGeneric.
+				pkg := skipFile(last)
+				for _, d := range n.Decls {
+					if !isGenericDecl(d) {
+						continue
+					}
+					switch d := d.(type) {
+					case *TypeDecl:
+						nx := &d.NameExpr
+						nx.Type = NameExprTypeDefine
+						pkg.Reserve(true, nx, d, NSTypeDecl, -1)
+					case *FuncDecl:
+						if d.IsMethod {
+							continue
+						}
+						nx := &d.NameExpr
+						nx.Type = NameExprTypeDefine
+						pkg.Reserve(false, nx, d, NSFuncDecl, -1)
+					}
+				}
 			case *IfCaseStmt:
 				// parent if statement.
 				ifs := ns[len(ns)-1].(*IfStmt)
@@ -673,6 +698,18 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 					}
 					xt = xt.Elem()
 					n.IsArrayPtr = true
+				case ChanKind:
+					if baseOf(xt).(*ChanType).Dir == SEND {
+						panic(fmt.Sprintf(
+							"cannot range over %s: receive from send-only channel %s",
+							n.X, xt))
+					}
+					if n.Value != nil {
+						panic(fmt.Sprintf(
+							"range over %s permits only one iteration variable",
+							n.X))
+					}
+					n.IsChan = true
 				}
 				// key value if define.
 				if n.Op == DEFINE {
@@ -687,6 +724,12 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 							vn := n.Value.(*NameExpr).Name
 							last.Define(vn, anyValue(vt))
 						}
+					} else if xt.Kind() == ChanKind {
+						if n.Key != nil {
+							et := baseOf(xt).(*ChanType).Elt
+							kn := n.Key.(*NameExpr).Name
+							last.Define(kn, anyValue(et))
+						}
 					} else if xt.Kind() == StringKind {
 						if n.Key != nil {
 							it := IntType
@@ -1110,6 +1153,11 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 						fillNameExprPath(last, n, true)
 						return n, TRANS_CONTINUE
 					default:
+						// Generic declarations are instantiated by
+						// the parent index or call expression.
+						if ref := lookupGeneric(last, n.Name); ref != nil {
+							return genericMarker(n, ftype, ref), TRANS_CONTINUE
+						}
 						fillNameExprPath(last, n, false)
 					}
 					// If uverse, return a *ConstExpr.
@@ -1328,6 +1376,10 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				}
 			// TRANS_LEAVE -----------------------
 			case *CallExpr:
+				// Instantiate generic func with inferred type args.
+				if ref := genericRefOf(n.Func); ref != nil {
+					n.Func = inferFunc(store, last, n, ref)
+				}
 				// Func type evaluation.
 				nft := evalStaticTypeOf(store, last, n.Func)
 				switch bnft := baseOf(nft).(type) {
@@ -1595,6 +1647,21 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 									n.Args[1] = args1
 								}
 							}
+						} else if fv.PkgPath == uversePkgPath && fv.Name == "close" {
+							if len(n.Args) == 1 {
+								at := evalStaticTypeOf(store, last, n.Args[0])
+								ct, ok := baseOf(at).(*ChanType)
+								if !ok {
+									panic(fmt.Sprintf(
+										"invalid operation: non-chan argument %s (variable of type %s)",
+										n.Args[0], at))
+								}
+								if ct.Dir == RECV {
+									panic(fmt.Sprintf(
+										"invalid operation: cannot close receive-only channel %s",
+										n.Args[0]))
+								}
+							}
 						} else if fv.PkgPath == uversePkgPath && fv.Name == "cross" {
 							panic("cross(fn)(...) syntax is deprecated, use fn(cross,...)")
 						} else if fv.PkgPath == uversePkgPath && fv.Name == "_cross_gno0p0" {
@@ -1853,8 +1920,19 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 						nft, reflect.TypeOf(nft)))
 				}
 
+			// TRANS_LEAVE -----------------------
+			case *IndexListExpr:
+				ref := genericRefOf(n.X)
+				if ref == nil {
+					panic("invalid operation: more than one index")
+				}
+				return instantiateExpr(store, last, n, ftype, ref, n.Indices), TRANS_CONTINUE
+
 			// TRANS_LEAVE -----------------------
 			case *IndexExpr:
+				if ref := genericRefOf(n.X); ref != nil {
+					return instantiateExpr(store, last, n, ftype, ref, Exprs{n.Index}), TRANS_CONTINUE
+				}
 				dt := evalStaticTypeOf(store, last, n.X)
 				if dt.Kind() == PointerKind {
 					// if a is a pointer to an array,
@@ -2125,6 +2203,9 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 						panic(fmt.Sprintf("cannot access %s.%s from %s",
 							pv.PkgPath, n.Sel, ctxpn.PkgPath))
 					}
+					if ref := packageGenericRef(pn, n.Sel); ref != nil {
+						return genericMarker(n, ftype, ref), TRANS_CONTINUE
+					}
 					// NOTE: this can happen with software upgrades,
 					// with multiple versions of the same package path.
 					n.Path = pn.GetPathForName(store, n.Sel)
@@ -2485,8 +2566,25 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 
 			// TRANS_LEAVE -----------------------
 			case *SendStmt:
-				// Value consts become default *ConstExprs.
-				checkOrConvertType(store, last, n, &n.Value, nil, false)
+				ct, ok := baseOf(evalStaticTypeOf(store, last, n.Chan)).(*ChanType)
+				if !ok {
+					panic(fmt.Sprintf(
+						"invalid operation: cannot send to non-channel %s",
+						n.Chan.String()))
+				}
+				if ct.Dir&SEND == 0 {
+					panic(fmt.Sprintf(
+						"invalid operation: cannot send to receive-only channel %s",
+						n.Chan.String()))
+				}
+				// Value consts become *ConstExprs of the element type.
+				checkOrConvertType(store, last, n, &n.Value, ct.Elt, false)
+
+			// TRANS_LEAVE -----------------------
+			case *GoStmt:
+				if n.Call.IsWithCross() {
+					panic("cannot cross-call a function in a go statement")
+				}
 
 			// TRANS_LEAVE -----------------------
 			case *SelectCaseStmt:
@@ -2620,6 +2718,13 @@ func preprocess1(store Store, ctx BlockNode, n Node) Node {
 				// Replace the type with *{},
 				// otherwise methods would be un at runtime.
 				n.Type = toConstTypeExpr(last, n.Type, dstT)
+
+			// TRANS_LEAVE -----------------------
+			case *FileNode:
+				// Preprocess the bodies of the instances of generic
+				// declarations of this file, now that all of its
+				// dependencies are defined.
+				preprocessPendingInstances(store, n)
 			}
 			// end type switch statement
 			// END TRANS_LEAVE -----------------------
@@ -2768,10 +2873,12 @@ func parseAssignFromExprList(
 // - var a, b, c T = f()
 // - var a, b = n.(T)
 // - var a, b = n[i], where n is a map
+// - var a, b = <-ch
 // Assign:
 // - a, b, c := f()
 // - a, b := n.(T)
 // - a, b := n[i], where n is a map
+// - a, b := <-ch
 func parseMultipleAssignFromOneExpr(
 	store Store,
 	bn BlockNode,
@@ -2810,6 +2917,20 @@ func parseMultipleAssignFromOneExpr(
 		}
 		tuple = &tupleType{Elts: []Type{mt.Value, BoolType}}
 		expr.HasOK = true
+	case *UnaryExpr:
+		// Channel receive case:
+		// var a, b = <-ch
+		// a, b := <-ch
+		if expr.Op != ARROW {
+			panic(fmt.Sprintf("unexpected unary expression %s", expr.String()))
+		}
+		dt := evalStaticTypeOf(store, bn, expr.X)
+		ct, ok := baseOf(dt).(*ChanType)
+		if !ok {
+			panic(fmt.Sprintf("invalid receive expression on %T", dt))
+		}
+		tuple = &tupleType{Elts: []Type{ct.Elt, BoolType}}
+		expr.HasOK = true
 	default:
 		panic(fmt.Sprintf("unexpected value expression type %T", expr))
 	}
@@ -3467,20 +3588,24 @@ func findPackageSelectors(bn BlockNode) {
 	})
 }
 
+// isSwitchLabel returns true if label names an enclosing switch or select
+// statement.
 func isSwitchLabel(ns []Node, label Name) bool {
-	for {
-		swch := lastSwitch(ns)
-		if swch == nil {
-			break
-		}
-
-		if swch.GetLabel() == label && label != "" {
-			return true
+	if label == "" {
+		return false
+	}
+	for i := len(ns) - 1; 0 <= i; i-- {
+		switch n := ns[i].(type) {
+		case *SwitchStmt:
+			if n.GetLabel() == label {
+				return true
+			}
+		case *SelectStmt:
+			if n.GetLabel() == label {
+				return true
+			}
 		}
-
-		ns = ns[:len(ns)-1]
 	}
-
 	return false
 }
 
@@ -3907,6 +4032,8 @@ func findBreakableNode(last BlockNode, store Store) {
 			return
 		case *SwitchClauseStmt:
 			return
+		case *SelectCaseStmt:
+			return
 		}
 
 		last = last.GetParentNode(store)
@@ -3991,7 +4118,7 @@ func findGotoLabel(last BlockNode, label Name) (
 					"cannot find GOTO label %q within current function",
 					label))
 			}
-		case *ForStmt, *RangeStmt, *SwitchClauseStmt:
+		case *ForStmt, *RangeStmt, *SelectCaseStmt, *SwitchClauseStmt:
 			body := cbn.GetBody()
 			_, bodyIdx = body.GetLabeledStmt(label)
 			if bodyIdx != -1 {
@@ -4002,7 +4129,7 @@ func findGotoLabel(last BlockNode, label Name) (
 				frameDepth += 1
 				blockDepth = 0 // reset
 			}
-		case *IfCaseStmt, *SelectCaseStmt, *BlockStmt:
+		case *IfCaseStmt, *BlockStmt:
 			body := cbn.GetBody()
 			_, bodyIdx = body.GetLabeledStmt(label)
 			if bodyIdx != -1 {
@@ -4296,6 +4423,10 @@ func findUndefinedAny(store Store, last BlockNode, x Expr, stack []Name, definin
 		if _, ok := UverseNode().GetLocalIndex(cx.Name); ok {
 			return
 		}
+		if lookupGeneric(last, cx.Name) != nil {
+			// only instances of generic declarations get defined.
+			return
+		}
 		/*
 			if _, ok := defining[cx.Name]; !ok {
 				return cx.Name
@@ -4532,6 +4663,17 @@ func findUndefinedAny(store Store, last BlockNode, x Expr, stack []Name, definin
 		if un != "" {
 			return
 		}
+	case *IndexListExpr:
+		un, directR = findUndefinedV(store, last, cx.X, stack, defining, direct, nil)
+		if un != "" {
+			return
+		}
+		for _, idx := range cx.Indices {
+			un, directR = findUndefinedV(store, last, idx, stack, defining, direct, nil)
+			if un != "" {
+				return
+			}
+		}
 	case *constTypeExpr:
 		return
 	case *ConstExpr:
@@ -4621,6 +4763,13 @@ func predefineRecursively(store Store, last BlockNode, d Decl) bool {
 func predefineRecursively2(store Store, last BlockNode, d Decl, stack []Name, defining map[Name]struct{}, direct bool) bool {
 	pkg := packageOf(last)
 
+	// Generic declarations are templates, only their instances get
+	// predefined; see generics.go.
+	if isGenericDecl(d) {
+		d.SetAttribute(ATTR_PREDEFINED, true)
+		return false
+	}
+
 	// NOTE: predefine fileset breaks up circular definitions like
 	// `var a, b, c = 1, a, b` which is only legal at the file level.
 	for _, dn := range d.GetDeclNames() {
@@ -4878,6 +5027,15 @@ func tryPredefine(store Store, pkg *PackageNode, last BlockNode, d Decl, stack [
 				tx.Path = pn.GetPathForName(store, tx.Sel)
 				ptr := pv.GetBlock(store).GetPointerTo(store, tx.Path)
 				t = ptr.TV.GetType()
+			case *IndexExpr, *IndexListExpr:
+				// instance of a generic type.
+				un, directR = findUndefinedT(store, last, tx, stack, defining, d.IsAlias, direct)
+				if un != "" {
+					untype = true
+					return
+				}
+				d.Type = Preprocess(store, last, tx).(Expr)
+				t = evalStaticType(store, last, d.Type)
 			default:
 				panic(fmt.Sprintf(
 					"unexpected type declaration type %v",
@@ -5341,6 +5499,11 @@ func findDependentNames(n Node, dst map[Name]struct{}) {
 	case *IndexExpr:
 		findDependentNames(cn.X, dst)
 		findDependentNames(cn.Index, dst)
+	case *IndexListExpr:
+		findDependentNames(cn.X, dst)
+		for _, idx := range cn.Indices {
+			findDependentNames(idx, dst)
+		}
 	case *FuncLitExpr:
 		findDependentNames(&cn.Type, dst)
 		for _, n := range cn.GetExternNames() {
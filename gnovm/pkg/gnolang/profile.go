@@ -0,0 +1,321 @@
+package gnolang
+
+import (
+	"encoding/binary"
+	"sort"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// Profiler attributes the CPU cycles and the gas consumed while executing
+// Gno code to the Gno call stack which consumed them.
+//
+// CPU cycles are recorded for any [Machine] whose Profiler field points to
+// this value. Gas is recorded through the gas meter returned by
+// [Profiler.GasMeter], which should be used both by the Machines and by
+// their Store: this way, the gas used by the store to read and write objects
+// (see [GasGetObjectDesc] and [GasSetObjectDesc]) is attributed to the
+// function which caused the store access, like the gas of the CPU cycles.
+// The executed ops are counted as well, along with their CPU cycles.
+//
+// Profiler is not safe for concurrent use; it is meant to be shared by the
+// Machines of a single (sequential) run.
+type Profiler struct {
+	frames   []ProfileFrame
+	frameIDs map[ProfileFrame]int
+	cycles   map[profileKey]int64
+	gas      map[profileKey]int64
+	ops      map[Op]*ProfileOp
+	op       *ProfileOp // last op executed
+
+	// Call stack of the last Machine which executed an op, cached as it
+	// only changes on function calls and returns.
+	m       *Machine
+	calls   []profileCall // call frames, outermost first
+	top     int           // index of the innermost call frame in m.Frames, or -1
+	fn      ProfileFrame  // function of the innermost call frame
+	callers string        // frame IDs of the callers, innermost first
+}
+
+// ProfileFrame is a location in a call stack of the profile.
+type ProfileFrame struct {
+	PkgPath   string
+	File      string
+	Func      string // function name, qualified by its receiver for methods
+	StartLine int    // line of the function declaration
+	Line      int    // line being executed
+}
+
+// ProfileSample is the amount of CPU cycles or gas consumed by a call stack.
+type ProfileSample struct {
+	Stack      []ProfileFrame // innermost call first
+	Descriptor string         // kind of gas consumed, for gas samples
+	Value      int64
+}
+
+// ProfileOp is the number of executions of an op, and the CPU cycles
+// consumed while executing it.
+type ProfileOp struct {
+	Op     Op
+	Count  int64
+	Cycles int64
+}
+
+type profileCall struct {
+	fv     *FuncValue
+	source Node
+}
+
+type profileKey struct {
+	leaf    int    // frame ID of the innermost call
+	callers string // see Profiler.callers
+	desc    string // gas descriptor
+}
+
+// NewProfiler returns a new, empty Profiler.
+func NewProfiler() *Profiler {
+	return &Profiler{
+		frameIDs: make(map[ProfileFrame]int),
+		cycles:   make(map[profileKey]int64),
+		gas:      make(map[profileKey]int64),
+		ops:      make(map[Op]*ProfileOp),
+		top:      -1,
+	}
+}
+
+// GasMeter returns a gas meter recording the gas consumed through gm,
+// attributed to the call stack of the last Machine which executed an op.
+func (p *Profiler) GasMeter(gm store.GasMeter) store.GasMeter {
+	return &profilerGasMeter{GasMeter: gm, p: p}
+}
+
+type profilerGasMeter struct {
+	store.GasMeter
+	p *Profiler
+}
+
+func (gm *profilerGasMeter) ConsumeGas(amount store.Gas, descriptor string) {
+	// Record before consuming, as it panics when running out of gas.
+	gm.p.recordGas(amount, descriptor)
+	gm.GasMeter.ConsumeGas(amount, descriptor)
+}
+
+// CPUSamples returns the CPU cycles recorded by the Profiler.
+func (p *Profiler) CPUSamples() []ProfileSample {
+	return p.samples(p.cycles)
+}
+
+// GasSamples returns the gas recorded by the Profiler.
+func (p *Profiler) GasSamples() []ProfileSample {
+	return p.samples(p.gas)
+}
+
+// Ops returns the ops executed, by decreasing CPU cycles.
+func (p *Profiler) Ops() []ProfileOp {
+	ops := make([]ProfileOp, 0, len(p.ops))
+	for _, op := range p.ops {
+		ops = append(ops, *op)
+	}
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Cycles != ops[j].Cycles {
+			return ops[i].Cycles > ops[j].Cycles
+		}
+		return ops[i].Op < ops[j].Op
+	})
+	return ops
+}
+
+func (p *Profiler) samples(values map[profileKey]int64) []ProfileSample {
+	keys := make([]profileKey, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.callers != b.callers {
+			return a.callers < b.callers
+		}
+		if a.leaf != b.leaf {
+			return a.leaf < b.leaf
+		}
+		return a.desc < b.desc
+	})
+	samples := make([]ProfileSample, len(keys))
+	for i, k := range keys {
+		stack := []ProfileFrame{p.frames[k.leaf]}
+		for callers := []byte(k.callers); len(callers) > 0; {
+			id, n := binary.Uvarint(callers)
+			stack = append(stack, p.frames[id])
+			callers = callers[n:]
+		}
+		samples[i] = ProfileSample{Stack: stack, Descriptor: k.desc, Value: values[k]}
+	}
+	return samples
+}
+
+// recordOp is called by the Machine before executing op.
+func (p *Profiler) recordOp(op Op) {
+	p.op = p.ops[op]
+	if p.op == nil {
+		p.op = &ProfileOp{Op: op}
+		p.ops[op] = p.op
+	}
+	p.op.Count++
+}
+
+// recordCycles is called by the Machine when consuming CPU cycles, which
+// are attributed to the last op executed.
+func (p *Profiler) recordCycles(m *Machine, cycles int64) {
+	p.cycles[p.key(m, "")] += cycles
+	if p.op != nil {
+		p.op.Cycles += cycles
+	}
+}
+
+func (p *Profiler) recordGas(gas int64, desc string) {
+	if p.m == nil {
+		// No Gno code executed yet, e.g. while loading imports.
+		p.gas[profileKey{leaf: p.frameID(ProfileFrame{Func: "(unattributed)"}), desc: desc}] += gas
+		return
+	}
+	p.gas[p.key(p.m, desc)] += gas
+}
+
+// release is called when m is released, so that it is not used anymore to
+// attribute gas.
+func (p *Profiler) release(m *Machine) {
+	if p.m == m {
+		p.m = nil
+		p.calls = p.calls[:0]
+	}
+}
+
+// key returns the key of the samples for the current call stack of m.
+func (p *Profiler) key(m *Machine, desc string) profileKey {
+	p.update(m)
+	leaf := p.fn
+	leaf.Line = p.line(m)
+	return profileKey{leaf: p.frameID(leaf), callers: p.callers, desc: desc}
+}
+
+func (p *Profiler) frameID(f ProfileFrame) int {
+	id, ok := p.frameIDs[f]
+	if !ok {
+		id = len(p.frames)
+		p.frames = append(p.frames, f)
+		p.frameIDs[f] = id
+	}
+	return id
+}
+
+// update refreshes the cached call stack, if the call frames of m changed.
+func (p *Profiler) update(m *Machine) {
+	n, top := 0, -1
+	valid := p.m == m
+	for i := range m.Frames {
+		fr := &m.Frames[i]
+		if !fr.IsCall() {
+			continue
+		}
+		if valid && (n >= len(p.calls) || p.calls[n] != (profileCall{fr.Func, fr.Source})) {
+			valid = false
+		}
+		n++
+		top = i
+	}
+	p.top = top
+	if valid && n == len(p.calls) {
+		return
+	}
+
+	p.m = m
+	p.calls = p.calls[:0]
+	for i := range m.Frames {
+		if fr := &m.Frames[i]; fr.IsCall() {
+			p.calls = append(p.calls, profileCall{fr.Func, fr.Source})
+		}
+	}
+	if top < 0 {
+		// Package level code, like the initialization of variables.
+		p.fn = ProfileFrame{Func: "init"}
+		if m.Package != nil {
+			p.fn.PkgPath = m.Package.PkgPath
+		}
+		p.callers = ""
+		return
+	}
+	var callers []byte
+	line := 0
+	for i := top; i >= 0; i-- {
+		fr := &m.Frames[i]
+		if !fr.IsCall() {
+			continue
+		}
+		f := profileFunc(m, fr.Func)
+		if i == top {
+			p.fn = f
+		} else {
+			f.Line = line
+			callers = binary.AppendUvarint(callers, uint64(p.frameID(f)))
+		}
+		if fr.Source != nil {
+			line = fr.Source.GetLine()
+		}
+	}
+	p.callers = string(callers)
+}
+
+// line returns the line being executed in the innermost call frame.
+func (p *Profiler) line(m *Machine) int {
+	var nx, ns int
+	if p.top >= 0 {
+		fr := &m.Frames[p.top]
+		nx, ns = fr.NumExprs, fr.NumStmts
+	}
+	for i := len(m.Exprs) - 1; i >= nx; i-- {
+		if l := m.Exprs[i].GetLine(); l > 0 {
+			return l
+		}
+	}
+	if len(m.Stmts) > ns {
+		if s := m.PeekStmt1(); s != nil {
+			if l := s.GetLine(); l > 0 {
+				return l
+			}
+		}
+	}
+	return p.fn.StartLine
+}
+
+// profileFunc returns the frame of fv, without the line being executed.
+func profileFunc(m *Machine, fv *FuncValue) ProfileFrame {
+	f := ProfileFrame{
+		PkgPath: fv.PkgPath,
+		File:    fv.FileName,
+		Func:    string(fv.Name),
+	}
+	if src := fv.GetSource(m.Store); src != nil {
+		f.StartLine = src.GetLine()
+	}
+	switch {
+	case fv.IsClosure:
+		f.Func = "func@" + strconv.Itoa(f.StartLine)
+	case fv.IsMethod:
+		if ft, ok := fv.Type.(*FuncType); ok && len(ft.Params) > 0 {
+			f.Func = profileRecvName(ft.Params[0].Type) + "." + f.Func
+		}
+	}
+	return f
+}
+
+func profileRecvName(t Type) string {
+	switch t := t.(type) {
+	case *PointerType:
+		return "(*" + profileRecvName(t.Elt) + ")"
+	case *DeclaredType:
+		return string(t.Name)
+	default:
+		return t.String()
+	}
+}
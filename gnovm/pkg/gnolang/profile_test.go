@@ -0,0 +1,107 @@
+package gnolang
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	const src = `package prof
+
+type T struct{ n int }
+
+func (t *T) Add(x int) {
+	t.n += x
+}
+
+func Sum(n int) int {
+	t := &T{}
+	for i := 0; i < n; i++ {
+		t.Add(i)
+	}
+	double := func() int {
+		return t.n * 2
+	}
+	return double()
+}
+`
+	p := NewProfiler()
+	gm := p.GasMeter(store.NewInfiniteGasMeter())
+	m := NewMachineWithOptions(MachineOptions{
+		PkgPath:  "gno.land/p/prof",
+		Profiler: p,
+		GasMeter: gm,
+	})
+	defer m.Release()
+	m.RunMemPackage(&std.MemPackage{
+		Type:  MPUserProd,
+		Name:  "prof",
+		Path:  "gno.land/p/prof",
+		Files: []*std.MemFile{{Name: "prof.gno", Body: src}},
+	}, false)
+	m.Eval(Call(X("Sum"), 10))
+
+	// Total CPU cycles, and per innermost function.
+	var total int64
+	byFunc := map[string]int64{}
+	stacks := map[string]bool{}
+	addLines := map[int]bool{}
+	for _, s := range p.CPUSamples() {
+		require.NotEmpty(t, s.Stack)
+		total += s.Value
+		byFunc[s.Stack[0].Func] += s.Value
+		if s.Stack[0].Func == "(*T).Add" {
+			assert.Equal(t, "prof.gno", s.Stack[0].File)
+			assert.Equal(t, 5, s.Stack[0].StartLine)
+			addLines[s.Stack[0].Line] = true
+			require.Len(t, s.Stack, 2)
+			assert.Equal(t, ProfileFrame{
+				PkgPath:   "gno.land/p/prof",
+				File:      "prof.gno",
+				Func:      "Sum",
+				StartLine: 9,
+				Line:      12,
+			}, s.Stack[1])
+		}
+		if s.Stack[0].Func == "func@14" {
+			require.Len(t, s.Stack, 2)
+			assert.Equal(t, 17, s.Stack[1].Line)
+		}
+		for _, f := range s.Stack {
+			stacks[f.Func] = true
+		}
+	}
+	assert.Equal(t, m.Cycles, total)
+	assert.Greater(t, byFunc["(*T).Add"], int64(0))
+	assert.Greater(t, byFunc["Sum"], int64(0))
+	assert.Greater(t, byFunc["func@14"], int64(0))
+	assert.True(t, stacks["init"])
+	assert.True(t, addLines[6])
+
+	// Gas of the CPU cycles, through the gas meter.
+	var gas int64
+	for _, s := range p.GasSamples() {
+		if s.Descriptor == "CPUCycles" {
+			gas += s.Value
+		}
+	}
+	assert.Equal(t, m.Cycles*GasFactorCPU, gas)
+	assert.Equal(t, gm.GasConsumed(), gas)
+
+	// Ops, by decreasing CPU cycles.
+	ops := p.Ops()
+	require.NotEmpty(t, ops)
+	var opCycles int64
+	for i, op := range ops {
+		assert.Greater(t, op.Count, int64(0))
+		if i > 0 {
+			assert.LessOrEqual(t, op.Cycles, ops[i-1].Cycles)
+		}
+		opCycles += op.Cycles
+	}
+	assert.LessOrEqual(t, opCycles, m.Cycles)
+}
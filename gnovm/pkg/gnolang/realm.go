@@ -876,6 +876,17 @@ func (rlm *Realm) clearMarks() {
 	rlm.escaped = nil
 }
 
+//----------------------------------------
+// ChanPersistError
+
+// ChanPersistError is raised when finalizing a realm whose objects reach a
+// channel value, as channels cannot be persisted.
+type ChanPersistError struct{}
+
+func (ChanPersistError) Error() string {
+	return "cannot persist channel values"
+}
+
 //----------------------------------------
 // getSelfOrChildObjects
 
@@ -963,6 +974,8 @@ func getChildObjects(val Value, more []Value) []Value {
 	case *HeapItemValue:
 		more = getSelfOrChildObjects(cv.Value.V, more)
 		return more
+	case *ChanValue:
+		panic(ChanPersistError{})
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
@@ -1301,6 +1314,8 @@ func copyValueWithRefs(val Value) Value {
 			Value:      refOrCopyValue(cv.Value),
 		}
 		return hiv
+	case *ChanValue:
+		panic(ChanPersistError{})
 	default:
 		panic(fmt.Sprintf(
 			"unexpected type %v",
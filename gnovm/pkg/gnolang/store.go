@@ -1,6 +1,7 @@
 package gnolang
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"iter"
@@ -18,7 +19,13 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/utils"
 	stringz "github.com/gnolang/gno/tm2/pkg/strings"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
 	"github.com/pmezard/go-difflib/difflib"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // PackageGetter specifies how the store may retrieve packages which are not
@@ -78,6 +85,7 @@ type Store interface {
 	SetNativeResolver(NativeResolver)                     // for native functions
 	GetNative(pkgPath string, name Name) func(m *Machine) // for native functions
 	SetLogStoreOps(dst io.Writer)
+	SetTraceContext(ctx context.Context)
 	LogFinalizeRealm(rlmpath string) // to mark finalization of realm boundaries
 	Print()
 }
@@ -154,8 +162,9 @@ type defaultStore struct {
 	nativeResolver NativeResolver // for injecting natives
 
 	// transient
-	opslog  io.Writer // for logging store operations.
-	current []string  // for detecting import cycles.
+	opslog   io.Writer       // for logging store operations.
+	current  []string        // for detecting import cycles.
+	traceCtx context.Context // for tracing the object loads.
 
 	// gas
 	gasMeter  store.GasMeter
@@ -434,7 +443,9 @@ func (ds *defaultStore) GetObjectSafe(oid ObjectID) Object {
 		defer bm.ResumeOpCode()
 	}
 	// check cache.
-	if oo, exists := ds.cacheObjects[oid]; exists {
+	oo, exists := ds.cacheObjects[oid]
+	logObjectCacheTelemetry(exists)
+	if exists {
 		return oo
 	}
 	// check baseStore.
@@ -446,6 +457,21 @@ func (ds *defaultStore) GetObjectSafe(oid ObjectID) Object {
 	return nil
 }
 
+// logObjectCacheTelemetry logs an object cache lookup, as a hit or a miss
+func logObjectCacheTelemetry(hit bool) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.VMObjectCacheLookups.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(
+			attribute.Bool("hit", hit),
+		),
+	)
+}
+
 // loads and caches an object.
 // CONTRACT: object isn't already in the cache.
 func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
@@ -462,6 +488,17 @@ func (ds *defaultStore) loadObjectSafe(oid ObjectID) Object {
 			bm.StopStore(size)
 		}()
 	}
+	// Only trace the loads within a recording span,
+	// not to export the loads of the queries for instance
+	if trace.SpanFromContext(ds.traceCtx).IsRecording() {
+		_, span := traces.Tracer().Start(
+			ds.traceCtx,
+			"gno/LoadObject",
+			trace.WithAttributes(attribute.String("gno.object_id", oid.String())),
+		)
+		defer span.End()
+	}
+
 	key := backendObjectKey(oid)
 	hashbz := ds.baseStore.Get([]byte(key))
 	if hashbz != nil {
@@ -849,6 +886,12 @@ func (ds *defaultStore) GetPackageNode(pkgPath string) *PackageNode {
 
 func (ds *defaultStore) GetBlockNode(loc Location) BlockNode {
 	bn := ds.GetBlockNodeSafe(loc)
+	if bn == nil && loc.Inst != "" {
+		// the nodes of generic instances are recreated from their
+		// template, see restoreInstance.
+		restoreInstance(ds, loc)
+		bn = ds.GetBlockNodeSafe(loc)
+	}
 	if bn == nil {
 		panic(fmt.Sprintf("unexpected node with location %s", loc.String()))
 	}
@@ -1117,6 +1160,13 @@ func (ds *defaultStore) ClearObjectCache() {
 	ds.SetCachePackage(Uverse())
 }
 
+// Unstable.
+// This function is used to set the trace context of every message, whose span
+// is the parent of the object load spans.
+func (ds *defaultStore) SetTraceContext(ctx context.Context) {
+	ds.traceCtx = ctx
+}
+
 func (ds *defaultStore) GarbageCollectObjectCache(gcCycle int64) {
 	for objId, obj := range ds.cacheObjects {
 		// Skip .uverse packages.
@@ -148,6 +148,7 @@ func _() {
 	_ = x[OpDefine-140]
 	_ = x[OpInc-141]
 	_ = x[OpDec-142]
+	_ = x[OpSend-143]
 	_ = x[OpValueDecl-144]
 	_ = x[OpTypeDecl-145]
 	_ = x[OpSticky-208]
@@ -158,10 +159,11 @@ func _() {
 	_ = x[OpRangeIterMap-213]
 	_ = x[OpRangeIterArrayPtr-214]
 	_ = x[OpReturnCallDefers-215]
+	_ = x[OpRangeIterChan-216]
 	_ = x[OpVoid-255]
 }
 
-const _Op_name = "OpInvalidOpHaltOpNoopOpExecOpPrecallOpEnterCrossingOpCallOpCallNativeBodyOpDeferOpCallDeferNativeBodyOpGoOpSelectOpSwitchClauseOpSwitchClauseCaseOpTypeSwitchOpIfCondOpPopValueOpPopResultsOpPopBlockOpPopFrameAndResetOpPanic1OpPanic2OpReturnOpReturnAfterCopyOpReturnFromBlockOpReturnToBlockOpUposOpUnegOpUnotOpUxorOpUrecvOpLorOpLandOpEqlOpNeqOpLssOpLeqOpGtrOpGeqOpAddOpSubOpBorOpXorOpMulOpQuoOpRemOpShlOpShrOpBandOpBandnOpEvalOpBinary1OpIndex1OpIndex2OpSelectorOpSliceOpStarOpRefOpTypeAssert1OpTypeAssert2OpStaticTypeOfOpCompositeLitOpArrayLitOpSliceLitOpSliceLit2OpMapLitOpStructLitOpFuncLitOpConvertOpFieldTypeOpArrayTypeOpSliceTypeOpPointerTypeOpInterfaceTypeOpChanTypeOpFuncTypeOpMapTypeOpStructTypeOpAssignOpAddAssignOpSubAssignOpMulAssignOpQuoAssignOpRemAssignOpBandAssignOpBandnAssignOpBorAssignOpXorAssignOpShlAssignOpShrAssignOpDefineOpIncOpDecOpValueDeclOpTypeDeclOpStickyOpBodyOpForLoopOpRangeIterOpRangeIterStringOpRangeIterMapOpRangeIterArrayPtrOpReturnCallDefersOpVoid"
+const _Op_name = "OpInvalidOpHaltOpNoopOpExecOpPrecallOpEnterCrossingOpCallOpCallNativeBodyOpDeferOpCallDeferNativeBodyOpGoOpSelectOpSwitchClauseOpSwitchClauseCaseOpTypeSwitchOpIfCondOpPopValueOpPopResultsOpPopBlockOpPopFrameAndResetOpPanic1OpPanic2OpReturnOpReturnAfterCopyOpReturnFromBlockOpReturnToBlockOpUposOpUnegOpUnotOpUxorOpUrecvOpLorOpLandOpEqlOpNeqOpLssOpLeqOpGtrOpGeqOpAddOpSubOpBorOpXorOpMulOpQuoOpRemOpShlOpShrOpBandOpBandnOpEvalOpBinary1OpIndex1OpIndex2OpSelectorOpSliceOpStarOpRefOpTypeAssert1OpTypeAssert2OpStaticTypeOfOpCompositeLitOpArrayLitOpSliceLitOpSliceLit2OpMapLitOpStructLitOpFuncLitOpConvertOpFieldTypeOpArrayTypeOpSliceTypeOpPointerTypeOpInterfaceTypeOpChanTypeOpFuncTypeOpMapTypeOpStructTypeOpAssignOpAddAssignOpSubAssignOpMulAssignOpQuoAssignOpRemAssignOpBandAssignOpBandnAssignOpBorAssignOpXorAssignOpShlAssignOpShrAssignOpDefineOpIncOpDecOpSendOpValueDeclOpTypeDeclOpStickyOpBodyOpForLoopOpRangeIterOpRangeIterStringOpRangeIterMapOpRangeIterArrayPtrOpReturnCallDefersOpRangeIterChanOpVoid"
 
 var _Op_map = map[Op]string{
 	0:   _Op_name[0:9],
@@ -257,17 +259,19 @@ var _Op_map = map[Op]string{
 	140: _Op_name[833:841],
 	141: _Op_name[841:846],
 	142: _Op_name[846:851],
-	144: _Op_name[851:862],
-	145: _Op_name[862:872],
-	208: _Op_name[872:880],
-	209: _Op_name[880:886],
-	210: _Op_name[886:895],
-	211: _Op_name[895:906],
-	212: _Op_name[906:923],
-	213: _Op_name[923:937],
-	214: _Op_name[937:956],
-	215: _Op_name[956:974],
-	255: _Op_name[974:980],
+	143: _Op_name[851:857],
+	144: _Op_name[857:868],
+	145: _Op_name[868:878],
+	208: _Op_name[878:886],
+	209: _Op_name[886:892],
+	210: _Op_name[892:901],
+	211: _Op_name[901:912],
+	212: _Op_name[912:929],
+	213: _Op_name[929:943],
+	214: _Op_name[943:962],
+	215: _Op_name[962:980],
+	216: _Op_name[980:995],
+	255: _Op_name[995:1001],
 }
 
 func (i Op) String() string {
@@ -306,83 +310,85 @@ func _() {
 	_ = x[TRANS_CALL_ARG-4]
 	_ = x[TRANS_INDEX_X-5]
 	_ = x[TRANS_INDEX_INDEX-6]
-	_ = x[TRANS_SELECTOR_X-7]
-	_ = x[TRANS_SLICE_X-8]
-	_ = x[TRANS_SLICE_LOW-9]
-	_ = x[TRANS_SLICE_HIGH-10]
-	_ = x[TRANS_SLICE_MAX-11]
-	_ = x[TRANS_STAR_X-12]
-	_ = x[TRANS_REF_X-13]
-	_ = x[TRANS_TYPEASSERT_X-14]
-	_ = x[TRANS_TYPEASSERT_TYPE-15]
-	_ = x[TRANS_UNARY_X-16]
-	_ = x[TRANS_COMPOSITE_TYPE-17]
-	_ = x[TRANS_COMPOSITE_KEY-18]
-	_ = x[TRANS_COMPOSITE_VALUE-19]
-	_ = x[TRANS_FUNCLIT_TYPE-20]
-	_ = x[TRANS_FUNCLIT_HEAP_CAPTURE-21]
-	_ = x[TRANS_FUNCLIT_BODY-22]
-	_ = x[TRANS_FIELDTYPE_NAME-23]
-	_ = x[TRANS_FIELDTYPE_TYPE-24]
-	_ = x[TRANS_FIELDTYPE_TAG-25]
-	_ = x[TRANS_ARRAYTYPE_LEN-26]
-	_ = x[TRANS_ARRAYTYPE_ELT-27]
-	_ = x[TRANS_SLICETYPE_ELT-28]
-	_ = x[TRANS_INTERFACETYPE_METHOD-29]
-	_ = x[TRANS_CHANTYPE_VALUE-30]
-	_ = x[TRANS_FUNCTYPE_PARAM-31]
-	_ = x[TRANS_FUNCTYPE_RESULT-32]
-	_ = x[TRANS_MAPTYPE_KEY-33]
-	_ = x[TRANS_MAPTYPE_VALUE-34]
-	_ = x[TRANS_STRUCTTYPE_FIELD-35]
-	_ = x[TRANS_ASSIGN_LHS-36]
-	_ = x[TRANS_ASSIGN_RHS-37]
-	_ = x[TRANS_BLOCK_BODY-38]
-	_ = x[TRANS_DECL_BODY-39]
-	_ = x[TRANS_DEFER_CALL-40]
-	_ = x[TRANS_EXPR_X-41]
-	_ = x[TRANS_FOR_INIT-42]
-	_ = x[TRANS_FOR_COND-43]
-	_ = x[TRANS_FOR_POST-44]
-	_ = x[TRANS_FOR_BODY-45]
-	_ = x[TRANS_GO_CALL-46]
-	_ = x[TRANS_IF_INIT-47]
-	_ = x[TRANS_IF_COND-48]
-	_ = x[TRANS_IF_BODY-49]
-	_ = x[TRANS_IF_ELSE-50]
-	_ = x[TRANS_IF_CASE_BODY-51]
-	_ = x[TRANS_INCDEC_X-52]
-	_ = x[TRANS_RANGE_X-53]
-	_ = x[TRANS_RANGE_KEY-54]
-	_ = x[TRANS_RANGE_VALUE-55]
-	_ = x[TRANS_RANGE_BODY-56]
-	_ = x[TRANS_RETURN_RESULT-57]
-	_ = x[TRANS_SELECT_CASE-58]
-	_ = x[TRANS_SELECTCASE_COMM-59]
-	_ = x[TRANS_SELECTCASE_BODY-60]
-	_ = x[TRANS_SEND_CHAN-61]
-	_ = x[TRANS_SEND_VALUE-62]
-	_ = x[TRANS_SWITCH_INIT-63]
-	_ = x[TRANS_SWITCH_X-64]
-	_ = x[TRANS_SWITCH_CASE-65]
-	_ = x[TRANS_SWITCHCASE_CASE-66]
-	_ = x[TRANS_SWITCHCASE_BODY-67]
-	_ = x[TRANS_FUNC_RECV-68]
-	_ = x[TRANS_FUNC_TYPE-69]
-	_ = x[TRANS_FUNC_BODY-70]
-	_ = x[TRANS_IMPORT_PATH-71]
-	_ = x[TRANS_CONST_TYPE-72]
-	_ = x[TRANS_CONST_VALUE-73]
-	_ = x[TRANS_VAR_NAME-74]
-	_ = x[TRANS_VAR_TYPE-75]
-	_ = x[TRANS_VAR_VALUE-76]
-	_ = x[TRANS_TYPE_TYPE-77]
-	_ = x[TRANS_FILE_BODY-78]
+	_ = x[TRANS_INDEXLIST_X-7]
+	_ = x[TRANS_INDEXLIST_INDEX-8]
+	_ = x[TRANS_SELECTOR_X-9]
+	_ = x[TRANS_SLICE_X-10]
+	_ = x[TRANS_SLICE_LOW-11]
+	_ = x[TRANS_SLICE_HIGH-12]
+	_ = x[TRANS_SLICE_MAX-13]
+	_ = x[TRANS_STAR_X-14]
+	_ = x[TRANS_REF_X-15]
+	_ = x[TRANS_TYPEASSERT_X-16]
+	_ = x[TRANS_TYPEASSERT_TYPE-17]
+	_ = x[TRANS_UNARY_X-18]
+	_ = x[TRANS_COMPOSITE_TYPE-19]
+	_ = x[TRANS_COMPOSITE_KEY-20]
+	_ = x[TRANS_COMPOSITE_VALUE-21]
+	_ = x[TRANS_FUNCLIT_TYPE-22]
+	_ = x[TRANS_FUNCLIT_HEAP_CAPTURE-23]
+	_ = x[TRANS_FUNCLIT_BODY-24]
+	_ = x[TRANS_FIELDTYPE_NAME-25]
+	_ = x[TRANS_FIELDTYPE_TYPE-26]
+	_ = x[TRANS_FIELDTYPE_TAG-27]
+	_ = x[TRANS_ARRAYTYPE_LEN-28]
+	_ = x[TRANS_ARRAYTYPE_ELT-29]
+	_ = x[TRANS_SLICETYPE_ELT-30]
+	_ = x[TRANS_INTERFACETYPE_METHOD-31]
+	_ = x[TRANS_CHANTYPE_VALUE-32]
+	_ = x[TRANS_FUNCTYPE_PARAM-33]
+	_ = x[TRANS_FUNCTYPE_RESULT-34]
+	_ = x[TRANS_MAPTYPE_KEY-35]
+	_ = x[TRANS_MAPTYPE_VALUE-36]
+	_ = x[TRANS_STRUCTTYPE_FIELD-37]
+	_ = x[TRANS_ASSIGN_LHS-38]
+	_ = x[TRANS_ASSIGN_RHS-39]
+	_ = x[TRANS_BLOCK_BODY-40]
+	_ = x[TRANS_DECL_BODY-41]
+	_ = x[TRANS_DEFER_CALL-42]
+	_ = x[TRANS_EXPR_X-43]
+	_ = x[TRANS_FOR_INIT-44]
+	_ = x[TRANS_FOR_COND-45]
+	_ = x[TRANS_FOR_POST-46]
+	_ = x[TRANS_FOR_BODY-47]
+	_ = x[TRANS_GO_CALL-48]
+	_ = x[TRANS_IF_INIT-49]
+	_ = x[TRANS_IF_COND-50]
+	_ = x[TRANS_IF_BODY-51]
+	_ = x[TRANS_IF_ELSE-52]
+	_ = x[TRANS_IF_CASE_BODY-53]
+	_ = x[TRANS_INCDEC_X-54]
+	_ = x[TRANS_RANGE_X-55]
+	_ = x[TRANS_RANGE_KEY-56]
+	_ = x[TRANS_RANGE_VALUE-57]
+	_ = x[TRANS_RANGE_BODY-58]
+	_ = x[TRANS_RETURN_RESULT-59]
+	_ = x[TRANS_SELECT_CASE-60]
+	_ = x[TRANS_SELECTCASE_COMM-61]
+	_ = x[TRANS_SELECTCASE_BODY-62]
+	_ = x[TRANS_SEND_CHAN-63]
+	_ = x[TRANS_SEND_VALUE-64]
+	_ = x[TRANS_SWITCH_INIT-65]
+	_ = x[TRANS_SWITCH_X-66]
+	_ = x[TRANS_SWITCH_CASE-67]
+	_ = x[TRANS_SWITCHCASE_CASE-68]
+	_ = x[TRANS_SWITCHCASE_BODY-69]
+	_ = x[TRANS_FUNC_RECV-70]
+	_ = x[TRANS_FUNC_TYPE-71]
+	_ = x[TRANS_FUNC_BODY-72]
+	_ = x[TRANS_IMPORT_PATH-73]
+	_ = x[TRANS_CONST_TYPE-74]
+	_ = x[TRANS_CONST_VALUE-75]
+	_ = x[TRANS_VAR_NAME-76]
+	_ = x[TRANS_VAR_TYPE-77]
+	_ = x[TRANS_VAR_VALUE-78]
+	_ = x[TRANS_TYPE_TYPE-79]
+	_ = x[TRANS_FILE_BODY-80]
 }
 
-const _TransField_name = "TRANS_ROOTTRANS_BINARY_LEFTTRANS_BINARY_RIGHTTRANS_CALL_FUNCTRANS_CALL_ARGTRANS_INDEX_XTRANS_INDEX_INDEXTRANS_SELECTOR_XTRANS_SLICE_XTRANS_SLICE_LOWTRANS_SLICE_HIGHTRANS_SLICE_MAXTRANS_STAR_XTRANS_REF_XTRANS_TYPEASSERT_XTRANS_TYPEASSERT_TYPETRANS_UNARY_XTRANS_COMPOSITE_TYPETRANS_COMPOSITE_KEYTRANS_COMPOSITE_VALUETRANS_FUNCLIT_TYPETRANS_FUNCLIT_HEAP_CAPTURETRANS_FUNCLIT_BODYTRANS_FIELDTYPE_NAMETRANS_FIELDTYPE_TYPETRANS_FIELDTYPE_TAGTRANS_ARRAYTYPE_LENTRANS_ARRAYTYPE_ELTTRANS_SLICETYPE_ELTTRANS_INTERFACETYPE_METHODTRANS_CHANTYPE_VALUETRANS_FUNCTYPE_PARAMTRANS_FUNCTYPE_RESULTTRANS_MAPTYPE_KEYTRANS_MAPTYPE_VALUETRANS_STRUCTTYPE_FIELDTRANS_ASSIGN_LHSTRANS_ASSIGN_RHSTRANS_BLOCK_BODYTRANS_DECL_BODYTRANS_DEFER_CALLTRANS_EXPR_XTRANS_FOR_INITTRANS_FOR_CONDTRANS_FOR_POSTTRANS_FOR_BODYTRANS_GO_CALLTRANS_IF_INITTRANS_IF_CONDTRANS_IF_BODYTRANS_IF_ELSETRANS_IF_CASE_BODYTRANS_INCDEC_XTRANS_RANGE_XTRANS_RANGE_KEYTRANS_RANGE_VALUETRANS_RANGE_BODYTRANS_RETURN_RESULTTRANS_SELECT_CASETRANS_SELECTCASE_COMMTRANS_SELECTCASE_BODYTRANS_SEND_CHANTRANS_SEND_VALUETRANS_SWITCH_INITTRANS_SWITCH_XTRANS_SWITCH_CASETRANS_SWITCHCASE_CASETRANS_SWITCHCASE_BODYTRANS_FUNC_RECVTRANS_FUNC_TYPETRANS_FUNC_BODYTRANS_IMPORT_PATHTRANS_CONST_TYPETRANS_CONST_VALUETRANS_VAR_NAMETRANS_VAR_TYPETRANS_VAR_VALUETRANS_TYPE_TYPETRANS_FILE_BODY"
+const _TransField_name = "TRANS_ROOTTRANS_BINARY_LEFTTRANS_BINARY_RIGHTTRANS_CALL_FUNCTRANS_CALL_ARGTRANS_INDEX_XTRANS_INDEX_INDEXTRANS_INDEXLIST_XTRANS_INDEXLIST_INDEXTRANS_SELECTOR_XTRANS_SLICE_XTRANS_SLICE_LOWTRANS_SLICE_HIGHTRANS_SLICE_MAXTRANS_STAR_XTRANS_REF_XTRANS_TYPEASSERT_XTRANS_TYPEASSERT_TYPETRANS_UNARY_XTRANS_COMPOSITE_TYPETRANS_COMPOSITE_KEYTRANS_COMPOSITE_VALUETRANS_FUNCLIT_TYPETRANS_FUNCLIT_HEAP_CAPTURETRANS_FUNCLIT_BODYTRANS_FIELDTYPE_NAMETRANS_FIELDTYPE_TYPETRANS_FIELDTYPE_TAGTRANS_ARRAYTYPE_LENTRANS_ARRAYTYPE_ELTTRANS_SLICETYPE_ELTTRANS_INTERFACETYPE_METHODTRANS_CHANTYPE_VALUETRANS_FUNCTYPE_PARAMTRANS_FUNCTYPE_RESULTTRANS_MAPTYPE_KEYTRANS_MAPTYPE_VALUETRANS_STRUCTTYPE_FIELDTRANS_ASSIGN_LHSTRANS_ASSIGN_RHSTRANS_BLOCK_BODYTRANS_DECL_BODYTRANS_DEFER_CALLTRANS_EXPR_XTRANS_FOR_INITTRANS_FOR_CONDTRANS_FOR_POSTTRANS_FOR_BODYTRANS_GO_CALLTRANS_IF_INITTRANS_IF_CONDTRANS_IF_BODYTRANS_IF_ELSETRANS_IF_CASE_BODYTRANS_INCDEC_XTRANS_RANGE_XTRANS_RANGE_KEYTRANS_RANGE_VALUETRANS_RANGE_BODYTRANS_RETURN_RESULTTRANS_SELECT_CASETRANS_SELECTCASE_COMMTRANS_SELECTCASE_BODYTRANS_SEND_CHANTRANS_SEND_VALUETRANS_SWITCH_INITTRANS_SWITCH_XTRANS_SWITCH_CASETRANS_SWITCHCASE_CASETRANS_SWITCHCASE_BODYTRANS_FUNC_RECVTRANS_FUNC_TYPETRANS_FUNC_BODYTRANS_IMPORT_PATHTRANS_CONST_TYPETRANS_CONST_VALUETRANS_VAR_NAMETRANS_VAR_TYPETRANS_VAR_VALUETRANS_TYPE_TYPETRANS_FILE_BODY"
 
-var _TransField_index = [...]uint16{0, 10, 27, 45, 60, 74, 87, 104, 120, 133, 148, 164, 179, 191, 202, 220, 241, 254, 274, 293, 314, 332, 358, 376, 396, 416, 435, 454, 473, 492, 518, 538, 558, 579, 596, 615, 637, 653, 669, 685, 700, 716, 728, 742, 756, 770, 784, 797, 810, 823, 836, 849, 867, 881, 894, 909, 926, 942, 961, 978, 999, 1020, 1035, 1051, 1068, 1082, 1099, 1120, 1141, 1156, 1171, 1186, 1203, 1219, 1236, 1250, 1264, 1279, 1294, 1309}
+var _TransField_index = [...]uint16{0, 10, 27, 45, 60, 74, 87, 104, 121, 142, 158, 171, 186, 202, 217, 229, 240, 258, 279, 292, 312, 331, 352, 370, 396, 414, 434, 454, 473, 492, 511, 530, 556, 576, 596, 617, 634, 653, 675, 691, 707, 723, 738, 754, 766, 780, 794, 808, 822, 835, 848, 861, 874, 887, 905, 919, 932, 947, 964, 980, 999, 1016, 1037, 1058, 1073, 1089, 1106, 1120, 1137, 1158, 1179, 1194, 1209, 1224, 1241, 1257, 1274, 1288, 1302, 1317, 1332, 1347}
 
 func (i TransField) String() string {
 	if i >= TransField(len(_TransField_index)-1) {
@@ -501,11 +507,12 @@ func _() {
 	_ = x[SWITCH-65]
 	_ = x[TYPE-66]
 	_ = x[VAR-67]
+	_ = x[TILDE-68]
 }
 
-const _Word_name = "ILLEGALNAMEINTFLOATIMAGCHARSTRINGADDSUBMULQUOREMBANDBORXORSHLSHRBAND_NOTADD_ASSIGNSUB_ASSIGNMUL_ASSIGNQUO_ASSIGNREM_ASSIGNBAND_ASSIGNBOR_ASSIGNXOR_ASSIGNSHL_ASSIGNSHR_ASSIGNBAND_NOT_ASSIGNLANDLORARROWINCDECEQLLSSGTRASSIGNNOTNEQLEQGEQDEFINEBREAKCASECHANCONSTCONTINUEDEFAULTDEFERELSEFALLTHROUGHFORFUNCGOGOTOIFIMPORTINTERFACEMAPPACKAGERANGERETURNSELECTSTRUCTSWITCHTYPEVAR"
+const _Word_name = "ILLEGALNAMEINTFLOATIMAGCHARSTRINGADDSUBMULQUOREMBANDBORXORSHLSHRBAND_NOTADD_ASSIGNSUB_ASSIGNMUL_ASSIGNQUO_ASSIGNREM_ASSIGNBAND_ASSIGNBOR_ASSIGNXOR_ASSIGNSHL_ASSIGNSHR_ASSIGNBAND_NOT_ASSIGNLANDLORARROWINCDECEQLLSSGTRASSIGNNOTNEQLEQGEQDEFINEBREAKCASECHANCONSTCONTINUEDEFAULTDEFERELSEFALLTHROUGHFORFUNCGOGOTOIFIMPORTINTERFACEMAPPACKAGERANGERETURNSELECTSTRUCTSWITCHTYPEVARTILDE"
 
-var _Word_index = [...]uint16{0, 7, 11, 14, 19, 23, 27, 33, 36, 39, 42, 45, 48, 52, 55, 58, 61, 64, 72, 82, 92, 102, 112, 122, 133, 143, 153, 163, 173, 188, 192, 195, 200, 203, 206, 209, 212, 215, 221, 224, 227, 230, 233, 239, 244, 248, 252, 257, 265, 272, 277, 281, 292, 295, 299, 301, 305, 307, 313, 322, 325, 332, 337, 343, 349, 355, 361, 365, 368}
+var _Word_index = [...]uint16{0, 7, 11, 14, 19, 23, 27, 33, 36, 39, 42, 45, 48, 52, 55, 58, 61, 64, 72, 82, 92, 102, 112, 122, 133, 143, 153, 163, 173, 188, 192, 195, 200, 203, 206, 209, 212, 215, 221, 224, 227, 230, 233, 239, 244, 248, 252, 257, 265, 272, 277, 281, 292, 295, 299, 301, 305, 307, 313, 322, 325, 332, 337, 343, 349, 355, 361, 365, 368, 373}
 
 func (i Word) String() string {
 	if i < 0 || i >= Word(len(_Word_index)-1) {
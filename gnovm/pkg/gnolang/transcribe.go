@@ -32,6 +32,8 @@ const (
 	TRANS_CALL_ARG
 	TRANS_INDEX_X
 	TRANS_INDEX_INDEX
+	TRANS_INDEXLIST_X
+	TRANS_INDEXLIST_INDEX
 	TRANS_SELECTOR_X
 	TRANS_SLICE_X
 	TRANS_SLICE_LOW
@@ -178,6 +180,17 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		if stopOrSkip(nc, c) {
 			return
 		}
+	case *IndexListExpr:
+		cnn.X = transcribe(t, nns, TRANS_INDEXLIST_X, 0, cnn.X, &c).(Expr)
+		if stopOrSkip(nc, c) {
+			return
+		}
+		for idx := range cnn.Indices {
+			cnn.Indices[idx] = transcribe(t, nns, TRANS_INDEXLIST_INDEX, idx, cnn.Indices[idx], &c).(Expr)
+			if stopOrSkip(nc, c) {
+				return
+			}
+		}
 	case *SelectorExpr:
 		cnn.X = transcribe(t, nns, TRANS_SELECTOR_X, 0, cnn.X, &c).(Expr)
 		if stopOrSkip(nc, c) {
@@ -537,9 +550,11 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 		} else {
 			cnn = cnn2.(*SelectCaseStmt)
 		}
-		cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
-		if stopOrSkip(nc, c) {
-			return
+		if cnn.Comm != nil { // nil if default case.
+			cnn.Comm = transcribe(t, nns, TRANS_SELECTCASE_COMM, 0, cnn.Comm, &c).(Stmt)
+			if stopOrSkip(nc, c) {
+				return
+			}
 		}
 		// iterate over Body; its length can change if a statement is decomposed.
 		for idx := 0; idx < len(cnn.Body); idx++ {
@@ -676,6 +691,11 @@ func transcribe(t Transform, ns []Node, ftype TransField, index int, n Node, nc
 			cnn = cnn2.(*FileNode)
 		}
 		for idx := range cnn.Decls {
+			if isGenericDecl(cnn.Decls[idx]) {
+				// generic declarations are templates, which are only
+				// transcribed through their instances; see generics.go.
+				continue
+			}
 			cnn.Decls[idx] = transcribe(t, nns, TRANS_FILE_BODY, idx, cnn.Decls[idx], &c).(Decl)
 			if stopOrSkip(nc, c) {
 				return
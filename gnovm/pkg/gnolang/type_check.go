@@ -32,10 +32,11 @@ var (
 	}
 	// TODO: star, addressable
 	unaryChecker = map[Word]func(t Type) bool{
-		ADD: isNumeric,
-		SUB: isNumeric,
-		XOR: isIntNum,
-		NOT: isBoolean,
+		ADD:   isNumeric,
+		SUB:   isNumeric,
+		XOR:   isIntNum,
+		NOT:   isBoolean,
+		ARROW: isRecvChan,
 	}
 	IncDecStmtChecker = map[Word]func(t Type) bool{
 		INC: isNumeric,
@@ -108,6 +109,15 @@ func isBoolean(t Type) bool {
 	}
 }
 
+func isRecvChan(t Type) bool {
+	switch t := baseOf(t).(type) {
+	case *ChanType:
+		return t.Dir&RECV != 0
+	default:
+		return false
+	}
+}
+
 // rune can be numeric and string
 func isNumeric(t Type) bool {
 	switch t := baseOf(t).(type) {
@@ -173,7 +183,7 @@ func assertComparable2(dt Type) {
 	case *StructType:
 		for _, f := range cdt.Fields {
 			switch cft := baseOf(f.Type).(type) {
-			case PrimitiveType, *PointerType, *InterfaceType, *ArrayType, *StructType:
+			case PrimitiveType, *PointerType, *InterfaceType, *ArrayType, *StructType, *ChanType:
 				assertComparable2(cft)
 			default:
 				panic(fmt.Sprintf("%v is not comparable", dt))
@@ -181,6 +191,7 @@ func assertComparable2(dt Type) {
 		}
 	case *PointerType: // &a == &b
 	case *InterfaceType:
+	case *ChanType:
 	case *SliceType, *FuncType, *MapType:
 	default:
 		panic(fmt.Sprintf("%v is not comparable", dt))
@@ -394,6 +405,13 @@ func checkValDefineMismatch(n Node) {
 				panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
 			}
 			return
+		case *UnaryExpr:
+			if values[0].(*UnaryExpr).Op == ARROW {
+				if numNames != 2 {
+					panic(fmt.Sprintf("assignment mismatch: %d variable(s) but %d value(s)", numNames, numValues))
+				}
+				return
+			}
 		}
 	}
 
@@ -631,7 +649,17 @@ func checkAssignableTo(n Node, xt, dt Type, autoNative bool) (err error) {
 		panic("should not happen")
 	case *DeclaredType:
 		panic("should not happen")
-	case *FuncType, *StructType, *PackageType, *ChanType, *TypeType:
+	case *ChanType:
+		// A bidirectional channel is assignable to a
+		// directional channel of the same element type.
+		if ct, ok := xt.(*ChanType); ok {
+			if ct.Dir == BOTH || ct.Dir == cdt.Dir {
+				if ct.Elt.TypeID() == cdt.Elt.TypeID() {
+					return nil // ok
+				}
+			}
+		}
+	case *FuncType, *StructType, *PackageType, *TypeType:
 		if xt.TypeID() == cdt.TypeID() {
 			return nil // ok
 		}
@@ -963,6 +991,29 @@ func (x *AssignStmt) AssertCompatible(store Store, last BlockNode) {
 					}
 				}
 				cx.HasOK = true
+			case *UnaryExpr: // must be a channel receive when len(Lhs) > len(Rhs)
+				if len(x.Lhs) != 2 || cx.Op != ARROW {
+					panic("should not happen")
+				}
+				if x.Op == ASSIGN {
+					assertValidAssignLhs(store, last, x.Lhs[0])
+					if !isBlankIdentifier(x.Lhs[0]) {
+						lt := evalStaticTypeOf(store, last, x.Lhs[0])
+						xt := evalStaticTypeOf(store, last, cx.X)
+						if ct, ok := baseOf(xt).(*ChanType); ok {
+							assertAssignableTo(x, ct.Elt, lt, false)
+						}
+					}
+
+					assertValidAssignLhs(store, last, x.Lhs[1])
+					if !isBlankIdentifier(x.Lhs[1]) {
+						dt := evalStaticTypeOf(store, last, x.Lhs[1])
+						if dt != nil && dt.Kind() != BoolKind { // typed, not bool
+							panic(fmt.Sprintf("want bool type got %v", dt))
+						}
+					}
+				}
+				cx.HasOK = true
 			default:
 				panic(fmt.Sprintf("RHS should not be %v when len(Lhs) > len(Rhs)", cx))
 			}
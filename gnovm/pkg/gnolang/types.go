@@ -1083,9 +1083,9 @@ func (ct *ChanType) TypeID() TypeID {
 		case SEND | RECV:
 			ct.typeid = typeidf("chan{%s}", ct.Elt.TypeID().String())
 		case SEND:
-			ct.typeid = typeidf("<-chan{%s}", ct.Elt.TypeID().String())
-		case RECV:
 			ct.typeid = typeidf("chan<-{%s}", ct.Elt.TypeID().String())
+		case RECV:
+			ct.typeid = typeidf("<-chan{%s}", ct.Elt.TypeID().String())
 		default:
 			panic("should not happen")
 		}
@@ -1098,9 +1098,9 @@ func (ct *ChanType) String() string {
 	case SEND | RECV:
 		return "chan " + ct.Elt.String()
 	case SEND:
-		return "<-chan " + ct.Elt.String()
-	case RECV:
 		return "chan<- " + ct.Elt.String()
+	case RECV:
+		return "<-chan " + ct.Elt.String()
 	default:
 		panic("should not happen")
 	}
@@ -1428,7 +1428,8 @@ type DeclaredType struct {
 	Methods   []TypedValue // {T:*FuncType,V:*FuncValue}...
 
 	typeid TypeID
-	sealed bool // for ensuring correctness with recursive types.
+	sealed bool   // for ensuring correctness with recursive types.
+	targs  []Type // type arguments, if an instance of a generic type.
 }
 
 // Returns an unsealed *DeclaredType.
@@ -555,6 +555,26 @@ func makeUverseNode() {
 			m.PushValue(res0)
 		},
 	)
+	defNative("close",
+		Flds( // params
+			"c", AnyT(),
+		),
+		nil, // results
+		func(m *Machine) {
+			arg0 := m.LastBlock().GetParams1(m.Store)
+			ct, ok := baseOf(arg0.TV.T).(*ChanType)
+			if !ok {
+				panic(fmt.Sprintf(
+					"unexpected chan type %s",
+					arg0.TV.T.String()))
+			}
+			cv, _ := arg0.TV.V.(*ChanValue)
+			if cv == nil {
+				m.Panic(typedString(`close of nil channel`))
+			}
+			m.chanClose(cv, ct.Elt)
+		},
+	)
 	defNative("copy",
 		Flds( // params
 			"dst", GenT("X", nil),
@@ -802,9 +822,25 @@ func makeUverseNode() {
 					panic("make() of map type takes 1 or 2 arguments")
 				}
 			case *ChanType:
+				// NOTE: the type is not used.
 				switch vargsl {
-				case 0, 1:
-					panic("not yet implemented")
+				case 0:
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(0),
+					})
+					return
+				case 1:
+					sv := vargs.TV.GetPointerAtIndexInt(m.Store, 0).Deref()
+					si := int(sv.ConvertGetInt())
+					if si < 0 {
+						m.Panic(typedString(`makechan: size out of range`))
+					}
+					m.PushValue(TypedValue{
+						T: tt,
+						V: m.Alloc.NewChan(si),
+					})
+					return
 				default:
 					panic("make() of chan type takes 1 or 2 arguments")
 				}
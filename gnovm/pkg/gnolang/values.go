@@ -51,6 +51,7 @@ func (*SliceValue) assertValue()       {}
 func (*StructValue) assertValue()      {}
 func (*FuncValue) assertValue()        {}
 func (*MapValue) assertValue()         {}
+func (*ChanValue) assertValue()        {}
 func (*BoundMethodValue) assertValue() {}
 func (TypeValue) assertValue()         {}
 func (*PackageValue) assertValue()     {}
@@ -74,6 +75,7 @@ var (
 	_ Value = &StructValue{}
 	_ Value = &FuncValue{}
 	_ Value = &MapValue{}
+	_ Value = &ChanValue{}
 	_ Value = &BoundMethodValue{}
 	_ Value = TypeValue{}
 	_ Value = &PackageValue{}
@@ -624,6 +626,21 @@ func (bmv *BoundMethodValue) IsCrossing() bool {
 	return bmv.Func.IsCrossing()
 }
 
+// ----------------------------------------
+// ChanValue
+
+// ChanValue is a channel created by make(chan T[, n]). Channels are only
+// used by goroutines within a transaction, so they are never persisted.
+type ChanValue struct {
+	Cap    int
+	Buffer []TypedValue // buffered values, in order
+	Closed bool
+
+	recvq    []*chanWaiter // parked receivers
+	sendq    []*chanWaiter // parked senders
+	visiting bool          // breaks cycles in VisitAssociated
+}
+
 // ----------------------------------------
 // MapValue
 
@@ -1638,7 +1655,12 @@ func (tv *TypedValue) ComputeMapKey(store Store, omitType bool) (key MapKey, isN
 		pv := tv.V.(*PackageValue)
 		bz = append(bz, []byte(strconv.Quote(pv.PkgPath))...)
 	case *ChanType:
-		panic("not yet implemented")
+		var ptrBytes [sizeOfUintPtr]byte // zero-initialized for nil channels
+		if tv.V != nil {
+			ptr := uintptr(unsafe.Pointer(tv.V.(*ChanValue)))
+			ptrBytes = uintptrToBytes(&ptr)
+		}
+		bz = append(bz, ptrBytes[:]...)
 	default:
 		panic(fmt.Sprintf(
 			"unexpected map key type %s",
@@ -2070,6 +2092,8 @@ func (tv *TypedValue) GetLength() int {
 			return 0
 		case *MapType:
 			return 0
+		case *ChanType:
+			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
 				return at.Len
@@ -2090,6 +2114,8 @@ func (tv *TypedValue) GetLength() int {
 		return cv.GetLength()
 	case *MapValue:
 		return cv.GetLength()
+	case *ChanValue:
+		return len(cv.Buffer)
 	case PointerValue:
 		if av, ok := cv.TV.V.(*ArrayValue); ok {
 			return av.GetLength()
@@ -2110,6 +2136,8 @@ func (tv *TypedValue) GetCapacity() int {
 			return bt.Len
 		case *SliceType:
 			return 0
+		case *ChanType:
+			return 0
 		case *PointerType:
 			if at, ok := bt.Elt.(*ArrayType); ok {
 				return at.Len
@@ -2124,6 +2152,8 @@ func (tv *TypedValue) GetCapacity() int {
 		return cv.GetCapacity()
 	case *SliceValue:
 		return cv.GetCapacity()
+	case *ChanValue:
+		return cv.Cap
 	case PointerValue:
 		if av, ok := cv.TV.V.(*ArrayValue); ok {
 			return av.GetCapacity()
@@ -26,6 +26,7 @@ type LoadConfig struct {
 	Test                bool                       // load test dependencies
 	GnoRoot             string                     // used to override GNOROOT
 	ExtraWorkspaceRoots []string                   // extra workspaces root used to find dependencies
+	Dir                 string                     // directory used to find the workspace or module, defaults to the current directory
 }
 
 func (conf *LoadConfig) applyDefaults() error {
@@ -51,7 +52,7 @@ func Load(conf LoadConfig, patterns ...string) (PkgList, error) {
 
 	// XXX: allow loading only stdlibs without a workspace (like go allow loading stdlibs without a go.mod)
 
-	loaderCtx, err := findLoaderContext()
+	loaderCtx, err := findLoaderContext(conf.Dir)
 	if err != nil {
 		return nil, err
 	}
@@ -92,6 +93,14 @@ func Load(conf LoadConfig, patterns ...string) (PkgList, error) {
 		toVisit = append(toVisit, pkg)
 	}
 
+	// pattern packages are already loaded, this avoids reloading them when
+	// they are imported by their own xtests or by the deps of those
+	for _, pkg := range pkgs {
+		if pkg.ImportPath != "" {
+			resolvedByPkgPath[pkg.ImportPath] = struct{}{}
+		}
+	}
+
 	visited := map[string]struct{}{}
 	loaded := []*Package{}
 
@@ -160,10 +169,14 @@ type loaderContext struct {
 	IsWorkspace bool
 }
 
-func findLoaderContext() (*loaderContext, error) {
-	wd, err := os.Getwd()
-	if err != nil {
-		return nil, err
+func findLoaderContext(wd string) (*loaderContext, error) {
+	if wd == "" {
+		var err error
+		if wd, err = os.Getwd(); err != nil {
+			return nil, err
+		}
+	} else if !filepath.IsAbs(wd) {
+		return nil, fmt.Errorf("loader directory should be absolute, got %q", wd)
 	}
 
 	{
@@ -179,7 +192,7 @@ func findLoaderContext() (*loaderContext, error) {
 	}
 
 	gnomodPath := filepath.Join(wd, "gnomod.toml")
-	_, err = os.Stat(gnomodPath)
+	_, err := os.Stat(gnomodPath)
 	switch {
 	case err == nil:
 		return &loaderContext{Root: wd}, nil
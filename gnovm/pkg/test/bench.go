@@ -0,0 +1,186 @@
+package test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"runtime/debug"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"go.uber.org/multierr"
+)
+
+// benchReport is a mirror of the report returned by Gno's
+// testing.RunBenchmark.
+type benchReport struct {
+	Failed  bool
+	Skipped bool
+	Results []benchResult
+}
+
+// benchResult is the result of a single (sub-)benchmark.
+// All values except N are the totals over the N iterations.
+type benchResult struct {
+	Name   string
+	N      int
+	T      int64 // nanoseconds
+	Cycles int64 // Machine CPU cycles
+	Gas    int64 // gas consumed by the Machine and store
+	Alloc  int64 // bytes allocated through the Allocator
+	Bytes  int64 // set with B.SetBytes; bytes processed per iteration
+	Extra  map[string]float64
+}
+
+// runBenchmarks runs the Benchmark functions in files matching
+// opts.BenchFlag, printing the results to opts.Error.
+// pv is the package value of mpkg, already set up by runTestFiles.
+func (opts *TestOptions) runBenchmarks(
+	mpkg *std.MemPackage,
+	files *gno.FileSet,
+	tgs gno.TransactionStore,
+	pv *gno.PackageValue,
+	gasMeter storetypes.GasMeter,
+) (errs error) {
+	var m *gno.Machine
+	defer func() {
+		if r := recover(); r != nil {
+			if st := m.ExceptionStacktrace(); st != "" {
+				errs = multierr.Append(errors.New(st), errs)
+			}
+			errs = multierr.Append(
+				fmt.Errorf("panic: %v\ngo stacktrace:\n%v\ngno machine: %v\ngno stacktrace:\n%v",
+					r, string(debug.Stack()), m.String(), m.Stacktrace()),
+				errs,
+			)
+		}
+	}()
+
+	benchTimeNs := opts.BenchTime.Nanoseconds()
+	if benchTimeNs <= 0 {
+		benchTimeNs = time.Second.Nanoseconds()
+	}
+
+	for _, bf := range loadTestFuncs(mpkg.Name, files, "Benchmark") {
+		m = Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug)
+		// Allocations are never garbage collected, so that the allocator
+		// keeps track of the total bytes allocated.
+		m.Alloc = gno.NewAllocator(math.MaxInt64)
+		m.GasMeter = gasMeter
+		m.Coverage = opts.Coverage
+		m.Profiler = opts.Profiler
+		m.SetActivePackage(pv)
+
+		if m.Eval(gno.Nx(bf.Name))[0].GetFunc().IsCrossing() {
+			fmt.Fprintf(opts.Error, "--- FAIL: %s\n", bf.Name)
+			errs = multierr.Append(errs, fmt.Errorf("%s: crossing benchmarks are not supported", bf.Name))
+			continue
+		}
+
+		testingpv := m.Store.GetPackage("testing/base", false)
+		testingtv := gno.TypedValue{T: &gno.PackageType{}, V: testingpv}
+		testingcx := &gno.ConstExpr{TypedValue: testingtv}
+
+		eval := m.Eval(gno.Call(
+			gno.Sel(testingcx, "RunBenchmark"),       // Call testing.RunBenchmark
+			gno.Str(opts.BenchFlag),                  // bench flag
+			gno.Nx(strconv.FormatBool(opts.Verbose)), // is verbose?
+			benchTimeNs,                              // minimum duration
+			opts.BenchTimeN,                          // or number of iterations
+			&gno.CompositeLitExpr{ // the testing.InternalBenchmark
+				Type: gno.Sel(testingcx, "InternalBenchmark"),
+				Elts: gno.KeyValueExprs{
+					{Key: gno.X("Name"), Value: gno.Str(bf.Name)},
+					{Key: gno.X("F"), Value: gno.Nx(bf.Name)},
+				},
+			},
+		))
+
+		var rep benchReport
+		if err := json.Unmarshal([]byte(eval[0].GetString()), &rep); err != nil {
+			errs = multierr.Append(errs, err)
+			fmt.Fprintf(opts.Error, "--- FAIL: %s [internal gno testing error]\n", bf.Name)
+			continue
+		}
+		for _, res := range rep.Results {
+			fmt.Fprintf(opts.Error, "%s\t%s\n", res.Name, res.String())
+		}
+		if rep.Failed {
+			errs = multierr.Append(errs, fmt.Errorf("failed: %q", bf.Name))
+			if opts.FailfastFlag {
+				return errs
+			}
+		}
+	}
+
+	return errs
+}
+
+// String formats the result similarly to Go's testing.BenchmarkResult,
+// adding the GnoVM-specific metrics.
+func (r benchResult) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%8d", r.N)
+	n := float64(r.N)
+	if n == 0 {
+		n = 1
+	}
+	sb.WriteByte('\t')
+	prettyPrint(&sb, float64(r.T)/n, "ns/op")
+	if r.Bytes > 0 && r.T > 0 {
+		mbs := (float64(r.Bytes) * float64(r.N) / 1e6) / (float64(r.T) / 1e9)
+		fmt.Fprintf(&sb, "\t%7.2f MB/s", mbs)
+	}
+	sb.WriteByte('\t')
+	prettyPrint(&sb, float64(r.Cycles)/n, "cycles/op")
+	sb.WriteByte('\t')
+	prettyPrint(&sb, float64(r.Gas)/n, "gas/op")
+	sb.WriteByte('\t')
+	prettyPrint(&sb, float64(r.Alloc)/n, "B/op")
+
+	units := make([]string, 0, len(r.Extra))
+	for unit := range r.Extra {
+		units = append(units, unit)
+	}
+	sort.Strings(units)
+	for _, unit := range units {
+		sb.WriteByte('\t')
+		prettyPrint(&sb, r.Extra[unit], unit)
+	}
+	return sb.String()
+}
+
+// Adapted from Go's testing.prettyPrint.
+func prettyPrint(w io.Writer, x float64, unit string) {
+	// Print all numbers with 10 places before the decimal point
+	// and small numbers with four sig figs. Field widths are
+	// chosen to fit the whole part in 10 places while aligning
+	// the decimal point of all fractional formats.
+	var format string
+	switch y := math.Abs(x); {
+	case y == 0 || y >= 999.95:
+		format = "%10.0f %s"
+	case y >= 99.995:
+		format = "%12.1f %s"
+	case y >= 9.9995:
+		format = "%13.2f %s"
+	case y >= 0.99995:
+		format = "%14.3f %s"
+	case y >= 0.099995:
+		format = "%15.4f %s"
+	case y >= 0.0099995:
+		format = "%16.5f %s"
+	case y >= 0.00099995:
+		format = "%17.6f %s"
+	default:
+		format = "%18.7f %s"
+	}
+	fmt.Fprintf(w, format, x, unit)
+}
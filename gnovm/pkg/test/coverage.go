@@ -0,0 +1,78 @@
+package test
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Coverage modes, matching those of `go test -covermode`.
+const (
+	CoverModeSet   = "set"
+	CoverModeCount = "count"
+)
+
+// addCoverageFiles registers the production files of mpkg into
+// opts.Coverage, so that statements which were never executed are still
+// part of the coverage report.
+func (opts *TestOptions) addCoverageFiles(mpkg *std.MemPackage) {
+	pmpkg := gno.MPFProd.FilterMemPackage(mpkg)
+	for _, mfile := range pmpkg.Files {
+		if !strings.HasSuffix(mfile.Name, ".gno") {
+			continue
+		}
+		fn, err := gno.ParseFile(mfile.Name, mfile.Body)
+		if err != nil {
+			// parse errors are reported when running the tests.
+			continue
+		}
+		opts.Coverage.AddFile(mpkg.Path, fn)
+	}
+}
+
+// WriteCoverProfile writes the statement counts collected in cov to w, in the
+// format of the cover profiles generated by `go test -coverprofile`.
+//
+// dirs maps each package path to the directory containing its files; if it is
+// found, the file names are written as absolute paths so that the profile
+// can be used directly with `go tool cover -html`. Otherwise, the file names
+// are written as pkgPath/file, like Go does.
+func WriteCoverProfile(w io.Writer, cov *gno.Coverage, mode string, dirs map[string]string) error {
+	switch mode {
+	case CoverModeSet, CoverModeCount:
+	default:
+		return fmt.Errorf("invalid cover mode %q", mode)
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "mode: %s\n", mode)
+	for _, pkgPath := range cov.Packages() {
+		dir, hasDir := dirs[pkgPath]
+		if hasDir {
+			if abs, err := filepath.Abs(dir); err == nil {
+				dir = abs
+			}
+		}
+		for _, cb := range cov.Blocks(pkgPath) {
+			fname := pkgPath + "/" + cb.File
+			if hasDir {
+				fname = filepath.Join(dir, cb.File)
+			}
+			count := cb.Count
+			if mode == CoverModeSet && count > 1 {
+				count = 1
+			}
+			fmt.Fprintf(bw, "%s:%d.%d,%d.%d %d %d\n",
+				fname,
+				cb.Span.Line, cb.Span.Column,
+				cb.Span.End.Line, cb.Span.End.Column,
+				cb.NumStmt, count)
+		}
+	}
+	return bw.Flush()
+}
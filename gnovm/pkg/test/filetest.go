@@ -17,6 +17,7 @@ import (
 	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
 	teststd "github.com/gnolang/gno/gnovm/tests/stdlibs/std"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
 	"github.com/pmezard/go-difflib/difflib"
 	"go.uber.org/multierr"
 )
@@ -68,13 +69,20 @@ func (opts *TestOptions) runFiletest(fname string, source []byte, tgs gno.Store,
 
 	// Create machine for execution and run test
 	tcw := opts.BaseStore.CacheWrap()
+	var gasMeter storetypes.GasMeter
+	if opts.Profiler != nil {
+		gasMeter = opts.Profiler.GasMeter(storetypes.NewInfiniteGasMeter())
+	}
 	m := gno.NewMachineWithOptions(gno.MachineOptions{
 		Output:        &opts.outWriter,
-		Store:         tgs.BeginTransaction(tcw, tcw, nil),
+		Store:         tgs.BeginTransaction(tcw, tcw, gasMeter),
 		Context:       ctx,
 		MaxAllocBytes: maxAlloc,
 		Debug:         opts.Debug,
 		ReviveEnabled: true,
+		Coverage:      opts.Coverage,
+		Profiler:      opts.Profiler,
+		GasMeter:      gasMeter,
 	})
 	defer m.Release()
 
@@ -0,0 +1,461 @@
+package test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	storetypes "github.com/gnolang/gno/tm2/pkg/store/types"
+	"go.uber.org/multierr"
+)
+
+// defaultFuzzMinimizeTime is the time spent minimizing a failing input if
+// neither TestOptions.FuzzMinimizeTime or FuzzMinimizeTimeN are set.
+const defaultFuzzMinimizeTime = time.Minute
+
+// fuzzReport is a mirror of the report returned by Gno's testing.RunFuzz.
+type fuzzReport struct {
+	Failed  bool
+	Skipped bool
+}
+
+// fuzzTarget is the function passed to testing.F.Fuzz.
+type fuzzTarget struct {
+	fn    gno.TypedValue
+	types []gno.Type     // of the fuzzed parameters
+	rts   []reflect.Type // Go equivalents of types
+}
+
+var fuzzTypes = map[gno.Type]reflect.Type{
+	gno.StringType:  reflect.TypeFor[string](),
+	gno.BoolType:    reflect.TypeFor[bool](),
+	gno.IntType:     reflect.TypeFor[int](),
+	gno.Int8Type:    reflect.TypeFor[int8](),
+	gno.Int16Type:   reflect.TypeFor[int16](),
+	gno.Int32Type:   reflect.TypeFor[int32](),
+	gno.Int64Type:   reflect.TypeFor[int64](),
+	gno.UintType:    reflect.TypeFor[uint](),
+	gno.Uint8Type:   reflect.TypeFor[uint8](),
+	gno.Uint16Type:  reflect.TypeFor[uint16](),
+	gno.Uint32Type:  reflect.TypeFor[uint32](),
+	gno.Uint64Type:  reflect.TypeFor[uint64](),
+	gno.Float32Type: reflect.TypeFor[float32](),
+	gno.Float64Type: reflect.TypeFor[float64](),
+}
+
+// newFuzzTarget checks that fn is a valid fuzz target for the fuzz test name:
+// a function without results, whose first parameter is *testing.T and whose
+// other parameters have one of the supported types.
+func newFuzzTarget(name string, fn gno.TypedValue) (*fuzzTarget, error) {
+	ft, ok := gno.BaseOf(fn.T).(*gno.FuncType)
+	if !ok {
+		return nil, fmt.Errorf("%s: F.Fuzz must be passed a function, got %s", name, fn.T)
+	}
+	if len(ft.Results) > 0 {
+		return nil, fmt.Errorf("%s: fuzz target must not return a value", name)
+	}
+	if len(ft.Params) < 2 || !isTestingT(ft.Params[0].Type) {
+		return nil, fmt.Errorf("%s: fuzz target must have *testing.T as its first parameter, followed by at least one fuzzed parameter", name)
+	}
+	if ft.HasVarg() {
+		return nil, fmt.Errorf("%s: fuzz target must not be variadic", name)
+	}
+	tgt := &fuzzTarget{fn: fn}
+	for _, p := range ft.Params[1:] {
+		rt, ok := fuzzTypes[p.Type]
+		if st, isSlice := p.Type.(*gno.SliceType); isSlice && !st.Vrd && st.Elt == gno.Uint8Type {
+			rt, ok = reflect.TypeFor[[]byte](), true
+		}
+		if !ok {
+			return nil, fmt.Errorf("%s: unsupported type for fuzzing %s; "+
+				"supported types are string, []byte, bool, int, int8, int16, int32, int64, "+
+				"uint, uint8, uint16, uint32, uint64, float32 and float64", name, p.Type)
+		}
+		tgt.types = append(tgt.types, p.Type)
+		tgt.rts = append(tgt.rts, rt)
+	}
+	return tgt, nil
+}
+
+func isTestingT(t gno.Type) bool {
+	pt, ok := t.(*gno.PointerType)
+	if !ok {
+		return false
+	}
+	dt, ok := pt.Elt.(*gno.DeclaredType)
+	return ok && dt.PkgPath == "testing/base" && dt.Name == "T"
+}
+
+// check returns an error if vals cannot be passed to the fuzz target.
+func (tgt *fuzzTarget) check(vals []any) error {
+	if len(vals) != len(tgt.rts) {
+		return fmt.Errorf("wrong number of values in corpus entry: %d, want %d", len(vals), len(tgt.rts))
+	}
+	for i, v := range vals {
+		if reflect.TypeOf(v) != tgt.rts[i] {
+			return fmt.Errorf("mismatched types in corpus entry: %T, want %v", v, tgt.rts[i])
+		}
+	}
+	return nil
+}
+
+// zeroValues returns the zero value of each fuzzed parameter; used as the
+// initial input when the corpus is empty.
+func (tgt *fuzzTarget) zeroValues() []any {
+	vals := make([]any, len(tgt.rts))
+	for i, rt := range tgt.rts {
+		if rt.Kind() == reflect.Slice {
+			vals[i] = []byte{}
+		} else {
+			vals[i] = reflect.Zero(rt).Interface()
+		}
+	}
+	return vals
+}
+
+// run calls the fuzz target with vals, as a sub-test named name, through
+// testing.RunTest. It returns whether the test failed.
+func (tgt *fuzzTarget) run(m *gno.Machine, name, runFlag string, verbose bool, vals []any) (bool, error) {
+	testingcx := testingBaseExpr(m)
+	args := []any{gno.Nx("t")}
+	for i, v := range vals {
+		var tv gno.TypedValue
+		if b, ok := v.([]byte); ok {
+			// Go2GnoValue would create a list array; []byte uses data arrays.
+			tv.T = tgt.types[i]
+			tv.V = m.Alloc.NewSliceFromData(bytes.Clone(b))
+		} else {
+			tv = gno.Go2GnoValue(m.Alloc, m.Store, reflect.ValueOf(v))
+			tv.T = tgt.types[i]
+		}
+		args = append(args, &gno.ConstExpr{TypedValue: tv})
+	}
+	// func(t *testing.T) { fn(t, args...) }
+	fnx := gno.Fn(
+		gno.Flds("t", gno.Ptr(gno.Sel(testingcx, "T"))),
+		nil,
+		gno.Ss(&gno.ExprStmt{X: gno.Call(&gno.ConstExpr{TypedValue: tgt.fn}, args...)}),
+	)
+
+	eval := m.Eval(gno.Call(
+		gno.Sel(testingcx, "RunTest"),       // Call testing.RunTest
+		gno.Str(runFlag),                    // run flag
+		gno.Nx(strconv.FormatBool(verbose)), // is verbose?
+		gno.Nx("false"),                     // failfast
+		&gno.CompositeLitExpr{ // the testing.InternalTest
+			Type: gno.Sel(testingcx, "InternalTest"),
+			Elts: gno.KeyValueExprs{
+				{Key: gno.X("Name"), Value: gno.Str(name)},
+				{Key: gno.X("F"), Value: fnx},
+				{Key: gno.X("Cur"), Value: gno.Nx("nil")},
+			},
+		},
+	))
+
+	var rep report
+	if err := json.Unmarshal([]byte(eval[0].GetString()), &rep); err != nil {
+		return false, err
+	}
+	return rep.Failed, nil
+}
+
+func testingBaseExpr(m *gno.Machine) *gno.ConstExpr {
+	testingpv := m.Store.GetPackage("testing/base", false)
+	testingtv := gno.TypedValue{T: &gno.PackageType{}, V: testingpv}
+	return &gno.ConstExpr{TypedValue: testingtv}
+}
+
+// setupFuzzTest calls the fuzz test function fz through testing.RunFuzz,
+// and returns its fuzz target and its corpus: the seed corpus, followed by
+// the inputs in testdata/fuzz/FuzzXxx. tgt is nil if the fuzz test was
+// skipped, or did not call F.Fuzz.
+func (opts *TestOptions) setupFuzzTest(m *gno.Machine, fz testFunc, fsDir string) (tgt *fuzzTarget, corpus []fuzzCorpusEntry, err error) {
+	fv := m.Eval(gno.Nx(fz.Name))[0].GetFunc()
+	if fv.IsCrossing() {
+		return nil, nil, fmt.Errorf("%s: crossing fuzz tests are not supported", fz.Name)
+	}
+
+	testingcx := testingBaseExpr(m)
+	eval := m.Eval(gno.Call(
+		gno.Sel(testingcx, "RunFuzz"),            // Call testing.RunFuzz
+		gno.Nx(strconv.FormatBool(opts.Verbose)), // is verbose?
+		&gno.CompositeLitExpr{ // the testing.InternalFuzzTarget
+			Type: gno.Sel(testingcx, "InternalFuzzTarget"),
+			Elts: gno.KeyValueExprs{
+				{Key: gno.X("Name"), Value: gno.Str(fz.Name)},
+				{Key: gno.X("Fn"), Value: gno.Nx(fz.Name)},
+			},
+		},
+	))
+
+	var rep fuzzReport
+	if err := json.Unmarshal([]byte(eval[0].GetString()), &rep); err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case rep.Failed:
+		return nil, nil, fmt.Errorf("failed: %q", fz.Name)
+	case rep.Skipped || eval[2].T == nil:
+		return nil, nil, nil
+	}
+
+	tgt, err = newFuzzTarget(fz.Name, eval[2])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Read the seed corpus, from F.Add.
+	if sv, ok := eval[1].V.(*gno.SliceValue); ok {
+		list := sv.GetBase(m.Store).List[sv.Offset : sv.Offset+sv.Length]
+		for i, tv := range list {
+			vals, err := unmarshalFuzzValues([]byte(tv.GetString()))
+			if err == nil {
+				err = tgt.check(vals)
+			}
+			if err != nil {
+				return nil, nil, fmt.Errorf("%s: seed#%d: %w", fz.Name, i, err)
+			}
+			corpus = append(corpus, fuzzCorpusEntry{Name: "seed#" + strconv.Itoa(i), Values: vals})
+		}
+	}
+
+	// Read the corpus in testdata.
+	if fsDir != "" {
+		entries, err := readFuzzCorpus(fuzzCorpusDir(fsDir, fz.Name))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s: %w", fz.Name, err)
+		}
+		for _, entry := range entries {
+			if err := tgt.check(entry.Values); err != nil {
+				return nil, nil, fmt.Errorf("%s: %s: %w", fz.Name, entry.Name, err)
+			}
+		}
+		corpus = append(corpus, entries...)
+	}
+	return tgt, corpus, nil
+}
+
+func fuzzCorpusDir(fsDir, name string) string {
+	return filepath.Join(fsDir, "testdata", "fuzz", name)
+}
+
+// runFuzzTests runs the Fuzz functions in files matching opts.RunFlag like
+// tests: the fuzz target is called with each input of its corpus.
+// pv is the package value of mpkg, already set up by runTestFiles.
+func (opts *TestOptions) runFuzzTests(
+	mpkg *std.MemPackage,
+	files *gno.FileSet,
+	tgs gno.TransactionStore,
+	pv *gno.PackageValue,
+	gasMeter storetypes.GasMeter,
+	fsDir string,
+) (errs error) {
+	filter := splitRegexp(opts.RunFlag)
+	for _, fz := range loadTestFuncs(mpkg.Name, files, "Fuzz") {
+		if !shouldRun(filter, fz.Name) {
+			continue
+		}
+		m := Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug)
+		m.Coverage = opts.Coverage
+		opts.profile(m, gasMeter)
+		m.SetActivePackage(pv)
+
+		start := time.Now()
+		tgt, corpus, err := opts.setupFuzzTest(m, fz, fsDir)
+		if err != nil {
+			errs = multierr.Append(errs, err)
+			if opts.FailfastFlag {
+				return errs
+			}
+			continue
+		}
+		if tgt == nil {
+			continue
+		}
+
+		failed := false
+		for _, entry := range corpus {
+			subFailed, err := tgt.run(m, fz.Name+"/"+entry.Name, opts.RunFlag, opts.Verbose, entry.Values)
+			if err != nil {
+				return multierr.Append(errs, err)
+			}
+			failed = failed || subFailed
+			if failed && opts.FailfastFlag {
+				break
+			}
+		}
+
+		dstr := fmtDuration(time.Since(start))
+		if failed {
+			fmt.Fprintf(opts.Error, "--- FAIL: %s (%s)\n", fz.Name, dstr)
+			errs = multierr.Append(errs, fmt.Errorf("failed: %q", fz.Name))
+			if opts.FailfastFlag {
+				return errs
+			}
+		} else if opts.Verbose {
+			fmt.Fprintf(opts.Error, "--- PASS: %s (%s)\n", fz.Name, dstr)
+		}
+	}
+	return errs
+}
+
+// fuzz runs the fuzzing engine on the Fuzz function in files matching
+// opts.FuzzFlag: new inputs are generated by mutating those in the corpus,
+// keeping the ones reaching new statements. When an input makes the fuzz
+// target fail, it is minimized and written to testdata/fuzz/FuzzXxx.
+func (opts *TestOptions) fuzz(
+	mpkg *std.MemPackage,
+	files *gno.FileSet,
+	tgs gno.TransactionStore,
+	pv *gno.PackageValue,
+	fsDir string,
+) error {
+	var matches []testFunc
+	var names []string
+	filter := splitRegexp(opts.FuzzFlag)
+	for _, fz := range loadTestFuncs(mpkg.Name, files, "Fuzz") {
+		if shouldRun(filter, fz.Name) {
+			matches = append(matches, fz)
+			names = append(names, fz.Name)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return nil
+	case 1:
+	default:
+		return fmt.Errorf("will not fuzz, -fuzz matches more than one fuzz test: %s", strings.Join(names, ", "))
+	}
+	fz := matches[0]
+
+	// The inputs are run silently; the output of a failing input is shown
+	// by running it again, once minimized.
+	saveW, saveErrW := opts.outWriter.w, opts.outWriter.errW
+	opts.outWriter.w, opts.outWriter.errW = io.Discard, io.Discard
+	restoreOutput := func() {
+		opts.outWriter.w, opts.outWriter.errW = saveW, saveErrW
+	}
+	defer restoreOutput()
+
+	// Coverage is used to find interesting inputs, so the Machine gets its
+	// own instead of opts.Coverage.
+	cov := gno.NewCoverage()
+	m := Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug)
+	m.Coverage = cov
+	m.SetActivePackage(pv)
+
+	start := time.Now()
+	verbose := opts.Verbose
+	opts.Verbose = false
+	tgt, corpus, err := opts.setupFuzzTest(m, fz, fsDir)
+	opts.Verbose = verbose
+	if err != nil || tgt == nil {
+		return err
+	}
+	if len(corpus) == 0 {
+		corpus = append(corpus, fuzzCorpusEntry{Values: tgt.zeroValues()})
+	}
+	run := func(vals []any) bool {
+		failed, err := tgt.run(m, fz.Name, "", false, vals)
+		if err != nil {
+			panic(err)
+		}
+		return failed
+	}
+	status := func(format string, args ...any) {
+		elapsed := time.Since(start).Round(time.Second)
+		fmt.Fprintf(opts.Error, "fuzz: elapsed: %s, "+format+"\n", append([]any{elapsed}, args...)...)
+	}
+	report := func(entry fuzzCorpusEntry, written bool) error {
+		restoreOutput()
+		// Run the input again, with its output.
+		if _, err := tgt.run(m, fz.Name+"/"+entry.Name, "", opts.Verbose, entry.Values); err != nil {
+			return err
+		}
+		fmt.Fprintf(opts.Error, "--- FAIL: %s (%s)\n", fz.Name, fmtDuration(time.Since(start)))
+		if written {
+			fmt.Fprintf(opts.Error, "    Failing input written to %s\n",
+				filepath.ToSlash(filepath.Join("testdata", "fuzz", fz.Name, entry.Name)))
+			fmt.Fprintf(opts.Error, "    To re-run:\n    gno test -run=%s/%s\n", fz.Name, entry.Name)
+		}
+		return fmt.Errorf("failed: %q", fz.Name)
+	}
+
+	// fail minimizes vals, writes them to testdata and reports them.
+	fail := func(vals []any) error {
+		status("minimizing")
+		mz := &fuzzMinimizer{fails: run, n: opts.FuzzMinimizeTimeN}
+		switch {
+		case opts.FuzzMinimizeTimeN > 0:
+		case opts.FuzzMinimizeTime > 0:
+			mz.deadline = time.Now().Add(opts.FuzzMinimizeTime)
+		default:
+			mz.deadline = time.Now().Add(defaultFuzzMinimizeTime)
+		}
+		vals = mz.minimize(vals)
+
+		name, err := writeFuzzCorpusFile(fuzzCorpusDir(fsDir, fz.Name), vals)
+		if err != nil {
+			return multierr.Append(err, fmt.Errorf("failed: %q", fz.Name))
+		}
+		return report(fuzzCorpusEntry{Name: name, Values: vals}, true)
+	}
+
+	// Gather the baseline coverage, running the corpus.
+	for _, entry := range corpus {
+		if !run(entry.Values) {
+			continue
+		}
+		if entry.Name == "" {
+			// The zero values, as the corpus is empty.
+			return fail(entry.Values)
+		}
+		// Already part of the corpus; it is also reported when running the
+		// tests, unless -run excluded it.
+		return report(entry, false)
+	}
+	covered := cov.NumCovered()
+	status("gathering baseline coverage: %d/%d completed, now fuzzing", len(corpus), len(corpus))
+
+	mutator := newFuzzMutator(uint64(time.Now().UnixNano()))
+	var execs, interesting int
+	lastStatus := time.Now()
+	for {
+		if opts.FuzzTimeN > 0 && execs >= opts.FuzzTimeN ||
+			opts.FuzzTime > 0 && time.Since(start) >= opts.FuzzTime {
+			break
+		}
+
+		vals := mutator.mutate(corpus[mutator.r.IntN(len(corpus))].Values, corpus)
+		execs++
+		if run(vals) {
+			return fail(vals)
+		}
+
+		if n := cov.NumCovered(); n > covered {
+			covered = n
+			interesting++
+			corpus = append(corpus, fuzzCorpusEntry{Values: vals})
+		}
+		if time.Since(lastStatus) >= 3*time.Second {
+			lastStatus = time.Now()
+			status("execs: %d (%.0f/sec), new interesting: %d (total: %d)",
+				execs, float64(execs)/time.Since(start).Seconds(), interesting, len(corpus))
+		}
+	}
+	status("execs: %d (%.0f/sec), new interesting: %d (total: %d)",
+		execs, float64(execs)/time.Since(start).Seconds(), interesting, len(corpus))
+	if opts.Verbose {
+		restoreOutput()
+		fmt.Fprintf(opts.Error, "--- PASS: %s (%s)\n", fz.Name, fmtDuration(time.Since(start)))
+	}
+	return nil
+}
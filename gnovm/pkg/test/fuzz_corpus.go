@@ -0,0 +1,332 @@
+package test
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// fuzzEncodingHeader is the first line of the corpus files. The files use the
+// same format as Go's, so that they can be shared with (and written by hand
+// like) Go fuzz tests.
+const fuzzEncodingHeader = "go test fuzz v1"
+
+// fuzzCorpusEntry is an input of a fuzz target.
+type fuzzCorpusEntry struct {
+	// Name of the entry, used to name its sub-test: seed#N for the seed
+	// corpus, or the name of the file in testdata/fuzz/FuzzXxx.
+	Name string
+	// The arguments passed to the fuzz target, after *testing.T.
+	Values []any
+}
+
+// marshalFuzzValues encodes vals in the format of the corpus files.
+func marshalFuzzValues(vals []any) []byte {
+	var b bytes.Buffer
+	b.WriteString(fuzzEncodingHeader + "\n")
+	for _, val := range vals {
+		switch v := val.(type) {
+		case []byte:
+			fmt.Fprintf(&b, "[]byte(%q)\n", v)
+		case string:
+			fmt.Fprintf(&b, "string(%q)\n", v)
+		case float32:
+			if math.IsNaN(float64(v)) && math.Float32bits(v) != math.Float32bits(float32(math.NaN())) {
+				fmt.Fprintf(&b, "math.Float32frombits(0x%x)\n", math.Float32bits(v))
+			} else {
+				fmt.Fprintf(&b, "float32(%v)\n", v)
+			}
+		case float64:
+			if math.IsNaN(v) && math.Float64bits(v) != math.Float64bits(math.NaN()) {
+				fmt.Fprintf(&b, "math.Float64frombits(0x%x)\n", math.Float64bits(v))
+			} else {
+				fmt.Fprintf(&b, "float64(%v)\n", v)
+			}
+		default:
+			// bool, and all integer types.
+			fmt.Fprintf(&b, "%T(%v)\n", v, v)
+		}
+	}
+	return b.Bytes()
+}
+
+// unmarshalFuzzValues decodes the content of a corpus file.
+func unmarshalFuzzValues(b []byte) ([]any, error) {
+	lines := bytes.Split(b, []byte("\n"))
+	if len(lines) < 2 {
+		return nil, errors.New("must include version and at least one value")
+	}
+	if string(bytes.TrimSpace(lines[0])) != fuzzEncodingHeader {
+		return nil, fmt.Errorf("unknown encoding version: %s", lines[0])
+	}
+	var vals []any
+	for _, line := range lines[1:] {
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			continue
+		}
+		v, err := parseFuzzValue(string(line))
+		if err != nil {
+			return nil, fmt.Errorf("malformed line %q: %w", line, err)
+		}
+		vals = append(vals, v)
+	}
+	if len(vals) == 0 {
+		return nil, errors.New("must include version and at least one value")
+	}
+	return vals, nil
+}
+
+// parseFuzzValue parses a single line of a corpus file, which is a
+// conversion of a literal to one of the supported types, like int(42).
+func parseFuzzValue(line string) (any, error) {
+	expr, err := parser.ParseExpr(line)
+	if err != nil {
+		return nil, err
+	}
+	call, ok := expr.(*ast.CallExpr)
+	if !ok || len(call.Args) != 1 {
+		return nil, errors.New("expected call expression with one argument")
+	}
+	arg := call.Args[0]
+
+	switch fn := call.Fun.(type) {
+	case *ast.ArrayType:
+		// []byte("...")
+		if elt, ok := fn.Elt.(*ast.Ident); fn.Len != nil || !ok || elt.Name != "byte" {
+			return nil, errors.New("expected []byte")
+		}
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.STRING {
+			return nil, errors.New("string literal required for type []byte")
+		}
+		s, err := strconv.Unquote(lit.Value)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(s), nil
+
+	case *ast.SelectorExpr:
+		// math.Float64frombits(0x...), used for NaNs with a payload.
+		if x, ok := fn.X.(*ast.Ident); !ok || x.Name != "math" {
+			return nil, fmt.Errorf("invalid function %s", fn.Sel.Name)
+		}
+		lit, ok := arg.(*ast.BasicLit)
+		if !ok || lit.Kind != token.INT {
+			return nil, errors.New("integer literal required for float bits")
+		}
+		switch fn.Sel.Name {
+		case "Float32frombits":
+			bits, err := strconv.ParseUint(lit.Value, 0, 32)
+			if err != nil {
+				return nil, err
+			}
+			return math.Float32frombits(uint32(bits)), nil
+		case "Float64frombits":
+			bits, err := strconv.ParseUint(lit.Value, 0, 64)
+			if err != nil {
+				return nil, err
+			}
+			return math.Float64frombits(bits), nil
+		default:
+			return nil, fmt.Errorf("invalid function math.%s", fn.Sel.Name)
+		}
+
+	case *ast.Ident:
+		return parseFuzzLiteral(fn.Name, arg)
+
+	default:
+		return nil, errors.New("expected type conversion")
+	}
+}
+
+func parseFuzzLiteral(typ string, arg ast.Expr) (any, error) {
+	if typ == "bool" {
+		id, ok := arg.(*ast.Ident)
+		if !ok || (id.Name != "true" && id.Name != "false") {
+			return nil, errors.New("true or false required for type bool")
+		}
+		return id.Name == "true", nil
+	}
+
+	// Extract the literal, and its sign.
+	neg := false
+	if u, ok := arg.(*ast.UnaryExpr); ok {
+		switch u.Op {
+		case token.SUB:
+			neg = true
+		case token.ADD:
+		default:
+			return nil, fmt.Errorf("unsupported operator %s", u.Op)
+		}
+		arg = u.X
+	}
+	if id, ok := arg.(*ast.Ident); ok {
+		// NaN and Inf, as printed by the %v verb.
+		var f float64
+		switch id.Name {
+		case "NaN":
+			f = math.NaN()
+		case "Inf":
+			f = math.Inf(1)
+			if neg {
+				f = math.Inf(-1)
+			}
+		default:
+			return nil, fmt.Errorf("invalid identifier %s", id.Name)
+		}
+		switch typ {
+		case "float32":
+			return float32(f), nil
+		case "float64":
+			return f, nil
+		default:
+			return nil, fmt.Errorf("%s only supported for floats", id.Name)
+		}
+	}
+	lit, ok := arg.(*ast.BasicLit)
+	if !ok {
+		return nil, errors.New("literal value required")
+	}
+	val := lit.Value
+	if neg {
+		val = "-" + val
+	}
+
+	switch typ {
+	case "string":
+		if lit.Kind != token.STRING || neg {
+			return nil, errors.New("string literal required for type string")
+		}
+		return strconv.Unquote(val)
+	case "float32", "float64":
+		if lit.Kind != token.FLOAT && lit.Kind != token.INT {
+			return nil, fmt.Errorf("float or integer literal required for type %s", typ)
+		}
+		bits := 64
+		if typ == "float32" {
+			bits = 32
+		}
+		f, err := strconv.ParseFloat(val, bits)
+		if err != nil {
+			return nil, err
+		}
+		if typ == "float32" {
+			return float32(f), nil
+		}
+		return f, nil
+	}
+
+	// Integer types. byte and rune may also use character literals.
+	if lit.Kind == token.CHAR {
+		switch typ {
+		case "byte", "uint8", "rune", "int32":
+		default:
+			return nil, fmt.Errorf("character literal not supported for type %s", typ)
+		}
+		r, _, _, err := strconv.UnquoteChar(lit.Value[1:len(lit.Value)-1], '\'')
+		if err != nil {
+			return nil, err
+		}
+		val = strconv.Itoa(int(r))
+		if neg {
+			val = "-" + val
+		}
+	} else if lit.Kind != token.INT {
+		return nil, fmt.Errorf("integer literal required for type %s", typ)
+	}
+	switch typ {
+	case "int", "int8", "int16", "int32", "rune", "int64":
+		n, err := strconv.ParseInt(val, 0, intBits(typ))
+		if err != nil {
+			return nil, err
+		}
+		rv := reflect.New(fuzzTypeByName[typ]).Elem()
+		rv.SetInt(n)
+		return rv.Interface(), nil
+	case "uint", "uint8", "byte", "uint16", "uint32", "uint64":
+		n, err := strconv.ParseUint(val, 0, intBits(typ))
+		if err != nil {
+			return nil, err
+		}
+		rv := reflect.New(fuzzTypeByName[typ]).Elem()
+		rv.SetUint(n)
+		return rv.Interface(), nil
+	default:
+		return nil, fmt.Errorf("unsupported type %s", typ)
+	}
+}
+
+var fuzzTypeByName = map[string]reflect.Type{
+	"int":    reflect.TypeFor[int](),
+	"int8":   reflect.TypeFor[int8](),
+	"int16":  reflect.TypeFor[int16](),
+	"int32":  reflect.TypeFor[int32](),
+	"rune":   reflect.TypeFor[int32](),
+	"int64":  reflect.TypeFor[int64](),
+	"uint":   reflect.TypeFor[uint](),
+	"uint8":  reflect.TypeFor[uint8](),
+	"byte":   reflect.TypeFor[uint8](),
+	"uint16": reflect.TypeFor[uint16](),
+	"uint32": reflect.TypeFor[uint32](),
+	"uint64": reflect.TypeFor[uint64](),
+}
+
+func intBits(typ string) int {
+	return fuzzTypeByName[typ].Bits()
+}
+
+// readFuzzCorpus reads the corpus files in dir, sorted by name.
+// A missing directory is not an error.
+func readFuzzCorpus(dir string) ([]fuzzCorpusEntry, error) {
+	files, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].Name() < files[j].Name()
+	})
+	var entries []fuzzCorpusEntry
+	for _, file := range files {
+		if file.IsDir() || strings.HasPrefix(file.Name(), ".") {
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(dir, file.Name()))
+		if err != nil {
+			return nil, err
+		}
+		vals, err := unmarshalFuzzValues(b)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal %q: %w", filepath.Join(dir, file.Name()), err)
+		}
+		entries = append(entries, fuzzCorpusEntry{Name: file.Name(), Values: vals})
+	}
+	return entries, nil
+}
+
+// writeFuzzCorpusFile writes vals to a new file in dir, named after the hash
+// of its contents like Go does, and returns the name of the file.
+func writeFuzzCorpusFile(dir string, vals []any) (string, error) {
+	b := marshalFuzzValues(vals)
+	name := fmt.Sprintf("%x", sha256.Sum256(b))[:16]
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(dir, name), b, 0o644); err != nil {
+		return "", err
+	}
+	return name, nil
+}
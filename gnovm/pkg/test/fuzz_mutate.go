@@ -0,0 +1,355 @@
+package test
+
+import (
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"time"
+)
+
+// fuzzMaxLen is the maximum length of the strings and byte slices generated
+// by the mutator. It is kept small, as inputs are run by the GnoVM.
+const fuzzMaxLen = 4096
+
+// fuzzMutator generates new inputs by randomly mutating existing ones.
+type fuzzMutator struct {
+	r *rand.Rand
+}
+
+func newFuzzMutator(seed uint64) *fuzzMutator {
+	return &fuzzMutator{r: rand.New(rand.NewPCG(seed, seed))}
+}
+
+// mutate returns a copy of vals with a few random mutations applied.
+// corpus is used to splice parts of other inputs into strings and []byte.
+func (fm *fuzzMutator) mutate(vals []any, corpus []fuzzCorpusEntry) []any {
+	res := make([]any, len(vals))
+	copy(res, vals)
+	for range 1 + fm.r.IntN(4) {
+		i := fm.r.IntN(len(res))
+		switch v := res[i].(type) {
+		case bool:
+			res[i] = !v
+		case string:
+			res[i] = string(fm.mutateBytes([]byte(v), fm.splice(corpus, i)))
+		case []byte:
+			res[i] = fm.mutateBytes(append([]byte(nil), v...), fm.splice(corpus, i))
+		case float32:
+			res[i] = float32(fm.mutateFloat(float64(v)))
+		case float64:
+			res[i] = fm.mutateFloat(v)
+		default:
+			res[i] = fm.mutateInt(v)
+		}
+	}
+	return res
+}
+
+// splice returns the i-th value of a random corpus entry as bytes, if it is
+// a string or a []byte.
+func (fm *fuzzMutator) splice(corpus []fuzzCorpusEntry, i int) []byte {
+	if len(corpus) == 0 {
+		return nil
+	}
+	vals := corpus[fm.r.IntN(len(corpus))].Values
+	if i >= len(vals) {
+		return nil
+	}
+	switch v := vals[i].(type) {
+	case string:
+		return []byte(v)
+	case []byte:
+		return v
+	}
+	return nil
+}
+
+var fuzzInterestingBytes = []byte{0, 1, '\n', ' ', '"', '%', '-', '0', '9', 'A', 'z', 0x7f, 0x80, 0xff}
+
+func (fm *fuzzMutator) mutateBytes(b, other []byte) []byte {
+	if len(b) == 0 {
+		// Only insertions are possible.
+		return fm.insertBytes(b, other)
+	}
+	switch fm.r.IntN(8) {
+	case 0, 1:
+		return fm.insertBytes(b, other)
+	case 2:
+		// Remove a range.
+		pos := fm.r.IntN(len(b))
+		n := 1 + fm.r.IntN(min(len(b)-pos, 8))
+		return append(b[:pos], b[pos+n:]...)
+	case 3:
+		// Replace a byte with a random one.
+		b[fm.r.IntN(len(b))] = byte(fm.r.UintN(256))
+	case 4:
+		// Flip a bit.
+		b[fm.r.IntN(len(b))] ^= 1 << fm.r.UintN(8)
+	case 5:
+		// Replace a byte with an interesting one.
+		b[fm.r.IntN(len(b))] = fuzzInterestingBytes[fm.r.IntN(len(fuzzInterestingBytes))]
+	case 6:
+		// Swap two bytes.
+		i, j := fm.r.IntN(len(b)), fm.r.IntN(len(b))
+		b[i], b[j] = b[j], b[i]
+	case 7:
+		// Increment or decrement a byte.
+		i := fm.r.IntN(len(b))
+		if fm.r.IntN(2) == 0 {
+			b[i]++
+		} else {
+			b[i]--
+		}
+	}
+	return b
+}
+
+// insertBytes inserts into b random bytes, a copy of one of its ranges, or a
+// range of other.
+func (fm *fuzzMutator) insertBytes(b, other []byte) []byte {
+	if len(b) >= fuzzMaxLen {
+		return b
+	}
+	var ins []byte
+	switch src := fm.r.IntN(3); {
+	case src == 1 && len(b) > 0:
+		other = b
+		fallthrough
+	case src == 2 && len(other) > 0:
+		start := fm.r.IntN(len(other))
+		n := 1 + fm.r.IntN(min(len(other)-start, 16))
+		ins = append(ins, other[start:start+n]...)
+	default:
+		ins = make([]byte, 1+fm.r.IntN(4))
+		for i := range ins {
+			if fm.r.IntN(2) == 0 {
+				ins[i] = fuzzInterestingBytes[fm.r.IntN(len(fuzzInterestingBytes))]
+			} else {
+				ins[i] = byte(fm.r.UintN(256))
+			}
+		}
+	}
+	if len(b)+len(ins) > fuzzMaxLen {
+		ins = ins[:fuzzMaxLen-len(b)]
+	}
+	pos := fm.r.IntN(len(b) + 1)
+	res := make([]byte, 0, len(b)+len(ins))
+	res = append(res, b[:pos]...)
+	res = append(res, ins...)
+	return append(res, b[pos:]...)
+}
+
+// mutateInt mutates any integer type, through reflection.
+// The result is truncated to the size of the type.
+func (fm *fuzzMutator) mutateInt(v any) any {
+	rv := reflect.New(reflect.TypeOf(v)).Elem()
+	rv.Set(reflect.ValueOf(v))
+	bits := rv.Type().Bits()
+	signed := rv.CanInt()
+
+	var n uint64
+	if signed {
+		n = uint64(rv.Int())
+	} else {
+		n = rv.Uint()
+	}
+	switch fm.r.IntN(5) {
+	case 0:
+		n += 1 + fm.r.Uint64N(16)
+	case 1:
+		n -= 1 + fm.r.Uint64N(16)
+	case 2:
+		n ^= 1 << fm.r.IntN(bits)
+	case 3:
+		// Interesting values: 0, 1, -1 and the limits of the type.
+		switch fm.r.IntN(5) {
+		case 0:
+			n = 0
+		case 1:
+			n = 1
+		case 2:
+			n = math.MaxUint64
+		case 3:
+			n = math.MaxUint64 >> (64 - bits) // max unsigned
+			if signed {
+				n >>= 1 // max signed
+			}
+		case 4:
+			n = 0
+			if signed {
+				n = 1 << (bits - 1) // min signed
+			}
+		}
+	case 4:
+		n = fm.r.Uint64()
+	}
+	if signed {
+		// Sign-extend the lower bits, so that SetInt does not overflow.
+		rv.SetInt(int64(n<<(64-bits)) >> (64 - bits))
+	} else {
+		rv.SetUint(n & (math.MaxUint64 >> (64 - bits)))
+	}
+	return rv.Interface()
+}
+
+func (fm *fuzzMutator) mutateFloat(f float64) float64 {
+	switch fm.r.IntN(6) {
+	case 0:
+		return f + float64(fm.r.IntN(33)-16)
+	case 1:
+		return f * 2
+	case 2:
+		return f / 2
+	case 3:
+		return -f
+	case 4:
+		interesting := []float64{0, 1, -1, 0.5, math.MaxFloat64, math.SmallestNonzeroFloat64, math.Inf(1), math.Inf(-1), math.NaN()}
+		return interesting[fm.r.IntN(len(interesting))]
+	default:
+		return fm.r.NormFloat64() * math.Pow(10, float64(fm.r.IntN(20)-10))
+	}
+}
+
+// fuzzMinimizer simplifies a failing input, keeping every simplification
+// for which the input still fails.
+type fuzzMinimizer struct {
+	fails    func(vals []any) bool
+	deadline time.Time // or zero, if limited by n
+	n        int       // remaining runs, if deadline is zero
+}
+
+// done reports whether the minimizer exhausted its budget.
+func (mz *fuzzMinimizer) done() bool {
+	if mz.deadline.IsZero() {
+		return mz.n <= 0
+	}
+	return time.Now().After(mz.deadline)
+}
+
+// try reports whether the candidate vals still fail.
+func (mz *fuzzMinimizer) try(vals []any) bool {
+	mz.n--
+	return mz.fails(vals)
+}
+
+func (mz *fuzzMinimizer) minimize(vals []any) []any {
+	vals = append([]any(nil), vals...)
+	for i := range vals {
+		// try replaces the i-th value with v, keeping it if it still fails.
+		try := func(v any) bool {
+			if mz.done() {
+				return false
+			}
+			prev := vals[i]
+			vals[i] = v
+			if mz.try(vals) {
+				return true
+			}
+			vals[i] = prev
+			return false
+		}
+
+		switch v := vals[i].(type) {
+		case bool:
+			if v {
+				try(false)
+			}
+		case string:
+			mz.minimizeBytes([]byte(v), func(b []byte) bool { return try(string(b)) })
+		case []byte:
+			mz.minimizeBytes(v, func(b []byte) bool { return try(b) })
+		case float32:
+			mz.minimizeFloat(float64(v), func(f float64) bool { return try(float32(f)) })
+		case float64:
+			mz.minimizeFloat(v, func(f float64) bool { return try(f) })
+		default:
+			mz.minimizeInt(v, try)
+		}
+	}
+	return vals
+}
+
+// minimizeBytes shortens b, then replaces its bytes with more readable ones,
+// calling try with each candidate; try reports whether it was kept.
+func (mz *fuzzMinimizer) minimizeBytes(b []byte, try func([]byte) bool) {
+	candidate := func(c []byte) bool {
+		// Pass a copy, as the fuzz target may keep it.
+		if try(append([]byte(nil), c...)) {
+			b = c
+			return true
+		}
+		return false
+	}
+
+	// Cut the tail, then remove chunks of decreasing size.
+	for len(b) > 0 && candidate(b[:len(b)/2]) {
+	}
+	for size := len(b) / 2; size > 0 && !mz.done(); size /= 2 {
+		for pos := 0; pos+size <= len(b) && !mz.done(); {
+			c := append(append([]byte(nil), b[:pos]...), b[pos+size:]...)
+			if !candidate(c) {
+				pos += size
+			}
+		}
+	}
+	// Make the remaining bytes human-readable.
+	for pos := 0; pos < len(b) && !mz.done(); pos++ {
+		if b[pos] == '0' || b[pos] == 'a' {
+			continue
+		}
+		for _, r := range []byte{'0', 'a'} {
+			c := append([]byte(nil), b...)
+			c[pos] = r
+			if candidate(c) {
+				break
+			}
+		}
+	}
+}
+
+func (mz *fuzzMinimizer) minimizeInt(v any, try func(any) bool) {
+	rv := reflect.ValueOf(v)
+	set := func(n int64) bool {
+		c := reflect.New(rv.Type()).Elem()
+		if c.CanInt() {
+			c.SetInt(n)
+		} else {
+			c.SetUint(uint64(n))
+		}
+		return try(c.Interface())
+	}
+	var n int64
+	if rv.CanInt() {
+		n = rv.Int()
+	} else if rv.Uint() > math.MaxInt64 {
+		// Halve it first, as it doesn't fit into an int64.
+		if !try(reflect.ValueOf(rv.Uint() / 2).Convert(rv.Type()).Interface()) {
+			return
+		}
+		n = int64(rv.Uint() / 2)
+	} else {
+		n = int64(rv.Uint())
+	}
+	if n == 0 || set(0) {
+		return
+	}
+	// Move the value towards 0.
+	for n/10 != 0 && set(n/10) {
+		n /= 10
+	}
+	for n/2 != 0 && set(n/2) {
+		n /= 2
+	}
+}
+
+func (mz *fuzzMinimizer) minimizeFloat(f float64, try func(float64) bool) {
+	if f == 0 || try(0) {
+		return
+	}
+	if t := math.Trunc(f); t != f && !math.IsInf(f, 0) && !math.IsNaN(f) && try(t) {
+		f = t
+	}
+	for math.Abs(f) > 1 && !math.IsInf(f, 0) && try(math.Trunc(f/10)) {
+		f = math.Trunc(f / 10)
+	}
+}
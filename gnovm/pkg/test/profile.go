@@ -0,0 +1,207 @@
+package test
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"path/filepath"
+
+	gno "github.com/gnolang/gno/gnovm/pkg/gnolang"
+)
+
+// Profile types, for [WriteProfile].
+const (
+	ProfileCPU = "cpu" // CPU cycles of the GnoVM
+	ProfileGas = "gas" // gas, including the gas used by the store
+)
+
+// WriteProfile writes the CPU cycles or the gas recorded by p to w, as a
+// gzipped pprof protocol buffer, which can be inspected with `go tool pprof`.
+// Gas samples are labeled with the kind of gas consumed ("descriptor"), to be
+// used with the -tagfocus and -tagignore flags of pprof.
+//
+// dirs maps each package path to the directory containing its files; like
+// for [WriteCoverProfile], it is used to write absolute file names, so that
+// `go tool pprof -list` can find the source code.
+func WriteProfile(w io.Writer, p *gno.Profiler, typ string, dirs map[string]string) error {
+	var (
+		samples    []gno.ProfileSample
+		sampleType string
+		unit       string
+	)
+	switch typ {
+	case ProfileCPU:
+		samples, sampleType, unit = p.CPUSamples(), "cycles", "count"
+	case ProfileGas:
+		samples, sampleType, unit = p.GasSamples(), "gas", "gas"
+	default:
+		return fmt.Errorf("invalid profile type %q", typ)
+	}
+
+	pw := newProfileWriter()
+	var prof protoBuffer
+	valueType := func(typ, unit string) []byte {
+		var vt protoBuffer
+		vt.int(1, pw.str(typ))
+		vt.int(2, pw.str(unit))
+		return vt.buf
+	}
+	prof.bytes(1, valueType(sampleType, unit))
+	// A single mapping, so that pprof does not try to symbolize the
+	// locations, which already have their function and line.
+	var mapping protoBuffer
+	mapping.int(1, 1)
+	mapping.int(5, pw.str("gnovm"))
+	mapping.int(7, 1) // has_functions
+	mapping.int(8, 1) // has_filenames
+	mapping.int(9, 1) // has_line_numbers
+	prof.bytes(3, mapping.buf)
+	for _, s := range samples {
+		var sample protoBuffer
+		locs := make([]uint64, len(s.Stack))
+		for i, f := range s.Stack {
+			locs[i] = pw.location(f, dirs)
+		}
+		sample.uints(1, locs)
+		sample.uints(2, []uint64{uint64(s.Value)})
+		if s.Descriptor != "" {
+			var label protoBuffer
+			label.int(1, pw.str("descriptor"))
+			label.int(2, pw.str(s.Descriptor))
+			sample.bytes(3, label.buf)
+		}
+		prof.bytes(2, sample.buf)
+	}
+	for _, loc := range pw.locs {
+		prof.bytes(4, loc)
+	}
+	for _, fn := range pw.funcs {
+		prof.bytes(5, fn)
+	}
+	for _, s := range pw.strs {
+		prof.str(6, s)
+	}
+	prof.bytes(11, valueType(sampleType, unit)) // period type
+	prof.int(12, 1)                             // period
+
+	zw := gzip.NewWriter(w)
+	if _, err := zw.Write(prof.buf); err != nil {
+		return err
+	}
+	return zw.Close()
+}
+
+// profileWriter holds the tables of a pprof profile: strings, functions and
+// locations are referenced by index or ID from the samples.
+type profileWriter struct {
+	strs    []string
+	strIdx  map[string]int
+	funcs   [][]byte
+	funcIDs map[gno.ProfileFrame]uint64 // keyed by frame without lines
+	locs    [][]byte
+	locIDs  map[gno.ProfileFrame]uint64
+}
+
+func newProfileWriter() *profileWriter {
+	return &profileWriter{
+		strs:    []string{""}, // index 0 must be the empty string
+		strIdx:  map[string]int{"": 0},
+		funcIDs: map[gno.ProfileFrame]uint64{},
+		locIDs:  map[gno.ProfileFrame]uint64{},
+	}
+}
+
+func (pw *profileWriter) str(s string) uint64 {
+	i, ok := pw.strIdx[s]
+	if !ok {
+		i = len(pw.strs)
+		pw.strs = append(pw.strs, s)
+		pw.strIdx[s] = i
+	}
+	return uint64(i)
+}
+
+// location returns the ID of the location of f, adding it if needed.
+func (pw *profileWriter) location(f gno.ProfileFrame, dirs map[string]string) uint64 {
+	if id, ok := pw.locIDs[f]; ok {
+		return id
+	}
+	fkey := f
+	fkey.Line = 0
+	fid, ok := pw.funcIDs[fkey]
+	if !ok {
+		fid = uint64(len(pw.funcs) + 1)
+		name := f.Func
+		if f.PkgPath != "" {
+			name = f.PkgPath + "." + name
+		}
+		fname := f.File
+		if dir, ok := dirs[f.PkgPath]; ok && f.File != "" {
+			if abs, err := filepath.Abs(dir); err == nil {
+				dir = abs
+			}
+			fname = filepath.Join(dir, f.File)
+		} else if f.PkgPath != "" && f.File != "" {
+			fname = f.PkgPath + "/" + f.File
+		}
+		var fn protoBuffer
+		fn.int(1, fid)
+		fn.int(2, pw.str(name))
+		fn.int(3, pw.str(name))
+		fn.int(4, pw.str(fname))
+		fn.int(5, uint64(f.StartLine))
+		pw.funcs = append(pw.funcs, fn.buf)
+		pw.funcIDs[fkey] = fid
+	}
+
+	id := uint64(len(pw.locs) + 1)
+	var line, loc protoBuffer
+	line.int(1, fid)
+	line.int(2, uint64(f.Line))
+	loc.int(1, id)
+	loc.int(2, 1) // mapping ID
+	loc.bytes(4, line.buf)
+	pw.locs = append(pw.locs, loc.buf)
+	pw.locIDs[f] = id
+	return id
+}
+
+// protoBuffer encodes protocol buffer messages.
+type protoBuffer struct {
+	buf []byte
+}
+
+func (b *protoBuffer) varint(x uint64) {
+	for x >= 0x80 {
+		b.buf = append(b.buf, byte(x)|0x80)
+		x >>= 7
+	}
+	b.buf = append(b.buf, byte(x))
+}
+
+func (b *protoBuffer) int(field int, x uint64) {
+	if x == 0 {
+		return
+	}
+	b.varint(uint64(field)<<3 | 0) // varint wire type
+	b.varint(x)
+}
+
+func (b *protoBuffer) bytes(field int, bz []byte) {
+	b.varint(uint64(field)<<3 | 2) // length-delimited wire type
+	b.varint(uint64(len(bz)))
+	b.buf = append(b.buf, bz...)
+}
+
+func (b *protoBuffer) str(field int, s string) {
+	b.bytes(field, []byte(s))
+}
+
+// uints writes xs as a packed repeated field.
+func (b *protoBuffer) uints(field int, xs []uint64) {
+	var packed protoBuffer
+	for _, x := range xs {
+		packed.varint(x)
+	}
+	b.bytes(field, packed.buf)
+}
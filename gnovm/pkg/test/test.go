@@ -128,6 +128,9 @@ type TestOptions struct {
 	Error io.Writer
 	// Debug enables the interactive debugger on gno tests.
 	Debug bool
+	// DebugDAP, if set with Debug, drives the debugger using the Debug
+	// Adapter Protocol instead of stdin and stdout.
+	DebugDAP *gno.DAPServer
 
 	// Not set by NewTestOptions:
 
@@ -144,12 +147,46 @@ type TestOptions struct {
 	Metrics bool
 	// Uses Error to print the events emitted.
 	Events bool
+	// If set, records the statements executed by the tests; see
+	// [WriteCoverProfile].
+	Coverage *gno.Coverage
+	// If set, records the CPU cycles and gas consumed by the tests; see
+	// [WriteProfile].
+	Profiler *gno.Profiler
+	// Flag to filter benchmarks to run. Benchmarks are only run if it is set.
+	BenchFlag string
+	// Minimum duration of each benchmark; ignored if BenchTimeN is set.
+	BenchTime time.Duration
+	// If non-zero, exact number of iterations to run each benchmark for.
+	BenchTimeN int
+	// Flag to select the fuzz test to fuzz. Fuzzing only happens if it is
+	// set, and it must match a single fuzz test of the package.
+	FuzzFlag string
+	// Time to spend fuzzing; ignored if FuzzTimeN is set. If both are
+	// zero, fuzzing runs until a failing input is found.
+	FuzzTime time.Duration
+	// If non-zero, number of inputs to try while fuzzing.
+	FuzzTimeN int
+	// Time to spend minimizing a failing input; ignored if
+	// FuzzMinimizeTimeN is set. Defaults to one minute.
+	FuzzMinimizeTime time.Duration
+	// If non-zero, number of inputs to try while minimizing.
+	FuzzMinimizeTimeN int
 
 	filetestBuffer bytes.Buffer
 	outWriter      proxyWriter
 	tcCache        gno.TypeCheckCache
 }
 
+// profile sets up m to record its CPU cycles and gas into opts.Profiler, if
+// set. gasMeter must be the gas meter of the store used by m.
+func (opts *TestOptions) profile(m *gno.Machine, gasMeter storetypes.GasMeter) {
+	if opts.Profiler != nil {
+		m.Profiler = opts.Profiler
+		m.GasMeter = gasMeter
+	}
+}
+
 // WriterForStore is the writer that should be passed to [Store], so that
 // [Test] is then able to swap it when needed.
 func (opts *TestOptions) WriterForStore() io.Writer {
@@ -225,11 +262,24 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 
 	var errs error
 
+	if opts.Coverage != nil {
+		opts.addCoverageFiles(mpkg)
+	}
+
 	// Create a common tcw/tgs for both the `pkg` tests as well as the
 	// `pkg_test` tests. This allows us to "export" symbols from the pkg
 	// tests and import them from the `pkg_test` tests.
 	tcw := opts.BaseStore.CacheWrap()
-	tgs := opts.TestStore.BeginTransaction(tcw, tcw, nil)
+	// Benchmarks and profiles measure both the gas consumed by the Machine
+	// and by the store, so they use the same gas meter.
+	var gasMeter storetypes.GasMeter
+	if opts.BenchFlag != "" || opts.Profiler != nil {
+		gasMeter = storetypes.NewInfiniteGasMeter()
+	}
+	if opts.Profiler != nil {
+		gasMeter = opts.Profiler.GasMeter(gasMeter)
+	}
+	tgs := opts.TestStore.BeginTransaction(tcw, tcw, gasMeter)
 
 	// Let opts.TestStore load itself.
 	// This needs to happen before LoadImports, as LoadImports will
@@ -246,7 +296,9 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 		// new packages by default, which we don't want.  Instead we
 		// will run the mempackage ourselves in the next line.
 		SkipPackage: true,
+		Coverage:    opts.Coverage,
 	})
+	opts.profile(m2, gasMeter)
 	// Filter out xxx_test *_test.gno and *_filetest.gno and run.
 	// If testing with only filetests, there will be no files.
 	tmpkg := gno.MPFTest.FilterMemPackage(mpkg)
@@ -269,7 +321,7 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 	if len(tset.Files)+len(itset.Files) > 0 {
 		// Run test files in pkg.
 		if len(tset.Files) > 0 {
-			err := opts.runTestFiles(mpkg, tset, tgs)
+			err := opts.runTestFiles(mpkg, tset, tgs, gasMeter, fsDir)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -290,7 +342,7 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 				Files: itfiles,
 			}
 
-			err := opts.runTestFiles(itmpkg, itset, tgs)
+			err := opts.runTestFiles(itmpkg, itset, tgs, gasMeter, fsDir)
 			if err != nil {
 				errs = multierr.Append(errs, err)
 			}
@@ -344,13 +396,16 @@ func Test(mpkg *std.MemPackage, fsDir string, opts *TestOptions) error {
 	return errs
 }
 
-// Runs *_test.go tests.
+// Runs *_test.go tests and fuzz tests, then fuzzing if opts.FuzzFlag is set
+// and benchmarks if opts.BenchFlag is set.
 // Not the same as pkg/test/filetest runFiletests()
 // which runs *_filetest.go tests.
 func (opts *TestOptions) runTestFiles(
 	mpkg *std.MemPackage,
 	files *gno.FileSet,
 	tgs gno.TransactionStore,
+	gasMeter storetypes.GasMeter,
+	fsDir string,
 ) (errs error) {
 	var m *gno.Machine
 	defer func() {
@@ -366,7 +421,7 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}()
 
-	tests := loadTestFuncs(mpkg.Name, files)
+	tests := loadTestFuncs(mpkg.Name, files, "Test")
 
 	var alloc *gno.Allocator
 	if opts.Metrics {
@@ -378,6 +433,8 @@ func (opts *TestOptions) runTestFiles(
 	// Check if we already have the package - it may have been eagerly loaded.
 	m = Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug)
 	m.Alloc = alloc
+	m.Coverage = opts.Coverage
+	opts.profile(m, gasMeter)
 	if tgs.GetMemPackage(mpkg.Path) == nil {
 		m.RunMemPackage(mpkg, false)
 	} else {
@@ -399,6 +456,8 @@ func (opts *TestOptions) runTestFiles(
 		// - Wrap here.
 		m = Machine(tgs, opts.WriterForStore(), mpkg.Path, opts.Debug)
 		m.Alloc = alloc.Reset()
+		m.Coverage = opts.Coverage
+		opts.profile(m, gasMeter)
 		m.SetActivePackage(pv)
 
 		testingpv := m.Store.GetPackage("testing/base", false)
@@ -459,7 +518,11 @@ func (opts *TestOptions) runTestFiles(
 				}
 				return string(b)
 			}
-			m.Debugger.Enable(os.Stdin, os.Stdout, fileContent)
+			if opts.DebugDAP != nil {
+				m.Debugger.EnableDAP(opts.DebugDAP, fileContent)
+			} else {
+				m.Debugger.Enable(os.Stdin, os.Stdout, fileContent)
+			}
 		}
 
 		eval := m.Eval(gno.Call(
@@ -533,6 +596,16 @@ func (opts *TestOptions) runTestFiles(
 		}
 	}
 
+	errs = multierr.Append(errs, opts.runFuzzTests(mpkg, files, tgs, pv, gasMeter, fsDir))
+
+	// Like Go, only fuzz and run benchmarks if all the tests passed.
+	if errs == nil && opts.FuzzFlag != "" {
+		errs = opts.fuzz(mpkg, files, tgs, pv, fsDir)
+	}
+	if errs == nil && opts.BenchFlag != "" {
+		errs = opts.runBenchmarks(mpkg, files, tgs, pv, gasMeter)
+	}
+
 	return errs
 }
 
@@ -548,7 +621,9 @@ type testFunc struct {
 	Filename string
 }
 
-func loadTestFuncs(pkgName string, tfiles *gno.FileSet) (rt []testFunc) {
+// loadTestFuncs returns the top-level functions in tfiles whose name starts
+// with prefix, like "Test" or "Benchmark".
+func loadTestFuncs(pkgName string, tfiles *gno.FileSet, prefix string) (rt []testFunc) {
 	for _, tf := range tfiles.Files {
 		for _, d := range tf.Decls {
 			if fd, ok := d.(*gno.FuncDecl); ok {
@@ -556,7 +631,7 @@ func loadTestFuncs(pkgName string, tfiles *gno.FileSet) (rt []testFunc) {
 					continue
 				}
 				fname := string(fd.Name)
-				if strings.HasPrefix(fname, "Test") {
+				if strings.HasPrefix(fname, prefix) {
 					tf := testFunc{
 						Package:  pkgName,
 						Name:     fname,
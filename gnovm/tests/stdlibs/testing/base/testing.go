@@ -26,3 +26,14 @@ func X_recoverWithStacktrace(m *gnolang.Machine) (gnolang.TypedValue, string) {
 	}
 	return exception.Value, exception.Stacktrace.String()
 }
+
+func X_vmMetrics(m *gnolang.Machine) (cycles, gas, alloc int64) {
+	cycles = m.Cycles
+	if m.GasMeter != nil {
+		gas = m.GasMeter.GasConsumed()
+	}
+	if m.Alloc != nil {
+		_, alloc = m.Alloc.Status()
+	}
+	return
+}
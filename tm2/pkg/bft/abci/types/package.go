@@ -37,6 +37,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		RequestDeliverTx{},
 		RequestEndBlock{},
 		RequestCommit{},
+		RequestListSnapshots{},
+		RequestOfferSnapshot{},
+		RequestLoadSnapshotChunk{},
+		RequestApplySnapshotChunk{},
 
 		// response types
 		ResponseBase{},
@@ -52,6 +56,10 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ResponseDeliverTx{},
 		ResponseEndBlock{},
 		ResponseCommit{},
+		ResponseListSnapshots{},
+		ResponseOfferSnapshot{},
+		ResponseLoadSnapshotChunk{},
+		ResponseApplySnapshotChunk{},
 
 		// error types
 		StringError(""),
@@ -63,6 +71,7 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 		ValidatorUpdate{},
 		LastCommitInfo{},
 		VoteInfo{},
+		Snapshot{},
 		// Validator{},
 		// Violation{},
 
@@ -75,6 +75,10 @@ type RequestCheckTx struct {
 	RequestBase
 	Tx   []byte
 	Type CheckTxType
+
+	// Set by the priority mempool, which replaces the pending tx of a same
+	// sender and sequence, see ResponseCheckTx.Replaces.
+	AllowReplacement bool
 }
 
 type RequestDeliverTx struct {
@@ -91,6 +95,34 @@ type RequestCommit struct {
 	RequestBase
 }
 
+type RequestListSnapshots struct {
+	RequestBase
+}
+
+// RequestOfferSnapshot offers a snapshot to restore, along with the app hash
+// of its height, trusted by the node.
+type RequestOfferSnapshot struct {
+	RequestBase
+	Snapshot *Snapshot
+	AppHash  []byte
+}
+
+type RequestLoadSnapshotChunk struct {
+	RequestBase
+	Height int64
+	Format uint32
+	Chunk  uint32
+}
+
+// RequestApplySnapshotChunk applies the chunks of the offered snapshot, in
+// order.
+type RequestApplySnapshotChunk struct {
+	RequestBase
+	Index  uint32
+	Chunk  []byte
+	Sender string
+}
+
 // ----------------------------------------
 // Response types
 
@@ -178,6 +210,13 @@ type ResponseCheckTx struct {
 	ResponseBase
 	GasWanted int64 // nondeterministic
 	GasUsed   int64
+
+	// Used by the priority mempool to order the txs, and to replace the
+	// pending tx of a same sender and sequence.
+	Priority int64  // nondeterministic
+	Sender   string // nondeterministic
+	Sequence uint64 // nondeterministic
+	Replaces bool   // nondeterministic, the tx reuses the sequence of a pending tx
 }
 
 type ResponseDeliverTx struct {
@@ -197,6 +236,30 @@ type ResponseCommit struct {
 	ResponseBase
 }
 
+type ResponseListSnapshots struct {
+	ResponseBase
+	Snapshots []Snapshot
+}
+
+// ResponseOfferSnapshot accepts the offered snapshot, unless it has an
+// error.
+type ResponseOfferSnapshot struct {
+	ResponseBase
+}
+
+type ResponseLoadSnapshotChunk struct {
+	ResponseBase
+	Chunk []byte
+}
+
+// ResponseApplySnapshotChunk rejects the snapshot if it has an error, unless
+// it lists chunks to fetch again, from other senders than the rejected ones.
+type ResponseApplySnapshotChunk struct {
+	ResponseBase
+	RefetchChunks []uint32
+	RejectSenders []string
+}
+
 // ----------------------------------------
 // Interface types
 
@@ -270,6 +333,17 @@ type LastCommitInfo struct {
 	Votes []VoteInfo
 }
 
+// Snapshot is a snapshot of the application state at a height, split into
+// chunks. The hash identifies the snapshot, and the metadata is specific to
+// the application.
+type Snapshot struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
 // unstable
 type VoteInfo struct {
 	Address         crypto.Address
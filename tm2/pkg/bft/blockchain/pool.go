@@ -150,6 +150,15 @@ func (pool *BlockPool) removeTimedoutPeers() {
 	}
 }
 
+// setHeight sets the height of the first block requested by the pool, which
+// must not be started.
+func (pool *BlockPool) setHeight(height int64) {
+	pool.mtx.Lock()
+	defer pool.mtx.Unlock()
+
+	pool.height = height
+}
+
 // GetStatus returns pool's height, numPending requests and the number of
 // requesters.
 func (pool *BlockPool) GetStatus() (height int64, numPending int32, lenRequesters int) {
@@ -273,16 +282,18 @@ func (pool *BlockPool) MaxPeerHeight() int64 {
 	return pool.maxPeerHeight
 }
 
-// SetPeerHeight sets the peer's alleged blockchain height.
-func (pool *BlockPool) SetPeerHeight(peerID p2pTypes.ID, height int64) {
+// SetPeerRange sets the peer's alleged blockchain base and height.
+// The blocks below the base were pruned by the peer.
+func (pool *BlockPool) SetPeerRange(peerID p2pTypes.ID, base, height int64) {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
 	peer := pool.peers[peerID]
 	if peer != nil {
+		peer.base = base
 		peer.height = height
 	} else {
-		peer = newBPPeer(pool, peerID, height)
+		peer = newBPPeer(pool, peerID, base, height)
 		peer.setLogger(pool.Logger.With("peer", peerID))
 		pool.peers[peerID] = peer
 	}
@@ -335,9 +346,9 @@ func (pool *BlockPool) updateMaxPeerHeight() {
 	pool.maxPeerHeight = maxVal
 }
 
-// Pick an available peer with at least the given minHeight.
+// Pick an available peer which has the block at the given height.
 // If no peers are available, returns nil.
-func (pool *BlockPool) pickIncrAvailablePeer(minHeight int64) *bpPeer {
+func (pool *BlockPool) pickIncrAvailablePeer(height int64) *bpPeer {
 	pool.mtx.Lock()
 	defer pool.mtx.Unlock()
 
@@ -349,7 +360,7 @@ func (pool *BlockPool) pickIncrAvailablePeer(minHeight int64) *bpPeer {
 		if peer.numPending >= maxPendingRequestsPerPeer {
 			continue
 		}
-		if peer.height < minHeight {
+		if height < peer.base || peer.height < height {
 			continue
 		}
 		peer.incrPending()
@@ -423,6 +434,7 @@ type bpPeer struct {
 	id          p2pTypes.ID
 	recvMonitor *flow.Monitor
 
+	base       int64
 	height     int64
 	numPending int32
 	timeout    *time.Timer
@@ -431,10 +443,11 @@ type bpPeer struct {
 	logger *slog.Logger
 }
 
-func newBPPeer(pool *BlockPool, peerID p2pTypes.ID, height int64) *bpPeer {
+func newBPPeer(pool *BlockPool, peerID p2pTypes.ID, base, height int64) *bpPeer {
 	peer := &bpPeer{
 		pool:       pool,
 		id:         peerID,
+		base:       base,
 		height:     height,
 		numPending: 0,
 		logger:     log.NewNoopLogger(),
@@ -20,6 +20,7 @@ func init() {
 
 type testPeer struct {
 	id        p2pTypes.ID
+	base      int64
 	height    int64
 	inputChan chan inputData // make sure each peer's data is sequential
 }
@@ -66,7 +67,7 @@ func makePeers(numPeers int, minHeight, maxHeight int64) testPeers {
 	for range numPeers {
 		peerID := p2pTypes.ID(random.RandStr(12))
 		height := minHeight + random.RandInt63n(maxHeight-minHeight)
-		peers[peerID] = testPeer{peerID, height, make(chan inputData, 10)}
+		peers[peerID] = testPeer{peerID, 1, height, make(chan inputData, 10)}
 	}
 	return peers
 }
@@ -94,7 +95,7 @@ func TestBlockPoolBasic(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, peer.base, peer.height)
 		}
 	}()
 
@@ -151,7 +152,7 @@ func TestBlockPoolTimeout(t *testing.T) {
 	// Introduce each peer.
 	go func() {
 		for _, peer := range peers {
-			pool.SetPeerHeight(peer.id, peer.height)
+			pool.SetPeerRange(peer.id, peer.base, peer.height)
 		}
 	}()
 
@@ -197,7 +198,7 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 	for i := range 10 {
 		peerID := p2pTypes.ID(fmt.Sprintf("%d", i+1))
 		height := int64(i + 1)
-		peers[peerID] = testPeer{peerID, height, make(chan inputData)}
+		peers[peerID] = testPeer{peerID, 1, height, make(chan inputData)}
 	}
 	requestsCh := make(chan BlockRequest)
 	errorsCh := make(chan peerError)
@@ -210,7 +211,7 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	// add peers
 	for peerID, peer := range peers {
-		pool.SetPeerHeight(peerID, peer.height)
+		pool.SetPeerRange(peerID, peer.base, peer.height)
 	}
 	assert.EqualValues(t, 10, pool.MaxPeerHeight())
 
@@ -228,3 +229,25 @@ func TestBlockPoolRemovePeer(t *testing.T) {
 
 	assert.EqualValues(t, 0, pool.MaxPeerHeight())
 }
+
+func TestBlockPoolPickPeerInRange(t *testing.T) {
+	t.Parallel()
+
+	pool := NewBlockPool(1, make(chan BlockRequest), make(chan peerError))
+	pool.SetLogger(log.NewTestingLogger(t))
+
+	// The peer pruned the blocks below 50
+	pool.SetPeerRange("pruned", 50, 100)
+	assert.EqualValues(t, 100, pool.MaxPeerHeight())
+
+	assert.Nil(t, pool.pickIncrAvailablePeer(49))
+	assert.Nil(t, pool.pickIncrAvailablePeer(101))
+
+	peer := pool.pickIncrAvailablePeer(50)
+	require.NotNil(t, peer)
+	assert.Equal(t, p2pTypes.ID("pruned"), peer.id)
+
+	// Updating the range of the peer
+	pool.SetPeerRange("pruned", 1, 100)
+	assert.NotNil(t, pool.pickIncrAvailablePeer(1))
+}
@@ -126,6 +126,30 @@ func (bcR *BlockchainReactor) OnStart() error {
 	return nil
 }
 
+// SwitchToFastSync starts fast syncing the blocks following the given
+// state, restored by the state sync. The reactor must have been created
+// without fast sync.
+func (bcR *BlockchainReactor) SwitchToFastSync(state sm.State) error {
+	if bcR.fastSync {
+		return errors.New("already fast syncing")
+	}
+	if state.LastBlockHeight != bcR.store.Height() {
+		return fmt.Errorf("state (%v) and store (%v) height mismatch", state.LastBlockHeight,
+			bcR.store.Height())
+	}
+
+	bcR.fastSync = true
+	bcR.initialState = state
+	bcR.pool.setHeight(state.LastBlockHeight + 1)
+
+	if err := bcR.pool.Start(); err != nil {
+		return err
+	}
+	go bcR.poolRoutine()
+
+	return nil
+}
+
 // OnStop implements cmn.Service.
 func (bcR *BlockchainReactor) OnStop() {
 	bcR.pool.Stop()
@@ -146,12 +170,12 @@ func (bcR *BlockchainReactor) GetChannels() []*p2p.ChannelDescriptor {
 
 // AddPeer implements Reactor by sending our state to peer.
 func (bcR *BlockchainReactor) AddPeer(peer p2p.PeerConn) {
-	msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{bcR.store.Height()})
+	msgBytes := amino.MustMarshalAny(bcR.statusResponse())
 	peer.Send(BlockchainChannel, msgBytes)
 	// it's OK if send fails. will try later in poolRoutine
 
 	// peer is added to the pool once we receive the first
-	// bcStatusResponseMessage from the peer and call pool.SetPeerHeight
+	// bcStatusResponseMessage from the peer and call pool.SetPeerRange
 }
 
 // RemovePeer implements Reactor by removing peer from the pool.
@@ -159,6 +183,14 @@ func (bcR *BlockchainReactor) RemovePeer(peer p2p.PeerConn, reason any) {
 	bcR.pool.RemovePeer(peer.ID())
 }
 
+// statusResponse returns the status of the block store. The base of a block
+// store restored by the state sync is above its height until the next block
+// is synced, in which case no block is available.
+func (bcR *BlockchainReactor) statusResponse() *bcStatusResponseMessage {
+	base, height := bcR.store.Base(), bcR.store.Height()
+	return &bcStatusResponseMessage{Height: height, Base: min(base, height)}
+}
+
 // respondToPeer loads a block and sends it to the requesting peer,
 // if we have it. Otherwise, we'll respond saying we don't have it.
 // According to the Tendermint spec, if all nodes are honest,
@@ -202,11 +234,11 @@ func (bcR *BlockchainReactor) Receive(chID byte, src p2p.PeerConn, msgBytes []by
 		bcR.pool.AddBlock(src.ID(), msg.Block, len(msgBytes))
 	case *bcStatusRequestMessage:
 		// Send peer our state.
-		msgBytes := amino.MustMarshalAny(&bcStatusResponseMessage{bcR.store.Height()})
+		msgBytes := amino.MustMarshalAny(bcR.statusResponse())
 		src.TrySend(BlockchainChannel, msgBytes)
 	case *bcStatusResponseMessage:
 		// Got a peer status. Unverified.
-		bcR.pool.SetPeerHeight(src.ID(), msg.Height)
+		bcR.pool.SetPeerRange(src.ID(), msg.Base, msg.Height)
 	default:
 		bcR.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
 	}
@@ -455,6 +487,7 @@ func (m *bcStatusRequestMessage) String() string {
 
 type bcStatusResponseMessage struct {
 	Height int64
+	Base   int64 // lowest retained height, 0 if unknown
 }
 
 // ValidateBasic performs basic validation.
@@ -462,9 +495,15 @@ func (m *bcStatusResponseMessage) ValidateBasic() error {
 	if m.Height < 0 {
 		return errors.New("negative height")
 	}
+	if m.Base < 0 {
+		return errors.New("negative base")
+	}
+	if m.Base > m.Height {
+		return fmt.Errorf("base %v cannot be greater than height %v", m.Base, m.Height)
+	}
 	return nil
 }
 
 func (m *bcStatusResponseMessage) String() string {
-	return fmt.Sprintf("[bcStatusResponseMessage %v]", m.Height)
+	return fmt.Sprintf("[bcStatusResponseMessage %v:%v]", m.Base, m.Height)
 }
@@ -371,12 +371,16 @@ func TestBcStatusResponseMessageValidateBasic(t *testing.T) {
 
 	testCases := []struct {
 		testName       string
+		responseBase   int64
 		responseHeight int64
 		expectErr      bool
 	}{
-		{"Valid Response Message", 0, false},
-		{"Valid Response Message", 1, false},
-		{"Invalid Response Message", -1, true},
+		{"Valid Response Message", 0, 0, false},
+		{"Valid Response Message", 0, 1, false},
+		{"Valid Response Message with base", 10, 100, false},
+		{"Invalid Response Message", 0, -1, true},
+		{"Invalid Response Message with negative base", -1, 1, true},
+		{"Invalid Response Message with base above height", 11, 10, true},
 	}
 
 	for _, tc := range testCases {
@@ -384,7 +388,7 @@ func TestBcStatusResponseMessageValidateBasic(t *testing.T) {
 		t.Run(tc.testName, func(t *testing.T) {
 			t.Parallel()
 
-			response := bcStatusResponseMessage{Height: tc.responseHeight}
+			response := bcStatusResponseMessage{Base: tc.responseBase, Height: tc.responseHeight}
 			assert.Equal(t, tc.expectErr, response.ValidateBasic() != nil, "Validate Basic had an unexpected result")
 		})
 	}
@@ -215,7 +215,7 @@ func (conR *ConsensusReactor) Receive(chID byte, src p2p.PeerConn, msgBytes []by
 		return
 	}
 
-	if err = msg.ValidateBasic(); err != nil {
+	if err = conR.validateMsg(msg); err != nil {
 		conR.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
 		conR.Switch.StopPeerForError(src, err)
 		return
@@ -474,6 +474,7 @@ OUTER_LOOP:
 				logger.Debug("Sending block part", "height", prs.Height, "round", prs.Round)
 				if peer.Send(DataChannel, amino.MustMarshalAny(msg)) {
 					ps.SetHasProposalBlockPart(prs.Height, prs.Round, index)
+					logBlockPartSentTelemetry()
 				}
 				continue OUTER_LOOP
 			}
@@ -483,6 +484,14 @@ OUTER_LOOP:
 		if (0 < prs.Height) && (prs.Height < rs.Height) {
 			heightLogger := logger.With("height", prs.Height)
 
+			// The peer needs blocks which were pruned, it has to catch up
+			// from other peers.
+			if base := conR.conS.blockStore.Base(); prs.Height < base {
+				heightLogger.Debug("Peer is lagging behind our pruned blocks, sleeping", "base", base)
+				time.Sleep(conR.conS.config.PeerGossipSleepDuration)
+				continue OUTER_LOOP
+			}
+
 			// if we never received the commit message from the peer, the block parts wont be initialized
 			if prs.ProposalBlockParts == nil {
 				blockMeta := conR.conS.blockStore.LoadBlockMeta(prs.Height)
@@ -577,6 +586,7 @@ func (conR *ConsensusReactor) gossipDataForCatchup(logger *slog.Logger, rs *csty
 		logger.Debug("Sending block part for catchup", "round", prs.Round, "index", index)
 		if peer.Send(DataChannel, amino.MustMarshalAny(msg)) {
 			ps.SetHasProposalBlockPart(prs.Height, prs.Round, index)
+			logBlockPartSentTelemetry()
 		} else {
 			logger.Debug("Sending block part for catchup failed")
 		}
@@ -1434,6 +1444,20 @@ func (m *NewValidBlockMessage) String() string {
 
 // -------------------------------------
 
+// validateMsg performs the basic validation of a message, allowing the
+// proposals and votes signatures of the validator key types enabled by the
+// consensus params.
+func (conR *ConsensusReactor) validateMsg(msg ConsensusMessage) error {
+	switch msg := msg.(type) {
+	case *ProposalMessage:
+		return msg.Proposal.ValidateBasicWithParams(conR.conS.GetConsensusParams())
+	case *VoteMessage:
+		return msg.Vote.ValidateBasicWithParams(conR.conS.GetConsensusParams())
+	default:
+		return msg.ValidateBasic()
+	}
+}
+
 // ProposalMessage is sent when a new block is proposed.
 type ProposalMessage struct {
 	Proposal *types.Proposal
@@ -15,6 +15,7 @@ import (
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/bitarray"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
 	"github.com/gnolang/gno/tm2/pkg/events"
 	p2pTesting "github.com/gnolang/gno/tm2/pkg/internal/p2p"
@@ -23,6 +24,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/p2p"
 	"github.com/gnolang/gno/tm2/pkg/testutils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // ----------------------------------------------
@@ -753,6 +755,27 @@ func TestHasVoteMessageValidateBasic(t *testing.T) {
 	}
 }
 
+func TestVoteMessageValidateSignatureSize(t *testing.T) {
+	t.Parallel()
+
+	cs, vss := randConsensusState(1)
+	conR := NewConsensusReactor(cs, false)
+
+	vote := signVote(vss[0], types.PrevoteType, nil, types.PartSetHeader{})
+	require.NoError(t, conR.validateMsg(&VoteMessage{vote}))
+
+	// A multisig sized signature is only allowed once multisig validators
+	// are enabled by the consensus params.
+	vote.Signature = make([]byte, types.MaxMultisigSignatureSize)
+	assert.Error(t, conR.validateMsg(&VoteMessage{vote}))
+
+	cs.state.ConsensusParams.Validator.PubKeyTypeURLs = append(
+		cs.state.ConsensusParams.Validator.PubKeyTypeURLs,
+		amino.GetTypeURL(multisig.PubKeyMultisigThreshold{}),
+	)
+	assert.NoError(t, conR.validateMsg(&VoteMessage{vote}))
+}
+
 func TestVoteSetMaj23MessageValidateBasic(t *testing.T) {
 	t.Parallel()
 
@@ -418,6 +418,10 @@ func (h *Handshaker) replayBlocks(state sm.State, proxyApp appconn.AppConns, app
 	if mutateState {
 		finalBlock--
 	}
+	if base := h.store.Base(); appBlockHeight+1 < base {
+		return nil, fmt.Errorf("cannot replay blocks from height %d, the blocks below %d were pruned",
+			appBlockHeight+1, base)
+	}
 	for i := appBlockHeight + 1; i <= finalBlock; i++ {
 		h.logger.Info("Applying block", "height", i)
 		block := h.store.LoadBlock(i)
@@ -12,6 +12,7 @@ import (
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	cnscfg "github.com/gnolang/gno/tm2/pkg/bft/consensus/config"
 	cstypes "github.com/gnolang/gno/tm2/pkg/bft/consensus/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/fail"
@@ -28,6 +29,8 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/service"
 	"github.com/gnolang/gno/tm2/pkg/telemetry"
 	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 )
 
 // -----------------------------------------------------------------------------
@@ -133,6 +136,10 @@ type ConsensusState struct {
 	// for tests where we want to limit the number of transitions the state makes
 	nSteps int
 
+	// when the current round and step started, for the telemetry
+	roundStartTime time.Time
+	stepStartTime  time.Time
+
 	// some functions can be overwritten for testing
 	decideProposal func(height int64, round int)
 	doPrevote      func(height int64, round int)
@@ -221,6 +228,13 @@ func (cs *ConsensusState) GetState() sm.State {
 	return cs.state.Copy()
 }
 
+// GetConsensusParams returns the consensus params of the chain state.
+func (cs *ConsensusState) GetConsensusParams() abci.ConsensusParams {
+	cs.mtx.RLock()
+	defer cs.mtx.RUnlock()
+	return cs.state.ConsensusParams
+}
+
 // GetLastHeight returns the last height committed.
 // If there were no blocks, returns 0.
 func (cs *ConsensusState) GetLastHeight() int64 {
@@ -457,6 +471,8 @@ func (cs *ConsensusState) updateHeight(height int64) {
 }
 
 func (cs *ConsensusState) updateRoundStep(round int, step cstypes.RoundStepType) {
+	cs.logStepTelemetry(round, step)
+
 	cs.Round = round
 	cs.Step = step
 }
@@ -861,7 +877,11 @@ func (cs *ConsensusState) needProofBlock(height int64) bool {
 		return true
 	}
 
+	// The last block is missing if the state was restored by the state sync
 	lastBlockMeta := cs.blockStore.LoadBlockMeta(height - 1)
+	if lastBlockMeta == nil {
+		return true
+	}
 	return !bytes.Equal(cs.state.AppHash, lastBlockMeta.Header.AppHash)
 }
 
@@ -1386,6 +1406,9 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 
 	fail.Fail() // XXX
 
+	// The validators which signed the block last commit, before they are updated
+	lastValidators := cs.LastValidators
+
 	// NewHeightStep!
 	cs.updateToState(stateCopy)
 
@@ -1401,7 +1424,7 @@ func (cs *ConsensusState) finalizeCommit(height int64) {
 	// * cs.StartTime is set to when we will start round0.
 
 	// Log the telemetry
-	cs.logTelemetry(block)
+	cs.logTelemetry(block, lastValidators)
 }
 
 // -----------------------------------------------------------------------------
@@ -1425,7 +1448,8 @@ func (cs *ConsensusState) defaultSetProposal(proposal *types.Proposal) error {
 	}
 
 	// Verify signature
-	if !cs.Validators.GetProposer().PubKey.VerifyBytes(proposal.SignBytes(cs.state.ChainID), proposal.Signature) {
+	proposerPubKey := cs.Validators.GetProposer().PubKey
+	if !types.VerifySignature(proposerPubKey, proposal.SignBytes(cs.state.ChainID), proposal.Signature) {
 		return ErrInvalidProposalSignature
 	}
 
@@ -1470,6 +1494,7 @@ func (cs *ConsensusState) addProposalBlockPart(msg *BlockPartMessage, peerID p2p
 	}
 
 	added, err = cs.ProposalBlockParts.AddPart(part)
+	cs.logBlockPartTelemetry(added)
 	if err != nil {
 		return added, err
 	}
@@ -1572,6 +1597,7 @@ func (cs *ConsensusState) addVote(vote *types.Vote, peerID p2pTypes.ID) (added b
 
 		cs.Logger.Info(fmt.Sprintf("Added to lastPrecommits: %v", cs.LastCommit.StringShort()))
 		cs.evsw.FireEvent(types.EventVote{Vote: vote})
+		logLateVoteTelemetry(vote)
 
 		// if we can skip timeoutCommit and have all the votes now,
 		if cs.config.SkipTimeoutCommit && cs.LastCommit.HasAll() {
@@ -1779,7 +1805,7 @@ func (cs *ConsensusState) signAddVote(type_ types.SignedMsgType, hash []byte, he
 }
 
 // logTelemetry logs the consensus state telemetry
-func (cs *ConsensusState) logTelemetry(block *types.Block) {
+func (cs *ConsensusState) logTelemetry(block *types.Block, lastValidators *types.ValidatorSet) {
 	if !telemetry.MetricsEnabled() {
 		return
 	}
@@ -1800,6 +1826,96 @@ func (cs *ConsensusState) logTelemetry(block *types.Block) {
 
 	metrics.BlockTxs.Record(context.Background(), block.TotalTxs)
 	metrics.BlockSizeBytes.Record(context.Background(), int64(block.Size()))
+
+	// Log the validators missing from the last commit
+	if block.LastCommit == nil || lastValidators == nil {
+		return
+	}
+
+	for i, precommit := range block.LastCommit.Precommits {
+		if precommit != nil || i >= lastValidators.Size() {
+			continue
+		}
+
+		metrics.MissedVotes.Add(
+			context.Background(),
+			1,
+			metric.WithAttributes(
+				attribute.String("validator", lastValidators.Validators[i].Address.String()),
+			),
+		)
+	}
+}
+
+// logStepTelemetry logs the duration of the current step, and of the current
+// round if the next step starts a new one
+func (cs *ConsensusState) logStepTelemetry(round int, step cstypes.RoundStepType) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	now := time.Now()
+
+	if !cs.stepStartTime.IsZero() {
+		metrics.ConsensusStepTime.Record(
+			context.Background(),
+			now.Sub(cs.stepStartTime).Milliseconds(),
+			metric.WithAttributes(
+				attribute.String("step", cs.Step.String()),
+			),
+		)
+	}
+	cs.stepStartTime = now
+
+	// Round 0 of a height starts with the new height step
+	if round == cs.Round && step != cstypes.RoundStepNewHeight {
+		return
+	}
+
+	if !cs.roundStartTime.IsZero() {
+		metrics.ConsensusRoundTime.Record(context.Background(), now.Sub(cs.roundStartTime).Milliseconds())
+	}
+	cs.roundStartTime = now
+}
+
+// logBlockPartTelemetry logs a block part received from a peer,
+// whether it was added or not (a duplicate or an invalid part)
+func (cs *ConsensusState) logBlockPartTelemetry(added bool) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.BlockPartsReceived.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(
+			attribute.Bool("added", added),
+		),
+	)
+}
+
+// logLateVoteTelemetry logs a precommit received after its block was committed
+func logLateVoteTelemetry(vote *types.Vote) {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.LateVotes.Add(
+		context.Background(),
+		1,
+		metric.WithAttributes(
+			attribute.String("validator", vote.ValidatorAddress.String()),
+		),
+	)
+}
+
+// logBlockPartSentTelemetry logs a block part gossiped to a peer
+func logBlockPartSentTelemetry() {
+	if !telemetry.MetricsEnabled() {
+		return
+	}
+
+	metrics.BlockPartsSent.Add(context.Background(), 1)
 }
 
 // ---------------------------------------------------------
@@ -0,0 +1,414 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+const (
+	// DefaultMaxClockDrift is the default maximum time a header can be
+	// ahead of the local clock.
+	DefaultMaxClockDrift = 10 * time.Second
+
+	// DefaultPruningSize is the default number of light blocks kept in the
+	// trusted store.
+	DefaultPruningSize = 1000
+)
+
+// ErrConflictingHeaders is returned when a witness has another header than
+// the verified one of the primary, at the same height. One of them is lying,
+// and the light client cannot tell which one.
+var ErrConflictingHeaders = errors.New("conflicting headers")
+
+// TrustOptions are the options of the header the light client initially
+// trusts, obtained from a trusted source.
+type TrustOptions struct {
+	// Period is the trusting period, during which a trusted header can be
+	// used to verify other headers. It must be below the unbonding period
+	// of the validators, so that the validators signing a forged header
+	// can still be punished.
+	Period time.Duration
+
+	// Height and Hash are the height and the hash of the trusted header.
+	Height int64
+	Hash   []byte
+}
+
+// ValidateBasic performs basic validation of the trust options.
+func (opts TrustOptions) ValidateBasic() error {
+	if opts.Period <= 0 {
+		return errors.New("trusting period must be positive")
+	}
+	if opts.Height <= 0 {
+		return errors.New("trusted height must be positive")
+	}
+	if len(opts.Hash) == 0 {
+		return errors.New("trusted hash must be set")
+	}
+
+	return nil
+}
+
+// Option is an option of the light client.
+type Option func(*Client)
+
+// SequentialVerification verifies every header between the trusted one and
+// the verified one.
+func SequentialVerification() Option {
+	return func(c *Client) { c.sequential = true }
+}
+
+// SkippingVerification skips the headers between the trusted one and the
+// verified one, as long as enough of the trusted validators signed it. This
+// is the default.
+func SkippingVerification() Option {
+	return func(c *Client) { c.sequential = false }
+}
+
+// Witnesses sets the providers the verified headers are cross-checked with.
+func Witnesses(witnesses ...Provider) Option {
+	return func(c *Client) { c.witnesses = witnesses }
+}
+
+// MaxClockDrift sets the maximum time a header can be ahead of the local
+// clock.
+func MaxClockDrift(d time.Duration) Option {
+	return func(c *Client) { c.maxClockDrift = d }
+}
+
+// PruningSize sets the number of light blocks kept in the trusted store, 0
+// to keep all of them.
+func PruningSize(size int) Option {
+	return func(c *Client) { c.pruningSize = size }
+}
+
+// Logger sets the logger of the light client.
+func Logger(logger *slog.Logger) Option {
+	return func(c *Client) { c.logger = logger }
+}
+
+// Client is a light client, verifying the light blocks fetched from the
+// primary provider from the trusted ones, which are kept in its store. The
+// verified light blocks are cross-checked with the witnesses, if any.
+type Client struct {
+	chainID        string
+	trustingPeriod time.Duration
+	sequential     bool
+	maxClockDrift  time.Duration
+	pruningSize    int
+
+	primary   Provider
+	witnesses []Provider
+	store     Store
+	logger    *slog.Logger
+
+	mtx sync.Mutex
+}
+
+// NewClient returns a light client of the given chain, trusting the header
+// of the trust options. The trusted header is fetched from the primary
+// provider, unless it is already in the store.
+func NewClient(
+	ctx context.Context,
+	chainID string,
+	trustOptions TrustOptions,
+	primary Provider,
+	store Store,
+	options ...Option,
+) (*Client, error) {
+	if err := trustOptions.ValidateBasic(); err != nil {
+		return nil, fmt.Errorf("invalid trust options: %w", err)
+	}
+
+	c := &Client{
+		chainID:        chainID,
+		trustingPeriod: trustOptions.Period,
+		maxClockDrift:  DefaultMaxClockDrift,
+		pruningSize:    DefaultPruningSize,
+		primary:        primary,
+		store:          store,
+		logger:         log.NewNoopLogger(),
+	}
+	for _, option := range options {
+		option(c)
+	}
+
+	if primary.ChainID() != chainID {
+		return nil, fmt.Errorf("primary provider is for chain %q, not %q", primary.ChainID(), chainID)
+	}
+	for _, witness := range c.witnesses {
+		if witness.ChainID() != chainID {
+			return nil, fmt.Errorf("witness provider is for chain %q, not %q", witness.ChainID(), chainID)
+		}
+	}
+
+	if err := c.initTrustedLightBlock(ctx, trustOptions); err != nil {
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// initTrustedLightBlock saves the trusted light block in the store, unless
+// it is already there.
+func (c *Client) initTrustedLightBlock(ctx context.Context, opts TrustOptions) error {
+	lb, err := c.store.LightBlock(opts.Height)
+	switch {
+	case err == nil:
+		if !bytes.Equal(lb.Hash(), opts.Hash) {
+			return fmt.Errorf("stored header %d hash %X does not match the trusted hash %X",
+				opts.Height, lb.Hash(), opts.Hash)
+		}
+
+		return nil
+
+	case !errors.Is(err, ErrLightBlockNotFound):
+		return err
+	}
+
+	lb, err = c.primary.LightBlock(ctx, opts.Height)
+	if err != nil {
+		return fmt.Errorf("unable to fetch the trusted header: %w", err)
+	}
+	if !bytes.Equal(lb.Hash(), opts.Hash) {
+		return fmt.Errorf("header %d hash %X does not match the trusted hash %X",
+			opts.Height, lb.Hash(), opts.Hash)
+	}
+	if err := c.compareWithWitnesses(ctx, lb); err != nil {
+		return err
+	}
+
+	return c.store.SaveLightBlock(lb)
+}
+
+// ChainID returns the ID of the chain followed by the light client.
+func (c *Client) ChainID() string {
+	return c.chainID
+}
+
+// TrustedLightBlock returns the trusted light block of the given height, or
+// the latest one if the height is 0. ErrLightBlockNotFound is returned if
+// the light block of the height was not verified.
+func (c *Client) TrustedLightBlock(height int64) (*LightBlock, error) {
+	if height == 0 {
+		return c.store.LatestLightBlock()
+	}
+
+	return c.store.LightBlock(height)
+}
+
+// Update verifies the latest light block of the primary provider, if it is
+// above the latest trusted one, and returns the latest trusted light block.
+func (c *Client) Update(ctx context.Context, now time.Time) (*LightBlock, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	latest, err := c.store.LatestLightBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	lb, err := c.primary.LightBlock(ctx, 0)
+	if err != nil {
+		return nil, err
+	}
+	if lb.Height <= latest.Height {
+		return latest, nil
+	}
+
+	if err := c.verifyForwards(ctx, latest, lb, now); err != nil {
+		return nil, err
+	}
+
+	return lb, nil
+}
+
+// VerifyLightBlockAtHeight returns the trusted light block of the given
+// height, which is verified if it is not trusted yet. The light blocks
+// above the latest trusted one are verified forwards from it, the ones
+// below by following the hashes of their headers back from the closest
+// trusted one above.
+func (c *Client) VerifyLightBlockAtHeight(ctx context.Context, height int64, now time.Time) (*LightBlock, error) {
+	if height <= 0 {
+		return nil, errors.New("height must be positive")
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	lb, err := c.store.LightBlock(height)
+	if !errors.Is(err, ErrLightBlockNotFound) {
+		return lb, err
+	}
+
+	latest, err := c.store.LatestLightBlock()
+	if err != nil {
+		return nil, err
+	}
+
+	if height > latest.Height {
+		lb, err := c.primary.LightBlock(ctx, height)
+		if err != nil {
+			return nil, err
+		}
+		if err := c.verifyForwards(ctx, latest, lb, now); err != nil {
+			return nil, err
+		}
+
+		return lb, nil
+	}
+
+	after, err := c.store.LightBlockAfter(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return c.verifyBackwards(ctx, after, height, now)
+}
+
+// verifyForwards verifies the untrusted light block from the trusted one
+// below, sequentially or by skipping, and saves the verified light blocks.
+func (c *Client) verifyForwards(ctx context.Context, trusted, untrusted *LightBlock, now time.Time) error {
+	var err error
+	if c.sequential {
+		err = c.verifySequential(ctx, trusted, untrusted, now)
+	} else {
+		err = c.verifySkipping(ctx, trusted, untrusted, now)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := c.compareWithWitnesses(ctx, untrusted); err != nil {
+		return err
+	}
+
+	return c.save(untrusted)
+}
+
+// verifySequential verifies every light block from the trusted one to the
+// untrusted one.
+func (c *Client) verifySequential(ctx context.Context, trusted, untrusted *LightBlock, now time.Time) error {
+	for height := trusted.Height + 1; height <= untrusted.Height; height++ {
+		next := untrusted
+		if height < untrusted.Height {
+			var err error
+			if next, err = c.primary.LightBlock(ctx, height); err != nil {
+				return err
+			}
+		}
+
+		if err := VerifyAdjacent(c.chainID, trusted, next, c.trustingPeriod, now, c.maxClockDrift); err != nil {
+			return fmt.Errorf("unable to verify header %d: %w", height, err)
+		}
+		c.logger.Debug("Verified light block", "height", height)
+
+		if height < untrusted.Height {
+			if err := c.save(next); err != nil {
+				return err
+			}
+		}
+		trusted = next
+	}
+
+	return nil
+}
+
+// verifySkipping verifies the untrusted light block from the trusted one,
+// skipping the blocks in between if enough of the trusted validators signed
+// it, otherwise verifying the block halfway first, by bisection.
+func (c *Client) verifySkipping(ctx context.Context, trusted, untrusted *LightBlock, now time.Time) error {
+	err := Verify(c.chainID, trusted, untrusted, c.trustingPeriod, now, c.maxClockDrift)
+	if !errors.Is(err, ErrNewValSetCantBeTrusted) {
+		if err != nil {
+			return fmt.Errorf("unable to verify header %d: %w", untrusted.Height, err)
+		}
+		c.logger.Debug("Verified light block", "height", untrusted.Height, "trusted", trusted.Height)
+
+		return nil
+	}
+
+	pivotHeight := trusted.Height + (untrusted.Height-trusted.Height)/2
+	pivot, err := c.primary.LightBlock(ctx, pivotHeight)
+	if err != nil {
+		return err
+	}
+	if err := c.verifySkipping(ctx, trusted, pivot, now); err != nil {
+		return err
+	}
+	if err := c.save(pivot); err != nil {
+		return err
+	}
+
+	return c.verifySkipping(ctx, pivot, untrusted, now)
+}
+
+// verifyBackwards verifies the light block of the given height from the
+// trusted one above, by following the hashes of the headers in between, and
+// saves it.
+func (c *Client) verifyBackwards(ctx context.Context, trusted *LightBlock, height int64, now time.Time) (*LightBlock, error) {
+	if HeaderExpired(trusted.Header, c.trustingPeriod, now) {
+		return nil, fmt.Errorf("%w: header %d at %v, trusting period %v",
+			ErrOldHeaderExpired, trusted.Height, trusted.Time, c.trustingPeriod)
+	}
+
+	for trusted.Height > height {
+		prev, err := c.primary.LightBlock(ctx, trusted.Height-1)
+		if err != nil {
+			return nil, err
+		}
+		if err := VerifyBackwards(c.chainID, prev.Header, trusted.Header); err != nil {
+			return nil, fmt.Errorf("unable to verify header %d: %w", prev.Height, err)
+		}
+		trusted = prev
+	}
+
+	if err := c.compareWithWitnesses(ctx, trusted); err != nil {
+		return nil, err
+	}
+	if err := c.save(trusted); err != nil {
+		return nil, err
+	}
+
+	return trusted, nil
+}
+
+// compareWithWitnesses cross-checks the verified light block with the
+// witnesses. The witnesses which are not able to provide the light block
+// are skipped.
+func (c *Client) compareWithWitnesses(ctx context.Context, lb *LightBlock) error {
+	for _, witness := range c.witnesses {
+		other, err := witness.LightBlock(ctx, lb.Height)
+		if err != nil {
+			c.logger.Info("Unable to cross-check light block with witness", "height", lb.Height, "err", err)
+			continue
+		}
+
+		if !bytes.Equal(other.Hash(), lb.Hash()) {
+			return fmt.Errorf("%w: header %d hash %X from the primary, %X from a witness",
+				ErrConflictingHeaders, lb.Height, lb.Hash(), other.Hash())
+		}
+	}
+
+	return nil
+}
+
+// save saves the verified light block, and prunes the store.
+func (c *Client) save(lb *LightBlock) error {
+	if err := c.store.SaveLightBlock(lb); err != nil {
+		return err
+	}
+
+	if c.pruningSize > 0 {
+		return c.store.Prune(c.pruningSize)
+	}
+
+	return nil
+}
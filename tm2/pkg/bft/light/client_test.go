@@ -0,0 +1,169 @@
+package light
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+func newTestClient(t *testing.T, chain *testChain, trustHeight int64, primary Provider, options ...Option) *Client {
+	t.Helper()
+
+	c, err := NewClient(
+		context.Background(),
+		testChainID,
+		TrustOptions{
+			Period: testTrustingPeriod,
+			Height: trustHeight,
+			Hash:   chain.blocks[trustHeight].Hash(),
+		},
+		primary,
+		NewDBStore(memdb.NewMemDB()),
+		options...,
+	)
+	require.NoError(t, err)
+
+	return c
+}
+
+func TestClient_Skipping(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 100, 50, nil)
+	primary, mock := newTestProvider(chain)
+	witness, _ := newTestProvider(chain)
+	c := newTestClient(t, chain, 10, primary, Witnesses(witness))
+
+	ctx := context.Background()
+	now := time.Now()
+
+	lb, err := c.VerifyLightBlockAtHeight(ctx, 80, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[80].Hash(), lb.Hash())
+
+	// The validator set change is verified by bisection, skipping most of
+	// the headers
+	assert.Less(t, mock.requests, 20)
+
+	trusted, err := c.TrustedLightBlock(80)
+	require.NoError(t, err)
+	assert.Equal(t, lb.Hash(), trusted.Hash())
+
+	// The heights below the latest trusted one are verified backwards
+	lb, err = c.VerifyLightBlockAtHeight(ctx, 70, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[70].Hash(), lb.Hash())
+
+	lb, err = c.VerifyLightBlockAtHeight(ctx, 5, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[5].Hash(), lb.Hash())
+
+	lb, err = c.Update(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[100].Hash(), lb.Hash())
+
+	latest, err := c.TrustedLightBlock(0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(100), latest.Height)
+
+	_, err = c.VerifyLightBlockAtHeight(ctx, 101, now)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+}
+
+func TestClient_Sequential(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 30, 20, nil)
+	primary, mock := newTestProvider(chain)
+	c := newTestClient(t, chain, 10, primary, SequentialVerification())
+
+	lb, err := c.VerifyLightBlockAtHeight(context.Background(), 30, time.Now())
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[30].Hash(), lb.Hash())
+
+	// Every header in between is verified, and trusted
+	assert.Equal(t, 21, mock.requests)
+	for h := int64(10); h <= 30; h++ {
+		_, err := c.TrustedLightBlock(h)
+		assert.NoError(t, err, "height %d", h)
+	}
+}
+
+func TestClient_Invalid(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 20, 50, nil)
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run("untrusted hash", func(t *testing.T) {
+		t.Parallel()
+
+		primary, _ := newTestProvider(chain)
+		_, err := NewClient(ctx, testChainID, TrustOptions{
+			Period: testTrustingPeriod,
+			Height: 10,
+			Hash:   chain.blocks[11].Hash(),
+		}, primary, NewDBStore(memdb.NewMemDB()))
+		assert.ErrorContains(t, err, "does not match the trusted hash")
+	})
+
+	t.Run("invalid trust options", func(t *testing.T) {
+		t.Parallel()
+
+		primary, _ := newTestProvider(chain)
+		_, err := NewClient(ctx, testChainID, TrustOptions{
+			Height: 10,
+			Hash:   chain.blocks[10].Hash(),
+		}, primary, NewDBStore(memdb.NewMemDB()))
+		assert.Error(t, err)
+	})
+
+	t.Run("forged header", func(t *testing.T) {
+		t.Parallel()
+
+		// A chain whose later headers are signed by other validators
+		other := newTestChain(t, 20, 0, nil)
+		forged := &testChain{blocks: make(map[int64]*LightBlock), height: chain.height}
+		for h, lb := range chain.blocks {
+			forged.blocks[h] = lb
+			if h > 12 {
+				forged.blocks[h] = other.blocks[h]
+			}
+		}
+
+		primary, _ := newTestProvider(forged)
+		c := newTestClient(t, chain, 10, primary)
+		_, err := c.VerifyLightBlockAtHeight(ctx, 15, now)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+
+	t.Run("witness mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		other := newTestChain(t, 20, 50, nil)
+		primary, _ := newTestProvider(chain)
+		witness, _ := newTestProvider(other)
+
+		_, err := NewClient(ctx, testChainID, TrustOptions{
+			Period: testTrustingPeriod,
+			Height: 10,
+			Hash:   chain.blocks[10].Hash(),
+		}, primary, NewDBStore(memdb.NewMemDB()), Witnesses(witness))
+		assert.ErrorIs(t, err, ErrConflictingHeaders)
+	})
+
+	t.Run("expired trusted header", func(t *testing.T) {
+		t.Parallel()
+
+		primary, _ := newTestProvider(chain)
+		c := newTestClient(t, chain, 10, primary)
+		_, err := c.VerifyLightBlockAtHeight(ctx, 15, now.Add(testTrustingPeriod))
+		assert.ErrorIs(t, err, ErrOldHeaderExpired)
+	})
+}
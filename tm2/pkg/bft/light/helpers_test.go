@@ -0,0 +1,131 @@
+package light
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const (
+	testChainID        = "test-chain"
+	testTrustingPeriod = 24 * time.Hour
+)
+
+// testChain is a chain of light blocks, one per minute up to now, whose
+// validator set is entirely replaced at the given height.
+type testChain struct {
+	blocks map[int64]*LightBlock
+	height int64
+}
+
+// newTestChain returns a chain of the given height, whose app hashes are
+// returned by the given function, if any.
+func newTestChain(t *testing.T, height, valsChange int64, appHash func(int64) []byte) *testChain {
+	t.Helper()
+
+	oldVals, oldPrivVals := types.RandValidatorSet(4, 10)
+	newVals, newPrivVals := types.RandValidatorSet(4, 10)
+
+	valsAt := func(h int64) (*types.ValidatorSet, []types.PrivValidator) {
+		if h >= valsChange {
+			return newVals, newPrivVals
+		}
+		return oldVals, oldPrivVals
+	}
+	if appHash == nil {
+		appHash = func(h int64) []byte { return fmt.Appendf(nil, "app hash %d", h) }
+	}
+
+	chain := &testChain{
+		blocks: make(map[int64]*LightBlock),
+		height: height,
+	}
+	start := time.Now().Add(-time.Duration(height) * time.Minute).UTC()
+
+	var lastBlockID types.BlockID
+	for h := int64(1); h <= height; h++ {
+		vals, privVals := valsAt(h)
+		nextVals, _ := valsAt(h + 1)
+
+		header := &types.Header{
+			Version:            "1",
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               start.Add(time.Duration(h) * time.Minute),
+			TotalTxs:           h,
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: nextVals.Hash(),
+			AppHash:            appHash(h),
+			ProposerAddress:    vals.Validators[0].Address,
+		}
+		blockID := types.BlockID{Hash: header.Hash()}
+
+		voteSet := types.NewVoteSet(testChainID, h, 0, types.PrecommitType, vals)
+		commit, err := types.MakeCommit(blockID, h, 0, voteSet, privVals)
+		require.NoError(t, err)
+
+		chain.blocks[h] = &LightBlock{
+			SignedHeader:     types.SignedHeader{Header: header, Commit: commit},
+			ValidatorSet:     vals,
+			NextValidatorSet: nextVals,
+		}
+		lastBlockID = blockID
+	}
+
+	return chain
+}
+
+// mockClient serves the light blocks of a chain, and counts the requested
+// headers.
+type mockClient struct {
+	client.Client
+
+	chain    *testChain
+	requests int
+}
+
+func (c *mockClient) Commit(_ context.Context, height *int64) (*ctypes.ResultCommit, error) {
+	c.requests++
+
+	h := c.chain.height
+	if height != nil {
+		h = *height
+	}
+	lb, ok := c.chain.blocks[h]
+	if !ok {
+		return nil, errors.New("height not available")
+	}
+	return &ctypes.ResultCommit{SignedHeader: lb.SignedHeader, CanonicalCommit: true}, nil
+}
+
+func (c *mockClient) Validators(_ context.Context, height *int64) (*ctypes.ResultValidators, error) {
+	h := c.chain.height + 1
+	if height != nil {
+		h = *height
+	}
+
+	var vals *types.ValidatorSet
+	switch {
+	case c.chain.blocks[h] != nil:
+		vals = c.chain.blocks[h].ValidatorSet
+	case c.chain.blocks[h-1] != nil:
+		vals = c.chain.blocks[h-1].NextValidatorSet
+	default:
+		return nil, errors.New("height not available")
+	}
+	return &ctypes.ResultValidators{BlockHeight: h, Validators: vals.Copy().Validators}, nil
+}
+
+func newTestProvider(chain *testChain) (Provider, *mockClient) {
+	c := &mockClient{chain: chain}
+	return NewProvider(testChainID, c), c
+}
@@ -0,0 +1,102 @@
+package light
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// Provider provides the light blocks of a chain, which are not trusted.
+type Provider interface {
+	// ChainID returns the ID of the chain of the light blocks.
+	ChainID() string
+
+	// LightBlock returns the light block of the given height, or the
+	// latest one if the height is 0. The light block is checked with
+	// ValidateBasic.
+	LightBlock(ctx context.Context, height int64) (*LightBlock, error)
+}
+
+// rpcProvider is a Provider fetching the light blocks from an RPC server.
+type rpcProvider struct {
+	chainID string
+	client  client.SignClient
+}
+
+// NewProvider returns a Provider fetching the light blocks of the given chain
+// with the given RPC client.
+func NewProvider(chainID string, c client.SignClient) Provider {
+	return &rpcProvider{
+		chainID: chainID,
+		client:  c,
+	}
+}
+
+// NewHTTPProvider returns a Provider fetching the light blocks of the given
+// chain from the RPC server at the given address.
+func NewHTTPProvider(chainID, remote string) (Provider, error) {
+	c, err := client.NewHTTPClient(remote)
+	if err != nil {
+		return nil, fmt.Errorf("unable to create RPC client for %s: %w", remote, err)
+	}
+
+	return NewProvider(chainID, c), nil
+}
+
+// ChainID implements Provider.
+func (p *rpcProvider) ChainID() string {
+	return p.chainID
+}
+
+// LightBlock implements Provider.
+func (p *rpcProvider) LightBlock(ctx context.Context, height int64) (*LightBlock, error) {
+	var heightPtr *int64
+	if height > 0 {
+		heightPtr = &height
+	}
+
+	res, err := p.client.Commit(ctx, heightPtr)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to fetch header %d: %w", ErrLightBlockNotFound, height, err)
+	}
+	sh := res.SignedHeader
+	if sh.Header == nil {
+		return nil, fmt.Errorf("%w: missing header %d", ErrLightBlockNotFound, height)
+	}
+	if height > 0 && sh.Height != height {
+		return nil, fmt.Errorf("expected header %d, got %d", height, sh.Height)
+	}
+
+	vals, err := p.validators(ctx, sh.Height)
+	if err != nil {
+		return nil, err
+	}
+	nextVals, err := p.validators(ctx, sh.Height+1)
+	if err != nil {
+		return nil, err
+	}
+
+	lb := &LightBlock{
+		SignedHeader:     sh,
+		ValidatorSet:     vals,
+		NextValidatorSet: nextVals,
+	}
+	if err := lb.ValidateBasic(p.chainID); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	return lb, nil
+}
+
+// validators fetches the validator set of the given height.
+func (p *rpcProvider) validators(ctx context.Context, height int64) (*types.ValidatorSet, error) {
+	res, err := p.client.Validators(ctx, &height)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unable to fetch validators %d: %w", ErrLightBlockNotFound, height, err)
+	}
+
+	// The proposer priorities of the validators are kept
+	return &types.ValidatorSet{Validators: res.Validators}, nil
+}
@@ -0,0 +1,213 @@
+package light
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+)
+
+// ErrUnverifiableQuery is returned for the ABCI queries whose result cannot
+// be proven, i.e. all but the store key queries.
+var ErrUnverifiableQuery = errors.New("query result cannot be verified")
+
+var _ client.Client = (*VerifyingClient)(nil)
+
+// VerifyingClient is an RPC client verifying the results of the wrapped
+// client with a light client: the ABCI queries of store keys are proven
+// against the app hash of the verified headers, and the headers, blocks and
+// validator sets against the verified headers. The other calls are passed
+// through as is.
+type VerifyingClient struct {
+	client.Client
+
+	lc  *Client
+	prt *merkle.ProofRuntime
+}
+
+// NewVerifyingClient returns an RPC client verifying the results of the
+// given client with the given light client.
+func NewVerifyingClient(next client.Client, lc *Client) *VerifyingClient {
+	return &VerifyingClient{
+		Client: next,
+		lc:     lc,
+		prt:    rootmulti.DefaultProofRuntime(),
+	}
+}
+
+// ABCIQuery implements client.Client, see ABCIQueryWithOptions.
+func (c *VerifyingClient) ABCIQuery(ctx context.Context, path string, data []byte) (*ctypes.ResultABCIQuery, error) {
+	return c.ABCIQueryWithOptions(ctx, path, data, client.DefaultABCIQueryOptions)
+}
+
+// ABCIQueryWithOptions implements client.Client by querying the value of a
+// store key, along with its proof, which is verified against the app hash of
+// the next verified header. Only the ".store/<name>/key" queries can be
+// proven, the other ones return ErrUnverifiableQuery.
+//
+// The queries of the latest height (0) are done at the height preceding
+// the latest header of the primary provider, whose app hash commits to it.
+func (c *VerifyingClient) ABCIQueryWithOptions(
+	ctx context.Context,
+	path string,
+	data []byte,
+	opts client.ABCIQueryOptions,
+) (*ctypes.ResultABCIQuery, error) {
+	storeName, ok := parseStoreKeyPath(path)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnverifiableQuery, path)
+	}
+	if len(data) == 0 {
+		return nil, errors.New("empty store key")
+	}
+
+	if opts.Height == 0 {
+		latest, err := c.lc.Update(ctx, time.Now())
+		if err != nil {
+			return nil, err
+		}
+		opts.Height = latest.Height - 1
+	}
+	opts.Prove = true
+
+	res, err := c.Client.ABCIQueryWithOptions(ctx, path, data, opts)
+	if err != nil {
+		return nil, err
+	}
+	resp := res.Response
+	if resp.IsErr() {
+		return res, nil
+	}
+
+	if resp.Height != opts.Height {
+		return nil, fmt.Errorf("expected query result at height %d, got %d", opts.Height, resp.Height)
+	}
+	if resp.Proof == nil || len(resp.Proof.Ops) == 0 {
+		return nil, errors.New("missing query proof")
+	}
+
+	// The app hash of a height is in the header of the next one
+	lb, err := c.lc.VerifyLightBlockAtHeight(ctx, resp.Height+1, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	keyPath := merkle.KeyPath{}.
+		AppendKey([]byte(storeName), merkle.KeyEncodingURL).
+		AppendKey(data, merkle.KeyEncodingHex).
+		String()
+	if resp.Value != nil {
+		err = c.prt.VerifyValue(resp.Proof, lb.AppHash, keyPath, resp.Value)
+	} else {
+		err = c.prt.VerifyAbsence(resp.Proof, lb.AppHash, keyPath)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid query proof: %w", err)
+	}
+
+	return res, nil
+}
+
+// parseStoreKeyPath returns the name of the store of a store key query path.
+func parseStoreKeyPath(path string) (string, bool) {
+	parts := strings.Split(strings.TrimPrefix(path, "/"), "/")
+	if len(parts) != 3 || parts[0] != ".store" || parts[1] == "" || parts[2] != "key" {
+		return "", false
+	}
+
+	return parts[1], true
+}
+
+// Commit implements client.Client by checking the header against the
+// verified one.
+func (c *VerifyingClient) Commit(ctx context.Context, height *int64) (*ctypes.ResultCommit, error) {
+	res, err := c.Client.Commit(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if res.Header == nil {
+		return nil, errors.New("missing header")
+	}
+	if err := res.ValidateBasic(c.lc.ChainID()); err != nil {
+		return nil, err
+	}
+
+	if err := c.verifyHash(ctx, res.Height, res.Hash()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Block implements client.Client by checking the block against the verified
+// header.
+func (c *VerifyingClient) Block(ctx context.Context, height *int64) (*ctypes.ResultBlock, error) {
+	res, err := c.Client.Block(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if res.BlockMeta == nil || res.Block == nil {
+		return nil, errors.New("missing block")
+	}
+	if err := res.Block.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(res.Block.Hash(), res.BlockMeta.BlockID.Hash) {
+		return nil, fmt.Errorf("block hash %X does not match the block meta hash %X",
+			res.Block.Hash(), res.BlockMeta.BlockID.Hash)
+	}
+
+	if err := c.verifyHash(ctx, res.Block.Height, res.Block.Hash()); err != nil {
+		return nil, err
+	}
+
+	return res, nil
+}
+
+// Validators implements client.Client by checking the validator set against
+// the verified header.
+func (c *VerifyingClient) Validators(ctx context.Context, height *int64) (*ctypes.ResultValidators, error) {
+	res, err := c.Client.Validators(ctx, height)
+	if err != nil {
+		return nil, err
+	}
+	if res.BlockHeight <= 0 {
+		return nil, fmt.Errorf("invalid validators height %d", res.BlockHeight)
+	}
+
+	lb, err := c.lc.VerifyLightBlockAtHeight(ctx, res.BlockHeight, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	vals := &types.ValidatorSet{Validators: res.Validators}
+	if !bytes.Equal(vals.Hash(), lb.ValidatorsHash) {
+		return nil, fmt.Errorf("validators %d hash %X does not match the verified header hash %X",
+			res.BlockHeight, vals.Hash(), lb.ValidatorsHash)
+	}
+
+	return res, nil
+}
+
+// verifyHash checks the hash of the header of the given height against the
+// verified one.
+func (c *VerifyingClient) verifyHash(ctx context.Context, height int64, hash []byte) error {
+	lb, err := c.lc.VerifyLightBlockAtHeight(ctx, height, time.Now())
+	if err != nil {
+		return err
+	}
+
+	if !bytes.Equal(hash, lb.Hash()) {
+		return fmt.Errorf("header %d hash %X does not match the verified hash %X", height, hash, lb.Hash())
+	}
+
+	return nil
+}
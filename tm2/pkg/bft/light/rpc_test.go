@@ -0,0 +1,114 @@
+package light
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// mockQueryClient serves the light blocks of a chain, and the store queries
+// of its multistore.
+type mockQueryClient struct {
+	mockClient
+
+	ms    types.CommitMultiStore
+	value []byte // value returned instead of the stored one, if set
+}
+
+func (c *mockQueryClient) ABCIQueryWithOptions(_ context.Context, path string, data []byte, opts client.ABCIQueryOptions) (*ctypes.ResultABCIQuery, error) {
+	res := c.ms.(types.Queryable).Query(abci.RequestQuery{
+		Path:   strings.TrimPrefix(strings.TrimPrefix(path, "/"), ".store"),
+		Data:   data,
+		Height: opts.Height,
+		Prove:  opts.Prove,
+	})
+	if c.value != nil {
+		res.Value = c.value
+	}
+
+	return &ctypes.ResultABCIQuery{Response: res}, nil
+}
+
+func TestVerifyingClient(t *testing.T) {
+	t.Parallel()
+
+	// A multistore whose key is updated at each height, and whose commit
+	// hashes are the app hashes of the following headers
+	ms := rootmulti.NewMultiStore(memdb.NewMemDB())
+	ms.SetStoreOptions(types.StoreOptions{PruningOptions: types.PruneNothing})
+	mainKey := types.NewStoreKey("main")
+	ms.MountStoreWithDB(mainKey, iavl.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	appHashes := map[int64][]byte{1: nil}
+	for h := int64(1); h < 20; h++ {
+		ms.GetStore(mainKey).Set([]byte("key"), fmt.Appendf(nil, "value %d", h))
+		appHashes[h+1] = ms.Commit().Hash
+	}
+	chain := newTestChain(t, 20, 10, func(h int64) []byte { return appHashes[h] })
+
+	mock := &mockQueryClient{mockClient: mockClient{chain: chain}, ms: ms}
+	lc := newTestClient(t, chain, 2, NewProvider(testChainID, mock))
+	c := NewVerifyingClient(mock, lc)
+
+	ctx := context.Background()
+
+	// Latest height
+	res, err := c.ABCIQuery(ctx, ".store/main/key", []byte("key"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(19), res.Response.Height)
+	assert.Equal(t, []byte("value 19"), res.Response.Value)
+
+	// Past height
+	res, err = c.ABCIQueryWithOptions(ctx, ".store/main/key", []byte("key"), client.ABCIQueryOptions{Height: 5})
+	require.NoError(t, err)
+	assert.Equal(t, []byte("value 5"), res.Response.Value)
+
+	// Missing key
+	res, err = c.ABCIQueryWithOptions(ctx, ".store/main/key", []byte("missing"), client.ABCIQueryOptions{Height: 5})
+	require.NoError(t, err)
+	assert.Nil(t, res.Response.Value)
+
+	// Headers and validators
+	height := int64(15)
+	_, err = c.Commit(ctx, &height)
+	require.NoError(t, err)
+	_, err = c.Validators(ctx, &height)
+	require.NoError(t, err)
+
+	_, err = c.ABCIQuery(ctx, "vm/qrender", []byte("gno.land/r/demo:"))
+	assert.ErrorIs(t, err, ErrUnverifiableQuery)
+
+	t.Run("forged value", func(t *testing.T) {
+		t.Parallel()
+
+		forged := &mockQueryClient{mockClient: mockClient{chain: chain}, ms: ms, value: []byte("forged")}
+		c := NewVerifyingClient(forged, lc)
+
+		_, err := c.ABCIQueryWithOptions(ctx, ".store/main/key", []byte("key"), client.ABCIQueryOptions{Height: 5})
+		assert.ErrorContains(t, err, "invalid query proof")
+	})
+
+	t.Run("forged header", func(t *testing.T) {
+		t.Parallel()
+
+		other := newTestChain(t, 20, 10, func(h int64) []byte { return appHashes[h] })
+		forged := &mockQueryClient{mockClient: mockClient{chain: other}, ms: ms}
+		c := NewVerifyingClient(forged, lc)
+
+		_, err := c.Commit(ctx, &height)
+		assert.ErrorContains(t, err, "does not match the verified hash")
+	})
+}
@@ -0,0 +1,154 @@
+package light
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+// ErrLightBlockNotFound is returned when a light block is not in the store,
+// or not available from a provider.
+var ErrLightBlockNotFound = errors.New("light block not found")
+
+// Store stores the trusted light blocks.
+type Store interface {
+	// SaveLightBlock saves a trusted light block.
+	SaveLightBlock(lb *LightBlock) error
+
+	// LightBlock returns the light block of the given height, or
+	// ErrLightBlockNotFound.
+	LightBlock(height int64) (*LightBlock, error)
+
+	// LatestLightBlock returns the light block of the highest height, or
+	// ErrLightBlockNotFound if the store is empty.
+	LatestLightBlock() (*LightBlock, error)
+
+	// LightBlockBefore returns the light block of the highest height below
+	// the given one, or ErrLightBlockNotFound.
+	LightBlockBefore(height int64) (*LightBlock, error)
+
+	// LightBlockAfter returns the light block of the lowest height above
+	// the given one, or ErrLightBlockNotFound.
+	LightBlockAfter(height int64) (*LightBlock, error)
+
+	// Prune deletes the light blocks of the lowest heights, keeping the
+	// given number of them.
+	Prune(size int) error
+}
+
+// dbStore is a Store saving the light blocks in a DB.
+type dbStore struct {
+	db dbm.DB
+}
+
+// NewDBStore returns a store of the light blocks saved in the given DB,
+// which must not be shared with other stores.
+func NewDBStore(db dbm.DB) Store {
+	return &dbStore{db: db}
+}
+
+// lightBlockKey returns the key of the light block of the given height,
+// sorting the keys by height.
+func lightBlockKey(height int64) []byte {
+	return fmt.Appendf(nil, "lb/%020d", height)
+}
+
+var (
+	lightBlockKeyStart = []byte("lb/")
+	lightBlockKeyEnd   = []byte("lb0") // '0' follows '/'
+)
+
+// SaveLightBlock implements Store.
+func (s *dbStore) SaveLightBlock(lb *LightBlock) error {
+	if lb.Header == nil || lb.Height <= 0 {
+		return errors.New("invalid light block height")
+	}
+
+	bz, err := amino.Marshal(lb)
+	if err != nil {
+		return err
+	}
+	s.db.SetSync(lightBlockKey(lb.Height), bz)
+
+	return nil
+}
+
+// LightBlock implements Store.
+func (s *dbStore) LightBlock(height int64) (*LightBlock, error) {
+	bz := s.db.Get(lightBlockKey(height))
+	if bz == nil {
+		return nil, ErrLightBlockNotFound
+	}
+
+	return decodeLightBlock(bz)
+}
+
+// LatestLightBlock implements Store.
+func (s *dbStore) LatestLightBlock() (*LightBlock, error) {
+	return s.first(s.db.ReverseIterator(lightBlockKeyStart, lightBlockKeyEnd))
+}
+
+// LightBlockBefore implements Store.
+func (s *dbStore) LightBlockBefore(height int64) (*LightBlock, error) {
+	return s.first(s.db.ReverseIterator(lightBlockKeyStart, lightBlockKey(height)))
+}
+
+// LightBlockAfter implements Store.
+func (s *dbStore) LightBlockAfter(height int64) (*LightBlock, error) {
+	return s.first(s.db.Iterator(lightBlockKey(height+1), lightBlockKeyEnd))
+}
+
+// first returns the first light block of the iterator, which it closes.
+func (s *dbStore) first(itr dbm.Iterator) (*LightBlock, error) {
+	defer itr.Close()
+
+	if !itr.Valid() {
+		return nil, ErrLightBlockNotFound
+	}
+
+	return decodeLightBlock(itr.Value())
+}
+
+// Prune implements Store.
+func (s *dbStore) Prune(size int) error {
+	if size <= 0 {
+		return errors.New("the number of light blocks to keep must be positive")
+	}
+
+	// The keys are deleted once the iteration is done
+	var pruned [][]byte
+	itr := s.db.ReverseIterator(lightBlockKeyStart, lightBlockKeyEnd)
+	for kept := 0; itr.Valid(); itr.Next() {
+		if kept < size {
+			kept++
+			continue
+		}
+		pruned = append(pruned, itr.Key())
+	}
+	itr.Close()
+
+	if len(pruned) == 0 {
+		return nil
+	}
+
+	batch := s.db.NewBatch()
+	defer batch.Close()
+
+	for _, key := range pruned {
+		batch.Delete(key)
+	}
+	batch.WriteSync()
+
+	return nil
+}
+
+func decodeLightBlock(bz []byte) (*LightBlock, error) {
+	lb := new(LightBlock)
+	if err := amino.Unmarshal(bz, lb); err != nil {
+		return nil, fmt.Errorf("unable to decode light block: %w", err)
+	}
+
+	return lb, nil
+}
@@ -0,0 +1,64 @@
+package light
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+func TestDBStore(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 20, 10, nil)
+	store := NewDBStore(memdb.NewMemDB())
+
+	_, err := store.LatestLightBlock()
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	for _, h := range []int64{3, 5, 12, 9} {
+		require.NoError(t, store.SaveLightBlock(chain.blocks[h]))
+	}
+
+	lb, err := store.LightBlock(5)
+	require.NoError(t, err)
+	assert.Equal(t, chain.blocks[5].Hash(), lb.Hash())
+	assert.Equal(t, chain.blocks[5].ValidatorSet.Hash(), lb.ValidatorSet.Hash())
+	assert.Equal(t, chain.blocks[5].NextValidatorSet.Hash(), lb.NextValidatorSet.Hash())
+	require.NoError(t, lb.ValidateBasic(testChainID))
+
+	_, err = store.LightBlock(4)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	lb, err = store.LatestLightBlock()
+	require.NoError(t, err)
+	assert.Equal(t, int64(12), lb.Height)
+
+	lb, err = store.LightBlockBefore(9)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), lb.Height)
+
+	_, err = store.LightBlockBefore(3)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	lb, err = store.LightBlockAfter(5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(9), lb.Height)
+
+	_, err = store.LightBlockAfter(12)
+	assert.ErrorIs(t, err, ErrLightBlockNotFound)
+
+	// The lowest heights are pruned
+	require.NoError(t, store.Prune(2))
+
+	for h, found := range map[int64]bool{3: false, 5: false, 9: true, 12: true} {
+		_, err := store.LightBlock(h)
+		if found {
+			assert.NoError(t, err, "height %d", h)
+		} else {
+			assert.ErrorIs(t, err, ErrLightBlockNotFound, "height %d", h)
+		}
+	}
+}
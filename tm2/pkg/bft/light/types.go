@@ -0,0 +1,54 @@
+// Package light implements a light client, which follows a chain by
+// verifying its signed headers against the validator sets they commit to,
+// from a trusted header, without executing its blocks. The verified headers
+// are kept in a trusted store, and their app hashes authenticate the proofs
+// of the ABCI queries, see VerifyingClient.
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+// LightBlock is a signed header, along with the validator set which signed
+// it and the one signing the next header.
+type LightBlock struct {
+	types.SignedHeader `json:"signed_header"`
+
+	ValidatorSet     *types.ValidatorSet `json:"validator_set"`
+	NextValidatorSet *types.ValidatorSet `json:"next_validator_set"`
+}
+
+// ValidateBasic checks that the light block belongs to the given chain, and
+// that its validator sets match the hashes of its header. The commit
+// signatures are not verified.
+func (lb *LightBlock) ValidateBasic(chainID string) error {
+	if err := lb.SignedHeader.ValidateBasic(chainID); err != nil {
+		return fmt.Errorf("invalid signed header: %w", err)
+	}
+
+	if lb.ValidatorSet.IsNilOrEmpty() {
+		return errors.New("missing validator set")
+	}
+	if !bytes.Equal(lb.ValidatorSet.Hash(), lb.ValidatorsHash) {
+		return fmt.Errorf("validator set hash %X does not match the header validators hash %X",
+			lb.ValidatorSet.Hash(), lb.ValidatorsHash)
+	}
+
+	if lb.NextValidatorSet.IsNilOrEmpty() {
+		return errors.New("missing next validator set")
+	}
+	if !bytes.Equal(lb.NextValidatorSet.Hash(), lb.NextValidatorsHash) {
+		return fmt.Errorf("next validator set hash %X does not match the header next validators hash %X",
+			lb.NextValidatorSet.Hash(), lb.NextValidatorsHash)
+	}
+
+	return nil
+}
+
+func (lb *LightBlock) String() string {
+	return fmt.Sprintf("LightBlock{%v #%X}", lb.Height, lb.Hash())
+}
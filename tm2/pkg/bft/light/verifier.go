@@ -0,0 +1,167 @@
+package light
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+var (
+	// ErrOldHeaderExpired is returned when the trusted header is older than
+	// the trusting period, in which case it can no longer be used to verify
+	// other headers.
+	ErrOldHeaderExpired = errors.New("trusted header expired")
+
+	// ErrInvalidHeader is returned when the untrusted header is invalid, or
+	// not signed by its validators.
+	ErrInvalidHeader = errors.New("invalid header")
+
+	// ErrNewValSetCantBeTrusted is returned when too few of the trusted
+	// validators signed the untrusted header, in which case the headers in
+	// between must be verified first.
+	ErrNewValSetCantBeTrusted = errors.New("new validator set cannot be trusted")
+)
+
+// HeaderExpired returns whether the header is older than the trusting period
+// at the given time.
+func HeaderExpired(h *types.Header, trustingPeriod time.Duration, now time.Time) bool {
+	return !h.Time.Add(trustingPeriod).After(now)
+}
+
+// Verify verifies the untrusted light block from the trusted one, see
+// VerifyAdjacent and VerifyNonAdjacent.
+func Verify(
+	chainID string,
+	trusted, untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+) error {
+	if untrusted.Height == trusted.Height+1 {
+		return VerifyAdjacent(chainID, trusted, untrusted, trustingPeriod, now, maxClockDrift)
+	}
+
+	return VerifyNonAdjacent(chainID, trusted, untrusted, trustingPeriod, now, maxClockDrift)
+}
+
+// VerifyAdjacent verifies the untrusted light block following the trusted
+// one: it must be signed by more than 2/3 of the next validators of the
+// trusted block.
+func VerifyAdjacent(
+	chainID string,
+	trusted, untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+) error {
+	if untrusted.Height != trusted.Height+1 {
+		return errors.New("headers must be adjacent in height")
+	}
+	if err := verifyNewHeader(chainID, trusted, untrusted, trustingPeriod, now, maxClockDrift); err != nil {
+		return err
+	}
+
+	if !bytes.Equal(untrusted.ValidatorsHash, trusted.NextValidatorsHash) {
+		return fmt.Errorf("%w: validators hash %X does not match the trusted next validators hash %X",
+			ErrInvalidHeader, untrusted.ValidatorsHash, trusted.NextValidatorsHash)
+	}
+
+	err := untrusted.ValidatorSet.VerifyCommit(chainID, untrusted.Commit.BlockID, untrusted.Height, untrusted.Commit)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	return nil
+}
+
+// VerifyNonAdjacent verifies the untrusted light block from a trusted one
+// below it, skipping the blocks in between: it must be signed by more than
+// 2/3 of its validators, and by more than 2/3 of the next validators of the
+// trusted block. ErrNewValSetCantBeTrusted is returned when the latter is
+// not the case, as the validators changed too much in between.
+func VerifyNonAdjacent(
+	chainID string,
+	trusted, untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+) error {
+	if untrusted.Height == trusted.Height+1 {
+		return errors.New("headers must be non adjacent in height")
+	}
+	if err := verifyNewHeader(chainID, trusted, untrusted, trustingPeriod, now, maxClockDrift); err != nil {
+		return err
+	}
+
+	err := trusted.NextValidatorSet.VerifyFutureCommit(untrusted.ValidatorSet, chainID,
+		untrusted.Commit.BlockID, untrusted.Height, untrusted.Commit)
+	switch {
+	case types.IsErrTooMuchChange(err):
+		return fmt.Errorf("%w: %w", ErrNewValSetCantBeTrusted, err)
+	case err != nil:
+		return fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+
+	return nil
+}
+
+// verifyNewHeader checks the untrusted light block, and that it follows the
+// trusted one, which must not be expired.
+func verifyNewHeader(
+	chainID string,
+	trusted, untrusted *LightBlock,
+	trustingPeriod time.Duration,
+	now time.Time,
+	maxClockDrift time.Duration,
+) error {
+	if HeaderExpired(trusted.Header, trustingPeriod, now) {
+		return fmt.Errorf("%w: header %d at %v, trusting period %v",
+			ErrOldHeaderExpired, trusted.Height, trusted.Time, trustingPeriod)
+	}
+
+	if err := untrusted.ValidateBasic(chainID); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidHeader, err)
+	}
+	if untrusted.Height <= trusted.Height {
+		return fmt.Errorf("%w: height %d is not above the trusted height %d",
+			ErrInvalidHeader, untrusted.Height, trusted.Height)
+	}
+	if !untrusted.Time.After(trusted.Time) {
+		return fmt.Errorf("%w: time %v is not after the trusted time %v",
+			ErrInvalidHeader, untrusted.Time, trusted.Time)
+	}
+	if untrusted.Time.After(now.Add(maxClockDrift)) {
+		return fmt.Errorf("%w: time %v is in the future (now %v, max clock drift %v)",
+			ErrInvalidHeader, untrusted.Time, now, maxClockDrift)
+	}
+
+	return nil
+}
+
+// VerifyBackwards verifies the untrusted header preceding the trusted one,
+// which is chained to it by hash.
+func VerifyBackwards(chainID string, untrusted, trusted *types.Header) error {
+	if untrusted == nil {
+		return fmt.Errorf("%w: missing header", ErrInvalidHeader)
+	}
+	if untrusted.ChainID != chainID {
+		return fmt.Errorf("%w: header belongs to another chain %q, not %q",
+			ErrInvalidHeader, untrusted.ChainID, chainID)
+	}
+	if untrusted.Height != trusted.Height-1 {
+		return errors.New("headers must be adjacent in height")
+	}
+	if !untrusted.Time.Before(trusted.Time) {
+		return fmt.Errorf("%w: time %v is not before the trusted time %v",
+			ErrInvalidHeader, untrusted.Time, trusted.Time)
+	}
+	if !bytes.Equal(untrusted.Hash(), trusted.LastBlockID.Hash) {
+		return fmt.Errorf("%w: hash %X does not match the trusted last block hash %X",
+			ErrInvalidHeader, untrusted.Hash(), trusted.LastBlockID.Hash)
+	}
+
+	return nil
+}
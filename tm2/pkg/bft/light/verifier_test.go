@@ -0,0 +1,113 @@
+package light
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+func TestVerifyAdjacent(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 5, nil)
+	now := time.Now()
+
+	// Across the validator set change
+	for h := int64(1); h < 10; h++ {
+		err := VerifyAdjacent(testChainID, chain.blocks[h], chain.blocks[h+1], testTrustingPeriod, now, DefaultMaxClockDrift)
+		require.NoError(t, err, "height %d", h)
+	}
+
+	t.Run("not adjacent", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[3], testTrustingPeriod, now, DefaultMaxClockDrift)
+		assert.Error(t, err)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[2], time.Minute, now, DefaultMaxClockDrift)
+		assert.ErrorIs(t, err, ErrOldHeaderExpired)
+	})
+
+	t.Run("future header", func(t *testing.T) {
+		t.Parallel()
+
+		past := chain.blocks[2].Time.Add(-time.Hour)
+		err := VerifyAdjacent(testChainID, chain.blocks[1], chain.blocks[2], testTrustingPeriod, past, DefaultMaxClockDrift)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+
+	t.Run("wrong chain", func(t *testing.T) {
+		t.Parallel()
+
+		err := VerifyAdjacent("other-chain", chain.blocks[1], chain.blocks[2], testTrustingPeriod, now, DefaultMaxClockDrift)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+
+	t.Run("other validators", func(t *testing.T) {
+		t.Parallel()
+
+		// A block signed by other validators, along with their validator
+		// sets
+		other := newTestChain(t, 10, 0, nil)
+		err := VerifyAdjacent(testChainID, chain.blocks[1], other.blocks[2], testTrustingPeriod, now, DefaultMaxClockDrift)
+		assert.ErrorIs(t, err, ErrInvalidHeader)
+	})
+}
+
+func TestVerifyNonAdjacent(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 5, nil)
+	now := time.Now()
+
+	err := VerifyNonAdjacent(testChainID, chain.blocks[1], chain.blocks[4], testTrustingPeriod, now, DefaultMaxClockDrift)
+	require.NoError(t, err)
+
+	// The validators changed entirely in between
+	err = VerifyNonAdjacent(testChainID, chain.blocks[1], chain.blocks[8], testTrustingPeriod, now, DefaultMaxClockDrift)
+	assert.ErrorIs(t, err, ErrNewValSetCantBeTrusted)
+
+	err = VerifyNonAdjacent(testChainID, chain.blocks[4], chain.blocks[8], testTrustingPeriod, now, DefaultMaxClockDrift)
+	require.NoError(t, err)
+
+	err = VerifyNonAdjacent(testChainID, chain.blocks[4], chain.blocks[5], testTrustingPeriod, now, DefaultMaxClockDrift)
+	assert.Error(t, err, "expecting an error for adjacent blocks")
+
+	err = VerifyNonAdjacent(testChainID, chain.blocks[8], chain.blocks[4], testTrustingPeriod, now, DefaultMaxClockDrift)
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+
+	// A block whose validator set does not match its header
+	forged := *chain.blocks[4]
+	forged.ValidatorSet, _ = types.RandValidatorSet(4, 10)
+	err = VerifyNonAdjacent(testChainID, chain.blocks[1], &forged, testTrustingPeriod, now, DefaultMaxClockDrift)
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
+
+func TestVerifyBackwards(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 10, 5, nil)
+
+	for h := int64(10); h > 1; h-- {
+		err := VerifyBackwards(testChainID, chain.blocks[h-1].Header, chain.blocks[h].Header)
+		require.NoError(t, err, "height %d", h)
+	}
+
+	err := VerifyBackwards(testChainID, chain.blocks[7].Header, chain.blocks[9].Header)
+	assert.Error(t, err, "expecting an error for non adjacent headers")
+
+	other := newTestChain(t, 10, 5, nil)
+	err = VerifyBackwards(testChainID, other.blocks[8].Header, chain.blocks[9].Header)
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+
+	err = VerifyBackwards("other-chain", chain.blocks[8].Header, chain.blocks[9].Header)
+	assert.ErrorIs(t, err, ErrInvalidHeader)
+}
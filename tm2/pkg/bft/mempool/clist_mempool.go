@@ -41,11 +41,15 @@ type CListMempool struct {
 	height       int64 // the last block Update()'d to
 	maxTxBytes   int64
 
+	// Which txs are accepted and in which order they are proposed, by
+	// arrival by default (see PriorityMempool).
+	policy txPolicy
+
 	// Track whether we're rechecking txs.
 	// These are not protected by a mutex and are expected to be mutated
 	// in serial (ie. by abci responses which are called in serial).
-	recheckCursor *clist.CElement // next expected response
-	recheckEnd    *clist.CElement // re-checking stops here
+	rechecked     []*clist.CElement // txs being rechecked, in the order of the requests
+	recheckCursor int               // index of the next expected response
 
 	// notify listeners (ie. consensus) when txs are available
 	notifiedTxsAvailable bool
@@ -55,6 +59,11 @@ type CListMempool struct {
 	// txsMap: txKey -> CElement
 	txsMap sync.Map
 
+	// Index of the txs of each sender reported by the application.
+	// bySender: sender -> sequence -> CElement
+	bySenderMtx sync.Mutex
+	bySender    map[string]map[uint64]*clist.CElement
+
 	// Atomic integers
 	txsBytes   int64 // total size of mempool, in bytes
 	rechecking int32 // for re-checking filtered txs on Update()
@@ -69,7 +78,7 @@ type CListMempool struct {
 	logger *slog.Logger
 }
 
-var _ Mempool = &CListMempool{}
+var _ GossipMempool = &CListMempool{}
 
 // CListMempoolOption sets an optional parameter on the mempool.
 type CListMempoolOption func(*CListMempool)
@@ -92,10 +101,12 @@ func NewCListMempool(
 		height:        height,
 		maxTxBytes:    maxTxBytes,
 		rechecking:    0,
-		recheckCursor: nil,
-		recheckEnd:    nil,
+		rechecked:     nil,
+		recheckCursor: 0,
+		bySender:      make(map[string]map[uint64]*clist.CElement),
 		logger:        log.NewNoopLogger(),
 	}
+	mempool.policy = fifoPolicy{mempool}
 	if config.CacheSize > 0 {
 		mempool.cache = newMapTxCache(config.CacheSize)
 	} else {
@@ -187,6 +198,9 @@ func (mem *CListMempool) Flush() {
 	}
 
 	mem.txsMap = sync.Map{}
+	mem.bySenderMtx.Lock()
+	mem.bySender = make(map[string]map[uint64]*clist.CElement)
+	mem.bySenderMtx.Unlock()
 	_ = atomic.SwapInt64(&mem.txsBytes, 0)
 }
 
@@ -219,24 +233,14 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 	// use defer to unlock mutex because application (*local client*) might panic
 	defer mem.mtx.Unlock()
 
-	var (
-		memSize  = mem.Size()
-		txsBytes = mem.TxsBytes()
-		txSize   = len(tx)
-	)
-
 	// Check max pending txs bytes
-	if memSize >= mem.config.Size ||
-		int64(txSize)+txsBytes > mem.config.MaxPendingTxsBytes {
-		return MempoolIsFullError{
-			memSize, mem.config.Size,
-			txsBytes, mem.config.MaxPendingTxsBytes,
-		}
+	if err := mem.policy.checkFull(tx); err != nil {
+		return err
 	}
 
 	// Check max tx bytes
-	if int64(txSize) > mem.maxTxBytes {
-		return TxTooLargeError{mem.maxTxBytes, int64(txSize)}
+	if txSize := int64(len(tx)); txSize > mem.maxTxBytes {
+		return TxTooLargeError{mem.maxTxBytes, txSize}
 	}
 
 	// Check custom preCheck function
@@ -283,7 +287,10 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 		return err
 	}
 
-	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{Tx: tx})
+	reqRes := mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
+		Tx:               tx,
+		AllowReplacement: mem.policy.replacesTxs(),
+	})
 	reqRes.SetCallback(mem.reqResCb(tx, txInfo.SenderID, cb))
 
 	return nil
@@ -297,7 +304,7 @@ func (mem *CListMempool) CheckTxWithInfo(tx types.Tx, cb func(abci.Response), tx
 // so the request specific callback can do the work.
 // When rechecking, we don't need the peerID, so the recheck callback happens here.
 func (mem *CListMempool) globalCb(req abci.Request, res abci.Response) {
-	if mem.recheckCursor == nil {
+	if mem.rechecked == nil {
 		return
 	} else {
 		mem.resCbRecheck(req, res)
@@ -315,12 +322,12 @@ func (mem *CListMempool) globalCb(req abci.Request, res abci.Response) {
 // Used in CheckTxWithInfo to record PeerID who sent us the tx.
 func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci.Response)) func(res abci.Response) {
 	return func(res abci.Response) {
-		if mem.recheckCursor != nil {
+		if mem.rechecked != nil {
 			// this should never happen
-			panic("recheck cursor is not nil in reqResCb")
+			panic("recheck is in progress in reqResCb")
 		}
 
-		mem.resCbFirstTime(tx, peerID, res)
+		res = mem.resCbFirstTime(tx, peerID, res)
 
 		// Passed in by the caller of CheckTx, eg. the RPC.
 		// The external callback cannot modify the result.
@@ -335,6 +342,20 @@ func (mem *CListMempool) reqResCb(tx []byte, peerID uint16, externalCb func(abci
 func (mem *CListMempool) addTx(memTx *mempoolTx) {
 	e := mem.txs.PushBack(memTx)
 	mem.txsMap.Store(txKey(memTx.tx), e)
+	if memTx.sender != "" {
+		mem.bySenderMtx.Lock()
+		seqs, ok := mem.bySender[memTx.sender]
+		if !ok {
+			seqs = make(map[uint64]*clist.CElement)
+			mem.bySender[memTx.sender] = seqs
+		}
+		// A fifoPolicy may add several txs with the same sequence, the
+		// first one is indexed.
+		if _, ok := seqs[memTx.sequence]; !ok {
+			seqs[memTx.sequence] = e
+		}
+		mem.bySenderMtx.Unlock()
+	}
 	atomic.AddInt64(&mem.txsBytes, int64(len(memTx.tx)))
 
 	// Update the telemetry
@@ -357,10 +378,22 @@ func (mem *CListMempool) logTelemetry() {
 // Called from:
 //   - Update (lock held) if tx was committed
 //   - resCbRecheck (lock not held) if tx was invalidated
+//   - resCbFirstTime (lock not held) if tx was replaced or evicted
 func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromCache bool) {
 	mem.txs.Remove(elem)
 	elem.DetachPrev()
 	mem.txsMap.Delete(txKey(tx))
+	if memTx := elem.Value.(*mempoolTx); memTx.sender != "" {
+		mem.bySenderMtx.Lock()
+		seqs := mem.bySender[memTx.sender]
+		if seqs[memTx.sequence] == elem {
+			delete(seqs, memTx.sequence)
+			if len(seqs) == 0 {
+				delete(mem.bySender, memTx.sender)
+			}
+		}
+		mem.bySenderMtx.Unlock()
+	}
 	atomic.AddInt64(&mem.txsBytes, int64(-len(tx)))
 
 	if removeFromCache {
@@ -372,36 +405,48 @@ func (mem *CListMempool) removeTx(tx types.Tx, elem *clist.CElement, removeFromC
 }
 
 // callback, which is called after the app checked the tx for the first time.
+// It returns the response, with an error if the tx was rejected by the mempool.
 //
 // The case where the app checks the tx for the second and subsequent times is
 // handled by the resCbRecheck callback.
-func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) {
-	switch res := res.(type) {
+func (mem *CListMempool) resCbFirstTime(tx []byte, peerID uint16, res abci.Response) abci.Response {
+	switch r := res.(type) {
 	case abci.ResponseCheckTx:
-		if res.Error == nil {
-			memTx := &mempoolTx{
-				height:    mem.height,
-				gasWanted: res.GasWanted,
-				tx:        tx,
-			}
-			memTx.senders.Store(peerID, true)
-			mem.addTx(memTx)
-			mem.logger.Info("Added good transaction",
-				"tx", txID(tx),
-				"res", res,
-				"height", memTx.height,
-				"total", mem.Size(),
-			)
-			mem.notifyTxsAvailable()
-		} else {
+		if r.Error != nil {
 			// ignore bad transaction
-			mem.logger.Info("Rejected bad transaction", "tx", txID(tx), "res", res, "err", res.Error)
+			mem.logger.Info("Rejected bad transaction", "tx", txID(tx), "res", r, "err", r.Error)
+			// remove from cache (it might be good later)
+			mem.cache.Remove(tx)
+			break
+		}
+
+		memTx := &mempoolTx{
+			height:    mem.height,
+			gasWanted: r.GasWanted,
+			tx:        tx,
+			priority:  r.Priority,
+			sender:    r.Sender,
+			sequence:  r.Sequence,
+		}
+		memTx.senders.Store(peerID, true)
+		if err := mem.policy.admitTx(memTx, r.Replaces); err != nil {
+			mem.logger.Info("Rejected transaction", "tx", txID(tx), "res", r, "err", err)
 			// remove from cache (it might be good later)
 			mem.cache.Remove(tx)
+			r.Error = abci.StringError(err.Error())
+			return r
 		}
+		mem.logger.Info("Added good transaction",
+			"tx", txID(tx),
+			"res", r,
+			"height", memTx.height,
+			"total", mem.Size(),
+		)
+		mem.notifyTxsAvailable()
 	default:
 		// ignore other messages
 	}
+	return res
 }
 
 // callback, which is called after the app rechecked the tx.
@@ -412,7 +457,8 @@ func (mem *CListMempool) resCbRecheck(req abci.Request, res abci.Response) {
 	switch res := res.(type) {
 	case abci.ResponseCheckTx:
 		tx := req.(abci.RequestCheckTx).Tx
-		memTx := mem.recheckCursor.Value.(*mempoolTx)
+		e := mem.rechecked[mem.recheckCursor]
+		memTx := e.Value.(*mempoolTx)
 		if !bytes.Equal(tx, memTx.tx) {
 			panic(fmt.Sprintf(
 				"Unexpected tx response from proxy during recheck\nExpected %X, got %X",
@@ -425,15 +471,12 @@ func (mem *CListMempool) resCbRecheck(req abci.Request, res abci.Response) {
 			// Tx became invalidated due to newly committed block.
 			mem.logger.Info("Tx is no longer valid", "tx", txID(tx), "res", res, "err", res.Error)
 			// NOTE: we remove tx from the cache because it might be good later
-			mem.removeTx(tx, mem.recheckCursor, true)
+			mem.removeTx(tx, e, true)
 		}
-		if mem.recheckCursor == mem.recheckEnd {
-			mem.recheckCursor = nil
-		} else {
-			mem.recheckCursor = mem.recheckCursor.Next()
-		}
-		if mem.recheckCursor == nil {
+		mem.recheckCursor++
+		if mem.recheckCursor == len(mem.rechecked) {
 			// Done!
+			mem.rechecked = nil
 			atomic.StoreInt32(&mem.rechecking, 0)
 			mem.logger.Info("Done rechecking txs")
 
@@ -484,7 +527,7 @@ func (mem *CListMempool) ReapMaxBytesMaxGas(maxDataBytes, maxGas int64) types.Tx
 	// size per tx, and set the initial capacity based off of that.
 	// txs := make([]types.Tx, 0, min(mem.txs.Len(), max/mem.avgTxSize))
 	txs := make([]types.Tx, 0, mem.txs.Len())
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
+	for _, e := range mem.policy.orderedTxs() {
 		memTx := e.Value.(*mempoolTx)
 		// Check total size requirement
 		if maxDataBytes > -1 && totalBytes+int64(len(memTx.tx)) > maxDataBytes {
@@ -509,17 +552,18 @@ func (mem *CListMempool) ReapMaxTxs(maxVal int) types.Txs {
 	mem.mtx.Lock()
 	defer mem.mtx.Unlock()
 
-	if maxVal < 0 {
-		maxVal = mem.txs.Len()
-	}
-
 	for atomic.LoadInt32(&mem.rechecking) > 0 {
 		// TODO: Something better?
 		time.Sleep(time.Millisecond * 10)
 	}
 
-	txs := make([]types.Tx, 0, min(mem.txs.Len(), maxVal))
-	for e := mem.txs.Front(); e != nil && len(txs) <= maxVal; e = e.Next() {
+	ordered := mem.policy.orderedTxs()
+	if maxVal < 0 || maxVal > len(ordered) {
+		maxVal = len(ordered)
+	}
+
+	txs := make([]types.Tx, 0, maxVal)
+	for _, e := range ordered[:maxVal] {
 		memTx := e.Value.(*mempoolTx)
 		txs = append(txs, memTx.tx)
 	}
@@ -575,8 +619,8 @@ func (mem *CListMempool) Update(
 			mem.logger.Info("Recheck txs", "numtxs", mem.Size(), "height", height)
 			mem.recheckTxs()
 			// At this point, mem.txs are being rechecked.
-			// mem.recheckCursor re-scans mem.txs and possibly removes some txs.
-			// Before mem.Reap(), we should wait for mem.recheckCursor to be nil.
+			// mem.recheckCursor re-scans mem.rechecked and possibly removes some txs.
+			// Before mem.Reap(), we should wait for mem.rechecked to be nil.
 		} else {
 			mem.notifyTxsAvailable()
 		}
@@ -590,13 +634,10 @@ func (mem *CListMempool) recheckTxs() {
 		panic("recheckTxs is called, but the mempool is empty")
 	}
 
-	atomic.StoreInt32(&mem.rechecking, 1)
-	mem.recheckCursor = mem.txs.Front()
-	mem.recheckEnd = mem.txs.Back()
-
-	// Push txs to proxyAppConn
-	// NOTE: globalCb may be called concurrently.
-	for e := mem.txs.Front(); e != nil; e = e.Next() {
+	// The txs are rechecked in the order they are proposed, for the txs of a
+	// same sender to be rechecked in the order of their sequences.
+	var rechecked []*clist.CElement
+	for _, e := range mem.policy.orderedTxs() {
 		memTx := e.Value.(*mempoolTx)
 		// check tx size
 		if int64(len(memTx.tx)) > mem.maxTxBytes {
@@ -610,9 +651,22 @@ func (mem *CListMempool) recheckTxs() {
 				continue
 			}
 		}
+		rechecked = append(rechecked, e)
+	}
+	if len(rechecked) == 0 {
+		return
+	}
+
+	atomic.StoreInt32(&mem.rechecking, 1)
+	mem.rechecked = rechecked
+	mem.recheckCursor = 0
+
+	// Push txs to proxyAppConn
+	// NOTE: globalCb may be called concurrently.
+	for _, e := range rechecked {
 		// run proxy app checktx
 		mem.proxyAppConn.CheckTxAsync(abci.RequestCheckTx{
-			Tx:   memTx.tx,
+			Tx:   e.Value.(*mempoolTx).tx,
 			Type: abci.CheckTxTypeRecheck,
 		})
 	}
@@ -628,6 +682,11 @@ type mempoolTx struct {
 	gasWanted int64    // amount of gas this tx states it will require
 	tx        types.Tx //
 
+	// reported by the application, see abci.ResponseCheckTx
+	priority int64
+	sender   string
+	sequence uint64
+
 	// ids of peers who've sent us this tx (as a map for quick lookups).
 	// senders: PeerID -> bool
 	senders sync.Map
@@ -640,6 +699,87 @@ func (memTx *mempoolTx) Height() int64 {
 
 // --------------------------------------------------------------------------------
 
+// txPolicy defines the txs accepted by a CListMempool and the order they are
+// proposed in.
+type txPolicy interface {
+	// checkFull returns an error if the mempool is full, before the tx is
+	// checked by the application.
+	checkFull(tx types.Tx) error
+
+	// admitTx adds a tx checked by the application to the mempool, or returns
+	// the error rejecting it. The application reports if the tx reuses the
+	// sequence of a pending tx, to replace it.
+	admitTx(memTx *mempoolTx, replaces bool) error
+
+	// replacesTxs returns true if the application may accept the txs reusing
+	// the sequence of a pending tx, see abci.RequestCheckTx.
+	replacesTxs() bool
+
+	// orderedTxs returns the txs in the order they are proposed.
+	orderedTxs() []*clist.CElement
+}
+
+// fifoPolicy is the default policy of a CListMempool, which proposes the txs
+// in the order they were added, and rejects the txs once it is full.
+type fifoPolicy struct {
+	mem *CListMempool
+}
+
+var _ txPolicy = fifoPolicy{}
+
+func (p fifoPolicy) checkFull(tx types.Tx) error {
+	var (
+		memSize  = p.mem.Size()
+		txsBytes = p.mem.TxsBytes()
+	)
+	if memSize >= p.mem.config.Size ||
+		int64(len(tx))+txsBytes > p.mem.config.MaxPendingTxsBytes {
+		return MempoolIsFullError{
+			memSize, p.mem.config.Size,
+			txsBytes, p.mem.config.MaxPendingTxsBytes,
+		}
+	}
+	return nil
+}
+
+// admitTx adds the tx as is: like before the senders were known, the txs
+// reusing the sequence of a pending tx are kept, and the application rejects
+// them when they are proposed after it.
+func (p fifoPolicy) admitTx(memTx *mempoolTx, _ bool) error {
+	p.mem.addTx(memTx)
+	return nil
+}
+
+func (p fifoPolicy) replacesTxs() bool {
+	return false
+}
+
+func (p fifoPolicy) orderedTxs() []*clist.CElement {
+	txs := make([]*clist.CElement, 0, p.mem.txs.Len())
+	for e := p.mem.txs.Front(); e != nil; e = e.Next() {
+		txs = append(txs, e)
+	}
+	return txs
+}
+
+// senderTx returns the pending tx of the sender with the given sequence, or nil.
+func (mem *CListMempool) senderTx(sender string, sequence uint64) *clist.CElement {
+	mem.bySenderMtx.Lock()
+	defer mem.bySenderMtx.Unlock()
+
+	return mem.bySender[sender][sequence]
+}
+
+// numSenderTxs returns the number of pending txs of the sender.
+func (mem *CListMempool) numSenderTxs(sender string) int {
+	mem.bySenderMtx.Lock()
+	defer mem.bySenderMtx.Unlock()
+
+	return len(mem.bySender[sender])
+}
+
+// --------------------------------------------------------------------------------
+
 type txCache interface {
 	Reset()
 	Push(tx types.Tx) bool
@@ -143,6 +143,34 @@ func TestReapMaxBytesMaxGas(t *testing.T) {
 	}
 }
 
+func TestReapMaxTxs(t *testing.T) {
+	app := kvstore.NewKVStoreApplication()
+	cc := proxy.NewLocalClientCreator(app)
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	tests := []struct {
+		numTxsToCreate int
+		maxVal         int
+		expectedNumTxs int
+	}{
+		0: {10, -1, 10},
+		1: {10, 0, 0},
+		2: {10, 1, 1},
+		3: {10, 5, 5},
+		4: {10, 10, 10},
+		5: {10, 20, 10},
+		6: {0, 5, 0},
+	}
+	for tcIndex, tt := range tests {
+		checkTxs(t, mempool, tt.numTxsToCreate, UnknownPeerID, false)
+		got := mempool.ReapMaxTxs(tt.maxVal)
+		assert.Equal(t, tt.expectedNumTxs, len(got), "Got %d txs, expected %d, tc #%d",
+			len(got), tt.expectedNumTxs, tcIndex)
+		mempool.Flush()
+	}
+}
+
 /* XXX test PreCheck filter.
    XXX this used to be a PostCheck filter test, so the code doesn't make much sense.
    TODO change numTxsToCreate to a slice of tx sizes.
@@ -525,6 +553,32 @@ func TestMempoolMaxPendingTxsBytes(t *testing.T) {
 	assert.EqualValues(t, 0, mempool.TxsBytes())
 }
 
+func TestMempoolSenderSequence(t *testing.T) {
+	cc := proxy.NewLocalClientCreator(&priorityApp{})
+	mempool, cleanup := newMempoolWithApp(cc)
+	defer cleanup()
+
+	require.NoError(t, checkTx(t, mempool, "a/0/10"))
+	require.NoError(t, checkTx(t, mempool, "b/0/10"))
+
+	// The txs reusing the sequence of a pending tx are kept after it, not
+	// replacing it
+	require.NoError(t, checkTx(t, mempool, "a/0/20"))
+	assert.Equal(t, []string{"a/0/10", "b/0/10", "a/0/20"}, txStrings(mempool.ReapMaxTxs(-1)))
+
+	// The app is not asked to accept the replacements
+	err := checkTx(t, mempool, "a/0/20/replace")
+	assert.EqualError(t, err, "invalid sequence")
+	assert.Equal(t, 3, mempool.Size())
+
+	// The tx with the same sequence is removed like any other
+	mempool.Update(1, []types.Tx{types.Tx("a/0/10")}, abciResponses(1, nil), nil, 0)
+	assert.Equal(t, []string{"b/0/10", "a/0/20"}, txStrings(mempool.ReapMaxTxs(-1)))
+	mempool.Update(2, []types.Tx{types.Tx("a/0/20")}, abciResponses(1, nil), nil, 0)
+	require.NoError(t, checkTx(t, mempool, "a/0/30"))
+	assert.Equal(t, []string{"b/0/10", "a/0/30"}, txStrings(mempool.ReapMaxTxs(-1)))
+}
+
 func checksumIt(data []byte) string {
 	h := sha256.New()
 	h.Write(data)
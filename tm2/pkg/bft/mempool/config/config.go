@@ -2,6 +2,15 @@ package config
 
 import "github.com/gnolang/gno/tm2/pkg/errors"
 
+const (
+	// MempoolTypeFIFO orders the transactions by arrival.
+	MempoolTypeFIFO = "fifo"
+
+	// MempoolTypePriority orders the transactions by priority, that is by
+	// gas price, and evicts the lowest priority ones when full.
+	MempoolTypePriority = "priority"
+)
+
 // -----------------------------------------------------------------------------
 // MempoolConfig
 
@@ -14,6 +23,8 @@ type MempoolConfig struct {
 	Size               int    `json:"size" toml:"size" comment:"Maximum number of transactions in the mempool"`
 	MaxPendingTxsBytes int64  `json:"max_pending_txs_bytes" toml:"max_pending_txs_bytes" comment:"Limit the total size of all txs in the mempool.\n This only accounts for raw transactions (e.g. given 1MB transactions and\n max_txs_bytes=5MB, mempool will only accept 5 transactions)."`
 	CacheSize          int    `json:"cache_size" toml:"cache_size" comment:"Size of the cache (used to filter transactions we saw earlier) in transactions"`
+	Type               string `json:"type" toml:"type" comment:"Mempool type, one of \"fifo\" (transactions ordered by arrival)\n or \"priority\" (transactions ordered by gas price, the lowest priced ones\n being evicted when full, and replaced by a higher priced one with the same\n sender and sequence)"`
+	MaxTxsPerSender    int    `json:"max_txs_per_sender" toml:"max_txs_per_sender" comment:"Maximum number of transactions of a same sender in the priority mempool\n (0 for no limit)"`
 }
 
 // DefaultMempoolConfig returns a default configuration for the Tendermint mempool
@@ -27,6 +38,8 @@ func DefaultMempoolConfig() *MempoolConfig {
 		Size:               5000,
 		MaxPendingTxsBytes: 1024 * 1024 * 1024, // 1GB
 		CacheSize:          10000,
+		Type:               MempoolTypeFIFO,
+		MaxTxsPerSender:    0,
 	}
 }
 
@@ -59,5 +72,13 @@ func (cfg *MempoolConfig) ValidateBasic() error {
 	if cfg.CacheSize < 0 {
 		return errors.New("cache_size can't be negative")
 	}
+	switch cfg.Type {
+	case "", MempoolTypeFIFO, MempoolTypePriority: // an empty type is a FIFO mempool
+	default:
+		return errors.New("type must be %q or %q", MempoolTypeFIFO, MempoolTypePriority)
+	}
+	if cfg.MaxTxsPerSender < 0 {
+		return errors.New("max_txs_per_sender can't be negative")
+	}
 	return nil
 }
@@ -34,3 +34,21 @@ func (e MempoolIsFullError) Error() string {
 		e.numTxs, e.maxTxs,
 		e.txsBytes, e.maxTxsBytes)
 }
+
+// ErrTxReplacementUnderpriced is returned when a tx does not have a higher
+// priority than the pending tx it replaces, with the same sender and sequence
+var ErrTxReplacementUnderpriced = errors.New("Tx replacement underpriced")
+
+// SenderTxsLimitError means the sender has too many pending txs in the mempool
+type SenderTxsLimitError struct {
+	sender string
+	max    int
+}
+
+func (e SenderTxsLimitError) Error() string {
+	return fmt.Sprintf("sender %s has too many pending txs (max: %d)", e.sender, e.max)
+}
+
+// ErrTxReplacedNotInMempool is returned when a tx reuses the sequence of a tx
+// which is no longer in the mempool, so that it can't replace it
+var ErrTxReplacedNotInMempool = errors.New("Tx replaced by sequence is not in mempool")
@@ -1,8 +1,11 @@
 package mempool
 
 import (
+	"log/slog"
+
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
 )
 
 // Mempool defines the mempool interface.
@@ -76,6 +79,23 @@ type Mempool interface {
 	CloseWAL()
 }
 
+// GossipMempool is a Mempool whose txs are gossiped to the peers by the
+// Reactor, in the order they were added.
+type GossipMempool interface {
+	Mempool
+
+	// SetLogger sets the Logger.
+	SetLogger(*slog.Logger)
+
+	// TxsFront returns the first tx in the list of txs, whose elements
+	// are *mempoolTx.
+	TxsFront() *clist.CElement
+
+	// TxsWaitChan returns a channel which is closed once the mempool is not
+	// empty.
+	TxsWaitChan() <-chan struct{}
+}
+
 //--------------------------------------------------------------------------------
 
 // PreCheckFunc is an optional filter executed before CheckTx and rejects
@@ -0,0 +1,242 @@
+package mempool
+
+import (
+	"container/heap"
+	"sort"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/clist"
+)
+
+// --------------------------------------------------------------------------------
+
+// PriorityMempool is a CListMempool which proposes the transactions by
+// decreasing priority, as reported by the application in CheckTx (ie. the gas
+// price for the SDK). The transactions of a same sender are proposed in the
+// order of their sequences.
+//
+// When full, the mempool evicts the lowest priority transactions to add a
+// higher priority one. A transaction with the same sender and sequence as a
+// pending one replaces it if its priority is higher, and the number of pending
+// transactions of a sender can be limited.
+//
+// The transactions are gossiped in the order they were added.
+type PriorityMempool struct {
+	*CListMempool
+}
+
+var (
+	_ GossipMempool = &PriorityMempool{}
+	_ txPolicy      = &PriorityMempool{}
+)
+
+// NewPriorityMempool returns a new priority mempool with the given
+// configuration and connection to an application.
+func NewPriorityMempool(
+	config *cfg.MempoolConfig,
+	proxyAppConn appconn.Mempool,
+	height int64,
+	maxTxBytes int64,
+	options ...CListMempoolOption,
+) *PriorityMempool {
+	mempool := &PriorityMempool{
+		CListMempool: NewCListMempool(config, proxyAppConn, height, maxTxBytes, options...),
+	}
+	mempool.policy = mempool
+	return mempool
+}
+
+// checkFull implements txPolicy. A full mempool is checked once the priority
+// of the tx is known, to evict the lower priority txs.
+func (mem *PriorityMempool) checkFull(types.Tx) error {
+	return nil
+}
+
+// admitTx implements txPolicy. It adds a checked tx to the mempool, replacing
+// the pending tx with the same sender and sequence, and evicting lower
+// priority txs if the mempool is full.
+func (mem *PriorityMempool) admitTx(memTx *mempoolTx, replaces bool) error {
+	var replaced *clist.CElement
+	if memTx.sender != "" {
+		replaced = mem.senderTx(memTx.sender, memTx.sequence)
+
+		switch maxTxs := mem.config.MaxTxsPerSender; {
+		case replaced != nil:
+			if replaced.Value.(*mempoolTx).priority >= memTx.priority {
+				return ErrTxReplacementUnderpriced
+			}
+		case replaces:
+			// The tx can only be added in place of the pending tx, as the
+			// application already checked the txs following it.
+			return ErrTxReplacedNotInMempool
+		case maxTxs > 0 && mem.numSenderTxs(memTx.sender) >= maxTxs:
+			return SenderTxsLimitError{memTx.sender, maxTxs}
+		}
+	}
+
+	evicted, err := mem.evictedTxs(memTx, replaced)
+	if err != nil {
+		return err
+	}
+
+	if replaced != nil {
+		replacedTx := replaced.Value.(*mempoolTx).tx
+		mem.logger.Info("Replaced transaction", "tx", txID(replacedTx), "by", txID(memTx.tx))
+		mem.removeTx(replacedTx, replaced, true)
+	}
+	for _, e := range evicted {
+		evictedTx := e.Value.(*mempoolTx).tx
+		mem.logger.Info("Evicted transaction", "tx", txID(evictedTx), "by", txID(memTx.tx))
+		mem.removeTx(evictedTx, e, true)
+	}
+	mem.addTx(memTx)
+
+	return nil
+}
+
+// replacesTxs implements txPolicy.
+func (mem *PriorityMempool) replacesTxs() bool {
+	return true
+}
+
+// orderedTxs implements txPolicy. It returns the txs by decreasing priority,
+// the txs of a same sender being in the order of their sequences.
+func (mem *PriorityMempool) orderedTxs() []*clist.CElement {
+	groups := senderGroupHeap(senderGroups(mem.txs))
+	heap.Init(&groups)
+
+	ordered := make([]*clist.CElement, 0, mem.txs.Len())
+	for groups.Len() > 0 {
+		group := &groups[0]
+		ordered = append(ordered, group.txs[0])
+		if group.txs = group.txs[1:]; len(group.txs) > 0 {
+			heap.Fix(&groups, 0)
+		} else {
+			heap.Pop(&groups)
+		}
+	}
+
+	return ordered
+}
+
+// evictedTxs returns the txs to evict for the given tx to fit in the mempool,
+// excluding the tx it replaces, or an error if there is not enough txs with a
+// lower priority to evict.
+//
+// The last tx of a sender is evicted first, as the next ones couldn't be
+// proposed without it, and the txs of the sender of the given tx are not
+// evicted.
+func (mem *PriorityMempool) evictedTxs(memTx *mempoolTx, replaced *clist.CElement) ([]*clist.CElement, error) {
+	var (
+		numTxs   = mem.Size() + 1
+		txsBytes = mem.TxsBytes() + int64(len(memTx.tx))
+	)
+	if replaced != nil {
+		numTxs--
+		txsBytes -= int64(len(replaced.Value.(*mempoolTx).tx))
+	}
+	isFull := func() bool {
+		return numTxs > mem.config.Size || txsBytes > mem.config.MaxPendingTxsBytes
+	}
+	if !isFull() {
+		return nil, nil
+	}
+
+	var evicted []*clist.CElement
+	groups := senderGroups(mem.txs)
+	for isFull() {
+		var lowest *mempoolTx
+		lowestGroup := -1
+		for i, group := range groups {
+			if len(group.txs) == 0 {
+				continue
+			}
+			tx := group.txs[len(group.txs)-1].Value.(*mempoolTx)
+			if tx.sender != "" && tx.sender == memTx.sender {
+				continue
+			}
+			if lowest == nil || tx.priority < lowest.priority {
+				lowest, lowestGroup = tx, i
+			}
+		}
+		if lowest == nil || lowest.priority >= memTx.priority {
+			return nil, MempoolIsFullError{
+				mem.Size(), mem.config.Size,
+				mem.TxsBytes(), mem.config.MaxPendingTxsBytes,
+			}
+		}
+
+		group := &groups[lowestGroup]
+		evicted = append(evicted, group.txs[len(group.txs)-1])
+		group.txs = group.txs[:len(group.txs)-1]
+		numTxs--
+		txsBytes -= int64(len(lowest.tx))
+	}
+
+	return evicted, nil
+}
+
+// senderGroup is the txs of a sender, by increasing sequence, or a single tx
+// without sender.
+type senderGroup struct {
+	txs   []*clist.CElement
+	index int // index of the group, in the order the txs were added
+}
+
+// senderGroups returns the txs of the list grouped by sender.
+func senderGroups(txs *clist.CList) []senderGroup {
+	var (
+		groups  []senderGroup
+		indexes = make(map[string]int)
+	)
+	for e := txs.Front(); e != nil; e = e.Next() {
+		sender := e.Value.(*mempoolTx).sender
+		if i, ok := indexes[sender]; ok && sender != "" {
+			groups[i].txs = append(groups[i].txs, e)
+			continue
+		}
+		if sender != "" {
+			indexes[sender] = len(groups)
+		}
+		groups = append(groups, senderGroup{txs: []*clist.CElement{e}, index: len(groups)})
+	}
+
+	for _, group := range groups {
+		sort.SliceStable(group.txs, func(i, j int) bool {
+			return group.txs[i].Value.(*mempoolTx).sequence < group.txs[j].Value.(*mempoolTx).sequence
+		})
+	}
+
+	return groups
+}
+
+// senderGroupHeap is a max-heap of non-empty sender groups, by the priority of
+// their next tx, the first added group first on a tie.
+type senderGroupHeap []senderGroup
+
+var _ heap.Interface = (*senderGroupHeap)(nil)
+
+func (h senderGroupHeap) Len() int { return len(h) }
+
+func (h senderGroupHeap) Less(i, j int) bool {
+	pi := h[i].txs[0].Value.(*mempoolTx).priority
+	pj := h[j].txs[0].Value.(*mempoolTx).priority
+	if pi != pj {
+		return pi > pj
+	}
+	return h[i].index < h[j].index
+}
+
+func (h senderGroupHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+
+func (h *senderGroupHeap) Push(x any) { *h = append(*h, x.(senderGroup)) }
+
+func (h *senderGroupHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,224 @@
+package mempool
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+)
+
+// priorityApp reports the sender, sequence and priority of the txs formatted
+// as "sender/sequence/priority[/memo]", and records the rechecked ones. The
+// txs with a "replace" memo reuse the sequence of a pending tx, and are only
+// accepted if the mempool replaces the txs.
+type priorityApp struct {
+	abci.BaseApplication
+
+	invalid   map[string]bool // txs failing the recheck
+	rechecked []string
+}
+
+func (app *priorityApp) CheckTx(req abci.RequestCheckTx) abci.ResponseCheckTx {
+	tx := string(req.Tx)
+	if req.Type == abci.CheckTxTypeRecheck {
+		app.rechecked = append(app.rechecked, tx)
+		if app.invalid[tx] {
+			return abci.ResponseCheckTx{ResponseBase: abci.ResponseBase{Error: abci.StringError("invalid")}}
+		}
+	}
+
+	parts := strings.Split(tx, "/")
+	sequence, _ := strconv.ParseUint(parts[1], 10, 64)
+	priority, _ := strconv.ParseInt(parts[2], 10, 64)
+	replaces := len(parts) > 3 && parts[3] == "replace"
+	if replaces && !req.AllowReplacement {
+		return abci.ResponseCheckTx{ResponseBase: abci.ResponseBase{Error: abci.StringError("invalid sequence")}}
+	}
+
+	return abci.ResponseCheckTx{
+		GasWanted: 1,
+		Priority:  priority,
+		Sender:    parts[0],
+		Sequence:  sequence,
+		Replaces:  replaces,
+	}
+}
+
+func newPriorityMempool(t *testing.T, app abci.Application, config *cfg.MempoolConfig) *PriorityMempool {
+	t.Helper()
+
+	appConnMem, _ := proxy.NewLocalClientCreator(app).NewABCIClient()
+	appConnMem.SetLogger(log.NewNoopLogger())
+	require.NoError(t, appConnMem.Start())
+	t.Cleanup(func() { appConnMem.Stop() })
+
+	config.Type = cfg.MempoolTypePriority
+	return NewPriorityMempool(config, appConnMem, 0, testMaxTxBytes)
+}
+
+// checkTx checks the tx, returning the CheckTx error or the response one.
+func checkTx(t *testing.T, mempool Mempool, tx string) error {
+	t.Helper()
+
+	var resErr error
+	err := mempool.CheckTx(types.Tx(tx), func(res abci.Response) {
+		if r := res.(abci.ResponseCheckTx); r.Error != nil {
+			resErr = r.Error
+		}
+	})
+	if err != nil {
+		return err
+	}
+	return resErr
+}
+
+func txStrings(txs types.Txs) []string {
+	strs := make([]string, 0, len(txs))
+	for _, tx := range txs {
+		strs = append(strs, string(tx))
+	}
+	return strs
+}
+
+func TestPriorityMempool_Reap(t *testing.T) {
+	t.Parallel()
+
+	mempool := newPriorityMempool(t, &priorityApp{}, cfg.TestMempoolConfig())
+	for _, tx := range []string{"a/0/10", "b/0/30", "a/1/50", "c/0/20", "/0/40"} {
+		require.NoError(t, checkTx(t, mempool, tx))
+	}
+
+	// The txs of a same sender are in the order of their sequences
+	expected := []string{"/0/40", "b/0/30", "c/0/20", "a/0/10", "a/1/50"}
+	assert.Equal(t, expected, txStrings(mempool.ReapMaxTxs(-1)))
+	assert.Equal(t, expected[:2], txStrings(mempool.ReapMaxTxs(2)))
+	assert.Equal(t, expected[:3], txStrings(mempool.ReapMaxBytesMaxGas(-1, 3)))
+	assert.Equal(t, expected[:1], txStrings(mempool.ReapMaxBytesMaxGas(6, -1)))
+
+	// The txs are gossiped in the order they were added
+	assert.Equal(t, "a/0/10", string(mempool.TxsFront().Value.(*mempoolTx).tx))
+}
+
+func TestPriorityMempool_Eviction(t *testing.T) {
+	t.Parallel()
+
+	config := cfg.TestMempoolConfig()
+	config.Size = 3
+	mempool := newPriorityMempool(t, &priorityApp{}, config)
+	for _, tx := range []string{"a/0/10", "b/0/20", "c/0/30"} {
+		require.NoError(t, checkTx(t, mempool, tx))
+	}
+
+	err := checkTx(t, mempool, "d/0/5")
+	assert.ErrorContains(t, err, "mempool is full")
+
+	// The lowest priority tx is evicted
+	require.NoError(t, checkTx(t, mempool, "d/0/25"))
+	assert.Equal(t, []string{"c/0/30", "d/0/25", "b/0/20"}, txStrings(mempool.ReapMaxTxs(-1)))
+
+	// The next tx of a sender is not evicted before its last one
+	require.NoError(t, checkTx(t, mempool, "b/1/40"))
+	assert.Equal(t, []string{"c/0/30", "b/0/20", "b/1/40"}, txStrings(mempool.ReapMaxTxs(-1)))
+
+	err = checkTx(t, mempool, "e/0/22")
+	assert.ErrorContains(t, err, "mempool is full")
+
+	require.NoError(t, checkTx(t, mempool, "a/0/50"))
+	assert.Equal(t, []string{"a/0/50", "b/0/20", "b/1/40"}, txStrings(mempool.ReapMaxTxs(-1)))
+
+	t.Run("max pending txs bytes", func(t *testing.T) {
+		t.Parallel()
+
+		config := cfg.TestMempoolConfig()
+		config.MaxPendingTxsBytes = 12
+		mempool := newPriorityMempool(t, &priorityApp{}, config)
+		require.NoError(t, checkTx(t, mempool, "a/0/10"))
+		require.NoError(t, checkTx(t, mempool, "b/0/20"))
+
+		require.NoError(t, checkTx(t, mempool, "c/0/15"))
+		assert.Equal(t, []string{"b/0/20", "c/0/15"}, txStrings(mempool.ReapMaxTxs(-1)))
+		assert.Equal(t, int64(12), mempool.TxsBytes())
+	})
+}
+
+func TestPriorityMempool_Replacement(t *testing.T) {
+	t.Parallel()
+
+	mempool := newPriorityMempool(t, &priorityApp{}, cfg.TestMempoolConfig())
+	require.NoError(t, checkTx(t, mempool, "a/0/10"))
+	require.NoError(t, checkTx(t, mempool, "a/1/10"))
+
+	for _, tx := range []string{"a/0/5/replace", "a/0/10/replace"} {
+		err := checkTx(t, mempool, tx)
+		assert.EqualError(t, err, ErrTxReplacementUnderpriced.Error(), tx)
+	}
+
+	require.NoError(t, checkTx(t, mempool, "a/0/20/replace"))
+	assert.Equal(t, []string{"a/0/20/replace", "a/1/10"}, txStrings(mempool.ReapMaxTxs(-1)))
+	assert.Equal(t, 2, mempool.Size())
+
+	// The txs replacing a tx which is no longer pending are rejected
+	mempool.Lock()
+	err := mempool.Update(1, types.Txs{types.Tx("a/0/20/replace")}, abciResponses(1, nil), nil, 0)
+	mempool.Unlock()
+	require.NoError(t, err)
+
+	err = checkTx(t, mempool, "a/0/30/replace")
+	assert.EqualError(t, err, ErrTxReplacedNotInMempool.Error())
+	assert.Equal(t, []string{"a/1/10"}, txStrings(mempool.ReapMaxTxs(-1)))
+}
+
+func TestPriorityMempool_SenderLimit(t *testing.T) {
+	t.Parallel()
+
+	config := cfg.TestMempoolConfig()
+	config.MaxTxsPerSender = 2
+	mempool := newPriorityMempool(t, &priorityApp{}, config)
+	require.NoError(t, checkTx(t, mempool, "a/0/10"))
+	require.NoError(t, checkTx(t, mempool, "a/1/10"))
+
+	err := checkTx(t, mempool, "a/2/10")
+	assert.EqualError(t, err, SenderTxsLimitError{"a", 2}.Error())
+
+	// The replacements and the other senders are not limited
+	require.NoError(t, checkTx(t, mempool, "a/1/20/replace"))
+	require.NoError(t, checkTx(t, mempool, "b/0/10"))
+	assert.Equal(t, 3, mempool.Size())
+}
+
+func TestPriorityMempool_Update(t *testing.T) {
+	t.Parallel()
+
+	app := &priorityApp{invalid: map[string]bool{"b/0/30": true}}
+	mempool := newPriorityMempool(t, app, cfg.TestMempoolConfig())
+	for _, tx := range []string{"a/1/50", "a/0/10", "b/0/30", "c/0/40"} {
+		require.NoError(t, checkTx(t, mempool, tx))
+	}
+
+	// The committed txs are removed, and the others are rechecked in the
+	// order they are proposed
+	mempool.Lock()
+	err := mempool.Update(1, types.Txs{types.Tx("c/0/40")}, abciResponses(1, nil), nil, 0)
+	mempool.Unlock()
+	require.NoError(t, err)
+	require.NoError(t, mempool.FlushAppConn())
+
+	assert.Equal(t, []string{"b/0/30", "a/0/10", "a/1/50"}, app.rechecked)
+	assert.Equal(t, []string{"a/0/10", "a/1/50"}, txStrings(mempool.ReapMaxTxs(-1)))
+
+	err = checkTx(t, mempool, "c/0/40")
+	assert.ErrorIs(t, err, ErrTxInCache)
+
+	mempool.Flush()
+	assert.Zero(t, mempool.Size())
+	assert.Zero(t, mempool.TxsBytes())
+	require.NoError(t, checkTx(t, mempool, "a/0/10"))
+}
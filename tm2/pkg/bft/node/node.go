@@ -4,6 +4,8 @@ package node
 // is enabled by the user by setting a profiling address
 
 import (
+	"context"
+	"encoding/hex"
 	"fmt"
 	"log/slog"
 	"net"
@@ -15,6 +17,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
 	"github.com/gnolang/gno/tm2/pkg/bft/privval"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/file"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
 	"github.com/gnolang/gno/tm2/pkg/p2p/conn"
 	"github.com/gnolang/gno/tm2/pkg/p2p/discovery"
 	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
@@ -25,13 +28,16 @@ import (
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/config"
 	cs "github.com/gnolang/gno/tm2/pkg/bft/consensus"
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
+	memcfg "github.com/gnolang/gno/tm2/pkg/bft/mempool/config"
 	"github.com/gnolang/gno/tm2/pkg/bft/proxy"
+	"github.com/gnolang/gno/tm2/pkg/bft/pruner"
 	rpccore "github.com/gnolang/gno/tm2/pkg/bft/rpc/core"
 	_ "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpcserver "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/server"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
+	"github.com/gnolang/gno/tm2/pkg/bft/statesync"
 	"github.com/gnolang/gno/tm2/pkg/bft/store"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
@@ -52,6 +58,7 @@ const (
 	blockchainReactorName = "BLOCKCHAIN"
 	consensusReactorName  = "CONSENSUS"
 	discoveryReactorName  = "DISCOVERY"
+	stateSyncReactorName  = "STATESYNC"
 )
 
 const (
@@ -60,6 +67,7 @@ const (
 	consensusModuleName  = "consensus"
 	p2pModuleName        = "p2p"
 	discoveryModuleName  = "discovery"
+	stateSyncModuleName  = "statesync"
 )
 
 // ------------------------------------------------------------------------------
@@ -167,9 +175,12 @@ type Node struct {
 	// services
 	evsw              events.EventSwitch
 	stateDB           dbm.DB
-	blockStore        *store.BlockStore // store the blockchain to disk
-	bcReactor         p2p.Reactor       // for fast-syncing
-	mempoolReactor    *mempl.Reactor    // for gossipping transactions
+	blockStore        *store.BlockStore     // store the blockchain to disk
+	bcReactor         *bc.BlockchainReactor // for fast-syncing
+	stateSyncReactor  *statesync.Reactor    // for restoring the state from snapshots
+	stateSync         bool                  // whether the node state syncs on start
+	stateSyncCancel   context.CancelFunc
+	mempoolReactor    *mempl.Reactor // for gossipping transactions
 	mempool           mempl.Mempool
 	consensusState    *cs.ConsensusState   // latest consensus state
 	consensusReactor  *cs.ConsensusReactor // for participating in the consensus
@@ -177,6 +188,7 @@ type Node struct {
 	rpcListeners      []net.Listener       // rpc servers
 	txEventStore      eventstore.TxEventStore
 	eventStoreService *eventstore.Service
+	pruner            *pruner.Pruner
 	firstBlockSignal  <-chan struct{}
 }
 
@@ -207,6 +219,7 @@ func createAndStartProxyAppConns(clientCreator proxy.ClientCreator, logger *slog
 
 func createAndStartEventStoreService(
 	cfg *cfg.Config,
+	dbProvider DBProvider,
 	evsw events.EventSwitch,
 	logger *slog.Logger,
 ) (*eventstore.Service, eventstore.TxEventStore, error) {
@@ -223,6 +236,13 @@ func createAndStartEventStoreService(
 		if err != nil {
 			return nil, nil, fmt.Errorf("unable to create file tx event store, %w", err)
 		}
+	case kv.EventStoreType:
+		// Transaction events should be indexed in a database
+		txIndexDB, err := dbProvider(&DBContext{"tx_index", cfg})
+		if err != nil {
+			return nil, nil, fmt.Errorf("unable to create kv tx event store, %w", err)
+		}
+		txEventStore = kv.NewTxEventStore(txIndexDB)
 	default:
 		// Transaction event storing should be omitted
 		txEventStore = null.NewNullEventStore()
@@ -237,6 +257,22 @@ func createAndStartEventStoreService(
 	return indexerService, txEventStore, nil
 }
 
+func createAndStartPruner(
+	cfg *cfg.Config,
+	stateDB dbm.DB,
+	blockStore *store.BlockStore,
+	evsw events.EventSwitch,
+	logger *slog.Logger,
+) (*pruner.Pruner, error) {
+	p := pruner.NewPruner(cfg.Pruning, stateDB, blockStore, evsw)
+	p.SetLogger(logger.With("module", "pruner"))
+	if err := p.Start(); err != nil {
+		return nil, fmt.Errorf("unable to start pruner, %w", err)
+	}
+
+	return p, nil
+}
+
 func doHandshake(stateDB dbm.DB, state sm.State, blockStore sm.BlockStore,
 	genDoc *types.GenesisDoc, evsw events.EventSwitch, proxyApp appconn.AppConns, consensusLogger *slog.Logger,
 ) error {
@@ -274,14 +310,26 @@ func onlyValidatorIsUs(state sm.State, privVal types.PrivValidator) bool {
 
 func createMempoolAndMempoolReactor(config *cfg.Config, proxyApp appconn.AppConns,
 	state sm.State, logger *slog.Logger,
-) (*mempl.Reactor, *mempl.CListMempool) {
-	mempool := mempl.NewCListMempool(
-		config.Mempool,
-		proxyApp.Mempool(),
-		state.LastBlockHeight,
-		state.ConsensusParams.Block.MaxTxBytes,
-		mempl.WithPreCheck(sm.TxPreCheck(state)),
-	)
+) (*mempl.Reactor, mempl.GossipMempool) {
+	var mempool mempl.GossipMempool
+	switch config.Mempool.Type {
+	case memcfg.MempoolTypePriority:
+		mempool = mempl.NewPriorityMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			state.ConsensusParams.Block.MaxTxBytes,
+			mempl.WithPreCheck(sm.TxPreCheck(state)),
+		)
+	default:
+		mempool = mempl.NewCListMempool(
+			config.Mempool,
+			proxyApp.Mempool(),
+			state.LastBlockHeight,
+			state.ConsensusParams.Block.MaxTxBytes,
+			mempl.WithPreCheck(sm.TxPreCheck(state)),
+		)
+	}
 	mempoolLogger := logger.With("module", mempoolModuleName)
 	mempoolReactor := mempl.NewReactor(config.Mempool, mempool)
 	mempoolReactor.SetLogger(mempoolLogger)
@@ -299,7 +347,7 @@ func createBlockchainReactor(
 	fastSync bool,
 	switchToConsensusFn bc.SwitchToConsensusFn,
 	logger *slog.Logger,
-) (bcReactor p2p.Reactor, err error) {
+) (bcReactor *bc.BlockchainReactor, err error) {
 	bcReactor = bc.NewBlockchainReactor(
 		state.Copy(),
 		blockExec,
@@ -316,7 +364,7 @@ func createConsensusReactor(config *cfg.Config,
 	state sm.State,
 	blockExec *sm.BlockExecutor,
 	blockStore sm.BlockStore,
-	mempool *mempl.CListMempool,
+	mempool mempl.Mempool,
 	privValidator types.PrivValidator,
 	fastSync bool,
 	evsw events.EventSwitch,
@@ -388,22 +436,35 @@ func NewNode(config *cfg.Config,
 	})
 
 	// Transaction event storing
-	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, evsw, logger)
+	eventStoreService, txEventStore, err := createAndStartEventStoreService(config, dbProvider, evsw, logger)
 	if err != nil {
 		return nil, err
 	}
 
+	// A fresh node restores its state from the snapshots of its peers, if
+	// the state sync is enabled. The application is then initialized by the
+	// snapshot, instead of the handshake.
+	stateSync := config.StateSync.Enable && state.LastBlockHeight == 0 && blockStore.Height() == 0
+
 	// Create the handshaker, which calls RequestInfo, sets the AppVersion on the state,
 	// and replays any blocks as necessary to sync tendermint with the app.
 	consensusLogger := logger.With("module", consensusModuleName)
-	if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
-		return nil, err
+	if !stateSync {
+		if err := doHandshake(stateDB, state, blockStore, genDoc, evsw, proxyApp, consensusLogger); err != nil {
+			return nil, err
+		}
+
+		// Reload the state. It will have the Version.Consensus.App set by the
+		// Handshake, and may have other modifications as well (ie. depending on
+		// what happened during block replay).
+		state = sm.LoadState(stateDB)
 	}
 
-	// Reload the state. It will have the Version.Consensus.App set by the
-	// Handshake, and may have other modifications as well (ie. depending on
-	// what happened during block replay).
-	state = sm.LoadState(stateDB)
+	// Block pruning, once the blocks needed by the handshake were replayed
+	blockPruner, err := createAndStartPruner(config, stateDB, blockStore, evsw, logger)
+	if err != nil {
+		return nil, err
+	}
 
 	logNodeStartupInfo(state, privValidator.PubKey(), logger, consensusLogger)
 
@@ -422,18 +483,18 @@ func NewNode(config *cfg.Config,
 		mempool,
 	)
 
-	// Make ConsensusReactor
+	// Make ConsensusReactor, waiting for the state sync if any
 	consensusReactor, consensusState := createConsensusReactor(
 		config, state, blockExec, blockStore, mempool,
-		privValidator, fastSync, evsw, consensusLogger,
+		privValidator, fastSync || stateSync, evsw, consensusLogger,
 	)
 
-	// Make BlockchainReactor
+	// Make BlockchainReactor, which fast syncs once the state is restored
 	bcReactor, err := createBlockchainReactor(
 		state,
 		blockExec,
 		blockStore,
-		fastSync,
+		fastSync && !stateSync,
 		consensusReactor.SwitchToConsensus,
 		logger,
 	)
@@ -441,6 +502,10 @@ func NewNode(config *cfg.Config,
 		return nil, errors.Wrap(err, "could not create blockchain reactor")
 	}
 
+	// Make StateSyncReactor, serving the snapshots of the application
+	stateSyncReactor := statesync.NewReactor(config.StateSync, proxyApp.Snapshot())
+	stateSyncReactor.SetLogger(logger.With("module", stateSyncModuleName))
+
 	reactors := []nodeReactor{
 		{
 			mempoolReactorName, mempoolReactor,
@@ -451,6 +516,9 @@ func NewNode(config *cfg.Config,
 		{
 			consensusReactorName, consensusReactor,
 		},
+		{
+			stateSyncReactorName, stateSyncReactor,
+		},
 	}
 
 	nodeInfo, err := makeNodeInfo(config, nodeKey, txEventStore, genDoc, state)
@@ -545,6 +613,8 @@ func NewNode(config *cfg.Config,
 		stateDB:           stateDB,
 		blockStore:        blockStore,
 		bcReactor:         bcReactor,
+		stateSyncReactor:  stateSyncReactor,
+		stateSync:         stateSync,
 		mempoolReactor:    mempoolReactor,
 		mempool:           mempool,
 		consensusState:    consensusState,
@@ -552,6 +622,7 @@ func NewNode(config *cfg.Config,
 		proxyApp:          proxyApp,
 		txEventStore:      txEventStore,
 		eventStoreService: eventStoreService,
+		pruner:            blockPruner,
 		firstBlockSignal:  cFirstBlock,
 	}
 	node.BaseService = *service.NewBaseService(logger, "Node", node)
@@ -633,6 +704,62 @@ func (n *Node) OnStart() error {
 	// Dial the persistent peers
 	n.sw.DialPeers(peerAddrs...)
 
+	// Restore the state from the snapshots of the peers
+	if n.stateSync {
+		if err := n.startStateSync(); err != nil {
+			return fmt.Errorf("unable to start state sync, %w", err)
+		}
+	}
+
+	return nil
+}
+
+// startStateSync restores the state of the node from the snapshots of its
+// peers, in the background. The node then fast syncs the following blocks.
+func (n *Node) startStateSync() error {
+	config := n.config.StateSync
+
+	trustHash, err := hex.DecodeString(config.TrustHash)
+	if err != nil {
+		return fmt.Errorf("invalid trust hash, %w", err)
+	}
+
+	stateProvider, err := statesync.NewRPCStateProvider(
+		n.genesisDoc.ChainID,
+		config.RPCServerList(),
+		config.TrustPeriod,
+		config.TrustHeight,
+		trustHash,
+	)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	n.stateSyncCancel = cancel
+
+	go func() {
+		state, commit, err := n.stateSyncReactor.Sync(ctx, stateProvider)
+		if err != nil {
+			if ctx.Err() == nil {
+				n.Logger.Error("State sync failed, the node data must be reset before retrying", "err", err)
+			}
+			return
+		}
+
+		state = sm.BootstrapState(n.stateDB, state)
+		if err := n.blockStore.Bootstrap(state.LastBlockHeight, commit); err != nil {
+			n.Logger.Error("Unable to bootstrap the block store", "err", err)
+			return
+		}
+
+		n.Logger.Info("State sync complete, fast syncing", "height", state.LastBlockHeight)
+
+		if err := n.bcReactor.SwitchToFastSync(state); err != nil {
+			n.Logger.Error("Unable to switch to fast sync", "err", err)
+		}
+	}()
+
 	return nil
 }
 
@@ -642,6 +769,11 @@ func (n *Node) OnStop() {
 
 	n.Logger.Info("Stopping Node")
 
+	// Abort any running state sync
+	if n.stateSyncCancel != nil {
+		n.stateSyncCancel()
+	}
+
 	// Fist close the private validator
 	if err := n.privValidator.Close(); err != nil {
 		n.Logger.Error("Error closing private validator", "err", err)
@@ -650,6 +782,7 @@ func (n *Node) OnStop() {
 	// Stop the non-reactor services
 	n.evsw.Stop()
 	n.eventStoreService.Stop()
+	n.pruner.Stop()
 
 	// Stop the node p2p transport
 	if err := n.transport.Close(); err != nil {
@@ -698,6 +831,7 @@ func (n *Node) configureRPC() {
 	rpccore.SetLogger(n.Logger.With("module", "rpc"))
 	rpccore.SetEventSwitch(n.evsw)
 	rpccore.SetConfig(*n.config.RPC)
+	rpccore.SetTxEventStore(n.txEventStore)
 }
 
 func (n *Node) startRPC() (listeners []net.Listener, err error) {
@@ -734,7 +868,8 @@ func (n *Node) startRPC() (listeners []net.Listener, err error) {
 		wm := rpcserver.NewWebsocketManager(rpccore.Routes,
 			rpcserver.OnDisconnect(func(remoteAddr string) {
 				// any cleanup...
-				// (we used to unsubscribe from all event subscriptions)
+				// (event subscriptions are removed when the
+				// connection's context is canceled)
 			}),
 			rpcserver.ReadLimit(config.MaxBodyBytes),
 		)
@@ -897,6 +1032,7 @@ func makeNodeInfo(
 			bcChannel,
 			cs.StateChannel, cs.DataChannel, cs.VoteChannel, cs.VoteSetBitsChannel,
 			mempl.MempoolChannel,
+			statesync.SnapshotChannel, statesync.ChunkChannel,
 		},
 		Moniker: config.Moniker,
 		Other: p2pTypes.NodeInfoOther{
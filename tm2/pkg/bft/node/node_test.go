@@ -160,6 +160,38 @@ func TestNodeSetAppVersion(t *testing.T) {
 	assert.Equal(t, appVersion2.Version, appVersion)
 }
 
+func TestNodeStateSync(t *testing.T) {
+	config, genesisFile := cfg.ResetTestRoot("node_state_sync_test")
+	defer os.RemoveAll(config.RootDir)
+
+	config.StateSync.Enable = true
+	config.StateSync.RPCServers = "tcp://127.0.0.1:0"
+	config.StateSync.TrustHeight = 1
+	config.StateSync.TrustHash = "00"
+
+	// create & start node
+	n, err := DefaultNewNode(config, genesisFile, events.NewEventSwitch(), log.NewTestingLogger(t))
+	require.NoError(t, err)
+
+	// the fresh node waits for the state sync, instead of initializing the
+	// application
+	assert.True(t, n.stateSync)
+	assert.True(t, n.ConsensusReactor().FastSync())
+	assert.Empty(t, sm.LoadState(n.stateDB).AppVersion)
+
+	err = n.Start()
+	require.NoError(t, err)
+	defer n.Stop()
+
+	// no block is produced until the state is restored
+	select {
+	case <-time.After(time.Second):
+	case <-n.Ready():
+		require.FailNow(t, "no block should be produced before the state sync")
+	}
+	assert.Equal(t, int64(0), n.BlockStore().Height())
+}
+
 func TestNodeSetPrivValTCP(t *testing.T) {
 	addr := "tcp://" + testFreeAddr(t)
 
@@ -7,13 +7,14 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/bft/privval/signer/local"
 	rsclient "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/client"
+	"github.com/gnolang/gno/tm2/pkg/bft/privval/signer/threshold"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 )
 
-// PrivValidatorConfig defines the configuration for the PrivValidator, with a local or remote
-// signer, including network parameters and filepaths.
+// PrivValidatorConfig defines the configuration for the PrivValidator, with a local, remote
+// or threshold signer, including network parameters and filepaths.
 type PrivValidatorConfig struct {
 	// File path configuration.
 	RootDir     string `json:"home" toml:"home"`
@@ -22,6 +23,9 @@ type PrivValidatorConfig struct {
 
 	// Remote Signer configuration.
 	RemoteSigner *rsclient.RemoteSignerClientConfig `json:"remote_signer" toml:"remote_signer" comment:"Configuration for the remote signer client"`
+
+	// Threshold Signer configuration.
+	ThresholdSigner *threshold.ThresholdSignerConfig `json:"threshold_signer" toml:"threshold_signer" comment:"Configuration for the threshold signer, using the remote signer client options to dial each of its remote signers"`
 }
 
 // PrivValidatorConfig validation errors.
@@ -33,9 +37,10 @@ var (
 // DefaultPrivValidatorConfig returns a default configuration for the PrivValidator.
 func DefaultPrivValidatorConfig() *PrivValidatorConfig {
 	return &PrivValidatorConfig{
-		SignState:    "priv_validator_state.json",
-		LocalSigner:  "priv_validator_key.json",
-		RemoteSigner: rsclient.DefaultRemoteSignerClientConfig(),
+		SignState:       "priv_validator_state.json",
+		LocalSigner:     "priv_validator_key.json",
+		RemoteSigner:    rsclient.DefaultRemoteSignerClientConfig(),
+		ThresholdSigner: threshold.DefaultThresholdSignerConfig(),
 	}
 }
 
@@ -72,12 +77,19 @@ func (cfg *PrivValidatorConfig) ValidateBasic() error {
 		return err
 	}
 
+	// Validate the threshold signer configuration.
+	if cfg.ThresholdSigner != nil {
+		if err := cfg.ThresholdSigner.ValidateBasic(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
 // NewPrivValidatorFromConfig returns a new PrivValidator instance based on the configuration.
-// The clientLogger is only used for the remote signer client and ignored it the signer is local.
-// The clientPrivKey is only used for the remote signer client using a TCP connection.
+// The clientLogger is only used for the remote signer clients and ignored it the signer is local.
+// The clientPrivKey is only used for the remote signer clients using a TCP connection.
 func NewPrivValidatorFromConfig(
 	config *PrivValidatorConfig,
 	clientPrivKey ed25519.PrivKeyEd25519,
@@ -89,15 +101,26 @@ func NewPrivValidatorFromConfig(
 	)
 
 	// Initialize the signer based on the configuration.
+	switch {
+	// If the threshold signer addresses are set, use a threshold signer.
+	case config.ThresholdSigner != nil && len(config.ThresholdSigner.ServerAddresses) > 0:
+		signer, err = threshold.NewThresholdSignerFromConfig(
+			config.ThresholdSigner,
+			config.RemoteSigner,
+			clientPrivKey,
+			clientLogger,
+		)
+
 	// If the remote signer address is set, use a remote signer client.
-	if config.RemoteSigner != nil && config.RemoteSigner.ServerAddress != "" {
+	case config.RemoteSigner != nil && config.RemoteSigner.ServerAddress != "":
 		signer, err = rsclient.NewRemoteSignerClientFromConfig(
 			config.RemoteSigner,
 			clientPrivKey,
 			clientLogger,
 		)
-	} else {
-		// Otherwise, use a local signer.
+
+	// Otherwise, use a local signer.
+	default:
 		signer, err = local.LoadOrMakeLocalSigner(config.LocalSignerPath())
 	}
 	if err != nil {
@@ -8,6 +8,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/bft/privval/signer/local"
 	rsclient "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/client"
 	rsserver "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/server"
+	"github.com/gnolang/gno/tm2/pkg/bft/privval/signer/threshold"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
 	"github.com/gnolang/gno/tm2/pkg/log"
@@ -57,6 +58,15 @@ func TestValidateBasic(t *testing.T) {
 
 		assert.Error(t, cfg.ValidateBasic())
 	})
+
+	t.Run("threshold signer config with invalid threshold", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultPrivValidatorConfig()
+		cfg.ThresholdSigner.ServerAddresses = []string{"unix:///tmp/remote_signer.sock"}
+
+		assert.ErrorIs(t, cfg.ValidateBasic(), threshold.ErrInvalidThreshold)
+	})
 }
 
 func TestPathGetters(t *testing.T) {
@@ -127,6 +137,40 @@ func TestNewPrivValidatorFromConfig(t *testing.T) {
 		rss.Stop()
 	})
 
+	t.Run("valid threshold signer", func(t *testing.T) {
+		t.Parallel()
+
+		// Setup Unix socket addresses for the remote signers.
+		unixSocketPath := "test_tm2_threshold_signer"
+		os.MkdirAll(unixSocketPath, 0o755)
+		t.Cleanup(func() {
+			os.Remove(unixSocketPath)
+		})
+
+		cfg := DefaultPrivValidatorConfig()
+		cfg.RootDir = t.TempDir()
+		cfg.ThresholdSigner.Threshold = 2
+
+		// Init the remote signer servers to fetch the public keys on client init.
+		for range 3 {
+			addr := fmt.Sprintf("unix://%s/%s.sock", unixSocketPath, xid.New().String())
+
+			rss, err := rsserver.NewRemoteSignerServer(types.NewMockSigner(), addr, log.NewNoopLogger())
+			require.NotNil(t, rss)
+			require.NoError(t, err)
+			require.NoError(t, rss.Start())
+			defer rss.Stop()
+
+			cfg.ThresholdSigner.ServerAddresses = append(cfg.ThresholdSigner.ServerAddresses, addr)
+		}
+
+		privval, err := NewPrivValidatorFromConfig(cfg, privKey, logger)
+		require.NotNil(t, privval)
+		require.NoError(t, err)
+		assert.IsType(t, &threshold.ThresholdSigner{}, privval.signer)
+		privval.Close()
+	})
+
 	t.Run("invalid authorized keys", func(t *testing.T) {
 		t.Parallel()
 
@@ -35,6 +35,12 @@
 * contribs/gnokms that aims to provide a remote signer server along with a set of backend signers, including
 * one based on gnokey.
 *
+* A threshold signer is provided in tm2/pkg/bft/privval/signer/threshold. It sends each signing request to n
+* remote signer servers, each holding its own key, and aggregates the first t signatures received into a
+* multisignature. The validator public key is then the t-of-n multisig of the servers' public keys, which must
+* be allowed by the Validator.PubKeyTypeURLs consensus param. This way, no single machine holds a key able to
+* sign on behalf of the validator, and up to n-t remote signers can be unavailable without halting it.
+*
 *
 * State
 *
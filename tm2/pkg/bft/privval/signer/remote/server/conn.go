@@ -7,6 +7,7 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	r "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote"
+	"go.uber.org/multierr"
 )
 
 func (rss *RemoteSignerServer) setListener(listener net.Listener) error {
@@ -24,17 +25,46 @@ func (rss *RemoteSignerServer) setListener(listener net.Listener) error {
 	return err
 }
 
-func (rss *RemoteSignerServer) setConnection(conn net.Conn) error {
+// addConnection tracks the given connection so it can be closed when the server stops.
+// It returns false and closes the connection if the server is already stopped.
+func (rss *RemoteSignerServer) addConnection(conn net.Conn) bool {
 	rss.lock.Lock()
 	defer rss.lock.Unlock()
 
-	// If the connection is already set, close it.
-	var err error
-	if rss.conn != nil {
-		err = rss.conn.Close()
+	if !rss.IsRunning() {
+		conn.Close()
+		return false
+	}
+
+	if rss.conns == nil {
+		rss.conns = make(map[net.Conn]struct{})
+	}
+	rss.conns[conn] = struct{}{}
+
+	return true
+}
+
+// removeConnection closes the given connection and stops tracking it.
+func (rss *RemoteSignerServer) removeConnection(conn net.Conn) {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
+
+	if _, ok := rss.conns[conn]; ok {
+		conn.Close()
+		delete(rss.conns, conn)
 	}
+}
+
+// closeConnections closes all the tracked connections.
+func (rss *RemoteSignerServer) closeConnections() error {
+	rss.lock.Lock()
+	defer rss.lock.Unlock()
 
-	rss.conn = conn
+	var err error
+	for conn := range rss.conns {
+		err = multierr.Append(err, conn.Close())
+	}
+	rss.conns = nil
 
 	return err
 }
@@ -59,33 +89,8 @@ func (rss *RemoteSignerServer) serve(listener net.Listener) {
 		}
 		rss.logger.Debug("Accepted new connection", "remote", conn.RemoteAddr())
 
-		// If the connection is a TCP connection, configure and secure it.
-		tcpConn, ok := conn.(*net.TCPConn)
-		if ok {
-			tcpCfg := r.TCPConnConfig{
-				KeepAlivePeriod:  rss.keepAlivePeriod,
-				HandshakeTimeout: rss.responseTimeout * 2, // Double the response timeout for the handshake (send + receive).
-			}
-
-			// Configure and secure the TCP connection then authenticate the client.
-			sconn, err := r.ConfigureTCPConnection(
-				tcpConn,
-				rss.serverPrivKey,
-				rss.authorizedKeys,
-				tcpCfg,
-			)
-			if err != nil {
-				rss.logger.Error("Failed to configure TCP connection", "error", err)
-				conn.Close() // Close the connection if its configuration failed.
-				continue
-			}
-
-			rss.logger.Debug("Configured TCP connection successfully")
-			conn = sconn
-		}
-
-		// Start serving the connection.
-		rss.handleConnection(conn)
+		// Start serving the connection, concurrently with the other clients.
+		go rss.handleConnection(conn)
 	}
 
 	rss.logger.Info("Stop listening",
@@ -96,8 +101,34 @@ func (rss *RemoteSignerServer) serve(listener net.Listener) {
 
 // handleConnection handles the connection with the client.
 func (rss *RemoteSignerServer) handleConnection(conn net.Conn) {
-	rss.setConnection(conn)
-	defer rss.setConnection(nil)
+	// Track the connection until it is closed, so stopping the server closes it.
+	if !rss.addConnection(conn) {
+		return
+	}
+	defer rss.removeConnection(conn)
+
+	// If the connection is a TCP connection, configure and secure it.
+	if tcpConn, ok := conn.(*net.TCPConn); ok {
+		tcpCfg := r.TCPConnConfig{
+			KeepAlivePeriod:  rss.keepAlivePeriod,
+			HandshakeTimeout: rss.responseTimeout * 2, // Double the response timeout for the handshake (send + receive).
+		}
+
+		// Configure and secure the TCP connection then authenticate the client.
+		sconn, err := r.ConfigureTCPConnection(
+			tcpConn,
+			rss.serverPrivKey,
+			rss.authorizedKeys,
+			tcpCfg,
+		)
+		if err != nil {
+			rss.logger.Error("Failed to configure TCP connection", "error", err)
+			return // The connection is closed if its configuration failed.
+		}
+
+		rss.logger.Debug("Configured TCP connection successfully")
+		conn = sconn
+	}
 
 	// Serve will run until the connection is closed or an error occurs while receiving
 	// a request from or sending a response to the client.
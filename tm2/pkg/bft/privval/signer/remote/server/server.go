@@ -16,6 +16,8 @@ import (
 )
 
 // RemoteSignerServer provides a service that forwards requests to a types.Signer.
+// Client connections are served concurrently, so the signer must be safe for
+// concurrent use.
 type RemoteSignerServer struct {
 	// Required config.
 	signer        types.Signer
@@ -32,7 +34,7 @@ type RemoteSignerServer struct {
 
 	// Internal.
 	listener net.Listener
-	conn     net.Conn
+	conns    map[net.Conn]struct{}
 	lock     sync.RWMutex
 	running  atomic.Bool
 }
@@ -80,10 +82,10 @@ func (rss *RemoteSignerServer) Stop() error {
 		return ErrServerAlreadyStopped
 	}
 
-	// Close the listener and conn if any.
+	// Close the listener and conns if any.
 	err := multierr.Combine(
 		rss.setListener(nil),
-		rss.setConnection(nil),
+		rss.closeConnections(),
 	)
 
 	rss.logger.Info("Server stopped")
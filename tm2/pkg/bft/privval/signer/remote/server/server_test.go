@@ -296,4 +296,27 @@ func TestServerConnection(t *testing.T) {
 		assert.ErrorIs(t, err, r.ErrUnauthorizedPubKey)
 		rss.Stop()
 	})
+
+	t.Run("concurrent clients", func(t *testing.T) {
+		t.Parallel()
+
+		unixSocket := testUnixSocket(t)
+
+		rss := newRemoteSignerServer(t, unixSocket, nil)
+		require.NotNil(t, rss)
+		require.NoError(t, rss.Start())
+		defer rss.Stop()
+
+		// Both clients are served while connected at the same time.
+		rsc1 := newRemoteSignerClient(t, unixSocket)
+		require.NotNil(t, rsc1)
+		defer rsc1.Close()
+		rsc2 := newRemoteSignerClient(t, unixSocket)
+		require.NotNil(t, rsc2)
+		defer rsc2.Close()
+
+		require.NoError(t, rsc1.Ping())
+		require.NoError(t, rsc2.Ping())
+		assert.NoError(t, rsc1.Ping())
+	})
 }
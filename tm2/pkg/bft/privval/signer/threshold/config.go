@@ -0,0 +1,141 @@
+package threshold
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	rsclient "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/client"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"go.uber.org/multierr"
+)
+
+// ThresholdSignerConfig defines the configuration options for a ThresholdSigner.
+// This is used to marshal/unmarshal the configuration to/from TOML and configure the signer
+// using the gnoland CLI tool.
+type ThresholdSignerConfig struct {
+	// Minimum number of signatures required out of the remote signers.
+	Threshold int `json:"threshold" toml:"threshold" comment:"Minimum number of signatures required out of the threshold remote signers"`
+
+	// Addresses of the remote signers to dial (UNIX or TCP).
+	ServerAddresses []string `json:"server_addresses" toml:"server_addresses" comment:"Addresses of the threshold remote signers to dial (UNIX or TCP), the other options of the remote signer client apply to each of them. If set, the local and remote signers are disabled. The order of the addresses defines the validator multisig public key"`
+}
+
+// DefaultThresholdSignerConfig returns a default configuration for the ThresholdSigner.
+func DefaultThresholdSignerConfig() *ThresholdSignerConfig {
+	return &ThresholdSignerConfig{
+		Threshold:       0,
+		ServerAddresses: []string{}, // Empty to disable threshold signer by default.
+	}
+}
+
+// TestThresholdSignerConfig returns a configuration for testing the ThresholdSigner.
+func TestThresholdSignerConfig() *ThresholdSignerConfig {
+	return DefaultThresholdSignerConfig()
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *ThresholdSignerConfig) ValidateBasic() error {
+	// Nothing to check if the threshold signer is disabled.
+	if len(cfg.ServerAddresses) == 0 {
+		return nil
+	}
+
+	// Verify the threshold against the number of remote signers.
+	if len(cfg.ServerAddresses) > types.MaxMultisigValidatorKeys {
+		return fmt.Errorf("%w: %d > %d", ErrTooManySigners, len(cfg.ServerAddresses), types.MaxMultisigValidatorKeys)
+	}
+	if cfg.Threshold < 1 || cfg.Threshold > len(cfg.ServerAddresses) {
+		return fmt.Errorf("%w: %d of %d signers", ErrInvalidThreshold, cfg.Threshold, len(cfg.ServerAddresses))
+	}
+
+	// Verify each remote signer is only dialed once.
+	seen := make(map[string]struct{}, len(cfg.ServerAddresses))
+	for _, address := range cfg.ServerAddresses {
+		if _, ok := seen[address]; ok {
+			return fmt.Errorf("%w: %s", ErrDuplicateAddress, address)
+		}
+		seen[address] = struct{}{}
+	}
+
+	return nil
+}
+
+// NewThresholdSignerFromConfig returns a new ThresholdSigner instance based on the configuration.
+// A remote signer client is initialized for each server address using remoteConfig for the
+// other options, or the default remote signer client configuration if nil.
+// The clientPrivKey is only used if the clients connect to the servers using TCP.
+func NewThresholdSignerFromConfig(
+	config *ThresholdSignerConfig,
+	remoteConfig *rsclient.RemoteSignerClientConfig,
+	clientPrivKey ed25519.PrivKeyEd25519,
+	clientLogger *slog.Logger,
+) (*ThresholdSigner, error) {
+	// Validate the configuration before dialing the remote signers.
+	if err := config.ValidateBasic(); err != nil {
+		return nil, err
+	}
+	if len(config.ServerAddresses) == 0 {
+		return nil, ErrNoSigners
+	}
+	if clientLogger == nil {
+		return nil, ErrNilLogger
+	}
+	if remoteConfig == nil {
+		remoteConfig = rsclient.DefaultRemoteSignerClientConfig()
+	}
+
+	var (
+		signers = make([]types.Signer, len(config.ServerAddresses))
+		errs    = make([]error, len(config.ServerAddresses))
+		wg      sync.WaitGroup
+	)
+
+	// Initialize the remote signer clients concurrently since each of them fetches
+	// the public key of its server on init.
+	for i, address := range config.ServerAddresses {
+		clientConfig := *remoteConfig
+		clientConfig.ServerAddress = address
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			client, err := rsclient.NewRemoteSignerClientFromConfig(
+				&clientConfig,
+				clientPrivKey,
+				clientLogger.With("server", address),
+			)
+			if err != nil {
+				errs[i] = fmt.Errorf("%w %s: %w", ErrSignerInitFailed, address, err)
+				return
+			}
+			signers[i] = client
+		}()
+	}
+	wg.Wait()
+
+	// Close the initialized clients if any of them failed.
+	closeSigners := func() {
+		for _, signer := range signers {
+			if signer != nil {
+				signer.Close()
+			}
+		}
+	}
+
+	if err := multierr.Combine(errs...); err != nil {
+		closeSigners()
+		return nil, err
+	}
+
+	signer, err := NewThresholdSigner(config.Threshold, signers, clientLogger)
+	if err != nil {
+		closeSigners()
+		return nil, err
+	}
+
+	return signer, nil
+}
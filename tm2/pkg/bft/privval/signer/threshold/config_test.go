@@ -0,0 +1,235 @@
+package threshold
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	rsclient "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/client"
+	rsserver "github.com/gnolang/gno/tm2/pkg/bft/privval/signer/remote/server"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/rs/xid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	unixSocketPath = "/tmp/test_tm2_threshold_signer"
+	testTimeouts   = 100 * time.Millisecond
+)
+
+func testUnixSocket(t *testing.T) string {
+	t.Helper()
+
+	// Ensure the unix socket path exists.
+	require.NoError(t, os.MkdirAll(unixSocketPath, 0o755))
+
+	// Create a unique unix socket file path.
+	filePath := fmt.Sprintf("%s/%s.sock", unixSocketPath, xid.New().String())
+
+	// Ensure the file is deleted after the test.
+	t.Cleanup(func() {
+		os.Remove(filePath)
+	})
+
+	return fmt.Sprintf("unix://%s", filePath)
+}
+
+func testRemoteSignerClientConfig() *rsclient.RemoteSignerClientConfig {
+	cfg := rsclient.TestRemoteSignerClientConfig()
+	cfg.DialMaxRetries = 3
+	cfg.DialRetryInterval = testTimeouts
+	cfg.DialTimeout = testTimeouts
+	cfg.RequestTimeout = testTimeouts
+
+	return cfg
+}
+
+// startRemoteSignerServers starts n remote signer servers, stopped at the end of the test.
+func startRemoteSignerServers(t *testing.T, n int) ([]*rsserver.RemoteSignerServer, []string) {
+	t.Helper()
+
+	var (
+		servers   = make([]*rsserver.RemoteSignerServer, n)
+		addresses = make([]string, n)
+	)
+
+	for i := range servers {
+		addresses[i] = testUnixSocket(t)
+
+		rss, err := rsserver.NewRemoteSignerServer(
+			types.NewMockSigner(),
+			addresses[i],
+			log.NewNoopLogger(),
+			rsserver.WithResponseTimeout(testTimeouts),
+		)
+		require.NoError(t, err)
+		require.NoError(t, rss.Start())
+		t.Cleanup(func() {
+			if rss.IsRunning() {
+				rss.Stop()
+			}
+		})
+
+		servers[i] = rss
+	}
+
+	return servers, addresses
+}
+
+func TestValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	t.Run("default config", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, DefaultThresholdSignerConfig().ValidateBasic())
+	})
+
+	t.Run("test config", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, TestThresholdSignerConfig().ValidateBasic())
+	})
+
+	t.Run("valid config", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultThresholdSignerConfig()
+		cfg.Threshold = 2
+		cfg.ServerAddresses = []string{"tcp://127.0.0.1:26659", "tcp://127.0.0.2:26659", "unix:///tmp/signer.sock"}
+
+		assert.NoError(t, cfg.ValidateBasic())
+	})
+
+	testTable := []struct {
+		name        string
+		threshold   int
+		addresses   []string
+		expectedErr error
+	}{
+		{"zero threshold", 0, []string{"tcp://127.0.0.1:26659"}, ErrInvalidThreshold},
+		{"threshold above signers", 3, []string{"tcp://127.0.0.1:26659", "tcp://127.0.0.2:26659"}, ErrInvalidThreshold},
+		{"too many signers", 2, make([]string, types.MaxMultisigValidatorKeys+1), ErrTooManySigners},
+		{"duplicate address", 1, []string{"tcp://127.0.0.1:26659", "tcp://127.0.0.1:26659"}, ErrDuplicateAddress},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			cfg := DefaultThresholdSignerConfig()
+			cfg.Threshold = testCase.threshold
+			cfg.ServerAddresses = testCase.addresses
+
+			assert.ErrorIs(t, cfg.ValidateBasic(), testCase.expectedErr)
+		})
+	}
+}
+
+func TestNewThresholdSignerFromConfig(t *testing.T) {
+	t.Parallel()
+
+	var (
+		privKey = ed25519.GenPrivKey()
+		logger  = log.NewNoopLogger()
+	)
+
+	t.Run("no server addresses", func(t *testing.T) {
+		t.Parallel()
+
+		ts, err := NewThresholdSignerFromConfig(DefaultThresholdSignerConfig(), nil, privKey, logger)
+		require.Nil(t, ts)
+		assert.ErrorIs(t, err, ErrNoSigners)
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultThresholdSignerConfig()
+		cfg.ServerAddresses = []string{"tcp://127.0.0.1:26659"}
+
+		ts, err := NewThresholdSignerFromConfig(cfg, nil, privKey, logger)
+		require.Nil(t, ts)
+		assert.ErrorIs(t, err, ErrInvalidThreshold)
+	})
+
+	t.Run("unreachable server", func(t *testing.T) {
+		t.Parallel()
+
+		_, addresses := startRemoteSignerServers(t, 2)
+
+		cfg := DefaultThresholdSignerConfig()
+		cfg.Threshold = 2
+		cfg.ServerAddresses = append(addresses, testUnixSocket(t))
+
+		ts, err := NewThresholdSignerFromConfig(cfg, testRemoteSignerClientConfig(), privKey, logger)
+		require.Nil(t, ts)
+		assert.ErrorIs(t, err, ErrSignerInitFailed)
+	})
+
+	t.Run("2-of-3 remote signers", func(t *testing.T) {
+		t.Parallel()
+
+		servers, addresses := startRemoteSignerServers(t, 3)
+
+		cfg := DefaultThresholdSignerConfig()
+		cfg.Threshold = 2
+		cfg.ServerAddresses = addresses
+
+		ts, err := NewThresholdSignerFromConfig(cfg, testRemoteSignerClientConfig(), privKey, logger)
+		require.NoError(t, err)
+		require.NotNil(t, ts)
+		defer ts.Close()
+
+		// The validator multisig public key is accepted by the consensus params.
+		params := types.DefaultConsensusParams()
+		params.Validator.PubKeyTypeURLs = append(params.Validator.PubKeyTypeURLs, "/tm.PubKeyMultisig")
+		require.NoError(t, types.ValidateConsensusParams(params))
+		require.NoError(t, types.ValidateValidatorPubKey(ts.PubKey()))
+
+		signVote := func(height int64) *types.Vote {
+			t.Helper()
+
+			vote := &types.Vote{
+				Type:             types.PrecommitType,
+				Height:           height,
+				Timestamp:        time.Now().UTC(),
+				ValidatorAddress: ts.PubKey().Address(),
+				BlockID: types.BlockID{
+					Hash: tmhash.Sum([]byte("block_hash")),
+					PartsHeader: types.PartSetHeader{
+						Total: 1,
+						Hash:  tmhash.Sum([]byte("part_set_header_hash")),
+					},
+				},
+			}
+
+			signature, err := ts.Sign(vote.SignBytes("test-chain"))
+			require.NoError(t, err)
+			vote.Signature = signature
+
+			require.NoError(t, vote.ValidateBasicWithParams(params))
+			require.NoError(t, vote.Verify("test-chain", ts.PubKey()))
+
+			return vote
+		}
+
+		// Sign a vote with all the remote signers up.
+		signVote(1)
+
+		// Sign a vote with one of the remote signers down.
+		servers[1].Stop()
+		signVote(2)
+
+		// The threshold can't be reached with two of the remote signers down.
+		servers[2].Stop()
+		signature, err := ts.Sign([]byte("sign bytes"))
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, ErrThresholdNotReached)
+	})
+}
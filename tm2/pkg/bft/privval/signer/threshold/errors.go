@@ -0,0 +1,20 @@
+package threshold
+
+import "errors"
+
+// Errors returned by the threshold signer.
+var (
+	// Init.
+	ErrNoSigners           = errors.New("no signers")
+	ErrInvalidThreshold    = errors.New("invalid threshold")
+	ErrTooManySigners      = errors.New("too many signers")
+	ErrInvalidPubKey       = errors.New("invalid signer public key")
+	ErrDuplicatePubKey     = errors.New("duplicate signer public key")
+	ErrDuplicateAddress    = errors.New("duplicate server address")
+	ErrNilLogger           = errors.New("nil logger")
+	ErrSignerInitFailed    = errors.New("failed to initialize signer")
+	ErrThresholdNotReached = errors.New("threshold not reached")
+
+	// Request.
+	ErrInvalidPartialSignature = errors.New("invalid partial signature")
+)
@@ -0,0 +1,226 @@
+package threshold
+
+import (
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"go.uber.org/multierr"
+)
+
+// ThresholdSigner implements types.Signer by requesting the signature of the same sign
+// bytes from n signers, typically remote signer clients connected to distinct gnokms
+// instances. The signature is the multisignature of the first t signatures received,
+// and can be verified using the t-of-n multisig public key of the signers.
+type ThresholdSigner struct {
+	threshold int
+	signers   []*lockedSigner
+	pubKey    multisig.PubKeyMultisigThreshold
+	logger    *slog.Logger
+}
+
+// lockedSigner serializes the requests sent to a signer, since the remote signer
+// client does not support concurrent requests. At most one request waits for the
+// one in progress, so an unresponsive signer doesn't accumulate pending requests.
+type lockedSigner struct {
+	types.Signer
+	lock  sync.Mutex
+	slots chan struct{} // Request in progress and waiting request.
+}
+
+// newLockedSigner returns a new lockedSigner wrapping the given signer.
+func newLockedSigner(signer types.Signer) *lockedSigner {
+	return &lockedSigner{
+		Signer: signer,
+		slots:  make(chan struct{}, 2),
+	}
+}
+
+// tryAcquire reserves a slot for a request and returns false if the signer already
+// has both a request in progress and a waiting one.
+func (ls *lockedSigner) tryAcquire() bool {
+	select {
+	case ls.slots <- struct{}{}:
+		return true
+	default:
+		return false
+	}
+}
+
+// release frees the slot reserved by tryAcquire.
+func (ls *lockedSigner) release() {
+	<-ls.slots
+}
+
+// partialSignature is the result of a sign request sent to one of the signers.
+type partialSignature struct {
+	index     int
+	signature []byte
+	err       error
+}
+
+// ThresholdSigner type implements types.Signer.
+var _ types.Signer = (*ThresholdSigner)(nil)
+
+// PubKey implements types.Signer.
+func (ts *ThresholdSigner) PubKey() crypto.PubKey {
+	return ts.pubKey
+}
+
+// Sign implements types.Signer.
+func (ts *ThresholdSigner) Sign(signBytes []byte) ([]byte, error) {
+	var (
+		results = make(chan partialSignature, len(ts.signers))
+		done    = make(chan struct{})
+	)
+	defer close(done)
+
+	// Send the sign request to all the signers concurrently.
+	requested := 0
+	for i, signer := range ts.signers {
+		// Skip the signers already busy with previous requests (e.g. unreachable
+		// servers), so they don't delay this request.
+		if !signer.tryAcquire() {
+			ts.logger.Warn("Signer is busy with previous requests, skipping it", "index", i)
+			continue
+		}
+		requested++
+
+		go func() {
+			defer signer.release()
+
+			signer.lock.Lock()
+			defer signer.lock.Unlock()
+
+			// Don't sign if the request completed while waiting for the previous one.
+			select {
+			case <-done:
+				return
+			default:
+			}
+
+			signature, err := signer.Sign(signBytes)
+			if err == nil && !ts.pubKey.PubKeys[i].VerifyBytes(signBytes, signature) {
+				err = ErrInvalidPartialSignature
+			}
+
+			results <- partialSignature{index: i, signature: signature, err: err}
+		}()
+	}
+
+	// Aggregate the first valid signatures until the threshold is reached.
+	var (
+		multiSig = multisig.NewMultisig(len(ts.signers))
+		signed   = 0
+		errs     error
+	)
+
+	for received := 1; received <= requested; received++ {
+		result := <-results
+
+		if result.err != nil {
+			ts.logger.Warn("Signer failed to sign", "index", result.index, "error", result.err)
+			errs = multierr.Append(errs, fmt.Errorf("signer %d: %w", result.index, result.err))
+		} else {
+			multiSig.AddSignature(result.signature, result.index)
+			signed++
+		}
+
+		// The threshold is reached, the remaining signatures are not needed.
+		if signed == ts.threshold {
+			return multiSig.Marshal(), nil
+		}
+
+		// The threshold can't be reached with the remaining signers.
+		if signed+requested-received < ts.threshold {
+			break
+		}
+	}
+
+	err := fmt.Errorf("%w: %d of %d signatures", ErrThresholdNotReached, signed, ts.threshold)
+	if errs != nil {
+		err = fmt.Errorf("%w: %w", err, errs)
+	}
+
+	return nil, err
+}
+
+// Close implements types.Signer.
+func (ts *ThresholdSigner) Close() error {
+	var err error
+
+	for _, signer := range ts.signers {
+		err = multierr.Append(err, signer.Close())
+	}
+
+	return err
+}
+
+// ThresholdSigner type implements fmt.Stringer.
+var _ fmt.Stringer = (*ThresholdSigner)(nil)
+
+// String implements fmt.Stringer.
+func (ts *ThresholdSigner) String() string {
+	return fmt.Sprintf("{Type: ThresholdSigner, Addr: %s, Threshold: %d/%d}",
+		ts.pubKey.Address(),
+		ts.threshold,
+		len(ts.signers),
+	)
+}
+
+// NewThresholdSigner returns a new ThresholdSigner requiring threshold signatures out
+// of the given signers. The order of the signers defines the validator public key.
+func NewThresholdSigner(
+	threshold int,
+	signers []types.Signer,
+	logger *slog.Logger,
+) (*ThresholdSigner, error) {
+	// Check the threshold against the number of signers.
+	switch {
+	case len(signers) == 0:
+		return nil, ErrNoSigners
+	case len(signers) > types.MaxMultisigValidatorKeys:
+		return nil, fmt.Errorf("%w: %d > %d", ErrTooManySigners, len(signers), types.MaxMultisigValidatorKeys)
+	case threshold < 1 || threshold > len(signers):
+		return nil, fmt.Errorf("%w: %d of %d signers", ErrInvalidThreshold, threshold, len(signers))
+	}
+
+	// Check if logger is nil.
+	if logger == nil {
+		return nil, ErrNilLogger
+	}
+
+	// Get the public keys of the signers.
+	ts := &ThresholdSigner{
+		threshold: threshold,
+		signers:   make([]*lockedSigner, len(signers)),
+		logger:    logger,
+	}
+	pubKeys := make([]crypto.PubKey, len(signers))
+
+	for i, signer := range signers {
+		pubKey := signer.PubKey()
+
+		switch pubKey.(type) {
+		case nil, multisig.PubKeyMultisigThreshold:
+			return nil, fmt.Errorf("%w: signer %d", ErrInvalidPubKey, i)
+		}
+
+		// A signer holding a key twice could reach the threshold by itself.
+		for j := range i {
+			if pubKeys[j].Equals(pubKey) {
+				return nil, fmt.Errorf("%w: signers %d and %d", ErrDuplicatePubKey, j, i)
+			}
+		}
+
+		pubKeys[i] = pubKey
+		ts.signers[i] = newLockedSigner(signer)
+	}
+
+	ts.pubKey = multisig.NewPubKeyMultisigThreshold(threshold, pubKeys).(multisig.PubKeyMultisigThreshold)
+
+	return ts, nil
+}
@@ -0,0 +1,205 @@
+package threshold
+
+import (
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingSigner blocks on each sign request until unblock is closed.
+type blockingSigner struct {
+	types.Signer
+	unblock chan struct{}
+}
+
+func (bs *blockingSigner) Sign(signBytes []byte) ([]byte, error) {
+	<-bs.unblock
+	return bs.Signer.Sign(signBytes)
+}
+
+// mismatchSigner returns a public key that does not match its signatures.
+type mismatchSigner struct {
+	types.Signer
+	pubKey crypto.PubKey
+}
+
+func (ms *mismatchSigner) PubKey() crypto.PubKey {
+	return ms.pubKey
+}
+
+func newMockSigners(n int) []types.Signer {
+	signers := make([]types.Signer, n)
+	for i := range signers {
+		signers[i] = types.NewMockSigner()
+	}
+
+	return signers
+}
+
+func newThresholdSigner(t *testing.T, threshold int, signers []types.Signer) *ThresholdSigner {
+	t.Helper()
+
+	ts, err := NewThresholdSigner(threshold, signers, log.NewNoopLogger())
+	require.NoError(t, err)
+	require.NotNil(t, ts)
+
+	return ts
+}
+
+func TestNewThresholdSigner(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid signers", func(t *testing.T) {
+		t.Parallel()
+
+		signers := newMockSigners(3)
+		ts := newThresholdSigner(t, 2, signers)
+
+		// The public key is the 2-of-3 multisig of the signers public keys, in order.
+		pubKey, ok := ts.PubKey().(multisig.PubKeyMultisigThreshold)
+		require.True(t, ok)
+		assert.Equal(t, uint(2), pubKey.K)
+		require.Len(t, pubKey.PubKeys, 3)
+		for i, signer := range signers {
+			assert.Equal(t, signer.PubKey(), pubKey.PubKeys[i])
+		}
+		assert.NoError(t, types.ValidateValidatorPubKey(pubKey))
+		assert.Contains(t, ts.String(), "2/3")
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		t.Parallel()
+
+		nested := newThresholdSigner(t, 1, newMockSigners(2))
+		duplicate := types.NewMockSigner()
+
+		testTable := []struct {
+			name        string
+			threshold   int
+			signers     []types.Signer
+			expectedErr error
+		}{
+			{"no signers", 1, nil, ErrNoSigners},
+			{"zero threshold", 0, newMockSigners(3), ErrInvalidThreshold},
+			{"threshold above signers", 4, newMockSigners(3), ErrInvalidThreshold},
+			{"too many signers", 2, newMockSigners(types.MaxMultisigValidatorKeys + 1), ErrTooManySigners},
+			{"nested multisig", 1, []types.Signer{types.NewMockSigner(), nested}, ErrInvalidPubKey},
+			{"duplicate public key", 1, []types.Signer{duplicate, types.NewMockSigner(), duplicate}, ErrDuplicatePubKey},
+		}
+
+		for _, testCase := range testTable {
+			t.Run(testCase.name, func(t *testing.T) {
+				t.Parallel()
+
+				ts, err := NewThresholdSigner(testCase.threshold, testCase.signers, log.NewNoopLogger())
+				require.Nil(t, ts)
+				assert.ErrorIs(t, err, testCase.expectedErr)
+			})
+		}
+	})
+
+	t.Run("nil logger", func(t *testing.T) {
+		t.Parallel()
+
+		ts, err := NewThresholdSigner(1, newMockSigners(1), nil)
+		require.Nil(t, ts)
+		assert.ErrorIs(t, err, ErrNilLogger)
+	})
+}
+
+func TestThresholdSign(t *testing.T) {
+	t.Parallel()
+
+	signBytes := []byte("sign bytes")
+
+	t.Run("all signers", func(t *testing.T) {
+		t.Parallel()
+
+		ts := newThresholdSigner(t, 3, newMockSigners(3))
+
+		signature, err := ts.Sign(signBytes)
+		require.NoError(t, err)
+		assert.True(t, ts.PubKey().VerifyBytes(signBytes, signature))
+		assert.LessOrEqual(t, len(signature), types.MaxSignatureSizeForPubKey(ts.PubKey()))
+	})
+
+	t.Run("threshold reached with failing signers", func(t *testing.T) {
+		t.Parallel()
+
+		signers := newMockSigners(5)
+		signers[1] = types.NewErroringMockSigner()
+		signers[3] = &mismatchSigner{Signer: types.NewMockSigner(), pubKey: types.NewMockSigner().PubKey()}
+		ts := newThresholdSigner(t, 3, signers)
+
+		signature, err := ts.Sign(signBytes)
+		require.NoError(t, err)
+		assert.True(t, ts.PubKey().VerifyBytes(signBytes, signature))
+	})
+
+	t.Run("threshold not reached", func(t *testing.T) {
+		t.Parallel()
+
+		signers := newMockSigners(3)
+		signers[0] = types.NewErroringMockSigner()
+		signers[2] = &mismatchSigner{Signer: types.NewMockSigner(), pubKey: types.NewMockSigner().PubKey()}
+		ts := newThresholdSigner(t, 2, signers)
+
+		signature, err := ts.Sign(signBytes)
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, ErrThresholdNotReached)
+		assert.ErrorIs(t, err, types.ErrErroringMockSigner)
+		assert.ErrorIs(t, err, ErrInvalidPartialSignature)
+	})
+
+	t.Run("busy signer skipped", func(t *testing.T) {
+		t.Parallel()
+
+		blocking := &blockingSigner{Signer: types.NewMockSigner(), unblock: make(chan struct{})}
+		signers := newMockSigners(3)
+		signers[0] = blocking
+		ts := newThresholdSigner(t, 2, signers)
+
+		// The first request doesn't wait for the blocking signer.
+		signature, err := ts.Sign(signBytes)
+		require.NoError(t, err)
+		require.True(t, ts.PubKey().VerifyBytes(signBytes, signature))
+
+		// The second request waits for the blocking signer to complete the first one.
+		signature, err = ts.Sign(signBytes)
+		require.NoError(t, err)
+		require.True(t, ts.PubKey().VerifyBytes(signBytes, signature))
+
+		// The blocking signer is busy with both previous requests and is skipped,
+		// so a 3-of-3 threshold can't be reached.
+		ts.threshold = 3
+		signature, err = ts.Sign(signBytes)
+		require.Nil(t, signature)
+		assert.ErrorIs(t, err, ErrThresholdNotReached)
+
+		close(blocking.unblock)
+	})
+}
+
+func TestThresholdClose(t *testing.T) {
+	t.Parallel()
+
+	t.Run("valid signers", func(t *testing.T) {
+		t.Parallel()
+
+		assert.NoError(t, newThresholdSigner(t, 2, newMockSigners(3)).Close())
+	})
+
+	t.Run("erroring signer", func(t *testing.T) {
+		t.Parallel()
+
+		signers := newMockSigners(3)
+		signers[1] = types.NewErroringMockSigner()
+
+		assert.ErrorIs(t, newThresholdSigner(t, 2, signers).Close(), types.ErrErroringMockSigner)
+	})
+}
@@ -0,0 +1,48 @@
+package config
+
+import "github.com/gnolang/gno/tm2/pkg/errors"
+
+// MinKeepRecent is the minimum number of recent blocks kept by the pruning,
+// so the node can still handshake with the app and serve its peers.
+const MinKeepRecent = 100
+
+// PruningConfig defines the configuration of the pruning of the old blocks
+// and of their results
+type PruningConfig struct {
+	KeepRecent int64 `json:"keep_recent" toml:"keep_recent" comment:"Number of recent blocks to keep, 0 keeps all the blocks.\n The older blocks, their commits, ABCI responses and tx result indexes are pruned."`
+	KeepEvery  int64 `json:"keep_every" toml:"keep_every" comment:"Keep the blocks at a height multiple of keep_every when pruning, 0 keeps none of them"`
+}
+
+// DefaultPruningConfig returns a default configuration for the pruning,
+// which keeps all the blocks
+func DefaultPruningConfig() *PruningConfig {
+	return &PruningConfig{
+		KeepRecent: 0,
+		KeepEvery:  0,
+	}
+}
+
+// TestPruningConfig returns a configuration for testing the pruning
+func TestPruningConfig() *PruningConfig {
+	return DefaultPruningConfig()
+}
+
+// Enabled returns true if the old blocks are pruned.
+func (cfg *PruningConfig) Enabled() bool {
+	return cfg.KeepRecent > 0
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *PruningConfig) ValidateBasic() error {
+	if cfg.KeepRecent < 0 {
+		return errors.New("keep_recent can't be negative")
+	}
+	if cfg.KeepRecent > 0 && cfg.KeepRecent < MinKeepRecent {
+		return errors.New("keep_recent must be 0 or at least %d", MinKeepRecent)
+	}
+	if cfg.KeepEvery < 0 {
+		return errors.New("keep_every can't be negative")
+	}
+	return nil
+}
@@ -0,0 +1,164 @@
+// Package pruner implements the service pruning the old blocks of the block
+// store, and their results in the state DB, as new blocks are committed.
+package pruner
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/pruner/config"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/store"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/service"
+)
+
+const listenerID = "pruner"
+
+// pruneBatchSize is the number of block results pruned in a single batch.
+const pruneBatchSize = 1000
+
+// Pruner prunes the blocks older than the configured number of recent
+// blocks, with their commits, ABCI responses and tx result indexes, each
+// time a new block is committed
+type Pruner struct {
+	service.BaseService
+
+	config     *config.PruningConfig
+	stateDB    dbm.DB
+	blockStore *store.BlockStore
+	evsw       events.EventSwitch
+
+	height atomic.Int64  // latest committed height
+	pruneC chan struct{} // signals a new committed height
+}
+
+// NewPruner returns a new pruner of the block store and state DB
+func NewPruner(
+	cfg *config.PruningConfig,
+	stateDB dbm.DB,
+	blockStore *store.BlockStore,
+	evsw events.EventSwitch,
+) *Pruner {
+	p := &Pruner{
+		config:     cfg,
+		stateDB:    stateDB,
+		blockStore: blockStore,
+		evsw:       evsw,
+		pruneC:     make(chan struct{}, 1),
+	}
+	p.BaseService = *service.NewBaseService(nil, "Pruner", p)
+
+	return p
+}
+
+func (p *Pruner) OnStart() error {
+	// The listener must not block the consensus, so it only records the
+	// latest height, which is pruned by the prune routine
+	p.evsw.AddListener(listenerID, func(event events.Event) {
+		ev, ok := event.(types.EventNewBlock)
+		if !ok {
+			return
+		}
+
+		p.height.Store(ev.Block.Height)
+		select {
+		case p.pruneC <- struct{}{}:
+		default:
+		}
+	})
+
+	go p.pruneRoutine()
+
+	return nil
+}
+
+func (p *Pruner) OnStop() {
+	p.evsw.RemoveListener(listenerID)
+}
+
+func (p *Pruner) pruneRoutine() {
+	for {
+		select {
+		case <-p.Quit():
+			return
+		case <-p.pruneC:
+			height := p.height.Load()
+
+			pruned, err := p.Prune(height)
+			if err != nil {
+				p.Logger.Error("unable to prune blocks", "height", height, "err", err)
+
+				continue
+			}
+			if pruned > 0 {
+				p.Logger.Debug("pruned blocks", "pruned", pruned, "base", p.blockStore.Base())
+			}
+		}
+	}
+}
+
+// Prune prunes the blocks which are not among the recent blocks kept at the
+// given height, along with their results. It returns the number of pruned
+// blocks.
+func (p *Pruner) Prune(height int64) (int64, error) {
+	if !p.config.Enabled() {
+		return 0, nil
+	}
+
+	retainHeight := height - p.config.KeepRecent + 1
+	base := p.blockStore.Base()
+	if retainHeight <= base {
+		return 0, nil
+	}
+
+	// The results are pruned before the blocks, so they are pruned
+	// again if the pruning is interrupted
+	if err := p.pruneResults(base, retainHeight); err != nil {
+		return 0, err
+	}
+
+	return p.blockStore.PruneBlocks(retainHeight, p.config.KeepEvery)
+}
+
+// pruneResults deletes the ABCI responses and tx result indexes of the
+// blocks in [from, to), except the kept ones
+func (p *Pruner) pruneResults(from, to int64) error {
+	batch := p.stateDB.NewBatch()
+	defer func() {
+		batch.Close()
+	}()
+
+	var pruned int64
+	for height := from; height < to; height++ {
+		if p.config.KeepEvery > 0 && height%p.config.KeepEvery == 0 {
+			continue
+		}
+
+		meta := p.blockStore.LoadBlockMeta(height)
+		if meta == nil {
+			return fmt.Errorf("missing block meta at height %d", height)
+		}
+
+		// The block itself is only needed for its transactions
+		block := &types.Block{Header: meta.Header}
+		if meta.Header.NumTxs > 0 {
+			if block = p.blockStore.LoadBlock(height); block == nil {
+				return fmt.Errorf("missing block at height %d", height)
+			}
+		}
+		sm.PruneBlockResults(p.stateDB, batch, block)
+
+		pruned++
+		if pruned%pruneBatchSize == 0 {
+			batch.WriteSync()
+			batch.Close()
+			batch = p.stateDB.NewBatch()
+		}
+	}
+	batch.WriteSync()
+
+	return nil
+}
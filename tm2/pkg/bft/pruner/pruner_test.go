@@ -0,0 +1,148 @@
+package pruner
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/pruner/config"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/store"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dupTx is included both in a pruned block and in a retained block
+var dupTx = types.Tx("dup")
+
+// makeChain saves the blocks up to the given height in a new block store,
+// along with their results in a new state DB
+func makeChain(t *testing.T, height int64) (dbm.DB, *store.BlockStore) {
+	t.Helper()
+
+	val, _ := types.RandValidator(false, 10)
+	state, err := sm.MakeGenesisState(&types.GenesisDoc{
+		ChainID:     "pruner-test",
+		GenesisTime: tmtime.Now(),
+		Validators: []types.GenesisValidator{
+			{Address: val.Address, PubKey: val.PubKey, Power: val.VotingPower},
+		},
+	})
+	require.NoError(t, err)
+
+	var (
+		stateDB    = memdb.NewMemDB()
+		blockStore = store.NewBlockStore(memdb.NewMemDB())
+	)
+	for h := int64(1); h <= height; h++ {
+		txs := types.Txs{types.Tx(fmt.Sprintf("tx-%d", h))}
+		if h == 10 || h == 200 {
+			txs = append(txs, dupTx)
+		}
+
+		block, parts := state.MakeBlock(h, txs, new(types.Commit), val.Address)
+		blockStore.SaveBlock(block, parts, new(types.Commit))
+
+		responses := sm.NewABCIResponsesFromNum(int64(len(txs)))
+		for i, tx := range txs {
+			responses.DeliverTxs[i] = abci.ResponseDeliverTx{}
+			stateDB.Set(sm.CalcTxResultKey(tx.Hash()), (&sm.TxResultIndex{BlockNum: h, TxIndex: uint32(i)}).Bytes())
+		}
+		sm.SaveABCIResponses(stateDB, h, responses)
+	}
+
+	return stateDB, blockStore
+}
+
+func TestPruner_Prune(t *testing.T) {
+	t.Parallel()
+
+	t.Run("pruning disabled", func(t *testing.T) {
+		t.Parallel()
+
+		stateDB, blockStore := makeChain(t, 150)
+		p := NewPruner(config.DefaultPruningConfig(), stateDB, blockStore, events.NilEventSwitch())
+
+		pruned, err := p.Prune(150)
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+		assert.Equal(t, int64(1), blockStore.Base())
+	})
+
+	t.Run("recent and kept blocks", func(t *testing.T) {
+		t.Parallel()
+
+		stateDB, blockStore := makeChain(t, 250)
+		cfg := &config.PruningConfig{
+			KeepRecent: 100,
+			KeepEvery:  50,
+		}
+		p := NewPruner(cfg, stateDB, blockStore, events.NilEventSwitch())
+
+		// Nothing to prune yet
+		pruned, err := p.Prune(100)
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+
+		// The 150 oldest blocks are pruned, except 50, 100 and 150
+		pruned, err = p.Prune(250)
+		require.NoError(t, err)
+		assert.Equal(t, int64(147), pruned)
+		assert.Equal(t, int64(151), blockStore.Base())
+
+		for _, h := range []int64{1, 10, 149} {
+			assert.Nil(t, blockStore.LoadBlock(h), "height %d", h)
+
+			_, err := sm.LoadABCIResponses(stateDB, h)
+			assert.Error(t, err, "height %d", h)
+
+			_, err = sm.LoadTxResultIndex(stateDB, types.Tx(fmt.Sprintf("tx-%d", h)).Hash())
+			assert.Error(t, err, "height %d", h)
+		}
+		for _, h := range []int64{50, 100, 150, 151, 250} {
+			assert.NotNil(t, blockStore.LoadBlock(h), "height %d", h)
+
+			_, err := sm.LoadABCIResponses(stateDB, h)
+			assert.NoError(t, err, "height %d", h)
+
+			_, err = sm.LoadTxResultIndex(stateDB, types.Tx(fmt.Sprintf("tx-%d", h)).Hash())
+			assert.NoError(t, err, "height %d", h)
+		}
+
+		// The result index of a transaction included again later is kept
+		index, err := sm.LoadTxResultIndex(stateDB, dupTx.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, int64(200), index.BlockNum)
+
+		// Pruning again is a no-op
+		pruned, err = p.Prune(250)
+		require.NoError(t, err)
+		assert.Zero(t, pruned)
+	})
+}
+
+func TestPruner_NewBlock(t *testing.T) {
+	t.Parallel()
+
+	stateDB, blockStore := makeChain(t, 120)
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	defer evsw.Stop()
+
+	p := NewPruner(&config.PruningConfig{KeepRecent: 100}, stateDB, blockStore, evsw)
+	require.NoError(t, p.Start())
+	defer p.Stop()
+
+	evsw.FireEvent(types.EventNewBlock{Block: blockStore.LoadBlock(120)})
+
+	require.Eventually(t, func() bool {
+		return blockStore.Base() == 21
+	}, 5*time.Second, 10*time.Millisecond)
+}
@@ -457,6 +457,21 @@ func TestRPCBatch_Endpoints(t *testing.T) {
 				return castResult
 			},
 		},
+		{
+			txSearchMethod,
+			&ctypes.ResultTxSearch{
+				TotalCount: 1,
+			},
+			func(batch *RPCBatch) {
+				require.NoError(t, batch.TxSearch("tx.height = 10", 1, 30, "asc"))
+			},
+			func(result any) any {
+				castResult, ok := result.(*ctypes.ResultTxSearch)
+				require.True(t, ok)
+
+				return castResult
+			},
+		},
 		{
 			validatorsMethod,
 			&ctypes.ResultValidators{
@@ -134,3 +134,7 @@ func (c *Local) Validators(_ context.Context, height *int64) (*ctypes.ResultVali
 func (c *Local) Tx(_ context.Context, hash []byte) (*ctypes.ResultTx, error) {
 	return core.Tx(c.ctx, hash)
 }
+
+func (c *Local) TxSearch(_ context.Context, query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	return core.TxSearch(c.ctx, query, page, perPage, orderBy)
+}
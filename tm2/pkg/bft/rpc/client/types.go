@@ -89,4 +89,5 @@ type MempoolClient interface {
 
 type TxClient interface {
 	Tx(ctx context.Context, hash []byte) (*ctypes.ResultTx, error)
+	TxSearch(ctx context.Context, query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error)
 }
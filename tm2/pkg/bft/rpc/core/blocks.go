@@ -85,6 +85,10 @@ func BlockchainInfo(ctx *rpctypes.Context, minHeight, maxHeight int64) (*ctypes.
 	blockMetas := []*types.BlockMeta{}
 	for height := maxHeight; height >= minHeight; height-- {
 		blockMeta := blockStore.LoadBlockMeta(height)
+		if blockMeta == nil {
+			// The block was pruned
+			continue
+		}
 		blockMetas = append(blockMetas, blockMeta)
 	}
 
@@ -242,6 +246,9 @@ func Block(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlock, error)
 	}
 
 	blockMeta := blockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, errPrunedHeight(height)
+	}
 	block := blockStore.LoadBlock(height)
 	return &ctypes.ResultBlock{BlockMeta: blockMeta, Block: block}, nil
 }
@@ -332,7 +339,11 @@ func Commit(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultCommit, erro
 		return nil, err
 	}
 
-	header := blockStore.LoadBlockMeta(height).Header
+	blockMeta := blockStore.LoadBlockMeta(height)
+	if blockMeta == nil {
+		return nil, errPrunedHeight(height)
+	}
+	header := blockMeta.Header
 
 	// If the next block has not been committed yet,
 	// use a non-canonical commit
@@ -407,6 +418,9 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 
 	results, err := sm.LoadABCIResponses(stateDB, height)
 	if err != nil {
+		if height > 0 && height < blockStore.Base() {
+			return nil, errPrunedHeight(height)
+		}
 		return nil, err
 	}
 
@@ -417,6 +431,16 @@ func BlockResults(ctx *rpctypes.Context, heightPtr *int64) (*ctypes.ResultBlockR
 	return res, nil
 }
 
+// errPrunedHeight returns the error of a request for the block, or the
+// results, at a height which was pruned from the node.
+func errPrunedHeight(height int64) error {
+	return fmt.Errorf(
+		"height %d is not available, it has been pruned; lowest retained height is %d",
+		height,
+		blockStore.Base(),
+	)
+}
+
 func getHeight(currentHeight int64, heightPtr *int64) (int64, error) {
 	return getHeightWithMin(currentHeight, heightPtr, 1)
 }
@@ -4,6 +4,11 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -89,6 +94,81 @@ func TestGetHeight(t *testing.T) {
 	}
 }
 
+func TestPrunedHeights(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	var (
+		base   = int64(100)
+		height = int64(200)
+		kept   = int64(50) // kept by the pruning
+
+		retained = func(h int64) bool {
+			return h == kept || (h >= base && h <= height)
+		}
+	)
+
+	SetLogger(log.NewNoopLogger())
+
+	sdb := memdb.NewMemDB()
+	state.SaveABCIResponses(sdb, kept, state.NewABCIResponsesFromNum(0))
+	SetStateDB(sdb)
+
+	SetBlockStore(&mockBlockStore{
+		baseFn: func() int64 {
+			return base
+		},
+		heightFn: func() int64 {
+			return height
+		},
+		loadBlockMetaFn: func(h int64) *types.BlockMeta {
+			if !retained(h) {
+				return nil
+			}
+
+			return &types.BlockMeta{Header: types.Header{Height: h}}
+		},
+		loadBlockFn: func(h int64) *types.Block {
+			if !retained(h) {
+				return nil
+			}
+
+			return &types.Block{Header: types.Header{Height: h}}
+		},
+		loadBlockCommitFn: func(h int64) *types.Commit {
+			return &types.Commit{}
+		},
+	})
+
+	const expectedErr = "height 10 is not available, it has been pruned; lowest retained height is 100"
+
+	_, err := Block(nil, int64Ptr(10))
+	assert.EqualError(t, err, expectedErr)
+
+	_, err = Commit(nil, int64Ptr(10))
+	assert.EqualError(t, err, expectedErr)
+
+	_, err = BlockResults(nil, int64Ptr(10))
+	assert.EqualError(t, err, expectedErr)
+
+	// The kept blocks are still available
+	block, err := Block(nil, int64Ptr(kept))
+	require.NoError(t, err)
+	assert.Equal(t, kept, block.Block.Height)
+
+	_, err = Commit(nil, int64Ptr(kept))
+	require.NoError(t, err)
+
+	results, err := BlockResults(nil, int64Ptr(kept))
+	require.NoError(t, err)
+	assert.Equal(t, kept, results.Height)
+
+	// The pruned blocks are skipped
+	info, err := BlockchainInfo(nil, 41, 60)
+	require.NoError(t, err)
+	require.Len(t, info.BlockMetas, 1)
+	assert.Equal(t, kept, info.BlockMetas[0].Header.Height)
+}
+
 func int64Ptr(v int64) *int64 {
 	return &v
 }
@@ -0,0 +1,331 @@
+package core
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	rpccfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types/query"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/random"
+	"github.com/gnolang/gno/tm2/pkg/service"
+)
+
+// Subscribe for events via WebSocket.
+//
+// The events matching the query are pushed to the WebSocket connection, as
+// responses with the same ID as the subscription request. See the
+// documentation of the tm2/pkg/bft/types/query package for the syntax of
+// queries. Some examples:
+//
+//	tm.event = 'NewBlock'
+//	tm.event = 'Tx' AND tx.hash = 'ABCD...'
+//	tm.event = 'Tx' AND event.pkg_path = 'gno.land/r/demo/foo' AND event.type = 'Transfer'
+//
+// If the client does not read its events fast enough, the subscription is
+// canceled, and an error response is sent with the ID of the subscription.
+// The number of subscriptions per connection, and of connections with
+// subscriptions, is limited by the RPC configuration.
+//
+// ```shell
+// websocat ws://localhost:26657/websocket
+// > { "jsonrpc": "2.0", "method": "subscribe", "params": ["tm.event='NewBlock'"], "id": 1 }
+// ```
+//
+// > The above command returns JSON structured like this, followed by the
+// > events:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {},
+//		"id": 1,
+//		"jsonrpc": "2.0"
+//	}
+//
+//	{
+//		"error": "",
+//		"result": {
+//			"query": "tm.event = 'NewBlock'",
+//			"event": {
+//				"@type": "/tm.EventNewBlock",
+//				"block": { ... },
+//				...
+//			}
+//		},
+//		"id": 1,
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description |
+// |-----------+--------+---------+----------+-------------|
+// | query     | string | ""      | true     | Query       |
+func Subscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultSubscribe, error) {
+	q, err := parseSubscriptionQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if err := gEventHub.subscribe(ctx.WSConn, ctx.JSONReq.ID, q); err != nil {
+		return nil, err
+	}
+	return &ctypes.ResultSubscribe{}, nil
+}
+
+// Unsubscribe from events matching the query, via WebSocket.
+//
+// ```shell
+// websocat ws://localhost:26657/websocket
+// > { "jsonrpc": "2.0", "method": "unsubscribe", "params": ["tm.event='NewBlock'"], "id": 2 }
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {},
+//		"id": 2,
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description |
+// |-----------+--------+---------+----------+-------------|
+// | query     | string | ""      | true     | Query       |
+func Unsubscribe(ctx *rpctypes.Context, query string) (*ctypes.ResultUnsubscribe, error) {
+	q, err := parseSubscriptionQuery(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	if !gEventHub.unsubscribe(ctx.WSConn, q.String()) {
+		return nil, fmt.Errorf("not subscribed to %q", q.String())
+	}
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+// Unsubscribe from all events, via WebSocket.
+//
+// ```shell
+// websocat ws://localhost:26657/websocket
+// > { "jsonrpc": "2.0", "method": "unsubscribe_all", "params": [], "id": 3 }
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {},
+//		"id": 3,
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+func UnsubscribeAll(ctx *rpctypes.Context) (*ctypes.ResultUnsubscribe, error) {
+	if ctx.WSConn == nil {
+		return nil, errors.New("subscriptions are only available over WebSocket")
+	}
+	gEventHub.unsubscribeAll(ctx.WSConn)
+	return &ctypes.ResultUnsubscribe{}, nil
+}
+
+func parseSubscriptionQuery(ctx *rpctypes.Context, s string) (*query.Query, error) {
+	if ctx.WSConn == nil {
+		return nil, errors.New("subscriptions are only available over WebSocket")
+	}
+	return query.Parse(s)
+}
+
+// ----------------------------------------
+// eventHub
+
+// eventHub dispatches the events of the EventSwitch to the subscriptions of
+// the WebSocket connections.
+type eventHub struct {
+	service.BaseService
+	evsw       events.EventSwitch
+	listenerID string
+
+	mtx     sync.Mutex
+	clients map[rpctypes.WSRPCConnection]map[string]*subscription // conn -> query -> subscription
+}
+
+type subscription struct {
+	conn   rpctypes.WSRPCConnection
+	id     rpctypes.JSONRPCID // of the subscribe request
+	query  *query.Query
+	events chan events.Event
+	done   chan struct{} // closed when the subscription is removed
+}
+
+func newEventHub(evsw events.EventSwitch) *eventHub {
+	h := &eventHub{
+		evsw:       evsw,
+		listenerID: fmt.Sprintf("eventHub#%v", random.RandStr(6)),
+		clients:    make(map[rpctypes.WSRPCConnection]map[string]*subscription),
+	}
+	h.BaseService = *service.NewBaseService(nil, "eventHub", h)
+	return h
+}
+
+func (h *eventHub) OnStart() error {
+	h.evsw.AddListener(h.listenerID, h.fireEvent)
+	return nil
+}
+
+func (h *eventHub) OnStop() {
+	h.evsw.RemoveListener(h.listenerID)
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	for conn := range h.clients {
+		h.removeClient(conn)
+	}
+}
+
+func (h *eventHub) subscribe(conn rpctypes.WSRPCConnection, id rpctypes.JSONRPCID, q *query.Query) error {
+	// Obtain the context outside of the lock, to not race with the
+	// connection initializing it.
+	connCtx := conn.Context()
+
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if !h.IsRunning() {
+		return errors.New("event subscriptions are not available")
+	}
+	subs := h.clients[conn]
+	if subs == nil {
+		if limit := config.MaxSubscriptionClients; limit > 0 && len(h.clients) >= limit {
+			return fmt.Errorf("max_subscription_clients %d reached", limit)
+		}
+		subs = make(map[string]*subscription)
+	}
+	if limit := config.MaxSubscriptionsPerClient; limit > 0 && len(subs) >= limit {
+		return fmt.Errorf("max_subscriptions_per_client %d reached", limit)
+	}
+	key := q.String()
+	if _, ok := subs[key]; ok {
+		return fmt.Errorf("already subscribed to %q", key)
+	}
+
+	bufferSize := config.SubscriptionBufferSize
+	if bufferSize <= 0 {
+		bufferSize = rpccfg.DefaultSubscriptionBufferSize
+	}
+	sub := &subscription{
+		conn:   conn,
+		id:     id,
+		query:  q,
+		events: make(chan events.Event, bufferSize),
+		done:   make(chan struct{}),
+	}
+	subs[key] = sub
+	h.clients[conn] = subs
+	go h.sendRoutine(connCtx, sub)
+	return nil
+}
+
+func (h *eventHub) unsubscribe(conn rpctypes.WSRPCConnection, key string) bool {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	sub, ok := h.clients[conn][key]
+	if ok {
+		h.remove(sub)
+	}
+	return ok
+}
+
+func (h *eventHub) unsubscribeAll(conn rpctypes.WSRPCConnection) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	h.removeClient(conn)
+}
+
+// removeClient removes the subscriptions of conn. h.mtx must be held.
+func (h *eventHub) removeClient(conn rpctypes.WSRPCConnection) {
+	for _, sub := range h.clients[conn] {
+		h.remove(sub)
+	}
+}
+
+// remove removes sub. h.mtx must be held.
+func (h *eventHub) remove(sub *subscription) {
+	subs := h.clients[sub.conn]
+	delete(subs, sub.query.String())
+	if len(subs) == 0 {
+		delete(h.clients, sub.conn)
+	}
+	close(sub.done)
+}
+
+// fireEvent is called synchronously by the EventSwitch, so it must not block:
+// the events are buffered for each subscription, and written to the
+// connections by their sendRoutine.
+func (h *eventHub) fireEvent(ev events.Event) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+
+	if len(h.clients) == 0 {
+		return
+	}
+	fields, ok := query.EventFields(ev)
+	if !ok {
+		return
+	}
+	for _, subs := range h.clients {
+		for _, sub := range subs {
+			if !sub.query.Matches(fields) {
+				continue
+			}
+			select {
+			case sub.events <- ev:
+			default:
+				// The client does not read its events fast enough.
+				h.remove(sub)
+				sub.conn.TryWriteRPCResponses(rpctypes.RPCResponses{
+					rpctypes.RPCInternalError(sub.id, errors.New(
+						"subscription to %q was canceled: client is not reading events fast enough",
+						sub.query.String())),
+				})
+			}
+		}
+	}
+}
+
+// sendRoutine writes the events of sub to its connection, until sub is
+// removed or the connection is closed.
+func (h *eventHub) sendRoutine(connCtx context.Context, sub *subscription) {
+	for {
+		select {
+		case ev := <-sub.events:
+			sub.conn.WriteRPCResponses(rpctypes.RPCResponses{
+				rpctypes.NewRPCSuccessResponse(sub.id, &ctypes.ResultEvent{
+					Query: sub.query.String(),
+					Event: ev,
+				}),
+			})
+		case <-sub.done:
+			return
+		case <-connCtx.Done():
+			h.unsubscribeAll(sub.conn)
+			return
+		}
+	}
+}
@@ -0,0 +1,237 @@
+package core
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	rpccfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type mockWSConn struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	writes chan rpctypes.RPCResponses
+
+	mtx       sync.Mutex
+	tryWrites []rpctypes.RPCResponses
+}
+
+func newMockWSConn(capacity int) *mockWSConn {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &mockWSConn{
+		ctx:    ctx,
+		cancel: cancel,
+		writes: make(chan rpctypes.RPCResponses, capacity),
+	}
+}
+
+func (c *mockWSConn) GetRemoteAddr() string { return "mock" }
+
+func (c *mockWSConn) WriteRPCResponses(resp rpctypes.RPCResponses) {
+	select {
+	case c.writes <- resp:
+	case <-c.ctx.Done():
+	}
+}
+
+func (c *mockWSConn) TryWriteRPCResponses(resp rpctypes.RPCResponses) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.tryWrites = append(c.tryWrites, resp)
+	return true
+}
+
+func (c *mockWSConn) Context() context.Context { return c.ctx }
+
+func (c *mockWSConn) rpcContext(id int) *rpctypes.Context {
+	return &rpctypes.Context{
+		JSONReq: &rpctypes.RPCRequest{ID: rpctypes.JSONRPCIntID(id)},
+		WSConn:  c,
+	}
+}
+
+// setupEventHub sets the GLOBAL event hub, listening on the returned
+// EventSwitch.
+func setupEventHub(t *testing.T, cfg *rpccfg.RPCConfig) events.EventSwitch {
+	t.Helper()
+
+	evsw := events.NewEventSwitch()
+	require.NoError(t, evsw.Start())
+	SetConfig(*cfg)
+	gEventHub = newEventHub(evsw)
+	require.NoError(t, gEventHub.Start())
+	t.Cleanup(func() {
+		gEventHub.Stop()
+		evsw.Stop()
+	})
+	return evsw
+}
+
+func numSubscriptions() int {
+	gEventHub.mtx.Lock()
+	defer gEventHub.mtx.Unlock()
+
+	n := 0
+	for _, subs := range gEventHub.clients {
+		n += len(subs)
+	}
+	return n
+}
+
+func newBlockEvent(height int64) types.EventNewBlock {
+	return types.EventNewBlock{Block: &types.Block{Header: types.Header{Height: height}}}
+}
+
+func TestSubscribe(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+
+	t.Run("matching events are pushed", func(t *testing.T) {
+		evsw := setupEventHub(t, rpccfg.DefaultRPCConfig())
+		conn := newMockWSConn(10)
+
+		_, err := Subscribe(conn.rpcContext(1), "tm.event = 'Tx' AND tx.height > 1")
+		require.NoError(t, err)
+		_, err = Subscribe(conn.rpcContext(2), "tm.event='NewBlock'")
+		require.NoError(t, err)
+
+		evsw.FireEvent(newBlockEvent(1))
+		evsw.FireEvent(types.EventTx{Result: types.TxResult{Height: 1, Tx: types.Tx("a")}})
+		evsw.FireEvent(types.EventTx{Result: types.TxResult{Height: 2, Tx: types.Tx("b")}})
+		evsw.FireEvent(types.EventVote{})
+
+		received := map[rpctypes.JSONRPCID]ctypes.ResultEvent{}
+		for range 2 {
+			select {
+			case resps := <-conn.writes:
+				require.Len(t, resps, 1)
+				require.Nil(t, resps[0].Error)
+				var res ctypes.ResultEvent
+				require.NoError(t, amino.UnmarshalJSON(resps[0].Result, &res))
+				received[resps[0].ID] = res
+			case <-time.After(5 * time.Second):
+				t.Fatal("timed out waiting for events")
+			}
+		}
+
+		txRes := received[rpctypes.JSONRPCIntID(1)]
+		assert.Equal(t, "tm.event = 'Tx' AND tx.height > 1", txRes.Query)
+		require.IsType(t, types.EventTx{}, txRes.Event)
+		assert.Equal(t, types.Tx("b"), txRes.Event.(types.EventTx).Result.Tx)
+
+		blockRes := received[rpctypes.JSONRPCIntID(2)]
+		assert.Equal(t, "tm.event = 'NewBlock'", blockRes.Query)
+		require.IsType(t, types.EventNewBlock{}, blockRes.Event)
+		assert.Equal(t, int64(1), blockRes.Event.(types.EventNewBlock).Block.Height)
+
+		select {
+		case resps := <-conn.writes:
+			t.Fatalf("unexpected event: %v", resps)
+		case <-time.After(100 * time.Millisecond):
+		}
+	})
+
+	t.Run("unsubscribe", func(t *testing.T) {
+		setupEventHub(t, rpccfg.DefaultRPCConfig())
+		conn := newMockWSConn(10)
+
+		_, err := Subscribe(conn.rpcContext(1), "tm.event = 'NewBlock'")
+		require.NoError(t, err)
+		_, err = Subscribe(conn.rpcContext(2), "tm.event = 'Tx'")
+		require.NoError(t, err)
+
+		// The query is matched by its normalized form.
+		_, err = Unsubscribe(conn.rpcContext(3), "tm.event='NewBlock'")
+		require.NoError(t, err)
+		assert.Equal(t, 1, numSubscriptions())
+		_, err = Unsubscribe(conn.rpcContext(4), "tm.event = 'NewBlock'")
+		assert.ErrorContains(t, err, "not subscribed")
+
+		_, err = UnsubscribeAll(conn.rpcContext(5))
+		require.NoError(t, err)
+		assert.Equal(t, 0, numSubscriptions())
+	})
+
+	t.Run("closed connection", func(t *testing.T) {
+		setupEventHub(t, rpccfg.DefaultRPCConfig())
+		conn := newMockWSConn(10)
+
+		_, err := Subscribe(conn.rpcContext(1), "tm.event = 'NewBlock'")
+		require.NoError(t, err)
+		conn.cancel()
+
+		assert.Eventually(t, func() bool {
+			return numSubscriptions() == 0
+		}, 5*time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("limits", func(t *testing.T) {
+		cfg := rpccfg.DefaultRPCConfig()
+		cfg.MaxSubscriptionClients = 1
+		cfg.MaxSubscriptionsPerClient = 2
+		setupEventHub(t, cfg)
+		conn := newMockWSConn(10)
+
+		_, err := Subscribe(conn.rpcContext(1), "tm.event = 'NewBlock'")
+		require.NoError(t, err)
+		_, err = Subscribe(conn.rpcContext(2), "tm.event = 'NewBlock'")
+		assert.ErrorContains(t, err, "already subscribed")
+		_, err = Subscribe(conn.rpcContext(3), "tm.event = 'Tx'")
+		require.NoError(t, err)
+		_, err = Subscribe(conn.rpcContext(4), "tm.event = 'NewBlockHeader'")
+		assert.ErrorContains(t, err, "max_subscriptions_per_client")
+
+		_, err = Subscribe(newMockWSConn(10).rpcContext(1), "tm.event = 'NewBlock'")
+		assert.ErrorContains(t, err, "max_subscription_clients")
+	})
+
+	t.Run("slow client", func(t *testing.T) {
+		cfg := rpccfg.DefaultRPCConfig()
+		cfg.SubscriptionBufferSize = 1
+		evsw := setupEventHub(t, cfg)
+		conn := newMockWSConn(0) // never written to
+
+		_, err := Subscribe(conn.rpcContext(1), "tm.event = 'NewBlock'")
+		require.NoError(t, err)
+
+		// The first event is blocked in WriteRPCResponses, the second one is
+		// buffered: the third one cancels the subscription.
+		for i := range 3 {
+			evsw.FireEvent(newBlockEvent(int64(i + 1)))
+			time.Sleep(50 * time.Millisecond)
+		}
+		assert.Equal(t, 0, numSubscriptions())
+
+		conn.mtx.Lock()
+		defer conn.mtx.Unlock()
+		require.Len(t, conn.tryWrites, 1)
+		resp := conn.tryWrites[0][0]
+		assert.Equal(t, rpctypes.JSONRPCIntID(1), resp.ID)
+		require.NotNil(t, resp.Error)
+		assert.Contains(t, resp.Error.Data, "not reading events fast enough")
+		conn.cancel()
+	})
+
+	t.Run("invalid query", func(t *testing.T) {
+		setupEventHub(t, rpccfg.DefaultRPCConfig())
+
+		_, err := Subscribe(newMockWSConn(10).rpcContext(1), "tm.event = 'Unknown'")
+		assert.ErrorContains(t, err, "unknown event type")
+	})
+
+	t.Run("not over websocket", func(t *testing.T) {
+		setupEventHub(t, rpccfg.DefaultRPCConfig())
+
+		_, err := Subscribe(&rpctypes.Context{}, "tm.event = 'NewBlock'")
+		assert.ErrorContains(t, err, "only available over WebSocket")
+	})
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/events"
 	"github.com/gnolang/gno/tm2/pkg/random"
 	"github.com/gnolang/gno/tm2/pkg/service"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // -----------------------------------------------------------------------------
@@ -78,7 +81,7 @@ import (
 // |-----------+------+---------+----------+-----------------|
 // | tx        | Tx   | nil     | true     | The transaction |
 func BroadcastTxAsync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
-	err := mempool.CheckTx(tx, nil)
+	err := checkTx(ctx, tx, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -144,7 +147,7 @@ func BroadcastTxAsync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadca
 // | tx        | Tx   | nil     | true     | The transaction |
 func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTx, error) {
 	resCh := make(chan abci.Response, 1)
-	err := mempool.CheckTx(tx, func(res abci.Response) {
+	err := checkTx(ctx, tx, func(res abci.Response) {
 		resCh <- res
 	})
 	if err != nil {
@@ -227,7 +230,7 @@ func BroadcastTxSync(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcas
 func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadcastTxCommit, error) {
 	// Broadcast tx and wait for CheckTx result
 	checkTxResCh := make(chan abci.Response, 1)
-	err := mempool.CheckTx(tx, func(res abci.Response) {
+	err := checkTx(ctx, tx, func(res abci.Response) {
 		checkTxResCh <- res
 	})
 	if err != nil {
@@ -245,10 +248,15 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 	}
 
 	// Wait for the tx to be included in a block or timeout.
+	_, span := traces.Tracer().Start(ctx.Context(), "rpc/WaitTxCommit")
 	txRes, err := gTxDispatcher.getTxResult(tx, nil)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+
 		return nil, err
 	}
+	span.End()
 	return &ctypes.ResultBroadcastTxCommit{
 		CheckTx:   checkTxRes,
 		DeliverTx: txRes.Response,
@@ -257,6 +265,22 @@ func BroadcastTxCommit(ctx *rpctypes.Context, tx types.Tx) (*ctypes.ResultBroadc
 	}, nil
 }
 
+// checkTx checks the tx in the mempool, within a trace span of the request
+func checkTx(ctx *rpctypes.Context, tx types.Tx, cb func(abci.Response)) error {
+	txHash := traces.TxHash(tx.Hash())
+	trace.SpanFromContext(ctx.Context()).SetAttributes(txHash)
+
+	_, span := traces.Tracer().Start(ctx.Context(), "mempool/CheckTx", trace.WithAttributes(txHash))
+	defer span.End()
+
+	err := mempool.CheckTx(tx, cb)
+	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
+
 // Get unconfirmed transactions (maximum ?limit entries) including their number.
 //
 // ```shell
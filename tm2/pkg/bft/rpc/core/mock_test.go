@@ -3,6 +3,7 @@ package core
 import "github.com/gnolang/gno/tm2/pkg/bft/types"
 
 type (
+	baseDelegate            func() int64
 	heightDelegate          func() int64
 	loadBlockMetaDelegate   func(int64) *types.BlockMeta
 	loadBlockDelegate       func(int64) *types.Block
@@ -14,6 +15,7 @@ type (
 )
 
 type mockBlockStore struct {
+	baseFn            baseDelegate
 	heightFn          heightDelegate
 	loadBlockMetaFn   loadBlockMetaDelegate
 	loadBlockFn       loadBlockDelegate
@@ -23,6 +25,14 @@ type mockBlockStore struct {
 	saveBlockFn       saveBlockDelegate
 }
 
+func (m *mockBlockStore) Base() int64 {
+	if m.baseFn != nil {
+		return m.baseFn()
+	}
+
+	return 0
+}
+
 func (m *mockBlockStore) Height() int64 {
 	if m.heightFn != nil {
 		return m.heightFn()
@@ -10,6 +10,7 @@ import (
 	mempl "github.com/gnolang/gno/tm2/pkg/bft/mempool"
 	cfg "github.com/gnolang/gno/tm2/pkg/bft/rpc/config"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
@@ -66,7 +67,9 @@ var (
 	genDoc        *types.GenesisDoc // cache the genesis structure
 	evsw          events.EventSwitch
 	gTxDispatcher *txDispatcher
+	gEventHub     *eventHub
 	mempool       mempl.Mempool
+	txEventStore  eventstore.TxEventStore
 	getFastSync   func() bool // avoids dependency on consensus pkg
 
 	logger *slog.Logger
@@ -86,6 +89,10 @@ func SetMempool(mem mempl.Mempool) {
 	mempool = mem
 }
 
+func SetTxEventStore(es eventstore.TxEventStore) {
+	txEventStore = es
+}
+
 func SetConsensusState(cs Consensus) {
 	consensusState = cs
 }
@@ -121,10 +128,12 @@ func SetLogger(l *slog.Logger) {
 func SetEventSwitch(sw events.EventSwitch) {
 	evsw = sw
 	gTxDispatcher = newTxDispatcher(evsw)
+	gEventHub = newEventHub(evsw)
 }
 
 func Start() {
 	gTxDispatcher.Start()
+	gEventHub.Start()
 }
 
 // SetConfig sets an RPCConfig.
@@ -66,6 +66,10 @@ import (
 //	  		"latest_app_hash": "0000000000000000",
 //	  		"latest_block_height": "18",
 //	  		"latest_block_time": "2018-09-17T11:42:19.149920551Z",
+//	  		"earliest_block_hash": "790BA84C3545FCCC49A5C629CEE6EA58A6E875C3862175BDC11EE7AF54703501",
+//	  		"earliest_app_hash": "",
+//	  		"earliest_block_height": "1",
+//	  		"earliest_block_time": "2018-09-17T11:40:01.011131235Z",
 //	  		"catching_up": false
 //	  	},
 //	  	"validator_info": {
@@ -100,8 +104,12 @@ func Status(ctx *rpctypes.Context, heightGtePtr *int64) (*ctypes.ResultStatus, e
 		latestAppHash       []byte
 		latestBlockTimeNano int64
 	)
+	// The latest block of a block store restored by the state sync is
+	// missing, until the next block is synced
 	if latestHeight != 0 {
 		latestBlockMeta = blockStore.LoadBlockMeta(latestHeight)
+	}
+	if latestBlockMeta != nil {
 		latestBlockHash = latestBlockMeta.BlockID.Hash
 		latestAppHash = latestBlockMeta.Header.AppHash
 		latestBlockTimeNano = latestBlockMeta.Header.Time.UnixNano()
@@ -109,6 +117,21 @@ func Status(ctx *rpctypes.Context, heightGtePtr *int64) (*ctypes.ResultStatus, e
 
 	latestBlockTime := time.Unix(0, latestBlockTimeNano)
 
+	// The blocks below the base of the block store were pruned
+	var (
+		earliestHeight        = blockStore.Base()
+		earliestBlockHash     []byte
+		earliestAppHash       []byte
+		earliestBlockTimeNano int64
+	)
+	if earliestBlockMeta := blockStore.LoadBlockMeta(earliestHeight); earliestBlockMeta != nil {
+		earliestBlockHash = earliestBlockMeta.BlockID.Hash
+		earliestAppHash = earliestBlockMeta.Header.AppHash
+		earliestBlockTimeNano = earliestBlockMeta.Header.Time.UnixNano()
+	}
+
+	earliestBlockTime := time.Unix(0, earliestBlockTimeNano)
+
 	var votingPower int64
 	if val := validatorAtHeight(latestHeight); val != nil {
 		votingPower = val.VotingPower
@@ -121,7 +144,13 @@ func Status(ctx *rpctypes.Context, heightGtePtr *int64) (*ctypes.ResultStatus, e
 			LatestAppHash:     latestAppHash,
 			LatestBlockHeight: latestHeight,
 			LatestBlockTime:   latestBlockTime,
-			CatchingUp:        getFastSync(),
+
+			EarliestBlockHash:   earliestBlockHash,
+			EarliestAppHash:     earliestAppHash,
+			EarliestBlockHeight: earliestHeight,
+			EarliestBlockTime:   earliestBlockTime,
+
+			CatchingUp: getFastSync(),
 		},
 		ValidatorInfo: ctypes.ValidatorInfo{
 			Address:     pubKey.Address(),
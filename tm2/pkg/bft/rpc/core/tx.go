@@ -2,10 +2,14 @@ package core
 
 import (
 	"fmt"
+	"slices"
 
 	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	tmquery "github.com/gnolang/gno/tm2/pkg/bft/types/query"
+	"github.com/gnolang/gno/tm2/pkg/errors"
 )
 
 // Tx allows you to query the transaction results. `nil` could mean the
@@ -26,6 +30,9 @@ func Tx(_ *rpctypes.Context, hash []byte) (*ctypes.ResultTx, error) {
 
 	// Load the block
 	block := blockStore.LoadBlock(height)
+	if block == nil {
+		return nil, errPrunedHeight(height)
+	}
 	numTxs := len(block.Txs)
 
 	if int(resultIndex.TxIndex) > numTxs || numTxs == 0 {
@@ -64,3 +71,101 @@ func Tx(_ *rpctypes.Context, hash []byte) (*ctypes.ResultTx, error) {
 		Tx:       rawTx,
 	}, nil
 }
+
+// TxSearch allows you to query for multiple transactions results, indexed by
+// the kv transaction event store. It returns the matching transactions,
+// paginated and ordered by height and index.
+//
+// See the documentation of the tm2/pkg/bft/types/query package for the
+// syntax of queries. Some examples:
+//
+//	tx.height >= 100 AND tx.signer = 'g1...'
+//	msg.route = 'vm' AND msg.type = 'exec' AND msg.pkg_path = 'gno.land/r/demo/foo'
+//	msg.pkg_path = 'gno.land/r/demo/foo' AND event.type = 'Transfer' AND event.attrs.to = 'g1...'
+//
+// Only the transactions committed while the kv event store is enabled are
+// indexed.
+//
+// ```shell
+// curl "localhost:26657/tx_search?query=\"tx.height>=1\"&page=1&per_page=30&order_by=\"desc\""
+// ```
+//
+// > The above command returns JSON structured like this:
+//
+// ```json
+//
+//	{
+//		"error": "",
+//		"result": {
+//			"txs": [
+//				{
+//					"hash": "...",
+//					"height": "2",
+//					"index": 0,
+//					"tx_result": { ... },
+//					"tx": "..."
+//				}
+//			],
+//			"total_count": "1"
+//		},
+//		"id": "",
+//		"jsonrpc": "2.0"
+//	}
+//
+// ```
+//
+// ### Query Parameters
+//
+// | Parameter | Type   | Default | Required | Description                                      |
+// |-----------+--------+---------+----------+--------------------------------------------------|
+// | query     | string | ""      | true     | Query                                            |
+// | page      | int    | 1       | false    | Page number (1-based)                            |
+// | per_page  | int    | 30      | false    | Number of entries per page (max: 100)            |
+// | order_by  | string | "asc"   | false    | Order by height and index, either asc or desc    |
+func TxSearch(_ *rpctypes.Context, query string, page, perPage int, orderBy string) (*ctypes.ResultTxSearch, error) {
+	searcher, ok := txEventStore.(eventstore.TxSearcher)
+	if !ok {
+		return nil, errors.New("transaction search is disabled, enable the kv event store to use it")
+	}
+
+	q, err := tmquery.Parse(query)
+	if err != nil {
+		return nil, err
+	}
+
+	results, err := searcher.SearchTxs(q)
+	if err != nil {
+		return nil, err
+	}
+
+	switch orderBy {
+	case "", "asc":
+	case "desc":
+		slices.Reverse(results)
+	default:
+		return nil, fmt.Errorf("invalid order_by %q, expected asc or desc", orderBy)
+	}
+
+	// Paginate the results
+	totalCount := len(results)
+	perPage = validatePerPage(perPage)
+	page, err = validatePage(page, perPage, totalCount)
+	if err != nil {
+		return nil, err
+	}
+	skipCount := (page - 1) * perPage
+	pageSize := min(perPage, totalCount-skipCount)
+
+	txs := make([]*ctypes.ResultTx, 0, pageSize)
+	for _, res := range results[skipCount : skipCount+pageSize] {
+		txs = append(txs, &ctypes.ResultTx{
+			Hash:     res.Tx.Hash(),
+			Height:   res.Height,
+			Index:    res.Index,
+			TxResult: res.Response,
+			Tx:       res.Tx,
+		})
+	}
+
+	return &ctypes.ResultTxSearch{Txs: txs, TotalCount: totalCount}, nil
+}
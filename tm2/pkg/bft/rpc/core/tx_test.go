@@ -1,11 +1,15 @@
 package core
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	rpctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
 	"github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/null"
 	"github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/db/memdb"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -220,3 +224,72 @@ func TestTxHandler(t *testing.T) {
 		assert.ErrorContains(t, err, "unable to load block results")
 	})
 }
+
+func TestTxSearchHandler(t *testing.T) {
+	// Tests are not run in parallel because the JSON-RPC
+	// handlers utilize global package-level variables
+	var (
+		es  = kv.NewTxEventStore(memdb.NewMemDB())
+		txs = make([]types.Tx, 5)
+	)
+
+	for i := range txs {
+		stdTx := &std.Tx{Memo: fmt.Sprintf("tx %d", i)}
+		txs[i] = amino.MustMarshal(stdTx)
+		require.NoError(t, es.Append(types.TxResult{
+			Height: int64(i + 1),
+			Tx:     txs[i],
+		}))
+	}
+
+	SetTxEventStore(es)
+	t.Cleanup(func() { SetTxEventStore(nil) })
+
+	t.Run("ascending pages", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height >= 2", 2, 3, "")
+		require.NoError(t, err)
+
+		assert.Equal(t, 4, res.TotalCount)
+		require.Len(t, res.Txs, 1)
+		assert.Equal(t, int64(5), res.Txs[0].Height)
+		assert.Equal(t, txs[4], res.Txs[0].Tx)
+		assert.Equal(t, txs[4].Hash(), res.Txs[0].Hash)
+	})
+
+	t.Run("descending", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height <= 3", 0, 0, "desc")
+		require.NoError(t, err)
+
+		assert.Equal(t, 3, res.TotalCount)
+		require.Len(t, res.Txs, 3)
+		for i, height := range []int64{3, 2, 1} {
+			assert.Equal(t, height, res.Txs[i].Height)
+		}
+	})
+
+	t.Run("no results", func(t *testing.T) {
+		res, err := TxSearch(&rpctypes.Context{}, "tx.height > 10", 0, 0, "asc")
+		require.NoError(t, err)
+
+		assert.Equal(t, 0, res.TotalCount)
+		assert.Empty(t, res.Txs)
+	})
+
+	t.Run("invalid parameters", func(t *testing.T) {
+		_, err := TxSearch(&rpctypes.Context{}, "tx.height", 0, 0, "")
+		assert.ErrorContains(t, err, "invalid query")
+
+		_, err = TxSearch(&rpctypes.Context{}, "", 0, 0, "random")
+		assert.ErrorContains(t, err, "invalid order_by")
+
+		_, err = TxSearch(&rpctypes.Context{}, "", 3, 0, "")
+		assert.ErrorContains(t, err, "page should be within")
+	})
+
+	t.Run("search disabled", func(t *testing.T) {
+		SetTxEventStore(null.NewNullEventStore())
+
+		_, err := TxSearch(&rpctypes.Context{}, "", 0, 0, "")
+		assert.ErrorContains(t, err, "transaction search is disabled")
+	})
+}
@@ -63,7 +63,14 @@ type SyncInfo struct {
 	LatestAppHash     []byte    `json:"latest_app_hash"`
 	LatestBlockHeight int64     `json:"latest_block_height"`
 	LatestBlockTime   time.Time `json:"latest_block_time"`
-	CatchingUp        bool      `json:"catching_up"`
+
+	// Lowest retained block, the older blocks were pruned
+	EarliestBlockHash   []byte    `json:"earliest_block_hash"`
+	EarliestAppHash     []byte    `json:"earliest_app_hash"`
+	EarliestBlockHeight int64     `json:"earliest_block_height"`
+	EarliestBlockTime   time.Time `json:"earliest_block_time"`
+
+	CatchingUp bool `json:"catching_up"`
 }
 
 // Info about the node's validator
@@ -199,10 +206,13 @@ type ResultABCIQuery struct {
 type (
 	ResultUnsafeFlushMempool struct{}
 	ResultUnsafeProfile      struct{}
+	ResultSubscribe          struct{}
+	ResultUnsubscribe        struct{}
 	ResultHealth             struct{}
 )
 
 // Event data from a subscription
 type ResultEvent struct {
+	Query string        `json:"query"`
 	Event types.TMEvent `json:"event"`
 }
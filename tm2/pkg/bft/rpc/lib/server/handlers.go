@@ -19,7 +19,10 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/telemetry"
 	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
 	"github.com/gorilla/websocket"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/lib/types"
@@ -194,6 +197,9 @@ func processRequest(r *http.Request, req types.RPCRequest, funcMap map[string]*R
 		return &resp
 	}
 
+	r, span := startSpan(r, req.Method)
+	defer span.End()
+
 	ctx := &types.Context{JSONReq: &req, HTTPReq: r}
 	args := []reflect.Value{reflect.ValueOf(ctx)}
 	if len(req.Params) > 0 {
@@ -212,6 +218,8 @@ func processRequest(r *http.Request, req types.RPCRequest, funcMap map[string]*R
 	// Convert the reflection return values into a result value for JSON serialization.
 	result, err := unreflectResult(returns)
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+
 		resp := types.RPCInternalError(req.ID, err)
 		return &resp
 	}
@@ -249,6 +257,18 @@ func telemetryMiddleware(next http.Handler) http.HandlerFunc {
 	}
 }
 
+// startSpan starts the trace span of an RPC method call,
+// returning the request with the span context
+func startSpan(r *http.Request, method string) (*http.Request, trace.Span) {
+	ctx, span := traces.Tracer().Start(
+		r.Context(),
+		"rpc/"+method,
+		trace.WithSpanKind(trace.SpanKindServer),
+	)
+
+	return r.WithContext(ctx), span
+}
+
 func mapParamsToArgs(rpcFunc *RPCFunc, params map[string]json.RawMessage, argsOffset int) ([]reflect.Value, error) {
 	values := make([]reflect.Value, len(rpcFunc.argNames))
 	for i, argName := range rpcFunc.argNames {
@@ -334,6 +354,9 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger *slog.Logger) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		logger.Debug("HTTP HANDLER", "req", r)
 
+		r, span := startSpan(r, strings.TrimPrefix(r.URL.Path, "/"))
+		defer span.End()
+
 		ctx := &types.Context{HTTPReq: r}
 		args := []reflect.Value{reflect.ValueOf(ctx)}
 
@@ -349,6 +372,8 @@ func makeHTTPHandler(rpcFunc *RPCFunc, logger *slog.Logger) http.HandlerFunc {
 		logger.Info("HTTPRestRPC", "method", r.URL.Path, "args", args, "returns", returns)
 		result, err := unreflectResult(returns)
 		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+
 			var statusErr *types.HTTPStatusError
 			if goerrors.As(err, &statusErr) {
 				WriteRPCResponseHTTPError(w, statusErr.Code, types.RPCInternalError(types.JSONRPCStringID(""), err))
@@ -0,0 +1,234 @@
+// Package kv implements a transaction event store indexing the transactions
+// in a key-value database, so they can be searched with a query.
+package kv
+
+import (
+	"bytes"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types/query"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+)
+
+var (
+	_ eventstore.TxEventStore = (*TxEventStore)(nil)
+	_ eventstore.TxSearcher   = (*TxEventStore)(nil)
+)
+
+const (
+	EventStoreType = "kv"
+)
+
+// Layout of the database:
+//
+//	tx/<ref>                      -> amino encoded TxResult
+//	hash/<tx hash>                -> <ref>
+//	idx/<key>\x00<value>\x00<ref> -> <ref>
+//
+// where <ref> is the zero-padded "<height>/<index>" of the transaction, so
+// the keys are ordered by height and index. The index entries are written
+// for the tx.signer, msg.* and event.* keys of the query package.
+const (
+	txPrefix    = "tx/"
+	hashPrefix  = "hash/"
+	indexPrefix = "idx/"
+)
+
+// TxEventStore is the implementation of a transaction event store
+// that indexes the transactions in a key-value database
+type TxEventStore struct {
+	db dbm.DB
+}
+
+// NewTxEventStore creates a new kv tx event store, on top of the given db
+func NewTxEventStore(db dbm.DB) *TxEventStore {
+	return &TxEventStore{
+		db: db,
+	}
+}
+
+// Start starts the kv transaction event store
+func (t *TxEventStore) Start() error {
+	return nil
+}
+
+// Stop stops the kv transaction event store, by closing the database
+func (t *TxEventStore) Stop() error {
+	return t.db.Close()
+}
+
+// GetType returns the kv transaction event store type
+func (t *TxEventStore) GetType() string {
+	return EventStoreType
+}
+
+// Append stores the transaction and its index entries, atomically
+func (t *TxEventStore) Append(tx types.TxResult) error {
+	txRaw, err := amino.Marshal(tx)
+	if err != nil {
+		return fmt.Errorf("unable to marshal transaction, %w", err)
+	}
+
+	ref := txRef(tx.Height, tx.Index)
+	batch := t.db.NewBatch()
+	defer batch.Close()
+
+	batch.Set([]byte(txPrefix+ref), txRaw)
+	batch.Set([]byte(hashPrefix+fmt.Sprintf("%X", tx.Tx.Hash())), []byte(ref))
+
+	fields := query.TxResultFields(tx)
+	for _, signer := range fields.Signers {
+		batch.Set(indexKey(query.KeyTxSigner, signer, ref), []byte(ref))
+	}
+	for _, msg := range fields.Msgs {
+		for field, value := range msg {
+			batch.Set(indexKey(query.MsgKeyPrefix+field, value, ref), []byte(ref))
+		}
+	}
+	for _, ev := range fields.Events {
+		for field, value := range ev {
+			batch.Set(indexKey(query.EventKeyPrefix+field, value, ref), []byte(ref))
+		}
+	}
+	batch.Write()
+
+	return nil
+}
+
+// SearchTxs returns the transactions matching the query, ordered by height
+// and index. The candidates are selected with the index entries and the
+// height range of the query, and then matched against the full query.
+func (t *TxEventStore) SearchTxs(q *query.Query) ([]types.TxResult, error) {
+	var (
+		minHeight, maxHeight int64 = 0, math.MaxInt64
+
+		refs map[string]struct{} // nil: all the transactions
+	)
+	intersect := func(set map[string]struct{}) {
+		if refs == nil {
+			refs = set
+			return
+		}
+		for ref := range refs {
+			if _, ok := set[ref]; !ok {
+				delete(refs, ref)
+			}
+		}
+	}
+
+	for _, c := range q.Conditions() {
+		switch {
+		case c.Key == query.KeyTxHash && c.Op == query.OpEqual:
+			set := map[string]struct{}{}
+			if ref := t.db.Get([]byte(hashPrefix + c.Operand)); ref != nil {
+				set[string(ref)] = struct{}{}
+			}
+			intersect(set)
+		case c.Key == query.KeyTxHeight:
+			height, err := strconv.ParseInt(c.Operand, 10, 64)
+			if err != nil {
+				continue // matched as a string
+			}
+			switch c.Op {
+			case query.OpEqual:
+				minHeight, maxHeight = max(minHeight, height), min(maxHeight, height)
+			case query.OpLess:
+				maxHeight = min(maxHeight, height-1)
+			case query.OpLessEqual:
+				maxHeight = min(maxHeight, height)
+			case query.OpGreater:
+				minHeight = max(minHeight, height+1)
+			case query.OpGreaterEqual:
+				minHeight = max(minHeight, height)
+			}
+		case c.Key == query.KeyTxSigner,
+			strings.HasPrefix(c.Key, query.MsgKeyPrefix),
+			strings.HasPrefix(c.Key, query.EventKeyPrefix):
+			intersect(t.scanIndex(c))
+		}
+	}
+	if minHeight > maxHeight || (refs != nil && len(refs) == 0) {
+		return nil, nil
+	}
+
+	// Select the candidates in the height range
+	var (
+		start = txRef(minHeight, 0)
+		end   = txRef(maxHeight, math.MaxUint32)
+	)
+	var candidates []string
+	if refs == nil {
+		it := t.db.Iterator([]byte(txPrefix+start), append([]byte(txPrefix+end), 0))
+		for ; it.Valid(); it.Next() {
+			candidates = append(candidates, string(bytes.TrimPrefix(it.Key(), []byte(txPrefix))))
+		}
+		it.Close()
+	} else {
+		for ref := range refs {
+			if start <= ref && ref <= end {
+				candidates = append(candidates, ref)
+			}
+		}
+		slices.Sort(candidates)
+	}
+
+	var results []types.TxResult
+	for _, ref := range candidates {
+		txRaw := t.db.Get([]byte(txPrefix + ref))
+		if txRaw == nil {
+			return nil, fmt.Errorf("missing transaction %s", ref)
+		}
+		var tx types.TxResult
+		if err := amino.Unmarshal(txRaw, &tx); err != nil {
+			return nil, fmt.Errorf("unable to unmarshal transaction %s, %w", ref, err)
+		}
+		if q.Matches(query.TxResultFields(tx)) {
+			results = append(results, tx)
+		}
+	}
+
+	return results, nil
+}
+
+// scanIndex returns the references of the transactions with a value of
+// c.Key matching c
+func (t *TxEventStore) scanIndex(c query.Condition) map[string]struct{} {
+	prefix := indexPrefix + c.Key + "\x00"
+	exact := c.Op == query.OpEqual
+	if exact {
+		prefix += c.Operand + "\x00"
+	}
+
+	refs := map[string]struct{}{}
+	it := dbm.IteratePrefix(t.db, []byte(prefix))
+	defer it.Close()
+	for ; it.Valid(); it.Next() {
+		if !exact {
+			// Strip the prefix, and the \x00 and <ref> suffix
+			key := it.Key()
+			value := string(key[len(prefix) : len(key)-len(it.Value())-1])
+			if !c.Matches(value) {
+				continue
+			}
+		}
+		refs[string(it.Value())] = struct{}{}
+	}
+
+	return refs
+}
+
+// txRef returns the reference of a transaction, ordered by height and index
+func txRef(height int64, index uint32) string {
+	return fmt.Sprintf("%020d/%010d", height, index)
+}
+
+func indexKey(key, value, ref string) []byte {
+	return []byte(indexPrefix + key + "\x00" + value + "\x00" + ref)
+}
@@ -0,0 +1,167 @@
+package kv
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types/query"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	Type    string     `json:"type"`
+	PkgPath string     `json:"pkg_path"`
+	Attrs   []testAttr `json:"attrs"`
+}
+
+type testAttr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+// The events are stored in their binary form, so their type must be
+// registered.
+var _ = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/bft/state/eventstore/kv",
+	"kv_test",
+	amino.GetCallersDirname(),
+).WithTypes(
+	testEvent{}, "testEvent",
+	testAttr{}, "testAttr",
+))
+
+var (
+	alice = crypto.AddressFromPreimage([]byte("alice"))
+	bob   = crypto.AddressFromPreimage([]byte("bob"))
+)
+
+// newTestTx returns a transaction result sending coins from -> to, emitting
+// the given events
+func newTestTx(t *testing.T, height int64, index uint32, from, to crypto.Address, evs ...abci.Event) types.TxResult {
+	t.Helper()
+
+	tx := std.Tx{
+		Msgs: []std.Msg{bank.NewMsgSend(from, to, std.NewCoins(std.NewCoin("ugnot", height)))},
+	}
+
+	return types.TxResult{
+		Height: height,
+		Index:  index,
+		Tx:     amino.MustMarshal(tx),
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{Events: evs},
+		},
+	}
+}
+
+func transfer(pkgPath string, to crypto.Address) testEvent {
+	return testEvent{
+		Type:    "Transfer",
+		PkgPath: pkgPath,
+		Attrs:   []testAttr{{"to", to.String()}},
+	}
+}
+
+func TestTxEventStore_SearchTxs(t *testing.T) {
+	t.Parallel()
+
+	txs := []types.TxResult{
+		newTestTx(t, 1, 0, alice, bob, transfer("gno.land/r/foo", bob)),
+		newTestTx(t, 1, 1, bob, alice, transfer("gno.land/r/foo", alice)),
+		newTestTx(t, 2, 0, alice, bob, transfer("gno.land/r/bar", bob), testEvent{Type: "Mint", PkgPath: "gno.land/r/foo"}),
+		newTestTx(t, 3, 0, alice, alice),
+		newTestTx(t, 12, 0, bob, bob, transfer("gno.land/r/foo", bob)),
+	}
+
+	s := NewTxEventStore(memdb.NewMemDB())
+	require.NoError(t, s.Start())
+	// Appended out of order
+	for _, i := range []int{4, 2, 0, 3, 1} {
+		require.NoError(t, s.Append(txs[i]))
+	}
+
+	testTable := []struct {
+		query    string
+		expected []int
+	}{
+		{"", []int{0, 1, 2, 3, 4}},
+		{"tm.event = 'Tx'", []int{0, 1, 2, 3, 4}},
+		{"tm.event = 'NewBlock'", nil},
+		{"tx.height = 1", []int{0, 1}},
+		{"tx.height > 1 AND tx.height <= 3", []int{2, 3}},
+		{"tx.height >= 3", []int{3, 4}},
+		{"tx.height < 1", nil},
+		{"tx.height > 3 AND tx.height < 3", nil},
+		{"tx.index = 1", []int{1}},
+		{fmt.Sprintf("tx.hash = '%x'", txs[3].Tx.Hash()), []int{3}},
+		{"tx.hash = 'ABCD'", nil},
+		{"tx.signer = '" + bob.String() + "'", []int{1, 4}},
+		{"msg.route = 'bank' AND msg.to_address = '" + bob.String() + "'", []int{0, 2, 4}},
+		{"msg.amount = '12ugnot'", []int{4}},
+		{"event.pkg_path = 'gno.land/r/foo'", []int{0, 1, 2, 4}},
+		{"event.pkg_path = 'gno.land/r/foo' AND event.type = 'Transfer'", []int{0, 1, 4}},
+		{
+			"event.pkg_path = 'gno.land/r/foo' AND event.type = 'Transfer' AND event.attrs.to = '" + bob.String() + "'",
+			[]int{0, 4},
+		},
+		{
+			"tx.height < 10 AND event.pkg_path = 'gno.land/r/foo' AND event.type = 'Transfer' AND event.attrs.to = '" + bob.String() + "'",
+			[]int{0},
+		},
+		// Non-exact conditions scan the values of the key
+		{"event.pkg_path CONTAINS '/r/b'", []int{2}},
+		{"msg.to_address CONTAINS 'g1'", []int{0, 1, 2, 3, 4}},
+		{"event.type = 'Burn'", nil},
+		{"event.unknown = 'x'", nil},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			results, err := s.SearchTxs(query.MustParse(testCase.query))
+			require.NoError(t, err)
+
+			expected := make([]types.TxResult, 0, len(testCase.expected))
+			for _, i := range testCase.expected {
+				expected = append(expected, txs[i])
+			}
+			assert.Equal(t, len(expected), len(results))
+			for i := range min(len(expected), len(results)) {
+				assert.Equal(t, expected[i].Height, results[i].Height)
+				assert.Equal(t, expected[i].Index, results[i].Index)
+				assert.Equal(t, expected[i].Tx, results[i].Tx)
+			}
+		})
+	}
+}
+
+func TestTxEventStore_Append(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+	s := NewTxEventStore(db)
+	assert.Equal(t, EventStoreType, s.GetType())
+
+	// Appending the same transaction again does not duplicate it
+	tx := newTestTx(t, 5, 3, alice, bob, transfer("gno.land/r/foo", bob))
+	require.NoError(t, s.Append(tx))
+	require.NoError(t, s.Append(tx))
+
+	results, err := s.SearchTxs(query.MustParse("event.type = 'Transfer'"))
+	require.NoError(t, err)
+	require.Len(t, results, 1)
+	assert.Equal(t, tx.Response.Events, results[0].Response.Events)
+
+	require.NoError(t, s.Stop())
+}
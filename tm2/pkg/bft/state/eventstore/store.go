@@ -1,6 +1,9 @@
 package eventstore
 
-import "github.com/gnolang/gno/tm2/pkg/bft/types"
+import (
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types/query"
+)
 
 const (
 	StatusOn  = "on"
@@ -22,3 +25,11 @@ type TxEventStore interface {
 	// to the event store
 	Append(result types.TxResult) error
 }
+
+// TxSearcher is implemented by the transaction event stores
+// which can be searched
+type TxSearcher interface {
+	// SearchTxs returns the stored transactions matching the query,
+	// ordered by height and index
+	SearchTxs(q *query.Query) ([]types.TxResult, error)
+}
@@ -335,6 +335,11 @@ func validateValidatorUpdates(abciUpdates []abci.ValidatorUpdate,
 			return fmt.Errorf("validator %v is using pubkey %s, which is unsupported for consensus",
 				valUpdate, pubkeyTypeURL)
 		}
+
+		// Check if validator's pubkey is a valid consensus key, e.g. a bounded multisig
+		if err := types.ValidateValidatorPubKey(valUpdate.PubKey); err != nil {
+			return fmt.Errorf("validator %v is using an invalid pubkey: %w", valUpdate, err)
+		}
 	}
 	return nil
 }
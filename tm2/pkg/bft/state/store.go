@@ -102,6 +102,23 @@ func SaveState(db dbm.DB, state State) {
 	saveState(db, state, stateKey)
 }
 
+// BootstrapState persists a State restored by the state sync, without its
+// previous blocks, to an empty database. The validator sets of the last,
+// current and next heights, which are needed to validate the following
+// blocks, are stored as if they changed at these heights, and so are the
+// consensus params of the next height.
+func BootstrapState(db dbm.DB, state State) State {
+	height := state.LastBlockHeight
+	state.LastHeightValidatorsChanged = height + 2
+	state.LastHeightConsensusParamsChanged = height + 1
+
+	saveValidatorsInfo(db, height, height, state.LastValidators)
+	saveValidatorsInfo(db, height+1, height+1, state.Validators)
+	saveState(db, state, stateKey)
+
+	return state
+}
+
 func saveState(db dbm.DB, state State, key []byte) {
 	nextHeight := state.LastBlockHeight + 1
 	// If first block, save validators for block 1.
@@ -185,6 +202,21 @@ func SaveABCIResponses(db dbm.DB, height int64, abciResponses *ABCIResponses) {
 	db.Set(CalcABCIResponsesKey(height), abciResponses.Bytes())
 }
 
+// PruneBlockResults deletes the ABCIResponses of the block, and the result
+// indexes of its transactions, with the given batch of db.
+// NOTE: this should only be used by the pruning of the blocks.
+func PruneBlockResults(db dbm.DB, batch dbm.SetDeleter, block *types.Block) {
+	for _, tx := range block.Txs {
+		hash := tx.Hash()
+
+		// The same transaction may have been included again in a later block
+		if resultIndex, err := LoadTxResultIndex(db, hash); err == nil && resultIndex.BlockNum == block.Height {
+			batch.Delete(CalcTxResultKey(hash))
+		}
+	}
+	batch.Delete(CalcABCIResponsesKey(block.Height))
+}
+
 // TxResultIndex keeps the result index information for a transaction
 type TxResultIndex struct {
 	BlockNum int64  // the block number the tx was contained in
@@ -57,6 +57,78 @@ func TestStoreLoadValidators(t *testing.T) {
 	assert.NotZero(t, loadedVals.Size())
 }
 
+func TestBootstrapState(t *testing.T) {
+	t.Parallel()
+
+	stateDB := memdb.NewMemDB()
+	lastVals := genValSet(2)
+	vals := genValSet(3)
+	nextVals := genValSet(4)
+	params := types.DefaultConsensusParams()
+
+	state := sm.BootstrapState(stateDB, sm.State{
+		ChainID:         "chain",
+		LastBlockHeight: 10,
+		LastValidators:  lastVals,
+		Validators:      vals,
+		NextValidators:  nextVals,
+		ConsensusParams: params,
+	})
+	assert.Equal(t, int64(12), state.LastHeightValidatorsChanged)
+	assert.Equal(t, int64(11), state.LastHeightConsensusParamsChanged)
+
+	loaded := sm.LoadState(stateDB)
+	assert.Equal(t, state.Bytes(), loaded.Bytes())
+
+	// The validator sets and the consensus params needed to validate the
+	// next blocks are stored
+	for height, expected := range map[int64]*types.ValidatorSet{10: lastVals, 11: vals, 12: nextVals} {
+		loadedVals, err := sm.LoadValidators(stateDB, height)
+		require.NoError(t, err)
+		assert.Equal(t, expected.Hash(), loadedVals.Hash(), "height %d", height)
+	}
+	_, err := sm.LoadValidators(stateDB, 9)
+	assert.Error(t, err)
+
+	loadedParams, err := sm.LoadConsensusParams(stateDB, 11)
+	require.NoError(t, err)
+	assert.Equal(t, params, loadedParams)
+}
+
+func TestPruneBlockResults(t *testing.T) {
+	t.Parallel()
+
+	var (
+		stateDB = memdb.NewMemDB()
+		height  = int64(10)
+		txs     = types.Txs{types.Tx("pruned"), types.Tx("included again")}
+	)
+	sm.SaveABCIResponses(stateDB, height, sm.NewABCIResponsesFromNum(int64(len(txs))))
+	for i, tx := range txs {
+		stateDB.Set(sm.CalcTxResultKey(tx.Hash()), (&sm.TxResultIndex{BlockNum: height, TxIndex: uint32(i)}).Bytes())
+	}
+	// The second transaction is included again in a later block
+	stateDB.Set(sm.CalcTxResultKey(txs[1].Hash()), (&sm.TxResultIndex{BlockNum: height + 5}).Bytes())
+
+	batch := stateDB.NewBatch()
+	sm.PruneBlockResults(stateDB, batch, &types.Block{
+		Header: types.Header{Height: height},
+		Data:   types.Data{Txs: txs},
+	})
+	batch.WriteSync()
+	batch.Close()
+
+	_, err := sm.LoadABCIResponses(stateDB, height)
+	assert.Error(t, err)
+
+	_, err = sm.LoadTxResultIndex(stateDB, txs[0].Hash())
+	assert.Error(t, err)
+
+	resultIndex, err := sm.LoadTxResultIndex(stateDB, txs[1].Hash())
+	require.NoError(t, err)
+	assert.Equal(t, height+5, resultIndex.BlockNum)
+}
+
 func BenchmarkLoadValidators(b *testing.B) {
 	const valSetSize = 100
 
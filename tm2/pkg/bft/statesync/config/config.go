@@ -0,0 +1,87 @@
+package config
+
+import (
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// StateSyncConfig defines the configuration of the state sync, restoring the
+// state of a fresh node from the snapshots of its peers
+type StateSyncConfig struct {
+	Enable        bool          `json:"enable" toml:"enable" comment:"State sync a fresh node from the snapshots of its peers, instead of replaying the whole chain.\n Only done when the node has no state, otherwise the node fast syncs from its last block."`
+	RPCServers    string        `json:"rpc_servers" toml:"rpc_servers" comment:"Comma separated list of the RPC servers the headers of the snapshots are fetched from"`
+	TrustHeight   int64         `json:"trust_height" toml:"trust_height" comment:"Height of the trusted header, which the headers of the snapshots are verified from"`
+	TrustHash     string        `json:"trust_hash" toml:"trust_hash" comment:"Hex-encoded hash of the trusted header"`
+	TrustPeriod   time.Duration `json:"trust_period" toml:"trust_period" comment:"Period during which the trusted header can verify the headers of the snapshots.\n It must be below the unbonding period of the validators, so that they can still be punished for signing a forged header"`
+	DiscoveryTime time.Duration `json:"discovery_time" toml:"discovery_time" comment:"Time spent discovering the snapshots of the peers, before restoring the best one"`
+	ChunkTimeout  time.Duration `json:"chunk_timeout" toml:"chunk_timeout" comment:"Timeout of the snapshot chunk requests, after which the chunk is requested from another peer"`
+}
+
+// DefaultStateSyncConfig returns a default configuration for the state sync,
+// which is disabled
+func DefaultStateSyncConfig() *StateSyncConfig {
+	return &StateSyncConfig{
+		Enable:        false,
+		RPCServers:    "",
+		TrustHeight:   0,
+		TrustHash:     "",
+		TrustPeriod:   168 * time.Hour, // 1 week
+		DiscoveryTime: 15 * time.Second,
+		ChunkTimeout:  30 * time.Second,
+	}
+}
+
+// TestStateSyncConfig returns a configuration for testing the state sync
+func TestStateSyncConfig() *StateSyncConfig {
+	cfg := DefaultStateSyncConfig()
+	cfg.DiscoveryTime = 100 * time.Millisecond
+	cfg.ChunkTimeout = time.Second
+	return cfg
+}
+
+// RPCServerList returns the RPC servers of the configuration.
+func (cfg *StateSyncConfig) RPCServerList() []string {
+	var servers []string
+	for _, server := range strings.Split(cfg.RPCServers, ",") {
+		if server = strings.TrimSpace(server); server != "" {
+			servers = append(servers, server)
+		}
+	}
+
+	return servers
+}
+
+// ValidateBasic performs basic validation (checking param bounds, etc.) and
+// returns an error if any check fails.
+func (cfg *StateSyncConfig) ValidateBasic() error {
+	if cfg.DiscoveryTime <= 0 {
+		return errors.New("discovery_time must be positive")
+	}
+	if cfg.ChunkTimeout <= 0 {
+		return errors.New("chunk_timeout must be positive")
+	}
+	if !cfg.Enable {
+		return nil
+	}
+
+	if len(cfg.RPCServerList()) == 0 {
+		return errors.New("rpc_servers must be set")
+	}
+	if cfg.TrustHeight <= 0 {
+		return errors.New("trust_height must be positive")
+	}
+	hash, err := hex.DecodeString(cfg.TrustHash)
+	if err != nil {
+		return errors.Wrap(err, "invalid trust_hash")
+	}
+	if len(hash) == 0 {
+		return errors.New("trust_hash must be set")
+	}
+	if cfg.TrustPeriod <= 0 {
+		return errors.New("trust_period must be positive")
+	}
+	return nil
+}
@@ -0,0 +1,345 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/p2p"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+const (
+	// SnapshotChannel is a channel for the snapshots available from peers
+	SnapshotChannel = byte(0x60)
+	// ChunkChannel is a channel for the chunks of the snapshots
+	ChunkChannel = byte(0x61)
+
+	// recentSnapshots is the number of recent snapshots advertised to peers
+	recentSnapshots = 10
+
+	maxSnapshotMsgSize = 4 << 20  // 4 MB
+	maxChunkMsgSize    = 16 << 20 // 16 MB
+)
+
+// Reactor serves the snapshots of the application to the peers, and restores
+// the state of a fresh node from the snapshots of its peers, see Sync.
+type Reactor struct {
+	p2p.BaseReactor
+
+	config *cfg.StateSyncConfig
+	conn   appconn.Snapshot
+
+	mtx    sync.RWMutex
+	syncer *syncer // set while syncing
+}
+
+// NewReactor returns a new state sync reactor, serving the snapshots of the
+// application through the given connection.
+func NewReactor(config *cfg.StateSyncConfig, conn appconn.Snapshot) *Reactor {
+	r := &Reactor{
+		config: config,
+		conn:   conn,
+	}
+	r.BaseReactor = *p2p.NewBaseReactor("StateSyncReactor", r)
+
+	return r
+}
+
+// GetChannels implements Reactor
+func (r *Reactor) GetChannels() []*p2p.ChannelDescriptor {
+	return []*p2p.ChannelDescriptor{
+		{
+			ID:                  SnapshotChannel,
+			Priority:            5,
+			SendQueueCapacity:   10,
+			RecvMessageCapacity: maxSnapshotMsgSize,
+		},
+		{
+			ID:                  ChunkChannel,
+			Priority:            3,
+			SendQueueCapacity:   4,
+			RecvBufferCapacity:  4096,
+			RecvMessageCapacity: maxChunkMsgSize,
+		},
+	}
+}
+
+// AddPeer implements Reactor by asking the peer for its snapshots, while
+// syncing.
+func (r *Reactor) AddPeer(peer p2p.PeerConn) {
+	if r.getSyncer() != nil {
+		peer.Send(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+	}
+}
+
+// RemovePeer implements Reactor by removing the snapshots of the peer, while
+// syncing.
+func (r *Reactor) RemovePeer(peer p2p.PeerConn, reason any) {
+	if s := r.getSyncer(); s != nil {
+		s.removePeer(peer.ID())
+	}
+}
+
+// Receive implements Reactor by handling 4 types of messages (look below).
+func (r *Reactor) Receive(chID byte, src p2p.PeerConn, msgBytes []byte) {
+	msg, err := decodeMsg(chID, msgBytes)
+	if err != nil {
+		r.Logger.Error("Error decoding message", "src", src, "chId", chID, "err", err)
+		r.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	if err = msg.ValidateBasic(); err != nil {
+		r.Logger.Error("Peer sent us invalid msg", "peer", src, "msg", msg, "err", err)
+		r.Switch.StopPeerForError(src, err)
+		return
+	}
+
+	r.Logger.Debug("Receive", "src", src, "chID", chID, "msg", msg)
+
+	switch msg := msg.(type) {
+	case *snapshotsRequestMessage:
+		r.respondSnapshots(src)
+	case *snapshotsResponseMessage:
+		if s := r.getSyncer(); s != nil {
+			s.addSnapshot(src.ID(), msg.snapshot())
+		}
+	case *chunkRequestMessage:
+		r.respondChunk(msg, src)
+	case *chunkResponseMessage:
+		if s := r.getSyncer(); s != nil {
+			s.addChunk(src.ID(), msg)
+		}
+	default:
+		r.Logger.Error(fmt.Sprintf("Unknown message type %v", reflect.TypeOf(msg)))
+	}
+}
+
+// respondSnapshots sends the recent snapshots of the application to the
+// requesting peer.
+func (r *Reactor) respondSnapshots(src p2p.PeerConn) {
+	res, err := r.conn.ListSnapshotsSync(abci.RequestListSnapshots{})
+	if err == nil && res.Error != nil {
+		err = res.Error
+	}
+	if err != nil {
+		r.Logger.Error("Unable to list snapshots", "err", err)
+		return
+	}
+
+	for i, snapshot := range res.Snapshots {
+		if i == recentSnapshots {
+			break
+		}
+
+		src.TrySend(SnapshotChannel, amino.MustMarshalAny(&snapshotsResponseMessage{
+			Height:   snapshot.Height,
+			Format:   snapshot.Format,
+			Chunks:   snapshot.Chunks,
+			Hash:     snapshot.Hash,
+			Metadata: snapshot.Metadata,
+		}))
+	}
+}
+
+// respondChunk sends a chunk of a snapshot to the requesting peer, or tells
+// it that the chunk is missing.
+func (r *Reactor) respondChunk(msg *chunkRequestMessage, src p2p.PeerConn) {
+	res, err := r.conn.LoadSnapshotChunkSync(abci.RequestLoadSnapshotChunk{
+		Height: msg.Height,
+		Format: msg.Format,
+		Chunk:  msg.Index,
+	})
+	if err == nil && res.Error != nil {
+		err = res.Error
+	}
+	if err != nil {
+		r.Logger.Info("Peer asking for a snapshot chunk we don't have",
+			"src", src, "height", msg.Height, "format", msg.Format, "index", msg.Index, "err", err)
+	}
+
+	src.TrySend(ChunkChannel, amino.MustMarshalAny(&chunkResponseMessage{
+		Height:  msg.Height,
+		Format:  msg.Format,
+		Index:   msg.Index,
+		Chunk:   res.Chunk,
+		Missing: err != nil || len(res.Chunk) == 0,
+	}))
+}
+
+// Sync restores the state of the application from the best snapshot of the
+// peers, verified with the given state provider. Snapshots are discovered
+// until a suitable one is found, or the context is done. It returns the
+// state and the commit of the height of the snapshot, which the node is
+// bootstrapped with.
+func (r *Reactor) Sync(ctx context.Context, stateProvider StateProvider) (sm.State, *types.Commit, error) {
+	r.mtx.Lock()
+	if r.syncer != nil {
+		r.mtx.Unlock()
+		return sm.State{}, nil, errors.New("a state sync is already running")
+	}
+	s := newSyncer(r.config, r.Logger, r.conn, stateProvider, r.requestSnapshots, r.requestChunk)
+	r.syncer = s
+	r.mtx.Unlock()
+
+	defer func() {
+		r.mtx.Lock()
+		r.syncer = nil
+		r.mtx.Unlock()
+	}()
+
+	return s.syncAny(ctx)
+}
+
+func (r *Reactor) getSyncer() *syncer {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+
+	return r.syncer
+}
+
+func (r *Reactor) requestSnapshots() {
+	r.Switch.Broadcast(SnapshotChannel, amino.MustMarshalAny(&snapshotsRequestMessage{}))
+}
+
+func (r *Reactor) requestChunk(peerID p2pTypes.ID, snapshot abci.Snapshot, index uint32) bool {
+	peer := r.Switch.Peers().Get(peerID)
+	if peer == nil {
+		return false
+	}
+
+	return peer.Send(ChunkChannel, amino.MustMarshalAny(&chunkRequestMessage{
+		Height: snapshot.Height,
+		Format: snapshot.Format,
+		Index:  index,
+	}))
+}
+
+// -----------------------------------------------------------------------------
+// Messages
+
+// StateSyncMessage is a message sent and received by the reactor.
+type StateSyncMessage interface {
+	ValidateBasic() error
+}
+
+func decodeMsg(chID byte, bz []byte) (msg StateSyncMessage, err error) {
+	maxMsgSize := maxSnapshotMsgSize
+	if chID == ChunkChannel {
+		maxMsgSize = maxChunkMsgSize
+	}
+	if len(bz) > maxMsgSize {
+		return msg, fmt.Errorf("msg exceeds max size (%d > %d)", len(bz), maxMsgSize)
+	}
+	err = amino.Unmarshal(bz, &msg)
+	return
+}
+
+// -------------------------------------
+
+type snapshotsRequestMessage struct{}
+
+// ValidateBasic performs basic validation.
+func (m *snapshotsRequestMessage) ValidateBasic() error {
+	return nil
+}
+
+func (m *snapshotsRequestMessage) String() string {
+	return "[snapshotsRequestMessage]"
+}
+
+// -------------------------------------
+
+type snapshotsResponseMessage struct {
+	Height   int64
+	Format   uint32
+	Chunks   uint32
+	Hash     []byte
+	Metadata []byte
+}
+
+// ValidateBasic performs basic validation.
+func (m *snapshotsResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("non-positive height")
+	}
+	if m.Chunks == 0 {
+		return errors.New("no chunks")
+	}
+	if len(m.Hash) == 0 {
+		return errors.New("no hash")
+	}
+	return nil
+}
+
+func (m *snapshotsResponseMessage) snapshot() abci.Snapshot {
+	return abci.Snapshot{
+		Height:   m.Height,
+		Format:   m.Format,
+		Chunks:   m.Chunks,
+		Hash:     m.Hash,
+		Metadata: m.Metadata,
+	}
+}
+
+func (m *snapshotsResponseMessage) String() string {
+	return fmt.Sprintf("[snapshotsResponseMessage %v:%v %X]", m.Height, m.Format, m.Hash)
+}
+
+// -------------------------------------
+
+type chunkRequestMessage struct {
+	Height int64
+	Format uint32
+	Index  uint32
+}
+
+// ValidateBasic performs basic validation.
+func (m *chunkRequestMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("non-positive height")
+	}
+	return nil
+}
+
+func (m *chunkRequestMessage) String() string {
+	return fmt.Sprintf("[chunkRequestMessage %v:%v %v]", m.Height, m.Format, m.Index)
+}
+
+// -------------------------------------
+
+type chunkResponseMessage struct {
+	Height  int64
+	Format  uint32
+	Index   uint32
+	Chunk   []byte
+	Missing bool // the peer does not have the chunk
+}
+
+// ValidateBasic performs basic validation.
+func (m *chunkResponseMessage) ValidateBasic() error {
+	if m.Height <= 0 {
+		return errors.New("non-positive height")
+	}
+	if m.Missing && len(m.Chunk) > 0 {
+		return errors.New("missing chunk with content")
+	}
+	if !m.Missing && len(m.Chunk) == 0 {
+		return errors.New("empty chunk")
+	}
+	return nil
+}
+
+func (m *chunkResponseMessage) String() string {
+	return fmt.Sprintf("[chunkResponseMessage %v:%v %v (%v bytes, missing %v)]",
+		m.Height, m.Format, m.Index, len(m.Chunk), m.Missing)
+}
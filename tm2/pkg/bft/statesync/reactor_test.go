@@ -0,0 +1,104 @@
+package statesync
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	"github.com/gnolang/gno/tm2/pkg/p2p/mock"
+)
+
+// receive passes the message to the reactor, and returns the messages sent
+// back to the peer.
+func receive(t *testing.T, r *Reactor, chID byte, msg StateSyncMessage) []StateSyncMessage {
+	t.Helper()
+
+	var sent []StateSyncMessage
+	peer := mock.GeneratePeers(t, 1)[0]
+	peer.TrySendFn = func(sentChID byte, bz []byte) bool {
+		assert.Equal(t, chID, sentChID)
+
+		msg, err := decodeMsg(sentChID, bz)
+		require.NoError(t, err)
+		sent = append(sent, msg)
+
+		return true
+	}
+
+	r.Receive(chID, peer, amino.MustMarshalAny(msg))
+
+	return sent
+}
+
+func TestReactor_RespondSnapshots(t *testing.T) {
+	t.Parallel()
+
+	conn := &mockSnapshotConn{}
+	for height := int64(20); height > 0; height-- {
+		conn.snapshots = append(conn.snapshots, abci.Snapshot{Height: height, Format: 1, Chunks: 1, Hash: []byte("hash")})
+	}
+
+	r := NewReactor(cfg.TestStateSyncConfig(), conn)
+	r.SetLogger(log.NewNoopLogger())
+
+	// Only the recent snapshots are sent
+	sent := receive(t, r, SnapshotChannel, &snapshotsRequestMessage{})
+	require.Len(t, sent, recentSnapshots)
+	for i, msg := range sent {
+		assert.Equal(t, conn.snapshots[i], msg.(*snapshotsResponseMessage).snapshot())
+	}
+}
+
+func TestReactor_RespondChunk(t *testing.T) {
+	t.Parallel()
+
+	conn := &mockSnapshotConn{chunks: map[uint32][]byte{0: []byte("chunk")}}
+	r := NewReactor(cfg.TestStateSyncConfig(), conn)
+	r.SetLogger(log.NewNoopLogger())
+
+	sent := receive(t, r, ChunkChannel, &chunkRequestMessage{Height: 1, Format: 1, Index: 0})
+	assert.Equal(t, []StateSyncMessage{
+		&chunkResponseMessage{Height: 1, Format: 1, Index: 0, Chunk: []byte("chunk")},
+	}, sent)
+
+	sent = receive(t, r, ChunkChannel, &chunkRequestMessage{Height: 1, Format: 1, Index: 1})
+	assert.Equal(t, []StateSyncMessage{
+		&chunkResponseMessage{Height: 1, Format: 1, Index: 1, Missing: true},
+	}, sent)
+}
+
+func TestMessagesValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		testName  string
+		msg       StateSyncMessage
+		expectErr bool
+	}{
+		{"Valid Snapshots Request Message", &snapshotsRequestMessage{}, false},
+		{"Valid Snapshots Response Message", &snapshotsResponseMessage{Height: 1, Chunks: 1, Hash: []byte("hash")}, false},
+		{"Invalid Snapshots Response Message height", &snapshotsResponseMessage{Height: 0, Chunks: 1, Hash: []byte("hash")}, true},
+		{"Invalid Snapshots Response Message chunks", &snapshotsResponseMessage{Height: 1, Chunks: 0, Hash: []byte("hash")}, true},
+		{"Invalid Snapshots Response Message hash", &snapshotsResponseMessage{Height: 1, Chunks: 1}, true},
+		{"Valid Chunk Request Message", &chunkRequestMessage{Height: 1}, false},
+		{"Invalid Chunk Request Message", &chunkRequestMessage{Height: -1}, true},
+		{"Valid Chunk Response Message", &chunkResponseMessage{Height: 1, Chunk: []byte("chunk")}, false},
+		{"Valid Missing Chunk Response Message", &chunkResponseMessage{Height: 1, Missing: true}, false},
+		{"Invalid Chunk Response Message height", &chunkResponseMessage{Height: 0, Chunk: []byte("chunk")}, true},
+		{"Invalid Chunk Response Message empty", &chunkResponseMessage{Height: 1}, true},
+		{"Invalid Chunk Response Message missing", &chunkResponseMessage{Height: 1, Chunk: []byte("chunk"), Missing: true}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.testName, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expectErr, tc.msg.ValidateBasic() != nil, "Validate Basic had an unexpected result")
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package statesync
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	tmver "github.com/gnolang/gno/tm2/pkg/bft/version"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// StateProvider provides the state of the heights of the snapshots, verified
+// from a trusted header.
+type StateProvider interface {
+	// AppHash returns the app hash after the block of the given height is
+	// committed.
+	AppHash(ctx context.Context, height int64) ([]byte, error)
+
+	// Commit returns the commit of the block of the given height.
+	Commit(ctx context.Context, height int64) (*types.Commit, error)
+
+	// State returns the state after the block of the given height is
+	// committed.
+	State(ctx context.Context, height int64) (sm.State, error)
+}
+
+// rpcClient is the subset of the RPC client used by the state provider.
+type rpcClient interface {
+	client.SignClient
+	ConsensusParams(ctx context.Context, height *int64) (*ctypes.ResultConsensusParams, error)
+}
+
+// rpcStateProvider is a StateProvider fetching the headers from RPC servers,
+// verified by a light client from the trusted header. The headers are
+// fetched from the primary server, and cross-checked with the other ones.
+type rpcStateProvider struct {
+	chainID      string
+	trustOptions light.TrustOptions
+	now          func() time.Time
+
+	primary   rpcClient
+	witnesses []rpcClient
+
+	mtx sync.Mutex
+	lc  *light.Client // created on first use, fetching the trusted header
+}
+
+// NewRPCStateProvider returns a StateProvider fetching the headers from the
+// given RPC servers, the first one being the primary one, and verifying them
+// from the trusted header of the given height and hash, within the trusting
+// period.
+func NewRPCStateProvider(
+	chainID string,
+	servers []string,
+	trustPeriod time.Duration,
+	trustHeight int64,
+	trustHash []byte,
+) (StateProvider, error) {
+	if len(servers) == 0 {
+		return nil, errors.New("at least one RPC server is required")
+	}
+
+	clients := make([]rpcClient, 0, len(servers))
+	for _, server := range servers {
+		c, err := client.NewHTTPClient(server)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create RPC client for %s: %w", server, err)
+		}
+		clients = append(clients, c)
+	}
+
+	return newRPCStateProvider(chainID, clients[0], clients[1:], trustPeriod, trustHeight, trustHash), nil
+}
+
+func newRPCStateProvider(
+	chainID string,
+	primary rpcClient,
+	witnesses []rpcClient,
+	trustPeriod time.Duration,
+	trustHeight int64,
+	trustHash []byte,
+) *rpcStateProvider {
+	return &rpcStateProvider{
+		chainID: chainID,
+		trustOptions: light.TrustOptions{
+			Period: trustPeriod,
+			Height: trustHeight,
+			Hash:   trustHash,
+		},
+		now:       time.Now,
+		primary:   primary,
+		witnesses: witnesses,
+	}
+}
+
+// AppHash implements StateProvider.
+func (p *rpcStateProvider) AppHash(ctx context.Context, height int64) ([]byte, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	// The app hash of a height is in the header of the next one
+	next, err := p.verify(ctx, height+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return next.AppHash, nil
+}
+
+// Commit implements StateProvider.
+func (p *rpcStateProvider) Commit(ctx context.Context, height int64) (*types.Commit, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	_, last, err := p.verifyWithLast(ctx, height+1)
+	if err != nil {
+		return nil, err
+	}
+
+	return last.Commit, nil
+}
+
+// State implements StateProvider.
+func (p *rpcStateProvider) State(ctx context.Context, height int64) (sm.State, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	next, last, err := p.verifyWithLast(ctx, height+1)
+	if err != nil {
+		return sm.State{}, err
+	}
+
+	nextHeight := height + 1
+	res, err := p.primary.ConsensusParams(ctx, &nextHeight)
+	if err != nil {
+		return sm.State{}, fmt.Errorf("unable to fetch consensus params %d: %w", nextHeight, err)
+	}
+	if !bytes.Equal(res.ConsensusParams.Hash(), next.ConsensusHash) {
+		return sm.State{}, fmt.Errorf("consensus params %d do not match the header consensus hash %X",
+			nextHeight, next.ConsensusHash)
+	}
+
+	return sm.State{
+		SoftwareVersion: tmver.Version,
+		BlockVersion:    next.Version,
+		AppVersion:      next.AppVersion,
+		ChainID:         p.chainID,
+
+		LastBlockHeight:  height,
+		LastBlockTotalTx: last.TotalTxs,
+		LastBlockID:      next.LastBlockID,
+		LastBlockTime:    last.Time,
+
+		NextValidators: next.NextValidatorSet,
+		Validators:     next.ValidatorSet,
+		LastValidators: last.ValidatorSet,
+
+		ConsensusParams: res.ConsensusParams,
+
+		LastResultsHash: next.LastResultsHash,
+		AppHash:         next.AppHash,
+	}, nil
+}
+
+// verify returns the light block of the given height, verified by the light
+// client.
+func (p *rpcStateProvider) verify(ctx context.Context, height int64) (*light.LightBlock, error) {
+	if p.lc == nil {
+		primary := light.NewProvider(p.chainID, p.primary)
+		witnesses := make([]light.Provider, 0, len(p.witnesses))
+		for _, witness := range p.witnesses {
+			witnesses = append(witnesses, light.NewProvider(p.chainID, witness))
+		}
+
+		lc, err := light.NewClient(ctx, p.chainID, p.trustOptions, primary,
+			light.NewDBStore(memdb.NewMemDB()), light.Witnesses(witnesses...))
+		if err != nil {
+			return nil, err
+		}
+		p.lc = lc
+	}
+
+	return p.lc.VerifyLightBlockAtHeight(ctx, height, p.now())
+}
+
+// verifyWithLast returns the verified light block of the given height, and
+// the one preceding it, whose commit is verified as well.
+func (p *rpcStateProvider) verifyWithLast(ctx context.Context, height int64) (next, last *light.LightBlock, err error) {
+	if next, err = p.verify(ctx, height); err != nil {
+		return nil, nil, err
+	}
+	if last, err = p.verify(ctx, height-1); err != nil {
+		return nil, nil, err
+	}
+
+	// The light blocks verified backwards are only chained by hash
+	if err := last.ValidatorSet.VerifyCommit(p.chainID, next.LastBlockID, last.Height, last.Commit); err != nil {
+		return nil, nil, fmt.Errorf("invalid commit %d: %w", last.Height, err)
+	}
+
+	return next, last, nil
+}
@@ -0,0 +1,244 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/light"
+	"github.com/gnolang/gno/tm2/pkg/bft/rpc/client"
+	ctypes "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+)
+
+const (
+	testChainID     = "test-chain"
+	testTrustPeriod = time.Hour
+)
+
+// testChain is a chain of signed headers, whose validator set is entirely
+// replaced at the given height.
+type testChain struct {
+	headers map[int64]types.SignedHeader
+	vals    map[int64]*types.ValidatorSet
+	params  abci.ConsensusParams
+}
+
+func newTestChain(t *testing.T, height, valsChange int64) *testChain {
+	t.Helper()
+
+	oldVals, oldPrivVals := types.RandValidatorSet(4, 10)
+	newVals, newPrivVals := types.RandValidatorSet(4, 10)
+
+	chain := &testChain{
+		headers: make(map[int64]types.SignedHeader),
+		vals:    make(map[int64]*types.ValidatorSet),
+		params:  types.DefaultConsensusParams(),
+	}
+
+	valsAt := func(h int64) (*types.ValidatorSet, []types.PrivValidator) {
+		if h >= valsChange {
+			return newVals, newPrivVals
+		}
+		return oldVals, oldPrivVals
+	}
+
+	// The headers are within the trusting period
+	start := time.Now().UTC().Add(-testTrustPeriod / 2)
+
+	var lastBlockID types.BlockID
+	for h := int64(1); h <= height+1; h++ {
+		vals, privVals := valsAt(h)
+		nextVals, _ := valsAt(h + 1)
+		chain.vals[h] = vals
+		chain.vals[h+1] = nextVals
+
+		header := &types.Header{
+			Version:            "1",
+			ChainID:            testChainID,
+			Height:             h,
+			Time:               start.Add(time.Duration(h) * time.Second),
+			TotalTxs:           h,
+			LastBlockID:        lastBlockID,
+			ValidatorsHash:     vals.Hash(),
+			NextValidatorsHash: nextVals.Hash(),
+			ConsensusHash:      chain.params.Hash(),
+			AppHash:            fmt.Appendf(nil, "app hash %d", h),
+			LastResultsHash:    fmt.Appendf(nil, "results hash %d", h),
+			ProposerAddress:    vals.Validators[0].Address,
+		}
+		blockID := types.BlockID{Hash: header.Hash()}
+
+		voteSet := types.NewVoteSet(testChainID, h, 0, types.PrecommitType, vals)
+		commit, err := types.MakeCommit(blockID, h, 0, voteSet, privVals)
+		require.NoError(t, err)
+
+		chain.headers[h] = types.SignedHeader{Header: header, Commit: commit}
+		lastBlockID = blockID
+	}
+
+	return chain
+}
+
+// mockRPCClient serves the headers of a chain, and counts the requested
+// headers.
+type mockRPCClient struct {
+	client.Client
+
+	chain    *testChain
+	requests int
+}
+
+func (c *mockRPCClient) Commit(_ context.Context, height *int64) (*ctypes.ResultCommit, error) {
+	c.requests++
+
+	sh, ok := c.chain.headers[*height]
+	if !ok {
+		return nil, errors.New("height not available")
+	}
+	return &ctypes.ResultCommit{SignedHeader: sh, CanonicalCommit: true}, nil
+}
+
+func (c *mockRPCClient) Validators(_ context.Context, height *int64) (*ctypes.ResultValidators, error) {
+	vals, ok := c.chain.vals[*height]
+	if !ok {
+		return nil, errors.New("height not available")
+	}
+	return &ctypes.ResultValidators{BlockHeight: *height, Validators: vals.Copy().Validators}, nil
+}
+
+func (c *mockRPCClient) ConsensusParams(_ context.Context, height *int64) (*ctypes.ResultConsensusParams, error) {
+	return &ctypes.ResultConsensusParams{BlockHeight: *height, ConsensusParams: c.chain.params}, nil
+}
+
+func TestRPCStateProvider(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 100, 50)
+	primary := &mockRPCClient{chain: chain}
+	witness := &mockRPCClient{chain: chain}
+	p := newRPCStateProvider(testChainID, primary, []rpcClient{witness}, testTrustPeriod, 10, chain.headers[10].Hash())
+
+	ctx := context.Background()
+
+	appHash, err := p.AppHash(ctx, 80)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[81].AppHash, appHash)
+
+	// The validator set change is verified by bisection, skipping most of
+	// the headers. The trusted and the verified headers are cross-checked
+	// with the witness.
+	assert.Less(t, primary.requests, 20)
+	assert.Equal(t, 2, witness.requests)
+
+	commit, err := p.Commit(ctx, 80)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[80].Commit.Hash(), commit.Hash())
+
+	state, err := p.State(ctx, 80)
+	require.NoError(t, err)
+	assert.Equal(t, testChainID, state.ChainID)
+	assert.Equal(t, int64(80), state.LastBlockHeight)
+	assert.Equal(t, int64(80), state.LastBlockTotalTx)
+	assert.Equal(t, chain.headers[81].LastBlockID, state.LastBlockID)
+	assert.Equal(t, chain.headers[80].Time, state.LastBlockTime)
+	assert.Equal(t, chain.headers[80].ValidatorsHash, state.LastValidators.Hash())
+	assert.Equal(t, chain.headers[81].ValidatorsHash, state.Validators.Hash())
+	assert.Equal(t, chain.headers[81].NextValidatorsHash, state.NextValidators.Hash())
+	assert.Equal(t, chain.params, state.ConsensusParams)
+	assert.Equal(t, chain.headers[81].LastResultsHash, state.LastResultsHash)
+	assert.Equal(t, chain.headers[81].AppHash, state.AppHash)
+
+	// The heights below the verified one are verified backwards
+	appHash, err = p.AppHash(ctx, 30)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[31].AppHash, appHash)
+
+	appHash, err = p.AppHash(ctx, 5)
+	require.NoError(t, err)
+	assert.Equal(t, chain.headers[6].AppHash, appHash)
+
+	_, err = p.AppHash(ctx, 101)
+	assert.Error(t, err, "expecting an error above the chain height")
+}
+
+func TestRPCStateProvider_Invalid(t *testing.T) {
+	t.Parallel()
+
+	chain := newTestChain(t, 20, 50)
+	ctx := context.Background()
+
+	t.Run("untrusted hash", func(t *testing.T) {
+		t.Parallel()
+
+		p := newRPCStateProvider(testChainID, &mockRPCClient{chain: chain}, nil, testTrustPeriod, 10, chain.headers[11].Hash())
+		_, err := p.AppHash(ctx, 15)
+		assert.ErrorContains(t, err, "does not match the trusted hash")
+	})
+
+	t.Run("wrong chain", func(t *testing.T) {
+		t.Parallel()
+
+		p := newRPCStateProvider("other-chain", &mockRPCClient{chain: chain}, nil, testTrustPeriod, 10, chain.headers[10].Hash())
+		_, err := p.AppHash(ctx, 15)
+		assert.Error(t, err)
+	})
+
+	t.Run("forged header", func(t *testing.T) {
+		t.Parallel()
+
+		// A header signed by other validators, along with their validator
+		// sets
+		other := newTestChain(t, 20, 0)
+		forged := &testChain{
+			headers: make(map[int64]types.SignedHeader),
+			vals:    make(map[int64]*types.ValidatorSet),
+			params:  chain.params,
+		}
+		maps.Copy(forged.headers, chain.headers)
+		maps.Copy(forged.vals, chain.vals)
+		forged.headers[16] = other.headers[16]
+		forged.vals[16] = other.vals[16]
+		forged.vals[17] = other.vals[17]
+
+		p := newRPCStateProvider(testChainID, &mockRPCClient{chain: forged}, nil, testTrustPeriod, 10, chain.headers[10].Hash())
+		_, err := p.AppHash(ctx, 15)
+		assert.Error(t, err)
+	})
+
+	t.Run("witness mismatch", func(t *testing.T) {
+		t.Parallel()
+
+		other := newTestChain(t, 20, 50)
+		p := newRPCStateProvider(testChainID, &mockRPCClient{chain: chain},
+			[]rpcClient{&mockRPCClient{chain: other}}, testTrustPeriod, 10, chain.headers[10].Hash())
+		_, err := p.AppHash(ctx, 15)
+		assert.ErrorIs(t, err, light.ErrConflictingHeaders)
+	})
+
+	t.Run("expired trusted header", func(t *testing.T) {
+		t.Parallel()
+
+		p := newRPCStateProvider(testChainID, &mockRPCClient{chain: chain}, nil, testTrustPeriod, 10, chain.headers[10].Hash())
+		p.now = func() time.Time { return chain.headers[10].Time.Add(testTrustPeriod) }
+		_, err := p.AppHash(ctx, 15)
+		assert.ErrorIs(t, err, light.ErrOldHeaderExpired)
+	})
+
+	t.Run("wrong consensus params", func(t *testing.T) {
+		t.Parallel()
+
+		wrong := *chain
+		wrong.params.Block = &abci.BlockParams{MaxTxBytes: 1, MaxDataBytes: 1, MaxBlockBytes: 1, MaxGas: 1, TimeIotaMS: 1}
+		p := newRPCStateProvider(testChainID, &mockRPCClient{chain: &wrong}, nil, testTrustPeriod, 10, chain.headers[10].Hash())
+		_, err := p.State(ctx, 15)
+		assert.ErrorContains(t, err, "consensus params")
+	})
+}
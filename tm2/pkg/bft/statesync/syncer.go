@@ -0,0 +1,399 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/appconn"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+var (
+	errRejectSnapshot = errors.New("snapshot rejected")
+	errNoChunkPeers   = errors.New("no peer provided the snapshot chunk")
+	errChunkTimeout   = errors.New("snapshot chunk request timed out")
+)
+
+// syncer discovers the snapshots of the peers, and restores the application
+// from the best one, fetching its chunks from the peers which advertised it.
+type syncer struct {
+	logger        *slog.Logger
+	conn          appconn.Snapshot
+	stateProvider StateProvider
+	discoveryTime time.Duration
+	chunkTimeout  time.Duration
+
+	requestSnapshots func()
+	requestChunk     func(peerID p2pTypes.ID, snapshot abci.Snapshot, index uint32) bool
+
+	chunks chan chunkResponse // responses to the chunk being fetched
+
+	mtx           sync.Mutex
+	snapshots     map[string]*snapshotPeers // by snapshot key
+	rejected      map[string]bool           // rejected snapshots, by key
+	rejectedPeers map[p2pTypes.ID]bool
+	fetching      *chunkResponse // chunk being fetched, without its content
+}
+
+// snapshotPeers is a snapshot, along with the peers which advertised it.
+type snapshotPeers struct {
+	snapshot abci.Snapshot
+	peers    []p2pTypes.ID
+}
+
+// chunkResponse is a chunk of a snapshot sent by a peer.
+type chunkResponse struct {
+	peerID p2pTypes.ID
+	*chunkResponseMessage
+}
+
+func newSyncer(
+	config *cfg.StateSyncConfig,
+	logger *slog.Logger,
+	conn appconn.Snapshot,
+	stateProvider StateProvider,
+	requestSnapshots func(),
+	requestChunk func(peerID p2pTypes.ID, snapshot abci.Snapshot, index uint32) bool,
+) *syncer {
+	return &syncer{
+		logger:           logger,
+		conn:             conn,
+		stateProvider:    stateProvider,
+		discoveryTime:    config.DiscoveryTime,
+		chunkTimeout:     config.ChunkTimeout,
+		requestSnapshots: requestSnapshots,
+		requestChunk:     requestChunk,
+		chunks:           make(chan chunkResponse, 1),
+		snapshots:        make(map[string]*snapshotPeers),
+		rejected:         make(map[string]bool),
+		rejectedPeers:    make(map[p2pTypes.ID]bool),
+	}
+}
+
+// snapshotKey identifies a snapshot by its whole content, as peers can
+// advertise different snapshots with the same height and hash.
+func snapshotKey(snapshot abci.Snapshot) string {
+	return string(amino.MustMarshal(snapshot))
+}
+
+// addSnapshot adds a snapshot advertised by a peer.
+func (s *syncer) addSnapshot(peerID p2pTypes.ID, snapshot abci.Snapshot) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := snapshotKey(snapshot)
+	if s.rejectedPeers[peerID] || s.rejected[key] {
+		return
+	}
+
+	sp := s.snapshots[key]
+	if sp == nil {
+		sp = &snapshotPeers{snapshot: snapshot}
+		s.snapshots[key] = sp
+
+		s.logger.Info("Discovered snapshot",
+			"height", snapshot.Height,
+			"format", snapshot.Format,
+			"hash", fmt.Sprintf("%X", snapshot.Hash),
+		)
+	}
+	if !slices.Contains(sp.peers, peerID) {
+		sp.peers = append(sp.peers, peerID)
+	}
+}
+
+// removePeer removes the peer from the peers of the snapshots.
+func (s *syncer) removePeer(peerID p2pTypes.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	for _, sp := range s.snapshots {
+		sp.peers = slices.DeleteFunc(sp.peers, func(id p2pTypes.ID) bool { return id == peerID })
+	}
+}
+
+// removeSnapshotPeer removes the peer from the peers of the snapshot.
+func (s *syncer) removeSnapshotPeer(snapshot abci.Snapshot, peerID p2pTypes.ID) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if sp := s.snapshots[snapshotKey(snapshot)]; sp != nil {
+		sp.peers = slices.DeleteFunc(sp.peers, func(id p2pTypes.ID) bool { return id == peerID })
+	}
+}
+
+// rejectPeer ignores the peer for the rest of the sync.
+func (s *syncer) rejectPeer(peerID p2pTypes.ID) {
+	s.mtx.Lock()
+	s.rejectedPeers[peerID] = true
+	s.mtx.Unlock()
+
+	s.removePeer(peerID)
+}
+
+// rejectSnapshot ignores the snapshot for the rest of the sync.
+func (s *syncer) rejectSnapshot(snapshot abci.Snapshot) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	key := snapshotKey(snapshot)
+	s.rejected[key] = true
+	delete(s.snapshots, key)
+}
+
+// best returns the best snapshot which has peers: the highest one, then the
+// one with the most peers.
+func (s *syncer) best() (abci.Snapshot, bool) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var best *snapshotPeers
+	for _, sp := range s.snapshots {
+		switch {
+		case len(sp.peers) == 0:
+		case best == nil,
+			sp.snapshot.Height > best.snapshot.Height,
+			sp.snapshot.Height == best.snapshot.Height && len(sp.peers) > len(best.peers):
+			best = sp
+		}
+	}
+	if best == nil {
+		return abci.Snapshot{}, false
+	}
+
+	return best.snapshot, true
+}
+
+// syncAny discovers snapshots and restores the best one, until one is
+// restored, or the context is done.
+func (s *syncer) syncAny(ctx context.Context) (sm.State, *types.Commit, error) {
+	for {
+		s.logger.Info("Discovering snapshots", "time", s.discoveryTime)
+		s.requestSnapshots()
+
+		select {
+		case <-ctx.Done():
+			return sm.State{}, nil, ctx.Err()
+		case <-time.After(s.discoveryTime):
+		}
+
+		for {
+			snapshot, ok := s.best()
+			if !ok {
+				break
+			}
+
+			state, commit, err := s.sync(ctx, snapshot)
+			if errors.Is(err, errRejectSnapshot) {
+				s.logger.Info("Snapshot rejected", "height", snapshot.Height, "format", snapshot.Format, "err", err)
+				s.rejectSnapshot(snapshot)
+				continue
+			}
+
+			return state, commit, err
+		}
+
+		s.logger.Info("No suitable snapshot found")
+	}
+}
+
+// sync restores the application from the given snapshot. It returns an
+// errRejectSnapshot error if another snapshot can be restored instead,
+// before the application applied any of its chunks.
+func (s *syncer) sync(ctx context.Context, snapshot abci.Snapshot) (sm.State, *types.Commit, error) {
+	// The state is verified before restoring the snapshot, which can't be
+	// undone
+	appHash, err := s.stateProvider.AppHash(ctx, snapshot.Height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: unable to verify the app hash: %w", errRejectSnapshot, err)
+	}
+	state, err := s.stateProvider.State(ctx, snapshot.Height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: unable to verify the state: %w", errRejectSnapshot, err)
+	}
+	commit, err := s.stateProvider.Commit(ctx, snapshot.Height)
+	if err != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: unable to verify the commit: %w", errRejectSnapshot, err)
+	}
+
+	res, err := s.conn.OfferSnapshotSync(abci.RequestOfferSnapshot{
+		Snapshot: &snapshot,
+		AppHash:  appHash,
+	})
+	if err != nil {
+		return sm.State{}, nil, err
+	}
+	if res.Error != nil {
+		return sm.State{}, nil, fmt.Errorf("%w: %w", errRejectSnapshot, res.Error)
+	}
+
+	s.logger.Info("Restoring snapshot",
+		"height", snapshot.Height,
+		"format", snapshot.Format,
+		"chunks", snapshot.Chunks,
+		"hash", fmt.Sprintf("%X", snapshot.Hash),
+	)
+
+	for index := uint32(0); index < snapshot.Chunks; {
+		chunk, sender, err := s.fetchChunk(ctx, snapshot, index)
+		if err != nil {
+			// Nothing is restored before the first chunk is applied
+			if index == 0 && ctx.Err() == nil {
+				err = fmt.Errorf("%w: %w", errRejectSnapshot, err)
+			}
+			return sm.State{}, nil, fmt.Errorf("unable to fetch snapshot chunk %d: %w", index, err)
+		}
+
+		res, err := s.conn.ApplySnapshotChunkSync(abci.RequestApplySnapshotChunk{
+			Index:  index,
+			Chunk:  chunk,
+			Sender: string(sender),
+		})
+		if err != nil {
+			return sm.State{}, nil, err
+		}
+		for _, peerID := range res.RejectSenders {
+			s.rejectPeer(p2pTypes.ID(peerID))
+		}
+		if slices.Contains(res.RefetchChunks, index) {
+			s.logger.Info("Refetching snapshot chunk", "index", index, "err", res.Error)
+			continue
+		}
+		if res.Error != nil {
+			return sm.State{}, nil, fmt.Errorf("unable to apply snapshot chunk %d: %w", index, res.Error)
+		}
+
+		s.logger.Debug("Applied snapshot chunk", "index", index, "chunks", snapshot.Chunks)
+		index++
+	}
+
+	s.logger.Info("Restored snapshot", "height", snapshot.Height, "app_hash", fmt.Sprintf("%X", appHash))
+
+	return state, commit, nil
+}
+
+// fetchChunk fetches a chunk of the snapshot from the peers which advertised
+// it, in turn, until one of them sends it in time. The peers which don't are
+// not asked for the other chunks of the snapshot.
+func (s *syncer) fetchChunk(ctx context.Context, snapshot abci.Snapshot, index uint32) ([]byte, p2pTypes.ID, error) {
+	for {
+		peers := s.snapshotPeers(snapshot)
+		if len(peers) == 0 {
+			return nil, "", errNoChunkPeers
+		}
+
+		// Spread the chunk requests over the peers
+		peerID := peers[int(index)%len(peers)]
+		chunk, err := s.fetchChunkFrom(ctx, peerID, snapshot, index)
+		if err == nil {
+			return chunk, peerID, nil
+		}
+		if ctx.Err() != nil {
+			return nil, "", ctx.Err()
+		}
+
+		s.logger.Info("Unable to fetch snapshot chunk", "peer", peerID, "index", index, "err", err)
+		s.removeSnapshotPeer(snapshot, peerID)
+	}
+}
+
+// snapshotPeers returns the peers of the snapshot.
+func (s *syncer) snapshotPeers(snapshot abci.Snapshot) []p2pTypes.ID {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if sp := s.snapshots[snapshotKey(snapshot)]; sp != nil {
+		return slices.Clone(sp.peers)
+	}
+
+	return nil
+}
+
+// fetchChunkFrom requests a chunk of the snapshot from the peer, and waits
+// for its response.
+func (s *syncer) fetchChunkFrom(ctx context.Context, peerID p2pTypes.ID, snapshot abci.Snapshot, index uint32) ([]byte, error) {
+	expected := chunkResponse{
+		peerID: peerID,
+		chunkResponseMessage: &chunkResponseMessage{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Index:  index,
+		},
+	}
+
+	s.mtx.Lock()
+	// Drop the late response to a previous request, if any
+	select {
+	case <-s.chunks:
+	default:
+	}
+	s.fetching = &expected
+	s.mtx.Unlock()
+
+	defer func() {
+		s.mtx.Lock()
+		s.fetching = nil
+		s.mtx.Unlock()
+	}()
+
+	if !s.requestChunk(peerID, snapshot, index) {
+		return nil, errors.New("unable to send the chunk request")
+	}
+
+	timer := time.NewTimer(s.chunkTimeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+
+	case <-timer.C:
+		return nil, errChunkTimeout
+
+	case res := <-s.chunks:
+		if res.Missing {
+			return nil, errors.New("peer does not have the chunk")
+		}
+
+		return res.Chunk, nil
+	}
+}
+
+// addChunk passes the chunk sent by a peer to the chunk request waiting for
+// it, if any.
+func (s *syncer) addChunk(peerID p2pTypes.ID, msg *chunkResponseMessage) {
+	res := chunkResponse{peerID: peerID, chunkResponseMessage: msg}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	if s.fetching == nil || !s.fetching.matches(res) {
+		s.logger.Debug("Dropping unexpected snapshot chunk", "peer", peerID, "height", msg.Height, "index", msg.Index)
+		return
+	}
+	s.fetching = nil
+
+	select {
+	case s.chunks <- res:
+	default:
+	}
+}
+
+// matches returns whether the response is for the same chunk, from the same
+// peer.
+func (r chunkResponse) matches(other chunkResponse) bool {
+	return r.peerID == other.peerID &&
+		r.Height == other.Height &&
+		r.Format == other.Format &&
+		r.Index == other.Index
+}
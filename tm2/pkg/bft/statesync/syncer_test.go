@@ -0,0 +1,274 @@
+package statesync
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	sm "github.com/gnolang/gno/tm2/pkg/bft/state"
+	cfg "github.com/gnolang/gno/tm2/pkg/bft/statesync/config"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/log"
+	p2pTypes "github.com/gnolang/gno/tm2/pkg/p2p/types"
+)
+
+// mockSnapshotConn is an application restoring snapshots, which can reject
+// some of their chunks.
+type mockSnapshotConn struct {
+	snapshots []abci.Snapshot   // listed snapshots
+	chunks    map[uint32][]byte // loaded chunks, by index
+
+	mtx     sync.Mutex
+	offered []abci.Snapshot
+	applied [][]byte
+
+	offerErr  map[int64]abci.Error                                                 // by snapshot height
+	applyFunc func(abci.RequestApplySnapshotChunk) abci.ResponseApplySnapshotChunk // optional
+}
+
+func (c *mockSnapshotConn) Error() error { return nil }
+
+func (c *mockSnapshotConn) ListSnapshotsSync(abci.RequestListSnapshots) (abci.ResponseListSnapshots, error) {
+	return abci.ResponseListSnapshots{Snapshots: c.snapshots}, nil
+}
+
+func (c *mockSnapshotConn) OfferSnapshotSync(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot, err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.offered = append(c.offered, *req.Snapshot)
+	res.Error = c.offerErr[req.Snapshot.Height]
+	return
+}
+
+func (c *mockSnapshotConn) LoadSnapshotChunkSync(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk, err error) {
+	chunk, ok := c.chunks[req.Chunk]
+	if !ok {
+		res.Error = abci.StringError("chunk not found")
+	}
+	res.Chunk = chunk
+	return
+}
+
+func (c *mockSnapshotConn) ApplySnapshotChunkSync(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk, err error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.applyFunc != nil {
+		if res = c.applyFunc(req); res.Error != nil {
+			return
+		}
+	}
+	c.applied = append(c.applied, req.Chunk)
+	return
+}
+
+// mockStateProvider provides the states of the given heights.
+type mockStateProvider struct {
+	states map[int64]sm.State
+}
+
+func (p *mockStateProvider) AppHash(_ context.Context, height int64) ([]byte, error) {
+	state, ok := p.states[height]
+	if !ok {
+		return nil, errors.New("no state")
+	}
+	return state.AppHash, nil
+}
+
+func (p *mockStateProvider) Commit(_ context.Context, height int64) (*types.Commit, error) {
+	if _, ok := p.states[height]; !ok {
+		return nil, errors.New("no state")
+	}
+	return types.NewCommit(types.BlockID{}, nil), nil
+}
+
+func (p *mockStateProvider) State(_ context.Context, height int64) (sm.State, error) {
+	state, ok := p.states[height]
+	if !ok {
+		return sm.State{}, errors.New("no state")
+	}
+	return state, nil
+}
+
+// mockPeers are peers serving the chunks of snapshots, some of them missing.
+type mockPeers struct {
+	mtx       sync.Mutex
+	chunks    map[p2pTypes.ID]map[uint32][]byte // by peer, by index
+	requested []p2pTypes.ID
+}
+
+func newTestSyncer(t *testing.T, conn *mockSnapshotConn, provider StateProvider, peers *mockPeers) *syncer {
+	t.Helper()
+
+	config := cfg.TestStateSyncConfig()
+	config.DiscoveryTime = 10 * time.Millisecond
+	config.ChunkTimeout = 100 * time.Millisecond
+
+	var s *syncer
+	s = newSyncer(config, log.NewNoopLogger(), conn, provider, func() {},
+		func(peerID p2pTypes.ID, snapshot abci.Snapshot, index uint32) bool {
+			peers.mtx.Lock()
+			defer peers.mtx.Unlock()
+
+			peers.requested = append(peers.requested, peerID)
+			chunks, ok := peers.chunks[peerID]
+			if !ok {
+				// The peer never responds
+				return true
+			}
+
+			chunk := chunks[index]
+			go s.addChunk(peerID, &chunkResponseMessage{
+				Height:  snapshot.Height,
+				Format:  snapshot.Format,
+				Index:   index,
+				Chunk:   chunk,
+				Missing: chunk == nil,
+			})
+
+			return true
+		},
+	)
+
+	return s
+}
+
+func TestSyncer_SyncAny(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunks = map[uint32][]byte{0: []byte("chunk 0"), 1: []byte("chunk 1")}
+		peers  = &mockPeers{
+			chunks: map[p2pTypes.ID]map[uint32][]byte{
+				"a": chunks,
+				"b": chunks,
+			},
+		}
+		conn = &mockSnapshotConn{
+			offerErr: map[int64]abci.Error{
+				3: abci.StringError("unsupported snapshot"),
+			},
+		}
+		state    = sm.State{LastBlockHeight: 2, AppHash: []byte("app hash")}
+		provider = &mockStateProvider{states: map[int64]sm.State{2: state, 3: {LastBlockHeight: 3}}}
+		s        = newTestSyncer(t, conn, provider, peers)
+	)
+
+	// The snapshot of height 4 can't be verified, and the application
+	// rejects the snapshot of height 3
+	snapshot := abci.Snapshot{Height: 2, Format: 1, Chunks: 2, Hash: []byte("hash")}
+	s.addSnapshot("a", snapshot)
+	s.addSnapshot("b", snapshot)
+	s.addSnapshot("a", abci.Snapshot{Height: 4, Format: 1, Chunks: 2, Hash: []byte("hash")})
+	s.addSnapshot("b", abci.Snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte("hash")})
+
+	restored, commit, err := s.syncAny(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, state, restored)
+	assert.NotNil(t, commit)
+
+	require.Len(t, conn.offered, 2)
+	assert.Equal(t, int64(3), conn.offered[0].Height)
+	assert.Equal(t, snapshot, conn.offered[1])
+	assert.Equal(t, [][]byte{chunks[0], chunks[1]}, conn.applied)
+
+	// The chunk requests are spread over the peers
+	assert.Equal(t, []p2pTypes.ID{"a", "b"}, peers.requested)
+
+	// Rejected snapshots are not added again
+	s.addSnapshot("a", abci.Snapshot{Height: 3, Format: 1, Chunks: 2, Hash: []byte("hash")})
+	best, ok := s.best()
+	require.True(t, ok)
+	assert.Equal(t, snapshot, best)
+}
+
+func TestSyncer_FetchChunk(t *testing.T) {
+	t.Parallel()
+
+	var (
+		chunks = map[uint32][]byte{0: []byte("chunk 0"), 1: []byte("chunk 1"), 2: []byte("chunk 2")}
+		peers  = &mockPeers{
+			chunks: map[p2pTypes.ID]map[uint32][]byte{
+				"missing":   {},
+				"corrupted": {0: []byte("corrupted"), 1: chunks[1], 2: chunks[2]},
+				"good":      chunks,
+			},
+		}
+		conn = &mockSnapshotConn{
+			applyFunc: func(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+				if string(req.Chunk) == "corrupted" {
+					res.Error = abci.StringError("invalid chunk")
+					res.RefetchChunks = []uint32{req.Index}
+					res.RejectSenders = []string{req.Sender}
+				}
+				return
+			},
+		}
+		state    = sm.State{LastBlockHeight: 2}
+		provider = &mockStateProvider{states: map[int64]sm.State{2: state}}
+		s        = newTestSyncer(t, conn, provider, peers)
+	)
+
+	snapshot := abci.Snapshot{Height: 2, Format: 1, Chunks: 3, Hash: []byte("hash")}
+	for _, peerID := range []p2pTypes.ID{"missing", "timeout", "corrupted", "good"} {
+		s.addSnapshot(peerID, snapshot)
+	}
+
+	_, _, err := s.syncAny(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, [][]byte{chunks[0], chunks[1], chunks[2]}, conn.applied)
+
+	// The peers missing chunks or timing out are dropped, and the sender of
+	// the corrupted chunk is rejected
+	assert.Equal(t, []p2pTypes.ID{"missing", "timeout", "corrupted", "good", "good", "good"}, peers.requested)
+	s.addSnapshot("corrupted", snapshot)
+	assert.Equal(t, []p2pTypes.ID{"good"}, s.snapshotPeers(snapshot))
+}
+
+func TestSyncer_NoChunkPeers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		peers = &mockPeers{
+			chunks: map[p2pTypes.ID]map[uint32][]byte{
+				"a": {0: []byte("chunk 0")},
+			},
+		}
+		conn     = &mockSnapshotConn{}
+		provider = &mockStateProvider{states: map[int64]sm.State{2: {LastBlockHeight: 2}}}
+		s        = newTestSyncer(t, conn, provider, peers)
+	)
+
+	// The sync fails once the first chunk is applied
+	s.addSnapshot("a", abci.Snapshot{Height: 2, Format: 1, Chunks: 2, Hash: []byte("hash")})
+
+	_, _, err := s.syncAny(context.Background())
+	assert.ErrorIs(t, err, errNoChunkPeers)
+	assert.NotErrorIs(t, err, errRejectSnapshot)
+	assert.Len(t, conn.applied, 1)
+}
+
+func TestSyncer_ContextDone(t *testing.T) {
+	t.Parallel()
+
+	var (
+		conn     = &mockSnapshotConn{}
+		provider = &mockStateProvider{}
+		s        = newTestSyncer(t, conn, provider, &mockPeers{})
+	)
+
+	// Snapshots are discovered until the context is done
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	_, _, err := s.syncAny(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Empty(t, conn.offered)
+}
@@ -22,6 +22,8 @@ Currently the precommit signatures are duplicated in the Block parts as
 well as the Commit.  In the future this may change, perhaps by moving
 the Commit data outside the Block. (TODO)
 
+The blocks below the base of the store may have been pruned, see PruneBlocks.
+
 // NOTE: BlockStore methods will panic if they encounter errors
 // deserializing loaded data, indicating probable corruption on disk.
 */
@@ -29,6 +31,7 @@ type BlockStore struct {
 	db dbm.DB
 
 	mtx    sync.RWMutex
+	base   int64
 	height int64
 }
 
@@ -37,11 +40,21 @@ type BlockStore struct {
 func NewBlockStore(db dbm.DB) *BlockStore {
 	bsjson := LoadBlockStoreStateJSON(db)
 	return &BlockStore{
+		base:   bsjson.Base,
 		height: bsjson.Height,
 		db:     db,
 	}
 }
 
+// Base returns the first known contiguous block height, or 0 for an empty
+// block store. The blocks below it have been pruned, except the ones kept
+// by PruneBlocks.
+func (bs *BlockStore) Base() int64 {
+	bs.mtx.RLock()
+	defer bs.mtx.RUnlock()
+	return bs.base
+}
+
 // Height returns the last known contiguous block height.
 func (bs *BlockStore) Height() int64 {
 	bs.mtx.RLock()
@@ -176,17 +189,104 @@ func (bs *BlockStore) SaveBlock(block *types.Block, blockParts *types.PartSet, s
 	bs.db.Set(calcSeenCommitKey(height), seenCommitBytes)
 
 	// Save new BlockStoreStateJSON descriptor
-	BlockStoreStateJSON{Height: height}.Save(bs.db)
-
-	// Done!
 	bs.mtx.Lock()
+	if bs.base == 0 {
+		bs.base = height
+	}
 	bs.height = height
+	BlockStoreStateJSON{Base: bs.base, Height: height}.Save(bs.db)
 	bs.mtx.Unlock()
 
 	// Flush
 	bs.db.SetSync(nil, nil)
 }
 
+// Bootstrap initializes an empty block store at the given height, whose
+// state was restored from a snapshot without its blocks. Only the commit
+// seen for the block at height is saved, and the next saved block must be
+// the following one. Until it is saved, the base of the store is above its
+// height.
+func (bs *BlockStore) Bootstrap(height int64, seenCommit *types.Commit) error {
+	if height <= 0 {
+		return fmt.Errorf("bootstrap height must be greater than 0, got %d", height)
+	}
+	if seenCommit == nil || seenCommit.Height() != height {
+		return fmt.Errorf("bootstrap requires the commit of height %d", height)
+	}
+
+	bs.mtx.Lock()
+	defer bs.mtx.Unlock()
+
+	if bs.height != 0 {
+		return fmt.Errorf("cannot bootstrap a block store at height %d", bs.height)
+	}
+
+	bs.db.Set(calcSeenCommitKey(height), amino.MustMarshal(seenCommit))
+	bs.base = height + 1
+	bs.height = height
+	BlockStoreStateJSON{Base: bs.base, Height: bs.height}.Save(bs.db)
+
+	return nil
+}
+
+// PruneBlocks removes the blocks below retainHeight, with their commits, and
+// sets the base of the store to retainHeight. If keepEvery is positive, the
+// blocks at a height multiple of keepEvery are kept. It returns the number of
+// pruned blocks.
+func (bs *BlockStore) PruneBlocks(retainHeight, keepEvery int64) (int64, error) {
+	if retainHeight <= 0 {
+		return 0, fmt.Errorf("retain height must be greater than 0, got %d", retainHeight)
+	}
+	if height := bs.Height(); retainHeight > height {
+		return 0, fmt.Errorf("cannot prune beyond the latest height %d, got %d", height, retainHeight)
+	}
+	base := bs.Base()
+	if retainHeight <= base {
+		return 0, nil
+	}
+
+	// Write the deletions and the new base atomically, in batches to
+	// bound their size.
+	flush := func(batch dbm.Batch, base int64) {
+		defer batch.Close()
+
+		bs.mtx.Lock()
+		defer bs.mtx.Unlock()
+		bs.base = base
+		batch.Set(blockStoreKey, BlockStoreStateJSON{Base: base, Height: bs.height}.bytes())
+		batch.WriteSync()
+	}
+
+	var pruned int64
+	batch := bs.db.NewBatch()
+	for height := base; height < retainHeight; height++ {
+		if keepEvery > 0 && height%keepEvery == 0 {
+			// The seen commit is a duplicate of the block commit,
+			// which is saved with the next block.
+			batch.Delete(calcSeenCommitKey(height))
+			continue
+		}
+
+		if meta := bs.LoadBlockMeta(height); meta != nil {
+			for i := range meta.BlockID.PartsHeader.Total {
+				batch.Delete(calcBlockPartKey(height, i))
+			}
+		}
+		batch.Delete(calcBlockMetaKey(height))
+		batch.Delete(calcBlockCommitKey(height))
+		batch.Delete(calcSeenCommitKey(height))
+		pruned++
+
+		if pruned%pruneBatchSize == 0 {
+			flush(batch, height+1)
+			batch = bs.db.NewBatch()
+		}
+	}
+	flush(batch, retainHeight)
+
+	return pruned, nil
+}
+
 func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 	if height != bs.Height()+1 {
 		panic(fmt.Sprintf("BlockStore can only save contiguous blocks. Wanted %v, got %v", bs.Height()+1, height))
@@ -197,6 +297,9 @@ func (bs *BlockStore) saveBlockPart(height int64, index int, part *types.Part) {
 
 //-----------------------------------------------------------------------------
 
+// pruneBatchSize is the number of blocks pruned in a single batch.
+const pruneBatchSize = 1000
+
 func calcBlockMetaKey(height int64) []byte {
 	return fmt.Appendf(nil, "H:%v", height)
 }
@@ -219,16 +322,21 @@ var blockStoreKey = []byte("blockStore")
 
 // BlockStoreStateJSON is the block store state JSON structure.
 type BlockStoreStateJSON struct {
+	Base   int64 `json:"base"`
 	Height int64 `json:"height"`
 }
 
 // Save persists the blockStore state to the database as JSON.
 func (bsj BlockStoreStateJSON) Save(db dbm.DB) {
+	db.SetSync(blockStoreKey, bsj.bytes())
+}
+
+func (bsj BlockStoreStateJSON) bytes() []byte {
 	bytes, err := amino.MarshalJSON(bsj)
 	if err != nil {
 		panic(fmt.Sprintf("Could not marshal state bytes: %v", err))
 	}
-	db.SetSync(blockStoreKey, bytes)
+	return bytes
 }
 
 // LoadBlockStoreStateJSON returns the BlockStoreStateJSON as loaded from disk.
@@ -245,5 +353,9 @@ func LoadBlockStoreStateJSON(db dbm.DB) BlockStoreStateJSON {
 	if err != nil {
 		panic(fmt.Sprintf("Could not unmarshal bytes: %X", bytes))
 	}
+	// Block stores saved before the base was introduced are not pruned.
+	if bsj.Base == 0 && bsj.Height > 0 {
+		bsj.Base = 1
+	}
 	return bsj
 }
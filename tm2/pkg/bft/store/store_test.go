@@ -63,7 +63,7 @@ func TestLoadBlockStoreStateJSON(t *testing.T) {
 
 	db := memdb.NewMemDB()
 
-	bsj := &BlockStoreStateJSON{Height: 1000}
+	bsj := &BlockStoreStateJSON{Base: 100, Height: 1000}
 	bsj.Save(db)
 
 	retrBSJ := LoadBlockStoreStateJSON(db)
@@ -78,6 +78,7 @@ func TestNewBlockStore(t *testing.T) {
 	db.Set(blockStoreKey, []byte(`{"height": "10000"}`))
 	bs := NewBlockStore(db)
 	require.Equal(t, int64(10000), bs.Height(), "failed to properly parse blockstore")
+	require.Equal(t, int64(1), bs.Base(), "expecting the base of a legacy blockstore to be 1")
 
 	panicCausers := []struct {
 		data    []byte
@@ -102,6 +103,7 @@ func TestNewBlockStore(t *testing.T) {
 	db.Set(blockStoreKey, nil)
 	bs = NewBlockStore(db)
 	assert.Equal(t, bs.Height(), int64(0), "expecting nil bytes to be unmarshalled alright")
+	assert.Equal(t, bs.Base(), int64(0), "expecting nil bytes to be unmarshalled alright")
 }
 
 func freshBlockStore() (*BlockStore, dbm.DB) {
@@ -421,6 +423,101 @@ func TestBlockFetchAtHeight(t *testing.T) {
 	require.Nil(t, blockAtHeightPlus2, "expecting an unsuccessful load of Height()+2")
 }
 
+func TestPruneBlocks(t *testing.T) {
+	t.Parallel()
+
+	state, bs, cleanup := makeStateAndBlockStore(log.NewNoopLogger())
+	defer cleanup()
+
+	_, err := bs.PruneBlocks(1, 0)
+	require.Error(t, err, "expecting an error when pruning an empty store")
+
+	// Save 1500 blocks, to prune more than a batch
+	for height := int64(1); height <= 1500; height++ {
+		block := makeBlock(height, state, new(types.Commit))
+		bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(height, tmtime.Now()))
+	}
+	require.Equal(t, int64(1), bs.Base())
+	require.Equal(t, int64(1500), bs.Height())
+
+	_, err = bs.PruneBlocks(0, 0)
+	require.Error(t, err)
+	_, err = bs.PruneBlocks(1501, 0)
+	require.Error(t, err)
+
+	// Keep every 500th block
+	pruned, err := bs.PruneBlocks(1200, 500)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1197), pruned)
+	assert.Equal(t, int64(1200), bs.Base())
+	assert.Equal(t, int64(1500), bs.Height())
+
+	for _, height := range []int64{1, 499, 501, 1199} {
+		assert.Nil(t, bs.LoadBlockMeta(height), "height %d", height)
+		assert.Nil(t, bs.LoadBlock(height), "height %d", height)
+		assert.Nil(t, bs.LoadBlockPart(height, 0), "height %d", height)
+		assert.Nil(t, bs.LoadBlockCommit(height), "height %d", height)
+		assert.Nil(t, bs.LoadSeenCommit(height), "height %d", height)
+	}
+	for _, height := range []int64{500, 1000, 1200, 1500} {
+		assert.NotNil(t, bs.LoadBlock(height), "height %d", height)
+	}
+	assert.Nil(t, bs.LoadSeenCommit(500), "the seen commits of kept blocks are pruned")
+	assert.NotNil(t, bs.LoadSeenCommit(1200))
+
+	// Pruning below the base does nothing
+	pruned, err = bs.PruneBlocks(1100, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), pruned)
+	assert.Equal(t, int64(1200), bs.Base())
+
+	// The base is persisted, and new blocks can be saved
+	bs = NewBlockStore(bs.db)
+	assert.Equal(t, int64(1200), bs.Base())
+	block := makeBlock(1501, state, new(types.Commit))
+	bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(1501, tmtime.Now()))
+	assert.Equal(t, int64(1200), bs.Base())
+	assert.Equal(t, int64(1501), bs.Height())
+
+	pruned, err = bs.PruneBlocks(1501, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(301), pruned)
+	assert.Equal(t, int64(1501), bs.Base())
+	assert.NotNil(t, bs.LoadBlock(1000))
+	assert.NotNil(t, bs.LoadBlock(1501))
+}
+
+func TestBootstrap(t *testing.T) {
+	t.Parallel()
+
+	state, bs, cleanup := makeStateAndBlockStore(log.NewNoopLogger())
+	defer cleanup()
+
+	require.Error(t, bs.Bootstrap(0, makeTestCommit(0, tmtime.Now())))
+	require.Error(t, bs.Bootstrap(10, nil))
+	require.Error(t, bs.Bootstrap(10, makeTestCommit(9, tmtime.Now())))
+
+	seenCommit := makeTestCommit(10, tmtime.Now())
+	require.NoError(t, bs.Bootstrap(10, seenCommit))
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, int64(10), bs.Height())
+	assert.Nil(t, bs.LoadBlockMeta(10))
+	assert.Equal(t, seenCommit.Hash(), bs.LoadSeenCommit(10).Hash())
+
+	require.Error(t, bs.Bootstrap(10, seenCommit), "expecting an error when bootstrapping a non-empty store")
+
+	// The bootstrap is persisted, and the next block can be saved
+	bs = NewBlockStore(bs.db)
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, int64(10), bs.Height())
+
+	block := makeBlock(11, state, new(types.Commit))
+	bs.SaveBlock(block, block.MakePartSet(2), makeTestCommit(11, tmtime.Now()))
+	assert.Equal(t, int64(11), bs.Base())
+	assert.Equal(t, int64(11), bs.Height())
+	assert.NotNil(t, bs.LoadBlock(11))
+}
+
 func doFn(fn func() (any, error)) (res any, err error, panicErr error) {
 	defer func() {
 		if r := recover(); r != nil {
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"fmt"
 
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
 	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
@@ -12,9 +13,21 @@ import (
 
 const (
 	// MaxEvidenceBytes is a maximum size of any evidence (including amino overhead).
-	MaxEvidenceBytes int64 = 484
+	MaxEvidenceBytes int64 = 588
+	// MaxMultisigEvidenceBytes is a maximum size of any evidence (including amino
+	// overhead), of a multisig validator with the most keys.
+	MaxMultisigEvidenceBytes int64 = 3514
 )
 
+// MaxEvidenceBytesForParams returns the maximum size of any evidence (including
+// amino overhead), for the validator key types enabled by the consensus params.
+func MaxEvidenceBytesForParams(params abci.ConsensusParams) int64 {
+	if isMultisigValidatorEnabled(params) {
+		return MaxMultisigEvidenceBytes
+	}
+	return MaxEvidenceBytes
+}
+
 // EvidenceInvalidError wraps a piece of evidence and the error denoting how or why it is invalid.
 type EvidenceInvalidError struct {
 	Evidence   Evidence
@@ -66,12 +79,13 @@ const (
 
 // MaxEvidencePerBlock returns the maximum number of evidences
 // allowed in the block and their maximum total size (limited to 1/10th
-// of the maximum block size).
+// of the maximum block size), for the validator key types enabled by the
+// consensus params.
 // TODO: change to a constant, or to a fraction of the validator set size.
 // See https://github.com/tendermint/classic/issues/2590
-func MaxEvidencePerBlock(blockMaxBytes int64) (int64, int64) {
+func MaxEvidencePerBlock(blockMaxBytes int64, params abci.ConsensusParams) (int64, int64) {
 	maxBytes := blockMaxBytes / MaxEvidenceBytesDenominator
-	maxNum := maxBytes / MaxEvidenceBytes
+	maxNum := maxBytes / MaxEvidenceBytesForParams(params)
 	return maxNum, maxBytes
 }
 
@@ -137,10 +151,10 @@ func (dve *DuplicateVoteEvidence) Verify(chainID string, pubKey crypto.PubKey) e
 	}
 
 	// Signatures must be valid
-	if !pubKey.VerifyBytes(dve.VoteA.SignBytes(chainID), dve.VoteA.Signature) {
+	if !VerifySignature(pubKey, dve.VoteA.SignBytes(chainID), dve.VoteA.Signature) {
 		return fmt.Errorf("DuplicateVoteEvidence Error verifying VoteA: %w", ErrVoteInvalidSignature)
 	}
-	if !pubKey.VerifyBytes(dve.VoteB.SignBytes(chainID), dve.VoteB.Signature) {
+	if !VerifySignature(pubKey, dve.VoteB.SignBytes(chainID), dve.VoteB.Signature) {
 		return fmt.Errorf("DuplicateVoteEvidence Error verifying VoteB: %w", ErrVoteInvalidSignature)
 	}
 
@@ -167,10 +181,13 @@ func (dve *DuplicateVoteEvidence) ValidateBasic() error {
 	if dve.VoteA == nil || dve.VoteB == nil {
 		return fmt.Errorf("one or both of the votes are empty %v, %v", dve.VoteA, dve.VoteB)
 	}
-	if err := dve.VoteA.ValidateBasic(); err != nil {
+	// The multisig validators are checked to be enabled when verifying the
+	// evidence against the validator set.
+	maxSignatureSize := MaxSignatureSizeForPubKey(dve.PubKey)
+	if err := dve.VoteA.validateBasic(maxSignatureSize); err != nil {
 		return fmt.Errorf("invalid VoteA: %w", err)
 	}
-	if err := dve.VoteB.ValidateBasic(); err != nil {
+	if err := dve.VoteB.validateBasic(maxSignatureSize); err != nil {
 		return fmt.Errorf("invalid VoteB: %w", err)
 	}
 	return nil
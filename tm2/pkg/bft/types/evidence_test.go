@@ -3,12 +3,14 @@ package types
 import (
 	"math"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
 	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
 )
@@ -121,6 +123,62 @@ func TestEvidenceByteSize(t *testing.T) {
 	assert.EqualValues(t, 548, len(bz))
 }
 
+func TestMaxEvidenceBytes(t *testing.T) {
+	t.Parallel()
+
+	// time is varint encoded so need to pick the max.
+	timestamp := time.Date(math.MaxInt64, 0, 0, 0, 0, 0, math.MaxInt64, time.UTC)
+
+	// The biggest multisig public key is a multisig of secp256k1 keys, as they are longer.
+	pubKeys := make([]crypto.PubKey, MaxMultisigValidatorKeys)
+	for i := range pubKeys {
+		pubKeys[i] = secp256k1.GenPrivKey().PubKey()
+	}
+	multisigKey := multisig.NewPubKeyMultisigThreshold(MaxMultisigValidatorKeys, pubKeys)
+
+	testTable := []struct {
+		name     string
+		pubKey   crypto.PubKey
+		maxBytes int64
+	}{
+		{"single key", secp256k1.GenPrivKey().PubKey(), MaxEvidenceBytes},
+		{"multisig", multisigKey, MaxMultisigEvidenceBytes},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			pubKey := testCase.pubKey
+			maxVote := func(blockHash string) *Vote {
+				return &Vote{
+					ValidatorAddress: pubKey.Address(),
+					ValidatorIndex:   math.MaxInt64,
+					Height:           math.MaxInt64,
+					Round:            math.MaxInt64,
+					Timestamp:        timestamp,
+					Type:             PrecommitType,
+					BlockID:          makeBlockID(tmhash.Sum([]byte(blockHash)), math.MaxInt64, tmhash.Sum([]byte("partshash"))),
+					Signature:        make([]byte, MaxSignatureSizeForPubKey(pubKey)),
+				}
+			}
+
+			ev := &DuplicateVoteEvidence{
+				PubKey: pubKey,
+				VoteA:  maxVote("blockhash"),
+				VoteB:  maxVote("blockhash2"),
+			}
+			require.NoError(t, ev.ValidateBasic())
+
+			// Evidence is encoded as an interface in blocks.
+			bz, err := amino.MarshalAnySized(ev)
+			require.NoError(t, err)
+
+			assert.EqualValues(t, testCase.maxBytes, len(bz))
+		})
+	}
+}
+
 func randomDuplicatedVoteEvidence() *DuplicateVoteEvidence {
 	val := NewMockPV()
 	blockID := makeBlockID([]byte("blockhash"), 1000, []byte("partshash"))
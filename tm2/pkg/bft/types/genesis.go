@@ -26,6 +26,7 @@ var (
 	ErrInvalidValidatorVotingPower = errors.New("validator has no voting power")
 	ErrInvalidValidatorAddress     = errors.New("invalid validator address")
 	ErrValidatorPubKeyMismatch     = errors.New("validator public key and address mismatch")
+	ErrInvalidValidatorPubKey      = errors.New("invalid validator public key")
 )
 
 // ------------------------------------------------------------
@@ -114,6 +115,16 @@ func (genDoc *GenesisDoc) Validate() error {
 		if v.PubKey.Address() != v.Address {
 			return fmt.Errorf("%w, %s", ErrValidatorPubKeyMismatch, v.Name)
 		}
+
+		// Check multisig pub keys are enabled by the consensus params
+		if !isValidatorPubKeyEnabled(genDoc.ConsensusParams, v.PubKey) {
+			return fmt.Errorf("%w, %s: multisig keys are not enabled", ErrInvalidValidatorPubKey, v.Name)
+		}
+
+		// Check the pub key can be used by a validator
+		if err := ValidateValidatorPubKey(v.PubKey); err != nil {
+			return fmt.Errorf("%w, %s: %w", ErrInvalidValidatorPubKey, v.Name, err)
+		}
 	}
 
 	return nil
@@ -144,6 +155,12 @@ func (genDoc *GenesisDoc) ValidateAndComplete() error {
 		} else if v.PubKey.Address() != v.Address {
 			return errors.New("Incorrect address for validator %v in the genesis file, should be %v", v, v.PubKey.Address())
 		}
+		if !isValidatorPubKeyEnabled(genDoc.ConsensusParams, v.PubKey) {
+			return errors.New("Multisig public key of validator %v is not enabled in the genesis file", v)
+		}
+		if err := ValidateValidatorPubKey(v.PubKey); err != nil {
+			return errors.New("Invalid public key for validator %v in the genesis file: %v", v, err)
+		}
 	}
 
 	if genDoc.GenesisTime.IsZero() {
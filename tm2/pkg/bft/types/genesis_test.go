@@ -10,7 +10,9 @@ import (
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 )
 
 func TestGenesisBad(t *testing.T) {
@@ -251,4 +253,29 @@ func TestGenesis_Validate(t *testing.T) {
 
 		assert.ErrorIs(t, g.Validate(), ErrValidatorPubKeyMismatch)
 	})
+
+	t.Run("multisig validator", func(t *testing.T) {
+		t.Parallel()
+
+		key := multisig.NewPubKeyMultisigThreshold(2, []crypto.PubKey{randPubKey(), randPubKey(), randPubKey()})
+
+		g := getValidTestGenesis()
+		g.Validators = []GenesisValidator{
+			{
+				Power:   1,
+				Address: key.Address(),
+				PubKey:  key,
+				Name:    "multisig validator",
+			},
+		}
+
+		// Multisig keys are not enabled by default.
+		assert.ErrorIs(t, g.Validate(), ErrInvalidValidatorPubKey)
+
+		g.ConsensusParams.Validator.PubKeyTypeURLs = append(
+			g.ConsensusParams.Validator.PubKeyTypeURLs,
+			amino.GetTypeURL(key),
+		)
+		assert.NoError(t, g.Validate())
+	})
 }
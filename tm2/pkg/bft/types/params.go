@@ -3,7 +3,9 @@ package types
 import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
 	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 )
@@ -32,8 +34,48 @@ const (
 )
 
 var validatorPubKeyTypeURLs = map[string]struct{}{
-	amino.GetTypeURL(ed25519.PubKeyEd25519{}):     {},
-	amino.GetTypeURL(secp256k1.PubKeySecp256k1{}): {},
+	amino.GetTypeURL(ed25519.PubKeyEd25519{}):            {},
+	amino.GetTypeURL(secp256k1.PubKeySecp256k1{}):        {},
+	amino.GetTypeURL(multisig.PubKeyMultisigThreshold{}): {}, // threshold signing, opt-in through Validator.PubKeyTypeURLs
+}
+
+// ValidateValidatorPubKey checks that the public key can be used by a validator.
+// A multisig threshold key must hold at most MaxMultisigValidatorKeys keys,
+// none of them being a multisig key.
+func ValidateValidatorPubKey(pubKey crypto.PubKey) error {
+	multisigKey, ok := pubKey.(multisig.PubKeyMultisigThreshold)
+	if !ok {
+		return nil
+	}
+
+	if multisigKey.K == 0 || int(multisigKey.K) > len(multisigKey.PubKeys) {
+		return errors.New("invalid multisig validator threshold: %d of %d keys",
+			multisigKey.K, len(multisigKey.PubKeys))
+	}
+
+	if len(multisigKey.PubKeys) > MaxMultisigValidatorKeys {
+		return errors.New("multisig validator has too many keys: %d > %d",
+			len(multisigKey.PubKeys), MaxMultisigValidatorKeys)
+	}
+
+	for i, key := range multisigKey.PubKeys {
+		switch key.(type) {
+		case nil, multisig.PubKeyMultisigThreshold:
+			return errors.New("multisig validator key %d is not a single key", i)
+		}
+	}
+
+	return nil
+}
+
+// isValidatorPubKeyEnabled returns false if the public key is a multisig threshold
+// key which is not enabled by the Validator.PubKeyTypeURLs consensus params.
+func isValidatorPubKeyEnabled(params abci.ConsensusParams, pubKey crypto.PubKey) bool {
+	if _, ok := pubKey.(multisig.PubKeyMultisigThreshold); !ok {
+		return true
+	}
+
+	return isMultisigValidatorEnabled(params)
 }
 
 func DefaultConsensusParams() abci.ConsensusParams {
@@ -8,11 +8,16 @@ import (
 	"github.com/stretchr/testify/assert"
 
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
 )
 
 var (
 	valEd25519   = []string{"/tm.PubKeyEd25519"}
 	valSecp256k1 = []string{"/tm.PubKeySecp256k1"}
+	valMultisig  = []string{"/tm.PubKeyEd25519", "/tm.PubKeyMultisig"}
 )
 
 func TestConsensusParamsValidation(t *testing.T) {
@@ -36,6 +41,8 @@ func TestConsensusParamsValidation(t *testing.T) {
 		9: {makeParams(1, 1024, 0, 10, []string{}), false},
 		// test invalid pubkey type provided
 		10: {makeParams(1, 1024, 0, 10, []string{"potatoes make good pubkeys"}), false},
+		// test multisig pubkey type provided
+		11: {makeParams(1, 1024, 0, 10, valMultisig), true},
 	}
 	for i, tc := range testCases {
 		if tc.valid {
@@ -46,6 +53,46 @@ func TestConsensusParamsValidation(t *testing.T) {
 	}
 }
 
+func TestValidateValidatorPubKey(t *testing.T) {
+	t.Parallel()
+
+	genPubKeys := func(n int) []crypto.PubKey {
+		pubKeys := make([]crypto.PubKey, n)
+		for i := range pubKeys {
+			pubKeys[i] = ed25519.GenPrivKey().PubKey()
+		}
+		return pubKeys
+	}
+
+	multisigKey := multisig.NewPubKeyMultisigThreshold(2, genPubKeys(3))
+
+	testCases := []struct {
+		name   string
+		pubKey crypto.PubKey
+		valid  bool
+	}{
+		{"ed25519", ed25519.GenPrivKey().PubKey(), true},
+		{"secp256k1", secp256k1.GenPrivKey().PubKey(), true},
+		{"multisig", multisigKey, true},
+		{"multisig with max keys", multisig.NewPubKeyMultisigThreshold(MaxMultisigValidatorKeys, genPubKeys(MaxMultisigValidatorKeys)), true},
+		{"multisig with too many keys", multisig.NewPubKeyMultisigThreshold(2, genPubKeys(MaxMultisigValidatorKeys+1)), false},
+		{"multisig with zero threshold", multisig.PubKeyMultisigThreshold{K: 0, PubKeys: genPubKeys(2)}, false},
+		{"multisig with threshold above keys", multisig.PubKeyMultisigThreshold{K: 3, PubKeys: genPubKeys(2)}, false},
+		{"nested multisig", multisig.NewPubKeyMultisigThreshold(1, []crypto.PubKey{multisigKey}), false},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			if tc.valid {
+				assert.NoError(t, ValidateValidatorPubKey(tc.pubKey))
+			} else {
+				assert.Error(t, ValidateValidatorPubKey(tc.pubKey))
+			}
+		})
+	}
+}
+
 func makeParams(
 	dataBytes, blockBytes, blockGas int64,
 	blockTimeIotaMS int64,
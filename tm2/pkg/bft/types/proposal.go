@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	tmtime "github.com/gnolang/gno/tm2/pkg/bft/types/time"
 )
 
@@ -43,8 +44,19 @@ func NewProposal(height int64, round int, polRound int, blockID BlockID) *Propos
 	}
 }
 
-// ValidateBasic performs basic validation.
+// ValidateBasic performs basic validation, allowing the signatures of single
+// key validators only.
 func (p *Proposal) ValidateBasic() error {
+	return p.validateBasic(MaxSignatureSize)
+}
+
+// ValidateBasicWithParams performs basic validation, allowing the signatures
+// of the validator key types enabled by the consensus params.
+func (p *Proposal) ValidateBasicWithParams(params abci.ConsensusParams) error {
+	return p.validateBasic(MaxSignatureSizeForParams(params))
+}
+
+func (p *Proposal) validateBasic(maxSignatureSize int) error {
 	if p.Type != ProposalType {
 		return errors.New("invalid Type")
 	}
@@ -70,8 +82,8 @@ func (p *Proposal) ValidateBasic() error {
 	if len(p.Signature) == 0 {
 		return errors.New("signature is missing")
 	}
-	if len(p.Signature) > MaxSignatureSize {
-		return fmt.Errorf("signature is too big (max: %d)", MaxSignatureSize)
+	if len(p.Signature) > maxSignatureSize {
+		return fmt.Errorf("signature is too big (max: %d)", maxSignatureSize)
 	}
 	return nil
 }
@@ -0,0 +1,142 @@
+package query
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/events"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// Fields are the values of an event which can be matched by a [Query].
+type Fields struct {
+	Values  map[string]string   // tm.event, block.* and tx.* keys
+	Signers []string            // addresses of the signers, for the tx.signer key
+	Msgs    []map[string]string // fields of each message, for the msg.* keys
+	Events  []map[string]string // fields of each ABCI event, for the event.* keys
+}
+
+// EventFields returns the fields of ev. It returns false if ev is not one of
+// the events which can be queried.
+func EventFields(ev events.Event) (Fields, bool) {
+	var f Fields
+	switch ev := ev.(type) {
+	case types.EventNewBlock:
+		f.Values = map[string]string{
+			KeyEvent:       EventNewBlock,
+			KeyBlockHeight: strconv.FormatInt(ev.Block.Height, 10),
+		}
+		f.Events = ABCIEventFields(ev.ResultBeginBlock.Events, ev.ResultEndBlock.Events)
+	case types.EventNewBlockHeader:
+		f.Values = map[string]string{
+			KeyEvent:       EventNewBlockHeader,
+			KeyBlockHeight: strconv.FormatInt(ev.Header.Height, 10),
+		}
+		f.Events = ABCIEventFields(ev.ResultBeginBlock.Events, ev.ResultEndBlock.Events)
+	case types.EventTx:
+		f = TxResultFields(ev.Result)
+	case types.EventValidatorSetUpdates:
+		f.Values = map[string]string{KeyEvent: EventValidatorSetUpdates}
+	default:
+		return Fields{}, false
+	}
+	return f, true
+}
+
+// TxResultFields returns the fields of the Tx event of res. The signers and
+// messages are only available if res.Tx is a [std.Tx].
+func TxResultFields(res types.TxResult) Fields {
+	f := Fields{
+		Values: map[string]string{
+			KeyEvent:    EventTx,
+			KeyTxHeight: strconv.FormatInt(res.Height, 10),
+			KeyTxIndex:  strconv.FormatUint(uint64(res.Index), 10),
+			KeyTxHash:   fmt.Sprintf("%X", res.Tx.Hash()),
+		},
+		Events: ABCIEventFields(res.Response.Events),
+	}
+	var tx std.Tx
+	if err := amino.Unmarshal(res.Tx, &tx); err != nil {
+		return f
+	}
+	for _, signer := range tx.GetSigners() {
+		f.Signers = append(f.Signers, signer.String())
+	}
+	for _, msg := range tx.GetMsgs() {
+		fields := objectFields(msg)
+		if fields == nil {
+			fields = make(map[string]string, 2)
+		}
+		fields["route"] = msg.Route()
+		fields["type"] = msg.Type()
+		f.Msgs = append(f.Msgs, fields)
+	}
+	return f
+}
+
+// ABCIEventFields returns the fields of the ABCI events, for the event.*
+// keys: the scalar fields of their JSON form, and the attributes of the
+// lists of key/value objects.
+func ABCIEventFields(evss ...[]abci.Event) []map[string]string {
+	var fields []map[string]string
+	for _, evs := range evss {
+		for _, ev := range evs {
+			if f := objectFields(ev); f != nil {
+				fields = append(fields, f)
+			}
+		}
+	}
+	return fields
+}
+
+// objectFields returns the scalar fields of the JSON form of v, and the
+// attributes of its lists of key/value objects. It returns nil if v is not
+// encoded as an object.
+func objectFields(v any) map[string]string {
+	bz, err := amino.MarshalJSON(v)
+	if err != nil {
+		return nil
+	}
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(bz, &obj); err != nil {
+		return nil
+	}
+	f := make(map[string]string, len(obj))
+	for name, raw := range obj {
+		if v, ok := scalar(raw); ok {
+			f[name] = v
+			continue
+		}
+		var attrs []map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &attrs); err != nil {
+			continue
+		}
+		for _, attr := range attrs {
+			key, ok1 := scalar(attr["key"])
+			value, ok2 := scalar(attr["value"])
+			if ok1 && ok2 {
+				f[name+"."+key] = value
+			}
+		}
+	}
+	return f
+}
+
+// scalar returns the value of a JSON string, number or boolean.
+func scalar(raw json.RawMessage) (string, bool) {
+	var v any
+	if err := json.Unmarshal(raw, &v); err != nil {
+		return "", false
+	}
+	switch v := v.(type) {
+	case string:
+		return v, true
+	case float64, bool:
+		return string(raw), true
+	}
+	return "", false
+}
@@ -0,0 +1,352 @@
+// Package query implements the queries used to filter the events of a node,
+// for instance to subscribe to them over the RPC.
+//
+// A query is a list of conditions joined by AND, each comparing the value of
+// a key with an operand:
+//
+//	tm.event = 'Tx' AND tx.height > 5 AND event.pkg_path = 'gno.land/r/demo/foo'
+//
+// The following keys are available:
+//
+//   - tm.event: the type of the event; one of NewBlock, NewBlockHeader, Tx and
+//     ValidatorSetUpdates
+//   - block.height: the height of the block, for NewBlock and NewBlockHeader
+//   - tx.height, tx.index, tx.hash: the height, index in the block and hex
+//     hash of the transaction, for Tx
+//   - tx.signer: the address of one of the signers of the transaction
+//   - msg.<field>: a field of a message of the transaction, in its JSON form
+//     (e.g. msg.pkg_path or msg.func), or its msg.route and msg.type. All the
+//     msg.* conditions of a query must match the same message.
+//   - event.<field>: a field of an event emitted by the application, in its
+//     JSON form (e.g. event.type or event.pkg_path). A list of key/value
+//     attributes is accessed with event.<field>.<key> (e.g. event.attrs.from).
+//     All the event.* conditions of a query must match the same event.
+//
+// Operands are quoted strings or integers. The operators are =, <, <=, >, >=,
+// which compare integers as numbers, and CONTAINS, which matches strings
+// containing the operand.
+package query
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Event types, for the tm.event key.
+const (
+	EventNewBlock            = "NewBlock"
+	EventNewBlockHeader      = "NewBlockHeader"
+	EventTx                  = "Tx"
+	EventValidatorSetUpdates = "ValidatorSetUpdates"
+)
+
+// Keys of the query, other than the event.* keys.
+const (
+	KeyEvent       = "tm.event"
+	KeyBlockHeight = "block.height"
+	KeyTxHeight    = "tx.height"
+	KeyTxIndex     = "tx.index"
+	KeyTxHash      = "tx.hash"
+	KeyTxSigner    = "tx.signer"
+
+	MsgKeyPrefix   = "msg."
+	EventKeyPrefix = "event."
+)
+
+// Operator is a comparison operator of a condition.
+type Operator string
+
+// Operators of the conditions.
+const (
+	OpEqual        Operator = "="
+	OpLess         Operator = "<"
+	OpLessEqual    Operator = "<="
+	OpGreater      Operator = ">"
+	OpGreaterEqual Operator = ">="
+	OpContains     Operator = "CONTAINS"
+)
+
+// Condition compares the value of Key with Operand.
+type Condition struct {
+	Key     string
+	Op      Operator
+	Operand string
+	number  bool // Operand is an integer
+}
+
+// Query is a parsed query. The zero value matches all the events.
+type Query struct {
+	conds []Condition
+}
+
+// MustParse is like [Parse], but panics on error.
+func MustParse(s string) *Query {
+	q, err := Parse(s)
+	if err != nil {
+		panic(err)
+	}
+	return q
+}
+
+// Parse parses a query. The empty string is the query matching all events.
+func Parse(s string) (*Query, error) {
+	p := &parser{s: s}
+	q := &Query{}
+	if p.skipSpaces(); p.eof() {
+		return q, nil
+	}
+	for {
+		cond, err := p.condition()
+		if err != nil {
+			return nil, fmt.Errorf("invalid query %q: %w", s, err)
+		}
+		q.conds = append(q.conds, cond)
+		if p.skipSpaces(); p.eof() {
+			return q, nil
+		}
+		if !p.keyword("AND") {
+			return nil, fmt.Errorf("invalid query %q: expected AND at offset %d", s, p.pos)
+		}
+	}
+}
+
+// Conditions returns the conditions of q.
+func (q *Query) Conditions() []Condition {
+	return q.conds
+}
+
+// String returns the normalized form of q: two queries with the same
+// conditions have the same string.
+func (q *Query) String() string {
+	parts := make([]string, len(q.conds))
+	for i, c := range q.conds {
+		parts[i] = c.String()
+	}
+	return strings.Join(parts, " AND ")
+}
+
+func (c Condition) String() string {
+	operand := c.Operand
+	if !c.number {
+		operand = "'" + strings.ReplaceAll(operand, "'", `\'`) + "'"
+	}
+	return c.Key + " " + string(c.Op) + " " + operand
+}
+
+// Matches reports whether the value of the condition matches.
+func (c Condition) Matches(value string) bool {
+	if c.Op == OpContains {
+		return strings.Contains(value, c.Operand)
+	}
+	if c.number {
+		v, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return false
+		}
+		operand, _ := strconv.ParseInt(c.Operand, 10, 64)
+		switch c.Op {
+		case OpEqual:
+			return v == operand
+		case OpLess:
+			return v < operand
+		case OpLessEqual:
+			return v <= operand
+		case OpGreater:
+			return v > operand
+		case OpGreaterEqual:
+			return v >= operand
+		}
+		return false
+	}
+	return c.Op == OpEqual && value == c.Operand
+}
+
+// Matches reports whether the fields of an event match all the conditions
+// of q.
+func (q *Query) Matches(f Fields) bool {
+	for _, c := range q.conds {
+		switch {
+		case strings.HasPrefix(c.Key, MsgKeyPrefix), strings.HasPrefix(c.Key, EventKeyPrefix):
+			// See matchesGroup.
+		case c.Key == KeyTxSigner:
+			if !slices.ContainsFunc(f.Signers, c.Matches) {
+				return false
+			}
+		default:
+			v, ok := f.Values[c.Key]
+			if !ok || !c.Matches(v) {
+				return false
+			}
+		}
+	}
+	return q.matchesGroup(MsgKeyPrefix, f.Msgs) && q.matchesGroup(EventKeyPrefix, f.Events)
+}
+
+// matchesGroup reports whether one of the groups of fields matches all the
+// conditions of q whose key has the given prefix.
+func (q *Query) matchesGroup(prefix string, groups []map[string]string) bool {
+	hasConds := false
+	for _, c := range q.conds {
+		if strings.HasPrefix(c.Key, prefix) {
+			hasConds = true
+			break
+		}
+	}
+	if !hasConds {
+		return true
+	}
+	return slices.ContainsFunc(groups, func(fields map[string]string) bool {
+		for _, c := range q.conds {
+			key, ok := strings.CutPrefix(c.Key, prefix)
+			if !ok {
+				continue
+			}
+			v, ok := fields[key]
+			if !ok || !c.Matches(v) {
+				return false
+			}
+		}
+		return true
+	})
+}
+
+// ----------------------------------------
+// parser
+
+type parser struct {
+	s   string
+	pos int
+}
+
+func (p *parser) eof() bool {
+	return p.pos >= len(p.s)
+}
+
+func (p *parser) skipSpaces() {
+	for !p.eof() && (p.s[p.pos] == ' ' || p.s[p.pos] == '\t' || p.s[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// keyword consumes kw, if it is the next word.
+func (p *parser) keyword(kw string) bool {
+	end := p.pos + len(kw)
+	if end > len(p.s) || !strings.EqualFold(p.s[p.pos:end], kw) {
+		return false
+	}
+	if end < len(p.s) && p.s[end] != ' ' && p.s[end] != '\t' && p.s[end] != '\n' {
+		return false
+	}
+	p.pos = end
+	return true
+}
+
+func (p *parser) condition() (Condition, error) {
+	var c Condition
+	p.skipSpaces()
+	start := p.pos
+	for !p.eof() && isKeyChar(p.s[p.pos]) {
+		p.pos++
+	}
+	c.Key = p.s[start:p.pos]
+	if err := validateKey(c.Key); err != nil {
+		return c, err
+	}
+
+	p.skipSpaces()
+	for _, op := range []Operator{OpLessEqual, OpGreaterEqual, OpLess, OpGreater, OpEqual} {
+		if strings.HasPrefix(p.s[p.pos:], string(op)) {
+			c.Op = op
+			p.pos += len(op)
+			break
+		}
+	}
+	if c.Op == "" {
+		if !p.keyword(string(OpContains)) {
+			return c, fmt.Errorf("expected an operator at offset %d", p.pos)
+		}
+		c.Op = OpContains
+	}
+
+	p.skipSpaces()
+	if p.eof() {
+		return c, fmt.Errorf("expected an operand at offset %d", p.pos)
+	}
+	if p.s[p.pos] == '\'' {
+		operand, err := p.quoted()
+		if err != nil {
+			return c, err
+		}
+		c.Operand = operand
+	} else {
+		start := p.pos
+		for !p.eof() && (p.s[p.pos] == '-' || '0' <= p.s[p.pos] && p.s[p.pos] <= '9') {
+			p.pos++
+		}
+		c.Operand = p.s[start:p.pos]
+		if _, err := strconv.ParseInt(c.Operand, 10, 64); err != nil {
+			return c, fmt.Errorf("invalid operand at offset %d: expected a quoted string or an integer", start)
+		}
+		c.number = true
+	}
+
+	switch {
+	case c.Op == OpContains && c.number:
+		return c, fmt.Errorf("%s expects a string operand", c.Op)
+	case c.Op != OpContains && c.Op != OpEqual && !c.number:
+		return c, fmt.Errorf("%s expects an integer operand", c.Op)
+	case c.Key == KeyTxHash:
+		c.Operand = strings.ToUpper(c.Operand)
+	case c.Key == KeyEvent:
+		switch c.Operand {
+		case EventNewBlock, EventNewBlockHeader, EventTx, EventValidatorSetUpdates:
+		default:
+			return c, fmt.Errorf("unknown event type %q", c.Operand)
+		}
+	}
+	return c, nil
+}
+
+// quoted consumes a quoted string, in which quotes are escaped with a
+// backslash.
+func (p *parser) quoted() (string, error) {
+	start := p.pos
+	p.pos++ // opening quote
+	var sb strings.Builder
+	for !p.eof() {
+		ch := p.s[p.pos]
+		p.pos++
+		switch {
+		case ch == '\'':
+			return sb.String(), nil
+		case ch == '\\' && !p.eof() && (p.s[p.pos] == '\'' || p.s[p.pos] == '\\'):
+			sb.WriteByte(p.s[p.pos])
+			p.pos++
+		default:
+			sb.WriteByte(ch)
+		}
+	}
+	return "", fmt.Errorf("unterminated string at offset %d", start)
+}
+
+func isKeyChar(ch byte) bool {
+	return 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z' ||
+		'0' <= ch && ch <= '9' || ch == '.' || ch == '_' || ch == '-' || ch == '@'
+}
+
+func validateKey(key string) error {
+	switch key {
+	case "":
+		return fmt.Errorf("expected a key")
+	case KeyEvent, KeyBlockHeight, KeyTxHeight, KeyTxIndex, KeyTxHash, KeyTxSigner:
+		return nil
+	}
+	for _, prefix := range []string{MsgKeyPrefix, EventKeyPrefix} {
+		if field, ok := strings.CutPrefix(key, prefix); ok && field != "" {
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown key %q", key)
+}
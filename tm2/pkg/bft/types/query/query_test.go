@@ -0,0 +1,204 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk/bank"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testEvent struct {
+	Type    string     `json:"type"`
+	PkgPath string     `json:"pkg_path"`
+	Attrs   []testAttr `json:"attrs"`
+	Amount  int        `json:"amount"`
+	Nested  struct{ X int }
+}
+
+type testAttr struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+}
+
+func (testEvent) AssertABCIEvent() {}
+
+func TestParse(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name  string
+		query string
+		str   string
+		err   bool
+	}{
+		{"empty", "  ", "", false},
+		{"event type", "tm.event='Tx'", "tm.event = 'Tx'", false},
+		{"conjunction", "tm.event = 'Tx' and tx.height >= 5 AND event.type CONTAINS 'Trans'", "tm.event = 'Tx' AND tx.height >= 5 AND event.type CONTAINS 'Trans'", false},
+		{"escaped quote", `event.type = 'it\'s'`, `event.type = 'it\'s'`, false},
+		{"negative number", "block.height > -1", "block.height > -1", false},
+		{"hash upper case", "tx.hash = 'abcd'", "tx.hash = 'ABCD'", false},
+		{"attribute", "event.attrs.from = 'g1xyz'", "event.attrs.from = 'g1xyz'", false},
+		{"signer and msg", "tx.signer='g1xyz' AND msg.route = 'bank'", "tx.signer = 'g1xyz' AND msg.route = 'bank'", false},
+		{"empty msg field", "msg. = 'x'", "", true},
+		{"unknown key", "foo.bar = 'x'", "", true},
+		{"empty event field", "event. = 'x'", "", true},
+		{"unknown event type", "tm.event = 'Vote'", "", true},
+		{"missing operator", "tx.height 5", "", true},
+		{"missing operand", "tx.height =", "", true},
+		{"unterminated string", "event.type = 'x", "", true},
+		{"invalid number", "tx.height = 5x", "", true},
+		{"ordering a string", "event.type > 'x'", "", true},
+		{"contains a number", "event.type CONTAINS 5", "", true},
+		{"missing AND", "tx.height = 5 tx.index = 1", "", true},
+		{"trailing AND", "tx.height = 5 AND", "", true},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			q, err := Parse(testCase.query)
+			if testCase.err {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, testCase.str, q.String())
+
+			// The normalized form parses to the same query.
+			q2, err := Parse(q.String())
+			require.NoError(t, err)
+			assert.Equal(t, q, q2)
+		})
+	}
+}
+
+func TestQuery_Matches(t *testing.T) {
+	t.Parallel()
+
+	tx := types.Tx("tx")
+	f, ok := EventFields(types.EventTx{Result: types.TxResult{
+		Height: 10,
+		Index:  2,
+		Tx:     tx,
+		Response: abci.ResponseDeliverTx{
+			ResponseBase: abci.ResponseBase{
+				Events: []abci.Event{
+					testEvent{Type: "Transfer", PkgPath: "gno.land/r/demo/foo", Amount: 100},
+					testEvent{Type: "Mint", PkgPath: "gno.land/r/demo/bar", Attrs: []testAttr{{"to", "g1xyz"}}},
+				},
+			},
+		},
+	}})
+	require.True(t, ok)
+
+	testTable := []struct {
+		query   string
+		matches bool
+	}{
+		{"", true},
+		{"tm.event = 'Tx'", true},
+		{"tm.event = 'NewBlock'", false},
+		{"tx.height = 10 AND tx.index = 2", true},
+		{"tx.height > 10", false},
+		{"tx.height >= 10 AND tx.height < 11", true},
+		{"tx.height <= 9", false},
+		{fmt.Sprintf("tx.hash = '%x'", tx.Hash()), true},
+		{"block.height = 10", false},
+		{"event.type = 'Transfer'", true},
+		{"event.type = 'Transfer' AND event.pkg_path = 'gno.land/r/demo/foo'", true},
+		// The event conditions must match the same event.
+		{"event.type = 'Transfer' AND event.pkg_path = 'gno.land/r/demo/bar'", false},
+		{"event.pkg_path CONTAINS 'gno.land/r/demo/'", true},
+		{"event.amount > 50", true},
+		{"event.amount > 100", false},
+		{"event.attrs.to = 'g1xyz' AND event.type = 'Mint'", true},
+		{"event.attrs.to = 'g1abc'", false},
+		{"event.Nested = '1'", false},
+		{"event.unknown = 'x'", false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.matches, MustParse(testCase.query).Matches(f))
+		})
+	}
+}
+
+func TestEventFields(t *testing.T) {
+	t.Parallel()
+
+	block := &types.Block{Header: types.Header{Height: 3}}
+	f, ok := EventFields(types.EventNewBlock{
+		Block: block,
+		ResultEndBlock: abci.ResponseEndBlock{
+			Events: []abci.Event{testEvent{Type: "EndBlock"}},
+		},
+	})
+	require.True(t, ok)
+	assert.True(t, MustParse("tm.event = 'NewBlock' AND block.height = 3 AND event.type = 'EndBlock'").Matches(f))
+
+	f, ok = EventFields(types.EventNewBlockHeader{Header: block.Header})
+	require.True(t, ok)
+	assert.True(t, MustParse("tm.event = 'NewBlockHeader' AND block.height = 3").Matches(f))
+
+	f, ok = EventFields(types.EventValidatorSetUpdates{})
+	require.True(t, ok)
+	assert.True(t, MustParse("tm.event = 'ValidatorSetUpdates'").Matches(f))
+
+	// Consensus events cannot be queried.
+	_, ok = EventFields(types.EventVote{})
+	assert.False(t, ok)
+}
+
+func TestTxResultFields(t *testing.T) {
+	t.Parallel()
+
+	var (
+		alice = crypto.AddressFromPreimage([]byte("alice"))
+		bob   = crypto.AddressFromPreimage([]byte("bob"))
+	)
+	tx := std.Tx{
+		Msgs: []std.Msg{
+			bank.NewMsgSend(alice, bob, std.NewCoins(std.NewCoin("ugnot", 10))),
+			bank.NewMsgSend(bob, alice, std.NewCoins(std.NewCoin("ugnot", 20))),
+		},
+	}
+	f := TxResultFields(types.TxResult{Height: 1, Tx: amino.MustMarshal(tx)})
+
+	testTable := []struct {
+		query   string
+		matches bool
+	}{
+		{"tx.signer = '" + alice.String() + "'", true},
+		{"tx.signer = '" + bob.String() + "'", true},
+		{"tx.signer = '" + crypto.AddressFromPreimage([]byte("carol")).String() + "'", false},
+		{"msg.route = 'bank' AND msg.type = 'send'", true},
+		{"msg.from_address = '" + alice.String() + "' AND msg.amount = '10ugnot'", true},
+		// The msg conditions must match the same message.
+		{"msg.from_address = '" + alice.String() + "' AND msg.amount = '20ugnot'", false},
+		{"msg.route = 'vm'", false},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.query, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.matches, MustParse(testCase.query).Matches(f))
+		})
+	}
+
+	// The transaction of the other tests is not a std.Tx.
+	other := TxResultFields(types.TxResult{Tx: types.Tx("tx")})
+	assert.Empty(t, other.Signers)
+	assert.Empty(t, other.Msgs)
+}
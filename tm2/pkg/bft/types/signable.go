@@ -1,14 +1,68 @@
 package types
 
 import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+)
+
+const (
+	// MaxMultisigValidatorKeys is the maximum number of keys of a multisig
+	// threshold validator public key.
+	MaxMultisigValidatorKeys = 16
+
+	// MaxMultisigSignatureSize is the maximum size of the amino encoded
+	// multisignature of a multisig threshold validator.
+	MaxMultisigSignatureSize = 1062
 )
 
 // MaxSignatureSize is a maximum allowed signature size for the Proposal
-// and Vote.
+// and Vote of a single key validator.
 // XXX: secp256k1 does not have Size nor MaxSize defined.
 const MaxSignatureSize = max(ed25519.SignatureSize, 64)
 
+// MaxSignatureSizeForPubKey returns the maximum allowed signature size for the
+// Proposal and Vote of a validator using the given public key.
+func MaxSignatureSizeForPubKey(pubKey crypto.PubKey) int {
+	if _, ok := pubKey.(multisig.PubKeyMultisigThreshold); ok {
+		return MaxMultisigSignatureSize
+	}
+	if size := maxTestSignatureSize(pubKey); size > 0 {
+		return size
+	}
+	return MaxSignatureSize
+}
+
+// maxTestSignatureSize returns the maximum allowed signature size of the
+// public key types only used in tests, or 0. It is set by the tests.
+var maxTestSignatureSize = func(crypto.PubKey) int { return 0 }
+
+// MaxSignatureSizeForParams returns the maximum allowed signature size for the
+// Proposals and Votes of the validators, which is the one of single key
+// validators unless multisig validators are enabled by the consensus params.
+func MaxSignatureSizeForParams(params abci.ConsensusParams) int {
+	if isMultisigValidatorEnabled(params) {
+		return MaxMultisigSignatureSize
+	}
+	return MaxSignatureSize
+}
+
+// isMultisigValidatorEnabled returns true if the Validator.PubKeyTypeURLs
+// consensus params enable multisig threshold validator keys.
+func isMultisigValidatorEnabled(params abci.ConsensusParams) bool {
+	return params.Validator != nil &&
+		params.Validator.IsValidPubKeyTypeURL(amino.GetTypeURL(multisig.PubKeyMultisigThreshold{}))
+}
+
+// VerifySignature verifies the signature of a Proposal or Vote using the validator
+// public key, rejecting signatures bigger than allowed for its key type.
+func VerifySignature(pubKey crypto.PubKey, signBytes, signature []byte) bool {
+	return len(signature) <= MaxSignatureSizeForPubKey(pubKey) &&
+		pubKey.VerifyBytes(signBytes, signature)
+}
+
 // Signable is an interface for all signable things.
 // It typically removes signatures before serializing.
 // SignBytes returns the bytes to be signed
@@ -653,7 +653,7 @@ func (vals *ValidatorSet) VerifyCommit(chainID string, blockID BlockID, height i
 		_, val := vals.GetByIndex(idx)
 		// Validate signature.
 		precommitSignBytes := commit.VoteSignBytes(chainID, idx)
-		if !val.PubKey.VerifyBytes(precommitSignBytes, precommit.Signature) {
+		if !VerifySignature(val.PubKey, precommitSignBytes, precommit.Signature) {
 			return fmt.Errorf("invalid commit -- invalid signature: %v", precommit)
 		}
 		// Good precommit!
@@ -739,7 +739,7 @@ func (vals *ValidatorSet) VerifyFutureCommit(newSet *ValidatorSet, chainID strin
 
 		// Validate signature.
 		precommitSignBytes := commit.VoteSignBytes(chainID, idx)
-		if !val.PubKey.VerifyBytes(precommitSignBytes, precommit.Signature) {
+		if !VerifySignature(val.PubKey, precommitSignBytes, precommit.Signature) {
 			return errors.New("Invalid commit -- invalid signature: %v", precommit)
 		}
 		// Good precommit!
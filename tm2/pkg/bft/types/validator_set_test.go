@@ -18,6 +18,16 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/random"
 )
 
+func init() {
+	// The signatures of the mock keys are bigger than the ones of real keys.
+	maxTestSignatureSize = func(pubKey crypto.PubKey) int {
+		if _, ok := pubKey.(mock.PubKeyMock); ok {
+			return MaxMultisigSignatureSize
+		}
+		return 0
+	}
+}
+
 func TestValidatorSetBasic(t *testing.T) {
 	t.Parallel()
 
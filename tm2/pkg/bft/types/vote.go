@@ -6,15 +6,28 @@ import (
 	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 )
 
 const (
 	// MaxVoteBytes is a maximum vote size (including amino overhead).
-	MaxVoteBytes int    = 247
-	nilVoteStr   string = "nil-Vote"
+	MaxVoteBytes int = 247
+	// MaxMultisigVoteBytes is a maximum vote size (including amino overhead),
+	// signed by a multisig validator with the most keys.
+	MaxMultisigVoteBytes int    = 1246
+	nilVoteStr           string = "nil-Vote"
 )
 
+// MaxVoteBytesForParams returns the maximum vote size (including amino
+// overhead), for the validator key types enabled by the consensus params.
+func MaxVoteBytesForParams(params abci.ConsensusParams) int {
+	if isMultisigValidatorEnabled(params) {
+		return MaxMultisigVoteBytes
+	}
+	return MaxVoteBytes
+}
+
 var (
 	ErrVoteUnexpectedStep            = errors.New("unexpected step")
 	ErrVoteInvalidValidatorIndex     = errors.New("invalid validator index")
@@ -113,14 +126,25 @@ func (vote *Vote) Verify(chainID string, pubKey crypto.PubKey) error {
 		return ErrVoteInvalidValidatorAddress
 	}
 
-	if !pubKey.VerifyBytes(vote.SignBytes(chainID), vote.Signature) {
+	if !VerifySignature(pubKey, vote.SignBytes(chainID), vote.Signature) {
 		return ErrVoteInvalidSignature
 	}
 	return nil
 }
 
-// ValidateBasic performs basic validation.
+// ValidateBasic performs basic validation, allowing the signatures of single
+// key validators only.
 func (vote *Vote) ValidateBasic() error {
+	return vote.validateBasic(MaxSignatureSize)
+}
+
+// ValidateBasicWithParams performs basic validation, allowing the signatures
+// of the validator key types enabled by the consensus params.
+func (vote *Vote) ValidateBasicWithParams(params abci.ConsensusParams) error {
+	return vote.validateBasic(MaxSignatureSizeForParams(params))
+}
+
+func (vote *Vote) validateBasic(maxSignatureSize int) error {
 	if !IsVoteTypeValid(vote.Type) {
 		return errors.New("invalid Type")
 	}
@@ -150,8 +174,8 @@ func (vote *Vote) ValidateBasic() error {
 	if len(vote.Signature) == 0 {
 		return errors.New("signature is missing")
 	}
-	if len(vote.Signature) > MaxSignatureSize {
-		return fmt.Errorf("signature is too big (max: %d)", MaxSignatureSize)
+	if len(vote.Signature) > maxSignatureSize {
+		return fmt.Errorf("signature is too big (max: %d)", maxSignatureSize)
 	}
 	return nil
 }
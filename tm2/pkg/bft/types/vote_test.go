@@ -9,8 +9,11 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/ed25519"
+	"github.com/gnolang/gno/tm2/pkg/crypto/multisig"
+	"github.com/gnolang/gno/tm2/pkg/crypto/secp256k1"
 	"github.com/gnolang/gno/tm2/pkg/crypto/tmhash"
 )
 
@@ -271,6 +274,124 @@ func TestMaxVoteBytes(t *testing.T) {
 	require.NoError(t, err)
 
 	assert.EqualValues(t, MaxVoteBytes, len(bz))
+
+	// The biggest signature is the multisignature of a multisig validator.
+	vote.Signature = make([]byte, MaxMultisigSignatureSize)
+
+	bz, err = amino.MarshalSized(vote)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, MaxMultisigVoteBytes, len(bz))
+}
+
+func TestMaxMultisigSignatureSize(t *testing.T) {
+	t.Parallel()
+
+	// Signatures of all the keys of the biggest multisig validator.
+	sig := multisig.NewMultisig(MaxMultisigValidatorKeys)
+	for i := range MaxMultisigValidatorKeys {
+		sig.AddSignature(make([]byte, ed25519.SignatureSize), i)
+	}
+
+	assert.EqualValues(t, MaxMultisigSignatureSize, len(sig.Marshal()))
+}
+
+func TestVoteVerifyMultisig(t *testing.T) {
+	t.Parallel()
+
+	privKeys := []crypto.PrivKey{ed25519.GenPrivKey(), ed25519.GenPrivKey(), ed25519.GenPrivKey()}
+	pubKeys := make([]crypto.PubKey, len(privKeys))
+	for i, privKey := range privKeys {
+		pubKeys[i] = privKey.PubKey()
+	}
+	pubKey := multisig.NewPubKeyMultisigThreshold(2, pubKeys)
+
+	vote := examplePrecommit()
+	vote.ValidatorAddress = pubKey.Address()
+	signBytes := vote.SignBytes("test_chain_id")
+
+	// Sign the vote with 2 of the 3 keys.
+	sig := multisig.NewMultisig(len(pubKeys))
+	for _, i := range []int{0, 2} {
+		signature, err := privKeys[i].Sign(signBytes)
+		require.NoError(t, err)
+		sig.AddSignature(signature, i)
+	}
+	vote.Signature = sig.Marshal()
+
+	// The multisignature is only valid once multisig validators are enabled.
+	require.Error(t, vote.ValidateBasic())
+	require.Error(t, vote.ValidateBasicWithParams(DefaultConsensusParams()))
+	require.NoError(t, vote.ValidateBasicWithParams(makeParams(1, 4, 2, 10, valMultisig)))
+	assert.NoError(t, vote.Verify("test_chain_id", pubKey))
+}
+
+func TestVoteVerifySignatureSize(t *testing.T) {
+	t.Parallel()
+
+	privKey := ed25519.GenPrivKey()
+	multisigKey := multisig.NewPubKeyMultisigThreshold(1, []crypto.PubKey{privKey.PubKey()})
+
+	testTable := []struct {
+		name    string
+		pubKey  crypto.PubKey
+		maxSize int
+	}{
+		{"ed25519", privKey.PubKey(), MaxSignatureSize},
+		{"secp256k1", secp256k1.GenPrivKey().PubKey(), MaxSignatureSize},
+		{"multisig", multisigKey, MaxMultisigSignatureSize},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.maxSize, MaxSignatureSizeForPubKey(testCase.pubKey))
+		})
+	}
+
+	t.Run("single key signature too big", func(t *testing.T) {
+		t.Parallel()
+
+		vote := examplePrecommit()
+		vote.ValidatorAddress = privKey.PubKey().Address()
+
+		signature, err := privKey.Sign(vote.SignBytes("test_chain_id"))
+		require.NoError(t, err)
+
+		// A multisig sized signature passes the basic validation of a chain
+		// with multisig validators, not the verification.
+		vote.Signature = append(signature, make([]byte, MaxMultisigSignatureSize-len(signature))...)
+		require.NoError(t, vote.ValidateBasicWithParams(makeParams(1, 4, 2, 10, valMultisig)))
+		assert.ErrorIs(t, vote.Verify("test_chain_id", privKey.PubKey()), ErrVoteInvalidSignature)
+	})
+}
+
+func TestMaxSignatureSizeForParams(t *testing.T) {
+	t.Parallel()
+
+	testTable := []struct {
+		name          string
+		params        abci.ConsensusParams
+		maxSize       int
+		maxVoteBytes  int
+		maxEvidenceBz int64
+	}{
+		{"default", DefaultConsensusParams(), MaxSignatureSize, MaxVoteBytes, MaxEvidenceBytes},
+		{"no validator params", abci.ConsensusParams{}, MaxSignatureSize, MaxVoteBytes, MaxEvidenceBytes},
+		{"secp256k1", makeParams(1, 4, 2, 10, valSecp256k1), MaxSignatureSize, MaxVoteBytes, MaxEvidenceBytes},
+		{"multisig", makeParams(1, 4, 2, 10, valMultisig), MaxMultisigSignatureSize, MaxMultisigVoteBytes, MaxMultisigEvidenceBytes},
+	}
+
+	for _, testCase := range testTable {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, testCase.maxSize, MaxSignatureSizeForParams(testCase.params))
+			assert.Equal(t, testCase.maxVoteBytes, MaxVoteBytesForParams(testCase.params))
+			assert.Equal(t, testCase.maxEvidenceBz, MaxEvidenceBytesForParams(testCase.params))
+		})
+	}
 }
 
 func TestVoteString(t *testing.T) {
@@ -0,0 +1,222 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MakeGrantCfg struct {
+	RootCfg *MakeTxCfg
+
+	Grantee         string
+	SpendLimit      string
+	Expiration      string
+	AllowedMsgs     commands.StringArr
+	AllowedPkgPaths commands.StringArr
+}
+
+func NewMakeGrantCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeGrantCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "grant",
+			ShortUsage: "grant [flags] <key-name or address>",
+			ShortHelp:  "grants a fee allowance to a grantee",
+			LongHelp:   "Grants a fee allowance to a grantee, whose txs setting the granter as their fee granter have their gas fee paid by the granter.",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeGrant(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeGrantCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Grantee,
+		"grantee",
+		"",
+		"grantee address",
+	)
+
+	fs.StringVar(
+		&c.SpendLimit,
+		"spend-limit",
+		"",
+		"maximum amount of fees paid (no limit if empty)",
+	)
+
+	fs.StringVar(
+		&c.Expiration,
+		"expiration",
+		"",
+		"RFC3339 time from which the allowance expires (never if empty)",
+	)
+
+	fs.Var(
+		&c.AllowedMsgs,
+		"allowed-msg",
+		"type of the messages allowed, as <route>/<type> (eg. vm/exec), can be used multiple times (all if not set)",
+	)
+
+	fs.Var(
+		&c.AllowedPkgPaths,
+		"allowed-pkgpath",
+		"path of the packages the messages are allowed to call, can be used multiple times (all if not set)",
+	)
+}
+
+func execMakeGrant(cfg *MakeGrantCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	// Parse the allowance.
+	spendLimit, err := std.ParseCoins(cfg.SpendLimit)
+	if err != nil {
+		return errors.Wrap(err, "parsing spend limit coins")
+	}
+	var expiration time.Time
+	if cfg.Expiration != "" {
+		expiration, err = time.Parse(time.RFC3339, cfg.Expiration)
+		if err != nil {
+			return errors.Wrap(err, "parsing expiration time")
+		}
+	}
+	allowance := auth.FeeAllowance{
+		SpendLimit:      spendLimit,
+		Expiration:      expiration,
+		AllowedMsgs:     cfg.AllowedMsgs,
+		AllowedPkgPaths: cfg.AllowedPkgPaths,
+	}
+
+	return execMakeAllowanceTx(cfg.RootCfg, args, cfg.Grantee, io, func(granter, grantee crypto.Address) std.Msg {
+		return auth.NewMsgGrantAllowance(granter, grantee, allowance)
+	})
+}
+
+type MakeRevokeCfg struct {
+	RootCfg *MakeTxCfg
+
+	Grantee string
+}
+
+func NewMakeRevokeCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeRevokeCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "revoke",
+			ShortUsage: "revoke [flags] <key-name or address>",
+			ShortHelp:  "revokes the fee allowance of a grantee",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeRevoke(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeRevokeCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.Grantee,
+		"grantee",
+		"",
+		"grantee address",
+	)
+}
+
+func execMakeRevoke(cfg *MakeRevokeCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.Grantee == "" {
+		return errors.New("grantee must be specified")
+	}
+
+	return execMakeAllowanceTx(cfg.RootCfg, args, cfg.Grantee, io, func(granter, grantee crypto.Address) std.Msg {
+		return auth.NewMsgRevokeAllowance(granter, grantee)
+	})
+}
+
+// execMakeAllowanceTx makes the tx of a fee allowance msg, signed by the
+// granter.
+func execMakeAllowanceTx(
+	cfg *MakeTxCfg,
+	args []string,
+	b32grantee string,
+	io commands.IO,
+	newMsg func(granter, grantee crypto.Address) std.Msg,
+) error {
+	if cfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	granter := info.GetAddress()
+
+	// Parse grantee address.
+	grantee, err := crypto.AddressFromBech32(b32grantee)
+	if err != nil {
+		return err
+	}
+
+	// parse gas wanted & fee.
+	gasfee, err := std.ParseCoin(cfg.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+	fee := std.NewFee(cfg.GasWanted, gasfee)
+	fee.Granter, err = cfg.ParseFeeGranter()
+	if err != nil {
+		return errors.Wrap(err, "parsing fee granter address")
+	}
+
+	// construct msg & tx and marshal.
+	tx := std.Tx{
+		Msgs:          []std.Msg{newMsg(granter, grantee)},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
+	}
+
+	if cfg.Broadcast {
+		err := ExecSignAndBroadcast(cfg, args, tx, io)
+		if err != nil {
+			return err
+		}
+	} else {
+		io.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	types "github.com/gnolang/gno/tm2/pkg/bft/rpc/core/types"
 	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -16,9 +17,11 @@ import (
 type MakeTxCfg struct {
 	RootCfg *BaseCfg
 
-	GasWanted int64
-	GasFee    string
-	Memo      string
+	GasWanted     int64
+	GasFee        string
+	Memo          string
+	TimeoutHeight uint64
+	FeeGranter    string
 
 	Broadcast bool
 	// Valid options are SimulateTest, SimulateSkip or SimulateOnly.
@@ -42,6 +45,19 @@ func (c *MakeTxCfg) Validate() error {
 	return nil
 }
 
+// ParseFeeGranter parses the address of the fee granter, if any.
+func (c *MakeTxCfg) ParseFeeGranter() (crypto.Bech32Address, error) {
+	if c.FeeGranter == "" {
+		return "", nil
+	}
+
+	granter, err := crypto.AddressFromBech32(c.FeeGranter)
+	if err != nil {
+		return "", err
+	}
+	return granter.Bech32(), nil
+}
+
 func NewMakeTxCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 	cfg := &MakeTxCfg{
 		RootCfg: rootCfg,
@@ -59,6 +75,10 @@ func NewMakeTxCmd(rootCfg *BaseCfg, io commands.IO) *commands.Command {
 
 	cmd.AddSubCommands(
 		NewMakeSendCmd(cfg, io),
+		NewMakeGrantCmd(cfg, io),
+		NewMakeRevokeCmd(cfg, io),
+		NewMakeCreateSessionCmd(cfg, io),
+		NewMakeRevokeSessionCmd(cfg, io),
 	)
 
 	return cmd
@@ -86,6 +106,20 @@ func (c *MakeTxCfg) RegisterFlags(fs *flag.FlagSet) {
 		"any descriptive text",
 	)
 
+	fs.Uint64Var(
+		&c.TimeoutHeight,
+		"timeout-height",
+		0,
+		"last block height the tx can be included in (0 means no timeout)",
+	)
+
+	fs.StringVar(
+		&c.FeeGranter,
+		"fee-granter",
+		"",
+		"address of the account paying the gas fee, from the fee allowance it granted",
+	)
+
 	fs.BoolVar(
 		&c.Broadcast,
 		"broadcast",
@@ -103,6 +103,11 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 	if err != nil {
 		return errors.Wrap(err, "parsing gas fee coin")
 	}
+	fee := std.NewFee(gaswanted, gasfee)
+	fee.Granter, err = cfg.RootCfg.ParseFeeGranter()
+	if err != nil {
+		return errors.Wrap(err, "parsing fee granter address")
+	}
 
 	// construct msg & tx and marshal.
 	msg := bank.MsgSend{
@@ -111,10 +116,11 @@ func execMakeSend(cfg *MakeSendCfg, args []string, io commands.IO) error {
 		Amount:      send,
 	}
 	tx := std.Tx{
-		Msgs:       []std.Msg{msg},
-		Fee:        std.NewFee(gaswanted, gasfee),
-		Signatures: nil,
-		Memo:       cfg.RootCfg.Memo,
+		Msgs:          []std.Msg{msg},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.RootCfg.Memo,
+		TimeoutHeight: cfg.RootCfg.TimeoutHeight,
 	}
 
 	if cfg.RootCfg.Broadcast {
@@ -0,0 +1,244 @@
+package client
+
+import (
+	"context"
+	"flag"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/commands"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/crypto/keys"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+type MakeCreateSessionCfg struct {
+	RootCfg *MakeTxCfg
+
+	SessionKey   string
+	Expiration   string
+	SpendLimit   string
+	AllowedPaths commands.StringArr
+}
+
+func NewMakeCreateSessionCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeCreateSessionCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "create-session",
+			ShortUsage: "create-session [flags] <key-name or address>",
+			ShortHelp:  "registers a session key on an account",
+			LongHelp: "Registers a session key on an account, which can sign the txs of the account calling the allowed realm functions until it expires. " +
+				"The txs are made for the account, and signed by the session key with the account number and sequence of the account (see gnokey sign).",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeCreateSession(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeCreateSessionCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.SessionKey,
+		"session-key",
+		"",
+		"name or address of the session key in the keybase, or its bech32 public key",
+	)
+
+	fs.StringVar(
+		&c.Expiration,
+		"expiration",
+		"",
+		"RFC3339 time from which the session expires",
+	)
+
+	fs.StringVar(
+		&c.SpendLimit,
+		"spend-limit",
+		"",
+		"maximum amount of coins spent in fees, sent coins and storage deposits (no limit if empty)",
+	)
+
+	fs.Var(
+		&c.AllowedPaths,
+		"allowed-path",
+		"function the session can call, as <pkgpath>.<func> or <pkgpath>.* for all the functions of a realm, can be used multiple times",
+	)
+}
+
+func execMakeCreateSession(cfg *MakeCreateSessionCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.SessionKey == "" {
+		return errors.New("session key must be specified")
+	}
+	if cfg.Expiration == "" {
+		return errors.New("expiration must be specified")
+	}
+
+	// Parse the session.
+	pubKey, err := parseSessionPubKey(cfg.RootCfg.RootCfg.Home, cfg.SessionKey)
+	if err != nil {
+		return err
+	}
+	expiration, err := time.Parse(time.RFC3339, cfg.Expiration)
+	if err != nil {
+		return errors.Wrap(err, "parsing expiration time")
+	}
+	spendLimit, err := std.ParseCoins(cfg.SpendLimit)
+	if err != nil {
+		return errors.Wrap(err, "parsing spend limit coins")
+	}
+	session := auth.Session{
+		PubKey:       pubKey,
+		Expiration:   expiration,
+		SpendLimit:   spendLimit,
+		AllowedPaths: cfg.AllowedPaths,
+	}
+
+	return execMakeSessionTx(cfg.RootCfg, args, io, func(addr crypto.Address) std.Msg {
+		return auth.NewMsgCreateSession(addr, session)
+	})
+}
+
+type MakeRevokeSessionCfg struct {
+	RootCfg *MakeTxCfg
+
+	SessionKey string
+}
+
+func NewMakeRevokeSessionCmd(rootCfg *MakeTxCfg, io commands.IO) *commands.Command {
+	cfg := &MakeRevokeSessionCfg{
+		RootCfg: rootCfg,
+	}
+
+	return commands.NewCommand(
+		commands.Metadata{
+			Name:       "revoke-session",
+			ShortUsage: "revoke-session [flags] <key-name or address>",
+			ShortHelp:  "removes a session key from an account",
+		},
+		cfg,
+		func(_ context.Context, args []string) error {
+			return execMakeRevokeSession(cfg, args, io)
+		},
+	)
+}
+
+func (c *MakeRevokeSessionCfg) RegisterFlags(fs *flag.FlagSet) {
+	fs.StringVar(
+		&c.SessionKey,
+		"session-key",
+		"",
+		"address of the session key, or its name in the keybase",
+	)
+}
+
+func execMakeRevokeSession(cfg *MakeRevokeSessionCfg, args []string, io commands.IO) error {
+	if len(args) != 1 {
+		return flag.ErrHelp
+	}
+	if cfg.SessionKey == "" {
+		return errors.New("session key must be specified")
+	}
+
+	sessionKey, err := crypto.AddressFromBech32(cfg.SessionKey)
+	if err != nil {
+		kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.RootCfg.Home)
+		if err != nil {
+			return err
+		}
+		info, err := kb.GetByName(cfg.SessionKey)
+		if err != nil {
+			return errors.Wrap(err, "getting session key")
+		}
+		sessionKey = info.GetAddress()
+	}
+
+	return execMakeSessionTx(cfg.RootCfg, args, io, func(addr crypto.Address) std.Msg {
+		return auth.NewMsgRevokeSession(addr, sessionKey)
+	})
+}
+
+// parseSessionPubKey returns the public key of a session key, given as a
+// bech32 public key, or as the name or address of a key in the keybase.
+func parseSessionPubKey(home, sessionKey string) (crypto.PubKey, error) {
+	if pubKey, err := crypto.PubKeyFromBech32(sessionKey); err == nil {
+		return pubKey, nil
+	}
+
+	kb, err := keys.NewKeyBaseFromDir(home)
+	if err != nil {
+		return nil, err
+	}
+	info, err := kb.GetByNameOrAddress(sessionKey)
+	if err != nil {
+		return nil, errors.Wrap(err, "getting session key")
+	}
+	return info.GetPubKey(), nil
+}
+
+// execMakeSessionTx makes the tx of a session msg, signed by the account of
+// the given key.
+func execMakeSessionTx(
+	cfg *MakeTxCfg,
+	args []string,
+	io commands.IO,
+	newMsg func(signer crypto.Address) std.Msg,
+) error {
+	if cfg.GasWanted == 0 {
+		return errors.New("gas-wanted not specified")
+	}
+	if cfg.GasFee == "" {
+		return errors.New("gas-fee not specified")
+	}
+
+	// read account pubkey.
+	nameOrBech32 := args[0]
+	kb, err := keys.NewKeyBaseFromDir(cfg.RootCfg.Home)
+	if err != nil {
+		return err
+	}
+	info, err := kb.GetByNameOrAddress(nameOrBech32)
+	if err != nil {
+		return err
+	}
+	signer := info.GetAddress()
+
+	// parse gas wanted & fee.
+	gasfee, err := std.ParseCoin(cfg.GasFee)
+	if err != nil {
+		return errors.Wrap(err, "parsing gas fee coin")
+	}
+	fee := std.NewFee(cfg.GasWanted, gasfee)
+	fee.Granter, err = cfg.ParseFeeGranter()
+	if err != nil {
+		return errors.Wrap(err, "parsing fee granter address")
+	}
+
+	// construct msg & tx and marshal.
+	tx := std.Tx{
+		Msgs:          []std.Msg{newMsg(signer)},
+		Fee:           fee,
+		Signatures:    nil,
+		Memo:          cfg.Memo,
+		TimeoutHeight: cfg.TimeoutHeight,
+	}
+
+	if cfg.Broadcast {
+		err := ExecSignAndBroadcast(cfg, args, tx, io)
+		if err != nil {
+			return err
+		}
+	} else {
+		io.Println(string(amino.MustMarshalJSON(tx)))
+	}
+	return nil
+}
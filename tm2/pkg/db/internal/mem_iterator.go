@@ -2,26 +2,28 @@ package internal
 
 import "github.com/gnolang/gno/tm2/pkg/db"
 
-// We need a copy of all of the keys.
+// We need a copy of all of the keys and values, so that the iterator sees
+// the DB as of its creation.
 // Not the best, but probably not a bottleneck depending.
 type MemIterator struct {
-	db    db.DB
-	cur   int
-	keys  []string
-	start []byte
-	end   []byte
+	cur    int
+	keys   []string
+	values [][]byte
+	start  []byte
+	end    []byte
 }
 
 var _ db.Iterator = (*MemIterator)(nil)
 
-// Keys is expected to be in reverse order for reverse iterators.
-func NewMemIterator(db db.DB, keys []string, start, end []byte) *MemIterator {
+// Keys is expected to be in reverse order for reverse iterators, and values
+// in the order of the keys.
+func NewMemIterator(keys []string, values [][]byte, start, end []byte) *MemIterator {
 	return &MemIterator{
-		db:    db,
-		cur:   0,
-		keys:  keys,
-		start: start,
-		end:   end,
+		cur:    0,
+		keys:   keys,
+		values: values,
+		start:  start,
+		end:    end,
 	}
 }
 
@@ -50,14 +52,13 @@ func (itr *MemIterator) Key() []byte {
 // Implements Iterator.
 func (itr *MemIterator) Value() []byte {
 	itr.assertIsValid()
-	key := []byte(itr.keys[itr.cur])
-	return itr.db.Get(key)
+	return itr.values[itr.cur]
 }
 
 // Implements Iterator.
 func (itr *MemIterator) Close() {
 	itr.keys = nil
-	itr.db = nil
+	itr.values = nil
 }
 
 func (itr *MemIterator) assertIsValid() {
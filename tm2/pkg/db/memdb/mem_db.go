@@ -163,7 +163,7 @@ func (db *MemDB) Iterator(start, end []byte) dbm.Iterator {
 	defer db.mtx.Unlock()
 
 	keys := db.getSortedKeys(start, end, false)
-	return internal.NewMemIterator(db, keys, start, end)
+	return internal.NewMemIterator(keys, db.getValues(keys), start, end)
 }
 
 // Implements DB.
@@ -172,12 +172,20 @@ func (db *MemDB) ReverseIterator(start, end []byte) dbm.Iterator {
 	defer db.mtx.Unlock()
 
 	keys := db.getSortedKeys(start, end, true)
-	return internal.NewMemIterator(db, keys, start, end)
+	return internal.NewMemIterator(keys, db.getValues(keys), start, end)
 }
 
 // ----------------------------------------
 // Misc.
 
+func (db *MemDB) getValues(keys []string) [][]byte {
+	values := make([][]byte, len(keys))
+	for i, key := range keys {
+		values[i] = db.db[key]
+	}
+	return values
+}
+
 func (db *MemDB) getSortedKeys(start, end []byte, reverse bool) []string {
 	keys := []string{}
 	for key := range db.db {
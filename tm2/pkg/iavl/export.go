@@ -0,0 +1,77 @@
+package iavl
+
+import "bytes"
+
+// ExportNode is a node of an exported tree. Nodes are exported in post-order,
+// i.e. the children of an inner node are exported before it, which is the
+// order in which an Importer rebuilds the tree.
+type ExportNode struct {
+	Key     []byte
+	Value   []byte // nil for inner nodes
+	Version int64
+	Height  int8 // 0 for leaf nodes
+}
+
+// Export calls fn for each node of the tree, in post-order. It stops at the
+// first error returned by fn, and returns it.
+//
+// The tree version must not be deleted while it is being exported.
+func (t *ImmutableTree) Export(fn func(ExportNode) error) error {
+	if t.root == nil {
+		return nil
+	}
+
+	return t.exportNode(t.root, fn)
+}
+
+func (t *ImmutableTree) exportNode(node *Node, fn func(ExportNode) error) error {
+	if !node.isLeaf() {
+		if err := t.exportNode(node.getLeftNode(t), fn); err != nil {
+			return err
+		}
+		if err := t.exportNode(node.getRightNode(t), fn); err != nil {
+			return err
+		}
+	}
+
+	return fn(ExportNode{
+		Key:     node.key,
+		Value:   node.value,
+		Version: node.version,
+		Height:  node.height,
+	})
+}
+
+// IsTreeKey returns whether the given key-value pair of the database of a
+// tree was written by the tree: a node, an orphan or a root. Nodes and
+// orphans are recognized by their hash, so that the keys written by other
+// stores sharing the database are not mistaken for them.
+func IsTreeKey(key, value []byte) bool {
+	if len(key) == 0 {
+		return false
+	}
+
+	switch key[0] {
+	case nodeKeyFormat.prefix:
+		if len(key) != nodeKeyFormat.length {
+			return false
+		}
+		node, err := MakeNode(value)
+		if err != nil {
+			return false
+		}
+
+		return bytes.Equal(node._hash(), key[1:])
+
+	case orphanKeyFormat.prefix:
+		return len(key) == orphanKeyFormat.length &&
+			bytes.Equal(value, key[len(key)-hashSize:])
+
+	case rootKeyFormat.prefix:
+		return len(key) == rootKeyFormat.length &&
+			(len(value) == 0 || len(value) == hashSize)
+
+	default:
+		return false
+	}
+}
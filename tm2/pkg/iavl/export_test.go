@@ -0,0 +1,174 @@
+package iavl
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+)
+
+// makeExportTree saves a few versions of a tree with updated and removed keys
+func makeExportTree(t *testing.T) *MutableTree {
+	t.Helper()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+	for v := range 3 {
+		for i := range 50 {
+			tree.Set([]byte(fmt.Sprintf("key-%03d", i*(v+1))), []byte(fmt.Sprintf("value-%d-%d", v, i)))
+		}
+		for i := range 5 {
+			tree.Remove([]byte(fmt.Sprintf("key-%03d", i*7)))
+		}
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	return tree
+}
+
+func exportTree(t *testing.T, tree *MutableTree, version int64) []ExportNode {
+	t.Helper()
+
+	itree, err := tree.GetImmutable(version)
+	require.NoError(t, err)
+
+	var nodes []ExportNode
+	require.NoError(t, itree.Export(func(node ExportNode) error {
+		nodes = append(nodes, node)
+		return nil
+	}))
+
+	return nodes
+}
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+
+	tree := makeExportTree(t)
+
+	for _, version := range []int64{2, 3} {
+		itree, err := tree.GetImmutable(version)
+		require.NoError(t, err)
+
+		nodes := exportTree(t, tree, version)
+		require.NotEmpty(t, nodes)
+
+		imported := NewMutableTree(memdb.NewMemDB(), 0)
+		importer, err := imported.Import(version)
+		require.NoError(t, err)
+		for _, node := range nodes {
+			require.NoError(t, importer.Add(node))
+		}
+		require.NoError(t, importer.Commit())
+
+		assert.Equal(t, version, imported.Version())
+		assert.Equal(t, itree.Hash(), imported.Hash())
+		assert.Equal(t, itree.Size(), imported.Size())
+		itree.Iterate(func(key, value []byte) bool {
+			_, got := imported.Get(key)
+			assert.Equal(t, value, got, "key %s", key)
+			return false
+		})
+
+		// The imported tree can be updated
+		imported.Set([]byte("new"), []byte("value"))
+		_, saved, err := imported.SaveVersion()
+		require.NoError(t, err)
+		assert.Equal(t, version+1, saved)
+	}
+}
+
+func TestImport_EmptyTree(t *testing.T) {
+	t.Parallel()
+
+	tree := NewMutableTree(memdb.NewMemDB(), 0)
+	importer, err := tree.Import(5)
+	require.NoError(t, err)
+	require.NoError(t, importer.Commit())
+
+	assert.Equal(t, int64(5), tree.Version())
+	assert.Nil(t, tree.Hash())
+}
+
+func TestImport_Invalid(t *testing.T) {
+	t.Parallel()
+
+	nodes := exportTree(t, makeExportTree(t), 3)
+
+	t.Run("tree not empty", func(t *testing.T) {
+		t.Parallel()
+
+		_, err := makeExportTree(t).Import(4)
+		assert.ErrorIs(t, err, ErrNotEmpty)
+	})
+
+	t.Run("missing children", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(3)
+		require.NoError(t, err)
+		assert.ErrorIs(t, importer.Add(nodes[len(nodes)-1]), ErrInvalidImport)
+	})
+
+	t.Run("version after the import", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(2)
+		require.NoError(t, err)
+
+		var addErr error
+		for _, node := range nodes {
+			if addErr = importer.Add(node); addErr != nil {
+				break
+			}
+		}
+		assert.ErrorIs(t, addErr, ErrInvalidImport)
+	})
+
+	t.Run("incomplete tree", func(t *testing.T) {
+		t.Parallel()
+
+		importer, err := NewMutableTree(memdb.NewMemDB(), 0).Import(3)
+		require.NoError(t, err)
+		require.NoError(t, importer.Add(nodes[0]))
+		require.NoError(t, importer.Add(nodes[0]))
+		assert.ErrorIs(t, importer.Commit(), ErrIncompleteTree)
+	})
+}
+
+func TestIsTreeKey(t *testing.T) {
+	t.Parallel()
+
+	db := memdb.NewMemDB()
+	tree := NewMutableTree(db, 0)
+	for v := range 3 {
+		for i := range 20 {
+			tree.Set([]byte(fmt.Sprintf("key-%d", i)), []byte(fmt.Sprintf("value-%d-%d", v, i)))
+		}
+		_, _, err := tree.SaveVersion()
+		require.NoError(t, err)
+	}
+
+	// All the keys written by the tree are recognized
+	itr := db.Iterator(nil, nil)
+	defer itr.Close()
+	for ; itr.Valid(); itr.Next() {
+		assert.True(t, IsTreeKey(itr.Key(), itr.Value()), "key %X", itr.Key())
+	}
+
+	// Keys of the same formats, written by another store, are not
+	hash := make([]byte, hashSize)
+	for _, kv := range [][2][]byte{
+		{nodeKeyFormat.KeyBytes(hash), []byte("value")},
+		{orphanKeyFormat.Key(int64(1), int64(2), hash), []byte("value")},
+		{rootKeyFormat.Key(int64(1)), []byte("value")},
+		{[]byte("node:main.gno:1:2"), []byte("value")},
+		{[]byte("oid:0000000000000000000000000000000000000000:1"), []byte("value")},
+		{[]byte{}, []byte("value")},
+	} {
+		assert.False(t, IsTreeKey(kv[0], kv[1]), "key %X", kv[0])
+	}
+}
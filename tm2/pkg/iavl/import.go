@@ -0,0 +1,136 @@
+package iavl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// importBatchSize is the number of imported nodes written in a single batch.
+const importBatchSize = 10000
+
+var (
+	ErrNotEmpty       = errors.New("tree must be empty to import")
+	ErrImportClosed   = errors.New("importer is closed")
+	ErrInvalidImport  = errors.New("invalid imported node")
+	ErrIncompleteTree = errors.New("imported nodes do not form a single tree")
+)
+
+// Importer rebuilds a tree version from the nodes of an exported tree,
+// added in the order of the export. The tree version is only saved by
+// Commit; the nodes added before are written to the database as they are
+// imported.
+type Importer struct {
+	tree    *MutableTree
+	version int64
+	stack   []*Node // nodes whose parent is not imported yet
+	batched int
+	closed  bool
+}
+
+// Import returns an Importer of the given version of the tree, which must
+// not have any saved version.
+func (tree *MutableTree) Import(version int64) (*Importer, error) {
+	if version <= 0 {
+		return nil, fmt.Errorf("invalid import version %d", version)
+	}
+	if tree.ndb.getLatestVersion() > 0 {
+		return nil, ErrNotEmpty
+	}
+
+	return &Importer{
+		tree:    tree,
+		version: version,
+	}, nil
+}
+
+// Add adds the next exported node to the imported tree.
+func (i *Importer) Add(exported ExportNode) error {
+	if i.closed {
+		return ErrImportClosed
+	}
+	if exported.Version <= 0 || exported.Version > i.version {
+		return fmt.Errorf("%w: version %d is not in (0, %d]", ErrInvalidImport, exported.Version, i.version)
+	}
+
+	node := &Node{
+		key:     exported.Key,
+		value:   exported.Value,
+		version: exported.Version,
+		height:  exported.Height,
+		size:    1,
+	}
+
+	switch {
+	case exported.Height < 0:
+		return fmt.Errorf("%w: negative height %d", ErrInvalidImport, exported.Height)
+
+	case exported.Height > 0:
+		// An inner node, whose children are the last imported nodes
+		if len(i.stack) < 2 {
+			return fmt.Errorf("%w: missing children of inner node at height %d", ErrInvalidImport, exported.Height)
+		}
+		if exported.Value != nil {
+			return fmt.Errorf("%w: inner node with a value", ErrInvalidImport)
+		}
+
+		left, right := i.stack[len(i.stack)-2], i.stack[len(i.stack)-1]
+		if exported.Height != max(left.height, right.height)+1 {
+			return fmt.Errorf("%w: inner node height %d does not match its children heights %d and %d",
+				ErrInvalidImport, exported.Height, left.height, right.height)
+		}
+		i.stack = i.stack[:len(i.stack)-2]
+
+		node.size = left.size + right.size
+		node.leftHash = left.hash
+		node.rightHash = right.hash
+	}
+
+	node._hash()
+	i.tree.ndb.SaveNode(node)
+	i.stack = append(i.stack, node)
+
+	i.batched++
+	if i.batched >= importBatchSize {
+		i.tree.ndb.Commit()
+		i.batched = 0
+	}
+
+	return nil
+}
+
+// Commit saves the imported tree version, and loads it in the tree.
+func (i *Importer) Commit() error {
+	if i.closed {
+		return ErrImportClosed
+	}
+	if len(i.stack) > 1 {
+		return ErrIncompleteTree
+	}
+	i.closed = true
+
+	ndb := i.tree.ndb
+
+	// The imported version is the first one of the tree, so it is saved
+	// directly instead of with the consecutive versions check of saveRoot
+	rootHash := []byte{}
+	if len(i.stack) == 1 {
+		rootHash = i.stack[0].hash
+	}
+
+	ndb.mtx.Lock()
+	ndb.batch.Set(ndb.rootKey(i.version), rootHash)
+	ndb.updateLatestVersion(i.version)
+	ndb.mtx.Unlock()
+	ndb.Commit()
+
+	_, err := i.tree.LoadVersion(i.version)
+
+	return err
+}
+
+// Close discards the importer. The nodes already written to the database
+// are not removed.
+func (i *Importer) Close() {
+	i.closed = true
+	i.stack = nil
+}
@@ -3,7 +3,9 @@ package auth
 import (
 	"encoding/hex"
 	"fmt"
+	"math"
 	"math/big"
+	"slices"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
@@ -37,7 +39,8 @@ type AnteOptions struct {
 
 // NewAnteHandler returns an AnteHandler that checks and increments sequence
 // numbers, checks signatures & account numbers, and deducts fees from the first
-// signer.
+// signer, or from its fee granter. The signatures of the session keys of the
+// signers (see Session) are checked against the limits of their session.
 func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer SignatureVerificationGasConsumer, opts AnteOptions) sdk.AnteHandler {
 	return func(
 		ctx sdk.Context, tx std.Tx, simulate bool,
@@ -108,6 +111,10 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			return newCtx, res, true
 		}
 
+		if res := ValidateTimeoutHeight(ctx, tx); !res.IsOK() {
+			return newCtx, res, true
+		}
+
 		// stdSigs contains the sequence number, account number, and signatures.
 		// When simulating, this would just be a 0-length slice.
 		signerAddrs := tx.GetSigners()
@@ -120,9 +127,17 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 			return newCtx, res, true
 		}
 
-		// deduct the fees
+		// deduct the fees, from the fee granter if any
 		if !tx.Fee.GasFee.IsZero() {
-			res = DeductFees(bank, newCtx, signerAccs[0], ak.FeeCollectorAddress(ctx), std.Coins{tx.Fee.GasFee})
+			payerAcc := signerAccs[0]
+			if tx.Fee.Granter != "" {
+				payerAcc, res = GetFeeGranterAcc(newCtx, ak, tx, signerAddrs[0])
+				if !res.IsOK() {
+					return newCtx, res, true
+				}
+			}
+
+			res = DeductFees(bank, newCtx, payerAcc, ak.FeeCollectorAddress(ctx), std.Coins{tx.Fee.GasFee})
 			if !res.IsOK() {
 				return newCtx, res, true
 			}
@@ -135,6 +150,12 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 		// When simulating, this would just be a 0-length slice.
 		stdSigs := tx.GetSignatures()
 
+		// sequence of the fee payer signature, reported to the mempool
+		var (
+			sequence uint64
+			replaces bool
+		)
+
 		for i := range stdSigs {
 			// skip the fee payer, account is cached and fees were deducted already
 			if i != 0 {
@@ -154,7 +175,22 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 				if err != nil {
 					return newCtx, res, true
 				}
-				signerAccs[i], res = processSig(newCtx, sacc, stdSigs[i], signBytes, simulate, params, sigGasConsumer)
+				if i == 0 {
+					sequence = sacc.GetSequence()
+				}
+				session, isSession := getSigSession(newCtx, ak, sacc, stdSigs[i])
+				if isSession {
+					signerAccs[i], res = processSessionSig(newCtx, ak, sacc, session, stdSigs[i], signBytes, tx, i == 0, simulate, params, sigGasConsumer)
+				} else {
+					signerAccs[i], res = processSig(newCtx, sacc, stdSigs[i], signBytes, simulate, params, sigGasConsumer)
+				}
+				if _, unauthorized := res.Error.(std.UnauthorizedError); unauthorized && !isSession && i == 0 && ctx.IsCheckTx() && !simulate {
+					// The fee payer may replace one of its pending txs in the
+					// mempool, by signing with its sequence.
+					if seq, ok := processReplacementSig(newCtx, ak, sacc, stdSigs[i], tx, params, sigGasConsumer); ok {
+						signerAccs[i], res, sequence, replaces = sacc, sdk.Result{}, seq, true
+					}
+				}
 				if !res.IsOK() {
 					return newCtx, res, true
 				}
@@ -163,8 +199,110 @@ func NewAnteHandler(ak AccountKeeper, bank BankKeeperI, sigGasConsumer Signature
 		}
 
 		// TODO: tx tags (?)
-		return newCtx, sdk.Result{GasWanted: tx.Fee.GasWanted}, false // continue...
+		res = sdk.Result{GasWanted: tx.Fee.GasWanted}
+		if ctx.IsCheckTx() {
+			res.Priority = TxPriority(tx.Fee)
+			res.Sender = signerAddrs[0].String()
+			res.Sequence = sequence
+			res.Replaces = replaces
+		}
+		return newCtx, res, false // continue...
+	}
+}
+
+// TxPriority returns the priority of a tx in the mempool, that is its gas
+// price: the gas fee paid per million units of gas wanted. The txs whose gas
+// price is lower than the one of the last block (see GasPriceKeeper) are
+// rejected on CheckTx.
+func TxPriority(fee std.Fee) int64 {
+	if fee.GasWanted <= 0 || !fee.GasFee.IsPositive() {
+		return 0
+	}
+
+	priority := big.NewInt(fee.GasFee.Amount)
+	priority.Mul(priority, big.NewInt(1_000_000))
+	priority.Quo(priority, big.NewInt(fee.GasWanted))
+	if !priority.IsInt64() {
+		return math.MaxInt64
 	}
+	return priority.Int64()
+}
+
+// maxReplacedSequences is the maximum number of pending sequences tried to
+// verify the signature of a tx replacing a pending one.
+const maxReplacedSequences = 16
+
+// processReplacementSig verifies the signature of the fee payer of a tx which
+// replaces one of its pending txs in the mempool, that is signed with one of
+// the sequences between its last committed one and its current one in the
+// check state. It returns the sequence of the signature, which isn't
+// incremented. Each sequence tried consumes the gas of a signature
+// verification.
+//
+// The txs are only replaced if the mempool evicts the pending tx they replace,
+// ie. if the committed state is given (see sdk.CommittedMultiStoreContextKey).
+func processReplacementSig(
+	ctx sdk.Context, ak AccountKeeper, acc std.Account, sig std.Signature, tx std.Tx, params Params,
+	sigGasConsumer SignatureVerificationGasConsumer,
+) (uint64, bool) {
+	committedStore, ok := ctx.Value(sdk.CommittedMultiStoreContextKey{}).(func() store.MultiStore)
+	if !ok {
+		return 0, false
+	}
+	pubKey := acc.GetPubKey()
+	if pubKey == nil {
+		return 0, false
+	}
+
+	var committedSeq uint64
+	if committed := ak.GetAccount(ctx.WithMultiStore(committedStore()), acc.GetAddress()); committed != nil {
+		committedSeq = committed.GetSequence()
+	}
+
+	seq := acc.GetSequence()
+	for i := 0; i < maxReplacedSequences && seq > committedSeq; i++ {
+		seq--
+		if res := sigGasConsumer(ctx.GasMeter(), sig.Signature, pubKey, params); !res.IsOK() {
+			return 0, false
+		}
+		signBytes, err := std.GetSignaturePayload(
+			std.SignDoc{
+				ChainID:       ctx.ChainID(),
+				AccountNumber: acc.GetAccountNumber(),
+				Sequence:      seq,
+				Fee:           tx.Fee,
+				Msgs:          tx.Msgs,
+				Memo:          tx.Memo,
+				TimeoutHeight: tx.TimeoutHeight,
+			},
+		)
+		if err != nil {
+			return 0, false
+		}
+		if pubKey.VerifyBytes(signBytes, sig.Signature) {
+			return seq, true
+		}
+	}
+
+	return 0, false
+}
+
+// GetFeeGranterAcc returns the account of the fee granter of a transaction,
+// once the fee is deducted from the allowance it granted to the fee payer.
+func GetFeeGranterAcc(ctx sdk.Context, ak AccountKeeper, tx std.Tx, payer crypto.Address) (std.Account, sdk.Result) {
+	granter, err := crypto.AddressFromBech32(string(tx.Fee.Granter))
+	if err != nil {
+		return nil, abciResult(std.ErrInvalidAddress(fmt.Sprintf("invalid fee granter %s", tx.Fee.Granter)))
+	}
+	if err := ak.UseFeeAllowance(ctx, granter, payer, std.Coins{tx.Fee.GasFee}, tx.GetMsgs()); err != nil {
+		return nil, abciResult(err)
+	}
+
+	acc := ak.GetAccount(ctx, granter)
+	if acc == nil {
+		return nil, abciResult(std.ErrUnknownAddress(fmt.Sprintf("fee granter %s does not exist", granter)))
+	}
+	return acc, sdk.Result{}
 }
 
 // GetSignerAcc returns an account for a given address that is expected to sign
@@ -209,6 +347,30 @@ func ValidateMemo(tx std.Tx, params Params) sdk.Result {
 	return sdk.Result{}
 }
 
+// ValidateTimeoutHeight validates that the tx can still be included in the
+// block. On CheckTx, and on the recheck of the mempool, the block is the next
+// one, since the height of the check state is the last committed one.
+func ValidateTimeoutHeight(ctx sdk.Context, tx std.Tx) sdk.Result {
+	if tx.TimeoutHeight == 0 {
+		return sdk.Result{}
+	}
+
+	height := ctx.BlockHeight()
+	if ctx.Mode() != sdk.RunTxModeDeliver {
+		height++
+	}
+	if uint64(height) > tx.TimeoutHeight {
+		return abciResult(std.ErrTxTimeout(
+			fmt.Sprintf(
+				"tx timeout height %d is lower than the block height %d",
+				tx.TimeoutHeight, height,
+			),
+		))
+	}
+
+	return sdk.Result{}
+}
+
 // verify the signature and increment the sequence. If the account doesn't
 // have a pubkey, set it.
 func processSig(
@@ -240,6 +402,61 @@ func processSig(
 	return acc, res
 }
 
+// getSigSession returns the session of an account whose key signed a tx, if
+// the signature is not the one of the account key.
+func getSigSession(ctx sdk.Context, ak AccountKeeper, acc std.Account, sig std.Signature) (Session, bool) {
+	if sig.PubKey == nil || sig.PubKey.Address() == acc.GetAddress() {
+		return Session{}, false
+	}
+	return ak.GetSession(ctx, acc.GetAddress(), sig.PubKey.Address())
+}
+
+// verify the signature of a session key of the account, deduct the coins
+// spent by the tx from the session, and increment the sequence of the account.
+func processSessionSig(
+	ctx sdk.Context, ak AccountKeeper, acc std.Account, session Session, sig std.Signature, signBytes []byte,
+	tx std.Tx, isFeePayer bool, simulate bool, params Params, sigGasConsumer SignatureVerificationGasConsumer,
+) (updatedAcc std.Account, res sdk.Result) {
+	if res := sigGasConsumer(ctx.GasMeter(), sig.Signature, session.PubKey, params); !res.IsOK() {
+		return nil, res
+	}
+
+	if !simulate && !session.PubKey.VerifyBytes(signBytes, sig.Signature) {
+		return nil, abciResult(std.ErrUnauthorized("session signature verification failed; verify correct account, sequence, and chain-id"))
+	}
+
+	if err := ak.UseSession(ctx, acc.GetAddress(), session.Address(), sessionSpent(tx, acc.GetAddress(), isFeePayer), tx.GetMsgs()); err != nil {
+		return nil, abciResult(err)
+	}
+
+	if err := acc.SetSequence(acc.GetSequence() + 1); err != nil {
+		panic(err)
+	}
+
+	return acc, res
+}
+
+// sessionSpent returns the coins spent by an account in a tx: the fees, if it
+// pays them, the coins sent by its messages and their maximum deposits.
+func sessionSpent(tx std.Tx, addr crypto.Address, isFeePayer bool) std.Coins {
+	var spent std.Coins
+	if isFeePayer && tx.Fee.Granter == "" && !tx.Fee.GasFee.IsZero() {
+		spent = std.Coins{tx.Fee.GasFee}
+	}
+	for _, msg := range tx.GetMsgs() {
+		if !slices.Contains(msg.GetSigners(), addr) {
+			continue
+		}
+		if smsg, ok := msg.(SendMsg); ok {
+			spent = spent.Add(smsg.GetSend())
+		}
+		if dmsg, ok := msg.(DepositMsg); ok {
+			spent = spent.Add(dmsg.GetMaxDeposit())
+		}
+	}
+	return spent
+}
+
 // ProcessPubKey verifies that the given account address matches that of the
 // std.Signature. In addition, it will set the public key of the account if it
 // has not been set.
@@ -308,7 +525,8 @@ func consumeMultisignatureVerificationGas(meter store.GasMeter,
 // NOTE: We could use the CoinKeeper (in addition to the AccountKeeper, because
 // the CoinKeeper doesn't give us accounts), but it seems easier to do this.
 func DeductFees(bk BankKeeperI, ctx sdk.Context, acc std.Account, collector crypto.Address, fees std.Coins) sdk.Result {
-	coins := acc.GetCoins()
+	// the locked coins of a vesting account cannot pay for fees
+	coins := std.SpendableCoins(acc, ctx.BlockTime())
 
 	if !fees.IsValid() {
 		return abciResult(std.ErrInsufficientFee(fmt.Sprintf("invalid fee amount: %s", fees)))
@@ -437,6 +655,7 @@ func GetSignBytes(chainID string, tx std.Tx, acc std.Account, genesis bool) ([]b
 			Fee:           tx.Fee,
 			Msgs:          tx.Msgs,
 			Memo:          tx.Memo,
+			TimeoutHeight: tx.TimeoutHeight,
 		},
 	)
 }
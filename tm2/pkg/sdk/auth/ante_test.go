@@ -2,10 +2,12 @@ package auth
 
 import (
 	"fmt"
+	"math"
 	"math/rand"
 	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -307,6 +309,322 @@ func TestAnteHandlerSequences(t *testing.T) {
 	checkValidTx(t, anteHandler, ctx, tx, false)
 }
 
+func TestAnteHandlerTimeoutHeight(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	ctx := env.ctx.WithValue(GasPriceContextKey{}, std.GasPrice{})
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc1)
+
+	newTx := func(seq, timeoutHeight uint64) std.Tx {
+		tx := std.Tx{
+			Msgs:          []std.Msg{tu.NewTestMsg(addr1)},
+			Fee:           tu.NewTestFee(),
+			TimeoutHeight: timeoutHeight,
+		}
+		signBytes, err := tx.GetSignBytes(ctx.ChainID(), 0, seq)
+		require.NoError(t, err)
+		sig, err := priv1.Sign(signBytes)
+		require.NoError(t, err)
+		tx.Signatures = []std.Signature{{PubKey: priv1.PubKey(), Signature: sig}}
+		return tx
+	}
+	atHeight := func(ctx sdk.Context, height int64) sdk.Context {
+		header := ctx.BlockHeader().(*bft.Header).Copy()
+		header.Height = height
+		return ctx.WithBlockHeader(header)
+	}
+
+	// the tx is valid up to its timeout height
+	checkValidTx(t, anteHandler, ctx, newTx(0, 1), false)
+	checkInvalidTx(t, anteHandler, atHeight(ctx, 2), newTx(1, 1), false, std.TxTimeoutError{})
+
+	// on CheckTx, the tx is included in the block following the committed one
+	checkCtx := ctx.WithMode(sdk.RunTxModeCheck)
+	checkInvalidTx(t, anteHandler, checkCtx, newTx(1, 1), false, std.TxTimeoutError{})
+	checkValidTx(t, anteHandler, checkCtx, newTx(1, 2), false)
+
+	// the tx signed without its timeout height is invalid
+	tx := newTx(2, 0)
+	tx.TimeoutHeight = 10
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+}
+
+func TestAnteHandlerFeeGrant(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	now := time.Now()
+	header := env.ctx.BlockHeader().(*bft.Header).Copy()
+	header.Time = now
+	ctx := env.ctx.WithBlockHeader(header)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	_, _, granter := tu.KeyTestPubAddr()
+
+	// the granter pays the fees of the account without coins
+	grantee := env.acck.NewAccountWithAddress(ctx, addr1)
+	env.acck.SetAccount(ctx, grantee)
+	granterAcc := env.acck.NewAccountWithAddress(ctx, granter)
+	granterAcc.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, granterAcc)
+
+	newTx := func(seq uint64) std.Tx {
+		tx := std.Tx{
+			Msgs: []std.Msg{tu.NewTestMsg(addr1)},
+			Fee:  tu.NewTestFee(),
+		}
+		tx.Fee.Granter = granter.Bech32()
+		signBytes, err := tx.GetSignBytes(ctx.ChainID(), 0, seq)
+		require.NoError(t, err)
+		sig, err := priv1.Sign(signBytes)
+		require.NoError(t, err)
+		tx.Signatures = []std.Signature{{PubKey: priv1.PubKey(), Signature: sig}}
+		return tx
+	}
+	fee := tu.NewTestFee().GasFee
+
+	// no allowance
+	checkInvalidTx(t, anteHandler, ctx, newTx(0), false, std.UnauthorizedError{})
+
+	// the allowance is used until its spend limit is exhausted
+	env.acck.SetFeeAllowance(ctx, granter, addr1, FeeAllowance{
+		SpendLimit: std.NewCoins(fee),
+		Expiration: now.Add(time.Hour),
+	})
+	checkValidTx(t, anteHandler, ctx, newTx(0), false)
+	assert.Equal(t, tu.NewTestCoins().Sub(std.NewCoins(fee)), env.acck.GetAccount(ctx, granter).GetCoins())
+	assert.True(t, env.acck.GetAccount(ctx, addr1).GetCoins().IsZero())
+	checkInvalidTx(t, anteHandler, ctx, newTx(1), false, std.UnauthorizedError{})
+
+	// expired allowance
+	env.acck.SetFeeAllowance(ctx, granter, addr1, FeeAllowance{Expiration: now})
+	checkInvalidTx(t, anteHandler, ctx, newTx(1), false, std.UnauthorizedError{})
+
+	// not allowed msgs
+	env.acck.SetFeeAllowance(ctx, granter, addr1, FeeAllowance{AllowedMsgs: []string{"bank/send"}})
+	checkInvalidTx(t, anteHandler, ctx, newTx(1), false, std.UnauthorizedError{})
+	env.acck.SetFeeAllowance(ctx, granter, addr1, FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/foo"}})
+	checkInvalidTx(t, anteHandler, ctx, newTx(1), false, std.UnauthorizedError{})
+
+	env.acck.SetFeeAllowance(ctx, granter, addr1, FeeAllowance{AllowedMsgs: []string{"TestMsg/Test message"}})
+	checkValidTx(t, anteHandler, ctx, newTx(1), false)
+}
+
+func TestAnteHandlerSession(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	now := time.Now()
+	header := env.ctx.BlockHeader().(*bft.Header).Copy()
+	header.Time = now
+	ctx := env.ctx.WithBlockHeader(header)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+	sessionPriv, sessionPubKey, sessionAddr := tu.KeyTestPubAddr()
+
+	// set the accounts
+	acc1 := env.acck.NewAccountWithAddress(ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(ctx, acc1)
+
+	newTx := func(priv crypto.PrivKey, seq uint64, msgs ...std.Msg) std.Tx {
+		tx := std.Tx{
+			Msgs: msgs,
+			Fee:  tu.NewTestFee(),
+		}
+		signBytes, err := tx.GetSignBytes(ctx.ChainID(), 0, seq)
+		require.NoError(t, err)
+		sig, err := priv.Sign(signBytes)
+		require.NoError(t, err)
+		tx.Signatures = []std.Signature{{PubKey: priv.PubKey(), Signature: sig}}
+		return tx
+	}
+	move := tu.NewTestFuncMsg(addr1, "gno.land/r/demo/game", "Move", std.NewCoins(std.NewCoin("atom", 100)))
+	fee := tu.NewTestFee().GasFee
+
+	// no session
+	checkInvalidTx(t, anteHandler, ctx, newTx(sessionPriv, 0, move), false, std.InvalidPubKeyError{})
+
+	env.acck.SetSession(ctx, addr1, Session{
+		PubKey:       sessionPubKey,
+		Expiration:   now.Add(time.Hour),
+		SpendLimit:   std.NewCoins(std.NewCoin("atom", 500)),
+		AllowedPaths: []string{"gno.land/r/demo/game.*"},
+	})
+
+	// the session key signs with the sequence of the account, and spends the
+	// fees and the sent coins
+	checkValidTx(t, anteHandler, ctx, newTx(sessionPriv, 0, move), false)
+	assert.Equal(t, uint64(1), env.acck.GetAccount(ctx, addr1).GetSequence())
+	session, ok := env.acck.GetSession(ctx, addr1, sessionAddr)
+	require.True(t, ok)
+	assert.Equal(t, std.NewCoins(std.NewCoin("atom", 500-fee.Amount-100)), session.SpendLimit)
+
+	// the account key still signs any tx
+	checkValidTx(t, anteHandler, ctx, newTx(priv1, 1, tu.NewTestMsg(addr1)), false)
+
+	// not allowed msgs
+	checkInvalidTx(t, anteHandler, ctx, newTx(sessionPriv, 2, tu.NewTestMsg(addr1)), false, std.UnauthorizedError{})
+	checkInvalidTx(t, anteHandler, ctx, newTx(sessionPriv, 2, tu.NewTestFuncMsg(addr1, "gno.land/r/demo/bank", "Send", nil)), false, std.UnauthorizedError{})
+
+	// invalid signature
+	checkInvalidTx(t, anteHandler, ctx, newTx(sessionPriv, 3, move), false, std.UnauthorizedError{})
+
+	// spend limit exceeded
+	checkInvalidTx(t, anteHandler, ctx, newTx(sessionPriv, 2, move, move), false, std.InsufficientFundsError{})
+
+	// expired session
+	header = header.Copy()
+	header.Time = now.Add(time.Hour)
+	checkInvalidTx(t, anteHandler, ctx.WithBlockHeader(header), newTx(sessionPriv, 2, move), false, std.UnauthorizedError{})
+}
+
+func TestAnteHandlerReplacementSequences(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the committed account
+	acc1 := env.acck.NewAccountWithAddress(env.ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(env.ctx, acc1)
+
+	// the check state, whose committed state is the one of the deliver context
+	ms := env.ctx.MultiStore()
+	ctx := env.ctx.
+		WithMode(sdk.RunTxModeCheck).
+		WithMultiStore(ms.MultiCacheWrap()).
+		WithValue(GasPriceContextKey{}, std.GasPrice{}).
+		WithValue(sdk.CommittedMultiStoreContextKey{}, ms.MultiCacheWrap)
+
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	privs, accnums := []crypto.PrivKey{priv1}, []uint64{0}
+	fee := tu.NewTestFee()
+
+	// two pending txs
+	for seq := uint64(0); seq < 2; seq++ {
+		tx := tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{seq}, fee)
+		_, res, abort := anteHandler(ctx, tx, false)
+		require.False(t, abort, res.Log)
+		assert.Equal(t, seq, res.Sequence)
+		assert.Equal(t, addr1.String(), res.Sender)
+		assert.Equal(t, TxPriority(fee), res.Priority)
+		assert.False(t, res.Replaces)
+	}
+
+	// a tx replacing the first pending one, with a higher fee
+	higherFee := std.NewFee(50000, std.NewCoin("atom", 300))
+	tx := tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, higherFee)
+	_, res, abort := anteHandler(ctx, tx, false)
+	require.False(t, abort, res.Log)
+	assert.Equal(t, uint64(0), res.Sequence)
+	assert.Equal(t, TxPriority(higherFee), res.Priority)
+	assert.True(t, res.Replaces)
+
+	// each pending sequence tried consumes the gas of a signature verification
+	replacementGas := func(seq uint64) int64 {
+		tx := tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{seq}, higherFee)
+		newCtx, res, abort := anteHandler(ctx.WithMultiStore(ctx.MultiStore().MultiCacheWrap()), tx, false)
+		require.False(t, abort, res.Log)
+		return newCtx.GasMeter().GasConsumed()
+	}
+	assert.Equal(t, DefaultSigVerifyCostSecp256k1, replacementGas(0)-replacementGas(1))
+
+	// the sequence of the check state is not incremented
+	assert.Equal(t, uint64(2), env.acck.GetAccount(ctx, addr1).GetSequence())
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{2}, fee)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	// the future sequences are not accepted
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{5}, fee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+
+	// the replacements are only accepted on CheckTx, with the committed state
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{1}, higherFee)
+	checkInvalidTx(t, anteHandler, ctx.WithValue(sdk.CommittedMultiStoreContextKey{}, nil), tx, false, std.UnauthorizedError{})
+	checkInvalidTx(t, anteHandler, ctx.WithMode(sdk.RunTxModeDeliver), tx, false, std.UnauthorizedError{})
+}
+
+func TestAnteHandlerReplacementSequencesFIFO(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// set the committed account
+	acc1 := env.acck.NewAccountWithAddress(env.ctx, addr1)
+	acc1.SetCoins(tu.NewTestCoins())
+	env.acck.SetAccount(env.ctx, acc1)
+
+	// the check state of a FIFO mempool, which doesn't replace the pending
+	// txs, so the committed state isn't given
+	ctx := env.ctx.
+		WithMode(sdk.RunTxModeCheck).
+		WithMultiStore(env.ctx.MultiStore().MultiCacheWrap()).
+		WithValue(GasPriceContextKey{}, std.GasPrice{})
+
+	msgs := []std.Msg{tu.NewTestMsg(addr1)}
+	privs, accnums := []crypto.PrivKey{priv1}, []uint64{0}
+
+	tx := tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, tu.NewTestFee())
+	checkValidTx(t, anteHandler, ctx, tx, false)
+
+	// a tx reusing the sequence of the pending one, with a higher fee
+	higherFee := std.NewFee(50000, std.NewCoin("atom", 300))
+	tx = tu.NewTestTx(t, ctx.ChainID(), msgs, privs, accnums, []uint64{0}, higherFee)
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.UnauthorizedError{})
+	assert.Equal(t, uint64(1), env.acck.GetAccount(ctx, addr1).GetSequence())
+}
+
+func TestTxPriority(t *testing.T) {
+	t.Parallel()
+
+	testCases := []struct {
+		name     string
+		fee      std.Fee
+		expected int64
+	}{
+		{"no gas wanted", std.NewFee(0, std.NewCoin("ugnot", 10)), 0},
+		{"no gas fee", std.NewFee(100, std.Coin{}), 0},
+		{"gas price", std.NewFee(100, std.NewCoin("ugnot", 10)), 100_000},
+		{"low gas price", std.NewFee(3_000_000, std.NewCoin("ugnot", 1)), 0},
+		{"overflow", std.NewFee(1, std.NewCoin("ugnot", math.MaxInt64)), math.MaxInt64},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.Equal(t, tc.expected, TxPriority(tc.fee))
+		})
+	}
+}
+
 // Test logic around fee deduction.
 func TestAnteHandlerFees(t *testing.T) {
 	t.Parallel()
@@ -352,6 +670,34 @@ func TestAnteHandlerFees(t *testing.T) {
 	require.Equal(t, env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"), int64(0))
 }
 
+func TestAnteHandlerFeesVesting(t *testing.T) {
+	t.Parallel()
+
+	// setup
+	env := setupTestEnv()
+	anteHandler := NewAnteHandler(env.acck, env.bankk, DefaultSigVerificationGasConsumer, defaultAnteOptions())
+	header := env.ctx.BlockHeader().(*bft.Header).Copy()
+	header.Time = time.Unix(1500, 0)
+	ctx := env.ctx.WithBlockHeader(header)
+
+	// keys and addresses
+	priv1, _, addr1 := tu.KeyTestPubAddr()
+
+	// the coins of the account are vesting, half of them vested
+	base := std.NewBaseAccountWithAddress(addr1)
+	base.Coins = std.NewCoins(std.NewCoin("atom", 200))
+	env.acck.SetAccount(ctx, std.NewContinuousVestingAccount(base, base.Coins, 1000, 2000))
+
+	// the locked coins cannot pay the fee
+	tx := tu.NewTestTx(t, ctx.ChainID(), []std.Msg{tu.NewTestMsg(addr1)}, []crypto.PrivKey{priv1}, []uint64{0}, []uint64{0}, tu.NewTestFee())
+	checkInvalidTx(t, anteHandler, ctx, tx, false, std.InsufficientFundsError{})
+
+	header.Time = time.Unix(1750, 0)
+	ctx = env.ctx.WithBlockHeader(header)
+	checkValidTx(t, anteHandler, ctx, tx, false)
+	require.Equal(t, int64(50), env.acck.GetAccount(ctx, addr1).GetCoins().AmountOf("atom"))
+}
+
 // Test logic around memo gas consumption.
 func TestAnteHandlerMemoGas(t *testing.T) {
 	t.Parallel()
@@ -16,6 +16,10 @@ const (
 
 	// AddressStoreKeyPrefix prefix for account-by-address store
 	AddressStoreKeyPrefix = "/a/"
+	// FeeAllowanceStoreKeyPrefix prefix for fee-allowance-by-granter-and-grantee store
+	FeeAllowanceStoreKeyPrefix = "/fa/"
+	// SessionStoreKeyPrefix prefix for session-by-account-and-key store
+	SessionStoreKeyPrefix = "/s/"
 	// key for gas price
 	GasPriceKey = "gasPrice"
 	// param key for global account number
@@ -26,3 +30,22 @@ const (
 func AddressStoreKey(addr crypto.Address) []byte {
 	return append([]byte(AddressStoreKeyPrefix), addr.Bytes()...)
 }
+
+// FeeAllowanceStoreKey turns a granter and a grantee to the key used to get
+// the fee allowance from the account store
+func FeeAllowanceStoreKey(granter, grantee crypto.Address) []byte {
+	key := append([]byte(FeeAllowanceStoreKeyPrefix), granter.Bytes()...)
+	return append(key, grantee.Bytes()...)
+}
+
+// SessionStoreKey turns an account and the address of a session key to the key
+// used to get the session from the account store
+func SessionStoreKey(addr, key crypto.Address) []byte {
+	return append(SessionsStoreKeyPrefix(addr), key.Bytes()...)
+}
+
+// SessionsStoreKeyPrefix turns an account to the prefix of the keys of its
+// sessions in the account store
+func SessionsStoreKeyPrefix(addr crypto.Address) []byte {
+	return append([]byte(SessionStoreKeyPrefix), addr.Bytes()...)
+}
@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// FeeAllowance is the allowance of a grantee to have the fees of its txs paid
+// by a granter, which is set as the std.Fee granter of the txs.
+type FeeAllowance struct {
+	// SpendLimit is the remaining amount of fees the granter pays. If empty,
+	// the fees are not limited.
+	SpendLimit std.Coins `json:"spend_limit" yaml:"spend_limit"`
+	// Expiration is the time from which the allowance is not valid anymore.
+	// If zero, the allowance never expires.
+	Expiration time.Time `json:"expiration" yaml:"expiration"`
+	// AllowedMsgs are the types of the messages of the txs, formatted as
+	// "<route>/<type>" (eg. "vm/exec"). If empty, all the messages are allowed.
+	AllowedMsgs []string `json:"allowed_msgs" yaml:"allowed_msgs"`
+	// AllowedPkgPaths are the paths of the packages (eg. the realms) targeted
+	// by the messages of the txs, see PkgPathMsg. If empty, all the packages
+	// are allowed.
+	AllowedPkgPaths []string `json:"allowed_pkg_paths" yaml:"allowed_pkg_paths"`
+}
+
+// PkgPathMsg is implemented by the messages which target a package, such as
+// the calls of a realm, to be restricted by FeeAllowance.AllowedPkgPaths.
+type PkgPathMsg interface {
+	std.Msg
+	GetPkgPath() string
+}
+
+// ValidateBasic validates the allowance.
+func (a FeeAllowance) ValidateBasic() error {
+	if !a.SpendLimit.IsValid() {
+		return std.ErrInvalidCoins(a.SpendLimit.String())
+	}
+	if slices.Contains(a.AllowedMsgs, "") {
+		return std.ErrUnknownRequest("empty allowed msg type")
+	}
+	if slices.Contains(a.AllowedPkgPaths, "") {
+		return std.ErrUnknownRequest("empty allowed pkg path")
+	}
+	return nil
+}
+
+// accept checks that the allowance is valid for the fees and the messages of
+// a tx, and returns the allowance with its remaining spend limit.
+func (a FeeAllowance) accept(blockTime time.Time, fees std.Coins, msgs []std.Msg) (FeeAllowance, error) {
+	if !a.Expiration.IsZero() && !blockTime.Before(a.Expiration) {
+		return a, std.ErrUnauthorized(fmt.Sprintf("fee allowance expired at %s", a.Expiration))
+	}
+
+	for _, msg := range msgs {
+		if len(a.AllowedMsgs) > 0 {
+			msgType := msg.Route() + "/" + msg.Type()
+			if !slices.Contains(a.AllowedMsgs, msgType) {
+				return a, std.ErrUnauthorized(fmt.Sprintf("fee allowance does not allow %s messages", msgType))
+			}
+		}
+		if len(a.AllowedPkgPaths) > 0 {
+			pmsg, ok := msg.(PkgPathMsg)
+			if !ok || !slices.Contains(a.AllowedPkgPaths, pmsg.GetPkgPath()) {
+				return a, std.ErrUnauthorized("fee allowance does not allow the package of the message")
+			}
+		}
+	}
+
+	if !a.SpendLimit.IsZero() {
+		if !a.SpendLimit.IsAllGTE(fees) {
+			return a, std.ErrInsufficientFunds(
+				fmt.Sprintf("insufficient fee allowance to pay for fees; %s < %s", a.SpendLimit, fees),
+			)
+		}
+		a.SpendLimit = a.SpendLimit.Sub(fees)
+	}
+
+	return a, nil
+}
+
+// GetFeeAllowance returns the fee allowance granted by a granter to a grantee.
+func (ak AccountKeeper) GetFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address) (FeeAllowance, bool) {
+	stor := ctx.GasStore(ak.key)
+	bz := stor.Get(FeeAllowanceStoreKey(granter, grantee))
+	if bz == nil {
+		return FeeAllowance{}, false
+	}
+
+	var allowance FeeAllowance
+	amino.MustUnmarshal(bz, &allowance)
+	return allowance, true
+}
+
+// SetFeeAllowance sets the fee allowance granted by a granter to a grantee.
+func (ak AccountKeeper) SetFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address, allowance FeeAllowance) {
+	stor := ctx.GasStore(ak.key)
+	stor.Set(FeeAllowanceStoreKey(granter, grantee), amino.MustMarshal(allowance))
+}
+
+// RemoveFeeAllowance removes the fee allowance granted by a granter to a
+// grantee.
+func (ak AccountKeeper) RemoveFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address) {
+	stor := ctx.GasStore(ak.key)
+	stor.Delete(FeeAllowanceStoreKey(granter, grantee))
+}
+
+// UseFeeAllowance deducts the fees of a tx of a grantee from its allowance.
+// The allowance is removed once its spend limit is exhausted.
+func (ak AccountKeeper) UseFeeAllowance(ctx sdk.Context, granter, grantee crypto.Address, fees std.Coins, msgs []std.Msg) error {
+	allowance, ok := ak.GetFeeAllowance(ctx, granter, grantee)
+	if !ok {
+		return std.ErrUnauthorized(fmt.Sprintf("%s has no fee allowance from %s", grantee, granter))
+	}
+
+	limited := !allowance.SpendLimit.IsZero()
+	allowance, err := allowance.accept(ctx.BlockTime(), fees, msgs)
+	if err != nil {
+		return err
+	}
+
+	if limited && allowance.SpendLimit.IsZero() {
+		ak.RemoveFeeAllowance(ctx, granter, grantee)
+	} else {
+		ak.SetFeeAllowance(ctx, granter, grantee, allowance)
+	}
+	return nil
+}
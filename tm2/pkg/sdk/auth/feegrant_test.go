@@ -0,0 +1,138 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestFeeAllowance_Accept(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	fees := std.NewCoins(std.NewCoin("atom", 10))
+	msg := tu.NewTestMsg()
+
+	testCases := []struct {
+		name      string
+		allowance FeeAllowance
+		msgs      []std.Msg
+		remaining std.Coins
+		err       error
+	}{
+		{"no limit", FeeAllowance{}, []std.Msg{msg}, nil, nil},
+		{
+			"spend limit",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("atom", 15))},
+			[]std.Msg{msg},
+			std.NewCoins(std.NewCoin("atom", 5)),
+			nil,
+		},
+		{
+			"spend limit exceeded",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("atom", 5))},
+			[]std.Msg{msg},
+			nil,
+			std.InsufficientFundsError{},
+		},
+		{
+			"spend limit of another denom",
+			FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("ugnot", 15))},
+			[]std.Msg{msg},
+			nil,
+			std.InsufficientFundsError{},
+		},
+		{"not expired", FeeAllowance{Expiration: now.Add(time.Second)}, []std.Msg{msg}, nil, nil},
+		{"expired", FeeAllowance{Expiration: now}, []std.Msg{msg}, nil, std.UnauthorizedError{}},
+		{
+			"allowed msg",
+			FeeAllowance{AllowedMsgs: []string{"bank/send", "TestMsg/Test message"}},
+			[]std.Msg{msg},
+			nil,
+			nil,
+		},
+		{
+			"not allowed msg",
+			FeeAllowance{AllowedMsgs: []string{"bank/send"}},
+			[]std.Msg{msg},
+			nil,
+			std.UnauthorizedError{},
+		},
+		{
+			"allowed pkg path",
+			FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/foo"}},
+			[]std.Msg{tu.NewTestFuncMsg(crypto.Address{}, "gno.land/r/demo/foo", "Foo", nil)},
+			nil,
+			nil,
+		},
+		{
+			"not allowed pkg path",
+			FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/foo"}},
+			[]std.Msg{tu.NewTestFuncMsg(crypto.Address{}, "gno.land/r/demo/foo", "Foo", nil), tu.NewTestFuncMsg(crypto.Address{}, "gno.land/r/demo/bar", "Bar", nil)},
+			nil,
+			std.UnauthorizedError{},
+		},
+		{
+			"msg without pkg path",
+			FeeAllowance{AllowedPkgPaths: []string{"gno.land/r/demo/foo"}},
+			[]std.Msg{msg},
+			nil,
+			std.UnauthorizedError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			allowance, err := tc.allowance.accept(now, fees, tc.msgs)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.remaining, allowance.SpendLimit)
+		})
+	}
+}
+
+func TestAccountKeeper_UseFeeAllowance(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx
+	granter := crypto.AddressFromPreimage([]byte("granter"))
+	grantee := crypto.AddressFromPreimage([]byte("grantee"))
+	fees := std.NewCoins(std.NewCoin("atom", 10))
+	msgs := []std.Msg{tu.NewTestMsg(grantee)}
+
+	err := env.acck.UseFeeAllowance(ctx, granter, grantee, fees, msgs)
+	assert.ErrorIs(t, err, std.UnauthorizedError{})
+
+	env.acck.SetFeeAllowance(ctx, granter, grantee, FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("atom", 20))})
+	require.NoError(t, env.acck.UseFeeAllowance(ctx, granter, grantee, fees, msgs))
+	allowance, ok := env.acck.GetFeeAllowance(ctx, granter, grantee)
+	require.True(t, ok)
+	assert.Equal(t, fees, allowance.SpendLimit)
+
+	// The allowance is removed once its spend limit is exhausted
+	require.NoError(t, env.acck.UseFeeAllowance(ctx, granter, grantee, fees, msgs))
+	_, ok = env.acck.GetFeeAllowance(ctx, granter, grantee)
+	assert.False(t, ok)
+
+	// The allowances without spend limit are kept
+	env.acck.SetFeeAllowance(ctx, granter, grantee, FeeAllowance{})
+	require.NoError(t, env.acck.UseFeeAllowance(ctx, granter, grantee, fees, msgs))
+	_, ok = env.acck.GetFeeAllowance(ctx, granter, grantee)
+	assert.True(t, ok)
+
+	// The allowances are per granter and grantee
+	_, ok = env.acck.GetFeeAllowance(ctx, grantee, granter)
+	assert.False(t, ok)
+}
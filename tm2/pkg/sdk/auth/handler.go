@@ -25,9 +25,68 @@ func NewHandler(acck AccountKeeper, gpKpr GasPriceKeeper) authHandler {
 }
 
 func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
-	// no messages supported yet.
-	errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
-	return abciResult(std.ErrUnknownRequest(errMsg))
+	switch msg := msg.(type) {
+	case MsgGrantAllowance:
+		return ah.handleMsgGrantAllowance(ctx, msg)
+
+	case MsgRevokeAllowance:
+		return ah.handleMsgRevokeAllowance(ctx, msg)
+
+	case MsgCreateSession:
+		return ah.handleMsgCreateSession(ctx, msg)
+
+	case MsgRevokeSession:
+		return ah.handleMsgRevokeSession(ctx, msg)
+
+	default:
+		errMsg := fmt.Sprintf("unrecognized auth message type: %T", msg)
+		return abciResult(std.ErrUnknownRequest(errMsg))
+	}
+}
+
+// Handle MsgGrantAllowance.
+func (ah authHandler) handleMsgGrantAllowance(ctx sdk.Context, msg MsgGrantAllowance) sdk.Result {
+	// The grantee may not own any coins yet, create its account so that it
+	// can sign its txs.
+	if ah.acck.GetAccount(ctx, msg.Grantee) == nil {
+		ah.acck.SetAccount(ctx, ah.acck.NewAccountWithAddress(ctx, msg.Grantee))
+	}
+
+	ah.acck.SetFeeAllowance(ctx, msg.Granter, msg.Grantee, msg.Allowance)
+	return sdk.Result{}
+}
+
+// Handle MsgRevokeAllowance.
+func (ah authHandler) handleMsgRevokeAllowance(ctx sdk.Context, msg MsgRevokeAllowance) sdk.Result {
+	if _, ok := ah.acck.GetFeeAllowance(ctx, msg.Granter, msg.Grantee); !ok {
+		return abciResult(std.ErrUnauthorized(
+			fmt.Sprintf("%s has no fee allowance from %s", msg.Grantee, msg.Granter)))
+	}
+
+	ah.acck.RemoveFeeAllowance(ctx, msg.Granter, msg.Grantee)
+	return sdk.Result{}
+}
+
+// Handle MsgCreateSession.
+func (ah authHandler) handleMsgCreateSession(ctx sdk.Context, msg MsgCreateSession) sdk.Result {
+	if !ctx.BlockTime().Before(msg.Session.Expiration) {
+		return abciResult(std.ErrUnknownRequest(
+			fmt.Sprintf("session expiration %s is not after the block time", msg.Session.Expiration)))
+	}
+
+	ah.acck.SetSession(ctx, msg.Address, msg.Session)
+	return sdk.Result{}
+}
+
+// Handle MsgRevokeSession.
+func (ah authHandler) handleMsgRevokeSession(ctx sdk.Context, msg MsgRevokeSession) sdk.Result {
+	if _, ok := ah.acck.GetSession(ctx, msg.Address, msg.SessionKey); !ok {
+		return abciResult(std.ErrUnauthorized(
+			fmt.Sprintf("%s has no session with key %s", msg.Address, msg.SessionKey)))
+	}
+
+	ah.acck.RemoveSession(ctx, msg.Address, msg.SessionKey)
+	return sdk.Result{}
 }
 
 //----------------------------------------
@@ -35,8 +94,10 @@ func (ah authHandler) Process(ctx sdk.Context, msg std.Msg) sdk.Result {
 
 // query path
 const (
-	QueryAccount  = "accounts"
-	QueryGasPrice = "gasprice"
+	QueryAccount      = "accounts"
+	QueryGasPrice     = "gasprice"
+	QueryFeeAllowance = "allowances"
+	QuerySessions     = "sessions"
 )
 
 func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
@@ -45,6 +106,10 @@ func (ah authHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.Re
 		return ah.queryAccount(ctx, req)
 	case QueryGasPrice:
 		return ah.queryGasPrice(ctx, req)
+	case QueryFeeAllowance:
+		return ah.queryFeeAllowance(ctx, req)
+	case QuerySessions:
+		return ah.querySessions(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown auth query endpoint"))
@@ -95,6 +160,64 @@ func (ah authHandler) queryGasPrice(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// queryFeeAllowance fetch the fee allowance of a grantee from a granter.
+// The granter and grantee addresses are passed as path components.
+func (ah authHandler) queryFeeAllowance(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addrs from path.
+	var addrs [2]crypto.Address
+	for i, b32addr := range []string{thirdPart(req.Path), fourthPart(req.Path)} {
+		addr, err := crypto.AddressFromBech32(b32addr)
+		if err != nil {
+			res = sdk.ABCIResponseQueryFromError(
+				std.ErrInvalidAddress(
+					"invalid query address " + b32addr))
+			return
+		}
+		addrs[i] = addr
+	}
+
+	// get allowance from addrs.
+	var allowance *FeeAllowance
+	if a, ok := ah.acck.GetFeeAllowance(ctx, addrs[0], addrs[1]); ok {
+		allowance = &a
+	}
+	bz, err := amino.MarshalJSONIndent(allowance, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
+// querySessions fetch the sessions of an account.
+// The account address is passed as the third path component.
+func (ah authHandler) querySessions(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addr from path.
+	b32addr := thirdPart(req.Path)
+	addr, err := crypto.AddressFromBech32(b32addr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress(
+				"invalid query address " + b32addr))
+		return
+	}
+
+	// get sessions from addr.
+	sessions := ah.acck.GetSessions(ctx, addr)
+	bz, err := amino.MarshalJSONIndent(sessions, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
 //----------------------------------------
 // misc
 
@@ -117,3 +240,13 @@ func thirdPart(path string) string {
 		return parts[2]
 	}
 }
+
+// returns the fourth component of a path.
+func fourthPart(path string) string {
+	parts := strings.Split(path, "/")
+	if len(parts) < 4 {
+		return ""
+	} else {
+		return parts[3]
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
@@ -101,3 +102,87 @@ func TestQuerierRouteNotFound(t *testing.T) {
 	res := h.Query(env.ctx, req)
 	require.Error(t, res.Error)
 }
+
+func TestHandleMsgGrantAllowance(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, env.gk)
+	_, _, granter := tu.KeyTestPubAddr()
+	_, _, grantee := tu.KeyTestPubAddr()
+
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("auth/%s/%s/%s", QueryFeeAllowance, granter, grantee),
+		Data: []byte{},
+	}
+	res := h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	require.Equal(t, "null", string(res.Data))
+
+	// the grantee account is created, so that it can sign
+	allowance := FeeAllowance{SpendLimit: std.NewCoins(std.NewCoin("foo", 10))}
+	result := h.Process(env.ctx, NewMsgGrantAllowance(granter, grantee, allowance))
+	require.True(t, result.IsOK(), result.Log)
+	require.NotNil(t, env.acck.GetAccount(env.ctx, grantee))
+
+	res = h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	bz, err := amino.MarshalJSONIndent(allowance, "", "  ")
+	require.NoError(t, err)
+	require.Equal(t, string(bz), string(res.Data))
+
+	result = h.Process(env.ctx, NewMsgRevokeAllowance(granter, grantee))
+	require.True(t, result.IsOK(), result.Log)
+	_, ok := env.acck.GetFeeAllowance(env.ctx, granter, grantee)
+	require.False(t, ok)
+
+	result = h.Process(env.ctx, NewMsgRevokeAllowance(granter, grantee))
+	require.False(t, result.IsOK())
+	require.True(t, strings.Contains(result.Log, "has no fee allowance"))
+}
+
+func TestHandleMsgCreateSession(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.acck, env.gk)
+	_, _, addr := tu.KeyTestPubAddr()
+	_, sessionPubKey, sessionAddr := tu.KeyTestPubAddr()
+
+	req := abci.RequestQuery{
+		Path: fmt.Sprintf("auth/%s/%s", QuerySessions, addr),
+		Data: []byte{},
+	}
+	res := h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	require.Equal(t, "[]", string(res.Data))
+
+	session := Session{
+		PubKey:       sessionPubKey,
+		Expiration:   time.Unix(1_000_000, 0).UTC(),
+		AllowedPaths: []string{"gno.land/r/demo/game.*"},
+	}
+	result := h.Process(env.ctx, NewMsgCreateSession(addr, session))
+	require.True(t, result.IsOK(), result.Log)
+
+	res = h.Query(env.ctx, req)
+	require.Nil(t, res.Error)
+	bz, err := amino.MarshalJSONIndent([]Session{session}, "", "  ")
+	require.NoError(t, err)
+	require.Equal(t, string(bz), string(res.Data))
+
+	// the expired sessions can't be created
+	header := env.ctx.BlockHeader().(*bft.Header).Copy()
+	header.Time = session.Expiration
+	result = h.Process(env.ctx.WithBlockHeader(header), NewMsgCreateSession(addr, session))
+	require.False(t, result.IsOK())
+
+	result = h.Process(env.ctx, NewMsgRevokeSession(addr, sessionAddr))
+	require.True(t, result.IsOK(), result.Log)
+	_, ok := env.acck.GetSession(env.ctx, addr, sessionAddr)
+	require.False(t, ok)
+
+	result = h.Process(env.ctx, NewMsgRevokeSession(addr, sessionAddr))
+	require.False(t, result.IsOK())
+	require.True(t, strings.Contains(result.Log, "has no session"))
+}
@@ -0,0 +1,174 @@
+package auth
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+// RouterKey is the name of the auth module
+const RouterKey = ModuleName
+
+// MsgGrantAllowance - grants a fee allowance to a grantee, replacing the
+// previous one if any
+type MsgGrantAllowance struct {
+	Granter   crypto.Address `json:"granter" yaml:"granter"`
+	Grantee   crypto.Address `json:"grantee" yaml:"grantee"`
+	Allowance FeeAllowance   `json:"allowance" yaml:"allowance"`
+}
+
+var _ std.Msg = MsgGrantAllowance{}
+
+// NewMsgGrantAllowance - construct a msg granting a fee allowance.
+func NewMsgGrantAllowance(granter, grantee crypto.Address, allowance FeeAllowance) MsgGrantAllowance {
+	return MsgGrantAllowance{Granter: granter, Grantee: grantee, Allowance: allowance}
+}
+
+// Route Implements Msg.
+func (msg MsgGrantAllowance) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgGrantAllowance) Type() string { return "grant_allowance" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgGrantAllowance) ValidateBasic() error {
+	if err := validateGranterGrantee(msg.Granter, msg.Grantee); err != nil {
+		return err
+	}
+	return msg.Allowance.ValidateBasic()
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgGrantAllowance) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgGrantAllowance) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
+
+// MsgRevokeAllowance - revokes the fee allowance of a grantee
+type MsgRevokeAllowance struct {
+	Granter crypto.Address `json:"granter" yaml:"granter"`
+	Grantee crypto.Address `json:"grantee" yaml:"grantee"`
+}
+
+var _ std.Msg = MsgRevokeAllowance{}
+
+// NewMsgRevokeAllowance - construct a msg revoking a fee allowance.
+func NewMsgRevokeAllowance(granter, grantee crypto.Address) MsgRevokeAllowance {
+	return MsgRevokeAllowance{Granter: granter, Grantee: grantee}
+}
+
+// Route Implements Msg.
+func (msg MsgRevokeAllowance) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRevokeAllowance) Type() string { return "revoke_allowance" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRevokeAllowance) ValidateBasic() error {
+	return validateGranterGrantee(msg.Granter, msg.Grantee)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRevokeAllowance) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRevokeAllowance) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Granter}
+}
+
+func validateGranterGrantee(granter, grantee crypto.Address) error {
+	if granter.IsZero() {
+		return std.ErrInvalidAddress("missing granter address")
+	}
+	if grantee.IsZero() {
+		return std.ErrInvalidAddress("missing grantee address")
+	}
+	if granter == grantee {
+		return std.ErrInvalidAddress("granter and grantee must differ")
+	}
+	return nil
+}
+
+// MsgCreateSession - registers a session key on an account, replacing the
+// session with the same key if any
+type MsgCreateSession struct {
+	Address crypto.Address `json:"address" yaml:"address"`
+	Session Session        `json:"session" yaml:"session"`
+}
+
+var _ std.Msg = MsgCreateSession{}
+
+// NewMsgCreateSession - construct a msg registering a session key.
+func NewMsgCreateSession(addr crypto.Address, session Session) MsgCreateSession {
+	return MsgCreateSession{Address: addr, Session: session}
+}
+
+// Route Implements Msg.
+func (msg MsgCreateSession) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgCreateSession) Type() string { return "create_session" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgCreateSession) ValidateBasic() error {
+	if msg.Address.IsZero() {
+		return std.ErrInvalidAddress("missing account address")
+	}
+	return msg.Session.ValidateBasic(msg.Address)
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgCreateSession) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgCreateSession) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Address}
+}
+
+// MsgRevokeSession - removes a session key from an account
+type MsgRevokeSession struct {
+	Address    crypto.Address `json:"address" yaml:"address"`
+	SessionKey crypto.Address `json:"session_key" yaml:"session_key"`
+}
+
+var _ std.Msg = MsgRevokeSession{}
+
+// NewMsgRevokeSession - construct a msg removing a session key.
+func NewMsgRevokeSession(addr, sessionKey crypto.Address) MsgRevokeSession {
+	return MsgRevokeSession{Address: addr, SessionKey: sessionKey}
+}
+
+// Route Implements Msg.
+func (msg MsgRevokeSession) Route() string { return RouterKey }
+
+// Type Implements Msg.
+func (msg MsgRevokeSession) Type() string { return "revoke_session" }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRevokeSession) ValidateBasic() error {
+	if msg.Address.IsZero() {
+		return std.ErrInvalidAddress("missing account address")
+	}
+	if msg.SessionKey.IsZero() {
+		return std.ErrInvalidAddress("missing session key address")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRevokeSession) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRevokeSession) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Address}
+}
@@ -0,0 +1,18 @@
+package auth
+
+import (
+	"github.com/gnolang/gno/tm2/pkg/amino"
+)
+
+var Package = amino.RegisterPackage(amino.NewPackage(
+	"github.com/gnolang/gno/tm2/pkg/sdk/auth",
+	"auth",
+	amino.GetCallersDirname(),
+).WithDependencies().WithTypes(
+	FeeAllowance{}, "FeeAllowance",
+	MsgGrantAllowance{}, "MsgGrantAllowance",
+	MsgRevokeAllowance{}, "MsgRevokeAllowance",
+	Session{}, "Session",
+	MsgCreateSession{}, "MsgCreateSession",
+	MsgRevokeSession{}, "MsgRevokeSession",
+))
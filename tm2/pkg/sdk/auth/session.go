@@ -0,0 +1,202 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+	"github.com/gnolang/gno/tm2/pkg/sdk"
+	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/store"
+)
+
+// Session is a key registered on an account, which signs the txs of the
+// account in place of its own key, within the limits of the session. It lets
+// an application, such as a dApp in a browser, hold a short-lived key which
+// can only call some functions of some realms.
+//
+// The txs signed by a session key use the account number and the sequence of
+// the account, and include the public key of the session in their signature.
+type Session struct {
+	// PubKey is the public key of the session key.
+	PubKey crypto.PubKey `json:"pub_key" yaml:"pub_key"`
+	// Expiration is the time from which the session is not valid anymore.
+	Expiration time.Time `json:"expiration" yaml:"expiration"`
+	// SpendLimit is the remaining amount of coins spent by the session, in
+	// fees, in coins sent by the messages (see SendMsg) and in their maximum
+	// storage deposits (see DepositMsg). If empty, the spending is not
+	// limited.
+	SpendLimit std.Coins `json:"spend_limit" yaml:"spend_limit"`
+	// AllowedPaths are the functions the session can call, formatted as
+	// "<pkgpath>.<func>", or "<pkgpath>.*" for all the functions of a package
+	// (eg. "gno.land/r/demo/game.*"). The session can only sign the messages
+	// calling one of them (see FuncMsg).
+	AllowedPaths []string `json:"allowed_paths" yaml:"allowed_paths"`
+}
+
+// FuncMsg is implemented by the messages which call a function of a package,
+// such as the calls of a realm, to be signed by a session key.
+type FuncMsg interface {
+	PkgPathMsg
+	GetFunc() string
+}
+
+// SendMsg is implemented by the messages which send coins of their caller,
+// to be deducted from the spend limit of a session.
+type SendMsg interface {
+	std.Msg
+	GetSend() std.Coins
+}
+
+// DepositMsg is implemented by the messages which lock a storage deposit from
+// the coins of their caller, up to a maximum, to be deducted from the spend
+// limit of a session. A session with a spend limit cannot sign them without a
+// maximum deposit.
+type DepositMsg interface {
+	std.Msg
+	GetMaxDeposit() std.Coins
+}
+
+// Address returns the address of the session key.
+func (s Session) Address() crypto.Address {
+	return s.PubKey.Address()
+}
+
+// ValidateBasic validates the session of an account.
+func (s Session) ValidateBasic(addr crypto.Address) error {
+	if s.PubKey == nil {
+		return std.ErrInvalidPubKey("missing session public key")
+	}
+	if s.Address() == addr {
+		return std.ErrInvalidPubKey("session key is the account key")
+	}
+	if s.Expiration.IsZero() {
+		return std.ErrUnknownRequest("missing session expiration")
+	}
+	if !s.SpendLimit.IsValid() {
+		return std.ErrInvalidCoins(s.SpendLimit.String())
+	}
+	if len(s.AllowedPaths) == 0 {
+		return std.ErrUnknownRequest("missing session allowed paths")
+	}
+	for _, path := range s.AllowedPaths {
+		i := strings.LastIndexByte(path, '.')
+		if i <= 0 || i == len(path)-1 || strings.Contains(path[i+1:], "/") {
+			return std.ErrUnknownRequest(fmt.Sprintf("invalid session allowed path %q", path))
+		}
+	}
+	return nil
+}
+
+// allows returns whether the session can sign a message.
+func (s Session) allows(msg std.Msg) bool {
+	fmsg, ok := msg.(FuncMsg)
+	if !ok {
+		return false
+	}
+
+	pkgPath := fmsg.GetPkgPath()
+	return slices.Contains(s.AllowedPaths, pkgPath+".*") ||
+		slices.Contains(s.AllowedPaths, pkgPath+"."+fmsg.GetFunc())
+}
+
+// accept checks that the session can sign the messages of a tx spending some
+// coins, and returns the session with its remaining spend limit.
+func (s Session) accept(blockTime time.Time, spent std.Coins, msgs []std.Msg) (Session, error) {
+	if !blockTime.Before(s.Expiration) {
+		return s, std.ErrUnauthorized(fmt.Sprintf("session expired at %s", s.Expiration))
+	}
+
+	for _, msg := range msgs {
+		if !s.allows(msg) {
+			return s, std.ErrUnauthorized(
+				fmt.Sprintf("session does not allow %s/%s messages", msg.Route(), msg.Type()),
+			)
+		}
+	}
+
+	if !s.SpendLimit.IsZero() {
+		for _, msg := range msgs {
+			if dmsg, ok := msg.(DepositMsg); ok && dmsg.GetMaxDeposit().IsZero() {
+				return s, std.ErrUnauthorized(
+					fmt.Sprintf("session with a spend limit requires a max deposit for %s/%s messages", msg.Route(), msg.Type()),
+				)
+			}
+		}
+		if !s.SpendLimit.IsAllGTE(spent) {
+			return s, std.ErrInsufficientFunds(
+				fmt.Sprintf("insufficient session spend limit; %s < %s", s.SpendLimit, spent),
+			)
+		}
+		s.SpendLimit = s.SpendLimit.Sub(spent)
+	}
+
+	return s, nil
+}
+
+// GetSession returns the session of an account with the given key address.
+func (ak AccountKeeper) GetSession(ctx sdk.Context, addr, key crypto.Address) (Session, bool) {
+	stor := ctx.GasStore(ak.key)
+	bz := stor.Get(SessionStoreKey(addr, key))
+	if bz == nil {
+		return Session{}, false
+	}
+
+	var session Session
+	amino.MustUnmarshal(bz, &session)
+	return session, true
+}
+
+// GetSessions returns the sessions of an account.
+func (ak AccountKeeper) GetSessions(ctx sdk.Context, addr crypto.Address) []Session {
+	sessions := []Session{}
+	stor := ctx.GasStore(ak.key)
+	iter := store.PrefixIterator(stor, SessionsStoreKeyPrefix(addr))
+	defer iter.Close()
+	for ; iter.Valid(); iter.Next() {
+		var session Session
+		amino.MustUnmarshal(iter.Value(), &session)
+		sessions = append(sessions, session)
+	}
+	return sessions
+}
+
+// SetSession sets a session of an account, replacing the one with the same
+// key if any.
+func (ak AccountKeeper) SetSession(ctx sdk.Context, addr crypto.Address, session Session) {
+	stor := ctx.GasStore(ak.key)
+	stor.Set(SessionStoreKey(addr, session.Address()), amino.MustMarshal(session))
+}
+
+// RemoveSession removes the session of an account with the given key
+// address.
+func (ak AccountKeeper) RemoveSession(ctx sdk.Context, addr, key crypto.Address) {
+	stor := ctx.GasStore(ak.key)
+	stor.Delete(SessionStoreKey(addr, key))
+}
+
+// UseSession deducts the coins spent by a tx signed by a session key from the
+// spend limit of the session. The session is removed once its spend limit is
+// exhausted.
+func (ak AccountKeeper) UseSession(ctx sdk.Context, addr, key crypto.Address, spent std.Coins, msgs []std.Msg) error {
+	session, ok := ak.GetSession(ctx, addr, key)
+	if !ok {
+		return std.ErrUnauthorized(fmt.Sprintf("%s has no session with key %s", addr, key))
+	}
+
+	limited := !session.SpendLimit.IsZero()
+	session, err := session.accept(ctx.BlockTime(), spent, msgs)
+	if err != nil {
+		return err
+	}
+
+	if limited && session.SpendLimit.IsZero() {
+		ak.RemoveSession(ctx, addr, key)
+	} else {
+		ak.SetSession(ctx, addr, session)
+	}
+	return nil
+}
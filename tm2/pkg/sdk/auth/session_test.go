@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
+	"github.com/gnolang/gno/tm2/pkg/std"
+)
+
+func TestSession_ValidateBasic(t *testing.T) {
+	t.Parallel()
+
+	_, pubKey, addr := tu.KeyTestPubAddr()
+	_, sessionPubKey, _ := tu.KeyTestPubAddr()
+	expiration := time.Now()
+
+	testCases := []struct {
+		name    string
+		session Session
+		valid   bool
+	}{
+		{"valid", Session{sessionPubKey, expiration, nil, []string{"gno.land/r/demo/game.*"}}, true},
+		{"function", Session{sessionPubKey, expiration, nil, []string{"gno.land/r/demo/game.Move"}}, true},
+		{"missing public key", Session{nil, expiration, nil, []string{"gno.land/r/demo/game.*"}}, false},
+		{"account key", Session{pubKey, expiration, nil, []string{"gno.land/r/demo/game.*"}}, false},
+		{"missing expiration", Session{sessionPubKey, time.Time{}, nil, []string{"gno.land/r/demo/game.*"}}, false},
+		{"missing allowed paths", Session{sessionPubKey, expiration, nil, nil}, false},
+		{"missing function", Session{sessionPubKey, expiration, nil, []string{"gno.land/r/demo/game"}}, false},
+		{"invalid spend limit", Session{sessionPubKey, expiration, std.Coins{{Denom: "atom", Amount: -1}}, []string{"gno.land/r/demo/game.*"}}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.session.ValidateBasic(addr)
+			if tc.valid {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestSession_Accept(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	_, sessionPubKey, addr := tu.KeyTestPubAddr()
+	session := Session{
+		PubKey:       sessionPubKey,
+		Expiration:   now.Add(time.Hour),
+		SpendLimit:   std.NewCoins(std.NewCoin("atom", 15)),
+		AllowedPaths: []string{"gno.land/r/demo/game.*", "gno.land/r/demo/foo.Bar"},
+	}
+	spent := std.NewCoins(std.NewCoin("atom", 10))
+
+	testCases := []struct {
+		name      string
+		blockTime time.Time
+		spent     std.Coins
+		msgs      []std.Msg
+		err       error
+	}{
+		{"all functions", now, spent, []std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", nil)}, nil},
+		{"function", now, spent, []std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/foo", "Bar", nil)}, nil},
+		{"expired", now.Add(time.Hour), spent, []std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", nil)}, std.UnauthorizedError{}},
+		{"not allowed function", now, spent, []std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/foo", "Baz", nil)}, std.UnauthorizedError{}},
+		{"not allowed package", now, spent, []std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game/sub", "Move", nil)}, std.UnauthorizedError{}},
+		{"not a function call", now, spent, []std.Msg{tu.NewTestMsg(addr)}, std.UnauthorizedError{}},
+		{
+			"max deposit",
+			now,
+			spent,
+			[]std.Msg{testDepositMsg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", nil), std.NewCoins(std.NewCoin("atom", 5))}},
+			nil,
+		},
+		{
+			"no max deposit",
+			now,
+			spent,
+			[]std.Msg{testDepositMsg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", nil), nil}},
+			std.UnauthorizedError{},
+		},
+		{
+			"spend limit exceeded",
+			now,
+			std.NewCoins(std.NewCoin("atom", 20)),
+			[]std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", nil)},
+			std.InsufficientFundsError{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			updated, err := session.accept(tc.blockTime, tc.spent, tc.msgs)
+			if tc.err != nil {
+				assert.ErrorIs(t, err, tc.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, std.NewCoins(std.NewCoin("atom", 5)), updated.SpendLimit)
+		})
+	}
+}
+
+func TestSessionSpent(t *testing.T) {
+	t.Parallel()
+
+	_, _, addr := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+	send := std.NewCoins(std.NewCoin("atom", 10))
+	deposit := std.NewCoins(std.NewCoin("atom", 20))
+	tx := std.Tx{
+		Msgs: []std.Msg{
+			tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", send),
+			testDepositMsg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", send), deposit},
+			testDepositMsg{tu.NewTestFuncMsg(addr2, "gno.land/r/demo/game", "Move", send), deposit},
+		},
+		Fee: tu.NewTestFee(),
+	}
+
+	// the fee, the sent coins and the max deposits of the messages of addr
+	assert.Equal(t, std.NewCoins(std.NewCoin("atom", 150+10+10+20)), sessionSpent(tx, addr, true))
+	assert.Equal(t, std.NewCoins(std.NewCoin("atom", 10+20)), sessionSpent(tx, addr2, false))
+}
+
+// testDepositMsg is a function call locking a storage deposit.
+type testDepositMsg struct {
+	*tu.TestFuncMsg
+	maxDeposit std.Coins
+}
+
+func (msg testDepositMsg) GetMaxDeposit() std.Coins { return msg.maxDeposit }
+
+func TestAccountKeeper_Sessions(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	ctx := env.ctx
+	_, _, addr := tu.KeyTestPubAddr()
+	_, pubKey1, key1 := tu.KeyTestPubAddr()
+	_, pubKey2, key2 := tu.KeyTestPubAddr()
+	msgs := []std.Msg{tu.NewTestFuncMsg(addr, "gno.land/r/demo/game", "Move", nil)}
+	spent := std.NewCoins(std.NewCoin("atom", 10))
+
+	session1 := Session{
+		PubKey:       pubKey1,
+		Expiration:   time.Now().Add(time.Hour),
+		SpendLimit:   std.NewCoins(std.NewCoin("atom", 20)),
+		AllowedPaths: []string{"gno.land/r/demo/game.*"},
+	}
+	session2 := session1
+	session2.PubKey = pubKey2
+	session2.SpendLimit = nil
+
+	assert.Empty(t, env.acck.GetSessions(ctx, addr))
+	err := env.acck.UseSession(ctx, addr, key1, spent, msgs)
+	assert.ErrorIs(t, err, std.UnauthorizedError{})
+
+	env.acck.SetSession(ctx, addr, session1)
+	env.acck.SetSession(ctx, addr, session2)
+	assert.Len(t, env.acck.GetSessions(ctx, addr), 2)
+	assert.Empty(t, env.acck.GetSessions(ctx, key1))
+
+	require.NoError(t, env.acck.UseSession(ctx, addr, key1, spent, msgs))
+	session, ok := env.acck.GetSession(ctx, addr, key1)
+	require.True(t, ok)
+	assert.Equal(t, spent, session.SpendLimit)
+
+	// The session is removed once its spend limit is exhausted
+	require.NoError(t, env.acck.UseSession(ctx, addr, key1, spent, msgs))
+	_, ok = env.acck.GetSession(ctx, addr, key1)
+	assert.False(t, ok)
+
+	// The sessions without spend limit are kept
+	require.NoError(t, env.acck.UseSession(ctx, addr, key2, spent, msgs))
+	_, ok = env.acck.GetSession(ctx, addr, key2)
+	assert.True(t, ok)
+
+	env.acck.RemoveSession(ctx, addr, key2)
+	assert.Empty(t, env.acck.GetSessions(ctx, addr))
+}
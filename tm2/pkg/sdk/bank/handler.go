@@ -98,13 +98,18 @@ func (bh bankHandler) handleMsgMultiSend(ctx sdk.Context, msg MsgMultiSend) sdk.
 //----------------------------------------
 // Query
 
-// query balance path
-const QueryBalance = "balances"
+// query endpoints supported by the bank Querier
+const (
+	QueryBalance = "balances"
+	QueryVesting = "vesting"
+)
 
 func (bh bankHandler) Query(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
 	switch secondPart(req.Path) {
 	case QueryBalance:
 		return bh.queryBalance(ctx, req)
+	case QueryVesting:
+		return bh.queryVesting(ctx, req)
 	default:
 		res = sdk.ABCIResponseQueryFromError(
 			std.ErrUnknownRequest("unknown bank query endpoint"))
@@ -135,6 +140,53 @@ func (bh bankHandler) queryBalance(ctx sdk.Context, req abci.RequestQuery) (res
 	return
 }
 
+// VestingBalance is the balance of an account at the time of the last block,
+// with its coins which vested and those which are still locked.
+// The coins of an account which does not vest are all spendable.
+type VestingBalance struct {
+	Coins           std.Coins `json:"coins"`
+	Spendable       std.Coins `json:"spendable"`
+	OriginalVesting std.Coins `json:"original_vesting"`
+	Vested          std.Coins `json:"vested"`
+	Locked          std.Coins `json:"locked"`
+}
+
+// queryVesting fetch the vested and locked coins of an account for the
+// supplied height. Account address is passed as path component.
+func (bh bankHandler) queryVesting(ctx sdk.Context, req abci.RequestQuery) (res abci.ResponseQuery) {
+	// parse addr from path.
+	b32addr := thirdPart(req.Path)
+	addr, err := crypto.AddressFromBech32(b32addr)
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInvalidAddress("invalid query address " + b32addr))
+		return
+	}
+
+	var bal VestingBalance
+	if acc := bh.bank.acck.GetAccount(ctx, addr); acc != nil {
+		bal.Coins = acc.GetCoins()
+		bal.Spendable = acc.GetCoins()
+		if vacc, ok := acc.(std.VestingAccount); ok {
+			blockTime := ctx.BlockTime()
+			bal.Spendable = vacc.SpendableCoins(blockTime)
+			bal.OriginalVesting = vacc.GetOriginalVesting()
+			bal.Vested = vacc.GetVestedCoins(blockTime)
+			bal.Locked = vacc.GetVestingCoins(blockTime)
+		}
+	}
+
+	bz, err := amino.MarshalJSONIndent(bal, "", "  ")
+	if err != nil {
+		res = sdk.ABCIResponseQueryFromError(
+			std.ErrInternal(fmt.Sprintf("could not marshal result to JSON: %s", err.Error())))
+		return
+	}
+
+	res.Data = bz
+	return
+}
+
 //----------------------------------------
 // misc
 
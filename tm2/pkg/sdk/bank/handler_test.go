@@ -4,12 +4,14 @@ import (
 	"fmt"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
 	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	tu "github.com/gnolang/gno/tm2/pkg/sdk/testutils"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -54,6 +56,50 @@ func TestBalances(t *testing.T) {
 	require.True(t, coins.AmountOf("foo") == 10)
 }
 
+func TestQueryVesting(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	h := NewHandler(env.bankk)
+	_, _, addr := tu.KeyTestPubAddr()
+	_, _, addr2 := tu.KeyTestPubAddr()
+
+	query := func(addr crypto.Address) VestingBalance {
+		t.Helper()
+
+		header := env.ctx.BlockHeader().(*bft.Header).Copy()
+		header.Time = time.Unix(1250, 0)
+		res := h.Query(env.ctx.WithBlockHeader(header), abci.RequestQuery{
+			Path: fmt.Sprintf("bank/%s/%s", QueryVesting, addr.String()),
+		})
+		require.Nil(t, res.Error)
+
+		var bal VestingBalance
+		require.NoError(t, amino.UnmarshalJSON(res.Data, &bal))
+		return bal
+	}
+
+	acc := env.acck.NewAccountWithAddress(env.ctx, addr)
+	acc.SetCoins(std.NewCoins(std.NewCoin("foo", 10)))
+	env.acck.SetAccount(env.ctx, acc)
+	bal := query(addr)
+	require.Equal(t, "10foo", bal.Spendable.String())
+	require.True(t, bal.Locked.IsZero())
+
+	base := std.NewBaseAccountWithAddress(addr2)
+	base.Coins = std.NewCoins(std.NewCoin("foo", 1000))
+	env.acck.SetAccount(env.ctx, std.NewContinuousVestingAccount(base, base.Coins, 1000, 2000))
+	bal = query(addr2)
+	require.Equal(t, "1000foo", bal.Coins.String())
+	require.Equal(t, "250foo", bal.Spendable.String())
+	require.Equal(t, "1000foo", bal.OriginalVesting.String())
+	require.Equal(t, "250foo", bal.Vested.String())
+	require.Equal(t, "750foo", bal.Locked.String())
+
+	res := h.Query(env.ctx, abci.RequestQuery{Path: "bank/vesting/invalid"})
+	require.Error(t, res.Error)
+}
+
 func TestQuerierRouteNotFound(t *testing.T) {
 	t.Parallel()
 
@@ -87,10 +87,11 @@ func (bank BankKeeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs
 	}
 
 	for _, in := range inputs {
-		if !bank.canSendCoins(ctx, in.Address, in.Coins) {
-			return std.RestrictedTransferError{}
+		acc := bank.acck.GetAccount(ctx, in.Address)
+		if err := bank.canSendCoins(ctx, acc, in.Coins); err != nil {
+			return err
 		}
-		_, err := bank.SubtractCoins(ctx, in.Address, in.Coins)
+		_, err := bank.subtractCoins(ctx, in.Address, acc, in.Coins)
 		if err != nil {
 			return err
 		}
@@ -124,47 +125,48 @@ func (bank BankKeeper) InputOutputCoins(ctx sdk.Context, inputs []Input, outputs
 	return nil
 }
 
-// canSendCoins returns true if the coins can be sent without violating any restriction.
-func (bank BankKeeper) canSendCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) bool {
+// canSendCoins returns an error if the coins cannot be sent from the account
+// (which may be nil) without violating any restriction, if they contain a
+// restricted denom.
+//
+// NOTE: the locked coins of a vesting account are checked by subtractCoins,
+// for any debit.
+func (bank BankKeeper) canSendCoins(ctx sdk.Context, acc std.Account, amt std.Coins) error {
 	rds := bank.RestrictedDenoms(ctx)
-	if len(rds) == 0 {
-		// No restrictions.
-		return true
-	}
-	if amt.ContainOneOfDenom(toSet(rds)) {
-		acc := bank.acck.GetAccount(ctx, addr)
+	if len(rds) > 0 && amt.ContainOneOfDenom(toSet(rds)) {
 		accr, ok := acc.(std.AccountUnrestricter)
-		if ok && accr.IsUnrestricted() {
-			return true
+		if !ok || !accr.IsUnrestricted() {
+			return std.RestrictedTransferError{}
 		}
-		return false
 	}
-	return true
+	return nil
 }
 
 // SendCoins moves coins from one account to another, restrction could be applied
 func (bank BankKeeper) SendCoins(ctx sdk.Context, fromAddr crypto.Address, toAddr crypto.Address, amt std.Coins) error {
 	// read restricted boolean value from param.IsRestrictedTransfer()
 	// canSendCoins is true until they have agreed to the waiver
-	if !bank.canSendCoins(ctx, fromAddr, amt) {
-		return std.RestrictedTransferError{}
+	acc := bank.acck.GetAccount(ctx, fromAddr)
+	if err := bank.canSendCoins(ctx, acc, amt); err != nil {
+		return err
 	}
 
-	return bank.sendCoins(ctx, fromAddr, toAddr, amt)
+	return bank.sendCoins(ctx, fromAddr, acc, toAddr, amt)
 }
 
 // SendCoinsUnrestricted is used for paying gas.
 func (bank BankKeeper) SendCoinsUnrestricted(ctx sdk.Context, fromAddr crypto.Address, toAddr crypto.Address, amt std.Coins) error {
-	return bank.sendCoins(ctx, fromAddr, toAddr, amt)
+	return bank.sendCoins(ctx, fromAddr, bank.acck.GetAccount(ctx, fromAddr), toAddr, amt)
 }
 
 func (bank BankKeeper) sendCoins(
 	ctx sdk.Context,
 	fromAddr crypto.Address,
+	fromAcc std.Account,
 	toAddr crypto.Address,
 	amt std.Coins,
 ) error {
-	_, err := bank.SubtractCoins(ctx, fromAddr, amt)
+	_, err := bank.subtractCoins(ctx, fromAddr, fromAcc, amt)
 	if err != nil {
 		return err
 	}
@@ -191,16 +193,33 @@ func (bank BankKeeper) sendCoins(
 	return nil
 }
 
-// SubtractCoins subtracts amt from the coins at the addr.
-//
-// CONTRACT: If the account is a vesting account, the amount has to be spendable.
+// SubtractCoins subtracts amt from the coins at the addr. If the account is a
+// vesting account, the amount has to be spendable, like for every debit of
+// the keeper (see subtractCoins).
 func (bank BankKeeper) SubtractCoins(ctx sdk.Context, addr crypto.Address, amt std.Coins) (std.Coins, error) {
+	return bank.subtractCoins(ctx, addr, bank.acck.GetAccount(ctx, addr), amt)
+}
+
+// subtractCoins subtracts amt from the coins of the account at the addr,
+// which was already read by the caller and may be nil. The locked coins of a
+// vesting account cannot be subtracted: as every debit goes through it, the
+// spendable coins are checked for the sends as well as for the fees and the
+// deposits.
+func (bank BankKeeper) subtractCoins(ctx sdk.Context, addr crypto.Address, acc std.Account, amt std.Coins) (std.Coins, error) {
 	if !amt.IsValid() {
 		return nil, std.ErrInvalidCoins(amt.String())
 	}
 
+	if vacc, ok := acc.(std.VestingAccount); ok {
+		spendable := vacc.SpendableCoins(ctx.BlockTime())
+		if !spendable.IsAllGTE(amt) {
+			return nil, std.ErrInsufficientCoins(
+				fmt.Sprintf("insufficient spendable funds; %s < %s", spendable, amt),
+			)
+		}
+	}
+
 	oldCoins := std.NewCoins()
-	acc := bank.acck.GetAccount(ctx, addr)
 	if acc != nil {
 		oldCoins = acc.GetCoins()
 	}
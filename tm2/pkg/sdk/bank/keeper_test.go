@@ -2,9 +2,11 @@ package bank
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 
+	bft "github.com/gnolang/gno/tm2/pkg/bft/types"
 	"github.com/gnolang/gno/tm2/pkg/crypto"
 	"github.com/gnolang/gno/tm2/pkg/sdk"
 	"github.com/gnolang/gno/tm2/pkg/std"
@@ -139,6 +141,55 @@ func TestBankKeeper(t *testing.T) {
 	require.Error(t, err)
 }
 
+func TestBankKeeper_Vesting(t *testing.T) {
+	t.Parallel()
+
+	env := setupTestEnv()
+	bankk := env.bankk
+
+	addr := crypto.AddressFromPreimage([]byte("addr1"))
+	addr2 := crypto.AddressFromPreimage([]byte("addr2"))
+	addr3 := crypto.AddressFromPreimage([]byte("addr3"))
+	base := std.NewBaseAccountWithAddress(addr)
+	base.Coins = std.NewCoins(std.NewCoin("barcoin", 10), std.NewCoin("foocoin", 100))
+	env.acck.SetAccount(env.ctx, std.NewContinuousVestingAccount(base, std.NewCoins(std.NewCoin("foocoin", 100)), 1000, 2000))
+
+	// Half of the vesting coins are spendable at the middle of the vesting.
+	header := env.ctx.BlockHeader().(*bft.Header).Copy()
+	header.Time = time.Unix(1500, 0)
+	ctx := env.ctx.WithBlockHeader(header)
+
+	err := bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("barcoin", 10), std.NewCoin("foocoin", 40)))
+	require.NoError(t, err)
+
+	err = bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 20)))
+	require.ErrorIs(t, err, std.InsufficientCoinsError{})
+	require.True(t, bankk.GetCoins(ctx, addr).IsEqual(std.NewCoins(std.NewCoin("foocoin", 60))))
+
+	err = bankk.InputOutputCoins(ctx,
+		[]Input{NewInput(addr, std.NewCoins(std.NewCoin("foocoin", 20)))},
+		[]Output{NewOutput(addr3, std.NewCoins(std.NewCoin("foocoin", 20)))},
+	)
+	require.ErrorIs(t, err, std.InsufficientCoinsError{})
+
+	// The received coins are spendable.
+	require.NoError(t, bankk.SendCoins(ctx, addr2, addr, std.NewCoins(std.NewCoin("foocoin", 20))))
+	require.NoError(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 30))))
+
+	// The other debits, eg. paying for gas or a storage deposit, are checked too.
+	err = bankk.SendCoinsUnrestricted(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 50)))
+	require.ErrorIs(t, err, std.InsufficientCoinsError{})
+	_, err = bankk.SubtractCoins(ctx, addr, std.NewCoins(std.NewCoin("foocoin", 50)))
+	require.ErrorIs(t, err, std.InsufficientCoinsError{})
+	require.True(t, bankk.GetCoins(ctx, addr).IsEqual(std.NewCoins(std.NewCoin("foocoin", 50))))
+
+	// All the coins are spendable after the vesting end.
+	header.Time = time.Unix(2000, 0)
+	ctx = env.ctx.WithBlockHeader(header)
+	require.NoError(t, bankk.SendCoins(ctx, addr2, addr, std.NewCoins(std.NewCoin("foocoin", 50))))
+	require.NoError(t, bankk.SendCoins(ctx, addr, addr2, std.NewCoins(std.NewCoin("foocoin", 50))))
+}
+
 func TestViewKeeper(t *testing.T) {
 	t.Parallel()
 
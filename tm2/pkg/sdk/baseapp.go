@@ -16,6 +16,11 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/std"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/traces"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Key to store the consensus params in the main store.
@@ -44,6 +49,7 @@ type BaseApp struct {
 
 	beginTxHook BeginTxHook // BaseApp-specific hook run before running transaction messages.
 	endTxHook   EndTxHook   // BaseApp-specific hook run after running transaction messages.
+	restoreHook RestoreHook // BaseApp-specific hook run after restoring a snapshot.
 
 	// --------------------
 	// Volatile state
@@ -73,6 +79,10 @@ type BaseApp struct {
 
 	// application's version string
 	appVersion string
+
+	// snapshots of the multistore, nil if disabled
+	snapshots      *snapshots.Manager
+	restoreAppHash []byte // trusted app hash of the snapshot being restored
 }
 
 var _ abci.Application = (*BaseApp)(nil)
@@ -579,15 +589,31 @@ func (app *BaseApp) CheckTx(req abci.RequestCheckTx) (res abci.ResponseCheckTx)
 		return
 	} else {
 		ctx := app.getContextForTx(RunTxModeCheck, req.Tx)
+		if req.AllowReplacement {
+			ctx = ctx.WithValue(CommittedMultiStoreContextKey{}, app.cms.MultiCacheWrap)
+		}
+		ctx, span := startTxSpan(ctx, "abci/CheckTx", req.Tx)
 
 		result := app.runTx(ctx, tx)
+		endTxSpan(span, result)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
+		res.Priority = result.Priority
+		res.Sender = result.Sender
+		res.Sequence = result.Sequence
+		res.Replaces = result.Replaces
 		return
 	}
 }
 
+// CommittedMultiStoreContextKey is the context key of a func returning a cache
+// wrap of the last committed state on CheckTx, whereas the context multistore
+// also has the changes of the txs already checked since then. It is only set if
+// the mempool replaces the pending txs (see abci.RequestCheckTx), for the
+// AnteHandler to accept the txs reusing their sequences.
+type CommittedMultiStoreContextKey struct{}
+
 // DeliverTx implements the ABCI interface.
 func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliverTx) {
 	var tx Tx
@@ -597,8 +623,10 @@ func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliv
 		return
 	} else {
 		ctx := app.getContextForTx(RunTxModeDeliver, req.Tx)
+		ctx, span := startTxSpan(ctx, "abci/DeliverTx", req.Tx)
 
 		result := app.runTx(ctx, tx)
+		endTxSpan(span, result)
 		res.ResponseBase = result.ResponseBase
 		res.GasWanted = result.GasWanted
 		res.GasUsed = result.GasUsed
@@ -606,6 +634,30 @@ func (app *BaseApp) DeliverTx(req abci.RequestDeliverTx) (res abci.ResponseDeliv
 	}
 }
 
+// startTxSpan starts the trace span of a tx, returning the context with the
+// span for the handlers to start their own spans
+func startTxSpan(ctx Context, name string, txBytes []byte) (Context, trace.Span) {
+	spanCtx, span := traces.Tracer().Start(ctx.Context(), name)
+	if span.IsRecording() {
+		span.SetAttributes(traces.TxHash(bft.Tx(txBytes).Hash()))
+	}
+
+	return ctx.WithContext(spanCtx), span
+}
+
+// endTxSpan ends the trace span of a tx with its result
+func endTxSpan(span trace.Span, result Result) {
+	span.SetAttributes(
+		attribute.Int64("tx.gas_wanted", result.GasWanted),
+		attribute.Int64("tx.gas_used", result.GasUsed),
+	)
+	if result.Error != nil {
+		span.SetStatus(codes.Error, result.Log)
+	}
+
+	span.End()
+}
+
 // validateBasicTxMsgs executes basic validator calls for messages.
 func validateBasicTxMsgs(msgs []Msg) error {
 	if len(msgs) == 0 {
@@ -735,6 +787,12 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 		// meter so we initialize upfront.
 		gasWanted int64
 
+		// Returned by the AnteHandler on CheckTx.
+		priority int64
+		sender   string
+		sequence uint64
+		replaces bool
+
 		ms   = ctx.MultiStore()
 		mode = ctx.Mode()
 	)
@@ -848,6 +906,7 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 			ctx = newCtx.WithMultiStore(ms)
 			msCache.MultiWrite()
 			gasWanted = result.GasWanted
+			priority, sender, sequence, replaces = result.Priority, result.Sender, result.Sequence, result.Replaces
 		}
 	}
 
@@ -861,6 +920,7 @@ func (app *BaseApp) runTx(ctx Context, tx Tx) (result Result) {
 
 	result = app.runMsgs(runMsgCtx, msgs, mode)
 	result.GasWanted = gasWanted
+	result.Priority, result.Sender, result.Sequence, result.Replaces = priority, sender, sequence, replaces
 
 	// Safety check: don't write the cache state unless we're in DeliverTx.
 	if mode != RunTxModeDeliver {
@@ -935,6 +995,12 @@ func (app *BaseApp) Commit() (res abci.ResponseCommit) {
 	headerBz := amino.MustMarshal(header)
 	baseStore.Set(mainLastHeaderKey, headerBz)
 
+	// Take a snapshot of this height in the background, if enabled. It
+	// does not delay the next commits.
+	if app.snapshots != nil {
+		app.snapshots.Commit(commitID.Version)
+	}
+
 	// Reset the Check state to the latest committed.
 	//
 	// NOTE: This is safe because Tendermint holds a lock on the mempool for
@@ -972,6 +1038,10 @@ func (app *BaseApp) halt() {
 }
 
 func (app *BaseApp) Close() error {
+	if app.snapshots != nil {
+		app.snapshots.Wait()
+	}
+
 	if app.db == nil {
 		return nil
 	}
@@ -22,6 +22,7 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/store"
 	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
 	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 var (
@@ -593,6 +594,39 @@ func TestCheckTx(t *testing.T) {
 	require.Nil(t, storedBytes)
 }
 
+func TestCheckTxAllowReplacement(t *testing.T) {
+	t.Parallel()
+
+	// This ante handler reports if the committed state is given, and the
+	// replacements are allowed.
+	anteOpt := func(bapp *BaseApp) {
+		bapp.SetAnteHandler(func(ctx Context, tx Tx, simulate bool) (newCtx Context, res Result, abort bool) {
+			committedStore, ok := ctx.Value(CommittedMultiStoreContextKey{}).(func() store.MultiStore)
+			if ok {
+				require.NotNil(t, committedStore())
+			}
+			return ctx, Result{Replaces: ok}, false
+		})
+	}
+	routerOpt := func(bapp *BaseApp) {
+		bapp.Router().AddRoute(routeMsgCounter, newTestHandler(func(ctx Context, msg Msg) Result { return Result{} }))
+	}
+
+	app := setupBaseApp(t, anteOpt, routerOpt)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	txBytes, err := amino.Marshal(newTxCounter(0, 0))
+	require.NoError(t, err)
+
+	r := app.CheckTx(abci.RequestCheckTx{Tx: txBytes})
+	require.True(t, r.IsOK(), r.Log)
+	assert.False(t, r.Replaces)
+
+	r = app.CheckTx(abci.RequestCheckTx{Tx: txBytes, AllowReplacement: true})
+	require.True(t, r.IsOK(), r.Log)
+	assert.True(t, r.Replaces)
+}
+
 // Test that successive DeliverTx can see each others' effects
 // on the store, both within and across blocks.
 func TestDeliverTx(t *testing.T) {
@@ -1247,3 +1281,131 @@ func TestGetMaximumBlockGas(t *testing.T) {
 	app.setConsensusParams(&abci.ConsensusParams{Block: &abci.BlockParams{MaxGas: -5000000}})
 	require.Panics(t, func() { app.getMaximumBlockGas() })
 }
+
+func TestSnapshots(t *testing.T) {
+	t.Parallel()
+
+	newSnapshotApp := func(t *testing.T, options ...func(*BaseApp)) *BaseApp {
+		t.Helper()
+
+		snapshotStore, err := snapshots.NewStore(t.TempDir())
+		require.NoError(t, err)
+
+		options = append([]func(*BaseApp){SetSnapshots(snapshotStore, snapshots.Options{Interval: 2})}, options...)
+		return setupBaseApp(t, options...)
+	}
+
+	app := newSnapshotApp(t)
+	app.InitChain(abci.RequestInitChain{ChainID: "test-chain"})
+
+	var commitID store.CommitID
+	for height := int64(1); height <= 3; height++ {
+		header := &bft.Header{ChainID: "test-chain", Height: height}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.deliverState.ms.GetStore(mainKey).Set([]byte("key"), []byte(fmt.Sprintf("main-%d", height)))
+		app.deliverState.ms.GetStore(baseKey).Set([]byte("key"), []byte(fmt.Sprintf("base-%d", height)))
+		app.EndBlock(abci.RequestEndBlock{})
+		res := app.Commit()
+
+		if height == 2 {
+			commitID = store.CommitID{Version: height, Hash: res.Data}
+		}
+	}
+	app.snapshots.Wait()
+
+	// A snapshot is taken at height 2
+	list := app.ListSnapshots(abci.RequestListSnapshots{})
+	require.True(t, list.IsOK())
+	require.Len(t, list.Snapshots, 1)
+	snapshot := list.Snapshots[0]
+	assert.Equal(t, int64(2), snapshot.Height)
+
+	// Snapshots cannot be restored once blocks are committed
+	offer := app.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: &snapshot, AppHash: commitID.Hash})
+	assert.True(t, offer.IsErr())
+
+	var chunks [][]byte
+	for i := range snapshot.Chunks {
+		res := app.LoadSnapshotChunk(abci.RequestLoadSnapshotChunk{
+			Height: snapshot.Height,
+			Format: snapshot.Format,
+			Chunk:  i,
+		})
+		require.True(t, res.IsOK())
+		chunks = append(chunks, res.Chunk)
+	}
+
+	applyChunks := func(t *testing.T, app *BaseApp) abci.ResponseApplySnapshotChunk {
+		t.Helper()
+
+		var res abci.ResponseApplySnapshotChunk
+		for i, chunk := range chunks {
+			res = app.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: uint32(i), Chunk: chunk, Sender: "peer"})
+			if res.IsErr() {
+				break
+			}
+		}
+
+		return res
+	}
+
+	t.Run("restore", func(t *testing.T) {
+		t.Parallel()
+
+		var restored store.MultiStore
+		restoreOpt := func(bapp *BaseApp) {
+			bapp.SetRestoreHook(func(ms store.MultiStore) { restored = ms })
+		}
+		app := newSnapshotApp(t, restoreOpt)
+
+		offer := app.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: &snapshot, AppHash: commitID.Hash})
+		require.True(t, offer.IsOK(), offer.Error)
+
+		// A corrupted chunk is fetched again, from another sender
+		corrupted := append([]byte{}, chunks[0]...)
+		corrupted[len(corrupted)-1]++
+		res := app.ApplySnapshotChunk(abci.RequestApplySnapshotChunk{Index: 0, Chunk: corrupted, Sender: "peer"})
+		require.True(t, res.IsErr())
+		assert.Equal(t, []uint32{0}, res.RefetchChunks)
+		assert.Equal(t, []string{"peer"}, res.RejectSenders)
+
+		res = applyChunks(t, app)
+		require.True(t, res.IsOK(), res.Error)
+
+		assert.Equal(t, commitID, app.LastCommitID())
+		assert.Equal(t, []byte("main-2"), app.cms.GetStore(mainKey).Get([]byte("key")))
+		assert.Equal(t, []byte("base-2"), app.cms.GetStore(baseKey).Get([]byte("key")))
+		assert.Equal(t, int64(2), app.checkState.ctx.BlockHeight())
+		assert.NotNil(t, restored)
+
+		// The next blocks can be committed on top of the restored state
+		header := &bft.Header{ChainID: "test-chain", Height: 3}
+		app.BeginBlock(abci.RequestBeginBlock{Header: header})
+		app.EndBlock(abci.RequestEndBlock{})
+		require.True(t, app.Commit().IsOK())
+		assert.Equal(t, int64(3), app.LastBlockHeight())
+	})
+
+	t.Run("untrusted app hash", func(t *testing.T) {
+		t.Parallel()
+
+		app := newSnapshotApp(t)
+
+		offer := app.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: &snapshot, AppHash: []byte("hash")})
+		require.True(t, offer.IsOK(), offer.Error)
+
+		res := applyChunks(t, app)
+		assert.True(t, res.IsErr())
+		assert.Empty(t, res.RefetchChunks)
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		t.Parallel()
+
+		app := setupBaseApp(t)
+
+		assert.Empty(t, app.ListSnapshots(abci.RequestListSnapshots{}).Snapshots)
+		offer := app.OfferSnapshot(abci.RequestOfferSnapshot{Snapshot: &snapshot, AppHash: commitID.Hash})
+		assert.True(t, offer.IsErr())
+	})
+}
@@ -14,6 +14,7 @@ import (
 var (
 	ErrInvalidMinGasPrices  = errors.New("invalid min gas prices")
 	ErrInvalidPruneStrategy = errors.New("invalid prune strategy")
+	ErrInvalidSnapshots     = errors.New("invalid snapshot options")
 )
 
 // AppConfig defines the configuration options for the Application
@@ -23,13 +24,21 @@ type AppConfig struct {
 
 	// The enforced state pruning stategy for the app
 	PruneStrategy types.PruneStrategy `json:"prune_strategy" toml:"prune_strategy" comment:"State pruning strategy [everything, nothing, syncable]"`
+
+	// The height interval between the state snapshots served to the state syncing nodes
+	SnapshotInterval int64 `json:"snapshot_interval" toml:"snapshot_interval" comment:"Height interval between state snapshots, 0 to disable them"`
+
+	// The number of recent snapshots to keep
+	SnapshotKeepRecent int `json:"snapshot_keep_recent" toml:"snapshot_keep_recent" comment:"Number of recent state snapshots to keep, 0 to keep all of them"`
 }
 
 // DefaultAppConfig returns a default configuration for the application
 func DefaultAppConfig() *AppConfig {
 	return &AppConfig{
-		MinGasPrices:  "",
-		PruneStrategy: types.PruneSyncableStrategy,
+		MinGasPrices:       "",
+		PruneStrategy:      types.PruneSyncableStrategy,
+		SnapshotInterval:   0,
+		SnapshotKeepRecent: 2,
 	}
 }
 
@@ -50,5 +59,13 @@ func (cfg *AppConfig) ValidateBasic() error {
 		return fmt.Errorf("%w: %q", ErrInvalidPruneStrategy, cfg.PruneStrategy)
 	}
 
+	// Make sure the snapshot options are not negative
+	if cfg.SnapshotInterval < 0 {
+		return fmt.Errorf("%w: negative interval %d", ErrInvalidSnapshots, cfg.SnapshotInterval)
+	}
+	if cfg.SnapshotKeepRecent < 0 {
+		return fmt.Errorf("%w: negative number of snapshots to keep %d", ErrInvalidSnapshots, cfg.SnapshotKeepRecent)
+	}
+
 	return nil
 }
@@ -65,6 +65,24 @@ func TestConfig_ValidateBasic(t *testing.T) {
 		assert.NoError(t, cfg.ValidateBasic())
 	})
 
+	t.Run("invalid snapshot interval", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.SnapshotInterval = -1
+
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshots)
+	})
+
+	t.Run("invalid snapshot keep recent", func(t *testing.T) {
+		t.Parallel()
+
+		cfg := DefaultAppConfig()
+		cfg.SnapshotKeepRecent = -1
+
+		assert.ErrorIs(t, cfg.ValidateBasic(), ErrInvalidSnapshots)
+	})
+
 	t.Run("valid default config", func(t *testing.T) {
 		t.Parallel()
 
@@ -5,6 +5,7 @@ import (
 
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
 	"github.com/gnolang/gno/tm2/pkg/store"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
 )
 
 // File for storing in-package BaseApp optional functions,
@@ -29,6 +30,19 @@ func SetMinGasPrices(gasPricesStr string) func(*BaseApp) {
 	return func(bap *BaseApp) { bap.setMinGasPrices(gasPrices) }
 }
 
+// SetSnapshots returns an option that periodically takes the snapshots of the
+// multistore into the given store, and enables serving them to the peers and
+// restoring the state from theirs.
+func SetSnapshots(snapshotStore *snapshots.Store, opts snapshots.Options) func(*BaseApp) {
+	return func(bap *BaseApp) {
+		snapshotter, ok := bap.cms.(store.Snapshotter)
+		if !ok {
+			panic(fmt.Sprintf("multistore of type %T does not support snapshots", bap.cms))
+		}
+		bap.snapshots = snapshots.NewManager(snapshotStore, snapshotter, opts, bap.logger)
+	}
+}
+
 func (app *BaseApp) SetName(name string) {
 	if app.sealed {
 		panic("SetName() on sealed BaseApp")
@@ -99,3 +113,10 @@ func (app *BaseApp) SetEndTxHook(endTx EndTxHook) {
 	}
 	app.endTxHook = endTx
 }
+
+func (app *BaseApp) SetRestoreHook(restore RestoreHook) {
+	if app.sealed {
+		panic("SetRestoreHook() on sealed BaseApp")
+	}
+	app.restoreHook = restore
+}
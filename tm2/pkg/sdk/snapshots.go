@@ -0,0 +1,144 @@
+package sdk
+
+import (
+	"bytes"
+	goerrors "errors"
+	"fmt"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/errors"
+	"github.com/gnolang/gno/tm2/pkg/store/snapshots"
+)
+
+var errSnapshotsDisabled = errors.New("snapshots are not enabled")
+
+// ListSnapshots implements the ABCI interface. It returns the snapshots
+// taken by the app, if enabled.
+func (app *BaseApp) ListSnapshots(req abci.RequestListSnapshots) (res abci.ResponseListSnapshots) {
+	if app.snapshots == nil {
+		return
+	}
+
+	list, err := app.snapshots.List()
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	res.Snapshots = list
+
+	return
+}
+
+// OfferSnapshot implements the ABCI interface. It starts restoring the
+// offered snapshot, which is only possible before any block is committed.
+// The restored state must match the given trusted app hash.
+func (app *BaseApp) OfferSnapshot(req abci.RequestOfferSnapshot) (res abci.ResponseOfferSnapshot) {
+	switch {
+	case app.snapshots == nil:
+		res.Error = ABCIError(errSnapshotsDisabled)
+		return
+
+	case app.LastBlockHeight() != 0:
+		res.Error = ABCIError(fmt.Errorf("cannot restore a snapshot after height %d is committed", app.LastBlockHeight()))
+		return
+
+	case req.Snapshot == nil:
+		res.Error = ABCIError(errors.New("no snapshot offered"))
+		return
+
+	case len(req.AppHash) == 0:
+		res.Error = ABCIError(errors.New("no trusted app hash for the offered snapshot"))
+		return
+	}
+
+	if err := app.snapshots.Restore(*req.Snapshot); err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	app.restoreAppHash = req.AppHash
+
+	app.logger.Info("restoring snapshot",
+		"height", req.Snapshot.Height,
+		"chunks", req.Snapshot.Chunks,
+		"hash", fmt.Sprintf("%X", req.Snapshot.Hash),
+	)
+
+	return
+}
+
+// LoadSnapshotChunk implements the ABCI interface. It returns a chunk of a
+// snapshot taken by the app, if enabled.
+func (app *BaseApp) LoadSnapshotChunk(req abci.RequestLoadSnapshotChunk) (res abci.ResponseLoadSnapshotChunk) {
+	if app.snapshots == nil {
+		res.Error = ABCIError(errSnapshotsDisabled)
+		return
+	}
+
+	chunk, err := app.snapshots.LoadChunk(req.Height, req.Format, req.Chunk)
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	res.Chunk = chunk
+
+	return
+}
+
+// ApplySnapshotChunk implements the ABCI interface. It applies the next
+// chunk of the offered snapshot, asking to fetch it again from another peer
+// if it does not match its hash. Once the last chunk is applied, the restored
+// state is verified against the trusted app hash, and the app is loaded from
+// it.
+//
+// NOTE: a snapshot failing after some of its chunks were applied can leave a
+// partially restored state, which must be reset before syncing again.
+func (app *BaseApp) ApplySnapshotChunk(req abci.RequestApplySnapshotChunk) (res abci.ResponseApplySnapshotChunk) {
+	if app.snapshots == nil {
+		res.Error = ABCIError(errSnapshotsDisabled)
+		return
+	}
+
+	done, err := app.snapshots.RestoreChunk(req.Index, req.Chunk)
+	if goerrors.Is(err, snapshots.ErrInvalidChunk) {
+		res.Error = ABCIError(err)
+		res.RefetchChunks = []uint32{req.Index}
+		if req.Sender != "" {
+			res.RejectSenders = []string{req.Sender}
+		}
+		return
+	}
+	if err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	if !done {
+		return
+	}
+
+	// The stores are authenticated by the app hash, the base store by the
+	// chunk hashes of the snapshot only
+	commitID := app.cms.LastCommitID()
+	if !bytes.Equal(commitID.Hash, app.restoreAppHash) {
+		res.Error = ABCIError(fmt.Errorf("restored app hash %X does not match the trusted app hash %X",
+			commitID.Hash, app.restoreAppHash))
+		return
+	}
+	app.restoreAppHash = nil
+
+	if err := app.initFromMainStore(); err != nil {
+		res.Error = ABCIError(err)
+		return
+	}
+	if app.restoreHook != nil {
+		ms := app.cms.MultiCacheWrap()
+		app.restoreHook(ms)
+		ms.MultiWrite()
+	}
+
+	app.logger.Info("restored snapshot",
+		"height", commitID.Version,
+		"hash", fmt.Sprintf("%X", commitID.Hash),
+	)
+
+	return
+}
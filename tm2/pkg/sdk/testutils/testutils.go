@@ -37,6 +37,39 @@ func (msg *TestMsg) GetSigners() []crypto.Address {
 	return msg.Signers
 }
 
+// TestFuncMsg is a test message calling a function of a package, sending
+// coins of its caller.
+type TestFuncMsg struct {
+	Caller  crypto.Address
+	PkgPath string
+	Func    string
+	Send    std.Coins
+}
+
+var _ std.Msg = &TestFuncMsg{}
+
+func NewTestFuncMsg(caller crypto.Address, pkgPath, fn string, send std.Coins) *TestFuncMsg {
+	return &TestFuncMsg{
+		Caller:  caller,
+		PkgPath: pkgPath,
+		Func:    fn,
+		Send:    send,
+	}
+}
+
+func (msg *TestFuncMsg) Route() string { return "TestFuncMsg" }
+func (msg *TestFuncMsg) Type() string  { return "call" }
+func (msg *TestFuncMsg) GetSignBytes() []byte {
+	return std.MustSortJSON(amino.MustMarshalJSON(msg))
+}
+func (msg *TestFuncMsg) ValidateBasic() error { return nil }
+func (msg *TestFuncMsg) GetSigners() []crypto.Address {
+	return []crypto.Address{msg.Caller}
+}
+func (msg *TestFuncMsg) GetPkgPath() string { return msg.PkgPath }
+func (msg *TestFuncMsg) GetFunc() string    { return msg.Func }
+func (msg *TestFuncMsg) GetSend() std.Coins { return msg.Send }
+
 // ----------------------------------------
 // Utility Methods
 
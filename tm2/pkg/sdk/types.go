@@ -25,6 +25,12 @@ type Result struct {
 	abci.ResponseBase
 	GasWanted int64
 	GasUsed   int64
+
+	// Set by the AnteHandler on CheckTx, see abci.ResponseCheckTx.
+	Priority int64
+	Sender   string
+	Sequence uint64
+	Replaces bool
 }
 
 // AnteHandler authenticates transactions, before their internal messages are handled.
@@ -11,7 +11,8 @@ import (
 // AccountNumber is a replay-prevention field for the whole account
 // (eg. nonce) to prevent the replay of txs after an account has been deleted
 // (due to zero balance). Sequence is a replay-prevention field for each transaction
-// given a nonce
+// given a nonce. TimeoutHeight is omitted when zero, so that the payload of
+// the txs which never expire is unchanged.
 type SignDoc struct {
 	ChainID       string `json:"chain_id" yaml:"chain_id"`
 	AccountNumber uint64 `json:"account_number" yaml:"account_number"`
@@ -19,6 +20,7 @@ type SignDoc struct {
 	Fee           Fee    `json:"fee" yaml:"fee"`
 	Msgs          []Msg  `json:"msgs" yaml:"msgs"`
 	Memo          string `json:"memo" yaml:"memo"`
+	TimeoutHeight uint64 `json:"timeout_height,omitempty" yaml:"timeout_height,omitempty"`
 }
 
 // GetSignaturePayload returns the sign payload for the SignDoc.
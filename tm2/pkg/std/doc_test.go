@@ -48,3 +48,22 @@ func TestSignDoc_GetSignaturePayload(t *testing.T) {
 		})
 	}
 }
+
+func TestSignDoc_TimeoutHeight(t *testing.T) {
+	t.Parallel()
+
+	doc := SignDoc{
+		ChainID:  "dummy",
+		Sequence: 20,
+	}
+
+	// The payload of the txs which never expire is unchanged
+	signPayload, err := GetSignaturePayload(doc)
+	require.NoError(t, err)
+	assert.NotContains(t, string(signPayload), "timeout_height")
+
+	doc.TimeoutHeight = 10
+	signPayload, err = GetSignaturePayload(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(signPayload), `"timeout_height":"10"`)
+}
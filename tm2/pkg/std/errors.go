@@ -32,6 +32,7 @@ type (
 	NoSignaturesError       struct{ abciError }
 	GasOverflowError        struct{ abciError }
 	RestrictedTransferError struct{ abciError }
+	TxTimeoutError          struct{ abciError }
 )
 
 func (e InternalError) Error() string           { return "internal error" }
@@ -53,6 +54,7 @@ func (e TooManySignaturesError) Error() string  { return "too many signatures er
 func (e NoSignaturesError) Error() string       { return "no signatures error" }
 func (e GasOverflowError) Error() string        { return "gas overflow error" }
 func (e RestrictedTransferError) Error() string { return "restricted token transfer error" }
+func (e TxTimeoutError) Error() string          { return "tx timeout error" }
 
 // NOTE also update pkg/std/package.go registrations.
 
@@ -127,3 +129,7 @@ func ErrNoSignatures(msg string) error {
 func ErrGasOverflow(msg string) error {
 	return errors.Wrap(GasOverflowError{}, msg)
 }
+
+func ErrTxTimeout(msg string) error {
+	return errors.Wrap(TxTimeoutError{}, msg)
+}
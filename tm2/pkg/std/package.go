@@ -12,6 +12,11 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 
 	// Account
 	&BaseAccount{}, "BaseAccount",
+	// Vesting accounts
+	&BaseVestingAccount{}, "BaseVestingAccount",
+	&ContinuousVestingAccount{}, "ContinuousVestingAccount",
+	&PeriodicVestingAccount{}, "PeriodicVestingAccount",
+	VestingPeriod{}, "VestingPeriod",
 	// Coin
 	&Coin{}, "Coin",
 	// GasPrice
@@ -41,4 +46,5 @@ var Package = amino.RegisterPackage(amino.NewPackage(
 	NoSignaturesError{}, "NoSignaturesError",
 	GasOverflowError{}, "GasOverflowError",
 	RestrictedTransferError{}, "RestrictedTransferError",
+	TxTimeoutError{}, "TxTimeoutError",
 ))
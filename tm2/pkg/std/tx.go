@@ -25,6 +25,10 @@ type Tx struct {
 	Fee        Fee         `json:"fee" yaml:"fee"`
 	Signatures []Signature `json:"signatures" yaml:"signatures"`
 	Memo       string      `json:"memo" yaml:"memo"`
+
+	// TimeoutHeight is the last block height the tx can be included in.
+	// Zero means the tx never expires.
+	TimeoutHeight uint64 `json:"timeout_height,omitempty" yaml:"timeout_height,omitempty"`
 }
 
 func NewTx(msgs []Msg, fee Fee, sigs []Signature, memo string) Tx {
@@ -50,6 +54,11 @@ func (tx Tx) ValidateBasic() error {
 	if !tx.Fee.GasFee.IsValid() {
 		return ErrInsufficientFee(fmt.Sprintf("invalid fee %s amount provided", tx.Fee.GasFee))
 	}
+	if tx.Fee.Granter != "" {
+		if _, err := crypto.AddressFromBech32(string(tx.Fee.Granter)); err != nil {
+			return ErrInvalidAddress(fmt.Sprintf("invalid fee granter %s", tx.Fee.Granter))
+		}
+	}
 	if len(stdSigs) == 0 {
 		return ErrNoSignatures("no signers")
 	}
@@ -115,6 +124,7 @@ func (tx Tx) GetSignBytes(chainID string, accountNumber uint64, sequence uint64)
 		Fee:           tx.Fee,
 		Msgs:          tx.Msgs,
 		Memo:          tx.Memo,
+		TimeoutHeight: tx.TimeoutHeight,
 	})
 }
 
@@ -126,6 +136,10 @@ func (tx Tx) GetSignBytes(chainID string, accountNumber uint64, sequence uint64)
 type Fee struct {
 	GasWanted int64 `json:"gas_wanted" yaml:"gas_wanted"`
 	GasFee    Coin  `json:"gas_fee" yaml:"gas_fee"`
+
+	// Granter is the account paying the fee in place of the first signer, if
+	// set. It must have granted it a fee allowance (see auth.FeeAllowance).
+	Granter crypto.Bech32Address `json:"granter,omitempty" yaml:"granter,omitempty"`
 }
 
 // NewFee returns a new instance of Fee
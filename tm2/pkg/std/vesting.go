@@ -0,0 +1,284 @@
+package std
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/gnolang/gno/tm2/pkg/errors"
+)
+
+// VestingAccount is an account whose original vesting coins are locked, and
+// vest over time following a schedule. Only the coins which are not locked can
+// be spent.
+type VestingAccount interface {
+	Account
+
+	// GetVestedCoins returns the original vesting coins which vested at the
+	// given time.
+	GetVestedCoins(blockTime time.Time) Coins
+	// GetVestingCoins returns the original vesting coins which did not vest
+	// yet at the given time, and are locked.
+	GetVestingCoins(blockTime time.Time) Coins
+	// SpendableCoins returns the coins of the account which are not locked at
+	// the given time.
+	SpendableCoins(blockTime time.Time) Coins
+
+	GetOriginalVesting() Coins
+	GetStartTime() int64 // UNIX time, in seconds.
+	GetEndTime() int64   // UNIX time, in seconds.
+}
+
+//----------------------------------------
+// BaseVestingAccount
+
+// BaseVestingAccount - the common fields of the vesting accounts, whose
+// original vesting coins vest from the start time to the end time.
+// It is embedded within the vesting accounts, which implement their schedule.
+type BaseVestingAccount struct {
+	BaseAccount
+	OriginalVesting Coins `json:"original_vesting" yaml:"original_vesting"`
+	StartTime       int64 `json:"start_time" yaml:"start_time"`
+	EndTime         int64 `json:"end_time" yaml:"end_time"`
+}
+
+// GetOriginalVesting - Implements VestingAccount.
+func (bva *BaseVestingAccount) GetOriginalVesting() Coins {
+	return bva.OriginalVesting
+}
+
+// GetStartTime - Implements VestingAccount.
+func (bva *BaseVestingAccount) GetStartTime() int64 {
+	return bva.StartTime
+}
+
+// GetEndTime - Implements VestingAccount.
+func (bva *BaseVestingAccount) GetEndTime() int64 {
+	return bva.EndTime
+}
+
+// Validate checks the original vesting coins and the vesting times.
+func (bva *BaseVestingAccount) Validate() error {
+	if !bva.OriginalVesting.IsValid() || bva.OriginalVesting.IsZero() {
+		return fmt.Errorf("invalid original vesting coins: %q", bva.OriginalVesting)
+	}
+	if bva.StartTime >= bva.EndTime {
+		return errors.New("vesting start time must be before its end time")
+	}
+	return nil
+}
+
+// spendableCoins returns the balance of the account minus the locked coins,
+// the denoms whose balance is not above the locked amount being omitted.
+func (bva *BaseVestingAccount) spendableCoins(locked Coins) Coins {
+	var spendable Coins
+	for _, coin := range bva.Coins {
+		if amount := coin.Amount - locked.AmountOf(coin.Denom); amount > 0 {
+			spendable = append(spendable, Coin{Denom: coin.Denom, Amount: amount})
+		}
+	}
+	return spendable
+}
+
+// String implements fmt.Stringer
+func (bva BaseVestingAccount) String() string {
+	return fmt.Sprintf(`%s
+  OriginalVesting: %s
+  StartTime:       %d
+  EndTime:         %d`,
+		bva.BaseAccount.String(), bva.OriginalVesting, bva.StartTime, bva.EndTime,
+	)
+}
+
+//----------------------------------------
+// ContinuousVestingAccount
+
+var _ VestingAccount = &ContinuousVestingAccount{}
+
+// ContinuousVestingAccount - a vesting account whose original vesting coins
+// vest linearly from the start time to the end time.
+type ContinuousVestingAccount struct {
+	BaseVestingAccount
+}
+
+// NewContinuousVestingAccount creates a new ContinuousVestingAccount object.
+// The times are UNIX times, in seconds.
+func NewContinuousVestingAccount(base BaseAccount, originalVesting Coins,
+	startTime int64, endTime int64,
+) *ContinuousVestingAccount {
+	return &ContinuousVestingAccount{
+		BaseVestingAccount: BaseVestingAccount{
+			BaseAccount:     base,
+			OriginalVesting: originalVesting,
+			StartTime:       startTime,
+			EndTime:         endTime,
+		},
+	}
+}
+
+// GetVestedCoins - Implements VestingAccount.
+func (cva *ContinuousVestingAccount) GetVestedCoins(blockTime time.Time) Coins {
+	now := blockTime.Unix()
+	switch {
+	case now <= cva.StartTime:
+		return nil
+	case now >= cva.EndTime:
+		return cva.OriginalVesting
+	}
+
+	// Multiply before dividing, in big ints not to overflow.
+	elapsed := big.NewInt(now - cva.StartTime)
+	duration := big.NewInt(cva.EndTime - cva.StartTime)
+
+	var vested Coins
+	for _, coin := range cva.OriginalVesting {
+		amount := new(big.Int).Mul(big.NewInt(coin.Amount), elapsed)
+		amount.Quo(amount, duration)
+		if amount.Sign() > 0 {
+			vested = append(vested, Coin{Denom: coin.Denom, Amount: amount.Int64()})
+		}
+	}
+	return vested
+}
+
+// GetVestingCoins - Implements VestingAccount.
+func (cva *ContinuousVestingAccount) GetVestingCoins(blockTime time.Time) Coins {
+	return cva.OriginalVesting.Sub(cva.GetVestedCoins(blockTime))
+}
+
+// SpendableCoins - Implements VestingAccount.
+func (cva *ContinuousVestingAccount) SpendableCoins(blockTime time.Time) Coins {
+	return cva.spendableCoins(cva.GetVestingCoins(blockTime))
+}
+
+//----------------------------------------
+// PeriodicVestingAccount
+
+var _ VestingAccount = &PeriodicVestingAccount{}
+
+// VestingPeriod is a period of a periodic vesting, at the end of which its
+// amount vests.
+type VestingPeriod struct {
+	Length int64 `json:"length" yaml:"length"` // in seconds.
+	Amount Coins `json:"amount" yaml:"amount"`
+}
+
+// String implements fmt.Stringer
+func (vp VestingPeriod) String() string {
+	return fmt.Sprintf("%ds:%s", vp.Length, vp.Amount)
+}
+
+// PeriodicVestingAccount - a vesting account whose original vesting coins
+// vest in steps, at the end of each of its vesting periods. The periods
+// follow each other from the start time.
+type PeriodicVestingAccount struct {
+	BaseVestingAccount
+	VestingPeriods []VestingPeriod `json:"vesting_periods" yaml:"vesting_periods"`
+}
+
+// NewPeriodicVestingAccount creates a new PeriodicVestingAccount object,
+// whose original vesting coins are the sum of the amounts of the periods.
+// The start time is a UNIX time, in seconds.
+func NewPeriodicVestingAccount(base BaseAccount, startTime int64, periods []VestingPeriod) *PeriodicVestingAccount {
+	var (
+		originalVesting Coins
+		endTime         = startTime
+	)
+	for _, period := range periods {
+		originalVesting = originalVesting.AddUnsafe(period.Amount)
+		endTime += period.Length
+	}
+
+	return &PeriodicVestingAccount{
+		BaseVestingAccount: BaseVestingAccount{
+			BaseAccount:     base,
+			OriginalVesting: originalVesting,
+			StartTime:       startTime,
+			EndTime:         endTime,
+		},
+		VestingPeriods: periods,
+	}
+}
+
+// Validate checks the vesting periods, which must add up to the original
+// vesting coins and end at the end time.
+func (pva *PeriodicVestingAccount) Validate() error {
+	if err := pva.BaseVestingAccount.Validate(); err != nil {
+		return err
+	}
+
+	var (
+		total   Coins
+		endTime = pva.StartTime
+	)
+	for i, period := range pva.VestingPeriods {
+		if period.Length <= 0 {
+			return fmt.Errorf("invalid length of vesting period #%d: %d", i, period.Length)
+		}
+		if !period.Amount.IsValid() || period.Amount.IsZero() {
+			return fmt.Errorf("invalid amount of vesting period #%d: %q", i, period.Amount)
+		}
+		total = total.AddUnsafe(period.Amount)
+		endTime += period.Length
+	}
+
+	if !total.IsAllGTE(pva.OriginalVesting) || !pva.OriginalVesting.IsAllGTE(total) {
+		return fmt.Errorf("vesting periods total %q, not the original vesting coins %q", total, pva.OriginalVesting)
+	}
+	if endTime != pva.EndTime {
+		return errors.New("vesting periods do not end at the vesting end time")
+	}
+	return nil
+}
+
+// GetVestedCoins - Implements VestingAccount.
+func (pva *PeriodicVestingAccount) GetVestedCoins(blockTime time.Time) Coins {
+	var (
+		now     = blockTime.Unix()
+		vested  Coins
+		endTime = pva.StartTime
+	)
+	for _, period := range pva.VestingPeriods {
+		endTime += period.Length
+		if now < endTime {
+			break
+		}
+		vested = vested.Add(period.Amount)
+	}
+	return vested
+}
+
+// GetVestingCoins - Implements VestingAccount.
+func (pva *PeriodicVestingAccount) GetVestingCoins(blockTime time.Time) Coins {
+	return pva.OriginalVesting.Sub(pva.GetVestedCoins(blockTime))
+}
+
+// SpendableCoins - Implements VestingAccount.
+func (pva *PeriodicVestingAccount) SpendableCoins(blockTime time.Time) Coins {
+	return pva.spendableCoins(pva.GetVestingCoins(blockTime))
+}
+
+// String implements fmt.Stringer
+func (pva PeriodicVestingAccount) String() string {
+	periods := make([]string, 0, len(pva.VestingPeriods))
+	for _, period := range pva.VestingPeriods {
+		periods = append(periods, period.String())
+	}
+
+	return fmt.Sprintf("%s\n  VestingPeriods:  %s",
+		pva.BaseVestingAccount.String(), strings.Join(periods, " "),
+	)
+}
+
+//----------------------------------------
+// Misc.
+
+// SpendableCoins returns the coins of the account which can be spent at the
+// given time: all its coins, but the locked ones of a vesting account.
+func SpendableCoins(acc Account, blockTime time.Time) Coins {
+	if vacc, ok := acc.(VestingAccount); ok {
+		return vacc.SpendableCoins(blockTime)
+	}
+	return acc.GetCoins()
+}
@@ -0,0 +1,165 @@
+package std
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto"
+)
+
+func TestContinuousVestingAccount(t *testing.T) {
+	t.Parallel()
+
+	base := BaseAccount{
+		Address: crypto.AddressFromPreimage([]byte("vesting")),
+		Coins:   NewCoins(NewCoin("atom", 100), NewCoin("gnot", 1000)),
+	}
+	acc := NewContinuousVestingAccount(base, NewCoins(NewCoin("gnot", 1000)), 1000, 2000)
+	require.NoError(t, acc.Validate())
+
+	tests := []struct {
+		name      string
+		time      int64
+		vested    Coins
+		spendable Coins
+	}{
+		{"before start", 500, nil, NewCoins(NewCoin("atom", 100))},
+		{"at start", 1000, nil, NewCoins(NewCoin("atom", 100))},
+		{"quarter", 1250, NewCoins(NewCoin("gnot", 250)), NewCoins(NewCoin("atom", 100), NewCoin("gnot", 250))},
+		{"rounded down", 1001, NewCoins(NewCoin("gnot", 1)), NewCoins(NewCoin("atom", 100), NewCoin("gnot", 1))},
+		{"at end", 2000, NewCoins(NewCoin("gnot", 1000)), base.Coins},
+		{"after end", 3000, NewCoins(NewCoin("gnot", 1000)), base.Coins},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockTime := time.Unix(tc.time, 0)
+			assert.Equal(t, tc.vested, acc.GetVestedCoins(blockTime))
+			assert.Equal(t, acc.OriginalVesting.Sub(tc.vested), acc.GetVestingCoins(blockTime))
+			assert.Equal(t, tc.spendable, acc.SpendableCoins(blockTime))
+		})
+	}
+}
+
+func TestPeriodicVestingAccount(t *testing.T) {
+	t.Parallel()
+
+	base := BaseAccount{
+		Address: crypto.AddressFromPreimage([]byte("vesting")),
+		Coins:   NewCoins(NewCoin("gnot", 1000)),
+	}
+	acc := NewPeriodicVestingAccount(base, 1000, []VestingPeriod{
+		{Length: 100, Amount: NewCoins(NewCoin("gnot", 500))},
+		{Length: 200, Amount: NewCoins(NewCoin("gnot", 300))},
+		{Length: 300, Amount: NewCoins(NewCoin("gnot", 200))},
+	})
+	require.NoError(t, acc.Validate())
+	assert.Equal(t, NewCoins(NewCoin("gnot", 1000)), acc.GetOriginalVesting())
+	assert.Equal(t, int64(1600), acc.GetEndTime())
+
+	tests := []struct {
+		name   string
+		time   int64
+		vested Coins
+	}{
+		{"before start", 500, nil},
+		{"first period", 1099, nil},
+		{"first period end", 1100, NewCoins(NewCoin("gnot", 500))},
+		{"second period end", 1300, NewCoins(NewCoin("gnot", 800))},
+		{"after end", 2000, NewCoins(NewCoin("gnot", 1000))},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			blockTime := time.Unix(tc.time, 0)
+			assert.Equal(t, tc.vested, acc.GetVestedCoins(blockTime))
+			assert.Equal(t, tc.vested, acc.SpendableCoins(blockTime))
+		})
+	}
+
+	t.Run("spent coins", func(t *testing.T) {
+		t.Parallel()
+
+		// The locked coins are deducted from the remaining coins.
+		acc := *acc
+		acc.Coins = NewCoins(NewCoin("gnot", 600))
+		assert.Equal(t, NewCoins(NewCoin("gnot", 100)), acc.SpendableCoins(time.Unix(1100, 0)))
+		assert.Nil(t, acc.SpendableCoins(time.Unix(1000, 0)))
+	})
+}
+
+func TestVestingAccount_Validate(t *testing.T) {
+	t.Parallel()
+
+	coins := NewCoins(NewCoin("gnot", 100))
+	period := VestingPeriod{Length: 100, Amount: coins}
+
+	tests := []struct {
+		name string
+		acc  interface{ Validate() error }
+		err  string
+	}{
+		{"valid continuous", NewContinuousVestingAccount(BaseAccount{}, coins, 0, 100), ""},
+		{"no vesting coins", NewContinuousVestingAccount(BaseAccount{}, nil, 0, 100), "invalid original vesting coins"},
+		{"end before start", NewContinuousVestingAccount(BaseAccount{}, coins, 100, 100), "start time must be before"},
+		{"valid periodic", NewPeriodicVestingAccount(BaseAccount{}, 0, []VestingPeriod{period, period}), ""},
+		{"no periods", NewPeriodicVestingAccount(BaseAccount{}, 0, nil), "invalid original vesting coins"},
+		{"negative length", NewPeriodicVestingAccount(BaseAccount{}, 0, []VestingPeriod{period, {Length: -50, Amount: coins}}), "invalid length"},
+		{"empty amount", NewPeriodicVestingAccount(BaseAccount{}, 0, []VestingPeriod{period, {Length: 50}}), "invalid amount"},
+		{
+			"total mismatch",
+			&PeriodicVestingAccount{
+				BaseVestingAccount: BaseVestingAccount{OriginalVesting: coins.Add(coins), StartTime: 0, EndTime: 100},
+				VestingPeriods:     []VestingPeriod{period},
+			},
+			"not the original vesting coins",
+		},
+		{
+			"end mismatch",
+			&PeriodicVestingAccount{
+				BaseVestingAccount: BaseVestingAccount{OriginalVesting: coins, StartTime: 0, EndTime: 200},
+				VestingPeriods:     []VestingPeriod{period},
+			},
+			"do not end at the vesting end time",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := tc.acc.Validate()
+			if tc.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorContains(t, err, tc.err)
+			}
+		})
+	}
+}
+
+func TestVestingAccount_Amino(t *testing.T) {
+	t.Parallel()
+
+	base := *NewBaseAccount(crypto.AddressFromPreimage([]byte("vesting")), NewCoins(NewCoin("gnot", 100)), nil, 1, 2)
+	for _, acc := range []Account{
+		NewContinuousVestingAccount(base, base.Coins, 1000, 2000),
+		NewPeriodicVestingAccount(base, 1000, []VestingPeriod{{Length: 100, Amount: base.Coins}}),
+	} {
+		bz, err := amino.Marshal(&acc)
+		require.NoError(t, err)
+
+		var decoded Account
+		require.NoError(t, amino.Unmarshal(bz, &decoded))
+		assert.Equal(t, acc, decoded)
+		assert.Equal(t, base.Coins, SpendableCoins(decoded, time.Unix(3000, 0)))
+	}
+}
@@ -1,7 +1,14 @@
 package dbadapter
 
 import (
+	"context"
+	"time"
+
 	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/gnolang/gno/tm2/pkg/store/cache"
 	"github.com/gnolang/gno/tm2/pkg/store/types"
@@ -24,6 +31,42 @@ func (dsa Store) CacheWrap() types.Store {
 	return cache.New(dsa)
 }
 
+// Implements Store.
+func (dsa Store) Get(key []byte) []byte {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreReadTime, "get", time.Now())
+	}
+
+	return dsa.DB.Get(key)
+}
+
+// Implements Store.
+func (dsa Store) Has(key []byte) bool {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreReadTime, "has", time.Now())
+	}
+
+	return dsa.DB.Has(key)
+}
+
+// Implements Store.
+func (dsa Store) Set(key, value []byte) {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreWriteTime, "set", time.Now())
+	}
+
+	dsa.DB.Set(key, value)
+}
+
+// Implements Store.
+func (dsa Store) Delete(key []byte) {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreWriteTime, "delete", time.Now())
+	}
+
+	dsa.DB.Delete(key)
+}
+
 // Implements Store.
 func (dsa Store) Write() {
 	// CacheWrap().Write() gets called, but not dsa.Write().
@@ -69,3 +112,15 @@ func (dsa Store) LoadVersion(ver int64) error {
 
 // dbm.DB implements Store.
 var _ types.Store = Store{}
+
+// logTelemetry logs the latency of a store operation started at the given time
+func logTelemetry(hist metric.Int64Histogram, op string, start time.Time) {
+	hist.Record(
+		context.Background(),
+		time.Since(start).Microseconds(),
+		metric.WithAttributes(
+			attribute.String("store", "db"),
+			attribute.String("op", op),
+		),
+	)
+}
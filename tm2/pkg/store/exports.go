@@ -24,6 +24,9 @@ type (
 	GasConfig              = types.GasConfig
 	OutOfGasError          = types.OutOfGasError
 	GasOverflowError       = types.GasOverflowError
+	Snapshotter            = types.Snapshotter
+	SnapshotExporter       = types.SnapshotExporter
+	SnapshotItem           = types.SnapshotItem
 )
 
 var (
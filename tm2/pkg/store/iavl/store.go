@@ -1,9 +1,11 @@
 package iavl
 
 import (
+	"context"
 	goerrors "errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/amino"
 	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
@@ -12,6 +14,10 @@ import (
 	"github.com/gnolang/gno/tm2/pkg/errors"
 	"github.com/gnolang/gno/tm2/pkg/iavl"
 	"github.com/gnolang/gno/tm2/pkg/std"
+	"github.com/gnolang/gno/tm2/pkg/telemetry"
+	"github.com/gnolang/gno/tm2/pkg/telemetry/metrics"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
 
 	"github.com/gnolang/gno/tm2/pkg/store/cache"
 	serrors "github.com/gnolang/gno/tm2/pkg/store/errors"
@@ -41,6 +47,10 @@ var (
 type Store struct {
 	tree Tree
 	opts types.StoreOptions
+
+	mtx      sync.Mutex
+	exported map[int64]int // number of open exports of each version
+	pruned   []int64       // pruned versions, deleted once not exported
 }
 
 func UnsafeNewStore(tree *iavl.MutableTree, opts types.StoreOptions) *Store {
@@ -89,10 +99,7 @@ func (st *Store) Commit() types.CommitID {
 	if st.opts.KeepRecent < previous {
 		toRelease := previous - st.opts.KeepRecent
 		if st.opts.KeepEvery == 0 || toRelease%st.opts.KeepEvery != 0 {
-			err := st.tree.DeleteVersion(toRelease)
-			if errCause := errors.Cause(err); errCause != nil && !goerrors.Is(errCause, iavl.ErrVersionDoesNotExist) {
-				panic(err)
-			}
+			st.pruneVersion(toRelease)
 		}
 	}
 
@@ -152,6 +159,88 @@ func (st *Store) VersionExists(version int64) bool {
 	return st.tree.VersionExists(version)
 }
 
+// pruneVersion deletes the given version, or defers it until its exports
+// are closed. The versions deferred before are deleted if they are not
+// exported anymore.
+func (st *Store) pruneVersion(version int64) {
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	pruned := append(st.pruned, version)
+	st.pruned = nil
+	for _, v := range pruned {
+		if st.exported[v] > 0 {
+			st.pruned = append(st.pruned, v)
+			continue
+		}
+
+		err := st.tree.DeleteVersion(v)
+		if errCause := errors.Cause(err); errCause != nil && !goerrors.Is(errCause, iavl.ErrVersionDoesNotExist) {
+			panic(err)
+		}
+	}
+}
+
+// Export returns an export of the tree at the given version. The version is
+// not pruned until the export is closed, so it can be exported while the
+// next versions are committed.
+func (st *Store) Export(version int64) (*Export, error) {
+	iTree, err := st.tree.GetImmutable(version)
+	if err != nil {
+		return nil, err
+	}
+
+	st.mtx.Lock()
+	defer st.mtx.Unlock()
+
+	if st.exported == nil {
+		st.exported = make(map[int64]int)
+	}
+	st.exported[version]++
+
+	return &Export{store: st, tree: iTree, version: version}, nil
+}
+
+// Export is an export of a version of an IAVL store.
+type Export struct {
+	store   *Store
+	tree    *iavl.ImmutableTree
+	version int64
+	once    sync.Once
+}
+
+// Nodes calls fn for each node of the exported tree, in the order expected
+// by Import.
+func (e *Export) Nodes(fn func(iavl.ExportNode) error) error {
+	return e.tree.Export(fn)
+}
+
+// Close releases the exported version, which can then be pruned.
+func (e *Export) Close() {
+	e.once.Do(func() {
+		st := e.store
+		st.mtx.Lock()
+		defer st.mtx.Unlock()
+
+		st.exported[e.version]--
+		if st.exported[e.version] == 0 {
+			delete(st.exported, e.version)
+		}
+	})
+}
+
+// Import returns an importer of the tree at the given version, which must
+// be committed to save and load the imported version. The store must be
+// empty.
+func (st *Store) Import(version int64) (*iavl.Importer, error) {
+	tree, ok := st.tree.(*iavl.MutableTree)
+	if !ok {
+		return nil, errors.New("cannot import into an immutable store")
+	}
+
+	return tree.Import(version)
+}
+
 // Implements Store.
 func (st *Store) CacheWrap() types.Store {
 	return cache.New(st)
@@ -164,26 +253,54 @@ func (st *Store) Write() {
 
 // Implements types.Store.
 func (st *Store) Set(key, value []byte) {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreWriteTime, "set", time.Now())
+	}
+
 	types.AssertValidValue(value)
 	st.tree.Set(key, value)
 }
 
 // Implements types.Store.
 func (st *Store) Get(key []byte) (value []byte) {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreReadTime, "get", time.Now())
+	}
+
 	_, v := st.tree.Get(key)
 	return v
 }
 
 // Implements types.Store.
 func (st *Store) Has(key []byte) (exists bool) {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreReadTime, "has", time.Now())
+	}
+
 	return st.tree.Has(key)
 }
 
 // Implements types.Store.
 func (st *Store) Delete(key []byte) {
+	if telemetry.MetricsEnabled() {
+		defer logTelemetry(metrics.StoreWriteTime, "delete", time.Now())
+	}
+
 	st.tree.Remove(key)
 }
 
+// logTelemetry logs the latency of a store operation started at the given time
+func logTelemetry(hist metric.Int64Histogram, op string, start time.Time) {
+	hist.Record(
+		context.Background(),
+		time.Since(start).Microseconds(),
+		metric.WithAttributes(
+			attribute.String("store", "iavl"),
+			attribute.String("op", op),
+		),
+	)
+}
+
 // Implements types.Store.
 func (st *Store) Iterator(start, end []byte) types.Iterator {
 	var iTree *iavl.ImmutableTree
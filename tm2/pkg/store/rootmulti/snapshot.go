@@ -0,0 +1,364 @@
+package rootmulti
+
+import (
+	goerrors "errors"
+	"fmt"
+	"io"
+	"sort"
+
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	tiavl "github.com/gnolang/gno/tm2/pkg/iavl"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// restoreBatchSize is the number of restored key-value pairs written in a
+// single batch.
+const restoreBatchSize = 10000
+
+var _ types.Snapshotter = (*multiStore)(nil)
+
+// Implements Snapshotter.
+// The stores are snapshotted in the order of their names: the IAVL stores
+// as the nodes of their tree, and the DB adapter stores as their key-value
+// pairs. The key-value pairs of the IAVL trees sharing the DB of a DB
+// adapter store are not part of its items.
+//
+// The DB adapter stores have no versions: they are read through iterators
+// opened by Snapshot, which see the DB as of their creation.
+func (ms *multiStore) Snapshot(version int64) (types.SnapshotExporter, error) {
+	if version <= 0 || version != ms.lastCommitID.Version {
+		return nil, fmt.Errorf("cannot snapshot version %d, only the last committed version %d",
+			version, ms.lastCommitID.Version)
+	}
+
+	e := &snapshotExporter{}
+	for _, key := range ms.sortedKeys() {
+		se := storeExport{name: key.Name()}
+
+		switch store := ms.stores[key].(type) {
+		case *iavl.Store:
+			export, err := store.Export(version)
+			if err != nil {
+				e.Close()
+				return nil, err
+			}
+			se.tree = export
+
+		case dbadapter.Store:
+			se.kvs = store.Iterator(nil, nil)
+			se.skipTreeKeys = ms.sharesTreeDB(key)
+
+		default:
+			e.Close()
+			return nil, fmt.Errorf("store %s of type %T cannot be snapshotted", key.Name(), store)
+		}
+		e.stores = append(e.stores, se)
+	}
+
+	return e, nil
+}
+
+// snapshotExporter exports the stores of a multistore, as of the creation
+// of their exports.
+type snapshotExporter struct {
+	stores []storeExport
+}
+
+// storeExport is the export of a store: either the export of its IAVL tree,
+// or an iterator over its key-value pairs.
+type storeExport struct {
+	name         string
+	tree         *iavl.Export
+	kvs          types.Iterator
+	skipTreeKeys bool
+}
+
+// Implements SnapshotExporter.
+func (e *snapshotExporter) Export(fn func(types.SnapshotItem) error) error {
+	for _, se := range e.stores {
+		err := fn(types.SnapshotItem{
+			Store: &types.SnapshotStoreItem{Name: se.name},
+		})
+		if err != nil {
+			return err
+		}
+
+		if se.tree != nil {
+			err = se.tree.Nodes(func(node tiavl.ExportNode) error {
+				return fn(types.SnapshotItem{
+					IAVL: &types.SnapshotIAVLItem{
+						Key:     node.Key,
+						Value:   node.Value,
+						Version: node.Version,
+						Height:  node.Height,
+					},
+				})
+			})
+		} else {
+			err = exportKVs(se.kvs, se.skipTreeKeys, fn)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Implements SnapshotExporter.
+func (e *snapshotExporter) Close() {
+	for _, se := range e.stores {
+		if se.tree != nil {
+			se.tree.Close()
+		}
+		if se.kvs != nil {
+			se.kvs.Close()
+		}
+	}
+}
+
+func exportKVs(itr types.Iterator, skipTreeKeys bool, fn func(types.SnapshotItem) error) error {
+	for ; itr.Valid(); itr.Next() {
+		key, value := itr.Key(), itr.Value()
+		if skipTreeKeys && tiavl.IsTreeKey(key, value) {
+			continue
+		}
+
+		err := fn(types.SnapshotItem{
+			KV: &types.SnapshotKVItem{Key: key, Value: value},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Implements Snapshotter.
+func (ms *multiStore) Restore(version int64, next func() (types.SnapshotItem, error)) error {
+	if version <= 0 {
+		return fmt.Errorf("invalid restore version %d", version)
+	}
+	if ms.lastCommitID.Version != 0 || getLatestVersion(ms.db) != 0 {
+		return fmt.Errorf("cannot restore into a non-empty multistore at version %d", ms.lastCommitID.Version)
+	}
+
+	r := &restorer{
+		version:  version,
+		restored: make(map[types.StoreKey]bool, len(ms.stores)),
+	}
+	defer r.close()
+
+	for {
+		item, err := next()
+		if goerrors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case item.Store != nil:
+			key := ms.keysByName[item.Store.Name]
+			if key == nil || ms.stores[key] == nil {
+				return fmt.Errorf("unknown store %q in snapshot", item.Store.Name)
+			}
+			if r.restored[key] {
+				return fmt.Errorf("duplicate store %q in snapshot", item.Store.Name)
+			}
+			if err := r.start(key, ms.stores[key]); err != nil {
+				return err
+			}
+
+		case item.IAVL != nil:
+			if err := r.addNode(item.IAVL); err != nil {
+				return err
+			}
+
+		case item.KV != nil:
+			if err := r.addKV(item.KV); err != nil {
+				return err
+			}
+
+		default:
+			return fmt.Errorf("empty snapshot item")
+		}
+	}
+	if err := r.finish(); err != nil {
+		return err
+	}
+
+	// The commit info of the restored version is rebuilt from the stores,
+	// as done by Commit
+	storeInfos := make([]storeInfo, 0, len(ms.stores))
+	for key, store := range ms.stores {
+		if !r.restored[key] {
+			return fmt.Errorf("store %s is missing from snapshot", key.Name())
+		}
+
+		si := storeInfo{}
+		si.Name = key.Name()
+		si.Core.CommitID = store.LastCommitID()
+		storeInfos = append(storeInfos, si)
+	}
+
+	batch := ms.db.NewBatch()
+	defer batch.Close()
+	setCommitInfo(batch, version, commitInfo{
+		Version:    version,
+		StoreInfos: storeInfos,
+	})
+	setLatestVersion(batch, version)
+	batch.WriteSync()
+
+	return ms.LoadVersion(version)
+}
+
+// sortedKeys returns the keys of the stores, sorted by name.
+func (ms *multiStore) sortedKeys() []types.StoreKey {
+	keys := make([]types.StoreKey, 0, len(ms.stores))
+	for key := range ms.stores {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Name() < keys[j].Name()
+	})
+
+	return keys
+}
+
+// sharesTreeDB returns whether the store of the given key shares its DB
+// with an IAVL store, i.e. both were mounted with the same DB.
+func (ms *multiStore) sharesTreeDB(key types.StoreKey) bool {
+	db := ms.storesParams[key].db
+	if db == nil {
+		return false
+	}
+
+	for other, params := range ms.storesParams {
+		if other == key || params.db != db {
+			continue
+		}
+		if _, ok := ms.stores[other].(*iavl.Store); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// restorer restores the stores of a snapshot one at a time.
+type restorer struct {
+	version  int64
+	restored map[types.StoreKey]bool
+
+	key      types.StoreKey
+	importer *tiavl.Importer // for IAVL stores
+	kvStore  *dbadapter.Store
+	kvBatch  dbm.Batch
+	batched  int
+}
+
+func (r *restorer) start(key types.StoreKey, store types.CommitStore) error {
+	if err := r.finish(); err != nil {
+		return err
+	}
+
+	switch store := store.(type) {
+	case *iavl.Store:
+		importer, err := store.Import(r.version)
+		if err != nil {
+			return fmt.Errorf("unable to import store %s: %w", key.Name(), err)
+		}
+		r.importer = importer
+
+	case dbadapter.Store:
+		r.kvStore = &store
+		r.kvBatch = store.NewBatch()
+
+	default:
+		return fmt.Errorf("store %s of type %T cannot be restored", key.Name(), store)
+	}
+	r.key = key
+
+	return nil
+}
+
+func (r *restorer) addNode(item *types.SnapshotIAVLItem) error {
+	if r.importer == nil {
+		return fmt.Errorf("unexpected IAVL node in snapshot, outside of an IAVL store")
+	}
+
+	return r.importer.Add(tiavl.ExportNode{
+		Key:     item.Key,
+		Value:   item.Value,
+		Version: item.Version,
+		Height:  item.Height,
+	})
+}
+
+func (r *restorer) addKV(item *types.SnapshotKVItem) error {
+	if r.kvStore == nil {
+		return fmt.Errorf("unexpected key-value pair in snapshot, outside of a DB adapter store")
+	}
+	if len(item.Key) == 0 {
+		return fmt.Errorf("empty key in snapshot")
+	}
+
+	// Empty values are decoded as nil
+	value := item.Value
+	if value == nil {
+		value = []byte{}
+	}
+
+	r.kvBatch.Set(item.Key, value)
+	r.batched++
+	if r.batched >= restoreBatchSize {
+		r.kvBatch.Write()
+		r.kvBatch.Close()
+		r.kvBatch = r.kvStore.NewBatch()
+		r.batched = 0
+	}
+
+	return nil
+}
+
+// finish saves the store being restored, if any.
+func (r *restorer) finish() error {
+	if r.key == nil {
+		return nil
+	}
+
+	switch {
+	case r.importer != nil:
+		if err := r.importer.Commit(); err != nil {
+			return fmt.Errorf("unable to import store %s: %w", r.key.Name(), err)
+		}
+		r.importer = nil
+
+	case r.kvStore != nil:
+		r.kvBatch.Write()
+		r.kvBatch.Close()
+		r.kvStore, r.kvBatch, r.batched = nil, nil, 0
+	}
+
+	r.restored[r.key] = true
+	r.key = nil
+
+	return nil
+}
+
+// close discards the store being restored, if any.
+func (r *restorer) close() {
+	if r.importer != nil {
+		r.importer.Close()
+	}
+	if r.kvBatch != nil {
+		r.kvBatch.Close()
+	}
+}
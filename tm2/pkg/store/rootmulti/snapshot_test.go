@@ -0,0 +1,234 @@
+package rootmulti
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/goleveldb"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var (
+	snapshotMainKey  = types.NewStoreKey("main")
+	snapshotBaseKey  = types.NewStoreKey("base")
+	snapshotOtherKey = types.NewStoreKey("other")
+)
+
+// newSnapshotMultiStore mounts a main IAVL store and a base DB adapter
+// store sharing the DB, as done by the applications, and another IAVL store
+func newSnapshotMultiStore(t *testing.T, db dbm.DB) *multiStore {
+	t.Helper()
+
+	ms := NewMultiStore(db)
+	ms.MountStoreWithDB(snapshotMainKey, iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(snapshotBaseKey, dbadapter.StoreConstructor, db)
+	ms.MountStoreWithDB(snapshotOtherKey, iavl.StoreConstructor, nil)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	return ms
+}
+
+func snapshotItems(t *testing.T, ms *multiStore, version int64) []types.SnapshotItem {
+	t.Helper()
+
+	exporter, err := ms.Snapshot(version)
+	require.NoError(t, err)
+
+	return exportItems(t, exporter)
+}
+
+func exportItems(t *testing.T, exporter types.SnapshotExporter) []types.SnapshotItem {
+	t.Helper()
+	defer exporter.Close()
+
+	var items []types.SnapshotItem
+	require.NoError(t, exporter.Export(func(item types.SnapshotItem) error {
+		// The items go through their encoding, as in the snapshot chunks
+		bz := amino.MustMarshal(item)
+		var decoded types.SnapshotItem
+		amino.MustUnmarshal(bz, &decoded)
+
+		items = append(items, decoded)
+		return nil
+	}))
+
+	return items
+}
+
+func restoreItems(ms *multiStore, version int64, items []types.SnapshotItem) error {
+	return ms.Restore(version, func() (types.SnapshotItem, error) {
+		if len(items) == 0 {
+			return types.SnapshotItem{}, io.EOF
+		}
+
+		item := items[0]
+		items = items[1:]
+		return item, nil
+	})
+}
+
+func TestMultiStoreSnapshotRestore(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	for v := range 3 {
+		for i := range 20 {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			value := []byte(fmt.Sprintf("value-%d-%d", v, i))
+
+			ms.GetStore(snapshotMainKey).Set(key, value)
+			ms.GetStore(snapshotBaseKey).Set(key, value)
+			ms.GetStore(snapshotOtherKey).Set(key, value)
+		}
+		ms.GetStore(snapshotBaseKey).Set([]byte("empty"), []byte{})
+		ms.Commit()
+	}
+	commitID := ms.LastCommitID()
+
+	// Only the last committed version can be snapshotted
+	_, err := ms.Snapshot(2)
+	require.Error(t, err)
+
+	items := snapshotItems(t, ms, 3)
+
+	// The nodes of the main tree are not part of the base store items
+	var kvs int
+	for _, item := range items {
+		if item.KV != nil {
+			kvs++
+		}
+	}
+	assert.Equal(t, 21, kvs)
+
+	restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+	require.NoError(t, restoreItems(restored, 3, items))
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	for _, key := range []types.StoreKey{snapshotMainKey, snapshotBaseKey, snapshotOtherKey} {
+		for i := range 20 {
+			k := []byte(fmt.Sprintf("key-%d", i))
+			assert.Equal(t, ms.GetStore(key).Get(k), restored.GetStore(key).Get(k), "store %s key %s", key.Name(), k)
+		}
+	}
+	assert.Equal(t, []byte{}, restored.GetStore(snapshotBaseKey).Get([]byte("empty")))
+
+	// The restored multistore can commit the next versions
+	restored.GetStore(snapshotMainKey).Set([]byte("new"), []byte("value"))
+	assert.Equal(t, int64(4), restored.Commit().Version)
+
+	// The restored multistore is not empty anymore
+	assert.Error(t, restoreItems(restored, 3, items))
+}
+
+func TestMultiStoreRestore_Invalid(t *testing.T) {
+	t.Parallel()
+
+	ms := newSnapshotMultiStore(t, memdb.NewMemDB())
+	ms.GetStore(snapshotMainKey).Set([]byte("key"), []byte("value"))
+	ms.GetStore(snapshotBaseKey).Set([]byte("key"), []byte("value"))
+	ms.Commit()
+
+	items := snapshotItems(t, ms, 1)
+
+	for _, tc := range []struct {
+		name  string
+		items []types.SnapshotItem
+	}{
+		{
+			name:  "missing store",
+			items: items[:len(items)-2],
+		},
+		{
+			name: "unknown store",
+			items: append([]types.SnapshotItem{
+				{Store: &types.SnapshotStoreItem{Name: "unknown"}},
+			}, items...),
+		},
+		{
+			name:  "duplicate store",
+			items: append(append([]types.SnapshotItem{}, items...), items[0]),
+		},
+		{
+			name: "key-value pair in an IAVL store",
+			items: []types.SnapshotItem{
+				{Store: &types.SnapshotStoreItem{Name: snapshotMainKey.Name()}},
+				{KV: &types.SnapshotKVItem{Key: []byte("key"), Value: []byte("value")}},
+			},
+		},
+		{
+			name:  "empty item",
+			items: append(append([]types.SnapshotItem{}, items...), types.SnapshotItem{}),
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+			assert.Error(t, restoreItems(restored, 1, tc.items))
+		})
+	}
+}
+
+func TestMultiStoreSnapshotPointInTime(t *testing.T) {
+	t.Parallel()
+
+	db, err := goleveldb.NewGoLevelDB("snapshot", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ms := newSnapshotMultiStore(t, db)
+	ms.SetStoreOptions(types.StoreOptions{PruningOptions: types.PruneEverything})
+
+	setAll := func(value string) {
+		for _, key := range []types.StoreKey{snapshotMainKey, snapshotBaseKey, snapshotOtherKey} {
+			for i := range 100 {
+				ms.GetStore(key).Set([]byte(fmt.Sprintf("key-%d", i)), []byte(value))
+			}
+		}
+	}
+	setAll("value-1")
+	ms.Commit()
+	commitID := ms.LastCommitID()
+
+	exporter, err := ms.Snapshot(1)
+	require.NoError(t, err)
+
+	// The next versions are committed, and the exported one pruned, while
+	// the snapshot is being taken
+	for v := 2; v <= 3; v++ {
+		setAll(fmt.Sprintf("value-%d", v))
+		ms.GetStore(snapshotBaseKey).Set([]byte(fmt.Sprintf("new-%d", v)), []byte("value"))
+		ms.Commit()
+	}
+
+	items := exportItems(t, exporter)
+
+	restored := newSnapshotMultiStore(t, memdb.NewMemDB())
+	require.NoError(t, restoreItems(restored, 1, items))
+
+	assert.Equal(t, commitID, restored.LastCommitID())
+	for _, key := range []types.StoreKey{snapshotMainKey, snapshotBaseKey, snapshotOtherKey} {
+		for i := range 100 {
+			k := []byte(fmt.Sprintf("key-%d", i))
+			assert.Equal(t, []byte("value-1"), restored.GetStore(key).Get(k), "store %s key %s", key.Name(), k)
+		}
+	}
+	assert.Nil(t, restored.GetStore(snapshotBaseKey).Get([]byte("new-2")))
+
+	// The exported version is pruned once the export is closed
+	setAll("value-4")
+	ms.Commit()
+	_, err = ms.Snapshot(1)
+	assert.Error(t, err)
+	assert.False(t, ms.GetCommitStore(snapshotMainKey).(*iavl.Store).VersionExists(1))
+}
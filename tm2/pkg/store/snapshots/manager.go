@@ -0,0 +1,301 @@
+// Package snapshots implements the periodic snapshots of a multistore, split
+// into hashed chunks which are served to the peers, and the restoration of a
+// multistore from the chunks of a snapshot.
+package snapshots
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+// Format is the format of the snapshots: the zlib-compressed stream of the
+// length-prefixed, amino-encoded items of the multistore.
+const Format uint32 = 1
+
+// maxItemSize is the maximum size of an encoded snapshot item.
+const maxItemSize = 64 << 20 // 64 MB
+
+var (
+	ErrInvalidSnapshot = errors.New("invalid snapshot")
+	ErrInvalidChunk    = errors.New("invalid snapshot chunk")
+	ErrNoRestore       = errors.New("no snapshot is being restored")
+
+	errRestoreAborted = errors.New("snapshot restore aborted")
+)
+
+// Options are the options of the snapshots taken by a manager.
+type Options struct {
+	Interval   int64 // height interval between snapshots, 0 to disable them
+	KeepRecent int   // number of recent snapshots kept, 0 to keep all of them
+}
+
+// Manager takes the snapshots of a multistore as its heights are
+// committed, and restores it from the chunks of a snapshot.
+type Manager struct {
+	store      *Store
+	multistore types.Snapshotter
+	opts       Options
+	logger     *slog.Logger
+
+	wg     sync.WaitGroup // snapshot being taken
+	taking atomic.Bool    // whether a snapshot is being taken
+
+	mtx     sync.Mutex
+	restore *restoration
+}
+
+// restoration is a snapshot being restored.
+type restoration struct {
+	snapshot abci.Snapshot
+	hashes   [][]byte       // chunk hashes
+	next     uint32         // index of the next chunk
+	writer   *io.PipeWriter // stream of the applied chunks
+	done     chan error     // restore result
+}
+
+// NewManager returns a manager of the snapshots of the given multistore,
+// saved in the given store.
+func NewManager(store *Store, multistore types.Snapshotter, opts Options, logger *slog.Logger) *Manager {
+	return &Manager{
+		store:      store,
+		multistore: multistore,
+		opts:       opts,
+		logger:     logger,
+	}
+}
+
+// Commit takes a snapshot of the committed height in the background, if it
+// is a snapshot height. The next heights can be committed while it is being
+// taken, as it exports the state as of this height. The snapshot is skipped
+// if the previous one is still being taken.
+func (m *Manager) Commit(height int64) {
+	if m.opts.Interval <= 0 || height%m.opts.Interval != 0 {
+		return
+	}
+	if !m.taking.CompareAndSwap(false, true) {
+		m.logger.Info("skipping snapshot, the previous one is still being taken", "height", height)
+		return
+	}
+
+	exporter, err := m.multistore.Snapshot(height)
+	if err != nil {
+		m.taking.Store(false)
+		m.logger.Error("unable to take snapshot", "height", height, "err", err)
+		return
+	}
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		defer m.taking.Store(false)
+
+		snapshot, err := m.create(height, exporter)
+		if err != nil {
+			m.logger.Error("unable to take snapshot", "height", height, "err", err)
+			return
+		}
+
+		m.logger.Info("took snapshot",
+			"height", height,
+			"chunks", snapshot.Chunks,
+			"hash", fmt.Sprintf("%X", snapshot.Hash),
+		)
+	}()
+}
+
+// Wait waits for the snapshot being taken, if any.
+func (m *Manager) Wait() {
+	m.wg.Wait()
+}
+
+// Create takes a snapshot of the given height, which must be the last
+// committed one, and prunes the old snapshots.
+func (m *Manager) Create(height int64) (*abci.Snapshot, error) {
+	exporter, err := m.multistore.Snapshot(height)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.create(height, exporter)
+}
+
+// create saves the snapshot of the given height from its export, and prunes
+// the old snapshots.
+func (m *Manager) create(height int64, exporter types.SnapshotExporter) (*abci.Snapshot, error) {
+	defer exporter.Close()
+
+	snapshot, err := m.store.Save(height, Format, func(w io.Writer) error {
+		zw := zlib.NewWriter(w)
+		err := exporter.Export(func(item types.SnapshotItem) error {
+			_, err := amino.MarshalSizedWriter(zw, item)
+			return err
+		})
+		if closeErr := zw.Close(); err == nil {
+			err = closeErr
+		}
+
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if m.opts.KeepRecent > 0 {
+		if _, err := m.store.Prune(m.opts.KeepRecent); err != nil {
+			return nil, err
+		}
+	}
+
+	return snapshot, nil
+}
+
+// List returns the available snapshots, the most recent first.
+func (m *Manager) List() ([]abci.Snapshot, error) {
+	return m.store.List()
+}
+
+// LoadChunk returns a chunk of a snapshot.
+func (m *Manager) LoadChunk(height int64, format, index uint32) ([]byte, error) {
+	return m.store.LoadChunk(height, format, index)
+}
+
+// Restore starts restoring the multistore from the given snapshot, whose
+// chunks are then applied in order with RestoreChunk. The snapshot being
+// restored, if any, is aborted.
+func (m *Manager) Restore(snapshot abci.Snapshot) error {
+	if snapshot.Format != Format {
+		return fmt.Errorf("%w: unsupported format %d", ErrInvalidSnapshot, snapshot.Format)
+	}
+	if snapshot.Height <= 0 {
+		return fmt.Errorf("%w: invalid height %d", ErrInvalidSnapshot, snapshot.Height)
+	}
+
+	var metadata Metadata
+	if err := amino.Unmarshal(snapshot.Metadata, &metadata); err != nil {
+		return fmt.Errorf("%w: invalid metadata: %w", ErrInvalidSnapshot, err)
+	}
+	if snapshot.Chunks == 0 || int(snapshot.Chunks) != len(metadata.ChunkHashes) {
+		return fmt.Errorf("%w: %d chunks for %d chunk hashes",
+			ErrInvalidSnapshot, snapshot.Chunks, len(metadata.ChunkHashes))
+	}
+	if !bytes.Equal(merkle.SimpleHashFromByteSlices(metadata.ChunkHashes), snapshot.Hash) {
+		return fmt.Errorf("%w: chunk hashes do not match the snapshot hash", ErrInvalidSnapshot)
+	}
+
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	m.abortRestore()
+
+	reader, writer := io.Pipe()
+	done := make(chan error, 1)
+	go func() {
+		err := m.restoreItems(snapshot.Height, reader)
+
+		// The chunks applied after a failure get its error
+		reader.CloseWithError(err)
+		done <- err
+	}()
+
+	m.restore = &restoration{
+		snapshot: snapshot,
+		hashes:   metadata.ChunkHashes,
+		writer:   writer,
+		done:     done,
+	}
+
+	return nil
+}
+
+func (m *Manager) restoreItems(height int64, r io.Reader) (err error) {
+	// The snapshot comes from a peer, and must not crash the node
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %v", ErrInvalidSnapshot, r)
+		}
+	}()
+
+	zr, err := zlib.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidSnapshot, err)
+	}
+	defer zr.Close()
+
+	br := bufio.NewReader(zr)
+	return m.multistore.Restore(height, func() (types.SnapshotItem, error) {
+		var item types.SnapshotItem
+		_, err := amino.UnmarshalSizedReader(br, &item, maxItemSize)
+
+		return item, err
+	})
+}
+
+// RestoreChunk applies the chunk of the given index of the snapshot being
+// restored, which must be the next one. It returns whether the multistore
+// is restored, once its last chunk is applied. ErrInvalidChunk is returned
+// if the chunk does not match its hash, in which case it can be applied
+// again.
+func (m *Manager) RestoreChunk(index uint32, chunk []byte) (bool, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+
+	r := m.restore
+	if r == nil {
+		return false, ErrNoRestore
+	}
+	if index != r.next {
+		return false, fmt.Errorf("expected snapshot chunk %d, got %d", r.next, index)
+	}
+
+	hash := sha256.Sum256(chunk)
+	if !bytes.Equal(hash[:], r.hashes[index]) {
+		return false, fmt.Errorf("%w: chunk %d does not match its hash", ErrInvalidChunk, index)
+	}
+
+	if _, err := r.writer.Write(chunk); err != nil {
+		m.restore = nil
+		if err := <-r.done; err != nil {
+			return false, err
+		}
+
+		return false, fmt.Errorf("%w: chunk %d is after the end of the snapshot", ErrInvalidSnapshot, index)
+	}
+	r.next++
+
+	if r.next < r.snapshot.Chunks {
+		return false, nil
+	}
+
+	r.writer.Close()
+	m.restore = nil
+	if err := <-r.done; err != nil {
+		return false, err
+	}
+
+	return true, nil
+}
+
+// abortRestore aborts the snapshot being restored, if any.
+func (m *Manager) abortRestore() {
+	if m.restore == nil {
+		return
+	}
+
+	m.restore.writer.CloseWithError(errRestoreAborted)
+	<-m.restore.done
+	m.restore = nil
+}
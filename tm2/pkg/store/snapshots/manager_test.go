@@ -0,0 +1,307 @@
+package snapshots
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	dbm "github.com/gnolang/gno/tm2/pkg/db"
+	"github.com/gnolang/gno/tm2/pkg/db/goleveldb"
+	"github.com/gnolang/gno/tm2/pkg/db/memdb"
+	"github.com/gnolang/gno/tm2/pkg/log"
+
+	"github.com/gnolang/gno/tm2/pkg/store/dbadapter"
+	"github.com/gnolang/gno/tm2/pkg/store/iavl"
+	"github.com/gnolang/gno/tm2/pkg/store/rootmulti"
+	"github.com/gnolang/gno/tm2/pkg/store/types"
+)
+
+var (
+	mainKey = types.NewStoreKey("main")
+	baseKey = types.NewStoreKey("base")
+)
+
+type testMultiStore interface {
+	types.CommitMultiStore
+	types.Snapshotter
+}
+
+func newTestMultiStore(t *testing.T, db dbm.DB) testMultiStore {
+	t.Helper()
+
+	ms := rootmulti.NewMultiStore(db)
+	ms.MountStoreWithDB(mainKey, iavl.StoreConstructor, db)
+	ms.MountStoreWithDB(baseKey, dbadapter.StoreConstructor, db)
+	require.NoError(t, ms.LoadLatestVersion())
+
+	return ms
+}
+
+// newTestManager returns a manager of a multistore with a few committed
+// versions, whose snapshots are split into small chunks
+func newTestManager(t *testing.T, opts Options) (*Manager, testMultiStore) {
+	t.Helper()
+
+	ms := newTestMultiStore(t, memdb.NewMemDB())
+	for v := range 3 {
+		for i := range 100 {
+			key := []byte(fmt.Sprintf("key-%d", i))
+			value := []byte(fmt.Sprintf("value-%d-%d", v, i))
+
+			ms.GetStore(mainKey).Set(key, value)
+			ms.GetStore(baseKey).Set(key, value)
+		}
+		ms.Commit()
+	}
+
+	store := newTestStore(t, 512)
+	return NewManager(store, ms, opts, log.NewNoopLogger()), ms
+}
+
+func loadChunks(t *testing.T, m *Manager, snapshot *abci.Snapshot) [][]byte {
+	t.Helper()
+
+	chunks := make([][]byte, 0, snapshot.Chunks)
+	for i := range snapshot.Chunks {
+		chunk, err := m.LoadChunk(snapshot.Height, snapshot.Format, i)
+		require.NoError(t, err)
+		chunks = append(chunks, chunk)
+	}
+
+	return chunks
+}
+
+func TestManager_CreateRestore(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, Options{})
+
+	snapshot, err := m.Create(3)
+	require.NoError(t, err)
+	require.Greater(t, snapshot.Chunks, uint32(1))
+
+	snapshots, err := m.List()
+	require.NoError(t, err)
+	assert.Equal(t, []abci.Snapshot{*snapshot}, snapshots)
+
+	chunks := loadChunks(t, m, snapshot)
+
+	restored := newTestMultiStore(t, memdb.NewMemDB())
+	rm := NewManager(newTestStore(t, 512), restored, Options{}, log.NewNoopLogger())
+	require.NoError(t, rm.Restore(*snapshot))
+
+	// A chunk out of order is refused
+	_, err = rm.RestoreChunk(1, chunks[1])
+	assert.Error(t, err)
+
+	for i, chunk := range chunks {
+		done, err := rm.RestoreChunk(uint32(i), chunk)
+		require.NoError(t, err)
+		assert.Equal(t, i == len(chunks)-1, done)
+	}
+
+	assert.Equal(t, ms.LastCommitID(), restored.LastCommitID())
+	for i := range 100 {
+		key := []byte(fmt.Sprintf("key-%d", i))
+		assert.Equal(t, ms.GetStore(mainKey).Get(key), restored.GetStore(mainKey).Get(key))
+		assert.Equal(t, ms.GetStore(baseKey).Get(key), restored.GetStore(baseKey).Get(key))
+	}
+
+	_, err = rm.RestoreChunk(0, chunks[0])
+	assert.ErrorIs(t, err, ErrNoRestore)
+}
+
+func TestManager_RestoreInvalidChunk(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, Options{})
+
+	snapshot, err := m.Create(3)
+	require.NoError(t, err)
+	chunks := loadChunks(t, m, snapshot)
+
+	restored := newTestMultiStore(t, memdb.NewMemDB())
+	rm := NewManager(newTestStore(t, 512), restored, Options{}, log.NewNoopLogger())
+	require.NoError(t, rm.Restore(*snapshot))
+
+	// A chunk not matching its hash can be fetched and applied again
+	corrupted := append([]byte{}, chunks[0]...)
+	corrupted[0]++
+	_, err = rm.RestoreChunk(0, corrupted)
+	assert.ErrorIs(t, err, ErrInvalidChunk)
+
+	for i, chunk := range chunks {
+		_, err := rm.RestoreChunk(uint32(i), chunk)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, ms.LastCommitID(), restored.LastCommitID())
+}
+
+func TestManager_RestoreInvalidSnapshot(t *testing.T) {
+	t.Parallel()
+
+	m, _ := newTestManager(t, Options{})
+
+	snapshot, err := m.Create(3)
+	require.NoError(t, err)
+
+	for _, tc := range []struct {
+		name   string
+		mutate func(*abci.Snapshot)
+	}{
+		{"unknown format", func(s *abci.Snapshot) { s.Format++ }},
+		{"invalid height", func(s *abci.Snapshot) { s.Height = 0 }},
+		{"invalid metadata", func(s *abci.Snapshot) { s.Metadata = []byte("metadata") }},
+		{"invalid chunk count", func(s *abci.Snapshot) { s.Chunks++ }},
+		{"invalid hash", func(s *abci.Snapshot) { s.Hash = []byte("hash") }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			invalid := *snapshot
+			tc.mutate(&invalid)
+
+			restored := newTestMultiStore(t, memdb.NewMemDB())
+			rm := NewManager(newTestStore(t, 512), restored, Options{}, log.NewNoopLogger())
+			assert.ErrorIs(t, rm.Restore(invalid), ErrInvalidSnapshot)
+		})
+	}
+}
+
+func TestManager_RestoreAbort(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, Options{})
+
+	snapshot, err := m.Create(3)
+	require.NoError(t, err)
+	chunks := loadChunks(t, m, snapshot)
+
+	restored := newTestMultiStore(t, memdb.NewMemDB())
+	rm := NewManager(newTestStore(t, 512), restored, Options{}, log.NewNoopLogger())
+	require.NoError(t, rm.Restore(*snapshot))
+
+	// Offering the snapshot again aborts the restore before any chunk is
+	// written, and starts over
+	require.NoError(t, rm.Restore(*snapshot))
+	for i, chunk := range chunks {
+		_, err := rm.RestoreChunk(uint32(i), chunk)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, ms.LastCommitID(), restored.LastCommitID())
+}
+
+func TestManager_Commit(t *testing.T) {
+	t.Parallel()
+
+	m, ms := newTestManager(t, Options{Interval: 2, KeepRecent: 1})
+
+	for range 4 {
+		ms.GetStore(mainKey).Set([]byte("key"), []byte("value"))
+		cid := ms.Commit()
+		m.Commit(cid.Version)
+		m.Wait()
+	}
+
+	// Snapshots are taken at heights 4 and 6, and only the last one is kept
+	snapshots, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	assert.Equal(t, int64(6), snapshots[0].Height)
+}
+
+// slowMultiStore is a multistore whose snapshot exports block until
+// released.
+type slowMultiStore struct {
+	testMultiStore
+	release chan struct{}
+}
+
+func (ms *slowMultiStore) Snapshot(version int64) (types.SnapshotExporter, error) {
+	exporter, err := ms.testMultiStore.Snapshot(version)
+	if err != nil {
+		return nil, err
+	}
+
+	return &slowExporter{SnapshotExporter: exporter, release: ms.release}, nil
+}
+
+type slowExporter struct {
+	types.SnapshotExporter
+	release chan struct{}
+}
+
+func (e *slowExporter) Export(fn func(types.SnapshotItem) error) error {
+	<-e.release
+	return e.SnapshotExporter.Export(fn)
+}
+
+func TestManager_CommitSlowSnapshot(t *testing.T) {
+	t.Parallel()
+
+	db, err := goleveldb.NewGoLevelDB("snapshots", t.TempDir())
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	ms := &slowMultiStore{
+		testMultiStore: newTestMultiStore(t, db),
+		release:        make(chan struct{}),
+	}
+	ms.SetStoreOptions(types.StoreOptions{PruningOptions: types.PruneEverything})
+	m := NewManager(newTestStore(t, 512), ms, Options{Interval: 2}, log.NewNoopLogger())
+
+	commit := func(value string) int64 {
+		ms.GetStore(mainKey).Set([]byte("key"), []byte(value))
+		ms.GetStore(baseKey).Set([]byte("key"), []byte(value))
+		cid := ms.Commit()
+		m.Commit(cid.Version)
+		return cid.Version
+	}
+
+	// The heights after a snapshot height are committed while its snapshot
+	// is still being taken, and the next snapshot height is skipped
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for v := 1; v <= 4; v++ {
+			commit(fmt.Sprintf("value-%d", v))
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(10 * time.Second):
+		t.Fatal("commits are blocked by the snapshot being taken")
+	}
+	close(ms.release)
+	m.Wait()
+
+	snapshots, err := m.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 1)
+	require.Equal(t, int64(2), snapshots[0].Height)
+
+	// The snapshot has the state of its height
+	restored := newTestMultiStore(t, memdb.NewMemDB())
+	rm := NewManager(newTestStore(t, 512), restored, Options{}, log.NewNoopLogger())
+	require.NoError(t, rm.Restore(snapshots[0]))
+	for i, chunk := range loadChunks(t, m, &snapshots[0]) {
+		_, err := rm.RestoreChunk(uint32(i), chunk)
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []byte("value-2"), restored.GetStore(mainKey).Get([]byte("key")))
+	assert.Equal(t, []byte("value-2"), restored.GetStore(baseKey).Get([]byte("key")))
+
+	// Once done, the next snapshot heights are snapshotted again
+	commit("value-5")
+	commit("value-6")
+	m.Wait()
+
+	snapshots, err = m.List()
+	require.NoError(t, err)
+	require.Len(t, snapshots, 2)
+	assert.Equal(t, int64(6), snapshots[0].Height)
+}
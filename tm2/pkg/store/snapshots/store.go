@@ -0,0 +1,284 @@
+package snapshots
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	abci "github.com/gnolang/gno/tm2/pkg/bft/abci/types"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+)
+
+// DefaultChunkSize is the default maximum size of a snapshot chunk.
+const DefaultChunkSize = 10 << 20 // 10 MB
+
+const (
+	snapshotFile = "snapshot.json"
+	tmpSuffix    = ".tmp"
+)
+
+var ErrSnapshotNotFound = errors.New("snapshot not found")
+
+// Metadata is the metadata of the snapshots: the hashes of their chunks, in
+// order. The hash of a snapshot is the simple merkle root of its chunk
+// hashes.
+type Metadata struct {
+	ChunkHashes [][]byte
+}
+
+// Store stores the snapshots in a directory, in a sub-directory per height
+// and format, holding the snapshot description and a file per chunk.
+type Store struct {
+	dir       string
+	chunkSize int
+}
+
+// NewStore returns a store of the snapshots in the given directory, which
+// is created if needed.
+func NewStore(dir string) (*Store, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("unable to create snapshot directory: %w", err)
+	}
+
+	return &Store{
+		dir:       dir,
+		chunkSize: DefaultChunkSize,
+	}, nil
+}
+
+// Save saves the snapshot of the given height and format, whose content is
+// the stream written by the given function, split into chunks.
+func (s *Store) Save(height int64, format uint32, write func(io.Writer) error) (*abci.Snapshot, error) {
+	if height <= 0 {
+		return nil, fmt.Errorf("invalid snapshot height %d", height)
+	}
+
+	// The snapshot is written in a temporary directory, renamed once
+	// complete, so that incomplete snapshots are never listed
+	dir := s.path(height, format)
+	tmpDir := dir + tmpSuffix
+	if err := os.RemoveAll(tmpDir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(tmpDir, 0o755); err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	w := &chunkWriter{
+		dir:  tmpDir,
+		size: s.chunkSize,
+	}
+	err := write(w)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unable to write snapshot at height %d: %w", height, err)
+	}
+
+	metadata, err := amino.Marshal(Metadata{ChunkHashes: w.hashes})
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &abci.Snapshot{
+		Height:   height,
+		Format:   format,
+		Chunks:   uint32(len(w.hashes)),
+		Hash:     merkle.SimpleHashFromByteSlices(w.hashes),
+		Metadata: metadata,
+	}
+
+	bz, err := amino.MarshalJSONIndent(snapshot, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, snapshotFile), bz, 0o644); err != nil {
+		return nil, err
+	}
+
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.Rename(tmpDir, dir); err != nil {
+		return nil, err
+	}
+
+	return snapshot, nil
+}
+
+// Get returns the snapshot of the given height and format.
+func (s *Store) Get(height int64, format uint32) (*abci.Snapshot, error) {
+	bz, err := os.ReadFile(filepath.Join(s.path(height, format), snapshotFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSnapshotNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	snapshot := new(abci.Snapshot)
+	if err := amino.UnmarshalJSON(bz, snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot at height %d: %w", height, err)
+	}
+
+	return snapshot, nil
+}
+
+// List returns the snapshots, the most recent first.
+func (s *Store) List() ([]abci.Snapshot, error) {
+	heights, err := s.heights()
+	if err != nil {
+		return nil, err
+	}
+
+	var snapshots []abci.Snapshot
+	for _, height := range heights {
+		formats, err := os.ReadDir(s.heightPath(height))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range formats {
+			format, err := strconv.ParseUint(entry.Name(), 10, 32)
+			if err != nil || !entry.IsDir() {
+				continue // temporary or foreign files
+			}
+
+			snapshot, err := s.Get(height, uint32(format))
+			if err != nil {
+				return nil, err
+			}
+			snapshots = append(snapshots, *snapshot)
+		}
+	}
+
+	return snapshots, nil
+}
+
+// LoadChunk returns the chunk of the given index of a snapshot.
+func (s *Store) LoadChunk(height int64, format, index uint32) ([]byte, error) {
+	chunk, err := os.ReadFile(filepath.Join(s.path(height, format), strconv.FormatUint(uint64(index), 10)))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrSnapshotNotFound
+	}
+
+	return chunk, err
+}
+
+// Prune deletes the snapshots of all but the given number of most recent
+// heights. It returns the number of pruned heights.
+func (s *Store) Prune(keepRecent int) (int, error) {
+	heights, err := s.heights()
+	if err != nil {
+		return 0, err
+	}
+	if len(heights) <= keepRecent {
+		return 0, nil
+	}
+
+	pruned := heights[keepRecent:]
+	for _, height := range pruned {
+		if err := os.RemoveAll(s.heightPath(height)); err != nil {
+			return 0, fmt.Errorf("unable to prune snapshot at height %d: %w", height, err)
+		}
+	}
+
+	return len(pruned), nil
+}
+
+// heights returns the heights of the snapshots, the most recent first.
+func (s *Store) heights() ([]int64, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	heights := make([]int64, 0, len(entries))
+	for _, entry := range entries {
+		height, err := strconv.ParseInt(entry.Name(), 10, 64)
+		if err != nil || !entry.IsDir() {
+			continue
+		}
+		heights = append(heights, height)
+	}
+	sort.Slice(heights, func(i, j int) bool {
+		return heights[i] > heights[j]
+	})
+
+	return heights, nil
+}
+
+func (s *Store) heightPath(height int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(height, 10))
+}
+
+func (s *Store) path(height int64, format uint32) string {
+	return filepath.Join(s.heightPath(height), strconv.FormatUint(uint64(format), 10))
+}
+
+// chunkWriter splits the written stream into chunk files of a maximum size,
+// and hashes them.
+type chunkWriter struct {
+	dir  string
+	size int
+
+	file    *os.File
+	hasher  hash.Hash
+	written int
+	hashes  [][]byte
+}
+
+func (w *chunkWriter) Write(p []byte) (int, error) {
+	var n int
+	for len(p) > 0 {
+		if w.file == nil {
+			file, err := os.Create(filepath.Join(w.dir, strconv.Itoa(len(w.hashes))))
+			if err != nil {
+				return n, err
+			}
+			w.file, w.hasher, w.written = file, sha256.New(), 0
+		}
+
+		part := p[:min(len(p), w.size-w.written)]
+		if _, err := w.file.Write(part); err != nil {
+			return n, err
+		}
+		w.hasher.Write(part)
+		w.written += len(part)
+		n += len(part)
+		p = p[len(part):]
+
+		if w.written == w.size {
+			if err := w.closeChunk(); err != nil {
+				return n, err
+			}
+		}
+	}
+
+	return n, nil
+}
+
+func (w *chunkWriter) closeChunk() error {
+	err := w.file.Close()
+	w.hashes = append(w.hashes, w.hasher.Sum(nil))
+	w.file = nil
+
+	return err
+}
+
+// Close closes the last chunk.
+func (w *chunkWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	return w.closeChunk()
+}
@@ -0,0 +1,128 @@
+package snapshots
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/gnolang/gno/tm2/pkg/amino"
+	"github.com/gnolang/gno/tm2/pkg/crypto/merkle"
+)
+
+func newTestStore(t *testing.T, chunkSize int) *Store {
+	t.Helper()
+
+	store, err := NewStore(filepath.Join(t.TempDir(), "snapshots"))
+	require.NoError(t, err)
+	store.chunkSize = chunkSize
+
+	return store
+}
+
+func TestStore_Save(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, 4)
+	content := []byte("0123456789")
+
+	snapshot, err := store.Save(5, 1, func(w io.Writer) error {
+		_, err := w.Write(content)
+		return err
+	})
+	require.NoError(t, err)
+
+	// The content is split into chunks of the maximum size
+	assert.Equal(t, int64(5), snapshot.Height)
+	assert.Equal(t, uint32(1), snapshot.Format)
+	assert.Equal(t, uint32(3), snapshot.Chunks)
+
+	var (
+		loaded []byte
+		hashes [][]byte
+	)
+	for i := range snapshot.Chunks {
+		chunk, err := store.LoadChunk(5, 1, i)
+		require.NoError(t, err)
+
+		hash := sha256.Sum256(chunk)
+		hashes = append(hashes, hash[:])
+		loaded = append(loaded, chunk...)
+	}
+	assert.Equal(t, content, loaded)
+
+	var metadata Metadata
+	require.NoError(t, amino.Unmarshal(snapshot.Metadata, &metadata))
+	assert.Equal(t, hashes, metadata.ChunkHashes)
+	assert.Equal(t, merkle.SimpleHashFromByteSlices(hashes), snapshot.Hash)
+
+	got, err := store.Get(5, 1)
+	require.NoError(t, err)
+	assert.Equal(t, snapshot, got)
+
+	_, err = store.LoadChunk(5, 1, 3)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+	_, err = store.Get(6, 1)
+	assert.ErrorIs(t, err, ErrSnapshotNotFound)
+}
+
+func TestStore_SaveError(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, 4)
+
+	_, err := store.Save(5, 1, func(w io.Writer) error {
+		w.Write([]byte("0123456789"))
+		return io.ErrUnexpectedEOF
+	})
+	assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+
+	// Failed snapshots are not saved
+	snapshots, err := store.List()
+	require.NoError(t, err)
+	assert.Empty(t, snapshots)
+
+	entries, err := os.ReadDir(store.heightPath(5))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestStore_ListPrune(t *testing.T) {
+	t.Parallel()
+
+	store := newTestStore(t, DefaultChunkSize)
+	for _, height := range []int64{3, 10, 1, 7} {
+		_, err := store.Save(height, 1, func(w io.Writer) error {
+			_, err := io.Copy(w, bytes.NewReader([]byte("snapshot")))
+			return err
+		})
+		require.NoError(t, err)
+	}
+
+	listHeights := func() []int64 {
+		snapshots, err := store.List()
+		require.NoError(t, err)
+
+		heights := make([]int64, 0, len(snapshots))
+		for _, snapshot := range snapshots {
+			heights = append(heights, snapshot.Height)
+		}
+
+		return heights
+	}
+	assert.Equal(t, []int64{10, 7, 3, 1}, listHeights())
+
+	pruned, err := store.Prune(2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, pruned)
+	assert.Equal(t, []int64{10, 7}, listHeights())
+
+	pruned, err = store.Prune(2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, pruned)
+}
@@ -0,0 +1,52 @@
+package types
+
+// SnapshotItem is an item of a multistore snapshot. Exactly one of its
+// fields is set: each store starts with a store item, followed by the nodes
+// of its IAVL tree, or by its key-value pairs if it is not merkleized.
+type SnapshotItem struct {
+	Store *SnapshotStoreItem
+	IAVL  *SnapshotIAVLItem
+	KV    *SnapshotKVItem
+}
+
+// SnapshotStoreItem starts the items of the named store.
+type SnapshotStoreItem struct {
+	Name string
+}
+
+// SnapshotIAVLItem is an exported node of an IAVL tree.
+type SnapshotIAVLItem struct {
+	Key     []byte
+	Value   []byte
+	Version int64
+	Height  int8
+}
+
+// SnapshotKVItem is a key-value pair of a store which is not merkleized.
+type SnapshotKVItem struct {
+	Key   []byte
+	Value []byte
+}
+
+// Snapshotter is a multistore whose state can be exported to, and restored
+// from, a stream of snapshot items.
+type Snapshotter interface {
+	// Snapshot returns an export of the state at the given version, which
+	// must be the last committed one. The export is not affected by the
+	// versions committed after Snapshot returns, and must be closed.
+	Snapshot(version int64) (SnapshotExporter, error)
+
+	// Restore restores the state at the given version from the items
+	// returned by next, until it returns io.EOF, and loads it. The
+	// multistore must be empty.
+	Restore(version int64, next func() (SnapshotItem, error)) error
+}
+
+// SnapshotExporter is a point-in-time export of the state of a multistore.
+type SnapshotExporter interface {
+	// Export calls fn for each item of the exported state.
+	Export(fn func(SnapshotItem) error) error
+
+	// Close releases the exported state.
+	Close()
+}
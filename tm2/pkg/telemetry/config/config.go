@@ -4,7 +4,10 @@ import (
 	"errors"
 )
 
-var errEndpointNotSet = errors.New("telemetry exporter endpoint not set")
+var (
+	errEndpointNotSet       = errors.New("neither the telemetry exporter endpoint nor the prometheus listen address is set")
+	errTracesEndpointNotSet = errors.New("telemetry exporter endpoint not set, required by the traces")
+)
 
 // Config is the configuration struct for the tm2 telemetry package
 type Config struct {
@@ -12,7 +15,11 @@ type Config struct {
 	MeterName         string `json:"meter_name" toml:"meter_name"`
 	ServiceName       string `json:"service_name" toml:"service_name" comment:"in Prometheus this is transformed into the label 'exported_job'"`
 	ServiceInstanceID string `json:"service_instance_id" toml:"service_instance_id" comment:"the ID helps to distinguish instances of the same service that exist at the same time (e.g. instances of a horizontally scaled service), in Prometheus this is transformed into the label 'exported_instance"`
-	ExporterEndpoint  string `json:"exporter_endpoint" toml:"exporter_endpoint" comment:"the endpoint to export metrics to, like a local OpenTelemetry collector"`
+	ExporterEndpoint  string `json:"exporter_endpoint" toml:"exporter_endpoint" comment:"the endpoint to export metrics and traces to, like a local OpenTelemetry collector"`
+
+	PrometheusListenAddr string `json:"prometheus_listen_addr" toml:"prometheus_listen_addr" comment:"the address to serve the metrics at /metrics to be scraped by Prometheus, like 127.0.0.1:26660. Disabled if empty"`
+
+	TracesEnabled bool `json:"traces_enabled" toml:"traces_enabled" comment:"export the traces of the RPC requests, ABCI txs and GnoVM executions to the exporter endpoint"`
 }
 
 // DefaultTelemetryConfig is the default configuration used for the node
@@ -23,15 +30,24 @@ func DefaultTelemetryConfig() *Config {
 		ServiceName:       "tm2",
 		ServiceInstanceID: "tm2-node-1",
 		ExporterEndpoint:  "",
+
+		PrometheusListenAddr: "",
+
+		TracesEnabled: false,
 	}
 }
 
 // ValidateBasic performs basic telemetry config validation and
 // returns an error if any check fails
 func (cfg *Config) ValidateBasic() error {
-	if cfg.ExporterEndpoint == "" {
+	if cfg.ExporterEndpoint == "" && cfg.PrometheusListenAddr == "" {
 		return errEndpointNotSet
 	}
 
+	// The traces can only be pushed
+	if cfg.ExporterEndpoint == "" && cfg.TracesEnabled {
+		return errTracesEndpointNotSet
+	}
+
 	return nil
 }
@@ -18,6 +18,25 @@ func TestConfig_ValidateBasic(t *testing.T) {
 		assert.ErrorIs(t, c.ValidateBasic(), errEndpointNotSet)
 	})
 
+	t.Run("prometheus listen address only", func(t *testing.T) {
+		t.Parallel()
+
+		c := DefaultTelemetryConfig()
+		c.PrometheusListenAddr = "127.0.0.1:26660"
+
+		assert.NoError(t, c.ValidateBasic())
+	})
+
+	t.Run("traces without exporter endpoint", func(t *testing.T) {
+		t.Parallel()
+
+		c := DefaultTelemetryConfig()
+		c.PrometheusListenAddr = "127.0.0.1:26660"
+		c.TracesEnabled = true
+
+		assert.ErrorIs(t, c.ValidateBasic(), errTracesEndpointNotSet)
+	})
+
 	t.Run("valid configuration", func(t *testing.T) {
 		t.Parallel()
 
@@ -3,12 +3,18 @@ package metrics
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/http"
 	"net/url"
+	"time"
 
 	"github.com/gnolang/gno/tm2/pkg/telemetry/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetrichttp"
+	otelprom "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -35,6 +41,18 @@ const (
 	blockSizeKey            = "block_size_hist"
 	gasPriceKey             = "block_gas_price_hist"
 
+	consensusStepTimeKey  = "consensus_step_hist"
+	consensusRoundTimeKey = "consensus_round_hist"
+	missedVotesKey        = "consensus_missed_votes_counter"
+	lateVotesKey          = "consensus_late_votes_counter"
+	blockPartsReceivedKey = "block_parts_received_counter"
+	blockPartsSentKey     = "block_parts_sent_counter"
+
+	storeReadTimeKey  = "store_read_hist"
+	storeWriteTimeKey = "store_write_hist"
+
+	vmObjectCacheKey = "vm_object_cache_counter"
+
 	httpRequestTimeKey = "http_request_time_hist"
 	wsRequestTimeKey   = "ws_request_time_hist"
 )
@@ -67,6 +85,9 @@ var (
 	// VMCPUCycles measures the VM CPU cycles
 	VMCPUCycles metric.Int64Histogram
 
+	// VMObjectCacheLookups measures the VM object cache lookups, as hits or misses
+	VMObjectCacheLookups metric.Int64Counter
+
 	// Consensus //
 
 	// BuildBlockTimer measures the block build duration
@@ -90,6 +111,32 @@ var (
 	// BlockGasPriceAmount measures the block gas price of the last block
 	BlockGasPriceAmount metric.Int64Histogram
 
+	// ConsensusStepTime measures the duration of each consensus round step
+	ConsensusStepTime metric.Int64Histogram
+
+	// ConsensusRoundTime measures the duration of each consensus round
+	ConsensusRoundTime metric.Int64Histogram
+
+	// MissedVotes measures the precommits missing from the commits, per validator
+	MissedVotes metric.Int64Counter
+
+	// LateVotes measures the precommits received after their commit, per validator
+	LateVotes metric.Int64Counter
+
+	// BlockPartsReceived measures the block parts received from peers
+	BlockPartsReceived metric.Int64Counter
+
+	// BlockPartsSent measures the block parts gossiped to peers
+	BlockPartsSent metric.Int64Counter
+
+	// Store //
+
+	// StoreReadTime measures the store read latency
+	StoreReadTime metric.Int64Histogram
+
+	// StoreWriteTime measures the store write latency
+	StoreWriteTime metric.Int64Histogram
+
 	// RPC //
 
 	// HTTPRequestTime measures the HTTP request response time
@@ -101,47 +148,49 @@ var (
 
 func Init(config config.Config) error {
 	var (
-		ctx = context.Background()
-		exp sdkMetric.Exporter
+		ctx     = context.Background()
+		options []sdkMetric.Option
+		err     error
 	)
 
-	u, err := url.Parse(config.ExporterEndpoint)
-	if err != nil {
-		return fmt.Errorf("error parsing exporter endpoint: %s, %w", config.ExporterEndpoint, err)
+	if config.ExporterEndpoint != "" {
+		exp, err := newExporter(ctx, config.ExporterEndpoint)
+		if err != nil {
+			return err
+		}
+
+		// Default period is 1m
+		options = append(options, sdkMetric.WithReader(sdkMetric.NewPeriodicReader(exp)))
 	}
 
-	// Use oltp metric exporter with http/https or grpc
-	switch u.Scheme {
-	case "http", "https":
-		exp, err = otlpmetrichttp.New(
-			ctx,
-			otlpmetrichttp.WithEndpointURL(config.ExporterEndpoint),
-		)
+	if config.PrometheusListenAddr != "" {
+		ln, err := net.Listen("tcp", config.PrometheusListenAddr)
 		if err != nil {
-			return fmt.Errorf("unable to create http metrics exporter, %w", err)
+			return fmt.Errorf("unable to listen on prometheus address: %s, %w", config.PrometheusListenAddr, err)
 		}
-	default:
-		exp, err = otlpmetricgrpc.New(
-			ctx,
-			otlpmetricgrpc.WithEndpoint(config.ExporterEndpoint),
-			otlpmetricgrpc.WithInsecure(),
-		)
+
+		reader, err := servePrometheus(ln)
 		if err != nil {
-			return fmt.Errorf("unable to create grpc metrics exporter, %w", err)
+			ln.Close()
+
+			return err
 		}
+
+		options = append(options, sdkMetric.WithReader(reader))
 	}
 
 	provider := sdkMetric.NewMeterProvider(
-		// Default period is 1m
-		sdkMetric.WithReader(sdkMetric.NewPeriodicReader(exp)),
-		sdkMetric.WithResource(
-			resource.NewWithAttributes(
-				semconv.SchemaURL,
-				semconv.ServiceNameKey.String(config.ServiceName),
-				semconv.ServiceVersionKey.String("1.0.0"),
-				semconv.ServiceInstanceIDKey.String(config.ServiceInstanceID),
+		append(
+			options,
+			sdkMetric.WithResource(
+				resource.NewWithAttributes(
+					semconv.SchemaURL,
+					semconv.ServiceNameKey.String(config.ServiceName),
+					semconv.ServiceVersionKey.String("1.0.0"),
+					semconv.ServiceInstanceIDKey.String(config.ServiceInstanceID),
+				),
 			),
-		),
+		)...,
 	)
 	otel.SetMeterProvider(provider)
 	meter := provider.Meter(config.MeterName)
@@ -211,6 +260,13 @@ func Init(config config.Config) error {
 		return fmt.Errorf("unable to create histogram, %w", err)
 	}
 
+	if VMObjectCacheLookups, err = meter.Int64Counter(
+		vmObjectCacheKey,
+		metric.WithDescription("VM object cache lookups"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
 	// Consensus //
 	if ValidatorsCount, err = meter.Int64Histogram(
 		validatorCountKey,
@@ -256,6 +312,68 @@ func Init(config config.Config) error {
 	); err != nil {
 		return fmt.Errorf("unable to create histogram, %w", err)
 	}
+
+	if ConsensusStepTime, err = meter.Int64Histogram(
+		consensusStepTimeKey,
+		metric.WithDescription("consensus round step duration"),
+		metric.WithUnit("ms"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
+	if ConsensusRoundTime, err = meter.Int64Histogram(
+		consensusRoundTimeKey,
+		metric.WithDescription("consensus round duration"),
+		metric.WithUnit("ms"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
+	if MissedVotes, err = meter.Int64Counter(
+		missedVotesKey,
+		metric.WithDescription("precommits missing from the commits"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if LateVotes, err = meter.Int64Counter(
+		lateVotesKey,
+		metric.WithDescription("precommits received after their commit"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if BlockPartsReceived, err = meter.Int64Counter(
+		blockPartsReceivedKey,
+		metric.WithDescription("block parts received from peers"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	if BlockPartsSent, err = meter.Int64Counter(
+		blockPartsSentKey,
+		metric.WithDescription("block parts gossiped to peers"),
+	); err != nil {
+		return fmt.Errorf("unable to create counter, %w", err)
+	}
+
+	// Store //
+	if StoreReadTime, err = meter.Int64Histogram(
+		storeReadTimeKey,
+		metric.WithDescription("store read latency"),
+		metric.WithUnit("us"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
+	if StoreWriteTime, err = meter.Int64Histogram(
+		storeWriteTimeKey,
+		metric.WithDescription("store write latency"),
+		metric.WithUnit("us"),
+	); err != nil {
+		return fmt.Errorf("unable to create histogram, %w", err)
+	}
+
 	// RPC //
 
 	if HTTPRequestTime, err = meter.Int64Histogram(
@@ -276,3 +394,59 @@ func Init(config config.Config) error {
 
 	return nil
 }
+
+// newExporter creates the OTLP metric exporter pushing to the given endpoint,
+// with http/https or grpc
+func newExporter(ctx context.Context, endpoint string) (sdkMetric.Exporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing exporter endpoint: %s, %w", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		exp, err := otlpmetrichttp.New(
+			ctx,
+			otlpmetrichttp.WithEndpointURL(endpoint),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create http metrics exporter, %w", err)
+		}
+
+		return exp, nil
+	default:
+		exp, err := otlpmetricgrpc.New(
+			ctx,
+			otlpmetricgrpc.WithEndpoint(endpoint),
+			otlpmetricgrpc.WithInsecure(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create grpc metrics exporter, %w", err)
+		}
+
+		return exp, nil
+	}
+}
+
+// servePrometheus creates the Prometheus metric reader, and serves the
+// metrics it collects at /metrics on the given listener, to be scraped
+func servePrometheus(ln net.Listener) (sdkMetric.Reader, error) {
+	// Use a dedicated registry, not to expose the global Go collectors
+	registry := prometheus.NewRegistry()
+
+	reader, err := otelprom.New(otelprom.WithRegisterer(registry))
+	if err != nil {
+		return nil, fmt.Errorf("unable to create prometheus metrics reader, %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
+
+	srv := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go srv.Serve(ln) //nolint:errcheck // the listener lives as long as the node
+
+	return reader, nil
+}
@@ -0,0 +1,42 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdkMetric "go.opentelemetry.io/otel/sdk/metric"
+)
+
+func TestServePrometheus(t *testing.T) {
+	t.Parallel()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { ln.Close() })
+
+	reader, err := servePrometheus(ln)
+	require.NoError(t, err)
+
+	// Record a metric with the reader
+	provider := sdkMetric.NewMeterProvider(sdkMetric.WithReader(reader))
+	counter, err := provider.Meter("test").Int64Counter(missedVotesKey)
+	require.NoError(t, err)
+
+	counter.Add(context.Background(), 3)
+
+	// Scrape the metrics
+	res, err := http.Get("http://" + ln.Addr().String() + "/metrics")
+	require.NoError(t, err)
+	defer res.Body.Close()
+
+	body, err := io.ReadAll(res.Body)
+	require.NoError(t, err)
+
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Regexp(t, `(?m)^consensus_missed_votes_counter_total(\{.*\})? 3$`, string(body))
+}
@@ -0,0 +1,95 @@
+package traces
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"net/url"
+
+	"github.com/gnolang/gno/tm2/pkg/telemetry/config"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.4.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const tracerName = "github.com/gnolang/gno"
+
+// Span attribute keys, shared by the traced layers
+const (
+	TxHashKey  = attribute.Key("tx.hash")
+	PkgPathKey = attribute.Key("vm.pkg_path")
+)
+
+// Tracer returns the tracer of the node spans.
+// Its spans are no-ops unless the traces have been initialized
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// TxHash returns the span attribute of the given tx hash,
+// encoded as displayed by gnokey
+func TxHash(hash []byte) attribute.KeyValue {
+	return TxHashKey.String(base64.StdEncoding.EncodeToString(hash))
+}
+
+// Init sets the global tracer provider, exporting the spans
+// to the OTLP exporter endpoint
+func Init(config config.Config) error {
+	exp, err := newExporter(context.Background(), config.ExporterEndpoint)
+	if err != nil {
+		return err
+	}
+
+	provider := sdkTrace.NewTracerProvider(
+		sdkTrace.WithBatcher(exp),
+		sdkTrace.WithResource(
+			resource.NewWithAttributes(
+				semconv.SchemaURL,
+				semconv.ServiceNameKey.String(config.ServiceName),
+				semconv.ServiceVersionKey.String("1.0.0"),
+				semconv.ServiceInstanceIDKey.String(config.ServiceInstanceID),
+			),
+		),
+	)
+	otel.SetTracerProvider(provider)
+
+	return nil
+}
+
+// newExporter creates the OTLP span exporter pushing to the given endpoint,
+// with http/https or grpc
+func newExporter(ctx context.Context, endpoint string) (sdkTrace.SpanExporter, error) {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing exporter endpoint: %s, %w", endpoint, err)
+	}
+
+	switch u.Scheme {
+	case "http", "https":
+		exp, err := otlptracehttp.New(
+			ctx,
+			otlptracehttp.WithEndpointURL(endpoint),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create http traces exporter, %w", err)
+		}
+
+		return exp, nil
+	default:
+		exp, err := otlptracegrpc.New(
+			ctx,
+			otlptracegrpc.WithEndpoint(endpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+		if err != nil {
+			return nil, fmt.Errorf("unable to create grpc traces exporter, %w", err)
+		}
+
+		return exp, nil
+	}
+}
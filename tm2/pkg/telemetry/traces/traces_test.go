@@ -0,0 +1,86 @@
+package traces
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdkTrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	collectortrace "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/gnolang/gno/tm2/pkg/telemetry/config"
+)
+
+// collector is an in-process OTLP/HTTP collector, recording the received spans
+type collector struct {
+	mux   sync.Mutex
+	spans map[string]map[string]string // span name -> attributes
+}
+
+func (c *collector) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var req collectortrace.ExportTraceServiceRequest
+	if err := proto.Unmarshal(body, &req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	for _, rs := range req.ResourceSpans {
+		for _, ss := range rs.ScopeSpans {
+			for _, span := range ss.Spans {
+				attrs := make(map[string]string)
+				for _, attr := range span.Attributes {
+					attrs[attr.Key] = attr.Value.GetStringValue()
+				}
+				c.spans[span.Name] = attrs
+			}
+		}
+	}
+
+	bz, _ := proto.Marshal(&collectortrace.ExportTraceServiceResponse{})
+	w.Header().Set("Content-Type", "application/x-protobuf")
+	w.Write(bz)
+}
+
+func TestInit(t *testing.T) {
+	c := &collector{spans: make(map[string]map[string]string)}
+	srv := httptest.NewServer(c)
+	t.Cleanup(srv.Close)
+
+	cfg := config.DefaultTelemetryConfig()
+	cfg.TracesEnabled = true
+	cfg.ExporterEndpoint = srv.URL
+
+	t.Cleanup(func() { otel.SetTracerProvider(noop.NewTracerProvider()) })
+	require.NoError(t, Init(*cfg))
+
+	_, span := Tracer().Start(context.Background(), "abci/DeliverTx")
+	span.SetAttributes(TxHash([]byte{0x1, 0x2, 0x3}))
+	span.End()
+
+	provider := otel.GetTracerProvider().(*sdkTrace.TracerProvider)
+	require.NoError(t, provider.ForceFlush(context.Background()))
+	require.NoError(t, provider.Shutdown(context.Background()))
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+
+	require.Contains(t, c.spans, "abci/DeliverTx")
+	assert.Equal(t, "AQID", c.spans["abci/DeliverTx"][string(TxHashKey)])
+}